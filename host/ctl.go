@@ -0,0 +1,19 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"AIComputingNode/pkg/ctl"
+)
+
+// runCtl runs the "host ctl" subcommand, the command line client of the node HTTP API.
+func runCtl(args []string) {
+	if err := ctl.Run(args, os.Stdout, os.Stderr); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		os.Exit(1)
+	}
+}
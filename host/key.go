@@ -0,0 +1,246 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/keystore"
+)
+
+const keyUsage = `Usage: host key <command> [flags]
+
+Commands:
+  import   -config x [-keyfile path] [-passphrase-file f] <key file>
+           replace the identity of the node with the key, encrypted into -keyfile if set
+  export   -config x [-passphrase-file f] [-plain] [-out file]
+           export the key of the node encrypted by the passphrase, or unencrypted with -plain
+  rotate   -config x [-passphrase-file f] [-type t]
+           replace the key of the node with a new one and announce the rotation in the heartbeats
+  signer   -key file -socket path [-passphrase-file f]
+           hold the key and serve the external signer on the unix socket`
+
+// runKey runs the "host key" subcommand which manages the private key of the node.
+func runKey(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, keyUsage)
+		os.Exit(1)
+	}
+	var err error
+	switch args[0] {
+	case "import":
+		err = keyImport(args[1:])
+	case "export":
+		err = keyExport(args[1:])
+	case "rotate":
+		err = keyRotate(args[1:])
+	case "signer":
+		err = keySigner(args[1:])
+	default:
+		fmt.Fprintln(os.Stderr, keyUsage)
+		err = fmt.Errorf("unknown command %q", args[0])
+	}
+	if err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// loadKeyConfig loads the configuration file without the overrides, which must not be saved into the file.
+func loadKeyConfig(configPath string) (*config.Config, error) {
+	if configPath == "" {
+		return nil, errors.New("-config is required")
+	}
+	return config.LoadConfigFile(configPath)
+}
+
+// saveKeyConfig backs up and rewrites the configuration file, which may hold the previous key.
+func saveKeyConfig(configPath, tag string, cfg *config.Config) error {
+	if tag == "" {
+		tag = "key"
+	}
+	backup, err := config.BackupFile(configPath, tag)
+	if err != nil {
+		return fmt.Errorf("backup config: %v", err)
+	}
+	fmt.Println("Backup configuration file at", backup)
+	if err := cfg.SaveConfig(configPath); err != nil {
+		return err
+	}
+	fmt.Println("Save configuration file at", configPath)
+	return nil
+}
+
+func keyImport(args []string) error {
+	fs := flag.NewFlagSet("key import", flag.ContinueOnError)
+	configPath := fs.String("config", "", "configuration file of the node")
+	keyFile := fs.String("keyfile", "", "write the key encrypted by the passphrase to the file instead of the configuration")
+	passphraseFile := fs.String("passphrase-file", "", "file of the passphrase, "+keystore.PassphraseEnv+" is used if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New("key file is required")
+	}
+	cfg, err := loadKeyConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	passphrase := keystore.ReadPassphrase(*passphraseFile)
+	priv, err := keystore.ReadKeyFile(fs.Arg(0), passphrase)
+	if err != nil {
+		return fmt.Errorf("read key: %v", err)
+	}
+	previous := cfg.Identity.PeerID
+	if *keyFile == "" {
+		passphrase = nil
+	}
+	if err := cfg.Identity.SetPrivateKey(priv, *keyFile, passphrase); err != nil {
+		return err
+	}
+	if *keyFile != "" {
+		cfg.Identity.PassphraseFile = *passphraseFile
+		fmt.Println("Write encrypted key at", *keyFile)
+	}
+	fmt.Println("Import peer id", cfg.Identity.PeerID)
+	return saveKeyConfig(*configPath, previous, cfg)
+}
+
+func keyExport(args []string) error {
+	fs := flag.NewFlagSet("key export", flag.ContinueOnError)
+	configPath := fs.String("config", "", "configuration file of the node")
+	passphraseFile := fs.String("passphrase-file", "", "file of the passphrase, "+keystore.PassphraseEnv+" is used if empty")
+	plain := fs.Bool("plain", false, "export the unencrypted key in base64")
+	out := fs.String("out", "", "write the key to the file instead of stdout")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := loadKeyConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	priv, err := cfg.Identity.PrivateKey()
+	if err != nil {
+		return fmt.Errorf("load key: %v", err)
+	}
+
+	var data []byte
+	if *plain {
+		text, err := keystore.PrivKeyToString(priv)
+		if err != nil {
+			return err
+		}
+		data = []byte(text + "\n")
+	} else {
+		pass, err := keystore.ReadPassphrase(*passphraseFile)()
+		if err != nil {
+			return err
+		}
+		key, err := keystore.Encrypt(priv, pass, keystore.DefaultScryptParams)
+		if err != nil {
+			return err
+		}
+		if data, err = json.MarshalIndent(key, "", "  "); err != nil {
+			return err
+		}
+		data = append(data, '\n')
+	}
+	if *out == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*out, data, 0600)
+}
+
+func keyRotate(args []string) error {
+	fs := flag.NewFlagSet("key rotate", flag.ContinueOnError)
+	configPath := fs.String("config", "", "configuration file of the node")
+	passphraseFile := fs.String("passphrase-file", "", "file of the passphrase, Identity.PassphraseFile or "+keystore.PassphraseEnv+" is used if empty")
+	keyType := fs.String("type", "", "type of the new key, one of "+strings.Join(keystore.KeyTypes, ", ")+", the type of the current key if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := loadKeyConfig(*configPath)
+	if err != nil {
+		return err
+	}
+	if *passphraseFile != "" {
+		cfg.Identity.PassphraseFile = *passphraseFile
+	}
+	previous := cfg.Identity.PeerID
+	if *keyType == "" {
+		current, err := cfg.Identity.PrivateKey()
+		if err != nil {
+			return fmt.Errorf("load key: %v", err)
+		}
+		*keyType = keystore.KeyTypeOf(current)
+	}
+	priv, err := keystore.GenerateKey(*keyType)
+	if err != nil {
+		return err
+	}
+	if cfg.Identity.KeyFile != "" {
+		backup, err := config.BackupFile(cfg.Identity.KeyFile, previous)
+		if err != nil {
+			return fmt.Errorf("backup key file: %v", err)
+		}
+		fmt.Println("Backup key file at", backup)
+	}
+
+	if err := cfg.Identity.Rotate(priv); err != nil {
+		return err
+	}
+	fmt.Printf("Rotate peer id from %s to %s\n", previous, cfg.Identity.PeerID)
+	if err := saveKeyConfig(*configPath, previous, cfg); err != nil {
+		return err
+	}
+	fmt.Println("Restart the node to use the new peer id, the heartbeats announce the rotation to the peers collect nodes")
+	return nil
+}
+
+func keySigner(args []string) error {
+	fs := flag.NewFlagSet("key signer", flag.ContinueOnError)
+	keyFile := fs.String("key", "", "key file held by the signer")
+	socket := fs.String("socket", "", "unix socket of the signer")
+	passphraseFile := fs.String("passphrase-file", "", "file of the passphrase, "+keystore.PassphraseEnv+" is used if empty")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *keyFile == "" || *socket == "" {
+		return errors.New("-key and -socket are required")
+	}
+	priv, err := keystore.ReadKeyFile(*keyFile, keystore.ReadPassphrase(*passphraseFile))
+	if err != nil {
+		return fmt.Errorf("read key: %v", err)
+	}
+	os.Remove(*socket)
+	l, err := net.Listen("unix", *socket)
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(*socket, 0600); err != nil {
+		l.Close()
+		return err
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		l.Close()
+	}()
+	fmt.Println("Serve signer at", *socket)
+	if err := keystore.ServeSigner(l, priv); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
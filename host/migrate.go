@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"AIComputingNode/pkg/config"
+)
+
+// runMigrate checks or migrates the configuration file of "host -config x -check/-migrate",
+// and returns the exit code.
+func runMigrate(w io.Writer, configPath string, write bool) int {
+	if configPath == "" {
+		fmt.Fprintln(w, "-config is required")
+		return 1
+	}
+	result, err := config.MigrateConfigFile(configPath, write)
+	if err != nil {
+		fmt.Fprintln(w, "Failed to migrate the configuration file:", err)
+		return 1
+	}
+
+	fmt.Fprintf(w, "Configuration version %d, current version %d\n", result.FromVersion, result.ToVersion)
+	for _, applied := range result.Applied {
+		fmt.Fprintln(w, "Migration", applied)
+	}
+	for _, key := range result.UnknownKeys {
+		fmt.Fprintf(w, "Unknown key %s is ignored\n", key)
+	}
+	if result.Changed() {
+		fmt.Fprint(w, config.Diff(result.Before, result.After))
+	}
+
+	if write {
+		if result.Backup != "" {
+			fmt.Fprintf(w, "Configuration file %s migrated, backup in %s\n", configPath, result.Backup)
+		} else {
+			fmt.Fprintln(w, "Configuration file is up to date")
+		}
+	} else if result.Changed() {
+		fmt.Fprintln(w, "Run with -migrate to rewrite the configuration file")
+	}
+
+	errs := result.Config.Check()
+	for _, err := range errs {
+		fmt.Fprintln(w, "Invalid configuration item:", err)
+	}
+	if len(errs) > 0 {
+		return 1
+	}
+	return 0
+}
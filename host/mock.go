@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"AIComputingNode/pkg/model/mock"
+)
+
+// runMock runs the fake model backend of the "host mock" subcommand.
+func runMock(args []string) {
+	fs := flag.NewFlagSet("mock", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:1088", "listen address of the mock model API")
+	project := fs.String("project", "Mock", "project name returned by the model list")
+	models := fs.String("models", "", "comma separated model names returned by the model list")
+	latency := fs.Duration("latency", 0, "delay before every response")
+	tokenRate := fs.Float64("token-rate", 0, "tokens per second of streaming chat completions, 0 means no delay")
+	tokens := fs.Int("tokens", mock.DefaultTokens, "number of tokens of every chat completion")
+	errorRate := fs.Float64("error-rate", 0, "probability in [0, 1] that a request fails")
+	errorStatus := fs.Int("error-status", http.StatusInternalServerError, "HTTP status of the failed requests")
+	seed := fs.Int64("seed", 0, "seed of the deterministic outputs")
+	fs.Parse(args)
+
+	if *errorRate < 0 || *errorRate > 1 {
+		fmt.Println("error-rate must be in [0, 1]")
+		os.Exit(1)
+	}
+	opts := mock.Options{
+		Project:     *project,
+		Latency:     *latency,
+		TokenRate:   *tokenRate,
+		Tokens:      *tokens,
+		ErrorRate:   *errorRate,
+		ErrorStatus: *errorStatus,
+		Seed:        *seed,
+	}
+	if *models != "" {
+		opts.Models = strings.Split(*models, ",")
+	}
+
+	fmt.Printf("Mock model API is running on http://%s\n", *addr)
+	if err := http.ListenAndServe(*addr, mock.New(opts)); err != nil {
+		fmt.Println("Run mock model API:", err)
+		os.Exit(1)
+	}
+}
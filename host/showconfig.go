@@ -0,0 +1,50 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"AIComputingNode/pkg/config"
+)
+
+// setFlags collects the repeated "-set key=value" flags.
+type setFlags []string
+
+func (s *setFlags) String() string {
+	return strings.Join(*s, " ")
+}
+
+func (s *setFlags) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// showConfig prints the effective configuration of "host -config x -show-config"
+// with the secrets redacted, and returns the exit code.
+func showConfig(w io.Writer, cfg *config.Config) int {
+	data, err := json.MarshalIndent(cfg.Redacted(), "", "  ")
+	if err != nil {
+		fmt.Fprintln(w, "Marshal configuration:", err)
+		return 1
+	}
+	fmt.Fprintln(w, string(data))
+
+	overrides := cfg.Overrides()
+	keys := make([]string, 0, len(overrides))
+	for key := range overrides {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "Overridden %s by %s\n", key, overrides[key])
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintln(w, "Invalid configuration item:", err)
+		return 1
+	}
+	return 0
+}
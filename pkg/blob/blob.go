@@ -0,0 +1,155 @@
+package blob
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"AIComputingNode/pkg/log"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+)
+
+var (
+	ErrNotFound = errors.New("blob not found")
+	ErrTooLarge = errors.New("blob exceeds the max size")
+)
+
+// Store keeps the blobs, such as the generated images, in files of the datastore named by their CIDs,
+// the blobs are removed by CleanExpired when they are not stored again within the TTL.
+type Store struct {
+	dir     string
+	ttl     time.Duration
+	maxSize int64
+}
+
+// Open opens the blob store in the folder, which is created if it does not exist.
+func Open(dir string, ttl time.Duration, maxSize int64) (*Store, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, ttl: ttl, maxSize: maxSize}, nil
+}
+
+// CID returns the CIDv1 of the raw data hashed by SHA2-256.
+func CID(data []byte) (string, error) {
+	mh, err := multihash.Sum(data, multihash.SHA2_256, -1)
+	if err != nil {
+		return "", err
+	}
+	return cid.NewCidV1(cid.Raw, mh).String(), nil
+}
+
+// ParseCID checks the CID and returns it in the canonical form, only the CIDs made by CID are accepted.
+func ParseCID(s string) (string, error) {
+	c, err := cid.Decode(s)
+	if err != nil {
+		return "", err
+	}
+	prefix := c.Prefix()
+	if prefix.Version != 1 || prefix.Codec != cid.Raw || prefix.MhType != multihash.SHA2_256 {
+		return "", fmt.Errorf("unsupported cid %s", s)
+	}
+	return c.String(), nil
+}
+
+// Verify checks that the data matches the CID.
+func Verify(id string, data []byte) error {
+	sum, err := CID(data)
+	if err != nil {
+		return err
+	}
+	if sum != id {
+		return fmt.Errorf("blob does not match cid %s", id)
+	}
+	return nil
+}
+
+func (s *Store) MaxSize() int64 {
+	return s.maxSize
+}
+
+func (s *Store) path(id string) string {
+	return filepath.Join(s.dir, id)
+}
+
+// Put stores the data and returns its CID, storing the same data again renews its TTL.
+func (s *Store) Put(data []byte) (string, error) {
+	if int64(len(data)) > s.maxSize {
+		return "", ErrTooLarge
+	}
+	id, err := CID(data)
+	if err != nil {
+		return "", err
+	}
+	p := s.path(id)
+	now := time.Now()
+	if _, err := os.Stat(p); err == nil {
+		return id, os.Chtimes(p, now, now)
+	}
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	return id, os.Rename(tmp.Name(), p)
+}
+
+// Get returns the data of the CID, or ErrNotFound if it is not stored or expired.
+func (s *Store) Get(id string) ([]byte, error) {
+	id, err := ParseCID(id)
+	if err != nil {
+		return nil, err
+	}
+	p := s.path(id)
+	info, err := os.Stat(p)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && s.expired(info, time.Now())) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (s *Store) expired(info os.FileInfo, now time.Time) bool {
+	return now.Sub(info.ModTime()) > s.ttl
+}
+
+// CleanExpired removes the blobs stored longer than the TTL.
+func (s *Store) CleanExpired() {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		log.Logger.Warnf("Read blob store failed %v", err)
+		return
+	}
+	now := time.Now()
+	count := 0
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !s.expired(info, now) {
+			continue
+		}
+		if err := os.Remove(s.path(entry.Name())); err != nil {
+			log.Logger.Warnf("Remove expired blob %s failed %v", entry.Name(), err)
+			continue
+		}
+		count++
+	}
+	if count > 0 {
+		log.Logger.Infof("Removed %d expired blobs", count)
+	}
+}
@@ -0,0 +1,109 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/types"
+)
+
+func TestStore(t *testing.T) {
+	s, err := Open(filepath.Join(t.TempDir(), "blobs"), time.Hour, 1024)
+	if err != nil {
+		t.Fatalf("Open store: %v", err)
+	}
+	data := []byte("image")
+	id, err := s.Put(data)
+	if err != nil {
+		t.Fatalf("Put blob: %v", err)
+	}
+	if parsed, err := ParseCID(id); err != nil || parsed != id {
+		t.Errorf("Parse cid %s: %s, %v", id, parsed, err)
+	}
+	if again, err := s.Put(data); err != nil || again != id {
+		t.Errorf("Put the same blob: %s, %v", again, err)
+	}
+	if got, err := s.Get(id); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("Get blob: %q, %v", got, err)
+	}
+	if err := Verify(id, []byte("other")); err == nil {
+		t.Error("Other data matches the cid")
+	}
+
+	other, _ := CID([]byte("other"))
+	if _, err := s.Get(other); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get missing blob: %v", err)
+	}
+	for _, invalid := range []string{"", "../config.json", "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"} {
+		if _, err := s.Get(invalid); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Get invalid cid %q: %v", invalid, err)
+		}
+	}
+	if _, err := s.Put(make([]byte, 1025)); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Put large blob: %v", err)
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	os.Chtimes(s.path(id), old, old)
+	if _, err := s.Get(id); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get expired blob: %v", err)
+	}
+	s.CleanExpired()
+	if _, err := os.Stat(s.path(id)); !os.IsNotExist(err) {
+		t.Errorf("Expired blob is kept: %v", err)
+	}
+}
+
+func TestStoreImages(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/cat.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("cat"))
+	}))
+	defer backend.Close()
+	s, err := Open(t.TempDir(), time.Hour, 1024)
+	if err != nil {
+		t.Fatalf("Open store: %v", err)
+	}
+	catID, _ := CID([]byte("cat"))
+	dogID, _ := CID([]byte("dog"))
+	dog := base64.StdEncoding.EncodeToString([]byte("dog"))
+
+	tests := []struct {
+		name   string
+		format string
+		choice types.ImageResponseChoice
+		want   types.ImageResponseChoice
+	}{
+		{"Url", "url", types.ImageResponseChoice{Url: backend.URL + "/cat.png", RevisedPrompt: "cat"},
+			types.ImageResponseChoice{Url: URL(catID, "node"), RevisedPrompt: "cat"}},
+		{"MissingUrl", "url", types.ImageResponseChoice{Url: backend.URL + "/bird.png"},
+			types.ImageResponseChoice{Url: backend.URL + "/bird.png"}},
+		{"B64Json", "", types.ImageResponseChoice{B64Json: dog},
+			types.ImageResponseChoice{Url: URL(dogID, "node")}},
+		{"KeepB64Json", "b64_json", types.ImageResponseChoice{B64Json: dog},
+			types.ImageResponseChoice{B64Json: dog}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			choices := []types.ImageResponseChoice{tt.choice}
+			s.StoreImages(context.Background(), "node", tt.format, choices)
+			if choices[0] != tt.want {
+				t.Errorf("Stored image %+v, want %+v", choices[0], tt.want)
+			}
+		})
+	}
+	if data, err := s.Get(catID); err != nil || string(data) != "cat" {
+		t.Errorf("Get stored image: %q, %v", data, err)
+	}
+}
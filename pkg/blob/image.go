@@ -0,0 +1,78 @@
+package blob
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/types"
+)
+
+// URLPrefix is the path of the blobs in the HTTP API.
+const URLPrefix = "/api/v0/blobs/"
+
+// URL returns the HTTP API path of the blob stored by the node.
+func URL(id, nodeID string) string {
+	return URLPrefix + id + "?" + url.Values{"node_id": {nodeID}}.Encode()
+}
+
+// StoreImages stores the generated images and replaces their backend URLs, which only this node can reach,
+// with the URLs of the blobs. The images returned inline are stored and replaced too unless the response
+// format is b64_json. The choices failed to store are kept as they are.
+func (s *Store) StoreImages(ctx context.Context, nodeID, responseFormat string, choices []types.ImageResponseChoice) {
+	for i := range choices {
+		choice := &choices[i]
+		var data []byte
+		var err error
+		switch {
+		case choice.B64Json != "":
+			if responseFormat == "b64_json" {
+				continue
+			}
+			data, err = base64.StdEncoding.DecodeString(choice.B64Json)
+		case strings.HasPrefix(choice.Url, "http://") || strings.HasPrefix(choice.Url, "https://"):
+			data, err = s.download(ctx, choice.Url)
+		default:
+			continue
+		}
+		if err != nil {
+			log.Logger.Warnf("Read generated image failed %v", err)
+			continue
+		}
+		id, err := s.Put(data)
+		if err != nil {
+			log.Logger.Warnf("Store generated image failed %v", err)
+			continue
+		}
+		choice.Url = URL(id, nodeID)
+		choice.B64Json = ""
+	}
+}
+
+func (s *Store) download(ctx context.Context, rawURL string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
@@ -0,0 +1,97 @@
+package blob
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"AIComputingNode/pkg/log"
+
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+// Protocol serves the blobs to the remote peers. The request is the CID in a line,
+// the response is a line of "OK <size>" followed by the data, or a line of "ERR <message>".
+const Protocol = "/blob/0.0.1"
+
+const streamTimeout = 60 * time.Second
+
+// StreamHandler serves the blob requested by the remote peer from the store.
+func (s *Store) StreamHandler(stream network.Stream) {
+	defer stream.Close()
+	stream.SetDeadline(time.Now().Add(streamTimeout))
+
+	line, err := bufio.NewReader(io.LimitReader(stream, 256)).ReadString('\n')
+	if err != nil {
+		stream.Reset()
+		log.Logger.Warnf("Read blob request from %s failed: %v", stream.Conn().RemotePeer(), err)
+		return
+	}
+	id := strings.TrimSpace(line)
+	data, err := s.Get(id)
+	if err != nil {
+		fmt.Fprintf(stream, "ERR %v\n", err)
+		return
+	}
+	if _, err := fmt.Fprintf(stream, "OK %d\n", len(data)); err != nil {
+		stream.Reset()
+		return
+	}
+	if _, err := stream.Write(data); err != nil {
+		stream.Reset()
+		log.Logger.Warnf("Write blob %s to %s failed: %v", id, stream.Conn().RemotePeer(), err)
+	}
+}
+
+// Fetch fetches the blob of the CID on the stream of Protocol opened with the remote peer and verifies it,
+// at most maxSize bytes are accepted. The stream is closed when it returns.
+func Fetch(stream network.Stream, id string, maxSize int64) ([]byte, error) {
+	defer stream.Close()
+	id, err := ParseCID(id)
+	if err != nil {
+		stream.Reset()
+		return nil, err
+	}
+	stream.SetDeadline(time.Now().Add(streamTimeout))
+
+	if _, err := fmt.Fprintf(stream, "%s\n", id); err != nil {
+		stream.Reset()
+		return nil, err
+	}
+	stream.CloseWrite()
+
+	reader := bufio.NewReader(stream)
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		stream.Reset()
+		return nil, err
+	}
+	line = strings.TrimSpace(line)
+	if msg, ok := strings.CutPrefix(line, "ERR "); ok {
+		if msg == ErrNotFound.Error() {
+			return nil, ErrNotFound
+		}
+		return nil, errors.New(msg)
+	}
+	var size int64
+	if _, err := fmt.Sscanf(line, "OK %d", &size); err != nil {
+		stream.Reset()
+		return nil, fmt.Errorf("invalid blob response %q", line)
+	}
+	if size < 0 || size > maxSize {
+		stream.Reset()
+		return nil, ErrTooLarge
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(reader, data); err != nil {
+		stream.Reset()
+		return nil, err
+	}
+	if err := Verify(id, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
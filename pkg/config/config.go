@@ -7,24 +7,32 @@ import (
 	"strings"
 	"time"
 
+	"AIComputingNode/pkg/keystore"
 	"AIComputingNode/pkg/types"
 
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/mattn/go-isatty"
 	"github.com/multiformats/go-multiaddr"
 )
 
-var GC *Config
-
 type Config struct {
+	// Schema version of the configuration file, see ConfigVersion
+	Version    int                     `json:"Version"`
 	Bootstrap  []string                `json:"Bootstrap"`
 	Addresses  []string                `json:"Addresses"`
-	API        APIConfig               `Json:"API"`
+	API        APIConfig               `json:"API"`
 	Identity   IdentityConfig          `json:"Identity"`
 	Swarm      SwarmConfig             `json:"Swarm"`
 	Pubsub     PubsubConfig            `json:"Pubsub"`
 	Routing    RoutingConfig           `json:"Routing"`
 	App        AppConfig               `json:"App"`
 	AIProjects []types.AIProjectConfig `json:"AIProjects"`
+
+	// the configuration of the file without the overrides
+	file *Config
+	// overridden keys and the environment variables or flags overriding them
+	overrides map[string]string
 }
 
 type APIConfig struct {
@@ -32,8 +40,25 @@ type APIConfig struct {
 }
 
 type IdentityConfig struct {
-	PeerID  string `json:"PeerID"`
+	PeerID string `json:"PeerID"`
+	// Unencrypted private key in base64, only one of PrivKey, KeyFile and Signer can be set
 	PrivKey string `json:"PrivKey"`
+	// Path of the private key file, an encrypted keystore or an unencrypted peer key
+	KeyFile string `json:"KeyFile"`
+	// Path of the passphrase file of the encrypted keystore, AICN_KEYSTORE_PASSPHRASE is used if empty
+	PassphraseFile string `json:"PassphraseFile"`
+	// Unix socket of the external signer holding the private key
+	Signer string `json:"Signer"`
+	// Proof of the last key rotation, announced to the peers collect nodes in the heartbeats
+	Rotation IdentityRotationConfig `json:"Rotation"`
+}
+
+type IdentityRotationConfig struct {
+	PreviousPeerID string `json:"PreviousPeerID"`
+	// Public key of the previous peer id in base64
+	PreviousPubKey string `json:"PreviousPubKey"`
+	// Signature of the previous key over the peer ids in base64
+	Signature string `json:"Signature"`
 }
 
 type SwarmConfig struct {
@@ -53,6 +78,10 @@ type SwarmRelayClientConfig struct {
 
 type SwarmRelayServiceConfig struct {
 	Enabled bool `json:"Enabled"`
+	// How long a relayed connection lasts before it is reset
+	ConnectionDurationLimit string `json:"ConnectionDurationLimit"`
+	// Bytes relayed in each direction of a connection before it is reset
+	ConnectionDataLimit int64 `json:"ConnectionDataLimit"`
 }
 
 type SwarmConnMgrConfig struct {
@@ -66,6 +95,12 @@ type PubsubConfig struct {
 	Enabled      bool   `json:"Enabled"`
 	Router       string `json:"Router"`
 	FloodPublish bool   `json:"FloodPublish"`
+	// Messages over the 1 MB pubsub limit are split into chunks, up to MaxMessageSize bytes
+	MaxMessageSize int `json:"MaxMessageSize"`
+	// Maximum bytes of the chunks kept for the incomplete messages
+	MaxChunkMemory int `json:"MaxChunkMemory"`
+	// How long the chunks of an incomplete message are kept
+	ChunkTimeout string `json:"ChunkTimeout"`
 }
 
 type RoutingConfig struct {
@@ -74,15 +109,27 @@ type RoutingConfig struct {
 }
 
 type AppConfig struct {
-	LogLevel     string            `json:"LogLevel"`
-	LogFile      string            `json:"LogFile"`
-	LogOutput    string            `json:"LogOutput"`
-	PreSharedKey string            `json:"PreSharedKey"`
-	TopicName    string            `json:"TopicName"`
-	Datastore    string            `json:"Datastore"`
-	AutoUpgrade  AutoUpgradeConfig `json:"AutoUpgrade"`
+	LogLevel     string `json:"LogLevel"`
+	LogFile      string `json:"LogFile"`
+	LogOutput    string `json:"LogOutput"`
+	PreSharedKey string `json:"PreSharedKey"`
+	TopicName    string `json:"TopicName"`
+	Datastore    string `json:"Datastore"`
+	// Storage backend of the datastore, one of leveldb, memory and sqlite
+	DatastoreBackend string            `json:"DatastoreBackend"`
+	AutoUpgrade      AutoUpgradeConfig `json:"AutoUpgrade"`
 	// peers collect config
 	PeersCollect AppPeersCollectConfig `json:"PeersCollect"`
+	// remote host info and peer identity query config
+	RemoteQuery AppRemoteQueryConfig `json:"RemoteQuery"`
+	// content-addressed store of the generated images
+	ImageStore AppImageStoreConfig `json:"ImageStore"`
+	// asynchronous jobs of the long-running model requests
+	Jobs AppJobsConfig `json:"Jobs"`
+	// scheduler of the model requests received by the worker
+	Scheduler AppSchedulerConfig `json:"Scheduler"`
+	// cache of the responses of the deterministic model requests sent by the node
+	ResponseCache AppResponseCacheConfig `json:"ResponseCache"`
 }
 
 type AutoUpgradeConfig struct {
@@ -94,84 +141,206 @@ type AppPeersCollectConfig struct {
 	Enabled           bool   `json:"Enabled"`
 	HeartbeatInterval string `json:"HeartbeatInterval"`
 	ClientProject     string `json:"ClientProject"`
+	// Include a summary of cpu/memory/gpu usage in heartbeats
+	HeartbeatMetrics bool `json:"HeartbeatMetrics"`
+}
+
+type AppRemoteQueryConfig struct {
+	// How long the responses of remote nodes are cached
+	CacheTTL string `json:"CacheTTL"`
+	// Minimum interval between two requests sent to the same remote node
+	MinInterval string `json:"MinInterval"`
+	// Maximum number of remote requests in flight for one batch query
+	BatchConcurrency int `json:"BatchConcurrency"`
+}
+
+type AppImageStoreConfig struct {
+	// Store the generated images and return the URLs of the blobs instead of the images
+	Enabled bool `json:"Enabled"`
+	// How long the stored images are kept
+	TTL string `json:"TTL"`
+	// Maximum size of one image in bytes
+	MaxSize int64 `json:"MaxSize"`
+}
+
+type AppJobsConfig struct {
+	// How long the finished jobs are kept
+	TTL string `json:"TTL"`
+	// Maximum number of jobs running at the same time, the others are queued
+	MaxRunning int `json:"MaxRunning"`
+	// Hosts of the webhooks allowed to be loopback, private or link-local addresses,
+	// the webhooks of the other hosts must be public
+	WebhookAllowedHosts []string `json:"WebhookAllowedHosts"`
+}
+
+type AppSchedulerConfig struct {
+	// Maximum number of model requests running at the same time, the others are queued
+	MaxRunning int `json:"MaxRunning"`
+	// Maximum number of queued model requests, 0 rejects the requests when MaxRunning is reached
+	MaxQueued int `json:"MaxQueued"`
+	// Priority classes of the requesters, the requesters in no class are in the default class
+	Classes []AppSchedulerClassConfig `json:"Classes"`
+}
+
+type AppSchedulerClassConfig struct {
+	Name string `json:"Name"`
+	// The queued requests of a higher priority run first, the default class has priority 0
+	Priority int `json:"Priority"`
+	// Share of each requester of the class among the requesters of the same priority,
+	// the requesters of the default class have weight 1
+	Weight int `json:"Weight"`
+	// Peer ids of the requesting nodes in the class, a request is classified by the node sending it,
+	// since the wallet of the request is not verified
+	Peers []string `json:"Peers"`
+}
+
+type AppResponseCacheConfig struct {
+	// Projects whose responses are cached, the cache is disabled if empty
+	Projects []string `json:"Projects"`
+	// How long the responses are cached
+	TTL string `json:"TTL"`
+	// Maximum number of the cached responses
+	MaxEntries int `json:"MaxEntries"`
+	// Maximum total size of the cached responses in bytes
+	MaxBytes int64 `json:"MaxBytes"`
 }
 
 func (config Config) Validate() error {
-	if len(config.Bootstrap) > 0 {
-		for _, peer := range config.Bootstrap {
-			_, err := multiaddr.NewMultiaddr(peer)
-			if err != nil {
-				return err
-			}
+	if errs := config.Check(); len(errs) > 0 {
+		return errs[0]
+	}
+	return nil
+}
+
+// Check validates all the sections of the configuration and returns all the errors.
+func (config Config) Check() []error {
+	errs := make([]error, 0)
+	for _, peer := range config.Bootstrap {
+		_, err := multiaddr.NewMultiaddr(peer)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("Bootstrap %q: %v", peer, err))
 		}
 	}
 
 	if len(config.Addresses) == 0 {
-		return fmt.Errorf("addresses can not be empty")
+		errs = append(errs, fmt.Errorf("addresses can not be empty"))
 	}
 	for _, addr := range config.Addresses {
 		_, err := multiaddr.NewMultiaddr(addr)
 		if err != nil {
-			return err
+			errs = append(errs, fmt.Errorf("Addresses %q: %v", addr, err))
 		}
 	}
 
-	err := config.API.Validate()
-	if err != nil {
-		return err
+	sections := []struct {
+		name     string
+		validate func() error
+	}{
+		{"API", config.API.Validate},
+		{"Identity", config.Identity.Validate},
+		{"Swarm", config.Swarm.Validate},
+		{"Pubsub", config.Pubsub.Validate},
+		{"Routing", config.Routing.Validate},
+		{"App", config.App.Validate},
+	}
+	for _, section := range sections {
+		if err := section.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %v", section.name, err))
+		}
 	}
+	return errs
+}
 
-	err = config.Identity.Validate()
-	if err != nil {
-		return err
+func (config APIConfig) Validate() error {
+	if config.Addr == "" {
+		return fmt.Errorf("http api address can not be empty")
 	}
+	return nil
+}
 
-	err = config.Swarm.Validate()
+func (config IdentityConfig) Validate() error {
+	privKey, err := config.PrivateKey()
 	if err != nil {
 		return err
 	}
-
-	err = config.Pubsub.Validate()
+	peer, err := peer.IDFromPrivateKey(privKey)
 	if err != nil {
 		return err
 	}
+	if peer.String() != config.PeerID {
+		return fmt.Errorf("private key and peer id do not match")
+	}
+	if config.Rotation.PreviousPeerID != "" {
+		rotation, err := config.Rotation.Decode()
+		if err != nil {
+			return fmt.Errorf("invalid key rotation: %v", err)
+		}
+		if err := rotation.Verify(config.PeerID); err != nil {
+			return fmt.Errorf("invalid key rotation: %v", err)
+		}
+	}
+	return nil
+}
 
-	err = config.Routing.Validate()
+// PrivateKey loads the private key from the configuration, the key file or the external signer.
+func (config IdentityConfig) PrivateKey() (crypto.PrivKey, error) {
+	return keystore.Source{
+		PrivKey:        config.PrivKey,
+		KeyFile:        config.KeyFile,
+		PassphraseFile: config.PassphraseFile,
+		Signer:         config.Signer,
+	}.Load()
+}
+
+// Decode decodes the proof of the key rotation.
+func (config IdentityRotationConfig) Decode() (*keystore.Rotation, error) {
+	pub, err := crypto.ConfigDecodeKey(config.PreviousPubKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	err = config.App.Validate()
+	sig, err := crypto.ConfigDecodeKey(config.Signature)
 	if err != nil {
-		return err
+		return nil, err
 	}
-
-	return nil
+	return &keystore.Rotation{
+		PreviousPeerID: config.PreviousPeerID,
+		PreviousPubKey: pub,
+		Signature:      sig,
+	}, nil
 }
 
-func (config APIConfig) Validate() error {
-	if config.Addr == "" {
-		return fmt.Errorf("http api address can not be empty")
+// NewIdentityRotationConfig encodes the proof of the key rotation.
+func NewIdentityRotationConfig(rotation *keystore.Rotation) IdentityRotationConfig {
+	return IdentityRotationConfig{
+		PreviousPeerID: rotation.PreviousPeerID,
+		PreviousPubKey: crypto.ConfigEncodeKey(rotation.PreviousPubKey),
+		Signature:      crypto.ConfigEncodeKey(rotation.Signature),
 	}
-	return nil
 }
 
-func (config IdentityConfig) Validate() error {
-	peer, err := PeerIDFromPrivKeyString(config.PrivKey)
+func (config SwarmConfig) Validate() error {
+	err := config.ConnMgr.Validate()
 	if err != nil {
 		return err
 	}
-	if peer.String() != config.PeerID {
-		return fmt.Errorf("private key and peer id do not match")
+	for _, relay := range config.RelayClient.StaticRelays {
+		if _, err := multiaddr.NewMultiaddr(relay); err != nil {
+			return fmt.Errorf("StaticRelays %q: %v", relay, err)
+		}
+	}
+	if err := config.RelayService.Validate(); err != nil {
+		return err
 	}
 	return nil
 }
 
-func (config SwarmConfig) Validate() error {
-	err := config.ConnMgr.Validate()
-	if err != nil {
+func (config SwarmRelayServiceConfig) Validate() error {
+	if _, err := time.ParseDuration(config.ConnectionDurationLimit); err != nil {
 		return err
 	}
+	if config.ConnectionDataLimit <= 0 {
+		return fmt.Errorf("relay connection data limit must be a positive integer")
+	}
 	return nil
 }
 
@@ -191,6 +360,15 @@ func (config PubsubConfig) Validate() error {
 	if config.Router != "gossipsub" && config.Router != "floodsub" {
 		return fmt.Errorf("unknowned pubsub router")
 	}
+	if config.MaxMessageSize <= 0 {
+		return fmt.Errorf("max message size must be a positive integer")
+	}
+	if config.MaxChunkMemory < config.MaxMessageSize {
+		return fmt.Errorf("max chunk memory can not be less than max message size")
+	}
+	if _, err := time.ParseDuration(config.ChunkTimeout); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -235,12 +413,32 @@ func (config AppConfig) Validate() error {
 	if s, err := os.Stat(config.Datastore); err != nil || !s.IsDir() {
 		return fmt.Errorf("datastore must be a folder that already exists")
 	}
+	switch config.DatastoreBackend {
+	case "leveldb", "memory", "sqlite":
+	default:
+		return fmt.Errorf("unknowned datastore backend")
+	}
 	if err := config.AutoUpgrade.Validate(); err != nil {
 		return err
 	}
 	if err := config.PeersCollect.Validate(); err != nil {
 		return err
 	}
+	if err := config.RemoteQuery.Validate(); err != nil {
+		return err
+	}
+	if err := config.ImageStore.Validate(); err != nil {
+		return err
+	}
+	if err := config.Jobs.Validate(); err != nil {
+		return err
+	}
+	if err := config.Scheduler.Validate(); err != nil {
+		return err
+	}
+	if err := config.ResponseCache.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -258,6 +456,85 @@ func (config AppPeersCollectConfig) Validate() error {
 	return nil
 }
 
+func (config AppRemoteQueryConfig) Validate() error {
+	if _, err := time.ParseDuration(config.CacheTTL); err != nil {
+		return err
+	}
+	if _, err := time.ParseDuration(config.MinInterval); err != nil {
+		return err
+	}
+	if config.BatchConcurrency <= 0 {
+		return fmt.Errorf("batch concurrency must be a positive integer")
+	}
+	return nil
+}
+
+func (config AppImageStoreConfig) Validate() error {
+	if _, err := time.ParseDuration(config.TTL); err != nil {
+		return err
+	}
+	if config.MaxSize <= 0 {
+		return fmt.Errorf("image store max size must be a positive integer")
+	}
+	return nil
+}
+
+func (config AppJobsConfig) Validate() error {
+	if _, err := time.ParseDuration(config.TTL); err != nil {
+		return err
+	}
+	if config.MaxRunning <= 0 {
+		return fmt.Errorf("max running jobs must be a positive integer")
+	}
+	return nil
+}
+
+func (config AppSchedulerConfig) Validate() error {
+	if config.MaxRunning <= 0 {
+		return fmt.Errorf("max running requests must be a positive integer")
+	}
+	if config.MaxQueued < 0 {
+		return fmt.Errorf("max queued requests can not be negative")
+	}
+	names := make(map[string]bool)
+	for _, class := range config.Classes {
+		if class.Name == "" || class.Name == "default" {
+			return fmt.Errorf("scheduler class name can not be empty or default")
+		}
+		if names[class.Name] {
+			return fmt.Errorf("duplicate scheduler class %s", class.Name)
+		}
+		names[class.Name] = true
+		if class.Weight <= 0 {
+			return fmt.Errorf("weight of scheduler class %s must be a positive integer", class.Name)
+		}
+		for _, id := range class.Peers {
+			if _, err := peer.Decode(id); err != nil {
+				return fmt.Errorf("invalid peer id %s of scheduler class %s: %v", id, class.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (config AppResponseCacheConfig) Validate() error {
+	if _, err := time.ParseDuration(config.TTL); err != nil {
+		return err
+	}
+	if config.MaxEntries <= 0 {
+		return fmt.Errorf("max cached responses must be a positive integer")
+	}
+	if config.MaxBytes <= 0 {
+		return fmt.Errorf("max bytes of the cached responses must be a positive integer")
+	}
+	for _, project := range config.Projects {
+		if project == "" {
+			return fmt.Errorf("cached project can not be empty")
+		}
+	}
+	return nil
+}
+
 // func (config Config) GetModelAPI(projectName, modelName, cid string) (*types.AIModelConfig, error) {
 // 	mi := &types.AIModelConfig{}
 // 	if projectName == "" || modelName == "" {
@@ -279,8 +556,10 @@ func (config AppPeersCollectConfig) Validate() error {
 // 	return mi, nil
 // }
 
+// SaveConfig writes the configuration to the file, the values overridden by
+// the environment variables and the -set flags are saved as they are in the file.
 func (config Config) SaveConfig(configPath string) error {
-	jsonData, err := json.MarshalIndent(config, "", "  ")
+	jsonData, err := json.MarshalIndent(config.withoutOverrides(), "", "  ")
 	if err != nil {
 		// fmt.Println("Marshal pretty json:", err)
 		return err
@@ -292,60 +571,164 @@ func (config Config) SaveConfig(configPath string) error {
 	return nil
 }
 
-func LoadConfig(configPath string) (*Config, error) {
+// LoadConfig loads the configuration file, then overrides the values with the
+// environment variables of EnvPrefix and the key=value pairs of the -set flags.
+func LoadConfig(configPath string, sets ...string) (*Config, error) {
+	doc, err := readDocument(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return loadOverrides(doc, os.Environ(), sets)
+}
+
+// LoadConfigFile loads the configuration file without the overrides, for the commands rewriting the file.
+func LoadConfigFile(configPath string) (*Config, error) {
+	doc, err := readDocument(configPath)
+	if err != nil {
+		return nil, err
+	}
+	return decodeConfig(doc)
+}
+
+func readDocument(configPath string) (map[string]any, error) {
 	configFile, err := os.ReadFile(configPath)
 	if err != nil {
 		return nil, err
 	}
 
-	GC = &Config{}
-	err = json.Unmarshal(configFile, GC)
+	doc, err := parseDocument(configFile)
 	if err != nil {
 		return nil, err
 	}
+	// upgrade the older configuration in memory, host -migrate rewrites the file
+	if _, _, err := Migrate(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// decodeConfig decodes the migrated configuration document and fills the default values.
+func decodeConfig(doc map[string]any) (*Config, error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, err
+	}
+
+	if cfg.Swarm.ConnMgr.Type == "" {
+		cfg.Swarm.ConnMgr.Type = "basic"
+	}
+
+	if cfg.Swarm.ConnMgr.GracePeriod == "" {
+		cfg.Swarm.ConnMgr.GracePeriod = "20s"
+	}
+
+	if cfg.Swarm.ConnMgr.LowWater == 0 {
+		cfg.Swarm.ConnMgr.LowWater = 100
+	}
+
+	if cfg.Swarm.ConnMgr.HighWater == 0 {
+		cfg.Swarm.ConnMgr.HighWater = 400
+	}
+
+	if cfg.Swarm.RelayService.ConnectionDurationLimit == "" {
+		cfg.Swarm.RelayService.ConnectionDurationLimit = "30m"
+	}
+
+	if cfg.Swarm.RelayService.ConnectionDataLimit == 0 {
+		cfg.Swarm.RelayService.ConnectionDataLimit = 64 << 20
+	}
+
+	cfg.Pubsub.Enabled = true
+	if cfg.Pubsub.Router == "" {
+		cfg.Pubsub.Router = "gossipsub"
+	}
+
+	if cfg.Pubsub.MaxMessageSize == 0 {
+		cfg.Pubsub.MaxMessageSize = 64 << 20
+	}
+
+	if cfg.Pubsub.MaxChunkMemory == 0 {
+		cfg.Pubsub.MaxChunkMemory = 256 << 20
+	}
+
+	if cfg.Pubsub.ChunkTimeout == "" {
+		cfg.Pubsub.ChunkTimeout = "60s"
+	}
+
+	if cfg.Routing.Type == "" {
+		cfg.Routing.Type = "auto"
+	}
+
+	if cfg.App.LogLevel == "" {
+		cfg.App.LogLevel = "info"
+	}
+
+	if cfg.App.LogOutput == "" {
+		cfg.App.LogOutput = "stderr"
+	}
+
+	if cfg.App.DatastoreBackend == "" {
+		cfg.App.DatastoreBackend = "leveldb"
+	}
+
+	if cfg.App.AutoUpgrade.TimeInterval == "" {
+		cfg.App.AutoUpgrade.TimeInterval = "1h"
+	}
+
+	if cfg.App.PeersCollect.HeartbeatInterval == "" {
+		cfg.App.PeersCollect.HeartbeatInterval = "180s"
+	}
+
+	if cfg.App.RemoteQuery.CacheTTL == "" {
+		cfg.App.RemoteQuery.CacheTTL = "5m"
+	}
 
-	if GC.Swarm.ConnMgr.Type == "" {
-		GC.Swarm.ConnMgr.Type = "basic"
+	if cfg.App.RemoteQuery.MinInterval == "" {
+		cfg.App.RemoteQuery.MinInterval = "10s"
 	}
 
-	if GC.Swarm.ConnMgr.GracePeriod == "" {
-		GC.Swarm.ConnMgr.GracePeriod = "20s"
+	if cfg.App.RemoteQuery.BatchConcurrency == 0 {
+		cfg.App.RemoteQuery.BatchConcurrency = 16
 	}
 
-	if GC.Swarm.ConnMgr.LowWater == 0 {
-		GC.Swarm.ConnMgr.LowWater = 100
+	if cfg.App.ImageStore.TTL == "" {
+		cfg.App.ImageStore.TTL = "24h"
 	}
 
-	if GC.Swarm.ConnMgr.HighWater == 0 {
-		GC.Swarm.ConnMgr.HighWater = 400
+	if cfg.App.ImageStore.MaxSize == 0 {
+		cfg.App.ImageStore.MaxSize = 32 << 20
 	}
 
-	GC.Pubsub.Enabled = true
-	if GC.Pubsub.Router == "" {
-		GC.Pubsub.Router = "gossipsub"
+	if cfg.App.Jobs.TTL == "" {
+		cfg.App.Jobs.TTL = "24h"
 	}
 
-	if GC.Routing.Type == "" {
-		GC.Routing.Type = "auto"
+	if cfg.App.Jobs.MaxRunning == 0 {
+		cfg.App.Jobs.MaxRunning = 4
 	}
 
-	if GC.App.LogLevel == "" {
-		GC.App.LogLevel = "info"
+	if cfg.App.Scheduler.MaxRunning == 0 {
+		cfg.App.Scheduler.MaxRunning = 8
+		cfg.App.Scheduler.MaxQueued = 256
 	}
 
-	if GC.App.LogOutput == "" {
-		GC.App.LogOutput = "stderr"
+	if cfg.App.ResponseCache.TTL == "" {
+		cfg.App.ResponseCache.TTL = "10m"
 	}
 
-	if GC.App.AutoUpgrade.TimeInterval == "" {
-		GC.App.AutoUpgrade.TimeInterval = "1h"
+	if cfg.App.ResponseCache.MaxEntries == 0 {
+		cfg.App.ResponseCache.MaxEntries = 10000
 	}
 
-	if GC.App.PeersCollect.HeartbeatInterval == "" {
-		GC.App.PeersCollect.HeartbeatInterval = "180s"
+	if cfg.App.ResponseCache.MaxBytes == 0 {
+		cfg.App.ResponseCache.MaxBytes = 64 << 20
 	}
 
-	return GC, nil
+	return cfg, nil
 }
 
 func isTerm(f *os.File) bool {
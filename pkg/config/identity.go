@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"os"
+
+	"AIComputingNode/pkg/keystore"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// SetPrivateKey replaces the identity with the private key. The key is written to the key file
+// encrypted by the passphrase, or unencrypted if passphrase is nil, and saved in PrivKey if keyFile is empty.
+func (config *IdentityConfig) SetPrivateKey(priv crypto.PrivKey, keyFile string, passphrase func() ([]byte, error)) error {
+	id, err := peer.IDFromPrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	if keyFile == "" {
+		config.PrivKey, err = keystore.PrivKeyToString(priv)
+		if err != nil {
+			return err
+		}
+	} else {
+		if passphrase == nil {
+			err = SavePeerKey(keyFile, priv)
+		} else {
+			var pass []byte
+			if pass, err = passphrase(); err == nil {
+				err = keystore.WriteEncryptedKeyFile(keyFile, priv, pass)
+			}
+		}
+		if err != nil {
+			return err
+		}
+		config.PrivKey = ""
+	}
+	config.PeerID = id.String()
+	config.KeyFile = keyFile
+	config.Signer = ""
+	config.Rotation = IdentityRotationConfig{}
+	return nil
+}
+
+// Rotate replaces the private key by the new one in the same storage, and keeps the proof
+// signed by the previous key to be announced in the heartbeats. The key file is overwritten.
+func (config *IdentityConfig) Rotate(priv crypto.PrivKey) error {
+	if config.Signer != "" {
+		return errors.New("the key of the external signer must be rotated by the signer")
+	}
+	previous, err := config.PrivateKey()
+	if err != nil {
+		return err
+	}
+	id, err := peer.IDFromPrivateKey(priv)
+	if err != nil {
+		return err
+	}
+	rotation, err := keystore.SignRotation(previous, id)
+	if err != nil {
+		return err
+	}
+
+	var passphrase func() ([]byte, error)
+	if config.KeyFile != "" {
+		data, err := os.ReadFile(config.KeyFile)
+		if err != nil {
+			return err
+		}
+		if keystore.IsEncrypted(data) {
+			passphrase = keystore.ReadPassphrase(config.PassphraseFile)
+		}
+	}
+	if err := config.SetPrivateKey(priv, config.KeyFile, passphrase); err != nil {
+		return err
+	}
+	config.Rotation = NewIdentityRotationConfig(rotation)
+	return nil
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"AIComputingNode/pkg/keystore"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+)
+
+func newIdentity(t *testing.T, keyFile string, passphrase func() ([]byte, error)) (IdentityConfig, crypto.PrivKey) {
+	priv, _, err := crypto.GenerateKeyPair(crypto.Secp256k1, -1)
+	if err != nil {
+		t.Fatalf("Generate key: %v", err)
+	}
+	var identity IdentityConfig
+	if err := identity.SetPrivateKey(priv, keyFile, passphrase); err != nil {
+		t.Fatalf("Set private key: %v", err)
+	}
+	return identity, priv
+}
+
+func TestRotateIdentity(t *testing.T) {
+	identity, previous := newIdentity(t, "", nil)
+	if err := identity.Validate(); err != nil {
+		t.Fatalf("Validate identity: %v", err)
+	}
+	previousID := identity.PeerID
+
+	priv, _, _ := crypto.GenerateKeyPair(crypto.Secp256k1, -1)
+	if err := identity.Rotate(priv); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	if err := identity.Validate(); err != nil {
+		t.Errorf("Validate rotated identity: %v", err)
+	}
+	if identity.PeerID == previousID || identity.Rotation.PreviousPeerID != previousID {
+		t.Errorf("Unexpected rotated identity %+v", identity)
+	}
+	if key, err := identity.PrivateKey(); err != nil || !key.Equals(priv) || key.Equals(previous) {
+		t.Errorf("Unexpected rotated key: %v", err)
+	}
+
+	// the proof must be signed by the previous key for the current peer id
+	identity.Rotation.PreviousPeerID = identity.PeerID
+	if err := identity.Validate(); err == nil {
+		t.Error("Validate identity with invalid rotation succeeded")
+	}
+}
+
+func TestEncryptedIdentity(t *testing.T) {
+	dir := t.TempDir()
+	keyFile := filepath.Join(dir, "peer.json")
+	t.Setenv(keystore.PassphraseEnv, "secret")
+	identity, _ := newIdentity(t, keyFile, keystore.ReadPassphrase(""))
+	if identity.PrivKey != "" || identity.KeyFile != keyFile {
+		t.Errorf("Unexpected identity %+v", identity)
+	}
+	data, _ := os.ReadFile(keyFile)
+	if !keystore.IsEncrypted(data) {
+		t.Errorf("Key file not encrypted %s", data)
+	}
+	if err := identity.Validate(); err != nil {
+		t.Fatalf("Validate identity: %v", err)
+	}
+
+	priv, _, _ := crypto.GenerateKeyPair(crypto.Secp256k1, -1)
+	if err := identity.Rotate(priv); err != nil {
+		t.Fatalf("Rotate: %v", err)
+	}
+	data, _ = os.ReadFile(keyFile)
+	if !keystore.IsEncrypted(data) || identity.Validate() != nil {
+		t.Errorf("Rotated key file not encrypted or invalid %s", data)
+	}
+
+	t.Setenv(keystore.PassphraseEnv, "wrong")
+	if err := identity.Validate(); err == nil {
+		t.Error("Validate identity with wrong passphrase succeeded")
+	}
+	identity.PrivKey = "CAISIA=="
+	if err := identity.Validate(); err == nil {
+		t.Error("Validate identity with both private key and key file succeeded")
+	}
+}
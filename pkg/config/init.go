@@ -6,6 +6,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"AIComputingNode/pkg/keystore"
 	"AIComputingNode/pkg/types"
 
 	"github.com/libp2p/go-libp2p/core/crypto"
@@ -18,7 +19,8 @@ var (
 	TopicName      string = "DeepBrainChain"
 )
 
-func Init(mode string) error {
+// Init generates a key of the key type, see keystore.KeyTypes, and the configuration file of the mode.
+func Init(mode, keyType string) error {
 	cwd, err := os.Getwd()
 	if err != nil {
 		fmt.Println("Failed to get program directory:", err)
@@ -26,11 +28,12 @@ func Init(mode string) error {
 	}
 
 	keyPath := GetUniqueFile(cwd, "peer", "key")
-	privKey, pubKey, err := crypto.GenerateKeyPair(crypto.Secp256k1, -1)
+	privKey, err := keystore.GenerateKey(keyType)
 	if err != nil {
 		fmt.Println("Generate peer key: ", err)
 		return err
 	}
+	pubKey := privKey.GetPublic()
 	if err := SavePeerKey(keyPath, privKey); err != nil {
 		fmt.Println("Save peer key:", err)
 		return err
@@ -59,6 +62,7 @@ func Init(mode string) error {
 		return err
 	}
 	fmt.Println("Transform Peer ID:", id)
+	fmt.Println("Key type:", keystore.KeyTypeOf(privKey))
 
 	tcpPort := 7001
 	for !CheckPortAvailability(tcpPort) {
@@ -77,6 +81,7 @@ func Init(mode string) error {
 	fmt.Println("Create datastore directory at", dataPath)
 
 	config := Config{
+		Version:   ConfigVersion,
 		Bootstrap: []string{},
 		Addresses: []string{
 			fmt.Sprintf("/ip4/0.0.0.0/tcp/%d", tcpPort),
@@ -101,27 +106,33 @@ func Init(mode string) error {
 				Enabled: false,
 			},
 			RelayService: SwarmRelayServiceConfig{
-				Enabled: false,
+				Enabled:                 false,
+				ConnectionDurationLimit: "30m",
+				ConnectionDataLimit:     64 << 20,
 			},
 			EnableHolePunching:   false,
 			EnableAutoNATService: true,
 		},
 		Pubsub: PubsubConfig{
-			Enabled:      true,
-			Router:       "floodsub",
-			FloodPublish: true,
+			Enabled:        true,
+			Router:         "floodsub",
+			FloodPublish:   true,
+			MaxMessageSize: 64 << 20,
+			MaxChunkMemory: 256 << 20,
+			ChunkTimeout:   "60s",
 		},
 		Routing: RoutingConfig{
 			Type:           "dhtclient",
 			ProtocolPrefix: ProtocolPrefix,
 		},
 		App: AppConfig{
-			LogLevel:     "info",
-			LogFile:      filepath.Join(cwd, "host.log"),
-			LogOutput:    "file",
-			PreSharedKey: PreSharedKey,
-			TopicName:    TopicName,
-			Datastore:    dataPath,
+			LogLevel:         "info",
+			LogFile:          filepath.Join(cwd, "host.log"),
+			LogOutput:        "file",
+			PreSharedKey:     PreSharedKey,
+			TopicName:        TopicName,
+			Datastore:        dataPath,
+			DatastoreBackend: "leveldb",
 			AutoUpgrade: AutoUpgradeConfig{
 				Enabled:      true,
 				TimeInterval: "1h",
@@ -131,6 +142,32 @@ func Init(mode string) error {
 				HeartbeatInterval: "180s",
 				ClientProject:     "",
 			},
+			RemoteQuery: AppRemoteQueryConfig{
+				CacheTTL:         "5m",
+				MinInterval:      "10s",
+				BatchConcurrency: 16,
+			},
+			ImageStore: AppImageStoreConfig{
+				Enabled: false,
+				TTL:     "24h",
+				MaxSize: 32 << 20,
+			},
+			Jobs: AppJobsConfig{
+				TTL:                 "24h",
+				MaxRunning:          4,
+				WebhookAllowedHosts: []string{},
+			},
+			Scheduler: AppSchedulerConfig{
+				MaxRunning: 8,
+				MaxQueued:  256,
+				Classes:    []AppSchedulerClassConfig{},
+			},
+			ResponseCache: AppResponseCacheConfig{
+				Projects:   []string{},
+				TTL:        "10m",
+				MaxEntries: 10000,
+				MaxBytes:   64 << 20,
+			},
 		},
 		AIProjects: []types.AIProjectConfig{},
 	}
@@ -174,14 +211,19 @@ func GetUniqueFile(dir string, name string, ext string) string {
 	return filePath
 }
 
-func PeerKeyParse(peerKeyPath string) error {
+// PeerKeyParse prints the key file, a key of the key type is generated if the file does not exist.
+func PeerKeyParse(peerKeyPath, keyType string) error {
 	privKey, pubKey, err := LoadPeerKey(peerKeyPath)
-	if err != nil {
-		privKey, pubKey, err = crypto.GenerateKeyPair(crypto.Secp256k1, -1)
+	if err != nil && !os.IsNotExist(err) {
+		fmt.Println("Load peer key:", err)
+		return err
+	} else if err != nil {
+		privKey, err = keystore.GenerateKey(keyType)
 		if err != nil {
 			fmt.Println("Generate peer key:", err)
 			return err
 		}
+		pubKey = privKey.GetPublic()
 		err := SavePeerKey(peerKeyPath, privKey)
 		if err != nil {
 			fmt.Println("Save peer key:", err)
@@ -212,16 +254,17 @@ func PeerKeyParse(peerKeyPath string) error {
 		return err
 	}
 	fmt.Println("Transform Peer ID:", id)
+	fmt.Println("Key type:", keystore.KeyTypeOf(privKey))
 	return nil
 }
 
+// LoadPeerKey loads the key file, an encrypted key file is decrypted by AICN_KEYSTORE_PASSPHRASE.
 func LoadPeerKey(filePath string) (crypto.PrivKey, crypto.PubKey, error) {
-	privBytes, err := os.ReadFile(filePath)
+	priv, err := keystore.ReadKeyFile(filePath, keystore.ReadPassphrase(""))
 	if err != nil {
 		return nil, nil, err
 	}
-	priv, err := crypto.UnmarshalPrivateKey(privBytes)
-	return priv, priv.GetPublic(), err
+	return priv, priv.GetPublic(), nil
 }
 
 func SavePeerKey(filePath string, priv crypto.PrivKey) error {
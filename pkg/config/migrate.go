@@ -0,0 +1,312 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ConfigVersion is the schema version of the configuration files written by this program.
+const ConfigVersion = 1
+
+type migration struct {
+	// Version of the configuration after the migration
+	version     int
+	description string
+	migrate     func(doc map[string]any) error
+}
+
+// migrations upgrade the configuration document one version at a time,
+// the migration to version n is migrations[n-1].
+var migrations = []migration{
+	{
+		version:     1,
+		description: "add the Version field and rename the keys to the exact case of the configuration fields",
+		migrate: func(doc map[string]any) error {
+			canonicalKeys(doc, reflect.TypeOf(Config{}))
+			return nil
+		},
+	},
+}
+
+// documentVersion returns the version of the configuration document, 0 if not set.
+func documentVersion(doc map[string]any) (int, error) {
+	value, ok := doc["Version"]
+	if !ok {
+		return 0, nil
+	}
+	var version float64
+	switch v := value.(type) {
+	case float64:
+		version = v
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, fmt.Errorf("invalid config version %v", v)
+		}
+		version = f
+	default:
+		return 0, fmt.Errorf("invalid config version %v", value)
+	}
+	if version < 0 || version != float64(int(version)) {
+		return 0, fmt.Errorf("invalid config version %v", value)
+	}
+	return int(version), nil
+}
+
+// Migrate upgrades the configuration document to ConfigVersion in place,
+// and returns the version of the document before and the descriptions of the applied migrations.
+func Migrate(doc map[string]any) (int, []string, error) {
+	version, err := documentVersion(doc)
+	if err != nil {
+		return 0, nil, err
+	}
+	if version > ConfigVersion {
+		return version, nil, fmt.Errorf("config version %d is newer than the supported version %d", version, ConfigVersion)
+	}
+	applied := make([]string, 0)
+	for _, m := range migrations[version:] {
+		if err := m.migrate(doc); err != nil {
+			return version, applied, fmt.Errorf("migrate config to version %d: %v", m.version, err)
+		}
+		doc["Version"] = m.version
+		applied = append(applied, fmt.Sprintf("version %d: %s", m.version, m.description))
+	}
+	return version, applied, nil
+}
+
+// jsonFields returns the json names of the fields of the struct type, including the embedded structs.
+func jsonFields(t reflect.Type) map[string]reflect.StructField {
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for k, v := range jsonFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f
+	}
+	return fields
+}
+
+// findField finds the field of the key like encoding/json, the exact name first and then case-insensitively.
+func findField(fields map[string]reflect.StructField, key string) (string, reflect.StructField, bool) {
+	if f, ok := fields[key]; ok {
+		return key, f, true
+	}
+	for name, f := range fields {
+		if strings.EqualFold(name, key) {
+			return name, f, true
+		}
+	}
+	return "", reflect.StructField{}, false
+}
+
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+// walkDocument calls fn with every object of the document and its struct type.
+func walkDocument(value any, t reflect.Type, path string, fn func(obj map[string]any, t reflect.Type, path string)) {
+	t = elemType(t)
+	switch v := value.(type) {
+	case map[string]any:
+		if t.Kind() != reflect.Struct {
+			return
+		}
+		fn(v, t, path)
+		fields := jsonFields(t)
+		for key, child := range v {
+			if _, f, ok := findField(fields, key); ok {
+				walkDocument(child, f.Type, path+"."+key, fn)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			walkDocument(item, t, fmt.Sprintf("%s[%d]", path, i), fn)
+		}
+	}
+}
+
+// canonicalKeys renames the keys of the document to the exact json names of the fields.
+func canonicalKeys(doc map[string]any, t reflect.Type) {
+	walkDocument(doc, t, "", func(obj map[string]any, t reflect.Type, path string) {
+		fields := jsonFields(t)
+		keys := make([]string, 0, len(obj))
+		for key := range obj {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			name, _, ok := findField(fields, key)
+			if !ok || name == key {
+				continue
+			}
+			// the exact key wins over the case-insensitive ones, like encoding/json
+			if _, exist := obj[name]; !exist {
+				obj[name] = obj[key]
+			}
+			delete(obj, key)
+		}
+	})
+}
+
+// UnknownKeys returns the paths of the keys in the configuration document which match no field,
+// their values are ignored when loading the configuration.
+func UnknownKeys(doc map[string]any) []string {
+	unknown := make([]string, 0)
+	walkDocument(doc, reflect.TypeOf(Config{}), "", func(obj map[string]any, t reflect.Type, path string) {
+		fields := jsonFields(t)
+		for key := range obj {
+			if _, _, ok := findField(fields, key); !ok {
+				unknown = append(unknown, strings.TrimPrefix(path+"."+key, "."))
+			}
+		}
+	})
+	sort.Strings(unknown)
+	return unknown
+}
+
+func parseDocument(data []byte) (map[string]any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	// keep the numbers as they are written
+	dec.UseNumber()
+	doc := make(map[string]any)
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// MigrationResult is the result of migrating a configuration file.
+type MigrationResult struct {
+	FromVersion int
+	ToVersion   int
+	Applied     []string
+	UnknownKeys []string
+	// Content of the configuration file before and after the migration
+	Before []byte
+	After  []byte
+	// Path of the backup of the configuration file, empty if the file is not written
+	Backup string
+	Config *Config
+}
+
+// Changed reports whether the configuration file is changed by the migration.
+func (r *MigrationResult) Changed() bool {
+	return !bytes.Equal(bytes.TrimSpace(r.Before), bytes.TrimSpace(r.After))
+}
+
+// MigrateConfigFile migrates the configuration file to ConfigVersion and fills the default values.
+// When write is true and the file is changed, the file is rewritten in place after being backed up.
+func MigrateConfigFile(configPath string, write bool) (*MigrationResult, error) {
+	before, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := parseDocument(before)
+	if err != nil {
+		return nil, err
+	}
+	result := &MigrationResult{
+		ToVersion:   ConfigVersion,
+		UnknownKeys: UnknownKeys(doc),
+		Before:      before,
+	}
+	result.FromVersion, result.Applied, err = Migrate(doc)
+	if err != nil {
+		return result, err
+	}
+	result.Config, err = decodeConfig(doc)
+	if err != nil {
+		return result, err
+	}
+	result.After, err = json.MarshalIndent(result.Config, "", "  ")
+	if err != nil {
+		return result, err
+	}
+
+	if !write || !result.Changed() {
+		return result, nil
+	}
+	result.Backup, err = BackupFile(configPath, fmt.Sprintf("v%d", result.FromVersion))
+	if err != nil {
+		return result, fmt.Errorf("backup config: %v", err)
+	}
+	if err := result.Config.SaveConfig(configPath); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+// BackupFile copies the file to a new file named like <file>.<tag>.bak in the same directory,
+// and returns the path of the backup.
+func BackupFile(path, tag string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	dir, name := filepath.Split(path)
+	backup := GetUniqueFile(dir, fmt.Sprintf("%s.%s", name, tag), "bak")
+	if err := os.WriteFile(backup, data, 0600); err != nil {
+		return "", err
+	}
+	return backup, nil
+}
+
+// Diff returns the line differences between the two contents,
+// the removed lines are prefixed with "- " and the added lines with "+ ".
+func Diff(before, after []byte) string {
+	a := strings.Split(strings.TrimSpace(string(before)), "\n")
+	b := strings.Split(strings.TrimSpace(string(after)), "\n")
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+	var sb strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			sb.WriteString("+ " + b[j] + "\n")
+			j++
+		default:
+			sb.WriteString("- " + a[i] + "\n")
+			i++
+		}
+	}
+	return sb.String()
+}
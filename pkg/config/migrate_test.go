@@ -0,0 +1,85 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const configV0 = `{
+  "Bootstrap": [],
+  "Addresses": ["/ip4/0.0.0.0/tcp/6001"],
+  "api": {"addr": "127.0.0.1:6000"},
+  "Identity": {"PrivKey": "", "PeerID": ""},
+  "App": {"LogLevel": "debug", "Obsolete": true},
+  "AIProjects": [{"Project": "DecentralGPT", "models": [{"Model": "Llama3-70B", "api": "http://127.0.0.1:1042/v1/chat/completions", "Type": 0}]}]
+}`
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigMigration(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, configV0))
+	if err != nil {
+		t.Fatalf("Load config: %v", err)
+	}
+	if cfg.Version != ConfigVersion || cfg.API.Addr != "127.0.0.1:6000" || cfg.App.LogLevel != "debug" {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+	if len(cfg.AIProjects) != 1 || len(cfg.AIProjects[0].Models) != 1 ||
+		cfg.AIProjects[0].Models[0].API != "http://127.0.0.1:1042/v1/chat/completions" {
+		t.Errorf("Unexpected AI projects %+v", cfg.AIProjects)
+	}
+
+	if _, err := LoadConfig(writeConfig(t, `{"Version": 99}`)); err == nil {
+		t.Error("Load config of newer version succeeded")
+	}
+}
+
+func TestMigrateConfigFile(t *testing.T) {
+	path := writeConfig(t, configV0)
+
+	result, err := MigrateConfigFile(path, false)
+	if err != nil {
+		t.Fatalf("Check config: %v", err)
+	}
+	if result.FromVersion != 0 || len(result.Applied) != 1 || !result.Changed() || result.Backup != "" {
+		t.Errorf("Unexpected result %+v", result)
+	}
+	if strings.Join(result.UnknownKeys, ",") != "App.Obsolete" {
+		t.Errorf("Unexpected unknown keys %v", result.UnknownKeys)
+	}
+	diff := Diff(result.Before, result.After)
+	if !strings.Contains(diff, `+   "Version": 1,`) || !strings.Contains(diff, `-   "api": {"addr": "127.0.0.1:6000"},`) {
+		t.Errorf("Unexpected diff:\n%s", diff)
+	}
+
+	result, err = MigrateConfigFile(path, true)
+	if err != nil {
+		t.Fatalf("Migrate config: %v", err)
+	}
+	backup, err := os.ReadFile(result.Backup)
+	if err != nil || string(backup) != configV0 {
+		t.Errorf("Unexpected backup %s: %v", result.Backup, err)
+	}
+	data, _ := os.ReadFile(path)
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil || doc["Version"] != float64(ConfigVersion) || doc["API"] == nil {
+		t.Errorf("Unexpected migrated config %s: %v", data, err)
+	}
+
+	result, err = MigrateConfigFile(path, true)
+	if err != nil {
+		t.Fatalf("Migrate config again: %v", err)
+	}
+	if result.FromVersion != ConfigVersion || len(result.Applied) != 0 || result.Changed() || result.Backup != "" {
+		t.Errorf("Unexpected result of migrated config %+v", result)
+	}
+}
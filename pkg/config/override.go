@@ -0,0 +1,229 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EnvPrefix is the prefix of the environment variables which override the configuration values,
+// such as AICN_API_ADDR for API.Addr and AICN_APP_PEERSCOLLECT_ENABLED for App.PeersCollect.Enabled.
+const EnvPrefix = "AICN_"
+
+// RedactedValue replaces the secrets in the redacted configuration.
+const RedactedValue = "******"
+
+// overrideField is a configuration value which can be overridden.
+type overrideField struct {
+	// json names of the field and its parents, such as ["App", "PeersCollect", "Enabled"]
+	path []string
+	typ  reflect.Type
+}
+
+func (f overrideField) key() string {
+	return strings.Join(f.path, ".")
+}
+
+func (f overrideField) env() string {
+	return EnvPrefix + strings.ToUpper(strings.Join(f.path, "_"))
+}
+
+// overrideFields returns all the fields of the configuration except the structs,
+// the slices such as Bootstrap are overridden as a whole.
+func overrideFields(t reflect.Type, parent []string) []overrideField {
+	fields := make([]overrideField, 0)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" || name == "Version" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		path := append(append([]string{}, parent...), name)
+		if f.Type.Kind() == reflect.Struct {
+			fields = append(fields, overrideFields(f.Type, path)...)
+		} else {
+			fields = append(fields, overrideField{path: path, typ: f.Type})
+		}
+	}
+	return fields
+}
+
+// findOverrideField finds the field of the dotted key case-insensitively.
+func findOverrideField(key string) (overrideField, bool) {
+	for _, f := range overrideFields(reflect.TypeOf(Config{}), nil) {
+		if strings.EqualFold(f.key(), key) {
+			return f, true
+		}
+	}
+	return overrideField{}, false
+}
+
+// parseOverrideValue parses the string value of the field, the lists are comma separated
+// or JSON arrays and the other types which are not scalars are JSON.
+func parseOverrideValue(t reflect.Type, value string) (any, error) {
+	switch t.Kind() {
+	case reflect.String:
+		return value, nil
+	case reflect.Bool:
+		return strconv.ParseBool(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.ParseInt(value, 10, 64)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseUint(value, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(value, 64)
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.String && !strings.HasPrefix(strings.TrimSpace(value), "[") {
+			items := make([]any, 0)
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					items = append(items, item)
+				}
+			}
+			return items, nil
+		}
+	}
+	var v any
+	if err := json.Unmarshal([]byte(value), &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// setDocumentValue sets the value of the migrated configuration document at the path.
+func setDocumentValue(doc map[string]any, path []string, value any) {
+	obj := doc
+	for _, name := range path[:len(path)-1] {
+		child, ok := obj[name].(map[string]any)
+		if !ok {
+			child = make(map[string]any)
+			obj[name] = child
+		}
+		obj = child
+	}
+	obj[path[len(path)-1]] = value
+}
+
+// applyOverrides sets the values of the environment variables and then the key=value pairs
+// to the migrated configuration document, and returns the overridden keys with their sources.
+func applyOverrides(doc map[string]any, environ []string, sets []string) (map[string]string, error) {
+	overrides := make(map[string]string)
+	apply := func(f overrideField, value, source string) error {
+		v, err := parseOverrideValue(f.typ, value)
+		if err != nil {
+			return fmt.Errorf("invalid value of %s: %v", source, err)
+		}
+		setDocumentValue(doc, f.path, v)
+		overrides[f.key()] = source
+		return nil
+	}
+
+	envFields := make(map[string]overrideField)
+	for _, f := range overrideFields(reflect.TypeOf(Config{}), nil) {
+		envFields[f.env()] = f
+	}
+	sort.Strings(environ)
+	for _, kv := range environ {
+		name, value, _ := strings.Cut(kv, "=")
+		// the other variables of the prefix, such as AICN_API_TOKEN of host ctl, are not configuration values
+		f, ok := envFields[name]
+		if !ok {
+			continue
+		}
+		if err := apply(f, value, name); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, kv := range sets {
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid -set %q, must be key=value", kv)
+		}
+		f, ok := findOverrideField(strings.TrimSpace(key))
+		if !ok {
+			return nil, fmt.Errorf("unknown configuration key %q", key)
+		}
+		if err := apply(f, value, "-set "+f.key()); err != nil {
+			return nil, err
+		}
+	}
+	return overrides, nil
+}
+
+// fieldByKey returns the field of the configuration value at the dotted key of json names.
+func fieldByKey(v reflect.Value, key string) reflect.Value {
+	for _, name := range strings.Split(key, ".") {
+		_, f, ok := findField(jsonFields(v.Type()), name)
+		if !ok {
+			return reflect.Value{}
+		}
+		v = v.FieldByIndex(f.Index)
+	}
+	return v
+}
+
+// Overrides returns the configuration keys overridden by the environment variables
+// and the -set flags, with the name of the variable or flag.
+func (config Config) Overrides() map[string]string {
+	overrides := make(map[string]string, len(config.overrides))
+	for k, v := range config.overrides {
+		overrides[k] = v
+	}
+	return overrides
+}
+
+// withoutOverrides returns the configuration with the overridden values of the file,
+// so that the environment variables and the -set flags are never saved into the file.
+func (config Config) withoutOverrides() Config {
+	if config.file == nil {
+		return config
+	}
+	dst := reflect.ValueOf(&config).Elem()
+	src := reflect.ValueOf(config.file).Elem()
+	for key := range config.overrides {
+		if field := fieldByKey(dst, key); field.IsValid() {
+			field.Set(fieldByKey(src, key))
+		}
+	}
+	return config
+}
+
+// Redacted returns a copy of the configuration with the secrets replaced by RedactedValue.
+func (config Config) Redacted() Config {
+	if config.Identity.PrivKey != "" {
+		config.Identity.PrivKey = RedactedValue
+	}
+	if config.App.PreSharedKey != "" {
+		config.App.PreSharedKey = RedactedValue
+	}
+	return config
+}
+
+// loadOverrides applies the environment variables and the -set flags, environ is os.Environ().
+func loadOverrides(doc map[string]any, environ []string, sets []string) (*Config, error) {
+	file, err := decodeConfig(doc)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := applyOverrides(doc, environ, sets)
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) == 0 {
+		return file, nil
+	}
+	cfg, err := decodeConfig(doc)
+	if err != nil {
+		return nil, err
+	}
+	cfg.file = file
+	cfg.overrides = overrides
+	return cfg, nil
+}
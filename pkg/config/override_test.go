@@ -0,0 +1,77 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"AIComputingNode/pkg/types"
+)
+
+const configOverride = `{
+  "Version": 1,
+  "Bootstrap": ["/ip4/127.0.0.1/tcp/6001/p2p/16Uiu2HAmRTpigc7jAbsLndB2xDEBMAXLb887SBEFhfdJeEJNtqRM"],
+  "Addresses": ["/ip4/0.0.0.0/tcp/6001"],
+  "API": {"Addr": "127.0.0.1:6000"},
+  "App": {"Datastore": "./datastore", "PeersCollect": {"Enabled": false}}
+}`
+
+func TestLoadConfigOverrides(t *testing.T) {
+	path := writeConfig(t, configOverride)
+	t.Setenv("AICN_API_ADDR", "0.0.0.0:7000")
+	t.Setenv("AICN_APP_DATASTORE", "/data")
+	t.Setenv("AICN_APP_PRESHAREDKEY", "secret")
+	t.Setenv("AICN_BOOTSTRAP", "/ip4/10.0.0.1/tcp/6001, /ip4/10.0.0.2/tcp/6001")
+	// not a configuration value
+	t.Setenv("AICN_API_TOKEN", "token")
+
+	cfg, err := LoadConfig(path, "app.peerscollect.enabled=true", "App.Datastore=/mnt/data", "App.RemoteQuery.BatchConcurrency=4")
+	if err != nil {
+		t.Fatalf("Load config: %v", err)
+	}
+	if cfg.API.Addr != "0.0.0.0:7000" || cfg.App.PreSharedKey != "secret" || len(cfg.Bootstrap) != 2 ||
+		cfg.Bootstrap[1] != "/ip4/10.0.0.2/tcp/6001" || !cfg.App.PeersCollect.Enabled ||
+		cfg.App.RemoteQuery.BatchConcurrency != 4 {
+		t.Errorf("Unexpected config %+v", cfg)
+	}
+	// the flags override the environment variables
+	if cfg.App.Datastore != "/mnt/data" || cfg.Overrides()["App.Datastore"] != "-set App.Datastore" {
+		t.Errorf("Unexpected datastore %s from %s", cfg.App.Datastore, cfg.Overrides()["App.Datastore"])
+	}
+	if cfg.Overrides()["API.Addr"] != "AICN_API_ADDR" || len(cfg.Overrides()) != 6 {
+		t.Errorf("Unexpected overrides %v", cfg.Overrides())
+	}
+
+	redacted := cfg.Redacted()
+	if redacted.App.PreSharedKey != RedactedValue || cfg.App.PreSharedKey != "secret" || redacted.Identity.PrivKey != "" {
+		t.Errorf("Unexpected redacted config %+v", redacted.App)
+	}
+
+	// the overrides are not saved into the file
+	cfg.AIProjects = append(cfg.AIProjects, types.AIProjectConfig{Project: "DecentralGPT"})
+	if err := cfg.SaveConfig(path); err != nil {
+		t.Fatalf("Save config: %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	for _, value := range []string{"0.0.0.0:7000", "secret", "/mnt/data", "10.0.0.1"} {
+		if strings.Contains(string(data), value) {
+			t.Errorf("Overridden value %s saved into the file", value)
+		}
+	}
+	if !strings.Contains(string(data), "./datastore") || !strings.Contains(string(data), "DecentralGPT") {
+		t.Errorf("Unexpected saved config %s", data)
+	}
+}
+
+func TestLoadConfigInvalidOverrides(t *testing.T) {
+	path := writeConfig(t, configOverride)
+	for _, set := range []string{"App.Unknown=1", "App.LogLevel", "App.PeersCollect.Enabled=yes", "Swarm.ConnMgr.HighWater=many"} {
+		if _, err := LoadConfig(path, set); err == nil {
+			t.Errorf("Load config with -set %s succeeded", set)
+		}
+	}
+	t.Setenv("AICN_SWARM_CONNMGR_LOWWATER", "few")
+	if _, err := LoadConfig(path); err == nil || !strings.Contains(err.Error(), "AICN_SWARM_CONNMGR_LOWWATER") {
+		t.Errorf("Unexpected error of invalid environment variable %v", err)
+	}
+}
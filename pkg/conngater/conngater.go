@@ -1,7 +1,6 @@
 package conngater
 
 import (
-	"AIComputingNode/pkg/config"
 	"AIComputingNode/pkg/db"
 	"AIComputingNode/pkg/log"
 	"AIComputingNode/pkg/types"
@@ -16,6 +15,16 @@ import (
 type ConnectionGater struct {
 	// blockedDialPeers   map[peer.ID]struct{}
 	// blockedDialedPeers map[peer.ID]struct{}
+	store db.Store
+	// only the model nodes of the project are accepted if it is set
+	clientProject string
+}
+
+func NewConnectionGater(store db.Store, clientProject string) *ConnectionGater {
+	return &ConnectionGater{
+		store:         store,
+		clientProject: clientProject,
+	}
 }
 
 func (cg *ConnectionGater) InterceptPeerDial(p peer.ID) (allow bool) {
@@ -39,11 +48,11 @@ func (cg *ConnectionGater) InterceptAccept(cma network.ConnMultiaddrs) (allow bo
 func (cg *ConnectionGater) InterceptSecured(dir network.Direction, p peer.ID, cma network.ConnMultiaddrs) (allow bool) {
 	log.Logger.Infof("InterceptSecured {Direction %s, Peer.ID %s, LocalMultiaddr %s, RemoteMultiaddr %s}",
 		dir.String(), p.String(), cma.LocalMultiaddr().String(), cma.RemoteMultiaddr().String())
-	if dir == network.DirInbound && config.GC.App.PeersCollect.ClientProject != "" {
+	if dir == network.DirInbound && cg.clientProject != "" {
 		// _, ok := cg.blockedDialedPeers[p]
 		// return !ok
 		info := &db.PeerCollectInfo{}
-		if err := db.GetAIProjectsOfNode(p.String(), info); err != nil {
+		if err := cg.store.GetAIProjectsOfNode(p.String(), info); err != nil {
 			return true
 		}
 		nt := (types.NodeType)(info.NodeType)
@@ -51,7 +60,7 @@ func (cg *ConnectionGater) InterceptSecured(dir network.Direction, p peer.ID, cm
 			return true
 		}
 		for pn := range info.AIProjects {
-			if pn == config.GC.App.PeersCollect.ClientProject {
+			if pn == cg.clientProject {
 				return true
 			}
 		}
@@ -0,0 +1,161 @@
+package ctl
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"AIComputingNode/pkg/types"
+)
+
+// APIError is the error code and message returned by the node.
+type APIError struct {
+	Status  int
+	Code    int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Code == 0 {
+		return fmt.Sprintf("http status %d: %s", e.Status, e.Message)
+	}
+	return fmt.Sprintf("code %d: %s", e.Code, e.Message)
+}
+
+// Client calls the HTTP API of a node.
+type Client struct {
+	// Base URL of the HTTP API, such as http://127.0.0.1:6000
+	API string
+	// Sent as a bearer token in the Authorization header if not empty
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient returns the client of the API address, which is a URL or host:port.
+func NewClient(addr, token string) *Client {
+	api := strings.TrimSuffix(addr, "/")
+	if !strings.HasPrefix(api, "http://") && !strings.HasPrefix(api, "https://") {
+		api = "http://" + api
+	}
+	return &Client{
+		API:        api,
+		Token:      token,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+func (c *Client) newRequest(method, path string, req any) (*http.Request, error) {
+	var body io.Reader
+	if req != nil {
+		data, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+		body = bytes.NewReader(data)
+	}
+	httpReq, err := http.NewRequest(method, c.API+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if req != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	return httpReq, nil
+}
+
+// Do sends req as JSON, or no body if req is nil, and returns the raw JSON response.
+// A response with a non-zero code is returned as an *APIError.
+func (c *Client) Do(method, path string, req any) (json.RawMessage, error) {
+	httpReq, err := c.newRequest(method, path, req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return body, checkResponse(resp, body)
+}
+
+func checkResponse(resp *http.Response, body []byte) error {
+	var base types.BaseHttpResponse
+	if err := json.Unmarshal(body, &base); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return &APIError{Status: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+		}
+		// not an object, such as the list of swarm peers
+		return nil
+	}
+	if base.Code != 0 {
+		return &APIError{Status: resp.StatusCode, Code: base.Code, Message: base.Message}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &APIError{Status: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	return nil
+}
+
+// Call is like Do and decodes the response into rsp.
+func (c *Client) Call(method, path string, req any, rsp any) error {
+	body, err := c.Do(method, path, req)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, rsp)
+}
+
+// Stream sends a streaming chat completion request and calls fn with every chunk
+// of the server-sent events until data: [DONE] or the end of the response.
+func (c *Client) Stream(path string, req any, fn func(chunk json.RawMessage) error) error {
+	httpReq, err := c.newRequest(http.MethodPost, path, req)
+	if err != nil {
+		return err
+	}
+	resp, err := c.HTTPClient.Do(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// errors are returned as a JSON object instead of events
+	if resp.StatusCode != http.StatusOK || strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json") {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if err := checkResponse(resp, body); err != nil {
+			return err
+		}
+		return fn(body)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if data, ok := strings.CutPrefix(line, "data:"); ok {
+			line = strings.TrimSpace(data)
+		} else if !strings.HasPrefix(line, "{") {
+			// empty lines, comments and other fields of the events
+			continue
+		}
+		if line == "[DONE]" {
+			return nil
+		}
+		if err := fn(json.RawMessage(line)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,511 @@
+// Package ctl implements the "host ctl" command line client of the node HTTP API.
+package ctl
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/libp2p/host"
+	"AIComputingNode/pkg/types"
+)
+
+const (
+	DefaultAPI = "127.0.0.1:6000"
+	// Environment variable of the auth token if the -token flag is not set
+	EnvToken = "AICN_API_TOKEN"
+)
+
+// env is the state shared by the commands.
+type env struct {
+	client *Client
+	out    *printer
+	cmd    *command
+	stderr io.Writer
+}
+
+type command struct {
+	// One or two words, such as "id" or "swarm connect"
+	name  string
+	args  string
+	usage string
+	run   func(e *env, args []string) error
+}
+
+// flagSet returns the flag set of the command.
+func (e *env) flagSet() *flag.FlagSet {
+	fs := flag.NewFlagSet("ctl "+e.cmd.name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "Usage: host ctl %s %s\n", e.cmd.name, e.cmd.args)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the flags of the command and checks the number of the remaining arguments,
+// n < 0 means at least -n arguments.
+func (e *env) parse(fs *flag.FlagSet, args []string, n int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	args = fs.Args()
+	if (n >= 0 && len(args) != n) || (n < 0 && len(args) < -n) {
+		fs.Usage()
+		return nil, fmt.Errorf("wrong number of arguments %d", len(args))
+	}
+	return args, nil
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{name: "id", usage: "show the identity of the node", run: runID},
+		{name: "peers", usage: "list the peers known to the node", run: runPeers},
+		{name: "swarm peers", usage: "list the peers with established connections", run: runSwarmPeers},
+		{name: "swarm connect", args: "<node_addr>", usage: "connect to a node", run: runSwarmConnect},
+		{name: "swarm disconnect", args: "<node_addr>", usage: "disconnect from a node", run: runSwarmDisconnect},
+		{name: "bootstrap list", usage: "list the bootstrap nodes", run: runBootstrapList},
+		{name: "bootstrap add", args: "<node_addr>", usage: "add a bootstrap node", run: runBootstrapAdd},
+		{name: "bootstrap rm", args: "<node_addr>", usage: "remove a bootstrap node", run: runBootstrapRemove},
+		{name: "project register", args: "[-type n] <project> <model>=<api>...", usage: "register an AI project with its models", run: runProjectRegister},
+		{name: "project unregister", args: "<project>", usage: "unregister an AI project", run: runProjectUnregister},
+		{name: "model register", args: "[-type n] [-cid cid] <project> <model> <api>", usage: "register an AI model", run: runModelRegister},
+		{name: "model unregister", args: "[-cid cid] <project> <model>", usage: "unregister an AI model", run: runModelUnregister},
+		{name: "hostinfo", args: "[-refresh] <node_id>", usage: "show the machine information of a node", run: runHostInfo},
+		{name: "chat", args: "[-node id] [-stream] [-system text] <project> <model> <message>", usage: "chat with a model, through the node if -node is set or any node running the model", run: runChat},
+		{name: "history", args: "[-n number]", usage: "list the latest model calls handled by the node", run: runHistory},
+	}
+}
+
+// Run runs the ctl command line, args excludes the program name and "ctl".
+func Run(args []string, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("ctl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "read the API address from the configuration file")
+	api := fs.String("api", "", "API address of the node, default "+DefaultAPI)
+	token := fs.String("token", os.Getenv(EnvToken), "auth token of the API, default $"+EnvToken)
+	jsonOutput := fs.Bool("json", false, "print the JSON responses instead of tables")
+	fs.Usage = func() { usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cmd, rest := findCommand(fs.Args())
+	if cmd == nil {
+		fs.Usage()
+		return errors.New("unknown command")
+	}
+
+	addr := *api
+	if addr == "" && *configPath != "" {
+		cfg, err := config.LoadConfig(*configPath)
+		if err != nil {
+			return fmt.Errorf("load configuration file: %v", err)
+		}
+		addr = cfg.API.Addr
+	}
+	if addr == "" {
+		addr = DefaultAPI
+	}
+
+	e := &env{
+		client: NewClient(addr, *token),
+		out:    &printer{w: stdout, json: *jsonOutput},
+		cmd:    cmd,
+		stderr: stderr,
+	}
+	return cmd.run(e, rest)
+}
+
+func usage(fs *flag.FlagSet) {
+	w := fs.Output()
+	fmt.Fprintln(w, "Usage: host ctl [-config file] [-api addr] [-token token] [-json] <command> [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-20s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Flags:")
+	fs.PrintDefaults()
+}
+
+// findCommand matches the longest command name with the first words of args.
+func findCommand(args []string) (*command, []string) {
+	for words := 2; words >= 1; words-- {
+		if len(args) < words {
+			continue
+		}
+		name := strings.Join(args[:words], " ")
+		for i := range commands {
+			if commands[i].name == name {
+				return &commands[i], args[words:]
+			}
+		}
+	}
+	return nil, nil
+}
+
+func runID(e *env, args []string) error {
+	if _, err := e.parse(e.flagSet(), args, 0); err != nil {
+		return err
+	}
+	var rsp types.IdentifyProtocol
+	raw, err := e.client.Do(http.MethodGet, "/api/v0/id", nil)
+	if err != nil {
+		return err
+	}
+	if e.out.json {
+		return e.out.raw(raw)
+	}
+	if err := json.Unmarshal(raw, &rsp); err != nil {
+		return err
+	}
+	rows := [][]string{
+		{"peer_id", rsp.ID},
+		{"protocol_version", rsp.ProtocolVersion},
+		{"agent_version", rsp.AgentVersion},
+	}
+	for _, addr := range rsp.Addresses {
+		rows = append(rows, []string{"address", addr})
+	}
+	for _, protocol := range rsp.Protocols {
+		rows = append(rows, []string{"protocol", protocol})
+	}
+	return e.out.table([]string{"KEY", "VALUE"}, rows)
+}
+
+// runList prints a response whose data is a string list.
+func runList(e *env, method, path string, req any, header string) error {
+	raw, err := e.client.Do(method, path, req)
+	if err != nil {
+		return err
+	}
+	if e.out.json {
+		return e.out.raw(raw)
+	}
+	var rsp types.PeerListResponse
+	if err := json.Unmarshal(raw, &rsp); err != nil {
+		return err
+	}
+	rows := make([][]string, len(rsp.Data))
+	for i, item := range rsp.Data {
+		rows[i] = []string{item}
+	}
+	return e.out.table([]string{header}, rows)
+}
+
+func runPeers(e *env, args []string) error {
+	if _, err := e.parse(e.flagSet(), args, 0); err != nil {
+		return err
+	}
+	return runList(e, http.MethodGet, "/api/v0/peers", nil, "NODE_ID")
+}
+
+func runSwarmPeers(e *env, args []string) error {
+	if _, err := e.parse(e.flagSet(), args, 0); err != nil {
+		return err
+	}
+	raw, err := e.client.Do(http.MethodGet, "/api/v0/swarm/peers", nil)
+	if err != nil {
+		return err
+	}
+	if e.out.json {
+		return e.out.raw(raw)
+	}
+	var peers []host.SwarmPeerInfo
+	if err := json.Unmarshal(raw, &peers); err != nil {
+		return err
+	}
+	rows := make([][]string, len(peers))
+	for i, peer := range peers {
+		rows[i] = []string{peer.Peer, peer.Addr, peer.Latency, peer.Direction}
+	}
+	return e.out.table([]string{"PEER", "ADDR", "LATENCY", "DIRECTION"}, rows)
+}
+
+// runAction prints the result of a request which only returns a code and message.
+func runAction(e *env, path string, req any) error {
+	raw, err := e.client.Do(http.MethodPost, path, req)
+	if err != nil {
+		return err
+	}
+	if e.out.json {
+		return e.out.raw(raw)
+	}
+	var rsp types.BaseHttpResponse
+	if err := json.Unmarshal(raw, &rsp); err != nil {
+		return err
+	}
+	if rsp.Message == "" {
+		rsp.Message = "ok"
+	}
+	return e.out.line(rsp.Message)
+}
+
+// runAddrAction sends the node address of the only argument to the path.
+func runAddrAction(e *env, args []string, path string) error {
+	args, err := e.parse(e.flagSet(), args, 1)
+	if err != nil {
+		return err
+	}
+	return runAction(e, path, types.SwarmConnectRequest{NodeAddr: args[0]})
+}
+
+func runSwarmConnect(e *env, args []string) error {
+	return runAddrAction(e, args, "/api/v0/swarm/connect")
+}
+
+func runSwarmDisconnect(e *env, args []string) error {
+	return runAddrAction(e, args, "/api/v0/swarm/disconnect")
+}
+
+func runBootstrapList(e *env, args []string) error {
+	if _, err := e.parse(e.flagSet(), args, 0); err != nil {
+		return err
+	}
+	return runList(e, http.MethodGet, "/api/v0/bootstrap/list", nil, "NODE_ADDR")
+}
+
+func runBootstrapAdd(e *env, args []string) error {
+	return runAddrAction(e, args, "/api/v0/bootstrap/add")
+}
+
+func runBootstrapRemove(e *env, args []string) error {
+	return runAddrAction(e, args, "/api/v0/bootstrap/rm")
+}
+
+func runProjectRegister(e *env, args []string) error {
+	fs := e.flagSet()
+	modelType := fs.Int("type", 0, "model type of all the models, 0 chat, 1 image generation, 2 image edit, 3 embedding, 4 audio transcription and 5 audio speech")
+	backend := fs.String("backend", "", "backend of all the models, such as openai, ollama, vllm, tgi, llamacpp, comfyui and automatic1111")
+	args, err := e.parse(fs, args, -2)
+	if err != nil {
+		return err
+	}
+	req := types.AIProjectConfig{Project: args[0]}
+	for _, arg := range args[1:] {
+		name, api, ok := strings.Cut(arg, "=")
+		if !ok || name == "" || api == "" {
+			return fmt.Errorf("invalid model %q, expected <model>=<api>", arg)
+		}
+		req.Models = append(req.Models, types.AIModelConfig{Model: name, API: api, Type: *modelType, Backend: *backend})
+	}
+	return runAction(e, "/api/v0/ai/project/register", req)
+}
+
+func runProjectUnregister(e *env, args []string) error {
+	args, err := e.parse(e.flagSet(), args, 1)
+	if err != nil {
+		return err
+	}
+	return runAction(e, "/api/v0/ai/project/unregister", types.AIProjectConfig{Project: args[0]})
+}
+
+func runModelRegister(e *env, args []string) error {
+	fs := e.flagSet()
+	modelType := fs.Int("type", 0, "model type, 0 chat, 1 image generation, 2 image edit, 3 embedding, 4 audio transcription and 5 audio speech")
+	cid := fs.String("cid", "", "cid of the model")
+	backend := fs.String("backend", "", "backend of the model, such as openai, ollama, vllm, tgi, llamacpp, comfyui and automatic1111")
+	workflow := fs.String("workflow", "", "API format workflow file of the comfyui backend")
+	args, err := e.parse(fs, args, 3)
+	if err != nil {
+		return err
+	}
+	return runAction(e, "/api/v0/ai/model/register", types.AIModelRegister{
+		Project: args[0],
+		AIModelConfig: types.AIModelConfig{
+			Model:    args[1],
+			API:      args[2],
+			Type:     *modelType,
+			CID:      *cid,
+			Backend:  *backend,
+			Workflow: *workflow,
+		},
+	})
+}
+
+func runModelUnregister(e *env, args []string) error {
+	fs := e.flagSet()
+	cid := fs.String("cid", "", "cid of the model")
+	args, err := e.parse(fs, args, 2)
+	if err != nil {
+		return err
+	}
+	return runAction(e, "/api/v0/ai/model/unregister", types.AIModelUnregister{
+		Project: args[0],
+		Model:   args[1],
+		CID:     *cid,
+	})
+}
+
+func runHostInfo(e *env, args []string) error {
+	fs := e.flagSet()
+	refresh := fs.Bool("refresh", false, "ignore the cached information")
+	args, err := e.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	req := types.HostInfoRequest{
+		BaseHttpRequest:    types.BaseHttpRequest{NodeID: args[0]},
+		RemoteQueryOptions: types.RemoteQueryOptions{Refresh: *refresh},
+	}
+	raw, err := e.client.Do(http.MethodPost, "/api/v0/host/info", req)
+	if err != nil {
+		return err
+	}
+	if e.out.json {
+		return e.out.raw(raw)
+	}
+	var rsp types.HostInfoResponse
+	if err := json.Unmarshal(raw, &rsp); err != nil {
+		return err
+	}
+	rows := [][]string{
+		{"os", strings.TrimSpace(fmt.Sprintf("%s %s %s", rsp.Os.Platform, rsp.Os.PlatformVersion, rsp.Os.KernelArch))},
+		{"kernel", rsp.Os.KernelVersion},
+	}
+	for _, cpu := range rsp.Cpu {
+		rows = append(rows, []string{"cpu", fmt.Sprintf("%s (%d cores, %d threads)", cpu.ModelName, cpu.Cores, cpu.Threads)})
+	}
+	rows = append(rows, []string{"memory", formatBytes(uint64(rsp.Memory.TotalPhysicalBytes))})
+	for _, disk := range rsp.Disk {
+		rows = append(rows, []string{"disk", fmt.Sprintf("%s %s %s", disk.Model, disk.DriveType, formatBytes(disk.SizeBytes))})
+	}
+	for _, gpu := range rsp.Gpu {
+		rows = append(rows, []string{"gpu", fmt.Sprintf("%s %s", gpu.Vendor, gpu.Product)})
+	}
+	if rsp.CachedAt != 0 {
+		rows = append(rows, []string{"cached_at", time.Unix(rsp.CachedAt, 0).Format(time.RFC3339)})
+	}
+	return e.out.table([]string{"KEY", "VALUE"}, rows)
+}
+
+func formatBytes(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+func textContent(text string) json.RawMessage {
+	content, _ := json.Marshal(text)
+	return content
+}
+
+func runChat(e *env, args []string) error {
+	fs := e.flagSet()
+	nodeID := fs.String("node", "", "id of the node running the model")
+	stream := fs.Bool("stream", false, "print the answer as it is generated")
+	system := fs.String("system", "", "system prompt")
+	args, err := e.parse(fs, args, 3)
+	if err != nil {
+		return err
+	}
+
+	chatReq := types.ChatModelRequest{Model: args[1], Stream: *stream}
+	if *system != "" {
+		chatReq.Messages = append(chatReq.Messages, types.ChatCompletionMessage{Role: "system", Content: textContent(*system)})
+	}
+	chatReq.Messages = append(chatReq.Messages, types.ChatCompletionMessage{Role: "user", Content: textContent(args[2])})
+
+	var req any = types.ChatCompletionProxyRequest{Project: args[0], ChatModelRequest: chatReq}
+	path := "/api/v0/chat/completion/proxy"
+	if *nodeID != "" {
+		req = types.ChatCompletionRequest{NodeID: *nodeID, Project: args[0], ChatModelRequest: chatReq}
+		path = "/api/v0/chat/completion"
+	}
+
+	if !*stream {
+		raw, err := e.client.Do(http.MethodPost, path, req)
+		if err != nil {
+			return err
+		}
+		if e.out.json {
+			return e.out.raw(raw)
+		}
+		var rsp types.ChatCompletionResponse
+		if err := json.Unmarshal(raw, &rsp); err != nil {
+			return err
+		}
+		for _, choice := range rsp.Choices {
+			if err := e.out.line(choice.Message.Content); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	err = e.client.Stream(path, req, func(chunk json.RawMessage) error {
+		if e.out.json {
+			return e.out.compact(chunk)
+		}
+		var data types.StreamChatModelResponseData
+		if err := json.Unmarshal(chunk, &data); err != nil {
+			return err
+		}
+		for _, choice := range data.Choices {
+			if _, err := io.WriteString(e.out.w, choice.Delta.Content); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil && !e.out.json {
+		err = e.out.line("")
+	}
+	return err
+}
+
+func runHistory(e *env, args []string) error {
+	fs := e.flagSet()
+	number := fs.Int("n", 20, "maximum number of records")
+	if _, err := e.parse(fs, args, 0); err != nil {
+		return err
+	}
+	query := url.Values{"number": {strconv.Itoa(*number)}}
+	raw, err := e.client.Do(http.MethodGet, "/api/v0/ai/history?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	if e.out.json {
+		return e.out.raw(raw)
+	}
+	var rsp types.ModelHistoryResponse
+	if err := json.Unmarshal(raw, &rsp); err != nil {
+		return err
+	}
+	sort.SliceStable(rsp.Data, func(i, j int) bool {
+		return rsp.Data[i].TimeStamp > rsp.Data[j].TimeStamp
+	})
+	rows := make([][]string, len(rsp.Data))
+	for i, mh := range rsp.Data {
+		rows[i] = []string{
+			time.Unix(mh.TimeStamp, 0).Format(time.DateTime),
+			mh.Project,
+			mh.Model,
+			strconv.Itoa(mh.Code),
+			mh.ReqNodeId,
+			strconv.Itoa(mh.ChatUsage.TotalTokens),
+		}
+	}
+	return e.out.table([]string{"TIME", "PROJECT", "MODEL", "CODE", "REQ_NODE_ID", "TOKENS"}, rows)
+}
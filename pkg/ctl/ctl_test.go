@@ -0,0 +1,191 @@
+package ctl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/types"
+)
+
+// fakeNode answers a few APIs of a node and records the requests.
+type fakeNode struct {
+	auth     []string
+	requests map[string]json.RawMessage
+}
+
+func newFakeNode(t *testing.T) (*fakeNode, *httptest.Server) {
+	node := &fakeNode{requests: make(map[string]json.RawMessage)}
+	mux := http.NewServeMux()
+	record := func(r *http.Request) {
+		node.auth = append(node.auth, r.Header.Get("Authorization"))
+		var body json.RawMessage
+		json.NewDecoder(r.Body).Decode(&body)
+		node.requests[r.URL.Path] = body
+	}
+	mux.HandleFunc("/api/v0/id", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		json.NewEncoder(w).Encode(types.IdentifyProtocol{
+			ID:        "16Uiu2HAmPKuJU5VE2PCnydyUn1VcTN2Lt59UDJFFEiRbb7h1x4CV",
+			Addresses: []string{"/ip4/127.0.0.1/tcp/6001"},
+		})
+	})
+	mux.HandleFunc("/api/v0/swarm/connect", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(types.BaseHttpResponse{Code: int(types.ErrCodeParam), Message: "Invalid parameter"})
+	})
+	mux.HandleFunc("/api/v0/ai/project/register", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		json.NewEncoder(w).Encode(types.BaseHttpResponse{Message: "ok"})
+	})
+	mux.HandleFunc("/api/v0/ai/history", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		if r.URL.Query().Get("number") != "5" {
+			t.Errorf("Unexpected query %s", r.URL.RawQuery)
+		}
+		json.NewEncoder(w).Encode(types.ModelHistoryResponse{Data: []types.ModelHistory{
+			{TimeStamp: 1729317600, Project: "DecentralGPT", Model: "Llama3-70B", ChatUsage: types.ChatResponseUsage{TotalTokens: 21}},
+		}})
+	})
+	mux.HandleFunc("/api/v0/chat/completion/proxy", func(w http.ResponseWriter, r *http.Request) {
+		record(r)
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, token := range []string{"Hello", " there"} {
+			data, _ := json.Marshal(types.StreamChatModelResponseData{
+				Choices: []types.StreamChatResponseChoice{{Delta: types.ChatCompletionResponseMessage{Content: token}}},
+			})
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return node, server
+}
+
+func run(t *testing.T, args ...string) (string, error) {
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	err := Run(args, stdout, stderr)
+	return stdout.String(), err
+}
+
+func TestID(t *testing.T) {
+	node, server := newFakeNode(t)
+
+	out, err := run(t, "-api", server.URL, "-token", "secret", "id")
+	if err != nil {
+		t.Fatalf("Run id: %v", err)
+	}
+	if !strings.Contains(out, "peer_id") || !strings.Contains(out, "16Uiu2HAmPKuJU5VE2PCnydyUn1VcTN2Lt59UDJFFEiRbb7h1x4CV") {
+		t.Errorf("Unexpected output %q", out)
+	}
+	if node.auth[0] != "Bearer secret" {
+		t.Errorf("Unexpected Authorization header %q", node.auth[0])
+	}
+
+	out, err = run(t, "-api", server.URL, "-json", "id")
+	if err != nil {
+		t.Fatalf("Run id: %v", err)
+	}
+	var id types.IdentifyProtocol
+	if err := json.Unmarshal([]byte(out), &id); err != nil || len(id.Addresses) != 1 {
+		t.Errorf("Unexpected JSON output %q: %v", out, err)
+	}
+	if node.auth[1] != "" {
+		t.Errorf("Unexpected Authorization header %q", node.auth[1])
+	}
+}
+
+func TestConfigAddress(t *testing.T) {
+	_, server := newFakeNode(t)
+	path := filepath.Join(t.TempDir(), "config.json")
+	cfg := config.Config{API: config.APIConfig{Addr: strings.TrimPrefix(server.URL, "http://")}}
+	if err := cfg.SaveConfig(path); err != nil {
+		t.Fatalf("Save config: %v", err)
+	}
+	if _, err := run(t, "-config", path, "id"); err != nil {
+		t.Errorf("Run id with config: %v", err)
+	}
+	if _, err := run(t, "-config", filepath.Join(t.TempDir(), "missing.json"), "id"); err == nil {
+		t.Error("Run id with missing config succeeded")
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	_, server := newFakeNode(t)
+	_, err := run(t, "-api", server.URL, "swarm", "connect", "/ip4/127.0.0.1/tcp/6001")
+	apiErr, ok := err.(*APIError)
+	if !ok || apiErr.Code != int(types.ErrCodeParam) {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if _, err := run(t, "-api", server.URL, "swarm", "connect"); err == nil {
+		t.Error("Run swarm connect without address succeeded")
+	}
+	if _, err := run(t, "-api", server.URL, "unknown"); err == nil {
+		t.Error("Run unknown command succeeded")
+	}
+}
+
+func TestProjectRegister(t *testing.T) {
+	node, server := newFakeNode(t)
+	out, err := run(t, "-api", server.URL, "project", "register", "-type", "1",
+		"SuperImageAI", "SuperImage=http://127.0.0.1:1088/v1/images/generations")
+	if err != nil {
+		t.Fatalf("Run project register: %v", err)
+	}
+	if strings.TrimSpace(out) != "ok" {
+		t.Errorf("Unexpected output %q", out)
+	}
+	var req types.AIProjectConfig
+	json.Unmarshal(node.requests["/api/v0/ai/project/register"], &req)
+	if req.Project != "SuperImageAI" || len(req.Models) != 1 || req.Models[0].Type != 1 ||
+		req.Models[0].API != "http://127.0.0.1:1088/v1/images/generations" {
+		t.Errorf("Unexpected request %+v", req)
+	}
+	if _, err := run(t, "-api", server.URL, "project", "register", "SuperImageAI", "SuperImage"); err == nil {
+		t.Error("Run project register with invalid model succeeded")
+	}
+}
+
+func TestHistory(t *testing.T) {
+	_, server := newFakeNode(t)
+	out, err := run(t, "-api", server.URL, "history", "-n", "5")
+	if err != nil {
+		t.Fatalf("Run history: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "TIME") || !strings.Contains(lines[1], "Llama3-70B") {
+		t.Errorf("Unexpected output %q", out)
+	}
+}
+
+func TestStreamChat(t *testing.T) {
+	node, server := newFakeNode(t)
+	out, err := run(t, "-api", server.URL, "chat", "-stream", "DecentralGPT", "Llama3-70B", "Hi")
+	if err != nil {
+		t.Fatalf("Run chat: %v", err)
+	}
+	if out != "Hello there\n" {
+		t.Errorf("Unexpected output %q", out)
+	}
+	var req types.ChatCompletionProxyRequest
+	json.Unmarshal(node.requests["/api/v0/chat/completion/proxy"], &req)
+	if !req.Stream || req.Project != "DecentralGPT" || len(req.Messages) != 1 || string(req.Messages[0].Content) != `"Hi"` {
+		t.Errorf("Unexpected request %+v", req)
+	}
+
+	out, err = run(t, "-api", server.URL, "-json", "chat", "-stream", "DecentralGPT", "Llama3-70B", "Hi")
+	if err != nil {
+		t.Fatalf("Run chat: %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 2 {
+		t.Errorf("Unexpected JSON output %q", out)
+	}
+}
@@ -0,0 +1,50 @@
+package ctl
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// printer writes the results as tables, or as the JSON responses of the node.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func (p *printer) raw(data json.RawMessage) error {
+	buf := &bytes.Buffer{}
+	if err := json.Indent(buf, data, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err := p.w.Write(buf.Bytes())
+	return err
+}
+
+func (p *printer) compact(data json.RawMessage) error {
+	buf := &bytes.Buffer{}
+	if err := json.Compact(buf, data); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err := p.w.Write(buf.Bytes())
+	return err
+}
+
+func (p *printer) line(s string) error {
+	_, err := fmt.Fprintln(p.w, s)
+	return err
+}
+
+func (p *printer) table(header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
@@ -1,30 +1,87 @@
 package db
 
 import (
-	"encoding/binary"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"path/filepath"
+	"math/rand"
+	"sort"
 	"time"
 
 	"AIComputingNode/pkg/log"
 	"AIComputingNode/pkg/types"
+)
+
+// Storage backends
+const (
+	BackendLevelDB = "leveldb"
+	BackendMemory  = "memory"
+	BackendSQLite  = "sqlite"
+)
 
-	"github.com/syndtr/goleveldb/leveldb"
+// Kinds of the cached responses of remote nodes
+const (
+	RemoteCacheHostInfo     = "host_info"
+	RemoteCachePeerIdentity = "peer_identity"
 )
 
-var connsDB *leveldb.DB
-var modelsDB *leveldb.DB
-var peersCollectDB *leveldb.DB
+// ErrJobNotFound is returned by GetJob when there is no job of the id.
+var ErrJobNotFound = errors.New("job not found")
 
 type InitOptions struct {
+	// Storage backend, one of leveldb, memory and sqlite, default leveldb
+	Backend      string
 	Folder       string
 	ConnsDBName  string
 	ModelsDBName string
+	// Cache of the host info and peer identity of remote nodes
+	RemoteCacheDBName string
+	// Asynchronous jobs
+	JobsDBName string
+	// Database file of the sqlite backend
+	SQLiteDBName string
 	// Collect node information or not
 	EnablePeersCollect bool
 }
 
+// Store is the persistent storage of a node, including the connection history,
+// the model call history, the collected peer information, the cache of remote nodes and the jobs.
+type Store interface {
+	LoadPeerConnHistory() map[string]string
+	PeerConnected(id string, addr string)
+	PeerConnectFailed(id string)
+
+	WriteModelHistory(mh *types.ModelHistory) error
+	// ListModelHistory returns at most limit model calls, the latest first
+	ListModelHistory(limit int) ([]types.ModelHistory, error)
+
+	UpdatePeerCollect(id string, info PeerCollectInfo) error
+	// DeletePeerCollect deletes the record of the peer, such as the previous id of a rotated key
+	DeletePeerCollect(id string) error
+	GetAIProjectsOfNode(id string, info *PeerCollectInfo) error
+	FindPeers(limit int) ([]string, int)
+	ListPeerCollectInfo() (map[string]PeerCollectInfo, int)
+	ListAIProjects(limit int) ([]string, int)
+	GetModelsOfAIProjects(project string, limit int) ([]string, int)
+	GetPeersOfAIProjects(project, model string, limit int) (map[string]types.ModelIdle, int)
+	CleanExpiredPeerCollectInfo()
+
+	PutRemoteCache(kind, id string, data any) error
+	GetRemoteCache(kind, id string, data any) (int64, error)
+	CleanExpiredRemoteCache(ttl time.Duration)
+
+	// PutJob saves the job, replacing the old one of the same id
+	PutJob(job *types.Job) error
+	GetJob(id string) (types.Job, error)
+	// ListPendingJobs returns the queued and running jobs, and the finished jobs whose webhooks are not
+	// delivered yet, which are resumed when the node starts
+	ListPendingJobs() ([]types.Job, error)
+	// CleanExpiredJobs deletes the jobs finished longer than ttl
+	CleanExpiredJobs(ttl time.Duration)
+
+	Close() error
+}
+
 type connInfo struct {
 	Address             string `json:"Address"`             // connection address
 	LastConnectTime     int64  `json:"LastConnectTime"`     // Time of last successful connection
@@ -35,71 +92,55 @@ type PeerCollectInfo struct {
 	AIProjects map[string][]types.ModelIdle `json:"AIProjects"`
 	NodeType   uint32                       `json:"NodeType"`
 	Timestamp  int64                        `json:"timestamp"`
+	Metrics    *types.HostMetrics           `json:"Metrics,omitempty"`
+	// Connections reported by the node, the edges of the topology
+	Connections []types.PeerConnection `json:"Connections,omitempty"`
 }
 
-func InitDb(opts InitOptions) error {
-	if opts.ConnsDBName == "" {
-		opts.ConnsDBName = "conns.db"
-	}
-	if opts.ModelsDBName == "" {
-		opts.ModelsDBName = "models.db"
-	}
-	var err error
-	connsDB, err = leveldb.OpenFile(filepath.Join(opts.Folder, opts.ConnsDBName), nil)
-	if err != nil {
-		return err
-	}
-	modelsDB, err = leveldb.OpenFile(filepath.Join(opts.Folder, opts.ModelsDBName), nil)
-	if err != nil {
-		return err
-	}
-	if opts.EnablePeersCollect {
-		peersCollectDB, err = leveldb.OpenFile(filepath.Join(opts.Folder, "peers_collect.db"), nil)
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+type remoteCacheItem struct {
+	Timestamp int64           `json:"Timestamp"`
+	Data      json.RawMessage `json:"Data"`
 }
 
-func LoadPeerConnHistory() map[string]string {
-	conns := make(map[string]string)
-	iter := connsDB.NewIterator(nil, nil)
-	for iter.Next() {
-		var pi connInfo
-		if err := json.Unmarshal(iter.Value(), &pi); err != nil {
-			log.Logger.Warn("Parse failed when load peer conn history of ", iter.Key(), err)
-			continue
+var (
+	_ Store = (*LevelDBStore)(nil)
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*SQLiteStore)(nil)
+)
+
+// Open opens the store of the backend in the options.
+func Open(opts InitOptions) (Store, error) {
+	switch opts.Backend {
+	case "", BackendLevelDB:
+		s, err := OpenLevelDBStore(opts)
+		if err != nil {
+			return nil, err
 		}
-		conns[string(iter.Key())] = pi.Address
-	}
-	iter.Release()
-	if err := iter.Error(); err != nil {
-		log.Logger.Warnf("Iterator failed when load peer conns history %v", err)
+		return s, nil
+	case BackendMemory:
+		return NewMemoryStore(opts.EnablePeersCollect), nil
+	case BackendSQLite:
+		s, err := OpenSQLiteStore(opts)
+		if err != nil {
+			return nil, err
+		}
+		return s, nil
+	default:
+		return nil, fmt.Errorf("unknowned datastore backend %s", opts.Backend)
 	}
-	return conns
 }
 
-func PeerConnected(id string, addr string) {
-	pi := connInfo{
+func newConnInfo(addr string) connInfo {
+	return connInfo{
 		Address:             addr,
 		LastConnectTime:     time.Now().Unix(),
 		ConsecutiveFailures: 0,
 	}
-	updatePeerConnHistory(id, pi)
 }
 
-func PeerConnectFailed(id string) {
-	value, err := connsDB.Get([]byte(id), nil)
-	if err != nil {
-		log.Logger.Warnf("Get connectiong db item failed %v", err)
-	}
-	var pi connInfo
-	err = json.Unmarshal(value, &pi)
-	if err != nil {
-		log.Logger.Warnf("Parse connectiong db item failed %v", err)
-	}
-
+// peerConnFailed counts a failed connection of the peer, and reports whether the
+// connection history should be saved or is too old and should be deleted.
+func peerConnFailed(id string, pi *connInfo) (update bool, remove bool) {
 	pi.ConsecutiveFailures = pi.ConsecutiveFailures + 1
 	if pi.ConsecutiveFailures >= 10 {
 		lastTime := time.Unix(pi.LastConnectTime, 0)
@@ -108,246 +149,115 @@ func PeerConnectFailed(id string) {
 		if duration.Abs().Hours()/24 > 30.0 {
 			log.Logger.Infof("Connection with %s is too old and failed too many times, %s",
 				id, "will be deleted soon")
-			if err := connsDB.Delete([]byte(id), nil); err != nil {
-				log.Logger.Warnf("Delete item %s failed %v", id, err)
-			}
+			return false, true
 		}
-	} else {
-		updatePeerConnHistory(id, pi)
-	}
-}
-
-func updatePeerConnHistory(id string, pi connInfo) error {
-	value, err := json.Marshal(pi)
-	if err != nil {
-		log.Logger.Warnf("Marshal failed when update connectiong db %v", err)
-	}
-	err = connsDB.Put([]byte(id), value, nil)
-	if err != nil {
-		log.Logger.Warnf("Update connectiong db failed %v", err)
+		return false, false
 	}
-	return nil
+	return true, false
 }
 
-func WriteModelHistory(mh *types.ModelHistory) error {
-	value, err := json.Marshal(mh)
-	if err != nil {
-		log.Logger.Warnf("Marshal failed when write model history %v", err)
-		return err
-	}
-	keyBytes := make([]byte, 8)
-	binary.LittleEndian.PutUint64(keyBytes, uint64(mh.TimeStamp))
-
-	if err := modelsDB.Put(keyBytes, value, nil); err != nil {
-		log.Logger.Warnf("Put model history failed %v", err)
-		return err
-	}
-	log.Logger.Infof("Put model history success")
-	return nil
-}
-
-func UpdatePeerCollect(id string, info PeerCollectInfo) error {
-	if peersCollectDB == nil {
-		log.Logger.Warn("Not supported to update peer collect")
-		return fmt.Errorf("not supported")
-	}
-	value, err := json.Marshal(info)
-	if err != nil {
-		log.Logger.Warnf("Marshal failed when update peer collect db %v", err)
-		return err
-	}
-	err = peersCollectDB.Put([]byte(id), value, nil)
-	if err != nil {
-		log.Logger.Warnf("Update peer collect db failed %v", err)
-		return err
-	}
-	log.Logger.Infof("Update peer collect of %s success", id)
-	return nil
+func remoteCacheKey(kind, id string) []byte {
+	return []byte(kind + "/" + id)
 }
 
-func GetAIProjectsOfNode(id string, info *PeerCollectInfo) error {
-	if peersCollectDB == nil {
-		return fmt.Errorf("peers collect db not exist")
-	}
-	value, err := peersCollectDB.Get([]byte(id), nil)
+func newRemoteCacheItem(data any) ([]byte, error) {
+	raw, err := json.Marshal(data)
 	if err != nil {
-		return fmt.Errorf("id not exist %v", err.Error())
-	}
-	if err := json.Unmarshal(value, info); err != nil {
-		return fmt.Errorf("unmarshal json failed %v", err.Error())
+		return nil, err
 	}
-	return nil
+	return json.Marshal(remoteCacheItem{
+		Timestamp: time.Now().Unix(),
+		Data:      raw,
+	})
 }
 
-func FindPeers(limit int) ([]string, int) {
-	ids := make([]string, 0)
-	if peersCollectDB == nil {
-		return ids, int(types.ErrCodeUnsupported)
+func parseRemoteCacheItem(value []byte, data any) (int64, error) {
+	var item remoteCacheItem
+	if err := json.Unmarshal(value, &item); err != nil {
+		return 0, fmt.Errorf("unmarshal json failed %v", err.Error())
 	}
-
-	iter := peersCollectDB.NewIterator(nil, nil)
-	var count int = 0
-	for iter.Next() && count < limit {
-		ids = append(ids, string(iter.Key()))
-		count = count + 1
-	}
-
-	iter.Release()
-	if err := iter.Error(); err != nil {
-		log.Logger.Warnf("Iterator failed when load peer collect info %v", err)
-		return ids, int(types.ErrCodeDatabase)
+	if err := json.Unmarshal(item.Data, data); err != nil {
+		return 0, fmt.Errorf("unmarshal json failed %v", err.Error())
 	}
-	return ids, 0
+	return item.Timestamp, nil
 }
 
-func ListAIProjects(limit int) ([]string, int) {
-	ids := make([]string, 0)
-	if peersCollectDB == nil {
-		return ids, int(types.ErrCodeUnsupported)
-	}
-
-	set := types.NewSet()
-	timestamp := time.Now().Add(-time.Hour * 12)
-	iter := peersCollectDB.NewIterator(nil, nil)
-	for iter.Next() && set.Size() < limit {
-		var info PeerCollectInfo
-		if err := json.Unmarshal(iter.Value(), &info); err != nil {
-			log.Logger.Warn("Parse failed when load peer collect info of ", iter.Key(), err)
+// pendingJobs decodes the jobs and returns the pending ones, the oldest first.
+func pendingJobs(values [][]byte) []types.Job {
+	jobs := make([]types.Job, 0)
+	for _, value := range values {
+		var job types.Job
+		if err := json.Unmarshal(value, &job); err != nil {
+			log.Logger.Warnf("Unmarshal job failed %v", err)
 			continue
 		}
-		if time.Unix(info.Timestamp, 0).Before(timestamp) {
-			continue
-		}
-		for project := range info.AIProjects {
-			set.Add(project)
+		if job.Pending() {
+			jobs = append(jobs, job)
 		}
 	}
-
-	iter.Release()
-	if err := iter.Error(); err != nil {
-		log.Logger.Warnf("Iterator failed when load peer collect info %v", err)
-		return ids, int(types.ErrCodeDatabase)
-	}
-
-	for _, item := range set.Elements() {
-		ids = append(ids, item.(string))
-	}
-	return ids, 0
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt < jobs[j].CreatedAt
+	})
+	return jobs
 }
 
-func GetModelsOfAIProjects(project string, limit int) ([]string, int) {
-	models := make([]string, 0)
-	if peersCollectDB == nil {
-		return models, int(types.ErrCodeUnsupported)
-	}
-
-	set := types.NewSet()
-	timestamp := time.Now().Add(-time.Hour * 12)
-	iter := peersCollectDB.NewIterator(nil, nil)
-	for iter.Next() && set.Size() < limit {
-		var info PeerCollectInfo
-		if err := json.Unmarshal(iter.Value(), &info); err != nil {
-			log.Logger.Warn("Parse failed when load peer collect info of ", iter.Key(), err)
-			continue
-		}
-		if time.Unix(info.Timestamp, 0).Before(timestamp) {
-			continue
-		}
-		for pn, item := range info.AIProjects {
-			if pn == project {
-				for _, model := range item {
-					set.Add(model.Model)
-				}
-			}
-		}
-	}
-
-	iter.Release()
-	if err := iter.Error(); err != nil {
-		log.Logger.Warnf("Iterator failed when load peer collect info %v", err)
-		return models, int(types.ErrCodeDatabase)
-	}
-
-	for _, item := range set.Elements() {
-		models = append(models, item.(string))
+// jobExpired reports whether the job is finished longer than ttl, the undecodable jobs are expired too.
+func jobExpired(value []byte, ttl time.Duration) bool {
+	var job types.Job
+	if err := json.Unmarshal(value, &job); err != nil {
+		return true
 	}
-	return models, 0
+	return job.Finished() && time.Unix(job.UpdatedAt, 0).Before(time.Now().Add(-ttl))
 }
 
-func GetPeersOfAIProjects(project, model string, limit int) (map[string]types.ModelIdle, int) {
-	ids := make(map[string]types.ModelIdle)
-	if peersCollectDB == nil {
-		return ids, int(types.ErrCodeUnsupported)
-	}
-
-	iter := peersCollectDB.NewIterator(nil, nil)
-	timestamp := time.Now().Add(-time.Minute * 10)
-	for iter.Next() && len(ids) < limit {
-		var info PeerCollectInfo
-		if err := json.Unmarshal(iter.Value(), &info); err != nil {
-			log.Logger.Warn("Parse failed when load peer collect info of ", iter.Key(), err)
-			continue
-		}
-		if time.Unix(info.Timestamp, 0).Before(timestamp) {
+// latestModelHistory decodes the model history records and returns at most limit of them, the latest first.
+func latestModelHistory(values [][]byte, limit int) []types.ModelHistory {
+	history := make([]types.ModelHistory, 0, len(values))
+	for _, value := range values {
+		var mh types.ModelHistory
+		if err := json.Unmarshal(value, &mh); err != nil {
+			log.Logger.Warnf("Unmarshal model history failed %v", err)
 			continue
 		}
-		node := string(iter.Key())
-		for pn, item := range info.AIProjects {
-			if pn == project {
-				for _, mi := range item {
-					if model == mi.Model {
-						if existed, ok := ids[node]; ok {
-							if mi.Idle < existed.Idle {
-								ids[node] = mi
-							}
-						} else {
-							ids[node] = mi
-						}
-					}
-				}
-			}
-		}
+		history = append(history, mh)
 	}
-
-	iter.Release()
-	if err := iter.Error(); err != nil {
-		log.Logger.Warnf("Iterator failed when load peer collect info %v", err)
-		return ids, int(types.ErrCodeDatabase)
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].TimeStamp > history[j].TimeStamp
+	})
+	if len(history) > limit {
+		history = history[:limit]
 	}
-
-	return ids, 0
+	return history
 }
 
-func CleanExpiredPeerCollectInfo() {
-	if peersCollectDB == nil {
-		return
+// selectPeers returns at most limit peers whose heartbeats are received in the last
+// 10 minutes. When there are more peers than limit, the most idle peers are preferred,
+// and the peers with the same idle are chosen randomly, so that the load is spread.
+func selectPeers(peers map[string]peerIndexValue, limit int) map[string]types.ModelIdle {
+	type candidate struct {
+		id string
+		mi types.ModelIdle
 	}
-
-	ids := make(map[string]int64)
-	iter := peersCollectDB.NewIterator(nil, nil)
-	timestamp := time.Now().Add(-time.Hour * 24)
-	for iter.Next() {
-		var info PeerCollectInfo
-		if err := json.Unmarshal(iter.Value(), &info); err != nil {
-			log.Logger.Warn("Parse failed when load peer collect info of ", iter.Key(), err)
+	candidates := make([]candidate, 0, len(peers))
+	timestamp := time.Now().Add(-time.Minute * 10)
+	for id, value := range peers {
+		if time.Unix(value.Timestamp, 0).Before(timestamp) {
 			continue
 		}
-		if time.Unix(info.Timestamp, 0).Before(timestamp) {
-			ids[string(iter.Key())] = info.Timestamp
-		}
-	}
-
-	iter.Release()
-	if err := iter.Error(); err != nil {
-		log.Logger.Warnf("Iterator failed when load peer collect info %v", err)
-		return
-	}
-
-	for id, ts := range ids {
-		if err := peersCollectDB.Delete([]byte(id), nil); err != nil {
-			log.Logger.Warnf("Delete expired peer collect info of %s failed %v", id, err)
-		} else {
-			log.Logger.Infof("Delete expired peer collect info of %s with %v", id, ts)
-		}
-	}
+		candidates = append(candidates, candidate{id: id, mi: value.ModelIdle})
+	}
+	if len(candidates) > limit {
+		rand.Shuffle(len(candidates), func(i, j int) {
+			candidates[i], candidates[j] = candidates[j], candidates[i]
+		})
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].mi.Idle < candidates[j].mi.Idle
+		})
+		candidates = candidates[:limit]
+	}
+	ids := make(map[string]types.ModelIdle, len(candidates))
+	for _, c := range candidates {
+		ids[c.id] = c.mi
+	}
+	return ids
 }
@@ -3,61 +3,85 @@ package db
 import (
 	"AIComputingNode/pkg/types"
 	"encoding/json"
+	"fmt"
 	"os"
 	"testing"
 	"time"
 )
 
+// openTestStores opens a store of every backend in a temporary folder.
+func openTestStores(t *testing.T, enablePeersCollect bool) map[string]Store {
+	stores := make(map[string]Store)
+	for _, backend := range []string{BackendLevelDB, BackendMemory, BackendSQLite} {
+		store, err := Open(InitOptions{
+			Backend:            backend,
+			Folder:             t.TempDir(),
+			EnablePeersCollect: enablePeersCollect,
+		})
+		if err != nil {
+			t.Fatalf("Open %s store failed %v", backend, err)
+		}
+		t.Cleanup(func() {
+			store.Close()
+		})
+		stores[backend] = store
+	}
+	return stores
+}
+
 func TestLevelDB(t *testing.T) {
-	if err := InitDb(InitOptions{
+	store, err := OpenLevelDBStore(InitOptions{
 		Folder:             ".",
 		ConnsDBName:        "conns.db",
 		ModelsDBName:       "models.db",
 		EnablePeersCollect: false,
-	}); err != nil {
+	})
+	if err != nil {
 		t.Fatal("Init db failed", err)
 	}
-	PeerConnected("16Uiu2HAmS4CErxrmPryJbbEX2HFQbLK8r8xCA5rmzdSU59rHc9AF",
+	store.PeerConnected("16Uiu2HAmS4CErxrmPryJbbEX2HFQbLK8r8xCA5rmzdSU59rHc9AF",
 		"/ip4/8.219.75.114/tcp/6001")
-	PeerConnected("16Uiu2HAmRTpigc7jAbsLndB2xDEBMAXLb887SBEFhfdJeEJNtqRM",
+	store.PeerConnected("16Uiu2HAmRTpigc7jAbsLndB2xDEBMAXLb887SBEFhfdJeEJNtqRM",
 		"/ip4/122.99.183.54/tcp/6001")
-	peers := LoadPeerConnHistory()
+	peers := store.LoadPeerConnHistory()
 	for key, value := range peers {
 		t.Log("load db item", key, value)
 	}
 
-	data, err := connsDB.Get([]byte("16Uiu2HAmS4CErxrmPryJbbEX2HFQbLK8r8xCA5rmzdSU59rHc9AF"), nil)
+	data, err := store.connsDB.Get([]byte("16Uiu2HAmS4CErxrmPryJbbEX2HFQbLK8r8xCA5rmzdSU59rHc9AF"), nil)
 	if err != nil {
 		t.Errorf("Level db get item failed %v", err)
 	} else {
 		t.Logf("Level db get item value %s", string(data))
 	}
 
-	none, err := connsDB.Get([]byte("1234567"), nil)
+	none, err := store.connsDB.Get([]byte("1234567"), nil)
 	if err != nil {
 		t.Errorf("Level db get not existed item failed %v", err)
 	} else {
 		t.Logf("Level db get not existed item value %v", none)
 	}
 
-	connsDB.Close()
+	store.Close()
 	os.RemoveAll("./conns.db")
-	modelsDB.Close()
 	os.RemoveAll("./models.db")
+	os.RemoveAll("./remote_cache.db")
+	os.RemoveAll("./jobs.db")
 }
 
 // go test -v -timeout 30s -count=1 -run TestGetPeersOfAIProject AIComputingNode/pkg/db
 func TestGetPeersOfAIProject(t *testing.T) {
-	if err := InitDb(InitOptions{
+	store, err := OpenLevelDBStore(InitOptions{
 		Folder:             ".",
 		ConnsDBName:        "conns.db",
 		ModelsDBName:       "models.db",
 		EnablePeersCollect: true,
-	}); err != nil {
+	})
+	if err != nil {
 		t.Fatal("Init db failed", err)
 	}
 
-	UpdatePeerCollect(
+	store.UpdatePeerCollect(
 		"16Uiu2HAkyKgcoYhNsTfrX3mq2wJiZAGnD4DrMdZZb8dbofZ3jrb8",
 		PeerCollectInfo{
 			AIProjects: map[string][]types.ModelIdle{
@@ -77,7 +101,7 @@ func TestGetPeersOfAIProject(t *testing.T) {
 			Timestamp: time.Now().Unix(),
 		},
 	)
-	UpdatePeerCollect(
+	store.UpdatePeerCollect(
 		"16Uiu2HAm4szuwGhRmXTBs7F2anRo9y9cQrcfZH7RXwEsVa1GrXVd",
 		PeerCollectInfo{
 			AIProjects: map[string][]types.ModelIdle{
@@ -97,7 +121,7 @@ func TestGetPeersOfAIProject(t *testing.T) {
 			Timestamp: time.Now().Unix(),
 		},
 	)
-	UpdatePeerCollect(
+	store.UpdatePeerCollect(
 		"16Uiu2HAmAZmg7WcW8jK6mkjFx6HBbc1HtPWFk88cjjDyvf6MYw8D",
 		PeerCollectInfo{
 			AIProjects: map[string][]types.ModelIdle{
@@ -164,7 +188,7 @@ func TestGetPeersOfAIProject(t *testing.T) {
 			Timestamp: time.Now().Unix(),
 		},
 	)
-	UpdatePeerCollect(
+	store.UpdatePeerCollect(
 		"16Uiu2HAmDBYxgdKxeCbmn8hYiqwK3xHR9533WDdEYmpEDQ259GTe",
 		PeerCollectInfo{
 			AIProjects: map[string][]types.ModelIdle{},
@@ -172,7 +196,7 @@ func TestGetPeersOfAIProject(t *testing.T) {
 			Timestamp:  time.Now().Unix(),
 		},
 	)
-	UpdatePeerCollect(
+	store.UpdatePeerCollect(
 		"16Uiu2HAmDR6u7WCPFnhJwx4P9FsXhtu9hdtnyhTC2BF8BGXw1ZiG",
 		PeerCollectInfo{
 			AIProjects: map[string][]types.ModelIdle{},
@@ -180,7 +204,7 @@ func TestGetPeersOfAIProject(t *testing.T) {
 			Timestamp:  time.Now().Unix(),
 		},
 	)
-	UpdatePeerCollect(
+	store.UpdatePeerCollect(
 		"16Uiu2HAmEav9vvFGZR4qLey63Khrc7Bqnu58ESDt2NHzWrZByntU",
 		PeerCollectInfo{
 			AIProjects: map[string][]types.ModelIdle{
@@ -218,7 +242,7 @@ func TestGetPeersOfAIProject(t *testing.T) {
 			Timestamp: time.Now().Unix(),
 		},
 	)
-	UpdatePeerCollect(
+	store.UpdatePeerCollect(
 		"16Uiu2HAmKk7Fg4WysTpEGd5q1wH2NL4wmxyQ5Nj4HhkQHyB3bDhm",
 		PeerCollectInfo{
 			AIProjects: map[string][]types.ModelIdle{},
@@ -226,7 +250,7 @@ func TestGetPeersOfAIProject(t *testing.T) {
 			Timestamp:  time.Now().Unix(),
 		},
 	)
-	UpdatePeerCollect(
+	store.UpdatePeerCollect(
 		"16Uiu2HAmLCRpZv6nUmeAmXoWpXeyrKjZ7pUvqx5m3e5gZMmUzScp",
 		PeerCollectInfo{
 			AIProjects: map[string][]types.ModelIdle{},
@@ -234,7 +258,7 @@ func TestGetPeersOfAIProject(t *testing.T) {
 			Timestamp:  time.Now().Unix(),
 		},
 	)
-	UpdatePeerCollect(
+	store.UpdatePeerCollect(
 		"16Uiu2HAmRTpigc7jAbsLndB2xDEBMAXLb887SBEFhfdJeEJNtqRM",
 		PeerCollectInfo{
 			AIProjects: map[string][]types.ModelIdle{},
@@ -242,7 +266,7 @@ func TestGetPeersOfAIProject(t *testing.T) {
 			Timestamp:  time.Now().Unix(),
 		},
 	)
-	UpdatePeerCollect(
+	store.UpdatePeerCollect(
 		"16Uiu2HAmS4CErxrmPryJbbEX2HFQbLK8r8xCA5rmzdSU59rHc9AF",
 		PeerCollectInfo{
 			AIProjects: map[string][]types.ModelIdle{},
@@ -251,7 +275,7 @@ func TestGetPeersOfAIProject(t *testing.T) {
 		},
 	)
 
-	iter := peersCollectDB.NewIterator(nil, nil)
+	iter := store.peersCollectDB.NewIterator(nil, nil)
 	for iter.Next() {
 		var info PeerCollectInfo
 		if err := json.Unmarshal(iter.Value(), &info); err != nil {
@@ -266,31 +290,163 @@ func TestGetPeersOfAIProject(t *testing.T) {
 		t.Logf("Iterator failed when load peer collect info %v", err)
 	}
 
-	ids, code := GetPeersOfAIProjects("DecentralGPT", "Codestral-22B-v0.1", 20)
+	ids, code := store.GetPeersOfAIProjects("DecentralGPT", "Codestral-22B-v0.1", 20)
 	if code != 0 {
 		t.Log("GetPeersOfAIProjects failed ", code)
 	} else {
 		t.Log("GetPeersOfAIProjects of Codestral-22B-v0.1 ", ids)
 	}
 
-	ids, code = GetPeersOfAIProjects("DecentralGPT", "Qwen2.5-Coder-32B", 20)
+	ids, code = store.GetPeersOfAIProjects("DecentralGPT", "Qwen2.5-Coder-32B", 20)
 	if code != 0 {
 		t.Log("GetPeersOfAIProjects failed ", code)
 	} else {
 		t.Log("GetPeersOfAIProjects of Qwen2.5-Coder-32B ", ids)
 	}
 
-	ids, code = GetPeersOfAIProjects("DecentralGPT", "Llama-3.1-405B", 20)
+	ids, code = store.GetPeersOfAIProjects("DecentralGPT", "Llama-3.1-405B", 20)
 	if code != 0 {
 		t.Log("GetPeersOfAIProjects failed ", code)
 	} else {
 		t.Log("GetPeersOfAIProjects of Llama-3.1-405B ", ids)
 	}
 
-	connsDB.Close()
+	store.Close()
 	os.RemoveAll("./conns.db")
-	modelsDB.Close()
 	os.RemoveAll("./models.db")
-	peersCollectDB.Close()
+	os.RemoveAll("./remote_cache.db")
+	os.RemoveAll("./jobs.db")
 	os.RemoveAll("./peers_collect.db")
 }
+
+// go test -v -timeout 30s -count=1 -run TestRemoteCache AIComputingNode/pkg/db
+func TestRemoteCache(t *testing.T) {
+	for backend, store := range openTestStores(t, false) {
+		t.Run(backend, func(t *testing.T) {
+			testRemoteCache(t, store)
+		})
+	}
+}
+
+func testRemoteCache(t *testing.T, store Store) {
+	id := "16Uiu2HAmS4CErxrmPryJbbEX2HFQbLK8r8xCA5rmzdSU59rHc9AF"
+	hi := types.HostInfo{
+		Os: types.OSInfo{
+			OS:       "linux",
+			Platform: "ubuntu",
+		},
+	}
+	var cached types.HostInfo
+	if _, err := store.GetRemoteCache(RemoteCacheHostInfo, id, &cached); err == nil {
+		t.Fatal("Get remote cache of not existed item succeeded")
+	}
+	if err := store.PutRemoteCache(RemoteCacheHostInfo, id, hi); err != nil {
+		t.Fatalf("Put remote cache failed %v", err)
+	}
+	ts, err := store.GetRemoteCache(RemoteCacheHostInfo, id, &cached)
+	if err != nil {
+		t.Fatalf("Get remote cache failed %v", err)
+	}
+	if time.Since(time.Unix(ts, 0)) > time.Minute {
+		t.Errorf("Unexpected remote cache timestamp %v", ts)
+	}
+	if cached.Os.Platform != hi.Os.Platform {
+		t.Errorf("Unexpected remote cache %v", cached)
+	}
+	var identity types.IdentifyProtocol
+	if _, err := store.GetRemoteCache(RemoteCachePeerIdentity, id, &identity); err == nil {
+		t.Error("Remote cache of different kinds are mixed up")
+	}
+
+	store.CleanExpiredRemoteCache(time.Hour)
+	if _, err := store.GetRemoteCache(RemoteCacheHostInfo, id, &cached); err != nil {
+		t.Errorf("Unexpired remote cache was deleted %v", err)
+	}
+	store.CleanExpiredRemoteCache(-time.Minute)
+	if _, err := store.GetRemoteCache(RemoteCacheHostInfo, id, &cached); err == nil {
+		t.Error("Expired remote cache was not deleted")
+	}
+}
+
+// go test -v -timeout 30s -count=1 -run TestJobs AIComputingNode/pkg/db
+func TestJobs(t *testing.T) {
+	for backend, store := range openTestStores(t, false) {
+		t.Run(backend, func(t *testing.T) {
+			now := time.Now().Unix()
+			jobs := []types.Job{
+				{ID: "running", Status: types.JobStatusRunning, CreatedAt: now - 10, UpdatedAt: now},
+				{ID: "queued", Status: types.JobStatusQueued, CreatedAt: now - 20, UpdatedAt: now},
+				{ID: "succeeded", Status: types.JobStatusSucceeded, CreatedAt: now - 30, UpdatedAt: now - 3600},
+				{ID: "delivered", Status: types.JobStatusSucceeded, CreatedAt: now - 40, UpdatedAt: now,
+					Webhook: "https://example.com/hook", WebhookDelivered: true},
+				{ID: "undelivered", Status: types.JobStatusFailed, CreatedAt: now - 50, UpdatedAt: now,
+					Webhook: "https://example.com/hook"},
+			}
+			for i := range jobs {
+				if err := store.PutJob(&jobs[i]); err != nil {
+					t.Fatalf("Put job failed %v", err)
+				}
+			}
+			if _, err := store.GetJob("missing"); err != ErrJobNotFound {
+				t.Errorf("Get job of not existed id %v", err)
+			}
+			job, err := store.GetJob("succeeded")
+			if err != nil || job.Status != types.JobStatusSucceeded {
+				t.Errorf("Unexpected job %v %v", job, err)
+			}
+			pending, err := store.ListPendingJobs()
+			if err != nil {
+				t.Fatalf("List pending jobs failed %v", err)
+			}
+			if len(pending) != 3 || pending[0].ID != "undelivered" || pending[1].ID != "queued" || pending[2].ID != "running" {
+				t.Errorf("Unexpected pending jobs %v", pending)
+			}
+
+			store.CleanExpiredJobs(2 * time.Hour)
+			if _, err := store.GetJob("succeeded"); err != nil {
+				t.Errorf("Unexpired job was deleted %v", err)
+			}
+			store.CleanExpiredJobs(time.Minute)
+			if _, err := store.GetJob("succeeded"); err != ErrJobNotFound {
+				t.Errorf("Expired job was not deleted %v", err)
+			}
+			if _, err := store.GetJob("running"); err != nil {
+				t.Errorf("Unfinished job was deleted %v", err)
+			}
+		})
+	}
+}
+
+// go test -v -timeout 30s -count=1 -run TestPeerConnHistory AIComputingNode/pkg/db
+func TestPeerConnHistory(t *testing.T) {
+	for backend, store := range openTestStores(t, false) {
+		t.Run(backend, func(t *testing.T) {
+			store.PeerConnected("16Uiu2HAm1", "/ip4/8.219.75.114/tcp/6001")
+			store.PeerConnected("16Uiu2HAm2", "/ip4/122.99.183.54/tcp/6001")
+			store.PeerConnectFailed("16Uiu2HAm2")
+			conns := store.LoadPeerConnHistory()
+			if len(conns) != 2 || conns["16Uiu2HAm1"] != "/ip4/8.219.75.114/tcp/6001" {
+				t.Errorf("Unexpected conn history %v", conns)
+			}
+			now := time.Now().Unix()
+			for i := int64(0); i < 3; i++ {
+				if err := store.WriteModelHistory(&types.ModelHistory{TimeStamp: now + i*300, ReqId: fmt.Sprint(i)}); err != nil {
+					t.Errorf("Write model history failed %v", err)
+				}
+			}
+			history, err := store.ListModelHistory(2)
+			if err != nil {
+				t.Fatalf("List model history failed %v", err)
+			}
+			if len(history) != 2 || history[0].ReqId != "2" || history[1].ReqId != "1" {
+				t.Errorf("Unexpected model history %v", history)
+			}
+			if err := store.UpdatePeerCollect("16Uiu2HAm1", PeerCollectInfo{}); err == nil {
+				t.Error("Update peer collect succeeded without peers collect")
+			}
+			if _, code := store.ListAIProjects(10); code != int(types.ErrCodeUnsupported) {
+				t.Errorf("Unexpected code %d without peers collect", code)
+			}
+		})
+	}
+}
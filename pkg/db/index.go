@@ -0,0 +1,258 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/types"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// The peers collect db keeps the peer records under the peer ids, and the
+// secondary index under indexPrefix + project + sep + model + sep + peer id.
+// Peer ids are base58 encoded, so they always sort before the index keys.
+var (
+	indexPrefix     = []byte("~idx/")
+	indexVersionKey = []byte("~meta/index_version")
+	indexSeparator  = []byte{0}
+	// Range of the peer records, excluding the index and meta keys
+	peerRecordRange = &util.Range{Limit: []byte("~")}
+)
+
+const indexVersion = "1"
+
+type peerIndexValue struct {
+	types.ModelIdle
+	Timestamp int64 `json:"Timestamp"`
+}
+
+func projectIndexPrefix(project string) []byte {
+	key := make([]byte, 0, len(indexPrefix)+len(project)+1)
+	key = append(key, indexPrefix...)
+	key = append(key, project...)
+	return append(key, indexSeparator...)
+}
+
+func modelIndexPrefix(project, model string) []byte {
+	key := projectIndexPrefix(project)
+	key = append(key, model...)
+	return append(key, indexSeparator...)
+}
+
+func peerIndexKey(project, model, id string) []byte {
+	return append(modelIndexPrefix(project, model), id...)
+}
+
+// parseIndexKey splits an index key into project, model and peer id.
+func parseIndexKey(key []byte) (string, string, string, bool) {
+	if !bytes.HasPrefix(key, indexPrefix) {
+		return "", "", "", false
+	}
+	parts := bytes.Split(key[len(indexPrefix):], indexSeparator)
+	if len(parts) != 3 {
+		return "", "", "", false
+	}
+	return string(parts[0]), string(parts[1]), string(parts[2]), true
+}
+
+// peerIndexEntries returns the index entries of a peer record, one entry per
+// project and model with the most idle instance of the model.
+func peerIndexEntries(id string, info PeerCollectInfo) map[string]peerIndexValue {
+	entries := make(map[string]peerIndexValue)
+	for project, models := range info.AIProjects {
+		for _, mi := range models {
+			key := string(peerIndexKey(project, mi.Model, id))
+			if existed, ok := entries[key]; ok && existed.Idle <= mi.Idle {
+				continue
+			}
+			entries[key] = peerIndexValue{
+				ModelIdle: mi,
+				Timestamp: info.Timestamp,
+			}
+		}
+	}
+	return entries
+}
+
+// writePeerIndex adds the changes of the index from the old record to the new
+// record into the batch. A nil record means the peer does not exist.
+func writePeerIndex(batch *leveldb.Batch, id string, old, info *PeerCollectInfo) (map[string]peerIndexValue, error) {
+	var oldEntries, newEntries map[string]peerIndexValue
+	if old != nil {
+		oldEntries = peerIndexEntries(id, *old)
+	}
+	if info != nil {
+		newEntries = peerIndexEntries(id, *info)
+	}
+	for key := range oldEntries {
+		if _, ok := newEntries[key]; !ok {
+			batch.Delete([]byte(key))
+		}
+	}
+	for key, value := range newEntries {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		batch.Put([]byte(key), data)
+	}
+	return oldEntries, nil
+}
+
+// rebuildPeerCollectIndex creates the index of the databases written before it was introduced.
+func rebuildPeerCollectIndex(peersCollectDB *leveldb.DB) error {
+	if version, err := peersCollectDB.Get(indexVersionKey, nil); err == nil && string(version) == indexVersion {
+		return nil
+	}
+
+	batch := new(leveldb.Batch)
+	iter := peersCollectDB.NewIterator(util.BytesPrefix(indexPrefix), nil)
+	for iter.Next() {
+		batch.Delete(append([]byte{}, iter.Key()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+
+	count := 0
+	iter = peersCollectDB.NewIterator(peerRecordRange, nil)
+	for iter.Next() {
+		var info PeerCollectInfo
+		if err := json.Unmarshal(iter.Value(), &info); err != nil {
+			log.Logger.Warn("Parse failed when rebuild index of ", iter.Key(), err)
+			continue
+		}
+		if _, err := writePeerIndex(batch, string(iter.Key()), nil, &info); err != nil {
+			iter.Release()
+			return err
+		}
+		count++
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	batch.Put(indexVersionKey, []byte(indexVersion))
+	if err := peersCollectDB.Write(batch, nil); err != nil {
+		return err
+	}
+	log.Logger.Infof("Rebuild peers collect index of %d peers", count)
+	return nil
+}
+
+// peerCollectCache is a read-through cache in front of the peers collect db,
+// it is kept up to date by the writes of the store.
+type peerCollectCache struct {
+	mutex sync.RWMutex
+	// peer id -> peer record
+	infos map[string]PeerCollectInfo
+	// project + sep + model -> peer id -> index value
+	peers map[string]map[string]peerIndexValue
+	// incremented by every change of the records, the snapshots loaded from the datastore
+	// before a change are not cached
+	generation uint64
+}
+
+func newPeerCollectCache() *peerCollectCache {
+	return &peerCollectCache{
+		infos: make(map[string]PeerCollectInfo),
+		peers: make(map[string]map[string]peerIndexValue),
+	}
+}
+
+func (pc *peerCollectCache) reset() {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	pc.infos = make(map[string]PeerCollectInfo)
+	pc.peers = make(map[string]map[string]peerIndexValue)
+	pc.generation++
+}
+
+// currentGeneration returns the generation to pass to setInfo and setPeers, it is read before
+// the records are loaded from the datastore.
+func (pc *peerCollectCache) currentGeneration() uint64 {
+	pc.mutex.RLock()
+	defer pc.mutex.RUnlock()
+	return pc.generation
+}
+
+func (pc *peerCollectCache) getInfo(id string) (PeerCollectInfo, bool) {
+	pc.mutex.RLock()
+	defer pc.mutex.RUnlock()
+	info, ok := pc.infos[id]
+	return info, ok
+}
+
+// setInfo caches the record loaded in the generation, unless the records have changed since.
+func (pc *peerCollectCache) setInfo(generation uint64, id string, info PeerCollectInfo) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	if generation != pc.generation {
+		return
+	}
+	pc.infos[id] = info
+}
+
+// getPeers returns a copy of the cached peers of the project and model.
+func (pc *peerCollectCache) getPeers(project, model string) (map[string]peerIndexValue, bool) {
+	pc.mutex.RLock()
+	defer pc.mutex.RUnlock()
+	peers, ok := pc.peers[string(modelIndexPrefix(project, model))]
+	if !ok {
+		return nil, false
+	}
+	return copyPeers(peers), true
+}
+
+// setPeers caches a copy of the peers loaded in the generation, unless the records have changed since.
+func (pc *peerCollectCache) setPeers(generation uint64, project, model string, peers map[string]peerIndexValue) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	if generation != pc.generation {
+		return
+	}
+	pc.peers[string(modelIndexPrefix(project, model))] = copyPeers(peers)
+}
+
+func copyPeers(peers map[string]peerIndexValue) map[string]peerIndexValue {
+	result := make(map[string]peerIndexValue, len(peers))
+	for id, value := range peers {
+		result[id] = value
+	}
+	return result
+}
+
+// update applies the change of a peer record to the cache, a nil record means the peer is deleted.
+func (pc *peerCollectCache) update(id string, oldEntries map[string]peerIndexValue, info *PeerCollectInfo) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+	pc.generation++
+	for key := range oldEntries {
+		project, model, _, ok := parseIndexKey([]byte(key))
+		if !ok {
+			continue
+		}
+		if peers, ok := pc.peers[string(modelIndexPrefix(project, model))]; ok {
+			delete(peers, id)
+		}
+	}
+	if info == nil {
+		delete(pc.infos, id)
+		return
+	}
+	pc.infos[id] = *info
+	for key, value := range peerIndexEntries(id, *info) {
+		project, model, _, ok := parseIndexKey([]byte(key))
+		if !ok {
+			continue
+		}
+		if peers, ok := pc.peers[string(modelIndexPrefix(project, model))]; ok {
+			peers[id] = value
+		}
+	}
+}
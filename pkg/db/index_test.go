@@ -0,0 +1,338 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/types"
+
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+func openPeersCollectStore(tb testing.TB) *LevelDBStore {
+	store, err := OpenLevelDBStore(InitOptions{
+		Folder:             tb.TempDir(),
+		EnablePeersCollect: true,
+	})
+	if err != nil {
+		tb.Fatal("Init db failed", err)
+	}
+	tb.Cleanup(func() {
+		store.Close()
+	})
+	return store
+}
+
+func newPeerCollectInfo(timestamp int64, project string, models ...types.ModelIdle) PeerCollectInfo {
+	return PeerCollectInfo{
+		AIProjects: map[string][]types.ModelIdle{
+			project: models,
+		},
+		NodeType:  uint32(types.ModelFlag),
+		Timestamp: timestamp,
+	}
+}
+
+func newModelIdle(model string, idle int) types.ModelIdle {
+	return types.ModelIdle{
+		AIModelConfig: types.AIModelConfig{
+			Model: model,
+			API:   "http://127.0.0.1:1042/v1/chat/completions",
+		},
+		Idle: idle,
+	}
+}
+
+func countIndexKeys(t *testing.T, store *LevelDBStore) int {
+	count := 0
+	iter := store.peersCollectDB.NewIterator(util.BytesPrefix(indexPrefix), nil)
+	for iter.Next() {
+		count++
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		t.Fatal("Iterator failed", err)
+	}
+	return count
+}
+
+// go test -v -timeout 30s -count=1 -run TestPeerCollectIndex AIComputingNode/pkg/db
+func TestPeerCollectIndex(t *testing.T) {
+	for backend, store := range openTestStores(t, true) {
+		t.Run(backend, func(t *testing.T) {
+			testPeerCollectIndex(t, store, func(expected int) {
+				if ls, ok := store.(*LevelDBStore); ok {
+					if n := countIndexKeys(t, ls); n != expected {
+						t.Errorf("Expected %d index keys, got %d", expected, n)
+					}
+				}
+			})
+		})
+	}
+}
+
+func testPeerCollectIndex(t *testing.T, store Store, checkIndexKeys func(expected int)) {
+	now := time.Now().Unix()
+
+	store.UpdatePeerCollect("16Uiu2HAm1", newPeerCollectInfo(now, "DecentralGPT",
+		newModelIdle("Qwen2.5-72B", 2), newModelIdle("Qwen2.5-72B", 0), newModelIdle("Llama3-70B", 1)))
+	store.UpdatePeerCollect("16Uiu2HAm2", newPeerCollectInfo(now, "DecentralGPT",
+		newModelIdle("Qwen2.5-72B", 1)))
+	store.UpdatePeerCollect("16Uiu2HAm3", newPeerCollectInfo(now, "SuperImageAI",
+		newModelIdle("FLUX.1-dev", 0)))
+
+	checkIndexKeys(4)
+	projects, code := store.ListAIProjects(10)
+	if code != 0 || len(projects) != 2 {
+		t.Errorf("ListAIProjects got %v %d", projects, code)
+	}
+	models, code := store.GetModelsOfAIProjects("DecentralGPT", 10)
+	if code != 0 || len(models) != 2 {
+		t.Errorf("GetModelsOfAIProjects got %v %d", models, code)
+	}
+	ids, code := store.GetPeersOfAIProjects("DecentralGPT", "Qwen2.5-72B", 10)
+	if code != 0 || len(ids) != 2 {
+		t.Fatalf("GetPeersOfAIProjects got %v %d", ids, code)
+	}
+	if ids["16Uiu2HAm1"].Idle != 0 {
+		t.Errorf("Expected the most idle instance, got %v", ids["16Uiu2HAm1"])
+	}
+
+	// The model no longer served by the peer must leave the index and the cache
+	store.UpdatePeerCollect("16Uiu2HAm1", newPeerCollectInfo(now, "DecentralGPT",
+		newModelIdle("Llama3-70B", 1)))
+	ids, _ = store.GetPeersOfAIProjects("DecentralGPT", "Qwen2.5-72B", 10)
+	if _, ok := ids["16Uiu2HAm1"]; ok || len(ids) != 1 {
+		t.Errorf("Stale index entry after update %v", ids)
+	}
+	checkIndexKeys(3)
+
+	// Expired peers are removed together with their index
+	store.UpdatePeerCollect("16Uiu2HAm2", newPeerCollectInfo(time.Now().Add(-48*time.Hour).Unix(), "DecentralGPT",
+		newModelIdle("Qwen2.5-72B", 1)))
+	store.CleanExpiredPeerCollectInfo()
+	ids, _ = store.GetPeersOfAIProjects("DecentralGPT", "Qwen2.5-72B", 10)
+	if len(ids) != 0 {
+		t.Errorf("Expired peer still indexed %v", ids)
+	}
+	info := &PeerCollectInfo{}
+	if err := store.GetAIProjectsOfNode("16Uiu2HAm2", info); err == nil {
+		t.Error("Expired peer still exists")
+	}
+	peers, _ := store.FindPeers(10)
+	if len(peers) != 2 {
+		t.Errorf("FindPeers returned index keys %v", peers)
+	}
+	infos, code := store.ListPeerCollectInfo()
+	if code != 0 || len(infos) != 2 {
+		t.Errorf("ListPeerCollectInfo got %v %d", infos, code)
+	}
+
+	// The previous id of a rotated key is deleted together with its index
+	if err := store.DeletePeerCollect("16Uiu2HAm1"); err != nil {
+		t.Fatalf("DeletePeerCollect failed %v", err)
+	}
+	if err := store.DeletePeerCollect("16Uiu2HAm1"); err != nil {
+		t.Errorf("DeletePeerCollect of missing peer failed %v", err)
+	}
+	if models, _ := store.GetModelsOfAIProjects("DecentralGPT", 10); len(models) != 0 {
+		t.Errorf("Deleted peer still indexed %v", models)
+	}
+	checkIndexKeys(1)
+}
+
+// go test -v -timeout 30s -count=1 -run TestRebuildPeerCollectIndex AIComputingNode/pkg/db
+func TestRebuildPeerCollectIndex(t *testing.T) {
+	store := openPeersCollectStore(t)
+
+	// Records written before the index was introduced
+	value, _ := json.Marshal(newPeerCollectInfo(time.Now().Unix(), "DecentralGPT", newModelIdle("Qwen2.5-72B", 0)))
+	if err := store.peersCollectDB.Put([]byte("16Uiu2HAm1"), value, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.peersCollectDB.Delete(indexVersionKey, nil); err != nil {
+		t.Fatal(err)
+	}
+	store.peerCache.reset()
+	if err := rebuildPeerCollectIndex(store.peersCollectDB); err != nil {
+		t.Fatal("Rebuild index failed", err)
+	}
+	ids, code := store.GetPeersOfAIProjects("DecentralGPT", "Qwen2.5-72B", 10)
+	if code != 0 || len(ids) != 1 {
+		t.Errorf("GetPeersOfAIProjects after rebuild got %v %d", ids, code)
+	}
+}
+
+// go test -v -timeout 30s -count=1 -run TestGetPeersOfAIProjectsLimit AIComputingNode/pkg/db
+func TestGetPeersOfAIProjectsLimit(t *testing.T) {
+	for backend, store := range openTestStores(t, true) {
+		t.Run(backend, func(t *testing.T) {
+			testGetPeersOfAIProjectsLimit(t, store)
+		})
+	}
+}
+
+func testGetPeersOfAIProjectsLimit(t *testing.T, store Store) {
+	now := time.Now().Unix()
+	for i := 0; i < 50; i++ {
+		idle := 1
+		if i%10 == 0 {
+			idle = 0
+		}
+		store.UpdatePeerCollect(fmt.Sprintf("16Uiu2HAm%03d", i), newPeerCollectInfo(now, "DecentralGPT",
+			newModelIdle("Qwen2.5-72B", idle)))
+	}
+
+	chosen := make(map[string]bool)
+	for round := 0; round < 10; round++ {
+		ids, code := store.GetPeersOfAIProjects("DecentralGPT", "Qwen2.5-72B", 20)
+		if code != 0 || len(ids) != 20 {
+			t.Fatalf("GetPeersOfAIProjects got %d peers %d", len(ids), code)
+		}
+		idle := 0
+		for id, mi := range ids {
+			chosen[id] = true
+			if mi.Idle == 0 {
+				idle++
+			}
+		}
+		if idle != 5 {
+			t.Errorf("Expected all 5 idle peers to be chosen, got %d", idle)
+		}
+	}
+	if len(chosen) <= 20 {
+		t.Errorf("The same %d peers are always chosen", len(chosen))
+	}
+}
+
+// go test -race -v -timeout 60s -count=1 -run TestGetPeersOfAIProjectsConcurrent AIComputingNode/pkg/db
+func TestGetPeersOfAIProjectsConcurrent(t *testing.T) {
+	for backend, store := range openTestStores(t, true) {
+		t.Run(backend, func(t *testing.T) {
+			testGetPeersOfAIProjectsConcurrent(t, store)
+		})
+	}
+}
+
+func testGetPeersOfAIProjectsConcurrent(t *testing.T, store Store) {
+	const peers = 40
+	const rounds = 20
+	now := time.Now().Unix()
+	update := func(i, idle int) {
+		store.UpdatePeerCollect(fmt.Sprintf("16Uiu2HAm%03d", i), newPeerCollectInfo(now, "DecentralGPT",
+			newModelIdle("Qwen2.5-72B", idle)))
+	}
+	for i := 0; i < peers; i++ {
+		update(i, 0)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			// the last round leaves the peers idle
+			for round := rounds; round >= 0; round-- {
+				for i := w; i < peers; i += 4 {
+					update(i, round)
+				}
+			}
+		}(w)
+	}
+	// every reader loads the peers once, so that its reads are ordered with the updates
+	// only by the locks of the store
+	for r := 0; r < 20; r++ {
+		wg.Add(1)
+		go func(r int) {
+			defer wg.Done()
+			time.Sleep(time.Duration(r) * time.Millisecond)
+			if ls, ok := store.(*LevelDBStore); ok {
+				// load the peers from the datastore while they are updated
+				ls.peerCache.reset()
+			}
+			store.GetPeersOfAIProjects("DecentralGPT", "Qwen2.5-72B", peers)
+		}(r)
+	}
+	wg.Wait()
+
+	// no snapshot loaded during the updates is left in the cache
+	ids, code := store.GetPeersOfAIProjects("DecentralGPT", "Qwen2.5-72B", peers)
+	if code != 0 || len(ids) != peers {
+		t.Fatalf("GetPeersOfAIProjects after the updates got %d peers %d", len(ids), code)
+	}
+	for id, mi := range ids {
+		if mi.Idle != 0 {
+			t.Errorf("Stale idle %d of peer %s", mi.Idle, id)
+		}
+	}
+}
+
+func seedPeersCollectDB(b *testing.B, peers int) *LevelDBStore {
+	store := openPeersCollectStore(b)
+	now := time.Now().Unix()
+	projects := []string{"DecentralGPT", "SuperImageAI", "DeepLink", "ChainGPT"}
+	for i := 0; i < peers; i++ {
+		project := projects[i%len(projects)]
+		info := newPeerCollectInfo(now, project,
+			newModelIdle(fmt.Sprintf("model-%d", i%16), i%3),
+			newModelIdle(fmt.Sprintf("model-%d", (i+1)%16), i%5))
+		if err := store.UpdatePeerCollect(fmt.Sprintf("16Uiu2HAm%06d", i), info); err != nil {
+			b.Fatal(err)
+		}
+	}
+	store.peerCache.reset()
+	b.ResetTimer()
+	return store
+}
+
+// go test -run ^$ -bench . -benchmem AIComputingNode/pkg/db
+func BenchmarkGetPeersOfAIProjects(b *testing.B) {
+	store := seedPeersCollectDB(b, 10000)
+	for i := 0; i < b.N; i++ {
+		if _, code := store.GetPeersOfAIProjects("DecentralGPT", "model-4", 20); code != 0 {
+			b.Fatal("GetPeersOfAIProjects failed", code)
+		}
+	}
+}
+
+func BenchmarkGetPeersOfAIProjectsUncached(b *testing.B) {
+	store := seedPeersCollectDB(b, 10000)
+	for i := 0; i < b.N; i++ {
+		store.peerCache.reset()
+		if _, code := store.GetPeersOfAIProjects("DecentralGPT", "model-4", 20); code != 0 {
+			b.Fatal("GetPeersOfAIProjects failed", code)
+		}
+	}
+}
+
+func BenchmarkListAIProjects(b *testing.B) {
+	store := seedPeersCollectDB(b, 10000)
+	for i := 0; i < b.N; i++ {
+		if _, code := store.ListAIProjects(100); code != 0 {
+			b.Fatal("ListAIProjects failed", code)
+		}
+	}
+}
+
+func BenchmarkGetModelsOfAIProjects(b *testing.B) {
+	store := seedPeersCollectDB(b, 10000)
+	for i := 0; i < b.N; i++ {
+		if _, code := store.GetModelsOfAIProjects("DecentralGPT", 100); code != 0 {
+			b.Fatal("GetModelsOfAIProjects failed", code)
+		}
+	}
+}
+
+func BenchmarkUpdatePeerCollect(b *testing.B) {
+	store := seedPeersCollectDB(b, 10000)
+	now := time.Now().Unix()
+	for i := 0; i < b.N; i++ {
+		info := newPeerCollectInfo(now, "DecentralGPT", newModelIdle(fmt.Sprintf("model-%d", i%16), i%3))
+		if err := store.UpdatePeerCollect(fmt.Sprintf("16Uiu2HAm%06d", i%10000), info); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,586 @@
+package db
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/types"
+
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// LevelDBStore keeps every kind of data in its own leveldb database under the folder.
+type LevelDBStore struct {
+	connsDB        *leveldb.DB
+	modelsDB       *leveldb.DB
+	remoteCacheDB  *leveldb.DB
+	jobsDB         *leveldb.DB
+	peersCollectDB *leveldb.DB
+
+	// Serializes the read-modify-write of the peer records and their index
+	peersCollectMutex sync.Mutex
+	peerCache         *peerCollectCache
+}
+
+func OpenLevelDBStore(opts InitOptions) (*LevelDBStore, error) {
+	if opts.ConnsDBName == "" {
+		opts.ConnsDBName = "conns.db"
+	}
+	if opts.ModelsDBName == "" {
+		opts.ModelsDBName = "models.db"
+	}
+	if opts.RemoteCacheDBName == "" {
+		opts.RemoteCacheDBName = "remote_cache.db"
+	}
+	if opts.JobsDBName == "" {
+		opts.JobsDBName = "jobs.db"
+	}
+	s := &LevelDBStore{
+		peerCache: newPeerCollectCache(),
+	}
+	var err error
+	s.connsDB, err = leveldb.OpenFile(filepath.Join(opts.Folder, opts.ConnsDBName), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.modelsDB, err = leveldb.OpenFile(filepath.Join(opts.Folder, opts.ModelsDBName), nil)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.remoteCacheDB, err = leveldb.OpenFile(filepath.Join(opts.Folder, opts.RemoteCacheDBName), nil)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	s.jobsDB, err = leveldb.OpenFile(filepath.Join(opts.Folder, opts.JobsDBName), nil)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	if opts.EnablePeersCollect {
+		s.peersCollectDB, err = leveldb.OpenFile(filepath.Join(opts.Folder, "peers_collect.db"), nil)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		if err := rebuildPeerCollectIndex(s.peersCollectDB); err != nil {
+			s.Close()
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+func (s *LevelDBStore) Close() error {
+	var result error
+	for _, db := range []*leveldb.DB{s.connsDB, s.modelsDB, s.remoteCacheDB, s.jobsDB, s.peersCollectDB} {
+		if db == nil {
+			continue
+		}
+		if err := db.Close(); err != nil && result == nil {
+			result = err
+		}
+	}
+	return result
+}
+
+func (s *LevelDBStore) LoadPeerConnHistory() map[string]string {
+	conns := make(map[string]string)
+	iter := s.connsDB.NewIterator(nil, nil)
+	for iter.Next() {
+		var pi connInfo
+		if err := json.Unmarshal(iter.Value(), &pi); err != nil {
+			log.Logger.Warn("Parse failed when load peer conn history of ", iter.Key(), err)
+			continue
+		}
+		conns[string(iter.Key())] = pi.Address
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		log.Logger.Warnf("Iterator failed when load peer conns history %v", err)
+	}
+	return conns
+}
+
+func (s *LevelDBStore) PeerConnected(id string, addr string) {
+	s.updatePeerConnHistory(id, newConnInfo(addr))
+}
+
+func (s *LevelDBStore) PeerConnectFailed(id string) {
+	value, err := s.connsDB.Get([]byte(id), nil)
+	if err != nil {
+		log.Logger.Warnf("Get connectiong db item failed %v", err)
+	}
+	var pi connInfo
+	err = json.Unmarshal(value, &pi)
+	if err != nil {
+		log.Logger.Warnf("Parse connectiong db item failed %v", err)
+	}
+
+	update, remove := peerConnFailed(id, &pi)
+	if remove {
+		if err := s.connsDB.Delete([]byte(id), nil); err != nil {
+			log.Logger.Warnf("Delete item %s failed %v", id, err)
+		}
+	} else if update {
+		s.updatePeerConnHistory(id, pi)
+	}
+}
+
+func (s *LevelDBStore) updatePeerConnHistory(id string, pi connInfo) {
+	value, err := json.Marshal(pi)
+	if err != nil {
+		log.Logger.Warnf("Marshal failed when update connectiong db %v", err)
+	}
+	err = s.connsDB.Put([]byte(id), value, nil)
+	if err != nil {
+		log.Logger.Warnf("Update connectiong db failed %v", err)
+	}
+}
+
+func (s *LevelDBStore) WriteModelHistory(mh *types.ModelHistory) error {
+	value, err := json.Marshal(mh)
+	if err != nil {
+		log.Logger.Warnf("Marshal failed when write model history %v", err)
+		return err
+	}
+	keyBytes := make([]byte, 8)
+	binary.LittleEndian.PutUint64(keyBytes, uint64(mh.TimeStamp))
+
+	if err := s.modelsDB.Put(keyBytes, value, nil); err != nil {
+		log.Logger.Warnf("Put model history failed %v", err)
+		return err
+	}
+	log.Logger.Infof("Put model history success")
+	return nil
+}
+
+func (s *LevelDBStore) ListModelHistory(limit int) ([]types.ModelHistory, error) {
+	// the keys are little endian timestamps which are not ordered, so all records are read
+	values := make([][]byte, 0)
+	iter := s.modelsDB.NewIterator(nil, nil)
+	for iter.Next() {
+		values = append(values, append([]byte{}, iter.Value()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return latestModelHistory(values, limit), nil
+}
+
+func (s *LevelDBStore) UpdatePeerCollect(id string, info PeerCollectInfo) error {
+	if s.peersCollectDB == nil {
+		log.Logger.Warn("Not supported to update peer collect")
+		return fmt.Errorf("not supported")
+	}
+	value, err := json.Marshal(info)
+	if err != nil {
+		log.Logger.Warnf("Marshal failed when update peer collect db %v", err)
+		return err
+	}
+
+	s.peersCollectMutex.Lock()
+	defer s.peersCollectMutex.Unlock()
+
+	var old *PeerCollectInfo
+	if oldValue, err := s.peersCollectDB.Get([]byte(id), nil); err == nil {
+		old = &PeerCollectInfo{}
+		if err := json.Unmarshal(oldValue, old); err != nil {
+			log.Logger.Warnf("Parse failed when load peer collect info of %s %v", id, err)
+			old = nil
+		}
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put([]byte(id), value)
+	oldEntries, err := writePeerIndex(batch, id, old, &info)
+	if err != nil {
+		log.Logger.Warnf("Marshal failed when update peer collect index %v", err)
+		return err
+	}
+	err = s.peersCollectDB.Write(batch, nil)
+	if err != nil {
+		log.Logger.Warnf("Update peer collect db failed %v", err)
+		return err
+	}
+	s.peerCache.update(id, oldEntries, &info)
+	log.Logger.Infof("Update peer collect of %s success", id)
+	return nil
+}
+
+func (s *LevelDBStore) DeletePeerCollect(id string) error {
+	if s.peersCollectDB == nil {
+		return fmt.Errorf("not supported")
+	}
+
+	s.peersCollectMutex.Lock()
+	defer s.peersCollectMutex.Unlock()
+
+	oldValue, err := s.peersCollectDB.Get([]byte(id), nil)
+	if err != nil {
+		return nil
+	}
+	old := &PeerCollectInfo{}
+	if err := json.Unmarshal(oldValue, old); err != nil {
+		log.Logger.Warnf("Parse failed when load peer collect info of %s %v", id, err)
+		old = nil
+	}
+	batch := new(leveldb.Batch)
+	batch.Delete([]byte(id))
+	oldEntries, _ := writePeerIndex(batch, id, old, nil)
+	if err := s.peersCollectDB.Write(batch, nil); err != nil {
+		log.Logger.Warnf("Delete peer collect info of %s failed %v", id, err)
+		return err
+	}
+	s.peerCache.update(id, oldEntries, nil)
+	log.Logger.Infof("Delete peer collect info of %s success", id)
+	return nil
+}
+
+func (s *LevelDBStore) GetAIProjectsOfNode(id string, info *PeerCollectInfo) error {
+	if s.peersCollectDB == nil {
+		return fmt.Errorf("peers collect db not exist")
+	}
+	if cached, ok := s.peerCache.getInfo(id); ok {
+		*info = cached
+		return nil
+	}
+	generation := s.peerCache.currentGeneration()
+	value, err := s.peersCollectDB.Get([]byte(id), nil)
+	if err != nil {
+		return fmt.Errorf("id not exist %v", err.Error())
+	}
+	if err := json.Unmarshal(value, info); err != nil {
+		return fmt.Errorf("unmarshal json failed %v", err.Error())
+	}
+	s.peerCache.setInfo(generation, id, *info)
+	return nil
+}
+
+func (s *LevelDBStore) FindPeers(limit int) ([]string, int) {
+	ids := make([]string, 0)
+	if s.peersCollectDB == nil {
+		return ids, int(types.ErrCodeUnsupported)
+	}
+
+	iter := s.peersCollectDB.NewIterator(peerRecordRange, nil)
+	var count int = 0
+	for iter.Next() && count < limit {
+		ids = append(ids, string(iter.Key()))
+		count = count + 1
+	}
+
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		log.Logger.Warnf("Iterator failed when load peer collect info %v", err)
+		return ids, int(types.ErrCodeDatabase)
+	}
+	return ids, 0
+}
+
+func (s *LevelDBStore) ListPeerCollectInfo() (map[string]PeerCollectInfo, int) {
+	infos := make(map[string]PeerCollectInfo)
+	if s.peersCollectDB == nil {
+		return infos, int(types.ErrCodeUnsupported)
+	}
+
+	iter := s.peersCollectDB.NewIterator(peerRecordRange, nil)
+	for iter.Next() {
+		var info PeerCollectInfo
+		if err := json.Unmarshal(iter.Value(), &info); err != nil {
+			log.Logger.Warn("Parse failed when load peer collect info of ", iter.Key(), err)
+			continue
+		}
+		infos[string(iter.Key())] = info
+	}
+
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		log.Logger.Warnf("Iterator failed when load peer collect info %v", err)
+		return infos, int(types.ErrCodeDatabase)
+	}
+	return infos, 0
+}
+
+func (s *LevelDBStore) ListAIProjects(limit int) ([]string, int) {
+	ids := make([]string, 0)
+	if s.peersCollectDB == nil {
+		return ids, int(types.ErrCodeUnsupported)
+	}
+
+	set := types.NewSet()
+	timestamp := time.Now().Add(-time.Hour * 12)
+	iter := s.peersCollectDB.NewIterator(util.BytesPrefix(indexPrefix), nil)
+	for ok := iter.Next(); ok && set.Size() < limit; {
+		project, _, _, valid := parseIndexKey(iter.Key())
+		var value peerIndexValue
+		if !valid || json.Unmarshal(iter.Value(), &value) != nil {
+			log.Logger.Warn("Parse failed when load peer collect index of ", iter.Key())
+			ok = iter.Next()
+			continue
+		}
+		if time.Unix(value.Timestamp, 0).Before(timestamp) {
+			ok = iter.Next()
+			continue
+		}
+		set.Add(project)
+		// skip the other models and peers of this project
+		ok = iter.Seek(append(projectIndexPrefix(project), 0xff))
+	}
+
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		log.Logger.Warnf("Iterator failed when load peer collect index %v", err)
+		return ids, int(types.ErrCodeDatabase)
+	}
+
+	for _, item := range set.Elements() {
+		ids = append(ids, item.(string))
+	}
+	return ids, 0
+}
+
+func (s *LevelDBStore) GetModelsOfAIProjects(project string, limit int) ([]string, int) {
+	models := make([]string, 0)
+	if s.peersCollectDB == nil {
+		return models, int(types.ErrCodeUnsupported)
+	}
+
+	set := types.NewSet()
+	timestamp := time.Now().Add(-time.Hour * 12)
+	iter := s.peersCollectDB.NewIterator(util.BytesPrefix(projectIndexPrefix(project)), nil)
+	for ok := iter.Next(); ok && set.Size() < limit; {
+		_, model, _, valid := parseIndexKey(iter.Key())
+		var value peerIndexValue
+		if !valid || json.Unmarshal(iter.Value(), &value) != nil {
+			log.Logger.Warn("Parse failed when load peer collect index of ", iter.Key())
+			ok = iter.Next()
+			continue
+		}
+		if time.Unix(value.Timestamp, 0).Before(timestamp) {
+			ok = iter.Next()
+			continue
+		}
+		set.Add(model)
+		// skip the other peers of this model
+		ok = iter.Seek(append(modelIndexPrefix(project, model), 0xff))
+	}
+
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		log.Logger.Warnf("Iterator failed when load peer collect index %v", err)
+		return models, int(types.ErrCodeDatabase)
+	}
+
+	for _, item := range set.Elements() {
+		models = append(models, item.(string))
+	}
+	return models, 0
+}
+
+func (s *LevelDBStore) loadPeersOfAIProjects(project, model string) (map[string]peerIndexValue, error) {
+	if peers, ok := s.peerCache.getPeers(project, model); ok {
+		return peers, nil
+	}
+
+	generation := s.peerCache.currentGeneration()
+	peers := make(map[string]peerIndexValue)
+	iter := s.peersCollectDB.NewIterator(util.BytesPrefix(modelIndexPrefix(project, model)), nil)
+	for iter.Next() {
+		_, _, id, ok := parseIndexKey(iter.Key())
+		if !ok {
+			continue
+		}
+		var value peerIndexValue
+		if err := json.Unmarshal(iter.Value(), &value); err != nil {
+			log.Logger.Warn("Parse failed when load peer collect index of ", iter.Key(), err)
+			continue
+		}
+		peers[id] = value
+	}
+
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	s.peerCache.setPeers(generation, project, model, peers)
+	return peers, nil
+}
+
+// GetPeersOfAIProjects returns at most limit peers running the model, see selectPeers.
+func (s *LevelDBStore) GetPeersOfAIProjects(project, model string, limit int) (map[string]types.ModelIdle, int) {
+	if s.peersCollectDB == nil {
+		return make(map[string]types.ModelIdle), int(types.ErrCodeUnsupported)
+	}
+
+	peers, err := s.loadPeersOfAIProjects(project, model)
+	if err != nil {
+		log.Logger.Warnf("Iterator failed when load peer collect index %v", err)
+		return make(map[string]types.ModelIdle), int(types.ErrCodeDatabase)
+	}
+	return selectPeers(peers, limit), 0
+}
+
+func (s *LevelDBStore) CleanExpiredPeerCollectInfo() {
+	if s.peersCollectDB == nil {
+		return
+	}
+
+	s.peersCollectMutex.Lock()
+	defer s.peersCollectMutex.Unlock()
+
+	infos := make(map[string]PeerCollectInfo)
+	iter := s.peersCollectDB.NewIterator(peerRecordRange, nil)
+	timestamp := time.Now().Add(-time.Hour * 24)
+	for iter.Next() {
+		var info PeerCollectInfo
+		if err := json.Unmarshal(iter.Value(), &info); err != nil {
+			log.Logger.Warn("Parse failed when load peer collect info of ", iter.Key(), err)
+			continue
+		}
+		if time.Unix(info.Timestamp, 0).Before(timestamp) {
+			infos[string(iter.Key())] = info
+		}
+	}
+
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		log.Logger.Warnf("Iterator failed when load peer collect info %v", err)
+		return
+	}
+
+	for id, info := range infos {
+		batch := new(leveldb.Batch)
+		batch.Delete([]byte(id))
+		oldEntries, _ := writePeerIndex(batch, id, &info, nil)
+		if err := s.peersCollectDB.Write(batch, nil); err != nil {
+			log.Logger.Warnf("Delete expired peer collect info of %s failed %v", id, err)
+		} else {
+			s.peerCache.update(id, oldEntries, nil)
+			log.Logger.Infof("Delete expired peer collect info of %s with %v", id, info.Timestamp)
+		}
+	}
+}
+
+// PutRemoteCache saves the response of the remote node id, replacing the old one.
+func (s *LevelDBStore) PutRemoteCache(kind, id string, data any) error {
+	value, err := newRemoteCacheItem(data)
+	if err != nil {
+		return err
+	}
+	if err := s.remoteCacheDB.Put(remoteCacheKey(kind, id), value, nil); err != nil {
+		log.Logger.Warnf("Update remote cache of %s %s failed %v", kind, id, err)
+		return err
+	}
+	return nil
+}
+
+// GetRemoteCache loads the cached response of the remote node id into data
+// and returns the unix time when it was cached.
+func (s *LevelDBStore) GetRemoteCache(kind, id string, data any) (int64, error) {
+	value, err := s.remoteCacheDB.Get(remoteCacheKey(kind, id), nil)
+	if err != nil {
+		return 0, err
+	}
+	return parseRemoteCacheItem(value, data)
+}
+
+// CleanExpiredRemoteCache deletes the responses cached longer than ttl.
+func (s *LevelDBStore) CleanExpiredRemoteCache(ttl time.Duration) {
+	keys := make([][]byte, 0)
+	iter := s.remoteCacheDB.NewIterator(nil, nil)
+	timestamp := time.Now().Add(-ttl)
+	for iter.Next() {
+		var item remoteCacheItem
+		if err := json.Unmarshal(iter.Value(), &item); err != nil ||
+			time.Unix(item.Timestamp, 0).Before(timestamp) {
+			keys = append(keys, append([]byte{}, iter.Key()...))
+		}
+	}
+
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		log.Logger.Warnf("Iterator failed when load remote cache %v", err)
+		return
+	}
+
+	for _, key := range keys {
+		if err := s.remoteCacheDB.Delete(key, nil); err != nil {
+			log.Logger.Warnf("Delete expired remote cache of %s failed %v", string(key), err)
+		}
+	}
+	if len(keys) > 0 {
+		log.Logger.Infof("Delete %d expired remote cache items", len(keys))
+	}
+}
+
+func (s *LevelDBStore) PutJob(job *types.Job) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if err := s.jobsDB.Put([]byte(job.ID), value, nil); err != nil {
+		log.Logger.Warnf("Put job %s failed %v", job.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *LevelDBStore) GetJob(id string) (types.Job, error) {
+	var job types.Job
+	value, err := s.jobsDB.Get([]byte(id), nil)
+	if errors.Is(err, leveldb.ErrNotFound) {
+		return job, ErrJobNotFound
+	} else if err != nil {
+		return job, err
+	}
+	err = json.Unmarshal(value, &job)
+	return job, err
+}
+
+func (s *LevelDBStore) ListPendingJobs() ([]types.Job, error) {
+	values := make([][]byte, 0)
+	iter := s.jobsDB.NewIterator(nil, nil)
+	for iter.Next() {
+		values = append(values, append([]byte{}, iter.Value()...))
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	return pendingJobs(values), nil
+}
+
+func (s *LevelDBStore) CleanExpiredJobs(ttl time.Duration) {
+	keys := make([][]byte, 0)
+	iter := s.jobsDB.NewIterator(nil, nil)
+	for iter.Next() {
+		if jobExpired(iter.Value(), ttl) {
+			keys = append(keys, append([]byte{}, iter.Key()...))
+		}
+	}
+
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		log.Logger.Warnf("Iterator failed when load jobs %v", err)
+		return
+	}
+
+	for _, key := range keys {
+		if err := s.jobsDB.Delete(key, nil); err != nil {
+			log.Logger.Warnf("Delete expired job %s failed %v", string(key), err)
+		}
+	}
+	if len(keys) > 0 {
+		log.Logger.Infof("Delete %d expired jobs", len(keys))
+	}
+}
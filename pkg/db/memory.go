@@ -0,0 +1,332 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/types"
+)
+
+// MemoryStore keeps everything in memory and loses it when the node exits,
+// it is meant for tests and short-lived nodes.
+type MemoryStore struct {
+	mutex              sync.RWMutex
+	enablePeersCollect bool
+	conns              map[string]connInfo
+	models             map[int64][]byte
+	peers              map[string]PeerCollectInfo
+	// index key -> index value, see peerIndexEntries
+	index       map[string]peerIndexValue
+	remoteCache map[string][]byte
+	jobs        map[string][]byte
+}
+
+func NewMemoryStore(enablePeersCollect bool) *MemoryStore {
+	return &MemoryStore{
+		enablePeersCollect: enablePeersCollect,
+		conns:              make(map[string]connInfo),
+		models:             make(map[int64][]byte),
+		peers:              make(map[string]PeerCollectInfo),
+		index:              make(map[string]peerIndexValue),
+		remoteCache:        make(map[string][]byte),
+		jobs:               make(map[string][]byte),
+	}
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+func (s *MemoryStore) LoadPeerConnHistory() map[string]string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	conns := make(map[string]string, len(s.conns))
+	for id, pi := range s.conns {
+		conns[id] = pi.Address
+	}
+	return conns
+}
+
+func (s *MemoryStore) PeerConnected(id string, addr string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.conns[id] = newConnInfo(addr)
+}
+
+func (s *MemoryStore) PeerConnectFailed(id string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	pi := s.conns[id]
+	update, remove := peerConnFailed(id, &pi)
+	if remove {
+		delete(s.conns, id)
+	} else if update {
+		s.conns[id] = pi
+	}
+}
+
+func (s *MemoryStore) WriteModelHistory(mh *types.ModelHistory) error {
+	value, err := json.Marshal(mh)
+	if err != nil {
+		log.Logger.Warnf("Marshal failed when write model history %v", err)
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.models[mh.TimeStamp] = value
+	return nil
+}
+
+func (s *MemoryStore) ListModelHistory(limit int) ([]types.ModelHistory, error) {
+	s.mutex.RLock()
+	values := make([][]byte, 0, len(s.models))
+	for _, value := range s.models {
+		values = append(values, value)
+	}
+	s.mutex.RUnlock()
+	return latestModelHistory(values, limit), nil
+}
+
+func (s *MemoryStore) UpdatePeerCollect(id string, info PeerCollectInfo) error {
+	if !s.enablePeersCollect {
+		log.Logger.Warn("Not supported to update peer collect")
+		return fmt.Errorf("not supported")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.setPeer(id, &info)
+	return nil
+}
+
+func (s *MemoryStore) DeletePeerCollect(id string) error {
+	if !s.enablePeersCollect {
+		return fmt.Errorf("not supported")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.setPeer(id, nil)
+	return nil
+}
+
+// setPeer replaces the record and the index entries of the peer, a nil record deletes the peer.
+func (s *MemoryStore) setPeer(id string, info *PeerCollectInfo) {
+	if old, ok := s.peers[id]; ok {
+		for key := range peerIndexEntries(id, old) {
+			delete(s.index, key)
+		}
+	}
+	if info == nil {
+		delete(s.peers, id)
+		return
+	}
+	s.peers[id] = *info
+	for key, value := range peerIndexEntries(id, *info) {
+		s.index[key] = value
+	}
+}
+
+func (s *MemoryStore) GetAIProjectsOfNode(id string, info *PeerCollectInfo) error {
+	if !s.enablePeersCollect {
+		return fmt.Errorf("peers collect db not exist")
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	value, ok := s.peers[id]
+	if !ok {
+		return fmt.Errorf("id not exist %v", id)
+	}
+	*info = value
+	return nil
+}
+
+func (s *MemoryStore) FindPeers(limit int) ([]string, int) {
+	ids := make([]string, 0)
+	if !s.enablePeersCollect {
+		return ids, int(types.ErrCodeUnsupported)
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for id := range s.peers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, 0
+}
+
+func (s *MemoryStore) ListPeerCollectInfo() (map[string]PeerCollectInfo, int) {
+	infos := make(map[string]PeerCollectInfo)
+	if !s.enablePeersCollect {
+		return infos, int(types.ErrCodeUnsupported)
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	for id, info := range s.peers {
+		infos[id] = info
+	}
+	return infos, 0
+}
+
+// collectIndex returns the sorted distinct values of part of the index keys with the prefix,
+// which are active in the last 12 hours.
+func (s *MemoryStore) collectIndex(prefix string, part func(project, model string) string, limit int) []string {
+	set := make(map[string]bool)
+	timestamp := time.Now().Add(-time.Hour * 12)
+	for key, value := range s.index {
+		if !strings.HasPrefix(key, prefix) || time.Unix(value.Timestamp, 0).Before(timestamp) {
+			continue
+		}
+		project, model, _, ok := parseIndexKey([]byte(key))
+		if !ok {
+			continue
+		}
+		set[part(project, model)] = true
+	}
+	result := make([]string, 0, len(set))
+	for item := range set {
+		result = append(result, item)
+	}
+	sort.Strings(result)
+	if len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+func (s *MemoryStore) ListAIProjects(limit int) ([]string, int) {
+	if !s.enablePeersCollect {
+		return make([]string, 0), int(types.ErrCodeUnsupported)
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.collectIndex(string(indexPrefix), func(project, model string) string {
+		return project
+	}, limit), 0
+}
+
+func (s *MemoryStore) GetModelsOfAIProjects(project string, limit int) ([]string, int) {
+	if !s.enablePeersCollect {
+		return make([]string, 0), int(types.ErrCodeUnsupported)
+	}
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.collectIndex(string(projectIndexPrefix(project)), func(project, model string) string {
+		return model
+	}, limit), 0
+}
+
+func (s *MemoryStore) GetPeersOfAIProjects(project, model string, limit int) (map[string]types.ModelIdle, int) {
+	if !s.enablePeersCollect {
+		return make(map[string]types.ModelIdle), int(types.ErrCodeUnsupported)
+	}
+	s.mutex.RLock()
+	prefix := string(modelIndexPrefix(project, model))
+	peers := make(map[string]peerIndexValue)
+	for key, value := range s.index {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		peers[key[len(prefix):]] = value
+	}
+	s.mutex.RUnlock()
+	return selectPeers(peers, limit), 0
+}
+
+func (s *MemoryStore) CleanExpiredPeerCollectInfo() {
+	if !s.enablePeersCollect {
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	timestamp := time.Now().Add(-time.Hour * 24)
+	for id, info := range s.peers {
+		if time.Unix(info.Timestamp, 0).Before(timestamp) {
+			s.setPeer(id, nil)
+			log.Logger.Infof("Delete expired peer collect info of %s with %v", id, info.Timestamp)
+		}
+	}
+}
+
+func (s *MemoryStore) PutRemoteCache(kind, id string, data any) error {
+	value, err := newRemoteCacheItem(data)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.remoteCache[string(remoteCacheKey(kind, id))] = value
+	return nil
+}
+
+func (s *MemoryStore) GetRemoteCache(kind, id string, data any) (int64, error) {
+	s.mutex.RLock()
+	value, ok := s.remoteCache[string(remoteCacheKey(kind, id))]
+	s.mutex.RUnlock()
+	if !ok {
+		return 0, fmt.Errorf("remote cache of %s %s not exist", kind, id)
+	}
+	return parseRemoteCacheItem(value, data)
+}
+
+func (s *MemoryStore) CleanExpiredRemoteCache(ttl time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	timestamp := time.Now().Add(-ttl)
+	for key, value := range s.remoteCache {
+		var item remoteCacheItem
+		if err := json.Unmarshal(value, &item); err != nil ||
+			time.Unix(item.Timestamp, 0).Before(timestamp) {
+			delete(s.remoteCache, key)
+		}
+	}
+}
+
+func (s *MemoryStore) PutJob(job *types.Job) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.jobs[job.ID] = value
+	return nil
+}
+
+func (s *MemoryStore) GetJob(id string) (types.Job, error) {
+	var job types.Job
+	s.mutex.RLock()
+	value, ok := s.jobs[id]
+	s.mutex.RUnlock()
+	if !ok {
+		return job, ErrJobNotFound
+	}
+	err := json.Unmarshal(value, &job)
+	return job, err
+}
+
+func (s *MemoryStore) ListPendingJobs() ([]types.Job, error) {
+	s.mutex.RLock()
+	values := make([][]byte, 0, len(s.jobs))
+	for _, value := range s.jobs {
+		values = append(values, value)
+	}
+	s.mutex.RUnlock()
+	return pendingJobs(values), nil
+}
+
+func (s *MemoryStore) CleanExpiredJobs(ttl time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for id, value := range s.jobs {
+		if jobExpired(value, ttl) {
+			delete(s.jobs, id)
+		}
+	}
+}
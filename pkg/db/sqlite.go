@@ -0,0 +1,490 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/types"
+
+	_ "modernc.org/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS conns (
+	id TEXT PRIMARY KEY,
+	address TEXT NOT NULL,
+	last_connect_time INTEGER NOT NULL,
+	consecutive_failures INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS model_history (
+	timestamp INTEGER PRIMARY KEY,
+	data BLOB NOT NULL
+);
+CREATE TABLE IF NOT EXISTS peers_collect (
+	id TEXT PRIMARY KEY,
+	data BLOB NOT NULL,
+	timestamp INTEGER NOT NULL
+);
+CREATE TABLE IF NOT EXISTS peer_models (
+	project TEXT NOT NULL,
+	model TEXT NOT NULL,
+	id TEXT NOT NULL,
+	data BLOB NOT NULL,
+	timestamp INTEGER NOT NULL,
+	PRIMARY KEY (project, model, id)
+);
+CREATE INDEX IF NOT EXISTS peer_models_id ON peer_models (id);
+CREATE TABLE IF NOT EXISTS remote_cache (
+	kind TEXT NOT NULL,
+	id TEXT NOT NULL,
+	data BLOB NOT NULL,
+	timestamp INTEGER NOT NULL,
+	PRIMARY KEY (kind, id)
+);
+CREATE TABLE IF NOT EXISTS jobs (
+	id TEXT PRIMARY KEY,
+	data BLOB NOT NULL,
+	finished INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL
+);
+`
+
+// SQLiteStore keeps all the data in a single sqlite database file,
+// the peer models table plays the role of the index of the leveldb store.
+type SQLiteStore struct {
+	db                 *sql.DB
+	enablePeersCollect bool
+}
+
+func OpenSQLiteStore(opts InitOptions) (*SQLiteStore, error) {
+	if opts.SQLiteDBName == "" {
+		opts.SQLiteDBName = "aicn.sqlite"
+	}
+	db, err := sql.Open("sqlite", filepath.Join(opts.Folder, opts.SQLiteDBName))
+	if err != nil {
+		return nil, err
+	}
+	// sqlite allows one writer at a time
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{
+		db:                 db,
+		enablePeersCollect: opts.EnablePeersCollect,
+	}, nil
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+func (s *SQLiteStore) LoadPeerConnHistory() map[string]string {
+	conns := make(map[string]string)
+	rows, err := s.db.Query("SELECT id, address FROM conns")
+	if err != nil {
+		log.Logger.Warnf("Query failed when load peer conns history %v", err)
+		return conns
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id, addr string
+		if err := rows.Scan(&id, &addr); err != nil {
+			log.Logger.Warnf("Scan failed when load peer conns history %v", err)
+			continue
+		}
+		conns[id] = addr
+	}
+	if err := rows.Err(); err != nil {
+		log.Logger.Warnf("Iterator failed when load peer conns history %v", err)
+	}
+	return conns
+}
+
+func (s *SQLiteStore) PeerConnected(id string, addr string) {
+	s.updatePeerConnHistory(id, newConnInfo(addr))
+}
+
+func (s *SQLiteStore) PeerConnectFailed(id string) {
+	var pi connInfo
+	err := s.db.QueryRow("SELECT address, last_connect_time, consecutive_failures FROM conns WHERE id = ?", id).
+		Scan(&pi.Address, &pi.LastConnectTime, &pi.ConsecutiveFailures)
+	if err != nil {
+		log.Logger.Warnf("Get connectiong db item failed %v", err)
+	}
+
+	update, remove := peerConnFailed(id, &pi)
+	if remove {
+		if _, err := s.db.Exec("DELETE FROM conns WHERE id = ?", id); err != nil {
+			log.Logger.Warnf("Delete item %s failed %v", id, err)
+		}
+	} else if update {
+		s.updatePeerConnHistory(id, pi)
+	}
+}
+
+func (s *SQLiteStore) updatePeerConnHistory(id string, pi connInfo) {
+	_, err := s.db.Exec("INSERT OR REPLACE INTO conns (id, address, last_connect_time, consecutive_failures) VALUES (?, ?, ?, ?)",
+		id, pi.Address, pi.LastConnectTime, pi.ConsecutiveFailures)
+	if err != nil {
+		log.Logger.Warnf("Update connectiong db failed %v", err)
+	}
+}
+
+func (s *SQLiteStore) WriteModelHistory(mh *types.ModelHistory) error {
+	value, err := json.Marshal(mh)
+	if err != nil {
+		log.Logger.Warnf("Marshal failed when write model history %v", err)
+		return err
+	}
+	if _, err := s.db.Exec("INSERT OR REPLACE INTO model_history (timestamp, data) VALUES (?, ?)", mh.TimeStamp, value); err != nil {
+		log.Logger.Warnf("Put model history failed %v", err)
+		return err
+	}
+	log.Logger.Infof("Put model history success")
+	return nil
+}
+
+func (s *SQLiteStore) ListModelHistory(limit int) ([]types.ModelHistory, error) {
+	rows, err := s.db.Query("SELECT data FROM model_history ORDER BY timestamp DESC LIMIT ?", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	values := make([][]byte, 0)
+	for rows.Next() {
+		var value []byte
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return latestModelHistory(values, limit), nil
+}
+
+func (s *SQLiteStore) UpdatePeerCollect(id string, info PeerCollectInfo) error {
+	if !s.enablePeersCollect {
+		log.Logger.Warn("Not supported to update peer collect")
+		return fmt.Errorf("not supported")
+	}
+	value, err := json.Marshal(info)
+	if err != nil {
+		log.Logger.Warnf("Marshal failed when update peer collect db %v", err)
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Logger.Warnf("Update peer collect db failed %v", err)
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("INSERT OR REPLACE INTO peers_collect (id, data, timestamp) VALUES (?, ?, ?)",
+		id, value, info.Timestamp); err != nil {
+		log.Logger.Warnf("Update peer collect db failed %v", err)
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM peer_models WHERE id = ?", id); err != nil {
+		log.Logger.Warnf("Update peer collect index failed %v", err)
+		return err
+	}
+	for key, entry := range peerIndexEntries(id, info) {
+		project, model, _, _ := parseIndexKey([]byte(key))
+		data, err := json.Marshal(entry)
+		if err != nil {
+			log.Logger.Warnf("Marshal failed when update peer collect index %v", err)
+			return err
+		}
+		if _, err := tx.Exec("INSERT INTO peer_models (project, model, id, data, timestamp) VALUES (?, ?, ?, ?, ?)",
+			project, model, id, data, entry.Timestamp); err != nil {
+			log.Logger.Warnf("Update peer collect index failed %v", err)
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		log.Logger.Warnf("Update peer collect db failed %v", err)
+		return err
+	}
+	log.Logger.Infof("Update peer collect of %s success", id)
+	return nil
+}
+
+func (s *SQLiteStore) GetAIProjectsOfNode(id string, info *PeerCollectInfo) error {
+	if !s.enablePeersCollect {
+		return fmt.Errorf("peers collect db not exist")
+	}
+	var value []byte
+	if err := s.db.QueryRow("SELECT data FROM peers_collect WHERE id = ?", id).Scan(&value); err != nil {
+		return fmt.Errorf("id not exist %v", err.Error())
+	}
+	if err := json.Unmarshal(value, info); err != nil {
+		return fmt.Errorf("unmarshal json failed %v", err.Error())
+	}
+	return nil
+}
+
+// queryStrings returns the first column of the rows of the query.
+func (s *SQLiteStore) queryStrings(query string, args ...any) ([]string, int) {
+	result := make([]string, 0)
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		log.Logger.Warnf("Query peer collect db failed %v", err)
+		return result, int(types.ErrCodeDatabase)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var item string
+		if err := rows.Scan(&item); err != nil {
+			log.Logger.Warnf("Scan peer collect db failed %v", err)
+			return result, int(types.ErrCodeDatabase)
+		}
+		result = append(result, item)
+	}
+	if err := rows.Err(); err != nil {
+		log.Logger.Warnf("Iterator failed when load peer collect db %v", err)
+		return result, int(types.ErrCodeDatabase)
+	}
+	return result, 0
+}
+
+func (s *SQLiteStore) FindPeers(limit int) ([]string, int) {
+	if !s.enablePeersCollect {
+		return make([]string, 0), int(types.ErrCodeUnsupported)
+	}
+	return s.queryStrings("SELECT id FROM peers_collect ORDER BY id LIMIT ?", limit)
+}
+
+func (s *SQLiteStore) ListPeerCollectInfo() (map[string]PeerCollectInfo, int) {
+	infos := make(map[string]PeerCollectInfo)
+	if !s.enablePeersCollect {
+		return infos, int(types.ErrCodeUnsupported)
+	}
+	rows, err := s.db.Query("SELECT id, data FROM peers_collect")
+	if err != nil {
+		log.Logger.Warnf("Query peer collect db failed %v", err)
+		return infos, int(types.ErrCodeDatabase)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id string
+		var value []byte
+		if err := rows.Scan(&id, &value); err != nil {
+			log.Logger.Warnf("Scan peer collect db failed %v", err)
+			return infos, int(types.ErrCodeDatabase)
+		}
+		var info PeerCollectInfo
+		if err := json.Unmarshal(value, &info); err != nil {
+			log.Logger.Warn("Parse failed when load peer collect info of ", id, err)
+			continue
+		}
+		infos[id] = info
+	}
+	if err := rows.Err(); err != nil {
+		log.Logger.Warnf("Iterator failed when load peer collect info %v", err)
+		return infos, int(types.ErrCodeDatabase)
+	}
+	return infos, 0
+}
+
+func (s *SQLiteStore) ListAIProjects(limit int) ([]string, int) {
+	if !s.enablePeersCollect {
+		return make([]string, 0), int(types.ErrCodeUnsupported)
+	}
+	timestamp := time.Now().Add(-time.Hour * 12).Unix()
+	return s.queryStrings("SELECT DISTINCT project FROM peer_models WHERE timestamp >= ? ORDER BY project LIMIT ?",
+		timestamp, limit)
+}
+
+func (s *SQLiteStore) GetModelsOfAIProjects(project string, limit int) ([]string, int) {
+	if !s.enablePeersCollect {
+		return make([]string, 0), int(types.ErrCodeUnsupported)
+	}
+	timestamp := time.Now().Add(-time.Hour * 12).Unix()
+	return s.queryStrings("SELECT DISTINCT model FROM peer_models WHERE project = ? AND timestamp >= ? ORDER BY model LIMIT ?",
+		project, timestamp, limit)
+}
+
+func (s *SQLiteStore) GetPeersOfAIProjects(project, model string, limit int) (map[string]types.ModelIdle, int) {
+	if !s.enablePeersCollect {
+		return make(map[string]types.ModelIdle), int(types.ErrCodeUnsupported)
+	}
+	rows, err := s.db.Query("SELECT id, data FROM peer_models WHERE project = ? AND model = ?", project, model)
+	if err != nil {
+		log.Logger.Warnf("Query peer collect index failed %v", err)
+		return make(map[string]types.ModelIdle), int(types.ErrCodeDatabase)
+	}
+	defer rows.Close()
+	peers := make(map[string]peerIndexValue)
+	for rows.Next() {
+		var id string
+		var data []byte
+		if err := rows.Scan(&id, &data); err != nil {
+			log.Logger.Warnf("Scan peer collect index failed %v", err)
+			return make(map[string]types.ModelIdle), int(types.ErrCodeDatabase)
+		}
+		var value peerIndexValue
+		if err := json.Unmarshal(data, &value); err != nil {
+			log.Logger.Warn("Parse failed when load peer collect index of ", id, err)
+			continue
+		}
+		peers[id] = value
+	}
+	if err := rows.Err(); err != nil {
+		log.Logger.Warnf("Iterator failed when load peer collect index %v", err)
+		return make(map[string]types.ModelIdle), int(types.ErrCodeDatabase)
+	}
+	return selectPeers(peers, limit), 0
+}
+
+func (s *SQLiteStore) DeletePeerCollect(id string) error {
+	if !s.enablePeersCollect {
+		return fmt.Errorf("not supported")
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Logger.Warnf("Delete peer collect info of %s failed %v", id, err)
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM peer_models WHERE id = ?", id); err != nil {
+		log.Logger.Warnf("Delete peer collect index of %s failed %v", id, err)
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM peers_collect WHERE id = ?", id); err != nil {
+		log.Logger.Warnf("Delete peer collect info of %s failed %v", id, err)
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		log.Logger.Warnf("Delete peer collect info of %s failed %v", id, err)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLiteStore) CleanExpiredPeerCollectInfo() {
+	if !s.enablePeersCollect {
+		return
+	}
+	timestamp := time.Now().Add(-time.Hour * 24).Unix()
+	tx, err := s.db.Begin()
+	if err != nil {
+		log.Logger.Warnf("Delete expired peer collect info failed %v", err)
+		return
+	}
+	defer tx.Rollback()
+	if _, err := tx.Exec("DELETE FROM peer_models WHERE id IN (SELECT id FROM peers_collect WHERE timestamp < ?)", timestamp); err != nil {
+		log.Logger.Warnf("Delete expired peer collect index failed %v", err)
+		return
+	}
+	result, err := tx.Exec("DELETE FROM peers_collect WHERE timestamp < ?", timestamp)
+	if err != nil {
+		log.Logger.Warnf("Delete expired peer collect info failed %v", err)
+		return
+	}
+	if err := tx.Commit(); err != nil {
+		log.Logger.Warnf("Delete expired peer collect info failed %v", err)
+		return
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		log.Logger.Infof("Delete %d expired peer collect info", n)
+	}
+}
+
+func (s *SQLiteStore) PutRemoteCache(kind, id string, data any) error {
+	value, err := newRemoteCacheItem(data)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("INSERT OR REPLACE INTO remote_cache (kind, id, data, timestamp) VALUES (?, ?, ?, ?)",
+		kind, id, value, time.Now().Unix()); err != nil {
+		log.Logger.Warnf("Update remote cache of %s %s failed %v", kind, id, err)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLiteStore) GetRemoteCache(kind, id string, data any) (int64, error) {
+	var value []byte
+	if err := s.db.QueryRow("SELECT data FROM remote_cache WHERE kind = ? AND id = ?", kind, id).Scan(&value); err != nil {
+		return 0, err
+	}
+	return parseRemoteCacheItem(value, data)
+}
+
+func (s *SQLiteStore) CleanExpiredRemoteCache(ttl time.Duration) {
+	result, err := s.db.Exec("DELETE FROM remote_cache WHERE timestamp < ?", time.Now().Add(-ttl).Unix())
+	if err != nil {
+		log.Logger.Warnf("Delete expired remote cache failed %v", err)
+		return
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		log.Logger.Infof("Delete %d expired remote cache items", n)
+	}
+}
+
+func (s *SQLiteStore) PutJob(job *types.Job) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if _, err := s.db.Exec("INSERT OR REPLACE INTO jobs (id, data, finished, updated_at) VALUES (?, ?, ?, ?)",
+		job.ID, value, job.Finished(), job.UpdatedAt); err != nil {
+		log.Logger.Warnf("Put job %s failed %v", job.ID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *SQLiteStore) GetJob(id string) (types.Job, error) {
+	var job types.Job
+	var value []byte
+	err := s.db.QueryRow("SELECT data FROM jobs WHERE id = ?", id).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return job, ErrJobNotFound
+	} else if err != nil {
+		return job, err
+	}
+	err = json.Unmarshal(value, &job)
+	return job, err
+}
+
+func (s *SQLiteStore) ListPendingJobs() ([]types.Job, error) {
+	// the finished jobs of the undelivered webhooks are pending too, they are filtered after decoding
+	rows, err := s.db.Query("SELECT data FROM jobs")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	values := make([][]byte, 0)
+	for rows.Next() {
+		var value []byte
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return pendingJobs(values), nil
+}
+
+func (s *SQLiteStore) CleanExpiredJobs(ttl time.Duration) {
+	result, err := s.db.Exec("DELETE FROM jobs WHERE finished = 1 AND updated_at < ?", time.Now().Add(-ttl).Unix())
+	if err != nil {
+		log.Logger.Warnf("Delete expired jobs failed %v", err)
+		return
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		log.Logger.Infof("Delete %d expired jobs", n)
+	}
+}
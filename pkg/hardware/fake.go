@@ -0,0 +1,28 @@
+package hardware
+
+import (
+	"context"
+
+	"AIComputingNode/pkg/types"
+)
+
+// FakeCollector reports fixed metrics, it is used in tests and on machines
+// where none of the real collectors work.
+type FakeCollector struct {
+	Metrics types.HostMetrics
+	Err     error
+}
+
+func (fc *FakeCollector) Name() string {
+	return "fake"
+}
+
+func (fc *FakeCollector) Collect(ctx context.Context, metrics *types.HostMetrics) error {
+	if fc.Err != nil {
+		return fc.Err
+	}
+	metrics.Cpu = fc.Metrics.Cpu
+	metrics.Memory = fc.Metrics.Memory
+	metrics.Gpu = append(metrics.Gpu, fc.Metrics.Gpu...)
+	return nil
+}
@@ -1,6 +1,9 @@
 package hardware
 
 import (
+	"context"
+	"time"
+
 	"AIComputingNode/pkg/log"
 	"AIComputingNode/pkg/types"
 
@@ -91,5 +94,10 @@ func GetHostInfo() (*types.HostInfo, error) {
 		}
 	}
 
+	metricsCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	// live metrics are best effort, the failed collectors are already logged
+	hi.Metrics, _ = GetHostMetrics(metricsCtx)
+
 	return hi, reterr
 }
@@ -0,0 +1,91 @@
+package hardware
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/types"
+)
+
+// MetricsCollector fills part of the live host metrics, e.g. cpu and memory
+// from procfs or gpu usage from the vendor tools.
+type MetricsCollector interface {
+	Name() string
+	Collect(ctx context.Context, metrics *types.HostMetrics) error
+}
+
+type metricsRegistry struct {
+	mutex      sync.RWMutex
+	collectors []MetricsCollector
+	latest     *types.HostMetrics
+}
+
+var registry = metricsRegistry{
+	mutex:      sync.RWMutex{},
+	collectors: defaultCollectors(),
+}
+
+func defaultCollectors() []MetricsCollector {
+	collectors := make([]MetricsCollector, 0)
+	if s, err := os.Stat(DefaultProcfsRoot); err == nil && s.IsDir() {
+		collectors = append(collectors, NewProcfsCollector(DefaultProcfsRoot))
+	}
+	if path, err := exec.LookPath("nvidia-smi"); err == nil {
+		collectors = append(collectors, NewNvidiaSmiCollector(path))
+	}
+	return collectors
+}
+
+// SetCollectors replaces the registered collectors, the fake collector can
+// be registered here in tests.
+func SetCollectors(collectors ...MetricsCollector) {
+	registry.mutex.Lock()
+	defer registry.mutex.Unlock()
+	registry.collectors = collectors
+	registry.latest = nil
+}
+
+func GetCollectors() []MetricsCollector {
+	registry.mutex.RLock()
+	defer registry.mutex.RUnlock()
+	res := make([]MetricsCollector, len(registry.collectors))
+	copy(res, registry.collectors)
+	return res
+}
+
+// GetHostMetrics runs every registered collector. A failed collector does not
+// stop the others, the metrics gathered so far are returned with the last error.
+func GetHostMetrics(ctx context.Context) (*types.HostMetrics, error) {
+	var reterr error = nil
+
+	metrics := &types.HostMetrics{
+		Timestamp: time.Now().Unix(),
+	}
+	for _, collector := range GetCollectors() {
+		if err := collector.Collect(ctx, metrics); err != nil {
+			log.Logger.Warnf("Error collecting %s metrics: %v", collector.Name(), err)
+			reterr = err
+		}
+	}
+
+	registry.mutex.Lock()
+	registry.latest = metrics
+	registry.mutex.Unlock()
+	return metrics, reterr
+}
+
+// LatestHostMetrics returns the last collected metrics if they are not older
+// than maxAge, otherwise the collectors are run again.
+func LatestHostMetrics(ctx context.Context, maxAge time.Duration) (*types.HostMetrics, error) {
+	registry.mutex.RLock()
+	latest := registry.latest
+	registry.mutex.RUnlock()
+	if latest != nil && time.Since(time.Unix(latest.Timestamp, 0)) <= maxAge {
+		return latest, nil
+	}
+	return GetHostMetrics(ctx)
+}
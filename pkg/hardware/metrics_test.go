@@ -0,0 +1,114 @@
+package hardware
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/types"
+)
+
+func writeProcfs(t *testing.T, root string, stat string) {
+	files := map[string]string{
+		"stat":    stat,
+		"loadavg": "0.52 0.58 0.59 1/467 3195\n",
+		"meminfo": "MemTotal:       16318420 kB\nMemFree:         1229972 kB\nMemAvailable:    8159210 kB\nBuffers:          361416 kB\nCached:          6539148 kB\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Write %s failed %v", name, err)
+		}
+	}
+}
+
+func TestProcfsCollector(t *testing.T) {
+	root := t.TempDir()
+	writeProcfs(t, root, "cpu  100 0 100 700 100 0 0 0 0 0\ncpu0 100 0 100 700 100 0 0 0 0 0\n")
+
+	pc := NewProcfsCollector(root)
+	pc.SampleInterval = time.Millisecond
+	metrics := &types.HostMetrics{}
+	if err := pc.Collect(context.Background(), metrics); err != nil {
+		t.Fatalf("Collect procfs metrics failed %v", err)
+	}
+	if metrics.Cpu.Load1 != 0.52 || metrics.Cpu.Load15 != 0.59 {
+		t.Errorf("Unexpected load average %+v", metrics.Cpu)
+	}
+	if metrics.Memory.TotalBytes != 16318420*1024 || metrics.Memory.AvailableBytes != 8159210*1024 {
+		t.Errorf("Unexpected memory %+v", metrics.Memory)
+	}
+	if metrics.Memory.UsagePercent() < 49.9 || metrics.Memory.UsagePercent() > 50.1 {
+		t.Errorf("Unexpected memory usage %v", metrics.Memory.UsagePercent())
+	}
+
+	// 800 jiffies passed, 200 of them idle
+	writeProcfs(t, root, "cpu  500 0 300 850 150 0 0 0 0 0\n")
+	if err := pc.Collect(context.Background(), metrics); err != nil {
+		t.Fatalf("Collect procfs metrics failed %v", err)
+	}
+	if metrics.Cpu.UsagePercent != 75 {
+		t.Errorf("Unexpected cpu usage %v", metrics.Cpu.UsagePercent)
+	}
+}
+
+func TestParseNvidiaSmiOutput(t *testing.T) {
+	out := "0, NVIDIA GeForce RTX 4090, 87, 24564, 20110, 71\n1, NVIDIA GeForce RTX 4090, [N/A], 24564, 0, 35\n"
+	gpus, err := ParseNvidiaSmiOutput([]byte(out))
+	if err != nil {
+		t.Fatalf("Parse nvidia-smi output failed %v", err)
+	}
+	if len(gpus) != 2 {
+		t.Fatalf("Expected 2 gpus but got %d", len(gpus))
+	}
+	if gpus[0].Name != "NVIDIA GeForce RTX 4090" || gpus[0].UtilizationPercent != 87 ||
+		gpus[0].MemoryUsedBytes != 20110*1024*1024 || gpus[0].TemperatureCelsius != 71 {
+		t.Errorf("Unexpected gpu metrics %+v", gpus[0])
+	}
+	if gpus[1].Index != 1 || gpus[1].UtilizationPercent != 0 {
+		t.Errorf("Unexpected gpu metrics %+v", gpus[1])
+	}
+
+	if _, err := ParseNvidiaSmiOutput([]byte("0, RTX\n")); err == nil {
+		t.Error("Expected error for truncated record")
+	}
+}
+
+func TestFakeCollector(t *testing.T) {
+	defer SetCollectors(defaultCollectors()...)
+
+	fake := &FakeCollector{
+		Metrics: types.HostMetrics{
+			Cpu:    types.CpuMetrics{UsagePercent: 42},
+			Memory: types.MemMetrics{TotalBytes: 100, UsedBytes: 25, AvailableBytes: 75},
+			Gpu: []types.GpuMetrics{
+				{Index: 0, Name: "fake", UtilizationPercent: 99, MemoryTotalBytes: 80, MemoryUsedBytes: 40},
+			},
+		},
+	}
+	SetCollectors(fake, &FakeCollector{Err: errors.New("broken")})
+
+	metrics, err := GetHostMetrics(context.Background())
+	if err == nil {
+		t.Error("Expected error from the broken collector")
+	}
+	if metrics.Cpu.UsagePercent != 42 || len(metrics.Gpu) != 1 || metrics.Gpu[0].MemoryUsagePercent() != 50 {
+		t.Errorf("Unexpected metrics %+v", metrics)
+	}
+
+	fake.Metrics.Cpu.UsagePercent = 10
+	latest, err := LatestHostMetrics(context.Background(), time.Minute)
+	if err != nil {
+		t.Fatalf("Get latest metrics failed %v", err)
+	}
+	if latest.Cpu.UsagePercent != 42 {
+		t.Errorf("Expected cached metrics but got %+v", latest.Cpu)
+	}
+
+	pm := types.HostMetrics2ProtocolMessage(metrics)
+	if back := types.ProtocolMessage2HostMetrics(pm); back.Gpu[0].Name != "fake" || back.Memory.UsedBytes != 25 {
+		t.Errorf("Unexpected protobuf round trip %+v", back)
+	}
+}
@@ -0,0 +1,84 @@
+package hardware
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"AIComputingNode/pkg/types"
+)
+
+var nvidiaSmiQuery = []string{
+	"--query-gpu=index,name,utilization.gpu,memory.total,memory.used,temperature.gpu",
+	"--format=csv,noheader,nounits",
+}
+
+// NvidiaSmiCollector queries the NVIDIA driver through nvidia-smi, which
+// ships with the driver and wraps NVML.
+type NvidiaSmiCollector struct {
+	Path string
+}
+
+func NewNvidiaSmiCollector(path string) *NvidiaSmiCollector {
+	return &NvidiaSmiCollector{
+		Path: path,
+	}
+}
+
+func (nc *NvidiaSmiCollector) Name() string {
+	return "nvidia-smi"
+}
+
+func (nc *NvidiaSmiCollector) Collect(ctx context.Context, metrics *types.HostMetrics) error {
+	out, err := exec.CommandContext(ctx, nc.Path, nvidiaSmiQuery...).Output()
+	if err != nil {
+		return err
+	}
+	gpus, err := ParseNvidiaSmiOutput(out)
+	if err != nil {
+		return err
+	}
+	metrics.Gpu = append(metrics.Gpu, gpus...)
+	return nil
+}
+
+// ParseNvidiaSmiOutput parses the csv output of nvidia-smi --query-gpu,
+// the memory is reported in MiB and unsupported values as "[N/A]".
+func ParseNvidiaSmiOutput(out []byte) ([]types.GpuMetrics, error) {
+	reader := csv.NewReader(strings.NewReader(string(out)))
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	gpus := make([]types.GpuMetrics, 0, len(records))
+	for _, record := range records {
+		if len(record) < 6 {
+			return nil, fmt.Errorf("invalid nvidia-smi record %v", record)
+		}
+		index, err := strconv.ParseUint(strings.TrimSpace(record[0]), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gpu index %q", record[0])
+		}
+		gpus = append(gpus, types.GpuMetrics{
+			Index:              uint32(index),
+			Name:               strings.TrimSpace(record[1]),
+			UtilizationPercent: parseNvidiaSmiFloat(record[2]),
+			MemoryTotalBytes:   uint64(parseNvidiaSmiFloat(record[3])) * 1024 * 1024,
+			MemoryUsedBytes:    uint64(parseNvidiaSmiFloat(record[4])) * 1024 * 1024,
+			TemperatureCelsius: parseNvidiaSmiFloat(record[5]),
+		})
+	}
+	return gpus, nil
+}
+
+func parseNvidiaSmiFloat(value string) float64 {
+	v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
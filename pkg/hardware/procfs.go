@@ -0,0 +1,174 @@
+package hardware
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"AIComputingNode/pkg/types"
+)
+
+const DefaultProcfsRoot = "/proc"
+
+// ProcfsCollector reads cpu usage, load average and memory usage from the
+// linux procfs, it works without any vendor tools.
+type ProcfsCollector struct {
+	Root string
+	// Sampling interval used when there is no previous cpu sample
+	SampleInterval time.Duration
+
+	mutex sync.Mutex
+	last  *cpuSample
+}
+
+type cpuSample struct {
+	total uint64
+	idle  uint64
+}
+
+func NewProcfsCollector(root string) *ProcfsCollector {
+	return &ProcfsCollector{
+		Root:           root,
+		SampleInterval: 200 * time.Millisecond,
+	}
+}
+
+func (pc *ProcfsCollector) Name() string {
+	return "procfs"
+}
+
+func (pc *ProcfsCollector) Collect(ctx context.Context, metrics *types.HostMetrics) error {
+	usage, err := pc.cpuUsage(ctx)
+	if err != nil {
+		return err
+	}
+	metrics.Cpu.UsagePercent = usage
+
+	load, err := os.ReadFile(filepath.Join(pc.Root, "loadavg"))
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(string(load))
+	if len(fields) < 3 {
+		return fmt.Errorf("invalid loadavg %q", string(load))
+	}
+	metrics.Cpu.Load1, _ = strconv.ParseFloat(fields[0], 64)
+	metrics.Cpu.Load5, _ = strconv.ParseFloat(fields[1], 64)
+	metrics.Cpu.Load15, _ = strconv.ParseFloat(fields[2], 64)
+
+	return pc.memory(metrics)
+}
+
+func (pc *ProcfsCollector) cpuUsage(ctx context.Context) (float64, error) {
+	pc.mutex.Lock()
+	defer pc.mutex.Unlock()
+
+	prev := pc.last
+	if prev == nil {
+		first, err := pc.readCpuSample()
+		if err != nil {
+			return 0, err
+		}
+		prev = first
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(pc.SampleInterval):
+		}
+	}
+	cur, err := pc.readCpuSample()
+	if err != nil {
+		return 0, err
+	}
+	pc.last = cur
+
+	if cur.total <= prev.total {
+		return 0, nil
+	}
+	total := cur.total - prev.total
+	idle := cur.idle - prev.idle
+	if idle > total {
+		return 0, nil
+	}
+	return float64(total-idle) * 100 / float64(total), nil
+}
+
+func (pc *ProcfsCollector) readCpuSample() (*cpuSample, error) {
+	file, err := os.Open(filepath.Join(pc.Root, "stat"))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 || fields[0] != "cpu" {
+			continue
+		}
+		sample := &cpuSample{}
+		for i, field := range fields[1:] {
+			value, err := strconv.ParseUint(field, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid cpu stat %q", field)
+			}
+			sample.total += value
+			// idle and iowait
+			if i == 3 || i == 4 {
+				sample.idle += value
+			}
+		}
+		return sample, nil
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("cpu line not found in stat")
+}
+
+func (pc *ProcfsCollector) memory(metrics *types.HostMetrics) error {
+	file, err := os.Open(filepath.Join(pc.Root, "meminfo"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		// values are reported in kB
+		values[strings.TrimSuffix(fields[0], ":")] = value * 1024
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	total, ok := values["MemTotal"]
+	if !ok {
+		return fmt.Errorf("MemTotal not found in meminfo")
+	}
+	available, ok := values["MemAvailable"]
+	if !ok {
+		available = values["MemFree"] + values["Buffers"] + values["Cached"]
+	}
+	metrics.Memory.TotalBytes = total
+	metrics.Memory.AvailableBytes = available
+	if total > available {
+		metrics.Memory.UsedBytes = total - available
+	}
+	return nil
+}
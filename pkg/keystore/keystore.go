@@ -0,0 +1,250 @@
+// Package keystore stores the private key of the node encrypted by a passphrase,
+// and loads it from the configuration, a key file or an external signer.
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// Version of the encrypted key file format
+	Version = 1
+	KDF     = "scrypt"
+	Cipher  = "aes-256-gcm"
+
+	// PassphraseEnv is the environment variable of the keystore passphrase.
+	PassphraseEnv = "AICN_KEYSTORE_PASSPHRASE"
+)
+
+var (
+	ErrPassphrase = errors.New("wrong passphrase or corrupted key file")
+	ErrNoKey      = errors.New("no private key configured")
+)
+
+// ScryptParams are the parameters of the scrypt key derivation.
+type ScryptParams struct {
+	N    int    `json:"N"`
+	R    int    `json:"R"`
+	P    int    `json:"P"`
+	Salt []byte `json:"Salt"`
+}
+
+// DefaultScryptParams costs about 100ms and 32MB to derive a key.
+var DefaultScryptParams = ScryptParams{N: 1 << 15, R: 8, P: 1}
+
+// EncryptedKey is the JSON content of an encrypted key file.
+type EncryptedKey struct {
+	Version    int          `json:"Version"`
+	PeerID     string       `json:"PeerID"`
+	KDF        string       `json:"KDF"`
+	KDFParams  ScryptParams `json:"KDFParams"`
+	Cipher     string       `json:"Cipher"`
+	Nonce      []byte       `json:"Nonce"`
+	Ciphertext []byte       `json:"Ciphertext"`
+}
+
+func (params ScryptParams) aead(passphrase []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(passphrase, params.Salt, params.N, params.R, params.P, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Encrypt encrypts the private key with the passphrase, the peer id is authenticated with the key.
+func Encrypt(priv crypto.PrivKey, passphrase []byte, params ScryptParams) (*EncryptedKey, error) {
+	if len(passphrase) == 0 {
+		return nil, errors.New("empty passphrase")
+	}
+	id, err := peer.IDFromPrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := crypto.MarshalPrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	params.Salt = make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, params.Salt); err != nil {
+		return nil, err
+	}
+	aead, err := params.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return &EncryptedKey{
+		Version:    Version,
+		PeerID:     id.String(),
+		KDF:        KDF,
+		KDFParams:  params,
+		Cipher:     Cipher,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(id.String())),
+	}, nil
+}
+
+// Decrypt decrypts the private key with the passphrase.
+func (k *EncryptedKey) Decrypt(passphrase []byte) (crypto.PrivKey, error) {
+	if k.Version != Version || k.KDF != KDF || k.Cipher != Cipher {
+		return nil, fmt.Errorf("unsupported key file version %d with %s and %s", k.Version, k.KDF, k.Cipher)
+	}
+	aead, err := k.KDFParams.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(k.Nonce) != aead.NonceSize() {
+		return nil, ErrPassphrase
+	}
+	plaintext, err := aead.Open(nil, k.Nonce, k.Ciphertext, []byte(k.PeerID))
+	if err != nil {
+		return nil, ErrPassphrase
+	}
+	priv, err := crypto.UnmarshalPrivateKey(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	if id, err := peer.IDFromPrivateKey(priv); err != nil || id.String() != k.PeerID {
+		return nil, fmt.Errorf("private key does not match peer id %s", k.PeerID)
+	}
+	return priv, nil
+}
+
+// WriteEncryptedKeyFile writes the private key encrypted by the passphrase to the file.
+func WriteEncryptedKeyFile(path string, priv crypto.PrivKey, passphrase []byte) error {
+	key, err := Encrypt(priv, passphrase, DefaultScryptParams)
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(key, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0600)
+}
+
+// IsEncrypted reports whether the content of the key file is an encrypted key.
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+// ParseKey parses the content of a key file, which is an encrypted key,
+// a protobuf encoded private key like peer.key, or the base64 text of it like the PrivKey configuration.
+// The passphrase is only read for encrypted keys.
+func ParseKey(data []byte, passphrase func() ([]byte, error)) (crypto.PrivKey, error) {
+	if IsEncrypted(data) {
+		var key EncryptedKey
+		if err := json.Unmarshal(data, &key); err != nil {
+			return nil, err
+		}
+		pass, err := passphrase()
+		if err != nil {
+			return nil, err
+		}
+		return key.Decrypt(pass)
+	}
+	if priv, err := crypto.UnmarshalPrivateKey(data); err == nil {
+		return priv, nil
+	}
+	return PrivKeyFromString(strings.TrimSpace(string(data)))
+}
+
+// ReadKeyFile reads the private key from the key file, see ParseKey.
+func ReadKeyFile(path string, passphrase func() ([]byte, error)) (crypto.PrivKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseKey(data, passphrase)
+}
+
+// PrivKeyFromString decodes the base64 private key of the configuration.
+func PrivKeyFromString(pk string) (crypto.PrivKey, error) {
+	privKeyBytes, err := crypto.ConfigDecodeKey(pk)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.UnmarshalPrivateKey(privKeyBytes)
+}
+
+// PrivKeyToString encodes the private key in base64 like the PrivKey configuration.
+func PrivKeyToString(priv crypto.PrivKey) (string, error) {
+	data, err := crypto.MarshalPrivateKey(priv)
+	if err != nil {
+		return "", err
+	}
+	return crypto.ConfigEncodeKey(data), nil
+}
+
+// ReadPassphrase returns a function reading the passphrase from the file,
+// or the PassphraseEnv environment variable if the file is empty.
+func ReadPassphrase(file string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		if file != "" {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("read passphrase file: %v", err)
+			}
+			return bytes.TrimRight(data, "\r\n"), nil
+		}
+		if pass := os.Getenv(PassphraseEnv); pass != "" {
+			return []byte(pass), nil
+		}
+		return nil, fmt.Errorf("passphrase required, set %s or the passphrase file", PassphraseEnv)
+	}
+}
+
+// Source is where the private key of the node is loaded from,
+// exactly one of PrivKey, KeyFile and Signer must be set.
+type Source struct {
+	// Unencrypted private key in base64
+	PrivKey string
+	// Path of the key file, see ParseKey
+	KeyFile string
+	// Path of the passphrase file of an encrypted key file
+	PassphraseFile string
+	// Unix socket of the external signer
+	Signer string
+}
+
+// Load loads the private key from the source.
+func (s Source) Load() (crypto.PrivKey, error) {
+	n := 0
+	for _, v := range []string{s.PrivKey, s.KeyFile, s.Signer} {
+		if v != "" {
+			n++
+		}
+	}
+	switch {
+	case n == 0:
+		return nil, ErrNoKey
+	case n > 1:
+		return nil, errors.New("only one of private key, key file and signer can be set")
+	case s.KeyFile != "":
+		return ReadKeyFile(s.KeyFile, ReadPassphrase(s.PassphraseFile))
+	case s.Signer != "":
+		return NewRemoteKey(s.Signer)
+	default:
+		return PrivKeyFromString(s.PrivKey)
+	}
+}
@@ -0,0 +1,252 @@
+package keystore
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+var testScryptParams = ScryptParams{N: 1 << 10, R: 8, P: 1}
+
+func generateKey(t *testing.T) crypto.PrivKey {
+	priv, _, err := crypto.GenerateKeyPair(crypto.Secp256k1, -1)
+	if err != nil {
+		t.Fatalf("Generate key: %v", err)
+	}
+	return priv
+}
+
+func passphrase(pass string) func() ([]byte, error) {
+	return func() ([]byte, error) { return []byte(pass), nil }
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	priv := generateKey(t)
+	key, err := Encrypt(priv, []byte("secret"), testScryptParams)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	data, _ := json.Marshal(key)
+
+	decrypted, err := ParseKey(data, passphrase("secret"))
+	if err != nil || !decrypted.Equals(priv) {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if _, err := ParseKey(data, passphrase("wrong")); err != ErrPassphrase {
+		t.Errorf("Decrypt with wrong passphrase: %v", err)
+	}
+
+	// the peer id is authenticated
+	other, _ := peer.IDFromPrivateKey(generateKey(t))
+	key.PeerID = other.String()
+	if _, err := key.Decrypt([]byte("secret")); err != ErrPassphrase {
+		t.Errorf("Decrypt with tampered peer id: %v", err)
+	}
+	if _, err := Encrypt(priv, nil, testScryptParams); err == nil {
+		t.Error("Encrypt with empty passphrase succeeded")
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	priv := generateKey(t)
+	raw, _ := crypto.MarshalPrivateKey(priv)
+	text, _ := PrivKeyToString(priv)
+	noPassphrase := func() ([]byte, error) {
+		t.Error("Passphrase read for unencrypted key")
+		return nil, nil
+	}
+	for name, data := range map[string][]byte{"raw": raw, "text": []byte(text + "\n")} {
+		if key, err := ParseKey(data, noPassphrase); err != nil || !key.Equals(priv) {
+			t.Errorf("Parse %s key: %v", name, err)
+		}
+	}
+
+	dir := t.TempDir()
+	t.Setenv(PassphraseEnv, "from env")
+	if err := WriteEncryptedKeyFile(filepath.Join(dir, "key.json"), priv, []byte("from env")); err != nil {
+		t.Fatalf("Write key file: %v", err)
+	}
+	if key, err := (Source{KeyFile: filepath.Join(dir, "key.json")}).Load(); err != nil || !key.Equals(priv) {
+		t.Errorf("Load key file: %v", err)
+	}
+	os.WriteFile(filepath.Join(dir, "pass"), []byte("from env\n"), 0600)
+	if key, err := ReadKeyFile(filepath.Join(dir, "key.json"), ReadPassphrase(filepath.Join(dir, "pass"))); err != nil || !key.Equals(priv) {
+		t.Errorf("Read key file with passphrase file: %v", err)
+	}
+
+	if _, err := (Source{}).Load(); err != ErrNoKey {
+		t.Errorf("Load empty source: %v", err)
+	}
+	if _, err := (Source{PrivKey: text, KeyFile: "key.json"}).Load(); err == nil {
+		t.Error("Load source with both private key and key file succeeded")
+	}
+}
+
+// startSigner serves the key by the external signer until the test finishes.
+func startSigner(t *testing.T, priv crypto.PrivKey) crypto.PrivKey {
+	// unix socket paths are limited to about 100 bytes
+	dir, err := os.MkdirTemp("", "signer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	socket := filepath.Join(dir, "signer.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	go ServeSigner(l, priv)
+
+	remote, err := (Source{Signer: socket}).Load()
+	if err != nil {
+		t.Fatalf("Load remote key: %v", err)
+	}
+	return remote
+}
+
+func TestRemoteKey(t *testing.T) {
+	priv := generateKey(t)
+	remote := startSigner(t, priv)
+	if !remote.GetPublic().Equals(priv.GetPublic()) || !remote.Equals(priv) {
+		t.Error("Unexpected public key of the signer")
+	}
+	sig, err := remote.Sign([]byte("hello"))
+	if err != nil {
+		t.Fatalf("Sign: %v", err)
+	}
+	if ok, _ := priv.GetPublic().Verify([]byte("hello"), sig); !ok {
+		t.Error("Invalid signature of the signer")
+	}
+	raw, _ := priv.Raw()
+	if derived, err := remote.Raw(); err != nil || len(derived) != 32 || bytes.Equal(derived, raw) {
+		t.Errorf("Raw of remote key %x: %v", derived, err)
+	}
+
+	peerKey := generateKey(t)
+	secret, err := SharedSecret(remote, peerKey.GetPublic())
+	if err != nil {
+		t.Fatalf("Shared secret: %v", err)
+	}
+	expected, _ := SharedSecret(peerKey, priv.GetPublic())
+	if !bytes.Equal(secret, expected) {
+		t.Error("Shared secrets do not match")
+	}
+
+	h, err := libp2p.New(libp2p.Identity(remote), libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+	if err != nil {
+		t.Fatalf("New host with remote key: %v", err)
+	}
+	defer h.Close()
+	if id, _ := peer.IDFromPrivateKey(priv); h.ID() != id {
+		t.Errorf("Unexpected host id %s", h.ID())
+	}
+}
+
+func TestRotation(t *testing.T) {
+	previous := generateKey(t)
+	current, _ := peer.IDFromPrivateKey(generateKey(t))
+	rotation, err := SignRotation(previous, current)
+	if err != nil {
+		t.Fatalf("Sign rotation: %v", err)
+	}
+	if err := rotation.Verify(current.String()); err != nil {
+		t.Errorf("Verify rotation: %v", err)
+	}
+	other, _ := peer.IDFromPrivateKey(generateKey(t))
+	if err := rotation.Verify(other.String()); err == nil {
+		t.Error("Verify rotation of another peer id succeeded")
+	}
+	rotation.PreviousPeerID = other.String()
+	if err := rotation.Verify(current.String()); err == nil {
+		t.Error("Verify rotation with mismatched public key succeeded")
+	}
+}
+
+func TestSharedSecret(t *testing.T) {
+	for _, keyType := range []int{crypto.Secp256k1, crypto.Ed25519} {
+		a, _, _ := crypto.GenerateKeyPair(keyType, -1)
+		b, _, _ := crypto.GenerateKeyPair(keyType, -1)
+		ab, err := SharedSecret(a, b.GetPublic())
+		if err != nil {
+			t.Fatalf("SharedSecret %v: %v", keyType, err)
+		}
+		ba, err := SharedSecret(b, a.GetPublic())
+		if err != nil || !bytes.Equal(ab, ba) {
+			t.Errorf("SharedSecret %v is not symmetric: %v", keyType, err)
+		}
+	}
+
+	// the converted X25519 key pair matches
+	priv, pub, _ := crypto.GenerateEd25519Key(nil)
+	xpriv, err := X25519PrivateKey(priv.(*crypto.Ed25519PrivateKey))
+	if err != nil {
+		t.Fatalf("X25519PrivateKey: %v", err)
+	}
+	xpub, err := X25519PublicKey(pub.(*crypto.Ed25519PublicKey))
+	if err != nil || !xpub.Equal(xpriv.PublicKey()) {
+		t.Errorf("X25519PublicKey does not match the private key: %v", err)
+	}
+
+	if _, err := SharedSecret(priv, generateKey(t).GetPublic()); err == nil {
+		t.Error("SharedSecret of ed25519 and secp256k1 keys succeeded")
+	}
+}
+
+func TestKeyTypes(t *testing.T) {
+	for _, keyType := range KeyTypes {
+		priv, err := GenerateKey(keyType)
+		if err != nil {
+			t.Fatalf("GenerateKey %s: %v", keyType, err)
+		}
+		if got := KeyTypeOf(priv); got != keyType {
+			t.Errorf("KeyTypeOf %s = %s", keyType, got)
+		}
+		// the keys of every type can be stored encrypted and signed by the signer
+		key, err := Encrypt(priv, []byte("secret"), testScryptParams)
+		if err != nil {
+			t.Fatalf("Encrypt %s: %v", keyType, err)
+		}
+		if decrypted, err := key.Decrypt([]byte("secret")); err != nil || !decrypted.Equals(priv) {
+			t.Errorf("Decrypt %s: %v", keyType, err)
+		}
+		remote := startSigner(t, priv)
+		sig, err := remote.Sign([]byte("data"))
+		if err != nil {
+			t.Fatalf("Sign %s: %v", keyType, err)
+		}
+		if ok, err := priv.GetPublic().Verify([]byte("data"), sig); !ok || err != nil {
+			t.Errorf("Verify %s: %v", keyType, err)
+		}
+	}
+	if _, err := GenerateKey("dsa"); err == nil {
+		t.Error("GenerateKey dsa succeeded")
+	}
+}
+
+func TestWrapSecret(t *testing.T) {
+	priv, err := GenerateKey(KeyTypeRSA)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	secret := []byte("0123456789abcdef0123456789abcdef")
+	wrapped, err := WrapSecret(priv.GetPublic(), secret)
+	if err != nil {
+		t.Fatalf("WrapSecret: %v", err)
+	}
+	for _, key := range []crypto.PrivKey{priv, startSigner(t, priv)} {
+		if got, err := UnwrapSecret(key, wrapped); err != nil || !bytes.Equal(got, secret) {
+			t.Errorf("UnwrapSecret %T: %v", key, err)
+		}
+	}
+	if _, err := WrapSecret(generateKey(t).GetPublic(), secret); err == nil {
+		t.Error("WrapSecret to secp256k1 key succeeded")
+	}
+}
@@ -0,0 +1,54 @@
+package keystore
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strings"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+)
+
+// Key types of the node identity, secp256k1 is the default and the only one supported by the legacy nodes.
+const (
+	KeyTypeSecp256k1 = "secp256k1"
+	KeyTypeEd25519   = "ed25519"
+	KeyTypeRSA       = "rsa"
+
+	DefaultKeyType = KeyTypeSecp256k1
+	// RSABits is the size of the generated RSA keys
+	RSABits = 2048
+)
+
+// KeyTypes lists the supported key types.
+var KeyTypes = []string{KeyTypeSecp256k1, KeyTypeEd25519, KeyTypeRSA}
+
+// GenerateKey generates a private key of the type, DefaultKeyType if empty.
+func GenerateKey(keyType string) (crypto.PrivKey, error) {
+	var priv crypto.PrivKey
+	var err error
+	switch strings.ToLower(keyType) {
+	case "", KeyTypeSecp256k1:
+		priv, _, err = crypto.GenerateSecp256k1Key(rand.Reader)
+	case KeyTypeEd25519:
+		priv, _, err = crypto.GenerateEd25519Key(rand.Reader)
+	case KeyTypeRSA:
+		priv, _, err = crypto.GenerateRSAKeyPair(RSABits, rand.Reader)
+	default:
+		return nil, fmt.Errorf("unsupported key type %q, must be one of %s", keyType, strings.Join(KeyTypes, ", "))
+	}
+	return priv, err
+}
+
+// KeyTypeOf returns the name of the key type.
+func KeyTypeOf(key crypto.Key) string {
+	switch key.Type() {
+	case crypto.Secp256k1:
+		return KeyTypeSecp256k1
+	case crypto.Ed25519:
+		return KeyTypeEd25519
+	case crypto.RSA:
+		return KeyTypeRSA
+	default:
+		return strings.ToLower(key.Type().String())
+	}
+}
@@ -0,0 +1,69 @@
+package keystore
+
+import (
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// Rotation proves that the previous key of a node handed over to its current peer id,
+// so that the other nodes can drop the records of the previous peer id.
+type Rotation struct {
+	PreviousPeerID string
+	// Protobuf encoded public key of the previous peer id
+	PreviousPubKey []byte
+	// Signature of the previous key over the peer ids
+	Signature []byte
+}
+
+func rotationPayload(previous, current string) []byte {
+	return []byte(fmt.Sprintf("AIComputingNode key rotation %s -> %s", previous, current))
+}
+
+// SignRotation signs the rotation from the previous key to the current peer id.
+func SignRotation(previous crypto.PrivKey, current peer.ID) (*Rotation, error) {
+	previousID, err := peer.IDFromPrivateKey(previous)
+	if err != nil {
+		return nil, err
+	}
+	pub, err := crypto.MarshalPublicKey(previous.GetPublic())
+	if err != nil {
+		return nil, err
+	}
+	sig, err := previous.Sign(rotationPayload(previousID.String(), current.String()))
+	if err != nil {
+		return nil, err
+	}
+	return &Rotation{
+		PreviousPeerID: previousID.String(),
+		PreviousPubKey: pub,
+		Signature:      sig,
+	}, nil
+}
+
+// Verify verifies that the rotation is signed by the previous peer id for the current peer id.
+func (r *Rotation) Verify(current string) error {
+	if r.PreviousPeerID == current {
+		return fmt.Errorf("the previous peer id is the current one")
+	}
+	pub, err := crypto.UnmarshalPublicKey(r.PreviousPubKey)
+	if err != nil {
+		return err
+	}
+	id, err := peer.IDFromPublicKey(pub)
+	if err != nil {
+		return err
+	}
+	if id.String() != r.PreviousPeerID {
+		return fmt.Errorf("public key does not match the previous peer id %s", r.PreviousPeerID)
+	}
+	ok, err := pub.Verify(rotationPayload(r.PreviousPeerID, current), r.Signature)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("invalid rotation signature of %s", r.PreviousPeerID)
+	}
+	return nil
+}
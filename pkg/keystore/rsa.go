@@ -0,0 +1,48 @@
+package keystore
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+)
+
+// rsaLabel is the RSA-OAEP label of the wrapped secrets.
+var rsaLabel = []byte("AIComputingNode wrapped secret")
+
+// WrapSecret encrypts the secret to the RSA public key by RSA-OAEP with SHA-256,
+// RSA keys have no ECDH so the secret of the message encryption is wrapped instead.
+func WrapSecret(pub crypto.PubKey, secret []byte) ([]byte, error) {
+	pubK, ok := pub.(*crypto.RsaPublicKey)
+	if !ok {
+		return nil, errors.New("not rsa public key")
+	}
+	std, err := crypto.PubKeyToStdKey(pubK)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.EncryptOAEP(sha256.New(), rand.Reader, std.(*rsa.PublicKey), secret, rsaLabel)
+}
+
+// secretUnwrapper is a private key unwrapping secrets itself, such as RemoteKey.
+type secretUnwrapper interface {
+	UnwrapSecret(wrapped []byte) ([]byte, error)
+}
+
+// UnwrapSecret decrypts the secret wrapped by WrapSecret with the RSA private key.
+func UnwrapSecret(priv crypto.PrivKey, wrapped []byte) ([]byte, error) {
+	if k, ok := priv.(secretUnwrapper); ok {
+		return k.UnwrapSecret(wrapped)
+	}
+	privK, ok := priv.(*crypto.RsaPrivateKey)
+	if !ok {
+		return nil, errors.New("not rsa private key")
+	}
+	std, err := crypto.PrivKeyToStdKey(privK)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.DecryptOAEP(sha256.New(), nil, std.(*rsa.PrivateKey), wrapped, rsaLabel)
+}
@@ -0,0 +1,236 @@
+package keystore
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/libp2p/go-libp2p/core/crypto"
+	pb "github.com/libp2p/go-libp2p/core/crypto/pb"
+	"golang.org/x/crypto/hkdf"
+)
+
+// The external signer serves one JSON request per line on a unix socket, and answers one JSON response per line.
+// The methods are PublicKey, Sign with Data, SharedSecret with the PublicKey of the remote peer,
+// UnwrapSecret with the Data wrapped to an RSA key, and DerivedSecret which returns a secret derived from the private key.
+type signerRequest struct {
+	Method    string `json:"Method"`
+	Data      []byte `json:"Data,omitempty"`
+	PublicKey []byte `json:"PublicKey,omitempty"`
+}
+
+type signerResponse struct {
+	PublicKey []byte `json:"PublicKey,omitempty"`
+	Signature []byte `json:"Signature,omitempty"`
+	Secret    []byte `json:"Secret,omitempty"`
+	Error     string `json:"Error,omitempty"`
+}
+
+const signerTimeout = 10 * time.Second
+
+// derivedSecretInfo is the HKDF info of the secret derived from the private key by the signer.
+const derivedSecretInfo = "AIComputingNode signer derived secret"
+
+// RemoteKey is a private key held by the external signer, it signs and
+// computes shared secrets by the signer but never exposes the raw key.
+type RemoteKey struct {
+	socket string
+	pub    crypto.PubKey
+	// secret derived from the private key, returned by Raw
+	derived []byte
+}
+
+var _ crypto.PrivKey = (*RemoteKey)(nil)
+
+// NewRemoteKey connects to the external signer at the unix socket and fetches its public key.
+func NewRemoteKey(socket string) (*RemoteKey, error) {
+	k := &RemoteKey{socket: socket}
+	rsp, err := k.call(signerRequest{Method: "PublicKey"})
+	if err != nil {
+		return nil, err
+	}
+	k.pub, err = crypto.UnmarshalPublicKey(rsp.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err = k.call(signerRequest{Method: "DerivedSecret"})
+	if err != nil {
+		return nil, err
+	}
+	k.derived = rsp.Secret
+	return k, nil
+}
+
+func (k *RemoteKey) call(req signerRequest) (*signerResponse, error) {
+	conn, err := net.DialTimeout("unix", k.socket, signerTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("connect signer: %v", err)
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(signerTimeout))
+	if err := json.NewEncoder(conn).Encode(req); err != nil {
+		return nil, fmt.Errorf("send signer request: %v", err)
+	}
+	var rsp signerResponse
+	if err := json.NewDecoder(conn).Decode(&rsp); err != nil {
+		return nil, fmt.Errorf("read signer response: %v", err)
+	}
+	if rsp.Error != "" {
+		return nil, fmt.Errorf("signer: %s", rsp.Error)
+	}
+	return &rsp, nil
+}
+
+func (k *RemoteKey) Sign(data []byte) ([]byte, error) {
+	rsp, err := k.call(signerRequest{Method: "Sign", Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Signature, nil
+}
+
+// SharedSecret computes the ECDH secret with the public key of the remote peer.
+func (k *RemoteKey) SharedSecret(pub crypto.PubKey) ([]byte, error) {
+	data, err := crypto.MarshalPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	rsp, err := k.call(signerRequest{Method: "SharedSecret", PublicKey: data})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Secret, nil
+}
+
+// UnwrapSecret decrypts the secret wrapped to the RSA key of the signer.
+func (k *RemoteKey) UnwrapSecret(wrapped []byte) ([]byte, error) {
+	rsp, err := k.call(signerRequest{Method: "UnwrapSecret", Data: wrapped})
+	if err != nil {
+		return nil, err
+	}
+	return rsp.Secret, nil
+}
+
+func (k *RemoteKey) GetPublic() crypto.PubKey {
+	return k.pub
+}
+
+func (k *RemoteKey) Type() pb.KeyType {
+	return k.pub.Type()
+}
+
+// Raw returns a stable secret derived from the private key instead of the key itself,
+// libp2p only derives the keys of the QUIC stateless resets and tokens from it.
+func (k *RemoteKey) Raw() ([]byte, error) {
+	return k.derived, nil
+}
+
+func (k *RemoteKey) Equals(other crypto.Key) bool {
+	priv, ok := other.(crypto.PrivKey)
+	if !ok {
+		return false
+	}
+	return k.pub.Equals(priv.GetPublic())
+}
+
+// sharedSecretKey is a private key computing shared secrets itself, such as RemoteKey.
+type sharedSecretKey interface {
+	SharedSecret(pub crypto.PubKey) ([]byte, error)
+}
+
+// SharedSecret computes the ECDH secret of the private key and the public key of the remote peer,
+// both secp256k1 keys, or both ed25519 keys converted to X25519.
+func SharedSecret(priv crypto.PrivKey, pub crypto.PubKey) ([]byte, error) {
+	if k, ok := priv.(sharedSecretKey); ok {
+		return k.SharedSecret(pub)
+	}
+	switch privK := priv.(type) {
+	case *crypto.Secp256k1PrivateKey:
+		pubK, ok := pub.(*crypto.Secp256k1PublicKey)
+		if !ok {
+			return nil, errors.New("not secp256k1 public key")
+		}
+		return secp256k1.GenerateSharedSecret((*secp256k1.PrivateKey)(privK), (*secp256k1.PublicKey)(pubK)), nil
+	case *crypto.Ed25519PrivateKey:
+		pubK, ok := pub.(*crypto.Ed25519PublicKey)
+		if !ok {
+			return nil, errors.New("not ed25519 public key")
+		}
+		return ed25519SharedSecret(privK, pubK)
+	default:
+		return nil, fmt.Errorf("unsupported %s private key for shared secret", priv.Type())
+	}
+}
+
+func derivedSecret(priv crypto.PrivKey) ([]byte, error) {
+	raw, err := priv.Raw()
+	if err != nil {
+		return nil, err
+	}
+	secret := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, raw, nil, []byte(derivedSecretInfo)), secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// ServeSigner serves the external signer of the private key on the listener until it is closed.
+func ServeSigner(l net.Listener, priv crypto.PrivKey) error {
+	pub, err := crypto.MarshalPublicKey(priv.GetPublic())
+	if err != nil {
+		return err
+	}
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			return err
+		}
+		go serveSignerConn(conn, priv, pub)
+	}
+}
+
+func serveSignerConn(conn net.Conn, priv crypto.PrivKey, pub []byte) {
+	defer conn.Close()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req signerRequest
+		var rsp signerResponse
+		err := json.Unmarshal(scanner.Bytes(), &req)
+		if err != nil {
+			rsp.Error = err.Error()
+			enc.Encode(rsp)
+			return
+		}
+		switch req.Method {
+		case "PublicKey":
+			rsp.PublicKey = pub
+		case "Sign":
+			rsp.Signature, err = priv.Sign(req.Data)
+		case "DerivedSecret":
+			rsp.Secret, err = derivedSecret(priv)
+		case "UnwrapSecret":
+			rsp.Secret, err = UnwrapSecret(priv, req.Data)
+		case "SharedSecret":
+			var remote crypto.PubKey
+			if remote, err = crypto.UnmarshalPublicKey(req.PublicKey); err == nil {
+				rsp.Secret, err = SharedSecret(priv, remote)
+			}
+		default:
+			err = fmt.Errorf("unknown method %q", req.Method)
+		}
+		if err != nil {
+			rsp.Error = err.Error()
+		}
+		if err := enc.Encode(rsp); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package keystore
+
+import (
+	"crypto/ecdh"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"errors"
+
+	"filippo.io/edwards25519"
+	"github.com/libp2p/go-libp2p/core/crypto"
+)
+
+// X25519PrivateKey converts the ed25519 private key to the X25519 private key of the same identity,
+// the scalar is the first half of the SHA-512 of the seed like RFC 8032, clamped by X25519.
+func X25519PrivateKey(priv *crypto.Ed25519PrivateKey) (*ecdh.PrivateKey, error) {
+	raw, err := priv.Raw()
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid ed25519 private key")
+	}
+	h := sha512.Sum512(raw[:ed25519.SeedSize])
+	return ecdh.X25519().NewPrivateKey(h[:32])
+}
+
+// X25519PublicKey converts the ed25519 public key to the X25519 public key, the birational map
+// from the Edwards point to the Montgomery u-coordinate.
+func X25519PublicKey(pub *crypto.Ed25519PublicKey) (*ecdh.PublicKey, error) {
+	raw, err := pub.Raw()
+	if err != nil {
+		return nil, err
+	}
+	point, err := new(edwards25519.Point).SetBytes(raw)
+	if err != nil {
+		return nil, err
+	}
+	return ecdh.X25519().NewPublicKey(point.BytesMontgomery())
+}
+
+func ed25519SharedSecret(priv *crypto.Ed25519PrivateKey, pub *crypto.Ed25519PublicKey) ([]byte, error) {
+	xpriv, err := X25519PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	xpub, err := X25519PublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return xpriv.ECDH(xpub)
+}
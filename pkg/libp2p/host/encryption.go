@@ -0,0 +1,229 @@
+package host
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"AIComputingNode/pkg/keystore"
+	"AIComputingNode/pkg/protocol"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Schemes of the encrypted message body, carried in the encryption field of the message header.
+const (
+	// EncryptionStatic uses the static ECDH secret of the secp256k1 keys of both peers as the AES-GCM key,
+	// it is the scheme of the legacy nodes which do not set the field.
+	EncryptionStatic uint32 = 0
+	// EncryptionEphemeral uses the ECDH secret of an ephemeral key and the key of the receiver,
+	// derives the AES-GCM key by HKDF-SHA256 and authenticates the message header.
+	// The secp256k1, ed25519 (by X25519) and RSA (by RSA-OAEP) identities are supported.
+	EncryptionEphemeral uint32 = 1
+)
+
+// EncryptionProtocol is advertised by the nodes supporting EncryptionEphemeral,
+// the messages to the other secp256k1 nodes keep EncryptionStatic.
+const EncryptionProtocol = "/aicn/encryption/1.0.0"
+
+const ephemeralInfo = "AIComputingNode message encryption v1"
+
+var (
+	ErrUnsupportedEncryption = errors.New("unsupported encryption scheme")
+	ErrEncryptedBody         = errors.New("invalid encrypted body")
+)
+
+// EncryptionStreamHandler only makes the node advertise EncryptionProtocol by the identify protocol.
+func EncryptionStreamHandler(s network.Stream) {
+	s.Close()
+}
+
+// EncryptMessage encrypts the body of the message to its receiver, and sets the encryption
+// scheme and the public key of this node in the header. The message is unchanged on error.
+func (hio *HostInfo) EncryptMessage(ctx context.Context, msg *protocol.Message) error {
+	id, err := peer.Decode(msg.Header.GetReceiver())
+	if err != nil {
+		return err
+	}
+	pubKey, err := hio.GetPublicKey(ctx, id)
+	if err != nil {
+		return err
+	}
+	nodePubKey, err := MarshalPubKeyFromPrivKey(hio.PrivKey)
+	if err != nil {
+		return err
+	}
+
+	var body []byte
+	scheme := EncryptionStatic
+	if hio.supportsEphemeral(id, pubKey) {
+		scheme = EncryptionEphemeral
+		body, err = ephemeralEncrypt(pubKey, messageAAD(msg, scheme), msg.Body)
+	} else {
+		body, err = staticEncrypt(hio.PrivKey, pubKey, msg.Body)
+	}
+	if err != nil {
+		return err
+	}
+	msg.Body = body
+	msg.Header.NodePubKey = nodePubKey
+	msg.Header.Encryption = scheme
+	return nil
+}
+
+// DecryptMessage returns the decrypted body of the message sent to this node,
+// the body is returned as it is if the message is not encrypted.
+func (hio *HostInfo) DecryptMessage(msg *protocol.Message) ([]byte, error) {
+	if msg.Header.GetNodePubKey() == nil || msg.Body == nil {
+		return msg.Body, nil
+	}
+	switch scheme := msg.Header.GetEncryption(); scheme {
+	case EncryptionStatic:
+		pubKey, err := crypto.UnmarshalPublicKey(msg.Header.GetNodePubKey())
+		if err != nil {
+			return msg.Body, err
+		}
+		return staticDecrypt(hio.PrivKey, pubKey, msg.Body)
+	case EncryptionEphemeral:
+		plaintext, err := ephemeralDecrypt(hio.PrivKey, messageAAD(msg, scheme), msg.Body)
+		if err == nil {
+			if id, err := peer.Decode(msg.Header.GetNodeId()); err == nil {
+				hio.ephemeralPeers.Store(id, struct{}{})
+			}
+		}
+		return plaintext, err
+	default:
+		return msg.Body, fmt.Errorf("%w %d", ErrUnsupportedEncryption, scheme)
+	}
+}
+
+// supportsEphemeral reports whether the messages to the peer use EncryptionEphemeral,
+// the legacy nodes only have secp256k1 keys and do not advertise EncryptionProtocol.
+// EncryptionStatic is impossible unless both nodes have secp256k1 keys.
+func (hio *HostInfo) supportsEphemeral(id peer.ID, pubKey crypto.PubKey) bool {
+	if pubKey.Type() != crypto.Secp256k1 || hio.PrivKey.Type() != crypto.Secp256k1 {
+		return true
+	}
+	if _, ok := hio.ephemeralPeers.Load(id); ok {
+		return true
+	}
+	protos, err := hio.Host.Peerstore().SupportsProtocols(id, EncryptionProtocol)
+	return err == nil && len(protos) > 0
+}
+
+// messageAAD binds the encrypted body to the scheme, request id, type, sender and receiver of the message.
+func messageAAD(msg *protocol.Message, scheme uint32) []byte {
+	aad := binary.BigEndian.AppendUint32(nil, scheme)
+	aad = binary.BigEndian.AppendUint32(aad, uint32(msg.Type))
+	for _, field := range []string{msg.Header.GetId(), msg.Header.GetNodeId(), msg.Header.GetReceiver()} {
+		aad = binary.AppendUvarint(aad, uint64(len(field)))
+		aad = append(aad, field...)
+	}
+	return aad
+}
+
+// ephemeralEncrypt seals the plaintext to the public key of the receiver, the body is the length of the
+// ephemeral key, the ephemeral key, the nonce and the ciphertext. The ephemeral key is the marshaled public key
+// of an ephemeral key pair for ECDH, or the random secret wrapped by RSA-OAEP for the RSA receivers.
+func ephemeralEncrypt(pubKey crypto.PubKey, aad, plaintext []byte) ([]byte, error) {
+	var ephemeralKey, secret []byte
+	var err error
+	switch pubKey.Type() {
+	case crypto.Secp256k1, crypto.Ed25519:
+		var ephemeral crypto.PrivKey
+		if pubKey.Type() == crypto.Secp256k1 {
+			ephemeral, _, err = crypto.GenerateSecp256k1Key(rand.Reader)
+		} else {
+			ephemeral, _, err = crypto.GenerateEd25519Key(rand.Reader)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if ephemeralKey, err = crypto.MarshalPublicKey(ephemeral.GetPublic()); err != nil {
+			return nil, err
+		}
+		secret, err = keystore.SharedSecret(ephemeral, pubKey)
+	case crypto.RSA:
+		secret = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, secret); err != nil {
+			return nil, err
+		}
+		ephemeralKey, err = keystore.WrapSecret(pubKey, secret)
+	default:
+		return nil, fmt.Errorf("%w for %s key", ErrUnsupportedEncryption, pubKey.Type())
+	}
+	if err != nil {
+		return nil, err
+	}
+	aead, err := ephemeralAEAD(secret, ephemeralKey, pubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	body := binary.AppendUvarint(nil, uint64(len(ephemeralKey)))
+	body = append(body, ephemeralKey...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	body = append(body, nonce...)
+	return aead.Seal(body, nonce, plaintext, aad), nil
+}
+
+func ephemeralDecrypt(priv crypto.PrivKey, aad, body []byte) ([]byte, error) {
+	n, l := binary.Uvarint(body)
+	if l <= 0 || n > uint64(len(body)-l) {
+		return nil, ErrEncryptedBody
+	}
+	ephemeralKey := body[l : l+int(n)]
+	body = body[l+int(n):]
+	var secret []byte
+	var err error
+	if priv.Type() == crypto.RSA {
+		secret, err = keystore.UnwrapSecret(priv, ephemeralKey)
+	} else {
+		var pubKey crypto.PubKey
+		if pubKey, err = crypto.UnmarshalPublicKey(ephemeralKey); err != nil {
+			return nil, err
+		}
+		secret, err = keystore.SharedSecret(priv, pubKey)
+	}
+	if err != nil {
+		return nil, err
+	}
+	aead, err := ephemeralAEAD(secret, ephemeralKey, priv.GetPublic())
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < aead.NonceSize() {
+		return nil, ErrEncryptedBody
+	}
+	return aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], aad)
+}
+
+// ephemeralAEAD derives the AES-256-GCM key from the secret, salted by the ephemeral key and the receiver key.
+func ephemeralAEAD(secret, ephemeralKey []byte, receiver crypto.PubKey) (cipher.AEAD, error) {
+	receiverPub, err := crypto.MarshalPublicKey(receiver)
+	if err != nil {
+		return nil, err
+	}
+	salt := append(append([]byte{}, ephemeralKey...), receiverPub...)
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, secret, salt, []byte(ephemeralInfo)), key); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,182 @@
+package host
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"AIComputingNode/pkg/protocol"
+
+	"github.com/libp2p/go-libp2p"
+	"github.com/libp2p/go-libp2p/core/crypto"
+)
+
+func newTestHostInfo(t *testing.T, keyType int) *HostInfo {
+	bits := -1
+	if keyType == crypto.RSA {
+		bits = 2048
+	}
+	priv, _, err := crypto.GenerateKeyPair(keyType, bits)
+	if err != nil {
+		t.Fatalf("generate key pair %v", err)
+	}
+	h, err := libp2p.New(libp2p.Identity(priv), libp2p.NoListenAddrs)
+	if err != nil {
+		t.Fatalf("new host %v", err)
+	}
+	t.Cleanup(func() { h.Close() })
+	return &HostInfo{Host: h, PrivKey: priv}
+}
+
+func newTestMessage(sender, receiver *HostInfo, body []byte) *protocol.Message {
+	return &protocol.Message{
+		Header: &protocol.MessageHeader{
+			Id:       "6a2b1c3e-0f4d-4e8a-9b7c-1d2e3f405162",
+			NodeId:   sender.Host.ID().String(),
+			Receiver: receiver.Host.ID().String(),
+		},
+		Type: protocol.MessageType_CHAT_COMPLETION,
+		Body: body,
+	}
+}
+
+// sendTestMessage encrypts the message by the sender and decrypts it by the receiver.
+func sendTestMessage(t *testing.T, sender, receiver *HostInfo, msg *protocol.Message, tamper func(*protocol.Message)) ([]byte, error) {
+	sender.Host.Peerstore().AddPubKey(receiver.Host.ID(), receiver.PrivKey.GetPublic())
+	if err := sender.EncryptMessage(context.Background(), msg); err != nil {
+		t.Fatalf("encrypt message %v", err)
+	}
+	if tamper != nil {
+		tamper(msg)
+	}
+	return receiver.DecryptMessage(msg)
+}
+
+func TestEncryptMessage(t *testing.T) {
+	plaintext := []byte("hello world")
+
+	tests := []struct {
+		name     string
+		sender   int
+		receiver int
+		legacy   bool
+		scheme   uint32
+	}{
+		{"secp256k1 legacy receiver", crypto.Secp256k1, crypto.Secp256k1, true, EncryptionStatic},
+		{"secp256k1", crypto.Secp256k1, crypto.Secp256k1, false, EncryptionEphemeral},
+		{"ed25519", crypto.Ed25519, crypto.Ed25519, true, EncryptionEphemeral},
+		{"secp256k1 to ed25519", crypto.Secp256k1, crypto.Ed25519, true, EncryptionEphemeral},
+		{"ed25519 to secp256k1", crypto.Ed25519, crypto.Secp256k1, true, EncryptionEphemeral},
+		{"rsa", crypto.RSA, crypto.RSA, true, EncryptionEphemeral},
+		{"secp256k1 to rsa", crypto.Secp256k1, crypto.RSA, true, EncryptionEphemeral},
+		{"rsa to ed25519", crypto.RSA, crypto.Ed25519, true, EncryptionEphemeral},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sender := newTestHostInfo(t, tt.sender)
+			receiver := newTestHostInfo(t, tt.receiver)
+			if !tt.legacy {
+				sender.Host.Peerstore().AddProtocols(receiver.Host.ID(), EncryptionProtocol)
+			}
+			msg := newTestMessage(sender, receiver, plaintext)
+			got, err := sendTestMessage(t, sender, receiver, msg, nil)
+			if err != nil {
+				t.Fatalf("decrypt message %v", err)
+			}
+			if msg.Header.GetEncryption() != tt.scheme {
+				t.Errorf("encryption %d, want %d", msg.Header.GetEncryption(), tt.scheme)
+			}
+			if bytes.Contains(msg.Body, plaintext) {
+				t.Errorf("body is not encrypted")
+			}
+			if !bytes.Equal(got, plaintext) {
+				t.Errorf("decrypted %q, want %q", got, plaintext)
+			}
+		})
+	}
+}
+
+func TestEncryptMessageHeader(t *testing.T) {
+	sender := newTestHostInfo(t, crypto.Ed25519)
+	receiver := newTestHostInfo(t, crypto.Ed25519)
+	other := newTestHostInfo(t, crypto.Ed25519)
+
+	tampers := map[string]func(*protocol.Message){
+		"id":       func(msg *protocol.Message) { msg.Header.Id = "other" },
+		"type":     func(msg *protocol.Message) { msg.Type = protocol.MessageType_IMAGE_GENERATION },
+		"sender":   func(msg *protocol.Message) { msg.Header.NodeId = other.Host.ID().String() },
+		"receiver": func(msg *protocol.Message) { msg.Header.Receiver = other.Host.ID().String() },
+		"scheme":   func(msg *protocol.Message) { msg.Header.Encryption = 2 },
+		"body":     func(msg *protocol.Message) { msg.Body[len(msg.Body)-1] ^= 1 },
+		"short":    func(msg *protocol.Message) { msg.Body = msg.Body[:8] },
+	}
+	for name, tamper := range tampers {
+		t.Run(name, func(t *testing.T) {
+			msg := newTestMessage(sender, receiver, []byte("hello world"))
+			if _, err := sendTestMessage(t, sender, receiver, msg, tamper); err == nil {
+				t.Errorf("tampered %s is decrypted", name)
+			}
+		})
+	}
+
+	t.Run("other receiver", func(t *testing.T) {
+		msg := newTestMessage(sender, receiver, []byte("hello world"))
+		if _, err := sendTestMessage(t, sender, other, msg, nil); err == nil {
+			t.Errorf("decrypted by other node")
+		}
+	})
+}
+
+// TestDecryptLegacyMessage decrypts the messages of the legacy nodes, which do not set the encryption of the header.
+func TestDecryptLegacyMessage(t *testing.T) {
+	sender := newTestHostInfo(t, crypto.Secp256k1)
+	receiver := newTestHostInfo(t, crypto.Secp256k1)
+
+	plaintext := []byte("hello world")
+	body, err := staticEncrypt(sender.PrivKey, receiver.PrivKey.GetPublic(), plaintext)
+	if err != nil {
+		t.Fatalf("encrypt %v", err)
+	}
+	msg := newTestMessage(sender, receiver, body)
+	msg.Header.NodePubKey, _ = MarshalPubKeyFromPrivKey(sender.PrivKey)
+
+	got, err := receiver.DecryptMessage(msg)
+	if err != nil {
+		t.Fatalf("decrypt %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("decrypted %q, want %q", got, plaintext)
+	}
+
+	// The reply uses the legacy scheme until the sender is known to support the ephemeral one
+	reply := newTestMessage(receiver, sender, plaintext)
+	receiver.Host.Peerstore().AddPubKey(sender.Host.ID(), sender.PrivKey.GetPublic())
+	if err := receiver.EncryptMessage(context.Background(), reply); err != nil {
+		t.Fatalf("encrypt reply %v", err)
+	}
+	if reply.Header.GetEncryption() != EncryptionStatic {
+		t.Errorf("reply encryption %d, want %d", reply.Header.GetEncryption(), EncryptionStatic)
+	}
+
+	// The sender upgrades once it learns the protocols of the receiver
+	sender.Host.Peerstore().AddProtocols(receiver.Host.ID(), EncryptionProtocol)
+	msg = newTestMessage(sender, receiver, plaintext)
+	if _, err := sendTestMessage(t, sender, receiver, msg, nil); err != nil {
+		t.Fatalf("decrypt %v", err)
+	}
+	reply = newTestMessage(receiver, sender, plaintext)
+	if err := receiver.EncryptMessage(context.Background(), reply); err != nil {
+		t.Fatalf("encrypt reply %v", err)
+	}
+	if reply.Header.GetEncryption() != EncryptionEphemeral {
+		t.Errorf("reply encryption %d after ephemeral message, want %d", reply.Header.GetEncryption(), EncryptionEphemeral)
+	}
+}
+
+func TestUnencryptedMessage(t *testing.T) {
+	msg := &protocol.Message{Header: &protocol.MessageHeader{NodeId: "a"}, Body: []byte("plain")}
+	got, err := (&HostInfo{}).DecryptMessage(msg)
+	if err != nil || string(got) != "plain" {
+		t.Errorf("unencrypted message %q %v", got, err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/libp2p/go-libp2p/core/crypto"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+	"github.com/multiformats/go-multiaddr"
 
 	ips "github.com/libp2p/go-libp2p/p2p/host/peerstore"
 )
@@ -125,3 +126,20 @@ func TestLatency(t *testing.T) {
 	// 	}
 	// }
 }
+
+// go test -v -timeout 30s -count=1 -run TestRelayOfAddr AIComputingNode/pkg/libp2p/host
+func TestRelayOfAddr(t *testing.T) {
+	relay := "16Uiu2HAmS4CErxrmPryJbbEX2HFQbLK8r8xCA5rmzdSU59rHc9AF"
+	for addr, expected := range map[string]string{
+		"/ip4/8.219.75.114/tcp/6001": "",
+		"/ip4/8.219.75.114/tcp/6001/p2p/" + relay + "/p2p-circuit": relay,
+	} {
+		maddr, err := multiaddr.NewMultiaddr(addr)
+		if err != nil {
+			t.Fatalf("parse multiaddr %v", err)
+		}
+		if got := RelayOfAddr(maddr); got != expected {
+			t.Errorf("relay of %s is %q, expected %q", addr, got, expected)
+		}
+	}
+}
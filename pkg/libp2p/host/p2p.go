@@ -3,6 +3,7 @@ package host
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"AIComputingNode/pkg/types"
@@ -23,8 +24,6 @@ import (
 	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
 )
 
-var Hio *HostInfo
-
 const (
 	connectionManagerTag    = "user-connect"
 	connectionManagerWeight = 100
@@ -41,6 +40,14 @@ type HostInfo struct {
 	Dht   *dht.IpfsDHT
 	RD    *drouting.RoutingDiscovery
 	Topic *pubsub.Topic
+	// Relays used by the nodes behind NAT, to reach the peers without known addresses
+	Relays []peer.AddrInfo
+
+	// ephemeralPeers are the peers which sent EncryptionEphemeral messages, they are answered with it
+	// even if their protocols are unknown, which is common for the peers only reached through pubsub.
+	ephemeralPeers sync.Map
+	// last attempts to upgrade the relayed connections, peer.ID to time.Time
+	upgradeAttempts sync.Map
 }
 
 type SwarmPeerInfo struct {
@@ -56,6 +63,13 @@ type PeerAddrInfo struct {
 	Addrs []string `json:"addrs"`
 }
 
+type PeerConnInfo struct {
+	Peer      string
+	Relay     string
+	Direction string
+	Latency   time.Duration
+}
+
 func (hio *HostInfo) GetIdentifyProtocol() types.IdentifyProtocol {
 	id := types.IdentifyProtocol{
 		ID:              hio.Host.ID().String(),
@@ -208,12 +222,20 @@ func (hio *HostInfo) GetPublicKey(ctx context.Context, p peer.ID) (crypto.PubKey
 	return hio.Dht.GetPublicKey(ctx, p)
 }
 
-func (hio *HostInfo) NewStream(ctx context.Context, nodeId string) (network.Stream, error) {
-	peer, err := peer.Decode(nodeId)
+// AddPublicKey saves the public key of the peer received in a message, such as the RSA keys which
+// can not be extracted from the peer id. The key is ignored if it does not match the peer id.
+func (hio *HostInfo) AddPublicKey(p peer.ID, data []byte) error {
+	if len(data) == 0 || hio.Host.Peerstore().PubKey(p) != nil {
+		return nil
+	}
+	pubKey, err := crypto.UnmarshalPublicKey(data)
 	if err != nil {
-		return nil, err
+		return err
 	}
-	return hio.Host.NewStream(ctx, peer, types.ChatProxyProtocol)
+	if !p.MatchesPublicKey(pubKey) {
+		return fmt.Errorf("public key does not match peer id %s", p)
+	}
+	return hio.Host.Peerstore().AddPubKey(p, pubKey)
 }
 
 func (hio *HostInfo) Connectedness(nodeId string) int {
@@ -232,6 +254,84 @@ func (hio *HostInfo) Latency(nodeId string) time.Duration {
 	return hio.Host.Peerstore().LatencyEWMA(peer)
 }
 
+func (hio *HostInfo) AgentVersion(nodeId string) string {
+	peer, err := peer.Decode(nodeId)
+	if err != nil {
+		return ""
+	}
+	av, err := hio.Host.Peerstore().Get(peer, "AgentVersion")
+	if err != nil {
+		return ""
+	}
+	if version, ok := av.(string); ok {
+		return version
+	}
+	return ""
+}
+
+// Relayed reports whether all the connections to the peer go through a relay.
+func (hio *HostInfo) Relayed(nodeId string) bool {
+	peer, err := peer.Decode(nodeId)
+	if err != nil {
+		return false
+	}
+	conns := hio.Host.Network().ConnsToPeer(peer)
+	for _, c := range conns {
+		if RelayOfAddr(c.RemoteMultiaddr()) == "" {
+			return false
+		}
+	}
+	return len(conns) > 0
+}
+
+// PeerConns lists the connections of this node, the relay is empty for direct connections.
+func (hio *HostInfo) PeerConns() []PeerConnInfo {
+	conns := hio.Host.Network().Conns()
+	infos := make([]PeerConnInfo, len(conns))
+	for i, c := range conns {
+		infos[i] = PeerConnInfo{
+			Peer:      c.RemotePeer().String(),
+			Relay:     RelayOfAddr(c.RemoteMultiaddr()),
+			Direction: c.Stat().Direction.String(),
+			Latency:   hio.Host.Peerstore().LatencyEWMA(c.RemotePeer()),
+		}
+	}
+	return infos
+}
+
+// PeerConnections returns the connections of the node as they are reported in the heartbeats.
+func (hio *HostInfo) PeerConnections() []types.PeerConnection {
+	conns := hio.PeerConns()
+	res := make([]types.PeerConnection, len(conns))
+	for i, conn := range conns {
+		res[i] = types.PeerConnection{
+			NodeID:    conn.Peer,
+			Relay:     conn.Relay,
+			Direction: conn.Direction,
+			Latency:   conn.Latency.Microseconds(),
+		}
+	}
+	return res
+}
+
+// RelayOfAddr returns the relay node id of a circuit address, or empty if the address is not relayed.
+func RelayOfAddr(addr multiaddr.Multiaddr) string {
+	relayAddr, circuit := multiaddr.SplitFunc(addr, func(c multiaddr.Component) bool {
+		return c.Protocol().Code == multiaddr.P_CIRCUIT
+	})
+	if circuit == nil {
+		return ""
+	}
+	if relayAddr == nil {
+		return ""
+	}
+	relay, err := relayAddr.ValueForProtocol(multiaddr.P_P2P)
+	if err != nil {
+		return ""
+	}
+	return relay
+}
+
 func PrivKeyFromString(pk string) (crypto.PrivKey, error) {
 	privKeyBytes, err := crypto.ConfigDecodeKey(pk)
 	if err != nil {
@@ -0,0 +1,139 @@
+package host
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/types"
+
+	"github.com/libp2p/go-libp2p/core/event"
+	libp2phost "github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/multiformats/go-multiaddr"
+)
+
+// RelayLatencyPenalty is added to the latency of the peers only reached through a relay,
+// for the extra hop and the limits of the relayed connections.
+const RelayLatencyPenalty = 100 * time.Millisecond
+
+const (
+	// interval between two attempts to upgrade the relayed connection of a peer to a direct one
+	upgradeInterval = time.Minute
+	upgradeTimeout  = 30 * time.Second
+	// TTL of the circuit addresses through the relays of this node
+	relayAddrTTL = 10 * time.Minute
+)
+
+// CanStream reports whether the peer is connected, directly or through a relay, so that streams can be opened.
+func (hio *HostInfo) CanStream(nodeId string) bool {
+	conn := hio.Connectedness(nodeId)
+	return conn == int(network.Connected) || conn == int(network.Limited)
+}
+
+// StreamLatency is the latency used to rank the peer. The relayed peers are usually not pinged,
+// their latency is estimated as the round trip through the relay, and RelayLatencyPenalty is added.
+func (hio *HostInfo) StreamLatency(nodeId string) time.Duration {
+	latency := hio.Latency(nodeId)
+	if !hio.Relayed(nodeId) {
+		return latency
+	}
+	if latency == 0 {
+		p, _ := peer.Decode(nodeId)
+		for _, c := range hio.Host.Network().ConnsToPeer(p) {
+			if relay, err := peer.Decode(RelayOfAddr(c.RemoteMultiaddr())); err == nil {
+				latency = max(latency, 2*hio.Host.Peerstore().LatencyEWMA(relay))
+			}
+		}
+	}
+	return latency + RelayLatencyPenalty
+}
+
+// NewStream opens a chat proxy stream with the peer.
+func (hio *HostInfo) NewStream(ctx context.Context, nodeId string) (network.Stream, error) {
+	return hio.NewProtocolStream(ctx, nodeId, types.ChatProxyProtocol)
+}
+
+// NewProtocolStream opens a stream of the protocol with the peer, through a relay if the peer
+// can not be reached directly. The relayed connections are upgraded to direct ones in the background.
+func (hio *HostInfo) NewProtocolStream(ctx context.Context, nodeId string, pid protocol.ID) (network.Stream, error) {
+	p, err := peer.Decode(nodeId)
+	if err != nil {
+		return nil, err
+	}
+	if hio.Relayed(nodeId) {
+		hio.upgradeConnection(p)
+	} else if !hio.CanStream(nodeId) {
+		hio.addRelayAddrs(p)
+	}
+	return hio.Host.NewStream(network.WithAllowLimitedConn(ctx, string(pid)), p, pid)
+}
+
+// addRelayAddrs adds the circuit addresses of the peer through the relays of this node,
+// the peers behind NAT reserve slots on the same relays, which are the bootstrap nodes by default.
+// The direct addresses of the peer are still dialed first.
+func (hio *HostInfo) addRelayAddrs(p peer.ID) {
+	for _, relay := range hio.Relays {
+		if relay.ID == p || hio.Host.Network().Connectedness(relay.ID) != network.Connected {
+			continue
+		}
+		addr, err := multiaddr.NewMultiaddr("/p2p/" + relay.ID.String() + "/p2p-circuit")
+		if err != nil {
+			continue
+		}
+		hio.Host.Peerstore().AddAddr(p, addr, relayAddrTTL)
+	}
+}
+
+// upgradeConnection tries to connect the relayed peer directly, by its public addresses or by hole punching,
+// at most once per upgradeInterval. The streams already opened keep using the relayed connection.
+func (hio *HostInfo) upgradeConnection(p peer.ID) {
+	now := time.Now()
+	if last, ok := hio.upgradeAttempts.Load(p); ok && now.Sub(last.(time.Time)) < upgradeInterval {
+		return
+	}
+	hio.upgradeAttempts.Store(p, now)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), upgradeTimeout)
+		defer cancel()
+		ctx = network.WithForceDirectDial(ctx, "upgrade relayed connection")
+		if err := hio.Host.Connect(ctx, peer.AddrInfo{ID: p}); err != nil {
+			log.Logger.Infof("Upgrade relayed connection with %s failed: %v", p, err)
+			return
+		}
+		log.Logger.Infof("Upgraded relayed connection with %s to a direct connection", p)
+	}()
+}
+
+// WatchRelayReservations logs the relay addresses of this node until the context is done,
+// they change when the reservations on the relays are made, refreshed or lost.
+func WatchRelayReservations(ctx context.Context, h libp2phost.Host) {
+	sub, err := h.EventBus().Subscribe(new(event.EvtLocalAddressesUpdated))
+	if err != nil {
+		log.Logger.Warnf("Subscribe local addresses events failed: %v", err)
+		return
+	}
+	defer sub.Close()
+	var relays []string
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sub.Out():
+			current := []string{}
+			for _, addr := range h.Addrs() {
+				if relay := RelayOfAddr(addr); relay != "" && !slices.Contains(current, relay) {
+					current = append(current, relay)
+				}
+			}
+			slices.Sort(current)
+			if !slices.Equal(current, relays) {
+				log.Logger.Infof("Relay reservations changed from %v to %v", relays, current)
+			}
+			relays = current
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package host
+
+import (
+	"bufio"
+	"context"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/types"
+
+	"github.com/libp2p/go-libp2p"
+	libp2phost "github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/client"
+	relayv2 "github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/relay"
+)
+
+func newRelayTestHost(t *testing.T, opts ...libp2p.Option) libp2phost.Host {
+	h, err := libp2p.New(append(opts, libp2p.DisableMetrics())...)
+	if err != nil {
+		t.Fatalf("Create libp2p host: %v", err)
+	}
+	t.Cleanup(func() { h.Close() })
+	return h
+}
+
+func TestRelayedStream(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	relay := newRelayTestHost(t,
+		libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"),
+		libp2p.EnableRelayService(relayv2.WithLimit(&relayv2.RelayLimit{Duration: time.Minute, Data: 1 << 20})),
+		// the relay service only runs on the public nodes
+		libp2p.ForceReachabilityPublic(),
+	)
+	relayInfo := peer.AddrInfo{ID: relay.ID(), Addrs: relay.Addrs()}
+
+	// the worker is behind NAT, it can only be reached through its reservation on the relay
+	worker := newRelayTestHost(t, libp2p.ListenAddrStrings("/p2p-circuit"))
+	worker.SetStreamHandler(types.ChatProxyProtocol, func(s network.Stream) {
+		defer s.Close()
+		line, _ := bufio.NewReader(s).ReadString('\n')
+		s.Write([]byte("echo " + line))
+	})
+	if err := worker.Connect(ctx, relayInfo); err != nil {
+		t.Fatalf("Connect worker to relay: %v", err)
+	}
+	if _, err := client.Reserve(ctx, worker, relayInfo); err != nil {
+		t.Fatalf("Reserve slot on relay: %v", err)
+	}
+
+	input := newRelayTestHost(t, libp2p.ListenAddrStrings("/ip4/127.0.0.1/tcp/0"))
+	if err := input.Connect(ctx, relayInfo); err != nil {
+		t.Fatalf("Connect input to relay: %v", err)
+	}
+	hio := &HostInfo{Host: input, Relays: []peer.AddrInfo{relayInfo}}
+	id := worker.ID().String()
+	if hio.CanStream(id) {
+		t.Error("Stream to an unconnected peer")
+	}
+
+	for i := 0; i < 2; i++ {
+		s, err := hio.NewStream(ctx, id)
+		if err != nil {
+			t.Fatalf("Open relayed stream: %v", err)
+		}
+		s.Write([]byte("hello\n"))
+		s.CloseWrite()
+		reply, err := bufio.NewReader(s).ReadString('\n')
+		s.Close()
+		if err != nil || reply != "echo hello\n" {
+			t.Errorf("Relayed stream replied %q: %v", reply, err)
+		}
+	}
+
+	if !hio.CanStream(id) || !hio.Relayed(id) || hio.Connectedness(id) != int(network.Limited) {
+		t.Errorf("Relayed peer connectedness %d, relayed %v", hio.Connectedness(id), hio.Relayed(id))
+	}
+	if latency := hio.StreamLatency(id); latency < RelayLatencyPenalty {
+		t.Errorf("Latency %v of relayed peer without penalty", latency)
+	}
+	if _, ok := hio.upgradeAttempts.Load(worker.ID()); !ok {
+		t.Error("Relayed connection is not upgraded")
+	}
+}
@@ -1,16 +1,15 @@
 package host
 
 import (
-	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
 	"fmt"
 	"io"
 
-	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"AIComputingNode/pkg/keystore"
+
 	"github.com/libp2p/go-libp2p/core/crypto"
-	"github.com/libp2p/go-libp2p/core/peer"
 )
 
 var (
@@ -18,55 +17,31 @@ var (
 	ErrNotSecp256k1PrivKey = fmt.Errorf("not secp256k1 private key")
 )
 
-func Encrypt(ctx context.Context, peerid string, plaintext []byte) ([]byte, error) {
-	peer, err := peer.Decode(peerid)
-	if err != nil {
-		return plaintext, err
-	}
-	pubKey, err := Hio.GetPublicKey(ctx, peer)
-	if err != nil {
-		return plaintext, err
-	}
-
-	pubK, ok := pubKey.(*crypto.Secp256k1PublicKey)
-	if !ok {
+// staticEncrypt encrypts with the static ECDH secret of the secp256k1 keys of both peers,
+// the scheme of the legacy nodes which is only used with the peers not supporting EncryptionEphemeral.
+func staticEncrypt(priv crypto.PrivKey, pubKey crypto.PubKey, plaintext []byte) ([]byte, error) {
+	if _, ok := pubKey.(*crypto.Secp256k1PublicKey); !ok {
 		return plaintext, ErrNotSecp256k1PubKey
 	}
-	secp256k1PubKey := (*secp256k1.PublicKey)(pubK)
-
-	privK, ok := Hio.PrivKey.(*crypto.Secp256k1PrivateKey)
-	if !ok {
+	if priv.Type() != crypto.Secp256k1 {
 		return plaintext, ErrNotSecp256k1PrivKey
 	}
-	secp256k1PrivKey := (*secp256k1.PrivateKey)(privK)
-
-	sharedKey := secp256k1.GenerateSharedSecret(secp256k1PrivKey, secp256k1PubKey)
-	return gcmEncrypt(sharedKey, plaintext)
-}
-
-func Decrypt(pubKey, plaintext []byte) ([]byte, error) {
-	if pubKey == nil || plaintext == nil {
-		return plaintext, nil
-	}
-	cpub, err := crypto.UnmarshalPublicKey(pubKey)
+	sharedKey, err := keystore.SharedSecret(priv, pubKey)
 	if err != nil {
 		return plaintext, err
 	}
+	return gcmEncrypt(sharedKey, plaintext)
+}
 
-	pubK, ok := cpub.(*crypto.Secp256k1PublicKey)
-	if !ok {
-		return plaintext, ErrNotSecp256k1PubKey
+func staticDecrypt(priv crypto.PrivKey, pubKey crypto.PubKey, ciphertext []byte) ([]byte, error) {
+	if _, ok := pubKey.(*crypto.Secp256k1PublicKey); !ok {
+		return ciphertext, ErrNotSecp256k1PubKey
 	}
-	secp256k1PubKey := (*secp256k1.PublicKey)(pubK)
-
-	privK, ok := Hio.PrivKey.(*crypto.Secp256k1PrivateKey)
-	if !ok {
-		return plaintext, ErrNotSecp256k1PrivKey
+	sharedKey, err := keystore.SharedSecret(priv, pubKey)
+	if err != nil {
+		return ciphertext, err
 	}
-	secp256k1PrivKey := (*secp256k1.PrivateKey)(privK)
-
-	sharedKey := secp256k1.GenerateSharedSecret(secp256k1PrivKey, secp256k1PubKey)
-	return gcmDecrypt(sharedKey, plaintext)
+	return gcmDecrypt(sharedKey, ciphertext)
 }
 
 func gcmEncrypt(key, plaintext []byte) ([]byte, error) {
@@ -2,16 +2,23 @@ package stream
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"io"
 	"net"
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
 	"time"
 
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/libp2p/host"
 	"AIComputingNode/pkg/log"
 	"AIComputingNode/pkg/model"
+	"AIComputingNode/pkg/scheduler"
 	"AIComputingNode/pkg/timer"
 	"AIComputingNode/pkg/types"
 
@@ -19,13 +26,21 @@ import (
 )
 
 type Libp2pStream struct {
+	cfg              *config.Config
+	hio              *host.HostInfo
+	models           *model.ProjectMap
 	pcn              chan<- []byte
+	scheduler        *scheduler.Scheduler
 	DefaultTransport http.RoundTripper
 }
 
-func NewLibp2pStream(publishChan chan<- []byte) *Libp2pStream {
+func NewLibp2pStream(cfg *config.Config, hio *host.HostInfo, models *model.ProjectMap, publishChan chan<- []byte, sched *scheduler.Scheduler) *Libp2pStream {
 	return &Libp2pStream{
-		pcn: publishChan,
+		cfg:       cfg,
+		hio:       hio,
+		models:    models,
+		pcn:       publishChan,
+		scheduler: sched,
 		DefaultTransport: &http.Transport{
 			// Proxy: ProxyFromEnvironment,
 			DialContext: (&net.Dialer{
@@ -138,11 +153,12 @@ func (ls *Libp2pStream) ChatProxyStreamHandler(stream network.Stream) {
 	// 	req.URL.Scheme = "http"
 	// }
 	// req.URL.Host = req.Host
+	path := req.URL.Path
 	queryValues := req.URL.Query()
 	projectName := queryValues.Get("project")
 	modelName := queryValues.Get("model")
 	cid := queryValues.Get("cid")
-	mi, err := model.GetModelInfo(projectName, modelName, cid)
+	mi, err := ls.models.GetModelInfo(projectName, modelName, cid)
 	if err != nil {
 		stream.Reset()
 		log.Logger.Errorf("Get model api interface failed: %v", err)
@@ -168,27 +184,71 @@ func (ls *Libp2pStream) ChatProxyStreamHandler(stream network.Stream) {
 		ctx = context.Background()
 	}
 	timeout := types.ChatCompletionRequestTimeout
-	if mi.Type == 1 {
+	switch mi.Type {
+	case types.ModelTypeImageGeneration:
 		timeout = types.ImageGenerationRequestTimeout
+	case types.ModelTypeAudioTranscription, types.ModelTypeAudioSpeech:
+		timeout = types.AudioRequestTimeout
 	}
 	pctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
-	outreq := req.WithContext(httptrace.WithClientTrace(pctx, NewHttpClientTrace()))
 
 	stream.SetDeadline(time.Now().Add(timeout))
 
-	model.IncRef(projectName, modelName, mi.CID)
-	timer.SendAIProjects(ls.pcn)
+	// Read the whole body first, nothing else is sent by the input node on the stream.
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		stream.Reset()
+		log.Logger.Errorf("Read chat proxy request body failed: %v", err)
+		return
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	// The input node resets the stream when its client goes away, which cancels the request.
+	go func() {
+		buf.ReadByte()
+		cancel()
+	}()
+
+	// The proxy requests share the scheduler of the worker with the pubsub requests.
+	release, err := ls.scheduler.Acquire(pctx, scheduler.Request{
+		ID:        stream.ID(),
+		Type:      path,
+		Requester: stream.Conn().RemotePeer().String(),
+	})
+	if errors.Is(err, scheduler.ErrBusy) || errors.Is(err, scheduler.ErrPreempted) {
+		log.Logger.Warnf("Drop chat proxy request with %s %v", stream.ID(), err)
+		resp := model.NewJSONResponse(http.StatusServiceUnavailable, types.BaseHttpResponse{
+			Code:    int(types.ErrCodeBusy),
+			Message: err.Error(),
+		})
+		if err := resp.Write(stream); err != nil {
+			stream.Reset()
+		}
+		return
+	} else if err != nil {
+		stream.Reset()
+		log.Logger.Warnf("Queued chat proxy request with %s stopped %v", stream.ID(), err)
+		return
+	}
+	defer release()
+	outreq := req.WithContext(httptrace.WithClientTrace(pctx, NewHttpClientTrace()))
+
+	ls.models.IncRef(projectName, modelName, mi.CID)
+	timer.SendAIProjects(ls.pcn, ls.cfg, ls.hio, ls.models)
 	defer func() {
-		model.DecRef(projectName, modelName, mi.CID)
-		timer.SendAIProjects(ls.pcn)
+		ls.models.DecRef(projectName, modelName, mi.CID)
+		timer.SendAIProjects(ls.pcn, ls.cfg, ls.hio, ls.models)
 	}()
 
 	// We now make the request
 	log.Logger.Infof("Making request to %s", req.URL)
-	resp, err := ls.DefaultTransport.RoundTrip(outreq)
+	resp, err := ls.roundTrip(outreq, mi.AIModelConfig, path)
 	if err != nil {
 		stream.Reset()
+		if errors.Is(pctx.Err(), context.Canceled) {
+			log.Logger.Warnf("Chat proxy request with %s canceled by the input node", stream.ID())
+			return
+		}
 		log.Logger.Errorf("RoundTrip chat proxy request failed: %v", err)
 		return
 	}
@@ -196,6 +256,41 @@ func (ls *Libp2pStream) ChatProxyStreamHandler(stream network.Stream) {
 	// resp.Write writes whatever response we obtained for our
 	// request back to the stream.
 	log.Logger.Info("Write roundtrip response into chat proxy stream")
-	resp.Write(stream)
+	if err := resp.Write(stream); err != nil {
+		stream.Reset()
+		log.Logger.Warnf("Write roundtrip response into chat proxy stream failed: %v", err)
+		return
+	}
 	log.Logger.Infof("Chat proxy stream with %s stopped", stream.ID())
 }
+
+// roundTrip translates the chat, image generation and audio requests with the backend of the model,
+// the requests of the default backend and the image edit requests are forwarded as they are.
+func (ls *Libp2pStream) roundTrip(req *http.Request, mc types.AIModelConfig, path string) (*http.Response, error) {
+	if mc.Backend == types.ModelBackendDefault {
+		return ls.DefaultTransport.RoundTrip(req)
+	}
+	switch path {
+	case "/api/v0/chat/completion":
+		var chatReq types.ChatModelRequest
+		if err := json.NewDecoder(req.Body).Decode(&chatReq); err != nil {
+			return nil, err
+		}
+		return model.ChatModelStream(req.Context(), mc, chatReq)
+	case "/api/v0/image/gen":
+		var igReq types.ImageGenModelRequest
+		if err := json.NewDecoder(req.Body).Decode(&igReq); err != nil {
+			return nil, err
+		}
+		return model.NewJSONResponse(http.StatusOK, model.ImageGenerationModel(req.Context(), mc, igReq)), nil
+	case "/api/v0/audio/transcriptions":
+		return model.AudioTranscriptionModel(req.Context(), mc, req.Body, req.Header.Get("Content-Type"))
+	case "/api/v0/audio/speech":
+		var asReq types.AudioSpeechModelRequest
+		if err := json.NewDecoder(req.Body).Decode(&asReq); err != nil {
+			return nil, err
+		}
+		return model.AudioSpeechModel(req.Context(), mc, asReq)
+	}
+	return ls.DefaultTransport.RoundTrip(req)
+}
@@ -0,0 +1,183 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"slices"
+
+	"AIComputingNode/pkg/types"
+)
+
+// audioBackends are the backends whose servers follow the audio APIs of OpenAI, "/v1/audio/transcriptions"
+// and "/v1/audio/speech", the requests are forwarded to them as they are.
+var audioBackends = []string{types.ModelBackendDefault, types.ModelBackendOpenAI, types.ModelBackendVLLM}
+
+type openAISpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat string  `json:"response_format,omitempty"`
+	Speed          float32 `json:"speed,omitempty"`
+}
+
+// MultipartBody copies the files and the values of the form into a new multipart body,
+// and returns it with its content type.
+func MultipartBody(form *multipart.Form) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	// copy file field in form
+	for fieldName, files := range form.File {
+		for _, fileHeader := range files {
+			if err := copyFormFile(writer, fieldName, fileHeader); err != nil {
+				return nil, "", err
+			}
+		}
+	}
+
+	// copy other field in form
+	for fieldName, values := range form.Value {
+		for _, value := range values {
+			if err := writer.WriteField(fieldName, value); err != nil {
+				return nil, "", fmt.Errorf("failed to copy %v field %v", fieldName, err)
+			}
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, "", fmt.Errorf("failed to close multipart writer %v", err)
+	}
+	return body, writer.FormDataContentType(), nil
+}
+
+func copyFormFile(writer *multipart.Writer, fieldName string, fileHeader *multipart.FileHeader) error {
+	file, err := fileHeader.Open()
+	if err != nil {
+		return fmt.Errorf("failed to open %v %v", fieldName, err)
+	}
+	defer file.Close()
+
+	part, err := writer.CreateFormFile(fieldName, fileHeader.Filename)
+	if err != nil {
+		return fmt.Errorf("failed to create form file for %v %v", fieldName, err)
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return fmt.Errorf("failed to copy form file for %v %v", fieldName, err)
+	}
+	return nil
+}
+
+// TranscriptionBody builds the multipart body of the transcription request
+// from the audio file and the other fields of the form.
+func TranscriptionBody(file []byte, filename string, fields map[string]string) (*bytes.Buffer, string, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", filename)
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := part.Write(file); err != nil {
+		return nil, "", err
+	}
+	for name, value := range fields {
+		if err := writer.WriteField(name, value); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return body, writer.FormDataContentType(), nil
+}
+
+// AudioTranscriptionModel posts the multipart body with the audio file to the model,
+// the response is JSON or text according to the response_format field of the form.
+func AudioTranscriptionModel(ctx context.Context, mc types.AIModelConfig, body io.Reader, contentType string) (*http.Response, error) {
+	if mc.API == "" {
+		return nil, errors.New("model API configuration is empty")
+	}
+	if !slices.Contains(audioBackends, mc.Backend) {
+		return errorResponse(types.ErrCodeUnsupported,
+			fmt.Sprintf("Audio transcription is not supported by the %s backend", mc.Backend)), nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, mc.API, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := backendClient.Do(req)
+	return audioResponse(mc, resp, err)
+}
+
+// AudioSpeechModel requests the audio of the text, whose body is streamed by the model.
+func AudioSpeechModel(ctx context.Context, mc types.AIModelConfig, req types.AudioSpeechModelRequest) (*http.Response, error) {
+	if mc.API == "" {
+		return nil, errors.New("model API configuration is empty")
+	}
+	if !slices.Contains(audioBackends, mc.Backend) {
+		return errorResponse(types.ErrCodeUnsupported,
+			fmt.Sprintf("Audio speech is not supported by the %s backend", mc.Backend)), nil
+	}
+	var body any = req
+	if mc.Backend != types.ModelBackendDefault {
+		// the wallet is rejected by the strict servers
+		body = openAISpeechRequest{
+			Model:          req.Model,
+			Input:          req.Input,
+			Voice:          req.Voice,
+			ResponseFormat: req.ResponseFormat,
+			Speed:          req.Speed,
+		}
+	}
+	resp, err := postJSON(ctx, mc.API, body)
+	return audioResponse(mc, resp, err)
+}
+
+// audioResponse translates the errors of the raw OpenAI compatible servers into the JSON envelope,
+// the responses of the default backend are already wrapped.
+func audioResponse(mc types.AIModelConfig, resp *http.Response, err error) (*http.Response, error) {
+	if err != nil {
+		return nil, err
+	}
+	if mc.Backend != types.ModelBackendDefault && resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return errorResponse(types.ErrCodeModel, backendError(resp)), nil
+	}
+	return resp, nil
+}
+
+// ReadAudioResponse reads the whole model response, to be sent in the pubsub messages.
+func ReadAudioResponse(resp *http.Response) *types.AudioResponse {
+	result := &types.AudioResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code: int(types.ErrCodeModel),
+		},
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		result.Message = backendError(resp)
+		return result
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		result.Message = fmt.Sprintf("Read model response error, %v", err)
+		return result
+	}
+	if isJSON(resp.Header) {
+		var envelope types.BaseHttpResponse
+		if err := json.Unmarshal(data, &envelope); err == nil && envelope.Code != 0 {
+			result.Code = envelope.Code
+			result.Message = envelope.Message
+			return result
+		}
+	}
+	result.Code = 0
+	result.ContentType = resp.Header.Get("Content-Type")
+	result.Data = data
+	return result
+}
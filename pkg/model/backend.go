@@ -0,0 +1,237 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"AIComputingNode/pkg/types"
+
+	"github.com/google/uuid"
+)
+
+// Backend translates the requests of this node into the API of a kind of model server,
+// and the responses of the server back into the OpenAI compatible responses of this node.
+// The backend of a model is selected by the Backend field of its configuration.
+type Backend interface {
+	// Chat runs the chat completion without streaming.
+	Chat(ctx context.Context, mc types.AIModelConfig, req types.ChatModelRequest) *types.ChatCompletionResponse
+	// ChatStream runs the chat completion with streaming. The body of the response is the server-sent events
+	// of the OpenAI chat completion chunks, or a JSON BaseHttpResponse when the model fails.
+	ChatStream(ctx context.Context, mc types.AIModelConfig, req types.ChatModelRequest) (*http.Response, error)
+	// ImageGeneration generates the images of the prompt.
+	ImageGeneration(ctx context.Context, mc types.AIModelConfig, req types.ImageGenModelRequest) *types.ImageGenerationResponse
+	// Embedding embeds the batch of texts.
+	Embedding(ctx context.Context, mc types.AIModelConfig, req types.EmbeddingModelRequest) *types.EmbeddingResponse
+}
+
+var backends = map[string]Backend{
+	types.ModelBackendDefault:       defaultBackend{},
+	types.ModelBackendOpenAI:        openAIBackend{streamUsage: true},
+	types.ModelBackendVLLM:          openAIBackend{streamUsage: true},
+	types.ModelBackendTGI:           openAIBackend{exclusiveTopP: true},
+	types.ModelBackendLlamaCpp:      openAIBackend{},
+	types.ModelBackendOllama:        ollamaBackend{},
+	types.ModelBackendComfyUI:       comfyUIBackend{},
+	types.ModelBackendAutomatic1111: automatic1111Backend{},
+}
+
+func GetBackend(name string) (Backend, error) {
+	backend, ok := backends[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown model backend %q", name)
+	}
+	return backend, nil
+}
+
+// the timeouts of the model requests are set by the contexts
+var backendClient = &http.Client{}
+
+func postJSON(ctx context.Context, api string, body any) (*http.Response, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, api, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	return backendClient.Do(req)
+}
+
+func isJSON(header http.Header) bool {
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// backendError reads the error message from the response of the model server,
+// the servers put it in different fields.
+func backendError(resp *http.Response) string {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	var msg struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+		Detail  json.RawMessage `json:"detail"`
+	}
+	if err := json.Unmarshal(body, &msg); err == nil {
+		var nested struct {
+			Message string `json:"message"`
+		}
+		var text string
+		switch {
+		case json.Unmarshal(msg.Error, &nested) == nil && nested.Message != "":
+			return nested.Message
+		case json.Unmarshal(msg.Error, &text) == nil && text != "":
+			return text
+		case msg.Message != "":
+			return msg.Message
+		case json.Unmarshal(msg.Detail, &text) == nil && text != "":
+			return text
+		case len(msg.Detail) > 0:
+			return string(msg.Detail)
+		}
+	}
+	if text := strings.TrimSpace(string(body)); text != "" {
+		return fmt.Sprintf("%s, %s", resp.Status, text)
+	}
+	return resp.Status
+}
+
+// NewJSONResponse creates the HTTP response with the JSON body of v.
+func NewJSONResponse(status int, v any) *http.Response {
+	body, _ := json.Marshal(v)
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+	}
+}
+
+func errorResponse(code types.ErrorCode, message string) *http.Response {
+	return NewJSONResponse(http.StatusOK, types.BaseHttpResponse{
+		Code:    int(code),
+		Message: message,
+	})
+}
+
+// eventStreamResponse creates the HTTP response of the server-sent events written into the pipe.
+func eventStreamResponse(body io.ReadCloser) *http.Response {
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header: http.Header{
+			"Content-Type":  {"text/event-stream"},
+			"Cache-Control": {"no-cache"},
+		},
+		Body:             body,
+		ContentLength:    -1,
+		TransferEncoding: []string{"chunked"},
+	}
+}
+
+// writeEvent writes the chunk as a server-sent event of the OpenAI chat completion stream.
+func writeEvent(w io.Writer, chunk any) error {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "data: %s\n\n", data)
+	return err
+}
+
+func writeDoneEvent(w io.Writer) error {
+	_, err := io.WriteString(w, "data: [DONE]\n\n")
+	return err
+}
+
+func writeErrorEvent(w io.Writer, message string) error {
+	return writeEvent(w, map[string]any{
+		"error": map[string]string{"message": message},
+	})
+}
+
+func newCompletionID() string {
+	return "chatcmpl-" + strings.ReplaceAll(uuid.NewString(), "-", "")
+}
+
+func unsupportedChat(backend string) *types.ChatCompletionResponse {
+	return &types.ChatCompletionResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code:    int(types.ErrCodeUnsupported),
+			Message: fmt.Sprintf("Chat completion is not supported by the %s backend", backend),
+		},
+	}
+}
+
+func unsupportedImageGeneration(backend string) *types.ImageGenerationResponse {
+	return &types.ImageGenerationResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code:    int(types.ErrCodeUnsupported),
+			Message: fmt.Sprintf("Image generation is not supported by the %s backend", backend),
+		},
+	}
+}
+
+func unsupportedEmbedding(backend string) *types.EmbeddingResponse {
+	return &types.EmbeddingResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code:    int(types.ErrCodeUnsupported),
+			Message: fmt.Sprintf("Embedding is not supported by the %s backend", backend),
+		},
+	}
+}
+
+const (
+	defaultImageSize  = 512
+	defaultImageSteps = 20
+)
+
+// imageParams returns the size, steps and number of the images to generate,
+// the size is the width and height of the request, or parsed from the size such as "1024x1024".
+func imageParams(req types.ImageGenModelRequest) (width, height, steps, number int) {
+	width, height = req.Width, req.Height
+	if w, h, ok := strings.Cut(req.Size, "x"); ok && (width <= 0 || height <= 0) {
+		width, _ = strconv.Atoi(w)
+		height, _ = strconv.Atoi(h)
+	}
+	if width <= 0 || height <= 0 {
+		width, height = defaultImageSize, defaultImageSize
+	}
+	steps = int(req.Step)
+	if steps <= 0 {
+		steps = defaultImageSteps
+	}
+	number = max(req.Number, 1)
+	return width, height, steps, number
+}
+
+// imageChoices returns the base64 encoded images, they are stored and returned by URLs if the image store is enabled.
+func imageChoices(images []string) *types.ImageGenerationResponse {
+	res := &types.ImageGenerationResponse{
+		ImageModelResponse: types.ImageModelResponse{
+			Created: time.Now().Unix(),
+		},
+	}
+	for _, image := range images {
+		res.Choices = append(res.Choices, types.ImageResponseChoice{
+			B64Json: image,
+		})
+	}
+	return res
+}
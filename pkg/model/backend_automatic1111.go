@@ -0,0 +1,84 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"AIComputingNode/pkg/types"
+)
+
+// automatic1111Backend serves the text to image API of the Stable Diffusion web UI of AUTOMATIC1111,
+// "/sdapi/v1/txt2img".
+type automatic1111Backend struct{}
+
+type automatic1111Request struct {
+	Prompt    string `json:"prompt"`
+	Width     int    `json:"width"`
+	Height    int    `json:"height"`
+	Steps     int    `json:"steps"`
+	BatchSize int    `json:"batch_size"`
+	// -1 picks a random seed
+	Seed int64 `json:"seed"`
+}
+
+func (automatic1111Backend) ImageGeneration(ctx context.Context, mc types.AIModelConfig, req types.ImageGenModelRequest) *types.ImageGenerationResponse {
+	result := &types.ImageGenerationResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code: int(types.ErrCodeModel),
+		},
+	}
+	width, height, steps, number := imageParams(req)
+	seed := int64(-1)
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+	resp, err := postJSON(ctx, mc.API, automatic1111Request{
+		Prompt:    req.Prompt,
+		Width:     width,
+		Height:    height,
+		Steps:     steps,
+		BatchSize: number,
+		Seed:      seed,
+	})
+	if err != nil {
+		result.Message = fmt.Sprintf("Post HTTP request error, %v", err)
+		return result
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		result.Message = backendError(resp)
+		return result
+	}
+	var response struct {
+		Images []string `json:"images"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		result.Message = "Unmarshal model response error"
+		return result
+	}
+	images := response.Images
+	// the grid of the batch comes first if the web UI is set to return it
+	if len(images) > number {
+		images = images[len(images)-number:]
+	}
+	if len(images) == 0 {
+		result.Message = "No images in model response"
+		return result
+	}
+	return imageChoices(images)
+}
+
+func (automatic1111Backend) Chat(ctx context.Context, mc types.AIModelConfig, req types.ChatModelRequest) *types.ChatCompletionResponse {
+	return unsupportedChat(types.ModelBackendAutomatic1111)
+}
+
+func (automatic1111Backend) ChatStream(ctx context.Context, mc types.AIModelConfig, req types.ChatModelRequest) (*http.Response, error) {
+	res := unsupportedChat(types.ModelBackendAutomatic1111)
+	return errorResponse(types.ErrorCode(res.Code), res.Message), nil
+}
+
+func (automatic1111Backend) Embedding(ctx context.Context, mc types.AIModelConfig, req types.EmbeddingModelRequest) *types.EmbeddingResponse {
+	return unsupportedEmbedding(types.ModelBackendAutomatic1111)
+}
@@ -0,0 +1,246 @@
+package model
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"AIComputingNode/pkg/types"
+
+	"github.com/google/uuid"
+)
+
+// comfyUIBackend runs the workflow with the prompt API of ComfyUI, "/prompt", waits for the workflow
+// in the history of the prompt and downloads the output images.
+type comfyUIBackend struct{}
+
+var comfyUIPollInterval = time.Second
+
+// comfyUIWorkflow is the default text to image workflow in the API format, whose checkpoint is the model name.
+// The strings "{{prompt}}", "{{model}}", "{{width}}", "{{height}}", "{{steps}}", "{{batch_size}}" and "{{seed}}"
+// in the workflows are replaced by the parameters of the requests.
+const comfyUIWorkflow = `{
+  "3": {"class_type": "KSampler", "inputs": {"seed": "{{seed}}", "steps": "{{steps}}", "cfg": 7, "sampler_name": "euler",
+    "scheduler": "normal", "denoise": 1, "model": ["4", 0], "positive": ["6", 0], "negative": ["7", 0], "latent_image": ["5", 0]}},
+  "4": {"class_type": "CheckpointLoaderSimple", "inputs": {"ckpt_name": "{{model}}"}},
+  "5": {"class_type": "EmptyLatentImage", "inputs": {"width": "{{width}}", "height": "{{height}}", "batch_size": "{{batch_size}}"}},
+  "6": {"class_type": "CLIPTextEncode", "inputs": {"text": "{{prompt}}", "clip": ["4", 1]}},
+  "7": {"class_type": "CLIPTextEncode", "inputs": {"text": "", "clip": ["4", 1]}},
+  "8": {"class_type": "VAEDecode", "inputs": {"samples": ["3", 0], "vae": ["4", 2]}},
+  "9": {"class_type": "SaveImage", "inputs": {"filename_prefix": "AIComputingNode", "images": ["8", 0]}}
+}`
+
+type comfyUIImage struct {
+	Filename  string `json:"filename"`
+	Subfolder string `json:"subfolder"`
+	Type      string `json:"type"`
+}
+
+type comfyUIHistory struct {
+	Outputs map[string]struct {
+		Images []comfyUIImage `json:"images"`
+	} `json:"outputs"`
+	Status struct {
+		StatusStr string `json:"status_str"`
+		Completed bool   `json:"completed"`
+	} `json:"status"`
+}
+
+// comfyUIPrompt loads the workflow of the model and fills in the parameters of the request.
+func comfyUIPrompt(mc types.AIModelConfig, req types.ImageGenModelRequest) (any, error) {
+	data := []byte(comfyUIWorkflow)
+	if mc.Workflow != "" {
+		var err error
+		if data, err = os.ReadFile(mc.Workflow); err != nil {
+			return nil, err
+		}
+	}
+	var workflow any
+	if err := json.Unmarshal(data, &workflow); err != nil {
+		return nil, fmt.Errorf("invalid workflow %s: %v", mc.Workflow, err)
+	}
+	width, height, steps, number := imageParams(req)
+	// the integers of JSON are exact below 2^53
+	seed := rand.Int64N(1 << 53)
+	if req.Seed != nil {
+		seed = *req.Seed
+	}
+	values := map[string]any{
+		"{{prompt}}":     req.Prompt,
+		"{{model}}":      mc.Model,
+		"{{width}}":      width,
+		"{{height}}":     height,
+		"{{steps}}":      steps,
+		"{{batch_size}}": number,
+		"{{seed}}":       seed,
+	}
+	return fillWorkflow(workflow, values), nil
+}
+
+// fillWorkflow replaces the strings which are placeholders by the values,
+// and the prompt placeholder inside the longer strings.
+func fillWorkflow(v any, values map[string]any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			v[key] = fillWorkflow(elem, values)
+		}
+	case []any:
+		for i, elem := range v {
+			v[i] = fillWorkflow(elem, values)
+		}
+	case string:
+		if value, ok := values[v]; ok {
+			return value
+		}
+		return strings.ReplaceAll(v, "{{prompt}}", values["{{prompt}}"].(string))
+	}
+	return v
+}
+
+func (comfyUIBackend) ImageGeneration(ctx context.Context, mc types.AIModelConfig, req types.ImageGenModelRequest) *types.ImageGenerationResponse {
+	result := &types.ImageGenerationResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code: int(types.ErrCodeModel),
+		},
+	}
+	workflow, err := comfyUIPrompt(mc, req)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	resp, err := postJSON(ctx, mc.API, map[string]any{
+		"prompt":    workflow,
+		"client_id": uuid.NewString(),
+	})
+	if err != nil {
+		result.Message = fmt.Sprintf("Post HTTP request error, %v", err)
+		return result
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		result.Message = backendError(resp)
+		return result
+	}
+	var queued struct {
+		PromptID string `json:"prompt_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&queued); err != nil || queued.PromptID == "" {
+		result.Message = "Unmarshal model response error"
+		return result
+	}
+
+	base, err := url.Parse(mc.API)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	base.Path = strings.TrimSuffix(base.Path, "/prompt")
+	images, err := comfyUIWaitImages(ctx, base, queued.PromptID)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	return imageChoices(images)
+}
+
+// comfyUIWaitImages polls the history of the prompt until the workflow is executed,
+// and returns the base64 encoded output images.
+func comfyUIWaitImages(ctx context.Context, base *url.URL, promptID string) ([]string, error) {
+	ticker := time.NewTicker(comfyUIPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("wait for the workflow of ComfyUI, %v", ctx.Err())
+		case <-ticker.C:
+		}
+		var histories map[string]comfyUIHistory
+		if err := comfyUIGet(ctx, base.JoinPath("history", promptID).String(), func(r io.Reader) error {
+			return json.NewDecoder(r).Decode(&histories)
+		}); err != nil {
+			return nil, err
+		}
+		history, ok := histories[promptID]
+		if !ok {
+			continue
+		}
+		if history.Status.StatusStr == "error" {
+			return nil, errors.New("ComfyUI failed to execute the workflow")
+		}
+		if !history.Status.Completed {
+			continue
+		}
+
+		// the output nodes are sorted by their ids to keep the order of the images
+		nodes := make([]string, 0, len(history.Outputs))
+		for node := range history.Outputs {
+			nodes = append(nodes, node)
+		}
+		slices.Sort(nodes)
+		images := []string{}
+		for _, node := range nodes {
+			for _, image := range history.Outputs[node].Images {
+				if image.Type != "output" {
+					continue
+				}
+				view := base.JoinPath("view")
+				view.RawQuery = url.Values{
+					"filename":  {image.Filename},
+					"subfolder": {image.Subfolder},
+					"type":      {image.Type},
+				}.Encode()
+				if err := comfyUIGet(ctx, view.String(), func(r io.Reader) error {
+					data, err := io.ReadAll(r)
+					images = append(images, base64.StdEncoding.EncodeToString(data))
+					return err
+				}); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if len(images) == 0 {
+			return nil, errors.New("no output images of the workflow")
+		}
+		return images, nil
+	}
+}
+
+func comfyUIGet(ctx context.Context, api string, read func(io.Reader) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, api, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := backendClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Get HTTP request error, %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.New(backendError(resp))
+	}
+	return read(resp.Body)
+}
+
+func (comfyUIBackend) Chat(ctx context.Context, mc types.AIModelConfig, req types.ChatModelRequest) *types.ChatCompletionResponse {
+	return unsupportedChat(types.ModelBackendComfyUI)
+}
+
+func (comfyUIBackend) ChatStream(ctx context.Context, mc types.AIModelConfig, req types.ChatModelRequest) (*http.Response, error) {
+	res := unsupportedChat(types.ModelBackendComfyUI)
+	return errorResponse(types.ErrorCode(res.Code), res.Message), nil
+}
+
+func (comfyUIBackend) Embedding(ctx context.Context, mc types.AIModelConfig, req types.EmbeddingModelRequest) *types.EmbeddingResponse {
+	return unsupportedEmbedding(types.ModelBackendComfyUI)
+}
@@ -0,0 +1,375 @@
+package model
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"AIComputingNode/pkg/types"
+
+	"github.com/google/uuid"
+)
+
+// ollamaBackend serves the native chat API of Ollama, "/api/chat", which streams the messages
+// in the lines of JSON objects, and the embedding API "/api/embed".
+type ollamaBackend struct{}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name string `json:"name"`
+		// Ollama uses the JSON object instead of the string
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	Images    []string         `json:"images,omitempty"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+}
+
+type ollamaOptions struct {
+	Temperature      *float32 `json:"temperature,omitempty"`
+	TopP             *float32 `json:"top_p,omitempty"`
+	Seed             *int64   `json:"seed,omitempty"`
+	NumPredict       int      `json:"num_predict,omitempty"`
+	Stop             []string `json:"stop,omitempty"`
+	FrequencyPenalty float32  `json:"frequency_penalty,omitempty"`
+	PresencePenalty  float32  `json:"presence_penalty,omitempty"`
+}
+
+type ollamaChatRequest struct {
+	Model    string          `json:"model"`
+	Messages []ollamaMessage `json:"messages"`
+	// Ollama streams by default
+	Stream  bool             `json:"stream"`
+	Options *ollamaOptions   `json:"options,omitempty"`
+	Tools   []types.ChatTool `json:"tools,omitempty"`
+	// "json" or the JSON schema
+	Format json.RawMessage `json:"format,omitempty"`
+}
+
+type ollamaChatResponse struct {
+	Model           string        `json:"model"`
+	CreatedAt       time.Time     `json:"created_at"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason"`
+	PromptEvalCount int           `json:"prompt_eval_count"`
+	EvalCount       int           `json:"eval_count"`
+	Error           string        `json:"error"`
+}
+
+// ollamaMessages translates the messages, the texts of the content parts are joined
+// and the images must be base64 data URLs.
+func ollamaMessages(messages []types.ChatCompletionMessage) ([]ollamaMessage, error) {
+	result := make([]ollamaMessage, 0, len(messages))
+	for _, ccm := range messages {
+		msg := ollamaMessage{Role: ccm.Role}
+		for _, call := range ccm.ToolCalls {
+			tc := ollamaToolCall{}
+			tc.Function.Name = call.Function.Name
+			tc.Function.Arguments = json.RawMessage(call.Function.Arguments)
+			if !json.Valid(tc.Function.Arguments) {
+				return nil, fmt.Errorf("invalid arguments of tool call %s", call.ID)
+			}
+			msg.ToolCalls = append(msg.ToolCalls, tc)
+		}
+		if len(ccm.Content) == 0 {
+			result = append(result, msg)
+			continue
+		}
+		if err := json.Unmarshal(ccm.Content, &msg.Content); err != nil {
+			parts := []types.ChatDynamicContentPart{}
+			if err := json.Unmarshal(ccm.Content, &parts); err != nil {
+				return nil, fmt.Errorf("invalid content of %s message", ccm.Role)
+			}
+			texts := []string{}
+			for _, part := range parts {
+				switch part.Type {
+				case "text":
+					texts = append(texts, part.Text)
+				case "image_url":
+					var data string
+					ok := part.ImageUrl != nil && strings.HasPrefix(part.ImageUrl.Url, "data:")
+					if ok {
+						_, data, ok = strings.Cut(part.ImageUrl.Url, ";base64,")
+					}
+					if !ok {
+						return nil, errors.New("only the base64 data URLs of images are supported by Ollama")
+					}
+					msg.Images = append(msg.Images, data)
+				default:
+					return nil, fmt.Errorf("content part %s is not supported by Ollama", part.Type)
+				}
+			}
+			msg.Content = strings.Join(texts, "\n")
+		}
+		result = append(result, msg)
+	}
+	return result, nil
+}
+
+func ollamaRequest(req types.ChatModelRequest, stream bool) (ollamaChatRequest, error) {
+	messages, err := ollamaMessages(req.Messages)
+	if err != nil {
+		return ollamaChatRequest{}, err
+	}
+	chatReq := ollamaChatRequest{
+		Model:    req.Model,
+		Messages: messages,
+		Stream:   stream,
+		Tools:    req.Tools,
+	}
+	options := ollamaOptions{
+		Temperature:      req.Temperature,
+		TopP:             req.TopP,
+		Seed:             req.Seed,
+		NumPredict:       req.MaxTokens,
+		Stop:             req.Stop,
+		FrequencyPenalty: req.FrequencyPenalty,
+		PresencePenalty:  req.PresencePenalty,
+	}
+	if req.MaxCompletionTokens != 0 {
+		options.NumPredict = req.MaxCompletionTokens
+	}
+	if !reflect.ValueOf(options).IsZero() {
+		chatReq.Options = &options
+	}
+	if format := req.ResponseFormat; format != nil {
+		switch format.Type {
+		case "json_object":
+			chatReq.Format = json.RawMessage(`"json"`)
+		case "json_schema":
+			chatReq.Format = format.JSONSchema.Schema
+		}
+	}
+	return chatReq, nil
+}
+
+// ollamaToolCalls translates the tool calls of the response, the index is only set in the stream chunks
+// and counted from the calls of the previous chunks.
+func ollamaToolCalls(calls []ollamaToolCall, stream bool, previous int) []types.ChatToolCall {
+	var result []types.ChatToolCall
+	for i, call := range calls {
+		tc := types.ChatToolCall{
+			ID:   "call_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:24],
+			Type: "function",
+			Function: types.ChatToolCallFunction{
+				Name:      call.Function.Name,
+				Arguments: string(call.Function.Arguments),
+			},
+		}
+		if stream {
+			index := previous + i
+			tc.Index = &index
+		}
+		result = append(result, tc)
+	}
+	return result
+}
+
+func (res ollamaChatResponse) usage() types.ChatResponseUsage {
+	return types.ChatResponseUsage{
+		CompletionTokens: res.EvalCount,
+		PromptTokens:     res.PromptEvalCount,
+		TotalTokens:      res.PromptEvalCount + res.EvalCount,
+	}
+}
+
+func (res ollamaChatResponse) finishReason() string {
+	if !res.Done {
+		return ""
+	}
+	if len(res.Message.ToolCalls) > 0 {
+		return "tool_calls"
+	}
+	if res.DoneReason == "" || res.DoneReason == "unload" {
+		return "stop"
+	}
+	return res.DoneReason
+}
+
+func (ollamaBackend) Chat(ctx context.Context, mc types.AIModelConfig, req types.ChatModelRequest) *types.ChatCompletionResponse {
+	result := &types.ChatCompletionResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code: int(types.ErrCodeModel),
+		},
+	}
+	chatReq, err := ollamaRequest(req, false)
+	if err != nil {
+		result.Message = err.Error()
+		return result
+	}
+	resp, err := postJSON(ctx, mc.API, chatReq)
+	if err != nil {
+		result.Message = fmt.Sprintf("Post HTTP request error, %v", err)
+		return result
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		result.Message = backendError(resp)
+		return result
+	}
+	var chatRes ollamaChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatRes); err != nil {
+		result.Message = "Unmarshal model response error"
+		return result
+	}
+	result.Code = 0
+	result.ChatModelResponseData = types.ChatModelResponseData{
+		Id:      newCompletionID(),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Choices: []types.ChatResponseChoice{{
+			Message: types.ChatCompletionResponseMessage{
+				Role:      chatRes.Message.Role,
+				Content:   chatRes.Message.Content,
+				ToolCalls: ollamaToolCalls(chatRes.Message.ToolCalls, false, 0),
+			},
+			FinishReason: chatRes.finishReason(),
+		}},
+		Usage: chatRes.usage(),
+	}
+	return result
+}
+
+func (ollamaBackend) ChatStream(ctx context.Context, mc types.AIModelConfig, req types.ChatModelRequest) (*http.Response, error) {
+	chatReq, err := ollamaRequest(req, true)
+	if err != nil {
+		return errorResponse(types.ErrCodeModel, err.Error()), nil
+	}
+	resp, err := postJSON(ctx, mc.API, chatReq)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return errorResponse(types.ErrCodeModel, backendError(resp)), nil
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		defer resp.Body.Close()
+		pw.CloseWithError(translateOllamaStream(resp.Body, pw))
+	}()
+	return eventStreamResponse(pr), nil
+}
+
+// translateOllamaStream translates the lines of the Ollama messages into the OpenAI chat completion chunks.
+func translateOllamaStream(r io.Reader, w io.Writer) error {
+	id := newCompletionID()
+	created := time.Now().Unix()
+	toolCalls := 0
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var chunk ollamaChatResponse
+		if err := json.Unmarshal(scanner.Bytes(), &chunk); err != nil {
+			return writeErrorEvent(w, "Unmarshal model response error")
+		}
+		if chunk.Error != "" {
+			return writeErrorEvent(w, chunk.Error)
+		}
+		data := types.StreamChatModelResponseData{
+			Id:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Choices: []types.StreamChatResponseChoice{{
+				Delta: types.ChatCompletionResponseMessage{
+					Role:      chunk.Message.Role,
+					Content:   chunk.Message.Content,
+					ToolCalls: ollamaToolCalls(chunk.Message.ToolCalls, true, toolCalls),
+				},
+				FinishReason: chunk.finishReason(),
+			}},
+		}
+		toolCalls += len(chunk.Message.ToolCalls)
+		if chunk.Done && toolCalls > 0 {
+			data.Choices[0].FinishReason = "tool_calls"
+		}
+		if chunk.Done {
+			data.Usage = chunk.usage()
+		}
+		if err := writeEvent(w, data); err != nil {
+			return err
+		}
+		if chunk.Done {
+			return writeDoneEvent(w)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return writeErrorEvent(w, err.Error())
+	}
+	return writeDoneEvent(w)
+}
+
+func (ollamaBackend) ImageGeneration(ctx context.Context, mc types.AIModelConfig, req types.ImageGenModelRequest) *types.ImageGenerationResponse {
+	return unsupportedImageGeneration(types.ModelBackendOllama)
+}
+
+type ollamaEmbedRequest struct {
+	Model      string   `json:"model"`
+	Input      []string `json:"input"`
+	Dimensions int      `json:"dimensions,omitempty"`
+}
+
+type ollamaEmbedResponse struct {
+	Model           string      `json:"model"`
+	Embeddings      [][]float32 `json:"embeddings"`
+	PromptEvalCount int         `json:"prompt_eval_count"`
+}
+
+func (ollamaBackend) Embedding(ctx context.Context, mc types.AIModelConfig, req types.EmbeddingModelRequest) *types.EmbeddingResponse {
+	result := &types.EmbeddingResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code: int(types.ErrCodeModel),
+		},
+	}
+	resp, err := postJSON(ctx, mc.API, ollamaEmbedRequest{
+		Model:      req.Model,
+		Input:      req.Input,
+		Dimensions: req.Dimensions,
+	})
+	if err != nil {
+		result.Message = fmt.Sprintf("Post HTTP request error, %v", err)
+		return result
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		result.Message = backendError(resp)
+		return result
+	}
+	var embedRes ollamaEmbedResponse
+	if err := json.NewDecoder(resp.Body).Decode(&embedRes); err != nil {
+		result.Message = "Unmarshal model response error"
+		return result
+	}
+	result.Code = 0
+	result.EmbeddingModelResponse = types.EmbeddingModelResponse{
+		Object: "list",
+		Model:  embedRes.Model,
+		Usage: types.EmbeddingUsage{
+			PromptTokens: embedRes.PromptEvalCount,
+			TotalTokens:  embedRes.PromptEvalCount,
+		},
+	}
+	for i, embedding := range embedRes.Embeddings {
+		result.Data = append(result.Data, types.EmbeddingData{
+			Object:    "embedding",
+			Index:     i,
+			Embedding: embedding,
+		})
+	}
+	return result
+}
@@ -0,0 +1,157 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"AIComputingNode/pkg/types"
+)
+
+// openAIBackend serves the raw OpenAI API, and the OpenAI compatible servers of vLLM, TGI and llama.cpp,
+// whose API is "/v1/chat/completions", "/v1/images/generations" or "/v1/embeddings".
+type openAIBackend struct {
+	// request the usage in the last chunk of the stream
+	streamUsage bool
+	// top_p must be less than 1
+	exclusiveTopP bool
+}
+
+type openAIChatRequest struct {
+	Model    string                        `json:"model"`
+	Messages []types.ChatCompletionMessage `json:"messages"`
+	Stream   bool                          `json:"stream"`
+	types.ChatOptions
+}
+
+type openAIEmbeddingRequest struct {
+	Model          string   `json:"model"`
+	Input          []string `json:"input"`
+	EncodingFormat string   `json:"encoding_format"`
+	Dimensions     int      `json:"dimensions,omitempty"`
+}
+
+type openAIImageRequest struct {
+	Model          string `json:"model,omitempty"`
+	Prompt         string `json:"prompt"`
+	Number         int    `json:"n,omitempty"`
+	Size           string `json:"size,omitempty"`
+	ResponseFormat string `json:"response_format,omitempty"`
+}
+
+// chatRequest leaves out the wallet of the request, which is rejected by the strict servers.
+func (b openAIBackend) chatRequest(req types.ChatModelRequest, stream bool) openAIChatRequest {
+	chatReq := openAIChatRequest{
+		Model:       req.Model,
+		Messages:    req.Messages,
+		Stream:      stream,
+		ChatOptions: req.ChatOptions,
+	}
+	if !stream {
+		// stream_options is rejected without stream
+		chatReq.StreamOptions = nil
+	} else if b.streamUsage {
+		chatReq.StreamOptions = &types.ChatStreamOptions{IncludeUsage: true}
+	}
+	if b.exclusiveTopP && chatReq.TopP != nil && *chatReq.TopP >= 1 {
+		chatReq.TopP = nil
+	}
+	return chatReq
+}
+
+func (b openAIBackend) Chat(ctx context.Context, mc types.AIModelConfig, req types.ChatModelRequest) *types.ChatCompletionResponse {
+	result := &types.ChatCompletionResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code: int(types.ErrCodeModel),
+		},
+	}
+	resp, err := postJSON(ctx, mc.API, b.chatRequest(req, false))
+	if err != nil {
+		result.Message = fmt.Sprintf("Post HTTP request error, %v", err)
+		return result
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		result.Message = backendError(resp)
+		return result
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result.ChatModelResponseData); err != nil {
+		result.Message = "Unmarshal model response error"
+		return result
+	}
+	result.Code = 0
+	return result
+}
+
+func (b openAIBackend) ChatStream(ctx context.Context, mc types.AIModelConfig, req types.ChatModelRequest) (*http.Response, error) {
+	resp, err := postJSON(ctx, mc.API, b.chatRequest(req, true))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		return errorResponse(types.ErrCodeModel, backendError(resp)), nil
+	}
+	// the chunks are the same
+	return resp, nil
+}
+
+func (b openAIBackend) ImageGeneration(ctx context.Context, mc types.AIModelConfig, req types.ImageGenModelRequest) *types.ImageGenerationResponse {
+	result := &types.ImageGenerationResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code: int(types.ErrCodeModel),
+		},
+	}
+	resp, err := postJSON(ctx, mc.API, openAIImageRequest{
+		Model:          req.Model,
+		Prompt:         req.Prompt,
+		Number:         req.Number,
+		Size:           req.Size,
+		ResponseFormat: req.ResponseFormat,
+	})
+	if err != nil {
+		result.Message = fmt.Sprintf("Post HTTP request error, %v", err)
+		return result
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		result.Message = backendError(resp)
+		return result
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result.ImageModelResponse); err != nil {
+		result.Message = "Unmarshal model response error"
+		return result
+	}
+	result.Code = 0
+	return result
+}
+
+func (b openAIBackend) Embedding(ctx context.Context, mc types.AIModelConfig, req types.EmbeddingModelRequest) *types.EmbeddingResponse {
+	result := &types.EmbeddingResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code: int(types.ErrCodeModel),
+		},
+	}
+	resp, err := postJSON(ctx, mc.API, openAIEmbeddingRequest{
+		Model:          req.Model,
+		Input:          req.Input,
+		EncodingFormat: "float",
+		Dimensions:     req.Dimensions,
+	})
+	if err != nil {
+		result.Message = fmt.Sprintf("Post HTTP request error, %v", err)
+		return result
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		result.Message = backendError(resp)
+		return result
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result.EmbeddingModelResponse); err != nil {
+		result.Message = "Unmarshal model response error"
+		return result
+	}
+	result.Code = 0
+	return result
+}
@@ -0,0 +1,372 @@
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/types"
+)
+
+func newChatRequest(stream bool) types.ChatModelRequest {
+	return types.ChatModelRequest{
+		Model: "llama3",
+		Messages: []types.ChatCompletionMessage{
+			{Role: "system", Content: []byte(`"You are a helpful assistant."`)},
+			{Role: "user", Content: []byte(`[{"type":"text","text":"Hello"},{"type":"image_url","image_url":{"url":"data:image/png;base64,aW1hZ2U="}}]`)},
+		},
+		Stream:             stream,
+		WalletVerification: types.WalletVerification{Wallet: "wallet"},
+	}
+}
+
+func readAll(t *testing.T, resp *http.Response) string {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("Read response: %v", err)
+	}
+	return string(body)
+}
+
+func TestOpenAIBackend(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = nil
+		json.NewDecoder(r.Body).Decode(&received)
+		switch {
+		case r.URL.Path == "/v1/images/generations":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"created":1,"data":[{"url":"http://images/bird.png"}]}`)
+		case r.URL.Path == "/v1/embeddings":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"object":"list","model":"bge-m3","data":[{"object":"embedding","index":0,"embedding":[0.1,0.2]},{"object":"embedding","index":1,"embedding":[0.3,0.4]}],"usage":{"prompt_tokens":4,"total_tokens":4}}`)
+		case received["model"] != "llama3":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":{"message":"model not found","type":"invalid_request_error"}}`)
+		case received["stream"] == true:
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\ndata: [DONE]\n\n")
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"id":"chatcmpl-1","object":"chat.completion","choices":[{"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],"usage":{"total_tokens":3}}`)
+		}
+	}))
+	defer server.Close()
+	mc := types.AIModelConfig{Model: "llama3", API: server.URL + "/v1/chat/completions", Backend: types.ModelBackendOpenAI}
+	ctx := context.Background()
+
+	res := ChatModel(ctx, mc, newChatRequest(false))
+	if res.Code != 0 || len(res.Choices) != 1 || res.Choices[0].Message.Content != "Hi" || res.Usage.TotalTokens != 3 {
+		t.Errorf("Chat with OpenAI backend: %+v", res)
+	}
+	if _, ok := received["wallet"]; ok {
+		t.Error("Wallet is sent to OpenAI backend")
+	}
+
+	temperature := float32(0)
+	req := newChatRequest(false)
+	req.Temperature = &temperature
+	req.StreamOptions = &types.ChatStreamOptions{IncludeUsage: true}
+	req.Tools = []types.ChatTool{{Type: "function", Function: types.ChatFunctionDefinition{Name: "get_weather"}}}
+	req.ToolChoice = json.RawMessage(`"required"`)
+	ChatModel(ctx, mc, req)
+	if received["temperature"] != 0.0 || received["tool_choice"] != "required" || len(received["tools"].([]any)) != 1 {
+		t.Errorf("OpenAI chat options: %v", received)
+	}
+	if _, ok := received["stream_options"]; ok {
+		t.Errorf("Stream options without stream: %v", received)
+	}
+
+	resp, err := ChatModelStream(ctx, mc, newChatRequest(true))
+	if err != nil {
+		t.Fatalf("Stream chat with OpenAI backend: %v", err)
+	}
+	if body := readAll(t, resp); !strings.HasSuffix(body, "data: [DONE]\n\n") {
+		t.Errorf("Stream of OpenAI backend: %q", body)
+	}
+	if options, _ := received["stream_options"].(map[string]any); options["include_usage"] != true {
+		t.Errorf("Stream usage is not requested: %v", received)
+	}
+
+	req = newChatRequest(true)
+	req.Model = "unknown"
+	resp, err = ChatModelStream(ctx, mc, req)
+	if err != nil {
+		t.Fatalf("Stream chat with OpenAI backend: %v", err)
+	}
+	var errRes types.BaseHttpResponse
+	if err := json.Unmarshal([]byte(readAll(t, resp)), &errRes); err != nil || errRes.Code != int(types.ErrCodeModel) || errRes.Message != "model not found" {
+		t.Errorf("Stream error of OpenAI backend: %+v, %v", errRes, err)
+	}
+
+	mc.API = server.URL + "/v1/images/generations"
+	igRes := ImageGenerationModel(ctx, mc, types.ImageGenModelRequest{Model: "dall-e", Prompt: "bird", Number: 1})
+	if igRes.Code != 0 || len(igRes.Choices) != 1 || igRes.Choices[0].Url != "http://images/bird.png" {
+		t.Errorf("Image generation with OpenAI backend: %+v", igRes)
+	}
+
+	mc.API = server.URL + "/v1/embeddings"
+	ebRes := EmbeddingModel(ctx, mc, types.EmbeddingModelRequest{Model: "bge-m3", Input: types.EmbeddingInput{"cat", "bird"}})
+	if ebRes.Code != 0 || len(ebRes.Data) != 2 || ebRes.Data[1].Embedding[1] != 0.4 || ebRes.Usage.TotalTokens != 4 {
+		t.Errorf("Embedding with OpenAI backend: %+v", ebRes)
+	}
+	if received["encoding_format"] != "float" || len(received["input"].([]any)) != 2 {
+		t.Errorf("OpenAI embedding request: %v", received)
+	}
+}
+
+func TestOllamaBackend(t *testing.T) {
+	var received ollamaChatRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/embed" {
+			fmt.Fprint(w, `{"model":"bge-m3","embeddings":[[0.1,0.2],[0.3,0.4]],"prompt_eval_count":4}`)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received)
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		if len(received.Tools) > 0 {
+			fmt.Fprint(w, `{"model":"llama3","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"get_weather","arguments":{"city":"Paris"}}}]},"done":true,"done_reason":"stop"}`)
+			return
+		}
+		if !received.Stream {
+			fmt.Fprint(w, `{"model":"llama3","message":{"role":"assistant","content":"Hi there"},"done":true,"done_reason":"stop","prompt_eval_count":5,"eval_count":2}`)
+			return
+		}
+		fmt.Fprintln(w, `{"model":"llama3","message":{"role":"assistant","content":"Hi"},"done":false}`)
+		fmt.Fprintln(w, `{"model":"llama3","message":{"role":"assistant","content":" there"},"done":false}`)
+		fmt.Fprintln(w, `{"model":"llama3","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","prompt_eval_count":5,"eval_count":2}`)
+	}))
+	defer server.Close()
+	mc := types.AIModelConfig{Model: "llama3", API: server.URL + "/api/chat", Backend: types.ModelBackendOllama}
+	ctx := context.Background()
+
+	res := ChatModel(ctx, mc, newChatRequest(false))
+	if res.Code != 0 || len(res.Choices) != 1 || res.Choices[0].Message.Content != "Hi there" ||
+		res.Choices[0].FinishReason != "stop" || res.Usage.TotalTokens != 7 {
+		t.Errorf("Chat with Ollama backend: %+v", res)
+	}
+	if len(received.Messages) != 2 || received.Messages[1].Content != "Hello" ||
+		len(received.Messages[1].Images) != 1 || received.Messages[1].Images[0] != "aW1hZ2U=" {
+		t.Errorf("Ollama request: %+v", received)
+	}
+
+	resp, err := ChatModelStream(ctx, mc, newChatRequest(true))
+	if err != nil {
+		t.Fatalf("Stream chat with Ollama backend: %v", err)
+	}
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Errorf("Content type of Ollama stream: %s", resp.Header.Get("Content-Type"))
+	}
+	content := ""
+	events := strings.Split(strings.TrimSpace(readAll(t, resp)), "\n\n")
+	for _, event := range events[:len(events)-1] {
+		var chunk types.StreamChatModelResponseData
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(event, "data: ")), &chunk); err != nil {
+			t.Fatalf("Unmarshal chunk %q: %v", event, err)
+		}
+		content += chunk.Choices[0].Delta.Content
+	}
+	if content != "Hi there" || events[len(events)-1] != "data: [DONE]" {
+		t.Errorf("Stream of Ollama backend: %q", events)
+	}
+
+	seed := int64(7)
+	req := newChatRequest(false)
+	req.Seed = &seed
+	req.MaxTokens = 64
+	req.ResponseFormat = &types.ChatResponseFormat{Type: "json_object"}
+	req.Tools = []types.ChatTool{{Type: "function", Function: types.ChatFunctionDefinition{Name: "get_weather"}}}
+	req.Messages = append(req.Messages, types.ChatCompletionMessage{
+		Role:      "assistant",
+		ToolCalls: []types.ChatToolCall{{ID: "call_0", Type: "function", Function: types.ChatToolCallFunction{Name: "get_weather", Arguments: `{"city":"Rome"}`}}},
+	})
+	res = ChatModel(ctx, mc, req)
+	if res.Code != 0 || res.Choices[0].FinishReason != "tool_calls" || len(res.Choices[0].Message.ToolCalls) != 1 ||
+		res.Choices[0].Message.ToolCalls[0].Function.Arguments != `{"city":"Paris"}` || res.Choices[0].Message.ToolCalls[0].ID == "" {
+		t.Errorf("Tool calls with Ollama backend: %+v", res)
+	}
+	if received.Options == nil || *received.Options.Seed != 7 || received.Options.NumPredict != 64 || string(received.Format) != `"json"` ||
+		string(received.Messages[2].ToolCalls[0].Function.Arguments) != `{"city":"Rome"}` {
+		t.Errorf("Ollama request with options: %+v", received)
+	}
+
+	igRes := ImageGenerationModel(ctx, mc, types.ImageGenModelRequest{Prompt: "bird"})
+	if igRes.Code != int(types.ErrCodeUnsupported) {
+		t.Errorf("Image generation with Ollama backend: %+v", igRes)
+	}
+
+	mc.API = server.URL + "/api/embed"
+	ebRes := EmbeddingModel(ctx, mc, types.EmbeddingModelRequest{Model: "bge-m3", Input: types.EmbeddingInput{"cat", "bird"}})
+	if ebRes.Code != 0 || len(ebRes.Data) != 2 || ebRes.Data[1].Index != 1 || ebRes.Data[1].Embedding[0] != 0.3 || ebRes.Usage.PromptTokens != 4 {
+		t.Errorf("Embedding with Ollama backend: %+v", ebRes)
+	}
+}
+
+func TestImageBackends(t *testing.T) {
+	comfyUIPollInterval = 10 * time.Millisecond
+	var prompt map[string]map[string]any
+	var txt2img automatic1111Request
+	polls := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /prompt", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Prompt map[string]map[string]any `json:"prompt"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		prompt = req.Prompt
+		fmt.Fprint(w, `{"prompt_id":"p1","number":1}`)
+	})
+	mux.HandleFunc("GET /history/p1", func(w http.ResponseWriter, r *http.Request) {
+		if polls++; polls < 2 {
+			fmt.Fprint(w, `{}`)
+			return
+		}
+		fmt.Fprint(w, `{"p1":{"outputs":{"9":{"images":[{"filename":"a.png","subfolder":"","type":"output"}]}},"status":{"status_str":"success","completed":true}}}`)
+	})
+	mux.HandleFunc("GET /view", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "image "+r.URL.Query().Get("filename"))
+	})
+	mux.HandleFunc("POST /sdapi/v1/txt2img", func(w http.ResponseWriter, r *http.Request) {
+		json.NewDecoder(r.Body).Decode(&txt2img)
+		fmt.Fprint(w, `{"images":["Z3JpZA==","aW1hZ2Ux","aW1hZ2Uy"],"info":"{}"}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	ctx := context.Background()
+
+	mc := types.AIModelConfig{Model: "sd_xl_base_1.0.safetensors", API: server.URL + "/prompt", Backend: types.ModelBackendComfyUI}
+	seed := int64(42)
+	res := ImageGenerationModel(ctx, mc, types.ImageGenModelRequest{Prompt: "bird", Size: "1024x768", Seed: &seed})
+	if res.Code != 0 || len(res.Choices) != 1 || res.Choices[0].B64Json != "aW1hZ2UgYS5wbmc=" {
+		t.Errorf("Image generation with ComfyUI backend: %+v", res)
+	}
+	if prompt["6"]["inputs"].(map[string]any)["text"] != "bird" ||
+		prompt["4"]["inputs"].(map[string]any)["ckpt_name"] != mc.Model ||
+		prompt["5"]["inputs"].(map[string]any)["width"] != float64(1024) ||
+		prompt["5"]["inputs"].(map[string]any)["height"] != float64(768) ||
+		prompt["3"]["inputs"].(map[string]any)["seed"] != float64(42) {
+		t.Errorf("ComfyUI workflow: %v", prompt)
+	}
+	if chatRes := ChatModel(ctx, mc, newChatRequest(false)); chatRes.Code != int(types.ErrCodeUnsupported) {
+		t.Errorf("Chat with ComfyUI backend: %+v", chatRes)
+	}
+	if ebRes := EmbeddingModel(ctx, mc, types.EmbeddingModelRequest{Input: types.EmbeddingInput{"bird"}}); ebRes.Code != int(types.ErrCodeUnsupported) {
+		t.Errorf("Embedding with ComfyUI backend: %+v", ebRes)
+	}
+
+	mc = types.AIModelConfig{Model: "sd", API: server.URL + "/sdapi/v1/txt2img", Backend: types.ModelBackendAutomatic1111}
+	res = ImageGenerationModel(ctx, mc, types.ImageGenModelRequest{Prompt: "bird", Number: 2})
+	if res.Code != 0 || len(res.Choices) != 2 || res.Choices[0].B64Json != "aW1hZ2Ux" {
+		t.Errorf("Image generation with Automatic1111 backend: %+v", res)
+	}
+	if txt2img.Prompt != "bird" || txt2img.BatchSize != 2 || txt2img.Width != defaultImageSize || txt2img.Steps != defaultImageSteps || txt2img.Seed != -1 {
+		t.Errorf("Automatic1111 request: %+v", txt2img)
+	}
+}
+
+func TestAudioBackends(t *testing.T) {
+	var received map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/audio/transcriptions":
+			if _, _, err := r.FormFile("file"); err != nil || r.FormValue("model") != "whisper-1" {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":{"message":"invalid form"}}`)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"text":"hello"}`)
+		default:
+			received = nil
+			json.NewDecoder(r.Body).Decode(&received)
+			w.Header().Set("Content-Type", "audio/mpeg")
+			fmt.Fprint(w, "mp3 of hello")
+		}
+	}))
+	defer server.Close()
+	mc := types.AIModelConfig{Model: "whisper-1", API: server.URL + "/v1/audio/transcriptions", Backend: types.ModelBackendOpenAI}
+	ctx := context.Background()
+
+	body, contentType, err := TranscriptionBody([]byte("fake mp3"), "hello.mp3", map[string]string{"model": "whisper-1"})
+	if err != nil {
+		t.Fatalf("Transcription body: %v", err)
+	}
+	resp, err := AudioTranscriptionModel(ctx, mc, body, contentType)
+	if err != nil {
+		t.Fatalf("Audio transcription with OpenAI backend: %v", err)
+	}
+	if res := ReadAudioResponse(resp); res.Code != 0 || string(res.Data) != `{"text":"hello"}` {
+		t.Errorf("Audio transcription with OpenAI backend: %+v", res)
+	}
+
+	body, contentType, _ = TranscriptionBody([]byte("fake mp3"), "hello.mp3", nil)
+	resp, err = AudioTranscriptionModel(ctx, mc, body, contentType)
+	if err != nil {
+		t.Fatalf("Audio transcription with OpenAI backend: %v", err)
+	}
+	if res := ReadAudioResponse(resp); res.Code != int(types.ErrCodeModel) || res.Message != "invalid form" {
+		t.Errorf("Audio transcription error of OpenAI backend: %+v", res)
+	}
+
+	mc.API = server.URL + "/v1/audio/speech"
+	req := types.AudioSpeechModelRequest{
+		Model:              "tts-1",
+		Input:              "hello",
+		Voice:              "alloy",
+		WalletVerification: types.WalletVerification{Wallet: "wallet"},
+	}
+	resp, err = AudioSpeechModel(ctx, mc, req)
+	if err != nil {
+		t.Fatalf("Audio speech with OpenAI backend: %v", err)
+	}
+	if res := ReadAudioResponse(resp); res.Code != 0 || res.ContentType != "audio/mpeg" || string(res.Data) != "mp3 of hello" {
+		t.Errorf("Audio speech with OpenAI backend: %+v", res)
+	}
+	if _, ok := received["wallet"]; ok || received["voice"] != "alloy" {
+		t.Errorf("OpenAI speech request: %v", received)
+	}
+
+	mc.Backend = types.ModelBackendComfyUI
+	resp, err = AudioSpeechModel(ctx, mc, req)
+	if err != nil {
+		t.Fatalf("Audio speech with ComfyUI backend: %v", err)
+	}
+	if res := ReadAudioResponse(resp); res.Code != int(types.ErrCodeUnsupported) {
+		t.Errorf("Audio speech with ComfyUI backend: %+v", res)
+	}
+}
+
+func TestBackendError(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{`{"error":{"message":"model not found"}}`, "model not found"},
+		{`{"error":"Input validation error","error_type":"validation"}`, "Input validation error"},
+		{`{"object":"error","message":"invalid top_p"}`, "invalid top_p"},
+		{`{"detail":"Not Found"}`, "Not Found"},
+		{`{"detail":[{"msg":"field required"}]}`, `[{"msg":"field required"}]`},
+		{"Bad Gateway", "502 Bad Gateway, Bad Gateway"},
+		{"", "502 Bad Gateway"},
+	}
+	for _, test := range tests {
+		resp := &http.Response{
+			Status: "502 Bad Gateway",
+			Body:   io.NopCloser(strings.NewReader(test.body)),
+		}
+		if got := backendError(resp); got != test.want {
+			t.Errorf("backendError(%q) = %q, want %q", test.body, got, test.want)
+		}
+	}
+	if _, err := GetBackend("unknown"); err == nil {
+		t.Error("Get unknown backend")
+	}
+}
@@ -0,0 +1,430 @@
+// Package mock implements a fake AI model backend which follows the interface standard
+// in docs/model_api.md, so that a whole network can be run without any GPU.
+//
+// The outputs only depend on the seed and the request, the same request always gets
+// the same chat completion, the same images and the same embeddings.
+package mock
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"AIComputingNode/pkg/types"
+)
+
+const (
+	DefaultTokens = 16
+	// Images larger than this are answered with this size
+	MaxImageSize = 1024
+	// Dimensions of the embeddings if not requested
+	DefaultEmbeddingDimensions = 16
+	// Embeddings larger than this are answered with this dimensions
+	MaxEmbeddingDimensions = 4096
+)
+
+var vocabulary = []string{
+	"the", "network", "node", "model", "answer", "is", "a", "distributed", "computing",
+	"power", "of", "peer", "to", "and", "with", "request", "response", "image", "text",
+	"token", "worker", "input", "project", "decentralized", "fast", "simple", "mock",
+}
+
+type Options struct {
+	// Project name returned by the model list
+	Project string
+	// Model names returned by the model list, any model name is accepted by the other APIs
+	Models []string
+	// Delay before every response
+	Latency time.Duration
+	// Tokens per second of the streaming chat completion, 0 means no delay between tokens
+	TokenRate float64
+	// Number of tokens of every chat completion, DefaultTokens if not positive
+	Tokens int
+	// Probability in [0, 1] that a request fails with ErrorStatus
+	ErrorRate float64
+	// HTTP status of the injected errors, 500 if zero
+	ErrorStatus int
+	// Seed of the outputs and the injected errors
+	Seed int64
+}
+
+// Server is the HTTP handler of the fake model backend.
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+
+	// random source of the injected errors
+	mutex   sync.Mutex
+	errRand *rand.Rand
+}
+
+func New(opts Options) *Server {
+	if opts.Tokens <= 0 {
+		opts.Tokens = DefaultTokens
+	}
+	if opts.ErrorStatus == 0 {
+		opts.ErrorStatus = http.StatusInternalServerError
+	}
+	s := &Server{
+		opts:    opts,
+		mux:     http.NewServeMux(),
+		errRand: rand.New(rand.NewSource(opts.Seed)),
+	}
+	s.mux.HandleFunc("/v1/chat/completions", s.handle(s.chatCompletions))
+	s.mux.HandleFunc("/v1/images/generations", s.handle(s.imageGenerations))
+	s.mux.HandleFunc("/v1/images/edits", s.handle(s.imageEdits))
+	s.mux.HandleFunc("/v1/images/files/", s.imageFile)
+	s.mux.HandleFunc("/v1/embeddings", s.handle(s.embeddings))
+	s.mux.HandleFunc("/v1/models", s.models)
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// handle applies the latency and the error injection before the model API.
+func (s *Server) handle(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if s.opts.Latency > 0 {
+			select {
+			case <-time.After(s.opts.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if s.injectError() {
+			http.Error(w, "mock injected error", s.opts.ErrorStatus)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) injectError() bool {
+	if s.opts.ErrorRate <= 0 {
+		return false
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.errRand.Float64() < s.opts.ErrorRate
+}
+
+// rand returns the random source of the output of the request parts.
+func (s *Server) rand(parts ...[]byte) *rand.Rand {
+	h := fnv.New64a()
+	binary.Write(h, binary.BigEndian, s.opts.Seed)
+	for _, part := range parts {
+		h.Write(part)
+		h.Write([]byte{0})
+	}
+	return rand.New(rand.NewSource(int64(h.Sum64())))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	// the model package only accepts exactly this content type
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func countTokens(messages []types.ChatCompletionMessage) int {
+	count := 0
+	for _, message := range messages {
+		count += len(strings.Fields(string(message.Content)))
+	}
+	return count
+}
+
+func (s *Server) chatCompletions(w http.ResponseWriter, r *http.Request) {
+	var req types.ChatModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	messages, _ := json.Marshal(req.Messages)
+	rnd := s.rand([]byte(req.Model), messages)
+	tokens := make([]string, s.opts.Tokens)
+	for i := range tokens {
+		tokens[i] = vocabulary[rnd.Intn(len(vocabulary))]
+		if i > 0 {
+			tokens[i] = " " + tokens[i]
+		}
+	}
+	id := fmt.Sprintf("chatcmpl-%016x", rnd.Uint64())
+	usage := types.ChatResponseUsage{
+		CompletionTokens: len(tokens),
+		PromptTokens:     countTokens(req.Messages),
+	}
+	usage.TotalTokens = usage.CompletionTokens + usage.PromptTokens
+
+	if req.Stream {
+		s.streamChatCompletions(w, r, id, tokens, usage)
+		return
+	}
+	writeJSON(w, types.ChatModelResponseData{
+		Id:      id,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Choices: []types.ChatResponseChoice{
+			{
+				Index: 0,
+				Message: types.ChatCompletionResponseMessage{
+					Role:    "assistant",
+					Content: strings.Join(tokens, ""),
+				},
+				FinishReason: "stop",
+			},
+		},
+		Usage: usage,
+	})
+}
+
+// streamChatCompletions sends the tokens one by one as server-sent events ending with data: [DONE].
+func (s *Server) streamChatCompletions(w http.ResponseWriter, r *http.Request, id string, tokens []string, usage types.ChatResponseUsage) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	flusher, _ := w.(http.Flusher)
+	created := time.Now().Unix()
+	send := func(delta types.ChatCompletionResponseMessage, finishReason string, usage types.ChatResponseUsage) {
+		data, _ := json.Marshal(types.StreamChatModelResponseData{
+			Id:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Choices: []types.StreamChatResponseChoice{
+				{Index: 0, Delta: delta, FinishReason: finishReason},
+			},
+			Usage: usage,
+		})
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+
+	var interval time.Duration
+	if s.opts.TokenRate > 0 {
+		interval = time.Duration(float64(time.Second) / s.opts.TokenRate)
+	}
+	send(types.ChatCompletionResponseMessage{Role: "assistant"}, "", types.ChatResponseUsage{})
+	for _, token := range tokens {
+		if interval > 0 {
+			select {
+			case <-time.After(interval):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		send(types.ChatCompletionResponseMessage{Content: token}, "", types.ChatResponseUsage{})
+	}
+	send(types.ChatCompletionResponseMessage{}, "stop", usage)
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	if flusher != nil {
+		flusher.Flush()
+	}
+}
+
+// imageSize parses the size like 1024x1024, or uses the width and height.
+func imageSize(size string, width, height int) (int, int) {
+	if w, h, ok := strings.Cut(size, "x"); ok {
+		width, _ = strconv.Atoi(w)
+		height, _ = strconv.Atoi(h)
+	}
+	if width <= 0 {
+		width = 256
+	}
+	if height <= 0 {
+		height = 256
+	}
+	return min(width, MaxImageSize), min(height, MaxImageSize)
+}
+
+// imageName encodes the color and the size of a generated image.
+type imageName struct {
+	Color  color.RGBA
+	Width  int
+	Height int
+}
+
+func (name imageName) String() string {
+	return fmt.Sprintf("%s-%dx%d.png", hex.EncodeToString([]byte{name.Color.R, name.Color.G, name.Color.B}), name.Width, name.Height)
+}
+
+func parseImageName(s string) (imageName, error) {
+	name := imageName{}
+	var rgb string
+	if _, err := fmt.Sscanf(strings.TrimSuffix(s, ".png"), "%6s-%dx%d", &rgb, &name.Width, &name.Height); err != nil {
+		return name, err
+	}
+	c, err := hex.DecodeString(rgb)
+	if err != nil || len(c) != 3 {
+		return name, fmt.Errorf("invalid image color %q", rgb)
+	}
+	if name.Width <= 0 || name.Width > MaxImageSize || name.Height <= 0 || name.Height > MaxImageSize {
+		return name, fmt.Errorf("invalid image size %dx%d", name.Width, name.Height)
+	}
+	name.Color = color.RGBA{R: c[0], G: c[1], B: c[2], A: 255}
+	return name, nil
+}
+
+func (name imageName) encode() []byte {
+	img := image.NewRGBA(image.Rect(0, 0, name.Width, name.Height))
+	for y := 0; y < name.Height; y++ {
+		for x := 0; x < name.Width; x++ {
+			img.SetRGBA(x, y, name.Color)
+		}
+	}
+	buf := &bytes.Buffer{}
+	png.Encode(buf, img)
+	return buf.Bytes()
+}
+
+func (s *Server) images(r *http.Request, rnd *rand.Rand, prompt string, number, width, height int, responseFormat string) types.ImageModelResponse {
+	if number <= 0 {
+		number = 1
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	rsp := types.ImageModelResponse{Created: time.Now().Unix()}
+	for i := 0; i < number; i++ {
+		name := imageName{
+			Color:  color.RGBA{R: uint8(rnd.Intn(256)), G: uint8(rnd.Intn(256)), B: uint8(rnd.Intn(256)), A: 255},
+			Width:  width,
+			Height: height,
+		}
+		choice := types.ImageResponseChoice{RevisedPrompt: prompt}
+		if responseFormat == "b64_json" {
+			choice.B64Json = base64.StdEncoding.EncodeToString(name.encode())
+		} else {
+			choice.Url = fmt.Sprintf("%s://%s/v1/images/files/%s", scheme, r.Host, name)
+		}
+		rsp.Choices = append(rsp.Choices, choice)
+	}
+	return rsp
+}
+
+func (s *Server) imageGenerations(w http.ResponseWriter, r *http.Request) {
+	var req types.ImageGenModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	width, height := imageSize(req.Size, req.Width, req.Height)
+	rnd := s.rand([]byte(req.Model), []byte(req.Prompt))
+	writeJSON(w, s.images(r, rnd, req.Prompt, req.Number, width, height, req.ResponseFormat))
+}
+
+func (s *Server) imageEdits(w http.ResponseWriter, r *http.Request) {
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	content, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	number, _ := strconv.Atoi(r.FormValue("n"))
+	width, height := imageSize(r.FormValue("size"), 0, 0)
+	prompt := r.FormValue("prompt")
+	rnd := s.rand([]byte(r.FormValue("model")), []byte(prompt), content)
+	writeJSON(w, s.images(r, rnd, prompt, number, width, height, r.FormValue("response_format")))
+}
+
+func (s *Server) imageFile(w http.ResponseWriter, r *http.Request) {
+	name, err := parseImageName(strings.TrimPrefix(r.URL.Path, "/v1/images/files/"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "image/png")
+	w.Write(name.encode())
+}
+
+// embedding returns the unit vector of the text.
+func (s *Server) embedding(model, text string, dimensions int) []float32 {
+	rnd := s.rand([]byte(model), []byte(text))
+	vector := make([]float64, dimensions)
+	norm := 0.0
+	for i := range vector {
+		vector[i] = rnd.NormFloat64()
+		norm += vector[i] * vector[i]
+	}
+	norm = math.Sqrt(norm)
+	embedding := make([]float32, dimensions)
+	for i := range vector {
+		embedding[i] = float32(vector[i] / norm)
+	}
+	return embedding
+}
+
+func (s *Server) embeddings(w http.ResponseWriter, r *http.Request) {
+	var req types.EmbeddingModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	dimensions := req.Dimensions
+	if dimensions <= 0 {
+		dimensions = DefaultEmbeddingDimensions
+	}
+	dimensions = min(dimensions, MaxEmbeddingDimensions)
+	rsp := types.EmbeddingModelResponse{
+		Object: "list",
+		Model:  req.Model,
+		Data:   make([]types.EmbeddingData, 0, len(req.Input)),
+	}
+	for i, text := range req.Input {
+		rsp.Data = append(rsp.Data, types.EmbeddingData{
+			Object:    "embedding",
+			Index:     i,
+			Embedding: s.embedding(req.Model, text, dimensions),
+		})
+		rsp.Usage.PromptTokens += len(strings.Fields(text))
+	}
+	rsp.Usage.TotalTokens = rsp.Usage.PromptTokens
+	writeJSON(w, rsp)
+}
+
+type modelInfo struct {
+	Model string `json:"model"`
+	Url   string `json:"url"`
+}
+
+type modelList struct {
+	Project string      `json:"project"`
+	Data    []modelInfo `json:"data"`
+}
+
+func (s *Server) models(w http.ResponseWriter, r *http.Request) {
+	rsp := modelList{Project: s.opts.Project, Data: make([]modelInfo, 0, len(s.opts.Models))}
+	for _, model := range s.opts.Models {
+		rsp.Data = append(rsp.Data, modelInfo{
+			Model: model,
+			Url:   fmt.Sprintf("http://%s/v1/chat/completions", r.Host),
+		})
+	}
+	writeJSON(w, rsp)
+}
@@ -0,0 +1,270 @@
+package mock
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/model"
+	"AIComputingNode/pkg/types"
+)
+
+func chatRequest(content string, stream bool) types.ChatModelRequest {
+	text, _ := json.Marshal(content)
+	return types.ChatModelRequest{
+		Model:    "Llama3-8B",
+		Messages: []types.ChatCompletionMessage{{Role: "user", Content: text}},
+		Stream:   stream,
+	}
+}
+
+func TestChatCompletions(t *testing.T) {
+	server := httptest.NewServer(New(Options{Tokens: 8, Seed: 1}))
+	defer server.Close()
+	api := server.URL + "/v1/chat/completions"
+
+	rsp := model.ChatModel(context.Background(), types.AIModelConfig{API: api}, chatRequest("Hello", false))
+	if rsp.Code != 0 {
+		t.Fatalf("Chat model: {code:%d, message:%s}", rsp.Code, rsp.Message)
+	}
+	if len(rsp.Choices) != 1 || len(strings.Fields(rsp.Choices[0].Message.Content)) != 8 {
+		t.Fatalf("Unexpected choices %+v", rsp.Choices)
+	}
+	if rsp.Usage.CompletionTokens != 8 || rsp.Usage.TotalTokens != 8+rsp.Usage.PromptTokens {
+		t.Errorf("Unexpected usage %+v", rsp.Usage)
+	}
+
+	again := model.ChatModel(context.Background(), types.AIModelConfig{API: api}, chatRequest("Hello", false))
+	if again.Choices[0].Message.Content != rsp.Choices[0].Message.Content {
+		t.Errorf("Same request got different replies %q and %q",
+			rsp.Choices[0].Message.Content, again.Choices[0].Message.Content)
+	}
+	other := New(Options{Tokens: 8, Seed: 2})
+	otherServer := httptest.NewServer(other)
+	defer otherServer.Close()
+	seeded := model.ChatModel(context.Background(), types.AIModelConfig{API: otherServer.URL + "/v1/chat/completions"}, chatRequest("Hello", false))
+	if seeded.Choices[0].Message.Content == rsp.Choices[0].Message.Content {
+		t.Errorf("Different seeds got the same reply %q", rsp.Choices[0].Message.Content)
+	}
+}
+
+func TestStreamChatCompletions(t *testing.T) {
+	server := httptest.NewServer(New(Options{Tokens: 5, TokenRate: 100}))
+	defer server.Close()
+
+	body, _ := json.Marshal(chatRequest("Hello", true))
+	start := time.Now()
+	resp, err := http.Post(server.URL+"/v1/chat/completions", "application/json", bytes.NewReader(body))
+	if err != nil {
+		t.Fatalf("Post chat request: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("Unexpected content type %q", resp.Header.Get("Content-Type"))
+	}
+
+	content := ""
+	finished, done := false, false
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			done = true
+			break
+		}
+		var chunk types.StreamChatModelResponseData
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			t.Fatalf("Unmarshal chunk %q: %v", data, err)
+		}
+		content += chunk.Choices[0].Delta.Content
+		if chunk.Choices[0].FinishReason == "stop" {
+			finished = true
+			if chunk.Usage.CompletionTokens != 5 {
+				t.Errorf("Unexpected usage %+v", chunk.Usage)
+			}
+		}
+	}
+	if !finished || !done {
+		t.Fatalf("Stream not finished (stop %v, done %v)", finished, done)
+	}
+	if len(strings.Fields(content)) != 5 {
+		t.Errorf("Unexpected content %q", content)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("Token rate not applied, streamed in %v", elapsed)
+	}
+
+	rsp := model.ChatModel(context.Background(), types.AIModelConfig{API: server.URL + "/v1/chat/completions"}, chatRequest("Hello", false))
+	if rsp.Choices[0].Message.Content != content {
+		t.Errorf("Streaming reply %q differs from %q", content, rsp.Choices[0].Message.Content)
+	}
+}
+
+func TestImageGenerations(t *testing.T) {
+	server := httptest.NewServer(New(Options{}))
+	defer server.Close()
+	api := server.URL + "/v1/images/generations"
+
+	rsp := model.ImageGenerationModel(context.Background(), types.AIModelConfig{API: api}, types.ImageGenModelRequest{
+		Model:  "SuperImage",
+		Prompt: "bird",
+		Number: 2,
+		Size:   "64x32",
+	})
+	if rsp.Code != 0 {
+		t.Fatalf("Image generation model: {code:%d, message:%s}", rsp.Code, rsp.Message)
+	}
+	if len(rsp.Choices) != 2 || rsp.Choices[0].Url == "" {
+		t.Fatalf("Unexpected choices %+v", rsp.Choices)
+	}
+	resp, err := http.Get(rsp.Choices[0].Url)
+	if err != nil {
+		t.Fatalf("Get image: %v", err)
+	}
+	defer resp.Body.Close()
+	img, err := png.Decode(resp.Body)
+	if err != nil {
+		t.Fatalf("Decode image: %v", err)
+	}
+	if size := img.Bounds().Size(); size.X != 64 || size.Y != 32 {
+		t.Errorf("Unexpected image size %v", size)
+	}
+
+	b64 := model.ImageGenerationModel(context.Background(), types.AIModelConfig{API: api}, types.ImageGenModelRequest{
+		Model:          "SuperImage",
+		Prompt:         "bird",
+		Width:          16,
+		Height:         16,
+		ResponseFormat: "b64_json",
+	})
+	if len(b64.Choices) != 1 {
+		t.Fatalf("Unexpected choices %+v", b64.Choices)
+	}
+	data, err := base64.StdEncoding.DecodeString(b64.Choices[0].B64Json)
+	if err != nil {
+		t.Fatalf("Decode b64_json: %v", err)
+	}
+	if _, err := png.Decode(bytes.NewReader(data)); err != nil {
+		t.Errorf("Decode image: %v", err)
+	}
+}
+
+func TestEmbeddings(t *testing.T) {
+	server := httptest.NewServer(New(Options{}))
+	defer server.Close()
+	mc := types.AIModelConfig{API: server.URL + "/v1/embeddings"}
+
+	rsp := model.EmbeddingModel(context.Background(), mc, types.EmbeddingModelRequest{
+		Model: "bge-m3",
+		Input: types.EmbeddingInput{"a bird", "a cat", "a bird"},
+	})
+	if rsp.Code != 0 {
+		t.Fatalf("Embedding model: {code:%d, message:%s}", rsp.Code, rsp.Message)
+	}
+	if len(rsp.Data) != 3 || len(rsp.Data[0].Embedding) != DefaultEmbeddingDimensions || rsp.Usage.TotalTokens != 6 {
+		t.Fatalf("Unexpected embeddings %+v", rsp)
+	}
+	if !slices.Equal(rsp.Data[0].Embedding, rsp.Data[2].Embedding) || slices.Equal(rsp.Data[0].Embedding, rsp.Data[1].Embedding) {
+		t.Errorf("Embeddings are not deterministic %+v", rsp.Data)
+	}
+	norm := float32(0)
+	for _, v := range rsp.Data[1].Embedding {
+		norm += v * v
+	}
+	if norm < 0.99 || norm > 1.01 {
+		t.Errorf("Embedding is not normalized, norm %v", norm)
+	}
+
+	rsp = model.EmbeddingModel(context.Background(), mc, types.EmbeddingModelRequest{
+		Model:      "bge-m3",
+		Input:      types.EmbeddingInput{"a bird"},
+		Dimensions: 4,
+	})
+	if len(rsp.Data) != 1 || len(rsp.Data[0].Embedding) != 4 {
+		t.Errorf("Unexpected embeddings %+v", rsp.Data)
+	}
+}
+
+func TestImageEdits(t *testing.T) {
+	server := httptest.NewServer(New(Options{}))
+	defer server.Close()
+
+	edit := func(content string) types.ImageModelResponse {
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("image", "otter.png")
+		part.Write([]byte(content))
+		writer.WriteField("prompt", "A cute baby sea otter wearing a beret")
+		writer.WriteField("n", "1")
+		writer.WriteField("size", "16x16")
+		writer.Close()
+		resp, err := http.Post(server.URL+"/v1/images/edits", writer.FormDataContentType(), body)
+		if err != nil {
+			t.Fatalf("Post image edit: %v", err)
+		}
+		defer resp.Body.Close()
+		var rsp types.ImageModelResponse
+		if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil {
+			t.Fatalf("Decode image edit response: %v", err)
+		}
+		if len(rsp.Choices) != 1 {
+			t.Fatalf("Unexpected choices %+v", rsp.Choices)
+		}
+		return rsp
+	}
+	first, second := edit("image one"), edit("image two")
+	if first.Choices[0].Url == second.Choices[0].Url {
+		t.Errorf("Different images got the same result %s", first.Choices[0].Url)
+	}
+	if again := edit("image one"); again.Choices[0].Url != first.Choices[0].Url {
+		t.Errorf("Same image got different results %s and %s", first.Choices[0].Url, again.Choices[0].Url)
+	}
+}
+
+func TestErrorInjection(t *testing.T) {
+	server := httptest.NewServer(New(Options{ErrorRate: 1, ErrorStatus: http.StatusServiceUnavailable}))
+	defer server.Close()
+
+	rsp := model.ChatModel(context.Background(), types.AIModelConfig{API: server.URL + "/v1/chat/completions"}, chatRequest("Hello", false))
+	if rsp.Code != int(types.ErrCodeModel) || !strings.Contains(rsp.Message, "503") {
+		t.Errorf("Unexpected response {code:%d, message:%s}", rsp.Code, rsp.Message)
+	}
+
+	half := New(Options{ErrorRate: 0.5, Seed: 7})
+	failed := 0
+	for i := 0; i < 1000; i++ {
+		if half.injectError() {
+			failed++
+		}
+	}
+	if failed < 400 || failed > 600 {
+		t.Errorf("Injected %d errors in 1000 requests with rate 0.5", failed)
+	}
+}
+
+func TestLatency(t *testing.T) {
+	server := httptest.NewServer(New(Options{Latency: 50 * time.Millisecond}))
+	defer server.Close()
+
+	start := time.Now()
+	rsp := model.ChatModel(context.Background(), types.AIModelConfig{API: server.URL + "/v1/chat/completions"}, chatRequest("Hello", false))
+	if rsp.Code != 0 {
+		t.Fatalf("Chat model: {code:%d, message:%s}", rsp.Code, rsp.Message)
+	}
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("Latency not applied, answered in %v", elapsed)
+	}
+}
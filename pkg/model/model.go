@@ -1,7 +1,7 @@
 package model
 
 import (
-	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,6 +12,95 @@ import (
 	"AIComputingNode/pkg/types"
 )
 
+// ChatModel runs the chat completion with the backend of the model.
+func ChatModel(ctx context.Context, mc types.AIModelConfig, chatReq types.ChatModelRequest) *types.ChatCompletionResponse {
+	if mc.API == "" {
+		return &types.ChatCompletionResponse{
+			BaseHttpResponse: types.BaseHttpResponse{
+				Code:    int(types.ErrCodeModel),
+				Message: "Model API configuration is empty",
+			},
+		}
+	}
+	backend, err := GetBackend(mc.Backend)
+	if err != nil {
+		return &types.ChatCompletionResponse{
+			BaseHttpResponse: types.BaseHttpResponse{
+				Code:    int(types.ErrCodeModel),
+				Message: err.Error(),
+			},
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, types.ChatCompletionRequestTimeout)
+	defer cancel()
+	return backend.Chat(ctx, mc, chatReq)
+}
+
+// ChatModelStream runs the streamed chat completion with the backend of the model until the context is done.
+// The body of the response is the server-sent events of the chat completion chunks, or a JSON BaseHttpResponse.
+func ChatModelStream(ctx context.Context, mc types.AIModelConfig, chatReq types.ChatModelRequest) (*http.Response, error) {
+	if mc.API == "" {
+		return nil, errors.New("model API configuration is empty")
+	}
+	backend, err := GetBackend(mc.Backend)
+	if err != nil {
+		return nil, err
+	}
+	return backend.ChatStream(ctx, mc, chatReq)
+}
+
+// ImageGenerationModel generates the images with the backend of the model.
+func ImageGenerationModel(ctx context.Context, mc types.AIModelConfig, req types.ImageGenModelRequest) *types.ImageGenerationResponse {
+	if mc.API == "" {
+		return &types.ImageGenerationResponse{
+			BaseHttpResponse: types.BaseHttpResponse{
+				Code:    int(types.ErrCodeModel),
+				Message: "Model API configuration is empty",
+			},
+		}
+	}
+	backend, err := GetBackend(mc.Backend)
+	if err != nil {
+		return &types.ImageGenerationResponse{
+			BaseHttpResponse: types.BaseHttpResponse{
+				Code:    int(types.ErrCodeModel),
+				Message: err.Error(),
+			},
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, types.ImageGenerationRequestTimeout)
+	defer cancel()
+	return backend.ImageGeneration(ctx, mc, req)
+}
+
+// EmbeddingModel embeds the batch of texts with the backend of the model.
+func EmbeddingModel(ctx context.Context, mc types.AIModelConfig, req types.EmbeddingModelRequest) *types.EmbeddingResponse {
+	if mc.API == "" {
+		return &types.EmbeddingResponse{
+			BaseHttpResponse: types.BaseHttpResponse{
+				Code:    int(types.ErrCodeModel),
+				Message: "Model API configuration is empty",
+			},
+		}
+	}
+	backend, err := GetBackend(mc.Backend)
+	if err != nil {
+		return &types.EmbeddingResponse{
+			BaseHttpResponse: types.BaseHttpResponse{
+				Code:    int(types.ErrCodeModel),
+				Message: err.Error(),
+			},
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, types.EmbeddingRequestTimeout)
+	defer cancel()
+	return backend.Embedding(ctx, mc, req)
+}
+
+// defaultBackend serves the models whose API is compatible with OpenAI, and wraps the responses
+// in the code/message envelope of this project.
+type defaultBackend struct{}
+
 //	curl http://127.0.0.1:1042/v1/chat/completions -H "Content-Type: application/json" -d "{
 //	   \"model\": \"Llama3-8B\",
 //	   \"messages\": [
@@ -25,35 +114,19 @@ import (
 //	     }
 //	   ]
 //	 }"
-func ChatModel(api string, chatReq types.ChatModelRequest) *types.ChatCompletionResponse {
+func (defaultBackend) Chat(ctx context.Context, mc types.AIModelConfig, chatReq types.ChatModelRequest) *types.ChatCompletionResponse {
 	result := &types.ChatCompletionResponse{
 		BaseHttpResponse: types.BaseHttpResponse{
 			Code: int(types.ErrCodeModel),
 		},
 	}
-	if api == "" {
-		result.Message = "Model API configuration is empty"
-		return result
-	}
-	jsonData, err := json.Marshal(chatReq)
-	if err != nil {
-		result.Message = "Marshal model request error"
-		return result
-	}
-	client := &http.Client{
-		Timeout: types.ChatCompletionRequestTimeout,
-	}
-	resp, err := client.Post(
-		api,
-		"application/json",
-		bytes.NewBuffer(jsonData),
-	)
+	resp, err := postJSON(ctx, mc.API, chatReq)
 	if err != nil {
 		result.Message = fmt.Sprintf("Post HTTP request error, %v", err)
 		return result
 	}
 	defer resp.Body.Close()
-	if resp.Header.Get("Content-Type") == "application/json" {
+	if isJSON(resp.Header) {
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			result.Message = "Read model response error"
@@ -74,37 +147,25 @@ func ChatModel(api string, chatReq types.ChatModelRequest) *types.ChatCompletion
 	return result
 }
 
+func (defaultBackend) ChatStream(ctx context.Context, mc types.AIModelConfig, chatReq types.ChatModelRequest) (*http.Response, error) {
+	return postJSON(ctx, mc.API, chatReq)
+}
+
 // curl -X POST "http://127.0.0.1:8080/models/superimage" -H "Content-Type: application/json" -d "{\"prompt\":\"bird\"}"
 // curl -X POST "http://127.0.0.1:1088/v1/images/generations" -H "Content-Type: application/json" -d "{\"model\":\"superimage\",\"prompt\":\"bird\",\"n\":1,\"size\":\"1024x1024\"}"
-func ImageGenerationModel(api string, req types.ImageGenModelRequest) *types.ImageGenerationResponse {
+func (defaultBackend) ImageGeneration(ctx context.Context, mc types.AIModelConfig, req types.ImageGenModelRequest) *types.ImageGenerationResponse {
 	result := &types.ImageGenerationResponse{
 		BaseHttpResponse: types.BaseHttpResponse{
 			Code: int(types.ErrCodeModel),
 		},
 	}
-	if api == "" {
-		result.Message = "Model API configuration is empty"
-		return result
-	}
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		result.Message = "Marshal model request error"
-		return result
-	}
-	client := &http.Client{
-		Timeout: types.ImageGenerationRequestTimeout,
-	}
-	resp, err := client.Post(
-		api,
-		"application/json",
-		bytes.NewBuffer(jsonData),
-	)
+	resp, err := postJSON(ctx, mc.API, req)
 	if err != nil {
 		result.Message = fmt.Sprintf("Post HTTP request error, %v", err)
 		return result
 	}
 	defer resp.Body.Close()
-	if resp.Header.Get("Content-Type") == "application/json" {
+	if isJSON(resp.Header) {
 		body, err := io.ReadAll(resp.Body)
 		if err != nil {
 			result.Message = "Read model response error"
@@ -125,53 +186,48 @@ func ImageGenerationModel(api string, req types.ImageGenModelRequest) *types.Ima
 	return result
 }
 
-func ImageEditModel(api string, form *multipart.Form) (*http.Response, error) {
-	if api == "" {
-		return nil, errors.New("model API configuration is empty")
+func (defaultBackend) Embedding(ctx context.Context, mc types.AIModelConfig, req types.EmbeddingModelRequest) *types.EmbeddingResponse {
+	result := &types.EmbeddingResponse{
+		BaseHttpResponse: types.BaseHttpResponse{
+			Code: int(types.ErrCodeModel),
+		},
 	}
-
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-	// copy file field in form
-	for fieldName, files := range form.File {
-		for _, fileHeader := range files {
-			file, err := fileHeader.Open()
-			if err != nil {
-				return nil, fmt.Errorf("failed to open %v %v", fieldName, err)
-			}
-			defer file.Close()
-
-			part, err := writer.CreateFormFile(fieldName, fileHeader.Filename)
-			if err != nil {
-				return nil, fmt.Errorf("failed to create form file for %v %v", fieldName, err)
-			}
-
-			_, err = io.Copy(part, file)
-			if err != nil {
-				return nil, fmt.Errorf("failed to copy form file for %v %v", fieldName, err)
-			}
-		}
+	resp, err := postJSON(ctx, mc.API, req)
+	if err != nil {
+		result.Message = fmt.Sprintf("Post HTTP request error, %v", err)
+		return result
 	}
-
-	// copy other field in form
-	for fieldName, values := range form.Value {
-		for _, value := range values {
-			if err := writer.WriteField(fieldName, value); err != nil {
-				return nil, fmt.Errorf("failed to copy %v field %v", fieldName, err)
-			}
+	defer resp.Body.Close()
+	if isJSON(resp.Header) {
+		response := types.EmbeddingResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+			result.Message = "Unmarshal model response error"
+			return result
 		}
+		*result = response
+	} else if resp.StatusCode != 200 {
+		result.Message = fmt.Sprintf("Post HTTP request error, %s", resp.Status)
+	} else {
+		result.Message = "Model HTTP reponse is not JSON"
 	}
+	return result
+}
 
-	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("failed to close multipart writer %v", err)
+func ImageEditModel(api string, form *multipart.Form) (*http.Response, error) {
+	if api == "" {
+		return nil, errors.New("model API configuration is empty")
 	}
 
+	body, contentType, err := MultipartBody(form)
+	if err != nil {
+		return nil, err
+	}
 	client := &http.Client{
 		Timeout: types.ImageGenerationRequestTimeout,
 	}
 	return client.Post(
 		api,
-		writer.FormDataContentType(),
+		contentType,
 		body,
 	)
 }
@@ -3,6 +3,7 @@ package model
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -43,7 +44,7 @@ func TestChatModel(t *testing.T) {
 		Role:    "user",
 		Content: []byte(`"Hello"`),
 	})
-	res := ChatModel(config.Models.Llama3.API, req)
+	res := ChatModel(context.Background(), types.AIModelConfig{API: config.Models.Llama3.API}, req)
 	if res.Code != 0 {
 		t.Fatalf("Execute model %s error {code: %v, message: %s}", config.Models.Llama3.Name, res.Code, res.Message)
 	}
@@ -295,7 +296,7 @@ func TestImageModel(t *testing.T) {
 		Size:           "1024x1024",
 		ResponseFormat: "url",
 	}
-	res := ImageGenerationModel(config.Models.SuperImage.API, req)
+	res := ImageGenerationModel(context.Background(), types.AIModelConfig{API: config.Models.SuperImage.API}, req)
 	if res.Code != 0 {
 		t.Fatalf("Execute model %s with %q error {code: %v, message: %s}", config.Models.SuperImage.Name, prompt, res.Code, res.Message)
 	}
@@ -7,17 +7,16 @@ import (
 	"AIComputingNode/pkg/types"
 )
 
+// ProjectMap is the registry of the models of a node, with the number of the requests running on them.
 type ProjectMap struct {
 	mutex    sync.RWMutex
 	elements map[string][]types.ModelIdle
 }
 
-var projects = ProjectMap{
-	mutex:    sync.RWMutex{},
-	elements: make(map[string][]types.ModelIdle),
-}
-
-func InitModels(ms []types.AIProjectConfig) error {
+func NewProjectMap(ms []types.AIProjectConfig) *ProjectMap {
+	projects := &ProjectMap{
+		elements: make(map[string][]types.ModelIdle),
+	}
 	for _, pc := range ms {
 		models := make([]types.ModelIdle, 0)
 		for _, model := range pc.Models {
@@ -28,10 +27,10 @@ func InitModels(ms []types.AIProjectConfig) error {
 		}
 		projects.elements[pc.Project] = models
 	}
-	return nil
+	return projects
 }
 
-func IdleCount() int {
+func (projects *ProjectMap) IdleCount() int {
 	projects.mutex.RLock()
 	defer projects.mutex.RUnlock()
 	idleCount := 0
@@ -43,7 +42,7 @@ func IdleCount() int {
 	return idleCount
 }
 
-func GetAIProjects() map[string][]types.ModelIdle {
+func (projects *ProjectMap) GetAIProjects() map[string][]types.ModelIdle {
 	projects.mutex.RLock()
 	defer projects.mutex.RUnlock()
 	// Do not modify the returned value
@@ -57,7 +56,7 @@ func GetAIProjects() map[string][]types.ModelIdle {
 	return res
 }
 
-func GetModelInfo(projectName, modelName, cid string) (*types.ModelIdle, error) {
+func (projects *ProjectMap) GetModelInfo(projectName, modelName, cid string) (*types.ModelIdle, error) {
 	mi := &types.ModelIdle{}
 	if projectName == "" || modelName == "" {
 		return mi, fmt.Errorf("empty project or model")
@@ -85,7 +84,7 @@ func GetModelInfo(projectName, modelName, cid string) (*types.ModelIdle, error)
 	return mi, nil
 }
 
-func RegisterAIProject(pjt types.AIProjectConfig) {
+func (projects *ProjectMap) RegisterAIProject(pjt types.AIProjectConfig) {
 	projects.mutex.Lock()
 	defer projects.mutex.Unlock()
 
@@ -110,13 +109,13 @@ func RegisterAIProject(pjt types.AIProjectConfig) {
 	projects.elements[pjt.Project] = models
 }
 
-func UnregisterAIProject(project string) {
+func (projects *ProjectMap) UnregisterAIProject(project string) {
 	projects.mutex.Lock()
 	defer projects.mutex.Unlock()
 	delete(projects.elements, project)
 }
 
-func RegisterAIModel(mr types.AIModelRegister) {
+func (projects *ProjectMap) RegisterAIModel(mr types.AIModelRegister) {
 	projects.mutex.Lock()
 	defer projects.mutex.Unlock()
 
@@ -157,7 +156,7 @@ func RegisterAIModel(mr types.AIModelRegister) {
 	}
 }
 
-func UnregisterAIModel(projectName, modelName, cid string) {
+func (projects *ProjectMap) UnregisterAIModel(projectName, modelName, cid string) {
 	projects.mutex.Lock()
 	defer projects.mutex.Unlock()
 
@@ -178,7 +177,7 @@ func UnregisterAIModel(projectName, modelName, cid string) {
 }
 
 // Increase Reference
-func IncRef(project, model, cid string) {
+func (projects *ProjectMap) IncRef(project, model, cid string) {
 	projects.mutex.Lock()
 	defer projects.mutex.Unlock()
 	if models, ok := projects.elements[project]; ok {
@@ -193,7 +192,7 @@ func IncRef(project, model, cid string) {
 }
 
 // Decrease reference
-func DecRef(project, model, cid string) {
+func (projects *ProjectMap) DecRef(project, model, cid string) {
 	projects.mutex.Lock()
 	defer projects.mutex.Unlock()
 	if models, ok := projects.elements[project]; ok {
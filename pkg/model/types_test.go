@@ -44,41 +44,41 @@ func TestSyncMap(t *testing.T) {
 		},
 	})
 
-	InitModels(pjt)
+	projects := NewProjectMap(pjt)
 
 	t.Log("Init models")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	IncRef("P1", "P1-M1", "")
-	IncRef("P1", "P1-M1", "P1-M1")
-	IncRef("P1", "P1-M2", "P1-M2")
+	projects.IncRef("P1", "P1-M1", "")
+	projects.IncRef("P1", "P1-M1", "P1-M1")
+	projects.IncRef("P1", "P1-M2", "P1-M2")
 
-	IncRef("P2", "P2-M1", "P2-M1")
-	IncRef("P2", "P2-M2", "P2-M2")
-	IncRef("P2", "P2-M2", "P2-M2")
+	projects.IncRef("P2", "P2-M1", "P2-M1")
+	projects.IncRef("P2", "P2-M2", "P2-M2")
+	projects.IncRef("P2", "P2-M2", "P2-M2")
 
 	t.Log("Increase reference")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	DecRef("P2", "P2-M1", "P2-M1")
+	projects.DecRef("P2", "P2-M1", "P2-M1")
 
 	t.Log("Decrease reference")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	DecRef("P2", "P2-M1", "P2-M1")
+	projects.DecRef("P2", "P2-M1", "P2-M1")
 
 	t.Log("Decrease reference")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	RegisterAIProject(types.AIProjectConfig{
+	projects.RegisterAIProject(types.AIProjectConfig{
 		Project: "P2",
 		Models: []types.AIModelConfig{
 			{
@@ -103,11 +103,11 @@ func TestSyncMap(t *testing.T) {
 	})
 
 	t.Log("Register AI Project")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	RegisterAIProject(types.AIProjectConfig{
+	projects.RegisterAIProject(types.AIProjectConfig{
 		Project: "P2",
 		Models: []types.AIModelConfig{
 			{
@@ -120,18 +120,18 @@ func TestSyncMap(t *testing.T) {
 	})
 
 	t.Log("Register AI Project again")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	UnregisterAIProject("P2")
+	projects.UnregisterAIProject("P2")
 
 	t.Log("Unregister AI Project")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	RegisterAIModel(types.AIModelRegister{
+	projects.RegisterAIModel(types.AIModelRegister{
 		AIModelConfig: types.AIModelConfig{
 			Model: "P2-M1",
 			API:   "P2-url1",
@@ -141,7 +141,7 @@ func TestSyncMap(t *testing.T) {
 		Project: "P2",
 	})
 
-	RegisterAIModel(types.AIModelRegister{
+	projects.RegisterAIModel(types.AIModelRegister{
 		AIModelConfig: types.AIModelConfig{
 			Model: "P2-M2",
 			API:   "P2-url2",
@@ -152,26 +152,26 @@ func TestSyncMap(t *testing.T) {
 	})
 
 	t.Log("Register AI Model")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	IncRef("P2", "P2-M1", "P2-M1")
-	IncRef("P2", "P2-M2", "P2-M2")
+	projects.IncRef("P2", "P2-M1", "P2-M1")
+	projects.IncRef("P2", "P2-M2", "P2-M2")
 
 	t.Log("Increase reference")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	DecRef("P2", "P2-M1", "P2-M1")
+	projects.DecRef("P2", "P2-M1", "P2-M1")
 
 	t.Log("Decrease reference")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	RegisterAIModel(types.AIModelRegister{
+	projects.RegisterAIModel(types.AIModelRegister{
 		AIModelConfig: types.AIModelConfig{
 			Model: "P2-M2",
 			API:   "P2-url3",
@@ -182,28 +182,28 @@ func TestSyncMap(t *testing.T) {
 	})
 
 	t.Log("Register AI Model again with modify")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	UnregisterAIModel("P2", "P2-M1", "")
+	projects.UnregisterAIModel("P2", "P2-M1", "")
 
 	t.Log("Unregister AI Model")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	UnregisterAIModel("P2", "P2-M1", "P2-M1")
+	projects.UnregisterAIModel("P2", "P2-M1", "P2-M1")
 
 	t.Log("Unregister AI Model again")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
-	UnregisterAIModel("P2", "P2-M2", "P2-M2")
+	projects.UnregisterAIModel("P2", "P2-M2", "P2-M2")
 
 	t.Log("Unregister AI Model again again")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 }
@@ -246,10 +246,10 @@ func TestConcurrentMap(t *testing.T) {
 		},
 	})
 
-	InitModels(pjt)
+	projects := NewProjectMap(pjt)
 
 	t.Log("Init models")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 
@@ -261,51 +261,51 @@ func TestConcurrentMap(t *testing.T) {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
-			defer DecRef("P1", "P1-M1", "P1-M1")
-			IncRef("P1", "P1-M1", "P1-M1")
+			defer projects.DecRef("P1", "P1-M1", "P1-M1")
+			projects.IncRef("P1", "P1-M1", "P1-M1")
 		}()
 	}
 
 	// wg.Add(1)
 	// go func() {
 	// 	defer wg.Done()
-	// 	IncRef("P1", "P1-M1", "P1-M1")
+	// 	projects.IncRef("P1", "P1-M1", "P1-M1")
 	// }()
 
 	// wg.Add(1)
 	// go func() {
 	// 	defer wg.Done()
-	// 	IncRef("P1", "P1-M1", "P1-M1")
+	// 	projects.IncRef("P1", "P1-M1", "P1-M1")
 	// }()
 
 	// wg.Add(1)
 	// go func() {
 	// 	defer wg.Done()
-	// 	DecRef("P1", "P1-M1", "P1-M1")
+	// 	projects.DecRef("P1", "P1-M1", "P1-M1")
 	// }()
 
 	// wg.Add(1)
 	// go func() {
 	// 	defer wg.Done()
-	// 	IncRef("P1", "P1-M2", "P1-M2")
+	// 	projects.IncRef("P1", "P1-M2", "P1-M2")
 	// }()
 
 	// wg.Add(1)
 	// go func() {
 	// 	defer wg.Done()
-	// 	IncRef("P1", "P1-M3", "P1-M3")
+	// 	projects.IncRef("P1", "P1-M3", "P1-M3")
 	// }()
 
 	// wg.Add(1)
 	// go func() {
 	// 	defer wg.Done()
-	// 	IncRef("P3", "P3-M1", "P3-M1")
+	// 	projects.IncRef("P3", "P3-M1", "P3-M1")
 	// }()
 
 	// wg.Add(1)
 	// go func() {
 	// 	defer wg.Done()
-	// 	for pn, models := range GetAIProjects() {
+	// 	for pn, models := range projects.GetAIProjects() {
 	// 		t.Log(pn, models)
 	// 	}
 	// }()
@@ -313,7 +313,7 @@ func TestConcurrentMap(t *testing.T) {
 	wg.Wait()
 
 	t.Log("After concurrent goroutine")
-	for pn, models := range GetAIProjects() {
+	for pn, models := range projects.GetAIProjects() {
 		t.Log(pn, models)
 	}
 }
@@ -0,0 +1,629 @@
+package node
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"AIComputingNode/pkg/blob"
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/conngater"
+	"AIComputingNode/pkg/db"
+	"AIComputingNode/pkg/libp2p/host"
+	"AIComputingNode/pkg/libp2p/stream"
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/model"
+	ps "AIComputingNode/pkg/pubsub"
+	"AIComputingNode/pkg/scheduler"
+	"AIComputingNode/pkg/selfupdate"
+	"AIComputingNode/pkg/serve"
+	"AIComputingNode/pkg/timer"
+	"AIComputingNode/pkg/types"
+
+	"github.com/go-co-op/gocron/v2"
+	"github.com/libp2p/go-libp2p"
+	libp2phost "github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/peerstore"
+	"github.com/libp2p/go-libp2p/core/protocol"
+	"github.com/libp2p/go-libp2p/core/routing"
+	drouting "github.com/libp2p/go-libp2p/p2p/discovery/routing"
+	dutil "github.com/libp2p/go-libp2p/p2p/discovery/util"
+	rcmgr "github.com/libp2p/go-libp2p/p2p/host/resource-manager"
+	"github.com/libp2p/go-libp2p/p2p/net/connmgr"
+	relayv2 "github.com/libp2p/go-libp2p/p2p/protocol/circuitv2/relay"
+	"github.com/libp2p/go-libp2p/p2p/protocol/ping"
+
+	dht "github.com/libp2p/go-libp2p-kad-dht"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const ProtocolVersion string = "aicn/0.0.1"
+
+var registerMetricsOnce sync.Once
+
+type Options struct {
+	// Path of the configuration file, which is rewritten by the register and bootstrap APIs
+	ConfigPath string
+	// Version of the program, used as the user agent and by the auto upgrade
+	Version string
+}
+
+// Node is an AI computing node: the libp2p host, the pubsub topic, the HTTP API
+// and the scheduled jobs built from a configuration. The state of the node is
+// passed to the packages it uses, so several nodes can run in a process.
+type Node struct {
+	cfg  *config.Config
+	opts Options
+	// configuration, host, models and pending requests used by the handlers
+	env *serve.Env
+
+	store db.Store
+	blobs *blob.Store
+	host  libp2phost.Host
+	dht   *dht.IpfsDHT
+	topic *pubsub.Topic
+	sub   *pubsub.Subscription
+	pst   *ps.PubSub
+	jobs  *serve.JobManager
+	// scheduler of the model requests received by the worker
+	sched *scheduler.Scheduler
+	// responses of the deterministic model requests sent by the node
+	cache *serve.ResponseCache
+
+	publishChan    chan []byte
+	activeHttpReqs int32
+	bootstrapPeers []peer.AddrInfo
+	relays         []peer.AddrInfo
+	peersHistory   []peer.AddrInfo
+
+	srv       *http.Server
+	listener  net.Listener
+	scheduler gocron.Scheduler
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	// cancel functions of the goroutines, in the order they are stopped
+	pingStopCancel  context.CancelFunc
+	subStopCancel   context.CancelFunc
+	timerStopCancel context.CancelFunc
+	pubStopCancel   context.CancelFunc
+	p2pStopCancel   context.CancelFunc
+	jobsStopCancel  context.CancelFunc
+}
+
+// New builds the node of the configuration, nothing is connected or served until Start.
+func New(cfg *config.Config, opts Options) (*Node, error) {
+	n := &Node{
+		cfg:         cfg,
+		opts:        opts,
+		env:         serve.NewEnv(cfg, nil, model.NewProjectMap(cfg.AIProjects)),
+		publishChan: make(chan []byte, 1024),
+		sched:       scheduler.New(cfg.App.Scheduler),
+		cache:       serve.NewResponseCache(cfg.App.ResponseCache),
+	}
+	n.ctx, n.cancel = context.WithCancel(context.Background())
+
+	var err error
+	n.store, err = db.Open(db.InitOptions{
+		Backend: cfg.App.DatastoreBackend,
+		Folder:  cfg.App.Datastore,
+		// the node is deployed on a public server && enable peers collect
+		EnablePeersCollect: cfg.App.PeersCollect.Enabled,
+	})
+	if err != nil {
+		n.cancel()
+		return nil, fmt.Errorf("init database: %v", err)
+	}
+	imageStoreTTL, _ := time.ParseDuration(cfg.App.ImageStore.TTL)
+	n.blobs, err = blob.Open(filepath.Join(cfg.App.Datastore, "blobs"), imageStoreTTL, cfg.App.ImageStore.MaxSize)
+	if err != nil {
+		n.store.Close()
+		n.cancel()
+		return nil, fmt.Errorf("init blob store: %v", err)
+	}
+
+	if err := n.init(); err != nil {
+		n.close()
+		return nil, err
+	}
+	return n, nil
+}
+
+func (n *Node) init() error {
+	cfg := n.cfg
+	var err error
+	n.peersHistory, err = host.ConvertPeersFromStringMap(n.store.LoadPeerConnHistory())
+	if err != nil {
+		return fmt.Errorf("load peer history: %v", err)
+	}
+
+	n.bootstrapPeers, err = host.ConvertPeersFromStringArray(cfg.Bootstrap)
+	if err != nil {
+		return fmt.Errorf("parse bootstrap: %v", err)
+	}
+	if len(n.bootstrapPeers) < 1 {
+		return errors.New("not enough bootstrap peers")
+	}
+	// the bootstrap nodes are the relays unless the static relays are configured
+	n.relays = n.bootstrapPeers
+	if len(cfg.Swarm.RelayClient.StaticRelays) > 0 {
+		n.relays, err = host.ConvertPeersFromStringArray(cfg.Swarm.RelayClient.StaticRelays)
+		if err != nil {
+			return fmt.Errorf("parse static relays: %v", err)
+		}
+	}
+
+	var p2pCtx context.Context
+	p2pCtx, n.p2pStopCancel = context.WithCancel(n.ctx)
+
+	var pingService *ping.PingService = nil
+
+	privKey, err := cfg.Identity.PrivateKey()
+	if err != nil {
+		return fmt.Errorf("load private key: %v", err)
+	}
+	connGater := conngater.NewConnectionGater(n.store, cfg.App.PeersCollect.ClientProject)
+
+	// https://github.com/ipfs/kubo/issues/9322
+	// https://github.com/ipfs/kubo/pull/9351/files
+	// https://github.com/ipfs/kubo/issues/9432
+	registerMetricsOnce.Do(func() {
+		rcmgr.MustRegisterWith(prometheus.DefaultRegisterer)
+	})
+	strpt, err := rcmgr.NewStatsTraceReporter()
+	if err != nil {
+		return fmt.Errorf("NewStatsTraceReporter: %v", err)
+	}
+	rclimits := rcmgr.DefaultLimits
+	libp2p.SetDefaultServiceLimits(&rclimits)
+	resmgr, err := rcmgr.NewResourceManager(rcmgr.NewFixedLimiter(rclimits.AutoScale()), rcmgr.WithTraceReporter(strpt))
+	if err != nil {
+		return fmt.Errorf("NewResourceManager: %v", err)
+	}
+
+	opts := []libp2p.Option{
+		libp2p.ListenAddrStrings(cfg.Addresses...),
+		libp2p.Identity(privKey),
+		libp2p.DefaultMuxers,
+		libp2p.DefaultSecurity,
+		libp2p.ProtocolVersion(ProtocolVersion),
+		libp2p.UserAgent(n.opts.Version),
+		libp2p.ConnectionGater(connGater),
+		// libp2p.DefaultResourceManager,
+		libp2p.ResourceManager(resmgr),
+	}
+	if cfg.Swarm.ConnMgr.Type == "basic" {
+		gracePeriod, _ := time.ParseDuration(cfg.Swarm.ConnMgr.GracePeriod)
+		connmgr, err := connmgr.NewConnManager(
+			cfg.Swarm.ConnMgr.LowWater,
+			cfg.Swarm.ConnMgr.HighWater,
+			connmgr.WithGracePeriod(gracePeriod),
+		)
+		if err != nil {
+			return fmt.Errorf("create connection manager: %v", err)
+		}
+		opts = append(opts, libp2p.ConnectionManager(connmgr))
+	}
+	if cfg.App.PreSharedKey != "" {
+		psk, err := hex.DecodeString(cfg.App.PreSharedKey)
+		if err != nil {
+			return fmt.Errorf("decoding PSK: %v", err)
+		}
+		opts = append(opts, libp2p.PrivateNetwork(psk), libp2p.DefaultPrivateTransports)
+	} else {
+		opts = append(opts, libp2p.DefaultTransports)
+	}
+	if cfg.Routing.Type != "none" {
+		opts = append(opts, libp2p.Routing(func(h libp2phost.Host) (routing.PeerRouting, error) {
+			dhtOpts := []dht.Option{}
+			if cfg.Routing.Type == "auto" {
+				dhtOpts = append(dhtOpts, dht.Mode(dht.ModeAuto))
+			} else if cfg.Routing.Type == "dhtclient" {
+				dhtOpts = append(dhtOpts, dht.Mode(dht.ModeClient))
+			} else if cfg.Routing.Type == "dhtserver" {
+				dhtOpts = append(dhtOpts, dht.Mode(dht.ModeServer))
+			}
+			if cfg.Routing.ProtocolPrefix != "" {
+				dhtOpts = append(dhtOpts, dht.ProtocolPrefix(protocol.ID(cfg.Routing.ProtocolPrefix)))
+			}
+			dhtOpts = append(dhtOpts, dht.BootstrapPeers(n.bootstrapPeers...))
+			n.dht, err = dht.New(p2pCtx, h, dhtOpts...)
+			return n.dht, err
+		}))
+	}
+	if cfg.Swarm.RelayClient.Enabled {
+		opts = append(opts, libp2p.EnableAutoRelayWithStaticRelays(n.relays))
+	}
+	if cfg.Swarm.RelayService.Enabled {
+		// the default limits of 2 minutes and 128 KB reset the relayed streams of the models
+		duration, _ := time.ParseDuration(cfg.Swarm.RelayService.ConnectionDurationLimit)
+		opts = append(opts, libp2p.EnableRelayService(relayv2.WithLimit(&relayv2.RelayLimit{
+			Duration: duration,
+			Data:     cfg.Swarm.RelayService.ConnectionDataLimit,
+		})))
+	}
+	if cfg.Swarm.RelayService.Enabled {
+		opts = append(opts, libp2p.Ping(false))
+	}
+	if !cfg.Swarm.DisableNatPortMap {
+		opts = append(opts, libp2p.NATPortMap())
+	}
+	if cfg.Swarm.EnableAutoNATService {
+		opts = append(opts, libp2p.EnableNATService())
+	}
+	if cfg.Swarm.EnableHolePunching {
+		opts = append(opts, libp2p.EnableHolePunching())
+	}
+	if dialTimeout, err := time.ParseDuration(cfg.Swarm.DialTimeout); err == nil && dialTimeout != 0 {
+		opts = append(opts, libp2p.WithDialTimeout(dialTimeout))
+	}
+	h, err := libp2p.New(opts...)
+	if err != nil {
+		return fmt.Errorf("create libp2p host: %v", err)
+	}
+	n.host = h
+	hio := &host.HostInfo{
+		Host:            h,
+		UserAgent:       n.opts.Version,
+		ProtocolVersion: ProtocolVersion,
+		PrivKey:         privKey,
+		Relays:          n.relays,
+	}
+	n.env.Host = hio
+	log.Logger.Info("Listen addresses:", h.Addrs())
+	log.Logger.Info("Node id:", h.ID())
+
+	// print the node's PeerInfo in multiaddr format
+	peerInfo := peer.AddrInfo{
+		ID:    h.ID(),
+		Addrs: h.Addrs(),
+	}
+	addrs, err := peer.AddrInfoToP2pAddrs(&peerInfo)
+	log.Logger.Info("libp2p node address:", addrs) // addrs[0]
+
+	h.Network().Notify(&network.NotifyBundle{
+		ConnectedF: func(_ network.Network, c network.Conn) {
+			log.Logger.Infof("OnConnected remote multi-addr %v %v", c.RemoteMultiaddr(), c.RemotePeer())
+			n.store.PeerConnected(c.RemotePeer().String(), c.RemoteMultiaddr().String())
+		},
+		DisconnectedF: func(_ network.Network, c network.Conn) {
+			log.Logger.Infof("OnDisconnected remote multi-addr %v %v", c.RemoteMultiaddr(), c.RemotePeer())
+			// db.PeerDisconnected(c.RemotePeer().String(), c.RemoteMultiaddr().String())
+		},
+	})
+
+	libp2pStream := stream.NewLibp2pStream(cfg, hio, n.env.Models, n.publishChan, n.sched)
+	h.SetStreamHandler(types.ChatProxyProtocol, libp2pStream.ChatProxyStreamHandler)
+	h.SetStreamHandler(host.EncryptionProtocol, host.EncryptionStreamHandler)
+	h.SetStreamHandler(blob.Protocol, n.blobs.StreamHandler)
+
+	if cfg.Swarm.RelayService.Enabled {
+		pingService = &ping.PingService{Host: h}
+		h.SetStreamHandler(ping.ID, pingService.PingHandler)
+	}
+	hio.PingService = pingService
+
+	if cfg.Routing.Type == "none" {
+		dhtOpts := []dht.Option{
+			dht.Mode(dht.ModeClient),
+		}
+		if cfg.Routing.ProtocolPrefix != "" {
+			dhtOpts = append(dhtOpts, dht.ProtocolPrefix(protocol.ID(cfg.Routing.ProtocolPrefix)))
+		}
+		n.dht, err = dht.New(p2pCtx, h, dhtOpts...)
+		if err != nil {
+			return fmt.Errorf("create Kademlia DHT: %v", err)
+		}
+	}
+
+	psOpts := []pubsub.Option{
+		// pubsub.WithDiscovery(routingDiscovery),
+		pubsub.WithEventTracer(&ps.Tracer{}),
+		// pubsub.WithRawTracer(&ps.RawTracer{}),
+	}
+	var gs *pubsub.PubSub
+
+	if cfg.Pubsub.Router == "gossipsub" {
+		psOpts = append(psOpts,
+			pubsub.WithDirectPeers(n.bootstrapPeers),
+			pubsub.WithDirectConnectTicks(30),
+		)
+		if cfg.Routing.Type == "dhtserver" || cfg.Swarm.RelayService.Enabled {
+			psOpts = append(psOpts, pubsub.WithPeerExchange(true))
+		}
+		if cfg.Pubsub.FloodPublish {
+			psOpts = append(psOpts, pubsub.WithFloodPublish(true))
+		}
+		gs, err = pubsub.NewGossipSub(p2pCtx, h, psOpts...)
+	} else {
+		gs, err = pubsub.NewFloodSub(p2pCtx, h, psOpts...)
+	}
+	if err != nil {
+		return fmt.Errorf("new %s: %v", cfg.Pubsub.Router, err)
+	}
+
+	n.topic, err = gs.Join(cfg.App.TopicName)
+	if err != nil {
+		return fmt.Errorf("join PubSub: %v", err)
+	}
+	n.sub, err = n.topic.Subscribe()
+	if err != nil {
+		return fmt.Errorf("subscribe PubSub: %v", err)
+	}
+	hio.Dht = n.dht
+	hio.Topic = n.topic
+	n.pst = ps.NewPubSub(n.env, n.topic, n.sub, n.publishChan, n.store, n.blobs, n.sched)
+
+	var jobsCtx context.Context
+	jobsCtx, n.jobsStopCancel = context.WithCancel(n.ctx)
+	n.jobs = serve.NewJobManager(jobsCtx, n.env, n.publishChan, n.store, n.blobs)
+
+	n.srv = &http.Server{
+		Addr:    cfg.API.Addr,
+		Handler: n.newRouter(),
+		// ReadTimeout:  120 * time.Second,
+		// WriteTimeout: 120 * time.Second,
+		// IdleTimeout:  120 * time.Second,
+	}
+	return n.newScheduler()
+}
+
+func (n *Node) newScheduler() error {
+	heartbeatInterval, _ := time.ParseDuration(n.cfg.App.PeersCollect.HeartbeatInterval)
+	remoteCacheTTL, _ := time.ParseDuration(n.cfg.App.RemoteQuery.CacheTTL)
+	jobsTTL, _ := time.ParseDuration(n.cfg.App.Jobs.TTL)
+	var timerCtx context.Context
+	timerCtx, n.timerStopCancel = context.WithCancel(n.ctx)
+	var err error
+	n.scheduler, err = gocron.NewScheduler()
+	if err != nil {
+		return fmt.Errorf("NewScheduler failed: %v", err)
+	}
+	job1, err := n.scheduler.NewJob(
+		gocron.DurationJob(heartbeatInterval),
+		gocron.NewTask(
+			func(pcn chan<- []byte) {
+				timer.SendAIProjects(pcn, n.cfg, n.env.Host, n.env.Models)
+				n.store.CleanExpiredPeerCollectInfo()
+				n.store.CleanExpiredRemoteCache(remoteCacheTTL)
+				n.store.CleanExpiredJobs(jobsTTL)
+				n.blobs.CleanExpired()
+				n.cache.CleanExpired()
+			},
+			n.publishChan,
+		),
+	)
+	if err != nil {
+		return fmt.Errorf("create scheduled ai projects job failed: %v", err)
+	}
+	log.Logger.Infof("Scheduled ai projects job: %v", job1.ID())
+	if n.cfg.App.AutoUpgrade.Enabled {
+		upgraderInterval, _ := time.ParseDuration(n.cfg.App.AutoUpgrade.TimeInterval)
+		job2, err := n.scheduler.NewJob(
+			gocron.DurationJob(upgraderInterval),
+			gocron.NewTask(
+				func(ctx context.Context, timeout time.Duration, cur_version string) {
+					upgradeCtx, pgradeCancel := context.WithTimeout(ctx, timeout)
+					defer pgradeCancel()
+					selfupdate.UpdateGithubLatestRelease(upgradeCtx, cur_version, &n.activeHttpReqs, n.env.Models)
+				},
+				timerCtx,
+				upgraderInterval,
+				n.opts.Version,
+			),
+		)
+		if err != nil {
+			return fmt.Errorf("create scheduled selftupdate job failed: %v", err)
+		}
+		log.Logger.Infof("Scheduled selfupdate job: %v", job2.ID())
+	}
+	return nil
+}
+
+// Start connects the node to the network and starts serving the HTTP API.
+func (n *Node) Start() error {
+	cfg := n.cfg
+	h := n.host
+	p2pCtx := n.ctx
+
+	// Let's connect to the bootstrap nodes first. They will tell us about the
+	// other nodes in the network.
+	errs := make(chan error, len(n.bootstrapPeers))
+	mapBootstrapIds := make(map[peer.ID]struct{})
+	var wg sync.WaitGroup
+	for _, peerinfo := range n.bootstrapPeers {
+		mapBootstrapIds[peerinfo.ID] = struct{}{}
+		if peerinfo.ID == h.ID() {
+			continue
+		}
+
+		wg.Add(1)
+		go func(pi peer.AddrInfo) {
+			defer wg.Done()
+
+			h.Peerstore().AddAddrs(pi.ID, pi.Addrs, peerstore.PermanentAddrTTL)
+			if err := h.Connect(p2pCtx, pi); err != nil {
+				log.Logger.Warnf("Connect bootstrap node %v : %v", pi, err)
+				errs <- err
+				return
+			}
+			log.Logger.Info("Connection established with bootstrap node:", pi)
+		}(peerinfo)
+	}
+	wg.Wait()
+
+	// our failure condition is when no connection attempt succeeded.
+	// So drain the errs channel, counting the results.
+	close(errs)
+	errCount := 0
+	var err error
+	for err = range errs {
+		if err != nil {
+			errCount++
+		}
+	}
+	if errCount == len(n.bootstrapPeers) {
+		// log.Logger.Fatalf("Failed to bootstrap. %s", err)
+		log.Logger.Warnf("Failed to bootstrap. %s", err)
+	}
+
+	if !cfg.Swarm.RelayService.Enabled {
+		for _, peerinfo := range n.peersHistory {
+			if _, ok := mapBootstrapIds[peerinfo.ID]; ok {
+				continue
+			}
+
+			wg.Add(1)
+			go func(pi peer.AddrInfo) {
+				defer wg.Done()
+				if h.Network().Connectedness(pi.ID) == network.Connected {
+					return
+				}
+				ispub := host.IsPublicNode(pi)
+				if ispub {
+					h.Peerstore().AddAddrs(pi.ID, pi.Addrs, peerstore.PermanentAddrTTL)
+				}
+				if err := h.Connect(p2pCtx, pi); err != nil {
+					log.Logger.Warnf("Connect history node %v : %v", pi, err)
+					n.store.PeerConnectFailed(pi.ID.String())
+					return
+				}
+				log.Logger.Info("Connection established with history node:", pi)
+			}(peerinfo)
+		}
+		wg.Wait()
+	}
+
+	if err := n.dht.Bootstrap(p2pCtx); err != nil {
+		return fmt.Errorf("bootstrap the host: %v", err)
+	}
+
+	routingDiscovery := drouting.NewRoutingDiscovery(n.dht)
+	dutil.Advertise(p2pCtx, routingDiscovery, cfg.App.TopicName)
+	n.env.Host.RD = routingDiscovery
+
+	n.listener, err = net.Listen("tcp", cfg.API.Addr)
+	if err != nil {
+		return fmt.Errorf("start HTTP Server: %v", err)
+	}
+
+	var pubCtx, subCtx, pingCtx context.Context
+	pubCtx, n.pubStopCancel = context.WithCancel(n.ctx)
+	subCtx, n.subStopCancel = context.WithCancel(n.ctx)
+	pingCtx, n.pingStopCancel = context.WithCancel(n.ctx)
+
+	go n.pst.PublishToTopic(pubCtx)
+	n.scheduler.Start()
+	go n.pst.ReadFromTopic(subCtx)
+	n.jobs.Resume()
+	go func() {
+		log.Logger.Info("HTTP server is running on http://", n.listener.Addr())
+		if err := n.srv.Serve(n.listener); err != nil && err != http.ErrServerClosed {
+			log.Logger.Errorf("Start HTTP Server: %v", err)
+		}
+		log.Logger.Info("HTTP server is stopped")
+	}()
+	n.env.Host.StartPingService(pingCtx)
+	if cfg.Swarm.RelayClient.Enabled {
+		go host.WatchRelayReservations(pingCtx, h)
+	}
+
+	log.Logger.Info("listening for connections")
+	return nil
+}
+
+// Stop shuts down the HTTP API, the scheduled jobs and the libp2p host, and closes the datastore.
+func (n *Node) Stop() error {
+	// Stop PingService
+	if n.pingStopCancel != nil {
+		n.pingStopCancel()
+	}
+	var result error
+	if n.listener != nil {
+		httpStopCtx, httpStopCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer httpStopCancel()
+		if err := n.srv.Shutdown(httpStopCtx); err != nil {
+			log.Logger.Errorf("Shutdown HTTP Server: %v", err)
+			result = err
+		} else {
+			log.Logger.Info("HTTP server is shutdown gracefully")
+		}
+	}
+	// the running jobs are resumed when the node restarts
+	n.jobsStopCancel()
+	n.jobs.Wait()
+	if n.subStopCancel != nil {
+		n.subStopCancel()
+	}
+	n.timerStopCancel()
+	if err := n.scheduler.Shutdown(); err != nil {
+		log.Logger.Errorf("Error shutdowning scheduler: %v", err)
+	}
+	if n.pubStopCancel != nil {
+		n.pubStopCancel()
+	}
+	n.close()
+	return result
+}
+
+// close releases what New has created.
+func (n *Node) close() {
+	if n.topic != nil {
+		n.sub.Cancel()
+		n.topic.Close()
+	}
+	if n.p2pStopCancel != nil {
+		n.p2pStopCancel()
+	}
+	if n.dht != nil {
+		if err := n.dht.Close(); err != nil {
+			log.Logger.Errorf("Error closing kadDHT: %v", err)
+		}
+	}
+	if n.host != nil {
+		if err := n.host.Close(); err != nil {
+			log.Logger.Errorf("Error closing host: %v", err)
+		}
+	}
+	n.cancel()
+	if err := n.store.Close(); err != nil {
+		log.Logger.Errorf("Error closing datastore: %v", err)
+	}
+}
+
+// ID returns the peer id of the node.
+func (n *Node) ID() string {
+	return n.host.ID().String()
+}
+
+// P2pAddrs returns the listen addresses of the node including the peer id.
+func (n *Node) P2pAddrs() []string {
+	addrs, _ := peer.AddrInfoToP2pAddrs(&peer.AddrInfo{
+		ID:    n.host.ID(),
+		Addrs: n.host.Addrs(),
+	})
+	result := make([]string, 0, len(addrs))
+	for _, addr := range addrs {
+		result = append(result, addr.String())
+	}
+	return result
+}
+
+// APIAddr returns the address the HTTP API is listening on, valid after Start.
+func (n *Node) APIAddr() string {
+	if n.listener == nil {
+		return ""
+	}
+	return n.listener.Addr().String()
+}
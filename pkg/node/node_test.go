@@ -0,0 +1,762 @@
+package node_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/blob"
+	"AIComputingNode/pkg/keystore"
+	"AIComputingNode/pkg/node/nodetest"
+	"AIComputingNode/pkg/types"
+)
+
+const (
+	chatProject  = "DecentralGPT"
+	chatModel    = "Llama3-70B"
+	imageProject = "SuperImageAI"
+	genModel     = "SuperImage"
+	editModel    = "SuperImageEdit"
+	embedProject = "SuperEmbedding"
+	embedModel   = "bge-m3"
+	audioProject = "SuperAudio"
+	sttModel     = "whisper-1"
+	ttsModel     = "tts-1"
+)
+
+func TestMain(m *testing.M) {
+	nodetest.Main(m)
+}
+
+type cluster struct {
+	collector *nodetest.Node
+	workers   []*nodetest.Node
+	backends  []*nodetest.Backend
+	input     *nodetest.Node
+}
+
+// startCluster starts a collector which is the bootstrap node of the others,
+// two workers serving the models with fake backends and an input node.
+func startCluster(t *testing.T) *cluster {
+	c := &cluster{}
+
+	cfg := nodetest.NewConfig(t)
+	cfg.Routing.Type = "dhtserver"
+	cfg.App.PeersCollect.Enabled = true
+	c.collector = nodetest.Start(t, cfg)
+
+	for i := 0; i < 2; i++ {
+		backend := nodetest.NewBackend(t, fmt.Sprintf("worker-%d", i))
+		cfg := nodetest.NewConfig(t)
+		cfg.Bootstrap = []string{c.collector.P2pAddr}
+		cfg.AIProjects = []types.AIProjectConfig{
+			{
+				Project: chatProject,
+				Models: []types.AIModelConfig{
+					{Model: chatModel, API: backend.ChatAPI(), Type: 0},
+				},
+			},
+			{
+				Project: imageProject,
+				Models: []types.AIModelConfig{
+					{Model: genModel, API: backend.ImageGenAPI(), Type: 1},
+					{Model: editModel, API: backend.ImageEditAPI(), Type: 1},
+				},
+			},
+			{
+				Project: embedProject,
+				Models: []types.AIModelConfig{
+					{Model: embedModel, API: backend.EmbeddingAPI(), Type: types.ModelTypeEmbedding},
+				},
+			},
+			{
+				Project: audioProject,
+				Models: []types.AIModelConfig{
+					{Model: sttModel, API: backend.TranscriptionAPI(), Type: types.ModelTypeAudioTranscription},
+					{Model: ttsModel, API: backend.SpeechAPI(), Type: types.ModelTypeAudioSpeech},
+				},
+			},
+		}
+		if i == 1 {
+			// one running and one queued request at most, see the Scheduler subtest
+			cfg.App.Scheduler.MaxRunning = 1
+			cfg.App.Scheduler.MaxQueued = 1
+		}
+		c.workers = append(c.workers, nodetest.Start(t, cfg))
+		c.backends = append(c.backends, backend)
+	}
+
+	cfg = nodetest.NewConfig(t)
+	cfg.Bootstrap = []string{c.collector.P2pAddr}
+	cfg.App.ResponseCache.Projects = []string{chatProject}
+	// the webhooks of the tests listen on the loopback address
+	cfg.App.Jobs.WebhookAllowedHosts = []string{"127.0.0.1"}
+	c.input = nodetest.Start(t, cfg)
+	return c
+}
+
+func (c *cluster) backendOf(id string) *nodetest.Backend {
+	for i, worker := range c.workers {
+		if worker.ID == id {
+			return c.backends[i]
+		}
+	}
+	return nil
+}
+
+func userMessages(content string) []types.ChatCompletionMessage {
+	text, _ := json.Marshal(content)
+	return []types.ChatCompletionMessage{{Role: "user", Content: text}}
+}
+
+func TestCluster(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping multi-node test in short mode")
+	}
+	c := startCluster(t)
+
+	t.Run("Heartbeat", func(t *testing.T) {
+		query := url.Values{"project": {chatProject}, "model": {chatModel}, "number": {"10"}}
+		var rsp types.GetPeersOfAIProjectResponse
+		nodetest.WaitFor(t, 30*time.Second, "heartbeats of the workers", func() bool {
+			rsp = types.GetPeersOfAIProjectResponse{}
+			if err := c.collector.Get("/api/v0/ai/projects/peers?"+query.Encode(), &rsp); err != nil {
+				return false
+			}
+			return rsp.Code == 0 && len(rsp.Data) == len(c.workers)
+		})
+		for _, peer := range rsp.Data {
+			if c.backendOf(peer.NodeID) == nil {
+				t.Errorf("Unexpected peer %s of %s/%s", peer.NodeID, chatProject, chatModel)
+			}
+		}
+	})
+
+	t.Run("ChatCompletion", func(t *testing.T) {
+		for i, worker := range c.workers {
+			req := types.ChatCompletionRequest{
+				NodeID:  worker.ID,
+				Project: chatProject,
+				ChatModelRequest: types.ChatModelRequest{
+					Model:    chatModel,
+					Messages: userMessages("Hello"),
+				},
+			}
+			var rsp types.ChatCompletionResponse
+			if err := c.input.Post("/api/v0/chat/completion", req, &rsp); err != nil {
+				t.Fatalf("Chat completion: %v", err)
+			}
+			if rsp.Code != 0 {
+				t.Fatalf("Chat completion: {code:%d, message:%s}", rsp.Code, rsp.Message)
+			}
+			if len(rsp.Choices) != 1 || rsp.Choices[0].Message.Content != c.backends[i].ChatReply() {
+				t.Errorf("Chat completion of %s: %+v", worker.ID, rsp.Choices)
+			}
+		}
+	})
+
+	t.Run("ModelHistory", func(t *testing.T) {
+		var rsp types.ModelHistoryResponse
+		if err := c.workers[0].Get("/api/v0/ai/history?number=10", &rsp); err != nil {
+			t.Fatalf("Model history: %v", err)
+		}
+		if len(rsp.Data) != 1 || rsp.Data[0].ReqNodeId != c.input.ID || rsp.Data[0].Model != chatModel {
+			t.Errorf("Unexpected model history %+v", rsp.Data)
+		}
+	})
+
+	t.Run("ImageGeneration", func(t *testing.T) {
+		worker, backend := c.workers[1], c.backends[1]
+		req := types.ImageGenerationRequest{
+			NodeID:  worker.ID,
+			Project: imageProject,
+			ImageGenModelRequest: types.ImageGenModelRequest{
+				Model:  genModel,
+				Prompt: "cat",
+				Number: 1,
+				Size:   "1024x1024",
+			},
+		}
+		var rsp types.ImageGenerationResponse
+		if err := c.input.Post("/api/v0/image/gen", req, &rsp); err != nil {
+			t.Fatalf("Image generation: %v", err)
+		}
+		if rsp.Code != 0 {
+			t.Fatalf("Image generation: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+		if len(rsp.Choices) != 1 || rsp.Choices[0].Url != backend.ImageURL("cat") {
+			t.Errorf("Image generation of %s: %+v", worker.ID, rsp.Choices)
+		}
+	})
+
+	t.Run("ImageGenerationJob", func(t *testing.T) {
+		worker, backend := c.workers[1], c.backends[1]
+		webhook := make(chan types.Job, 1)
+		hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var job types.Job
+			json.NewDecoder(r.Body).Decode(&job)
+			webhook <- job
+		}))
+		defer hook.Close()
+
+		igReq, _ := json.Marshal(types.ImageGenerationRequest{
+			NodeID:  worker.ID,
+			Project: imageProject,
+			ImageGenModelRequest: types.ImageGenModelRequest{
+				Model:  genModel,
+				Prompt: "dog",
+				Number: 1,
+				Size:   "1024x1024",
+			},
+		})
+		req := types.JobRequest{Type: types.JobTypeImageGen, Webhook: hook.URL, Request: igReq}
+		var rsp types.JobResponse
+		if err := c.input.Post("/api/v0/jobs", req, &rsp); err != nil {
+			t.Fatalf("Submit job: %v", err)
+		}
+		if rsp.Code != 0 || rsp.Data == nil {
+			t.Fatalf("Submit job: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+		id := rsp.Data.ID
+
+		// the events end when the job is finished
+		resp, err := http.Get(c.input.API + "/api/v0/jobs/" + id + "/events")
+		if err != nil {
+			t.Fatalf("Job events: %v", err)
+		}
+		events, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			t.Fatalf("Read job events: %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(string(events)), "\n")
+		var last types.Job
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(lines[len(lines)-1], "data: ")), &last); err != nil {
+			t.Fatalf("Unexpected job events %q", events)
+		}
+		if last.Status != types.JobStatusSucceeded || last.Progress != types.JobProgressFinished {
+			t.Errorf("Unexpected last job event %+v", last)
+		}
+
+		rsp = types.JobResponse{}
+		if err := c.input.Get("/api/v0/jobs/"+id, &rsp); err != nil {
+			t.Fatalf("Get job: %v", err)
+		}
+		var result types.ImageGenerationResponse
+		if rsp.Data == nil || json.Unmarshal(rsp.Data.Result, &result) != nil {
+			t.Fatalf("Unexpected job %+v", rsp)
+		}
+		if len(result.Choices) != 1 || result.Choices[0].Url != backend.ImageURL("dog") {
+			t.Errorf("Image generation job of %s: %+v", worker.ID, result.Choices)
+		}
+
+		select {
+		case job := <-webhook:
+			if job.ID != id || job.Status != types.JobStatusSucceeded {
+				t.Errorf("Unexpected webhook job %+v", job)
+			}
+		case <-time.After(10 * time.Second):
+			t.Error("Timeout waiting for the webhook")
+		}
+
+		rsp = types.JobResponse{}
+		if err := c.input.Get("/api/v0/jobs/not-exist", &rsp); err != nil {
+			t.Fatalf("Get job: %v", err)
+		}
+		if rsp.Code != int(types.ErrCodeNotFound) {
+			t.Errorf("Get not existed job: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+	})
+
+	t.Run("ImageEdit", func(t *testing.T) {
+		worker, backend := c.workers[0], c.backends[0]
+		// image edit is proxied through a stream which needs a direct connection
+		c.input.Connect(t, worker)
+		query := url.Values{"node_id": {worker.ID}, "project": {imageProject}, "model": {editModel}}
+		var rsp types.ImageGenerationResponse
+		if err := c.input.PostFile("/api/v0/image/edit?"+query.Encode(), "image", "dog", []byte("fake png"), &rsp); err != nil {
+			t.Fatalf("Image edit: %v", err)
+		}
+		if rsp.Code != 0 {
+			t.Fatalf("Image edit: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+		if len(rsp.Choices) != 1 || rsp.Choices[0].Url != backend.ImageURL("dog") {
+			t.Errorf("Image edit of %s: %+v", worker.ID, rsp.Choices)
+		}
+	})
+
+	t.Run("ChatCompletionProxy", func(t *testing.T) {
+		req := types.ChatCompletionProxyRequest{
+			Project: chatProject,
+			ChatModelRequest: types.ChatModelRequest{
+				Model:    chatModel,
+				Messages: userMessages("Hello"),
+			},
+		}
+		var rsp types.ChatCompletionResponse
+		if err := c.collector.Post("/api/v0/chat/completion/proxy", req, &rsp); err != nil {
+			t.Fatalf("Chat completion proxy: %v", err)
+		}
+		if rsp.Code != 0 {
+			t.Fatalf("Chat completion proxy: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+		if len(rsp.Choices) != 1 || !isChatReply(c, rsp.Choices[0].Message.Content) {
+			t.Errorf("Chat completion proxy: %+v", rsp.Choices)
+		}
+	})
+
+	t.Run("ImageGenerationProxy", func(t *testing.T) {
+		req := types.ImageGenerationProxyRequest{
+			Project: imageProject,
+			ImageGenModelRequest: types.ImageGenModelRequest{
+				Model:  genModel,
+				Prompt: "bird",
+				Number: 1,
+				Size:   "1024x1024",
+			},
+		}
+		var rsp types.ImageGenerationResponse
+		if err := c.collector.Post("/api/v0/image/gen/proxy", req, &rsp); err != nil {
+			t.Fatalf("Image generation proxy: %v", err)
+		}
+		if rsp.Code != 0 {
+			t.Fatalf("Image generation proxy: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+		if len(rsp.Choices) != 1 {
+			t.Fatalf("Image generation proxy: %+v", rsp.Choices)
+		}
+		matched := false
+		for _, backend := range c.backends {
+			matched = matched || rsp.Choices[0].Url == backend.ImageURL("bird")
+		}
+		if !matched {
+			t.Errorf("Image generation proxy: %+v", rsp.Choices)
+		}
+	})
+
+	t.Run("Embedding", func(t *testing.T) {
+		worker, backend := c.workers[1], c.backends[1]
+		req := types.EmbeddingRequest{
+			NodeID:  worker.ID,
+			Project: embedProject,
+			EmbeddingModelRequest: types.EmbeddingModelRequest{
+				Model: embedModel,
+				Input: types.EmbeddingInput{"cat", "bird"},
+			},
+		}
+		var rsp types.EmbeddingResponse
+		if err := c.input.Post("/api/v0/embeddings", req, &rsp); err != nil {
+			t.Fatalf("Embedding: %v", err)
+		}
+		if rsp.Code != 0 {
+			t.Fatalf("Embedding: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+		if len(rsp.Data) != 2 || rsp.Data[1].Index != 1 ||
+			!slices.Equal(rsp.Data[1].Embedding, backend.Embedding("bird")) || rsp.Usage.TotalTokens != 2 {
+			t.Errorf("Embedding of %s: %+v", worker.ID, rsp)
+		}
+	})
+
+	t.Run("EmbeddingProxy", func(t *testing.T) {
+		req := types.EmbeddingProxyRequest{
+			Project: embedProject,
+			EmbeddingModelRequest: types.EmbeddingModelRequest{
+				Model: embedModel,
+				Input: types.EmbeddingInput{"fish"},
+			},
+		}
+		var rsp types.EmbeddingResponse
+		if err := c.collector.Post("/api/v0/embeddings/proxy", req, &rsp); err != nil {
+			t.Fatalf("Embedding proxy: %v", err)
+		}
+		if rsp.Code != 0 {
+			t.Fatalf("Embedding proxy: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+		if len(rsp.Data) != 1 || !slices.Equal(rsp.Data[0].Embedding, c.backends[0].Embedding("fish")) {
+			t.Errorf("Embedding proxy: %+v", rsp.Data)
+		}
+
+		// the chat model is not advertised as an embedding model
+		req.Project, req.Model = chatProject, chatModel
+		rsp = types.EmbeddingResponse{}
+		if err := c.collector.Post("/api/v0/embeddings/proxy", req, &rsp); err != nil {
+			t.Fatalf("Embedding proxy: %v", err)
+		}
+		if rsp.Code != int(types.ErrCodeProxy) {
+			t.Errorf("Embedding proxy of chat model: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+	})
+
+	t.Run("AudioTranscription", func(t *testing.T) {
+		// worker 0 is reached through a stream since the image edit, worker 1 through pubsub
+		for i, worker := range c.workers {
+			query := url.Values{"node_id": {worker.ID}, "project": {audioProject}, "model": {sttModel}}
+			var rsp struct {
+				Text string `json:"text"`
+			}
+			if err := c.input.PostFile("/api/v0/audio/transcriptions?"+query.Encode(), "file", "hello.mp3", []byte("fake mp3"), &rsp); err != nil {
+				t.Fatalf("Audio transcription: %v", err)
+			}
+			if rsp.Text != c.backends[i].Transcription("hello.mp3") {
+				t.Errorf("Audio transcription of %s: %+v", worker.ID, rsp)
+			}
+		}
+	})
+
+	t.Run("AudioSpeech", func(t *testing.T) {
+		for i, worker := range c.workers {
+			req := types.AudioSpeechRequest{
+				NodeID:  worker.ID,
+				Project: audioProject,
+				AudioSpeechModelRequest: types.AudioSpeechModelRequest{
+					Model: ttsModel,
+					Input: "hello",
+					Voice: "alloy",
+				},
+			}
+			var audio []byte
+			if err := c.input.Post("/api/v0/audio/speech", req, &audio); err != nil {
+				t.Fatalf("Audio speech: %v", err)
+			}
+			if !bytes.Equal(audio, c.backends[i].Speech("hello")) {
+				t.Errorf("Audio speech of %s: %q", worker.ID, audio)
+			}
+		}
+	})
+
+	t.Run("AudioSpeechProxy", func(t *testing.T) {
+		req := types.AudioSpeechProxyRequest{
+			Project: audioProject,
+			AudioSpeechModelRequest: types.AudioSpeechModelRequest{
+				Model: ttsModel,
+				Input: "world",
+				Voice: "alloy",
+			},
+		}
+		var audio []byte
+		if err := c.collector.Post("/api/v0/audio/speech/proxy", req, &audio); err != nil {
+			t.Fatalf("Audio speech proxy: %v", err)
+		}
+		if !bytes.Equal(audio, c.backends[0].Speech("world")) && !bytes.Equal(audio, c.backends[1].Speech("world")) {
+			t.Errorf("Audio speech proxy: %q", audio)
+		}
+
+		// the transcription model does not answer the speech requests
+		req.Model = sttModel
+		var rsp types.BaseHttpResponse
+		if err := c.collector.Post("/api/v0/audio/speech/proxy", req, &rsp); err != nil {
+			t.Fatalf("Audio speech proxy: %v", err)
+		}
+		if rsp.Code != int(types.ErrCodeProxy) {
+			t.Errorf("Audio speech proxy of transcription model: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+	})
+
+	t.Run("LargeMessage", func(t *testing.T) {
+		// the request is over the 1 MB pubsub limit and sent in chunks
+		req := types.ChatCompletionRequest{
+			NodeID:  c.workers[1].ID,
+			Project: chatProject,
+			ChatModelRequest: types.ChatModelRequest{
+				Model:    chatModel,
+				Messages: userMessages(strings.Repeat("large image ", 300*1024)),
+			},
+		}
+		var rsp types.ChatCompletionResponse
+		if err := c.input.Post("/api/v0/chat/completion", req, &rsp); err != nil {
+			t.Fatalf("Chat completion: %v", err)
+		}
+		if rsp.Code != 0 {
+			t.Fatalf("Chat completion: {code:%d, message:%s}", rsp.Code, rsp.Message)
+		}
+		if len(rsp.Choices) != 1 || rsp.Choices[0].Message.Content != c.backends[1].ChatReply() {
+			t.Errorf("Chat completion of %s: %+v", c.workers[1].ID, rsp.Choices)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		worker, backend := c.workers[0], c.backends[0]
+		for i, stream := range []bool{false, true} {
+			req := types.ChatCompletionRequest{
+				NodeID:  worker.ID,
+				Project: chatProject,
+				ChatModelRequest: types.ChatModelRequest{
+					Model:    chatModel,
+					Messages: userMessages(nodetest.HangPrompt),
+					Stream:   stream,
+				},
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			var rsp types.ChatCompletionResponse
+			if err := c.input.PostContext(ctx, "/api/v0/chat/completion", req, &rsp); err == nil {
+				t.Errorf("Hanging chat completion answered: %+v", rsp)
+			}
+			cancel()
+			nodetest.WaitFor(t, 10*time.Second, "the backend request canceled", func() bool {
+				return backend.Canceled() == i+1
+			})
+		}
+
+		// only the pubsub requests are in the model history
+		var rsp types.ModelHistoryResponse
+		nodetest.WaitFor(t, 10*time.Second, "the canceled request in the model history", func() bool {
+			rsp = types.ModelHistoryResponse{}
+			if err := worker.Get("/api/v0/ai/history?number=10", &rsp); err != nil {
+				return false
+			}
+			return slices.ContainsFunc(rsp.Data, func(h types.ModelHistory) bool {
+				return h.Canceled && h.ReqNodeId == c.input.ID
+			})
+		})
+	})
+
+	t.Run("Scheduler", func(t *testing.T) {
+		worker := c.workers[1]
+		post := func(ctx context.Context, prompt string) types.ChatCompletionResponse {
+			req := types.ChatCompletionRequest{
+				NodeID:  worker.ID,
+				Project: chatProject,
+				ChatModelRequest: types.ChatModelRequest{
+					Model:    chatModel,
+					Messages: userMessages(prompt),
+				},
+			}
+			var rsp types.ChatCompletionResponse
+			c.input.PostContext(ctx, "/api/v0/chat/completion", req, &rsp)
+			return rsp
+		}
+		waitState := func(what string, running, queued int) types.SchedulerState {
+			var rsp types.SchedulerResponse
+			nodetest.WaitFor(t, 10*time.Second, what, func() bool {
+				rsp = types.SchedulerResponse{}
+				if err := worker.Get("/api/v0/scheduler", &rsp); err != nil {
+					return false
+				}
+				return len(rsp.Data.Running) == running && len(rsp.Data.Queued) == queued
+			})
+			return rsp.Data
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		for i := 0; i < 2; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				post(ctx, nodetest.HangPrompt)
+			}()
+			waitState("the hanging requests scheduled", 1, i)
+		}
+		state := waitState("the hanging requests scheduled", 1, 1)
+		if r := state.Queued[0]; r.Requester != c.input.ID || r.Class != "default" || r.Type != "CHAT_COMPLETION" {
+			t.Errorf("Unexpected queued request %+v", r)
+		}
+
+		// the queue is full and the request of the same requester runs last
+		if rsp := post(context.Background(), "hello"); rsp.Code != int(types.ErrCodeBusy) {
+			t.Errorf("Expected busy worker but got %+v", rsp)
+		}
+		cancel()
+		wg.Wait()
+		if state := waitState("the canceled requests leaving the scheduler", 0, 0); state.Rejected != 1 {
+			t.Errorf("Expected one rejected request but got %+v", state)
+		}
+	})
+
+	t.Run("ResponseCache", func(t *testing.T) {
+		temperature := float32(0)
+		post := func(t *testing.T, temperature *float32) (string, types.ChatCompletionResponse) {
+			req := types.ChatCompletionRequest{
+				NodeID:  c.workers[0].ID,
+				Project: chatProject,
+				ChatModelRequest: types.ChatModelRequest{
+					Model:       chatModel,
+					Messages:    userMessages("cached"),
+					ChatOptions: types.ChatOptions{Temperature: temperature},
+				},
+			}
+			body, _ := json.Marshal(req)
+			resp, err := http.Post(c.input.API+"/api/v0/chat/completion", "application/json", bytes.NewReader(body))
+			if err != nil {
+				t.Fatalf("Post chat completion failed %v", err)
+			}
+			defer resp.Body.Close()
+			var rsp types.ChatCompletionResponse
+			if err := json.NewDecoder(resp.Body).Decode(&rsp); err != nil || rsp.Code != 0 {
+				t.Fatalf("Unexpected response %+v %v", rsp, err)
+			}
+			return resp.Header.Get("X-Cache"), rsp
+		}
+
+		cache, first := post(t, &temperature)
+		if cache != "MISS" {
+			t.Errorf("Expected cache miss but got %q", cache)
+		}
+		cache, second := post(t, &temperature)
+		if cache != "HIT" || second.Id != first.Id {
+			t.Errorf("Expected cache hit of %s but got %q %s", first.Id, cache, second.Id)
+		}
+		if cache, _ := post(t, nil); cache != "BYPASS" {
+			t.Errorf("Expected cache bypass but got %q", cache)
+		}
+
+		resp, err := http.Get(c.input.API + "/api/v0/debug/metrics/prometheus")
+		if err != nil {
+			t.Fatalf("Get metrics failed %v", err)
+		}
+		defer resp.Body.Close()
+		metrics, _ := io.ReadAll(resp.Body)
+		if !strings.Contains(string(metrics), `aicn_response_cache_requests_total{project="`+chatProject+`",result="hit"}`) {
+			t.Error("No cache hit in the metrics")
+		}
+	})
+}
+
+func isChatReply(c *cluster, content string) bool {
+	for _, backend := range c.backends {
+		if content == backend.ChatReply() {
+			return true
+		}
+	}
+	return false
+}
+
+// TestKeyTypes sends encrypted requests between the nodes of every key type.
+func TestKeyTypes(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping multi-node test in short mode")
+	}
+	cfg := nodetest.NewConfig(t)
+	cfg.Routing.Type = "dhtserver"
+	collector := nodetest.Start(t, cfg)
+
+	var nodes []*nodetest.Node
+	var backends []*nodetest.Backend
+	for _, keyType := range keystore.KeyTypes {
+		backend := nodetest.NewBackend(t, keyType)
+		cfg := nodetest.NewConfigWithKeyType(t, keyType)
+		cfg.Bootstrap = []string{collector.P2pAddr}
+		cfg.AIProjects = []types.AIProjectConfig{
+			{
+				Project: chatProject,
+				Models: []types.AIModelConfig{
+					{Model: chatModel, API: backend.ChatAPI(), Type: 0},
+				},
+			},
+		}
+		nodes = append(nodes, nodetest.Start(t, cfg))
+		backends = append(backends, backend)
+	}
+
+	for i, from := range nodes {
+		for j, to := range nodes {
+			if i == j {
+				continue
+			}
+			name := fmt.Sprintf("%s to %s", keystore.KeyTypes[i], keystore.KeyTypes[j])
+			req := types.ChatCompletionRequest{
+				NodeID:  to.ID,
+				Project: chatProject,
+				ChatModelRequest: types.ChatModelRequest{
+					Model:    chatModel,
+					Messages: userMessages("Hello"),
+				},
+			}
+			var rsp types.ChatCompletionResponse
+			// the RSA keys are learned from the heartbeats of the nodes
+			nodetest.WaitFor(t, 30*time.Second, "public key of "+name, func() bool {
+				rsp = types.ChatCompletionResponse{}
+				if err := from.Post("/api/v0/chat/completion", req, &rsp); err != nil {
+					t.Fatalf("Chat completion %s: %v", name, err)
+				}
+				return rsp.Code != int(types.ErrCodeEncrypt)
+			})
+			if rsp.Code != 0 {
+				t.Errorf("Chat completion %s: {code:%d, message:%s}", name, rsp.Code, rsp.Message)
+			} else if len(rsp.Choices) != 1 || rsp.Choices[0].Message.Content != backends[j].ChatReply() {
+				t.Errorf("Chat completion %s: %+v", name, rsp.Choices)
+			}
+		}
+	}
+}
+
+// TestImageStore generates an image on a worker storing the images, and fetches
+// the image from the input node, which gets it from the worker by the blob protocol.
+func TestImageStore(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping multi-node test in short mode")
+	}
+	cfg := nodetest.NewConfig(t)
+	cfg.Routing.Type = "dhtserver"
+	collector := nodetest.Start(t, cfg)
+
+	backend := nodetest.NewBackend(t, "worker")
+	cfg = nodetest.NewConfig(t)
+	cfg.Bootstrap = []string{collector.P2pAddr}
+	cfg.App.ImageStore.Enabled = true
+	cfg.AIProjects = []types.AIProjectConfig{
+		{
+			Project: imageProject,
+			Models: []types.AIModelConfig{
+				{Model: genModel, API: backend.ImageGenAPI(), Type: 1},
+			},
+		},
+	}
+	worker := nodetest.Start(t, cfg)
+
+	cfg = nodetest.NewConfig(t)
+	cfg.Bootstrap = []string{collector.P2pAddr}
+	input := nodetest.Start(t, cfg)
+
+	req := types.ImageGenerationRequest{
+		NodeID:  worker.ID,
+		Project: imageProject,
+		ImageGenModelRequest: types.ImageGenModelRequest{
+			Model:  genModel,
+			Prompt: "fox",
+			Number: 1,
+			Size:   "1024x1024",
+		},
+	}
+	var rsp types.ImageGenerationResponse
+	if err := input.Post("/api/v0/image/gen", req, &rsp); err != nil {
+		t.Fatalf("Image generation: %v", err)
+	}
+	if rsp.Code != 0 {
+		t.Fatalf("Image generation: {code:%d, message:%s}", rsp.Code, rsp.Message)
+	}
+	if len(rsp.Choices) != 1 || !strings.HasPrefix(rsp.Choices[0].Url, "/api/v0/blobs/") ||
+		!strings.Contains(rsp.Choices[0].Url, worker.ID) {
+		t.Fatalf("Image generation of %s: %+v", worker.ID, rsp.Choices)
+	}
+
+	// the blob is fetched by a stream which needs a direct connection
+	input.Connect(t, worker)
+	for _, node := range []*nodetest.Node{input, worker, input} {
+		resp, err := http.Get(node.API + rsp.Choices[0].Url)
+		if err != nil {
+			t.Fatalf("Get blob from %s: %v", node.ID, err)
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil || resp.StatusCode != http.StatusOK || !bytes.Equal(data, backend.ImageData("fox")) {
+			t.Errorf("Get blob from %s: %s %q %v", node.ID, resp.Status, data, err)
+		}
+	}
+
+	var errRsp types.BaseHttpResponse
+	missing, _ := blob.CID([]byte("missing"))
+	if err := input.Get(blob.URL(missing, worker.ID), &errRsp); err != nil || errRsp.Code != int(types.ErrCodeNotFound) {
+		t.Errorf("Get missing blob: %+v, %v", errRsp, err)
+	}
+}
@@ -0,0 +1,565 @@
+// Package nodetest starts networks of AI computing nodes on the loopback interface
+// for end-to-end tests. The nodes run in the test process, and log to a file shared
+// by all the nodes of the process. The test package must call Main from its TestMain:
+//
+//	func TestMain(m *testing.M) {
+//		nodetest.Main(m)
+//	}
+package nodetest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/keystore"
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/node"
+	"AIComputingNode/pkg/types"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+// StartTimeout is how long to wait for the HTTP API of a started node.
+var StartTimeout = 30 * time.Second
+
+// logFile is the log of all the nodes started by the test process.
+var logFile string
+
+// Main initializes the logging of the nodes, runs the tests and exits.
+func Main(m *testing.M) {
+	os.Exit(runTests(m))
+}
+
+func runTests(m *testing.M) int {
+	dir, err := os.MkdirTemp("", "nodetest")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Create log folder:", err)
+		return 1
+	}
+	defer os.RemoveAll(dir)
+	logFile = filepath.Join(dir, "host.log")
+	if err := log.InitLogging("info", logFile, "file"); err != nil {
+		fmt.Fprintln(os.Stderr, "Init logging:", err)
+		return 1
+	}
+	return m.Run()
+}
+
+// FreePort returns a tcp port of the loopback interface which is not in use.
+func FreePort(t testing.TB) int {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen on a free port: %v", err)
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// NewConfig returns the configuration of a node with a new identity, listening on
+// free loopback ports and storing data in a temporary folder.
+// The node bootstraps from itself until the Bootstrap field is changed.
+func NewConfig(t testing.TB) *config.Config {
+	t.Helper()
+	return NewConfigWithKeyType(t, keystore.DefaultKeyType)
+}
+
+// NewConfigWithKeyType is like NewConfig with an identity of the key type, see keystore.KeyTypes.
+func NewConfigWithKeyType(t testing.TB, keyType string) *config.Config {
+	t.Helper()
+	privKey, err := keystore.GenerateKey(keyType)
+	if err != nil {
+		t.Fatalf("Generate peer key: %v", err)
+	}
+	pubKey := privKey.GetPublic()
+	privkeyBytes, err := crypto.MarshalPrivateKey(privKey)
+	if err != nil {
+		t.Fatalf("Marshal private key: %v", err)
+	}
+	id, err := peer.IDFromPublicKey(pubKey)
+	if err != nil {
+		t.Fatalf("Transform peer id: %v", err)
+	}
+
+	dir := t.TempDir()
+	addr := fmt.Sprintf("/ip4/127.0.0.1/tcp/%d", FreePort(t))
+	return &config.Config{
+		Version:   config.ConfigVersion,
+		Bootstrap: []string{fmt.Sprintf("%s/p2p/%s", addr, id)},
+		Addresses: []string{addr},
+		API: config.APIConfig{
+			Addr: fmt.Sprintf("127.0.0.1:%d", FreePort(t)),
+		},
+		Identity: config.IdentityConfig{
+			PeerID:  id.String(),
+			PrivKey: crypto.ConfigEncodeKey(privkeyBytes),
+		},
+		Swarm: config.SwarmConfig{
+			ConnMgr: config.SwarmConnMgrConfig{
+				Type:        "basic",
+				GracePeriod: "60s",
+				HighWater:   400,
+				LowWater:    100,
+			},
+			DisableNatPortMap: true,
+		},
+		Pubsub: config.PubsubConfig{
+			Enabled:        true,
+			Router:         "gossipsub",
+			FloodPublish:   true,
+			MaxMessageSize: 64 << 20,
+			MaxChunkMemory: 256 << 20,
+			ChunkTimeout:   "60s",
+		},
+		Routing: config.RoutingConfig{
+			Type:           "dhtclient",
+			ProtocolPrefix: config.ProtocolPrefix,
+		},
+		App: config.AppConfig{
+			LogLevel:         "info",
+			LogFile:          logFile,
+			LogOutput:        "file",
+			PreSharedKey:     config.PreSharedKey,
+			TopicName:        config.TopicName,
+			Datastore:        dir,
+			DatastoreBackend: "leveldb",
+			AutoUpgrade: config.AutoUpgradeConfig{
+				Enabled:      false,
+				TimeInterval: "1h",
+			},
+			PeersCollect: config.AppPeersCollectConfig{
+				Enabled:           false,
+				HeartbeatInterval: "1s",
+			},
+			RemoteQuery: config.AppRemoteQueryConfig{
+				CacheTTL:         "5m",
+				MinInterval:      "10s",
+				BatchConcurrency: 16,
+			},
+			ImageStore: config.AppImageStoreConfig{
+				Enabled: false,
+				TTL:     "24h",
+				MaxSize: 32 << 20,
+			},
+			Jobs: config.AppJobsConfig{
+				TTL:        "24h",
+				MaxRunning: 4,
+			},
+			Scheduler: config.AppSchedulerConfig{
+				MaxRunning: 8,
+				MaxQueued:  256,
+				Classes:    []config.AppSchedulerClassConfig{},
+			},
+			ResponseCache: config.AppResponseCacheConfig{
+				Projects:   []string{},
+				TTL:        "10m",
+				MaxEntries: 10000,
+				MaxBytes:   64 << 20,
+			},
+		},
+		AIProjects: []types.AIProjectConfig{},
+	}
+}
+
+// Node is a node running in the test process.
+type Node struct {
+	Config *config.Config
+	// Peer id of the node
+	ID string
+	// Full libp2p address of the node, including the peer id
+	P2pAddr string
+	// Base URL of the HTTP API
+	API string
+}
+
+// Start runs the node of the configuration and waits for its HTTP API,
+// the node is stopped when the test finishes.
+func Start(t testing.TB, cfg *config.Config) *Node {
+	t.Helper()
+	if logFile == "" {
+		t.Fatal("nodetest.Main is not called by TestMain")
+	}
+	configPath := filepath.Join(cfg.App.Datastore, "config.json")
+	if err := cfg.SaveConfig(configPath); err != nil {
+		t.Fatalf("Save config: %v", err)
+	}
+	// the node runs with the loaded file like the program, which fills the defaults
+	loaded, err := config.LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("Load config: %v", err)
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Fatalf("Validate config: %v", err)
+	}
+
+	nd, err := node.New(loaded, node.Options{ConfigPath: configPath, Version: "nodetest"})
+	if err != nil {
+		t.Fatalf("Create node: %v", err)
+	}
+	if err := nd.Start(); err != nil {
+		nd.Stop()
+		t.Fatalf("Start node: %v", err)
+	}
+
+	n := &Node{
+		Config:  cfg,
+		ID:      cfg.Identity.PeerID,
+		P2pAddr: fmt.Sprintf("%s/p2p/%s", cfg.Addresses[0], cfg.Identity.PeerID),
+		API:     "http://" + cfg.API.Addr,
+	}
+	t.Cleanup(func() {
+		if err := nd.Stop(); err != nil {
+			t.Logf("Stop node %s: %v", n.ID, err)
+		}
+		if t.Failed() {
+			t.Logf("Log of the nodes:\n%s", logTail(logFile, 200))
+		}
+	})
+
+	deadline := time.Now().Add(StartTimeout)
+	for {
+		var rsp types.IdentifyProtocol
+		if err := n.Get("/api/v0/id", &rsp); err == nil && rsp.ID == n.ID {
+			return n
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Node %s not ready in %v", n.ID, StartTimeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func logTail(path string, lines int) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err.Error()
+	}
+	all := strings.Split(string(data), "\n")
+	if len(all) > lines {
+		all = all[len(all)-lines:]
+	}
+	return strings.Join(all, "\n")
+}
+
+// Get sends a GET request to the HTTP API of the node and decodes the JSON response into rsp.
+func (n *Node) Get(path string, rsp any) error {
+	resp, err := http.Get(n.API + path)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, rsp)
+}
+
+// Post sends req as JSON to the HTTP API of the node and decodes the JSON response into rsp.
+func (n *Node) Post(path string, req any, rsp any) error {
+	return n.PostContext(context.Background(), path, req, rsp)
+}
+
+// PostContext is like Post, the request is canceled when the context is done.
+func (n *Node) PostContext(ctx context.Context, path string, req any, rsp any) error {
+	body, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	hreq, err := http.NewRequestWithContext(ctx, http.MethodPost, n.API+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	hreq.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(hreq)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, rsp)
+}
+
+// PostFile sends a multipart form with the file to the HTTP API of the node
+// and decodes the JSON response into rsp.
+func (n *Node) PostFile(path string, field, filename string, content []byte, rsp any) error {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, filename)
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write(content); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+	resp, err := http.Post(n.API+path, writer.FormDataContentType(), body)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, rsp)
+}
+
+func decodeResponse(resp *http.Response, rsp any) error {
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if raw, ok := rsp.(*[]byte); ok {
+		// the audio responses are not json
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("response %q with status %d", body, resp.StatusCode)
+		}
+		*raw = body
+		return nil
+	}
+	if err := json.Unmarshal(body, rsp); err != nil {
+		return fmt.Errorf("unmarshal response %q with status %d: %v", body, resp.StatusCode, err)
+	}
+	return nil
+}
+
+// Connect connects the node to the other node through the swarm connect API.
+func (n *Node) Connect(t testing.TB, other *Node) {
+	t.Helper()
+	var rsp types.SwarmConnectResponse
+	if err := n.Post("/api/v0/swarm/connect", types.SwarmConnectRequest{NodeAddr: other.P2pAddr}, &rsp); err != nil {
+		t.Fatalf("Connect %s to %s: %v", n.ID, other.ID, err)
+	}
+	if rsp.Code != 0 {
+		t.Fatalf("Connect %s to %s: {code:%d, message:%s}", n.ID, other.ID, rsp.Code, rsp.Message)
+	}
+}
+
+// WaitFor calls cond until it returns true or the timeout expires.
+func WaitFor(t testing.TB, timeout time.Duration, msg string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timeout after %v waiting for %s", timeout, msg)
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+}
+
+// HangPrompt is the chat message which the backend never answers, until the request is canceled.
+const HangPrompt = "hang"
+
+// Backend is a fake model backend answering the OpenAI compatible chat completion,
+// image generation, image edit, embedding and audio APIs. Every response contains the name of the backend.
+type Backend struct {
+	Name     string
+	Server   *httptest.Server
+	canceled atomic.Int32
+}
+
+// NewBackend starts a fake model backend which is closed when the test finishes.
+func NewBackend(t testing.TB, name string) *Backend {
+	t.Helper()
+	b := &Backend{Name: name}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", b.chatCompletions)
+	mux.HandleFunc("/v1/images/generations", b.imageGenerations)
+	mux.HandleFunc("/v1/images/edits", b.imageEdits)
+	mux.HandleFunc("/v1/images/files/", b.imageFiles)
+	mux.HandleFunc("/v1/embeddings", b.embeddings)
+	mux.HandleFunc("/v1/audio/transcriptions", b.audioTranscriptions)
+	mux.HandleFunc("/v1/audio/speech", b.audioSpeech)
+	b.Server = httptest.NewServer(mux)
+	t.Cleanup(b.Server.Close)
+	return b
+}
+
+// ChatAPI is the chat completion API of the backend used in AIModelConfig.
+func (b *Backend) ChatAPI() string {
+	return b.Server.URL + "/v1/chat/completions"
+}
+
+// ImageGenAPI is the image generation API of the backend used in AIModelConfig.
+func (b *Backend) ImageGenAPI() string {
+	return b.Server.URL + "/v1/images/generations"
+}
+
+// ImageEditAPI is the image edit API of the backend used in AIModelConfig.
+func (b *Backend) ImageEditAPI() string {
+	return b.Server.URL + "/v1/images/edits"
+}
+
+// EmbeddingAPI is the embedding API of the backend used in AIModelConfig.
+func (b *Backend) EmbeddingAPI() string {
+	return b.Server.URL + "/v1/embeddings"
+}
+
+// TranscriptionAPI is the audio transcription API of the backend used in AIModelConfig.
+func (b *Backend) TranscriptionAPI() string {
+	return b.Server.URL + "/v1/audio/transcriptions"
+}
+
+// SpeechAPI is the audio speech API of the backend used in AIModelConfig.
+func (b *Backend) SpeechAPI() string {
+	return b.Server.URL + "/v1/audio/speech"
+}
+
+// Canceled is the number of the HangPrompt requests canceled by the workers.
+func (b *Backend) Canceled() int {
+	return int(b.canceled.Load())
+}
+
+// ChatReply is the content of the chat completion answered by the backend.
+func (b *Backend) ChatReply() string {
+	return "reply from " + b.Name
+}
+
+// ImageURL is the image url answered by the backend for the prompt or the edited file.
+func (b *Backend) ImageURL(prompt string) string {
+	return fmt.Sprintf("%s/v1/images/files/%s.png", b.Server.URL, prompt)
+}
+
+// ImageData is the content of the image at ImageURL.
+func (b *Backend) ImageData(prompt string) []byte {
+	return []byte(fmt.Sprintf("image of %s from %s", prompt, b.Name))
+}
+
+// Embedding is the embedding of the text answered by the backend.
+func (b *Backend) Embedding(text string) []float32 {
+	return []float32{float32(len(text)), float32(len(b.Name))}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	// the model package only accepts exactly this content type
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func (b *Backend) chatCompletions(w http.ResponseWriter, r *http.Request) {
+	var req types.ChatModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if n := len(req.Messages); n > 0 && string(req.Messages[n-1].Content) == fmt.Sprintf("%q", HangPrompt) {
+		<-r.Context().Done()
+		b.canceled.Add(1)
+		return
+	}
+	writeJSON(w, types.ChatModelResponseData{
+		Id:      "chatcmpl-" + b.Name,
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Choices: []types.ChatResponseChoice{
+			{
+				Index: 0,
+				Message: types.ChatCompletionResponseMessage{
+					Role:    "assistant",
+					Content: b.ChatReply(),
+				},
+				FinishReason: "stop",
+			},
+		},
+		Usage: types.ChatResponseUsage{
+			CompletionTokens: 3,
+			PromptTokens:     len(req.Messages),
+			TotalTokens:      len(req.Messages) + 3,
+		},
+	})
+}
+
+func (b *Backend) imageGenerations(w http.ResponseWriter, r *http.Request) {
+	var req types.ImageGenModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, types.ImageModelResponse{
+		Created: time.Now().Unix(),
+		Choices: []types.ImageResponseChoice{{Url: b.ImageURL(req.Prompt)}},
+	})
+}
+
+func (b *Backend) imageFiles(w http.ResponseWriter, r *http.Request) {
+	prompt := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/images/files/"), ".png")
+	w.Write(b.ImageData(prompt))
+}
+
+func (b *Backend) imageEdits(w http.ResponseWriter, r *http.Request) {
+	file, header, err := r.FormFile("image")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	file.Close()
+	writeJSON(w, types.ImageModelResponse{
+		Created: time.Now().Unix(),
+		Choices: []types.ImageResponseChoice{{Url: b.ImageURL(header.Filename)}},
+	})
+}
+
+func (b *Backend) embeddings(w http.ResponseWriter, r *http.Request) {
+	var req types.EmbeddingModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	res := types.EmbeddingModelResponse{
+		Object: "list",
+		Model:  req.Model,
+		Usage: types.EmbeddingUsage{
+			PromptTokens: len(req.Input),
+			TotalTokens:  len(req.Input),
+		},
+	}
+	for i, text := range req.Input {
+		res.Data = append(res.Data, types.EmbeddingData{
+			Object:    "embedding",
+			Index:     i,
+			Embedding: b.Embedding(text),
+		})
+	}
+	writeJSON(w, res)
+}
+
+// Transcription is the text of the uploaded audio file answered by the backend.
+func (b *Backend) Transcription(filename string) string {
+	return fmt.Sprintf("transcription of %s from %s", filename, b.Name)
+}
+
+// Speech is the audio of the input answered by the backend.
+func (b *Backend) Speech(input string) []byte {
+	return []byte(fmt.Sprintf("speech of %s from %s", input, b.Name))
+}
+
+func (b *Backend) audioTranscriptions(w http.ResponseWriter, r *http.Request) {
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	file.Close()
+	if r.FormValue("model") == "" {
+		http.Error(w, "missing model", http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, map[string]string{"text": b.Transcription(header.Filename)})
+}
+
+func (b *Backend) audioSpeech(w http.ResponseWriter, r *http.Request) {
+	var req types.AudioSpeechModelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "audio/mpeg")
+	w.Write(b.Speech(req.Input))
+}
@@ -0,0 +1,172 @@
+package node
+
+import (
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/serve"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func (n *Node) newRouter() *gin.Engine {
+	// router := gin.Default()
+	// router.Use(gin.Recovery())
+	// router.Use(errorHandler)
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	// router.NoRoute(func(ctx *gin.Context) {
+	// 	ctx.JSON(http.StatusNotFound, gin.H{"code": "PAGE_NOT_FOUND", "message": "Page not found"})
+	// })
+	// router.NoMethod(func(ctx *gin.Context) {
+	// 	ctx.JSON(http.StatusMethodNotAllowed, gin.H{"code": "METHOD_NOT_ALLOWED", "message": "Method not allowed"})
+	// })
+	router.Use(
+		log.GinzapWithConfig(
+			&log.GinConfig{
+				SkipPaths: []string{},
+				Skip:      nil,
+			},
+			&n.activeHttpReqs,
+		),
+		log.GinzapRecovery(true),
+	)
+	// router.GET("/api/v0/id", serve.IdHandler)
+	v0 := router.Group("/api/v0")
+	{
+		v0.GET("/id", func(ctx *gin.Context) {
+			serve.IdHandler(ctx, n.env)
+		})
+		v0.GET("/peers", func(ctx *gin.Context) {
+			serve.PeersHandler(ctx, n.store)
+		})
+		v0.GET("/peers/directory", func(ctx *gin.Context) {
+			serve.PeerDirectoryHandler(ctx, n.env, n.store)
+		})
+		v0.GET("/peers/topology", func(ctx *gin.Context) {
+			serve.TopologyHandler(ctx, n.env, n.store)
+		})
+		v0.POST("/peer", func(ctx *gin.Context) {
+			serve.PeerHandler(ctx, n.env, n.publishChan, n.store)
+		})
+		v0.POST("/peer/batch", func(ctx *gin.Context) {
+			serve.PeerBatchHandler(ctx, n.env, n.publishChan, n.store)
+		})
+		v0.POST("/host/info", func(ctx *gin.Context) {
+			serve.HostInfoHandler(ctx, n.env, n.publishChan, n.store)
+		})
+		v0.POST("/host/info/batch", func(ctx *gin.Context) {
+			serve.HostInfoBatchHandler(ctx, n.env, n.publishChan, n.store)
+		})
+		v0.GET("/rendezvous/peers", func(ctx *gin.Context) {
+			serve.RendezvousPeersHandler(ctx, n.env)
+		})
+		v0.GET("/swarm/peers", func(ctx *gin.Context) {
+			serve.SwarmPeersHandler(ctx, n.env)
+		})
+		v0.GET("/swarm/addrs", func(ctx *gin.Context) {
+			serve.SwarmAddrsHandler(ctx, n.env)
+		})
+		v0.POST("/swarm/connect", func(ctx *gin.Context) {
+			serve.SwarmConnectHandler(ctx, n.env)
+		})
+		v0.POST("/swarm/disconnect", func(ctx *gin.Context) {
+			serve.SwarmDisconnectHandler(ctx, n.env)
+		})
+		v0.GET("/pubsub/peers", func(ctx *gin.Context) {
+			serve.PubsubPeersHandler(ctx, n.env)
+		})
+
+		v0.POST("/chat/completion", func(ctx *gin.Context) {
+			serve.ChatCompletionHandler(ctx, n.env, n.publishChan, n.cache)
+		})
+		v0.POST("/chat/completion/proxy", func(ctx *gin.Context) {
+			serve.ChatCompletionProxyHandler(ctx, n.env, n.publishChan, n.store, n.cache)
+		})
+		v0.POST("/image/gen", func(ctx *gin.Context) {
+			serve.ImageGenHandler(ctx, n.env, n.publishChan, n.blobs, n.cache)
+		})
+		v0.POST("/image/gen/proxy", func(ctx *gin.Context) {
+			serve.ImageGenProxyHandler(ctx, n.env, n.publishChan, n.store, n.blobs, n.cache)
+		})
+		v0.POST("/image/edit", func(ctx *gin.Context) {
+			serve.ImageEditHandler(ctx, n.env, n.publishChan)
+		})
+		v0.POST("/image/edit/proxy", func(ctx *gin.Context) {
+			serve.ImageEditProxyHandler(ctx, n.env, n.publishChan, n.store)
+		})
+		v0.POST("/audio/transcriptions", func(ctx *gin.Context) {
+			serve.AudioTranscriptionHandler(ctx, n.env, n.publishChan)
+		})
+		v0.POST("/audio/transcriptions/proxy", func(ctx *gin.Context) {
+			serve.AudioTranscriptionProxyHandler(ctx, n.env, n.publishChan, n.store)
+		})
+		v0.POST("/audio/speech", func(ctx *gin.Context) {
+			serve.AudioSpeechHandler(ctx, n.env, n.publishChan)
+		})
+		v0.POST("/audio/speech/proxy", func(ctx *gin.Context) {
+			serve.AudioSpeechProxyHandler(ctx, n.env, n.publishChan, n.store)
+		})
+		v0.POST("/embeddings", func(ctx *gin.Context) {
+			serve.EmbeddingHandler(ctx, n.env, n.publishChan, n.cache)
+		})
+		v0.POST("/embeddings/proxy", func(ctx *gin.Context) {
+			serve.EmbeddingProxyHandler(ctx, n.env, n.publishChan, n.store, n.cache)
+		})
+		v0.POST("/jobs", func(ctx *gin.Context) {
+			serve.JobSubmitHandler(ctx, n.jobs)
+		})
+		v0.GET("/jobs/:id", func(ctx *gin.Context) {
+			serve.JobHandler(ctx, n.jobs)
+		})
+		v0.GET("/jobs/:id/events", func(ctx *gin.Context) {
+			serve.JobEventsHandler(ctx, n.jobs)
+		})
+		v0.GET("/scheduler", func(ctx *gin.Context) {
+			serve.SchedulerHandler(ctx, n.sched)
+		})
+		v0.GET("/blobs/:cid", func(ctx *gin.Context) {
+			serve.BlobHandler(ctx, n.env, n.blobs)
+		})
+
+		v0.POST("/ai/project/register", func(ctx *gin.Context) {
+			serve.RegisterAIProjectHandler(ctx, n.env, n.opts.ConfigPath, n.publishChan)
+		})
+		v0.POST("/ai/project/unregister", func(ctx *gin.Context) {
+			serve.UnregisterAIProjectHandler(ctx, n.env, n.opts.ConfigPath, n.publishChan)
+		})
+		v0.POST("/ai/project/peer", func(ctx *gin.Context) {
+			serve.GetAIProjectOfNodeHandler(ctx, n.env, n.publishChan)
+		})
+		v0.GET("/ai/projects/list", func(ctx *gin.Context) {
+			serve.ListAIProjectsHandler(ctx, n.store)
+		})
+		v0.GET("/ai/projects/models", func(ctx *gin.Context) {
+			serve.GetModelsOfAIProjectHandler(ctx, n.store)
+		})
+		v0.GET("/ai/projects/peers", func(ctx *gin.Context) {
+			serve.GetPeersOfAIProjectHandler(ctx, n.env, n.store)
+		})
+		v0.GET("/ai/history", func(ctx *gin.Context) {
+			serve.ModelHistoryHandler(ctx, n.store)
+		})
+		v0.POST("/ai/model/register", func(ctx *gin.Context) {
+			serve.RegisterAIModelHandler(ctx, n.env, n.opts.ConfigPath, n.publishChan)
+		})
+		v0.POST("/ai/model/unregister", func(ctx *gin.Context) {
+			serve.UnregisterAIModelHandler(ctx, n.env, n.opts.ConfigPath, n.publishChan)
+		})
+
+		v0.GET("/bootstrap/list", func(ctx *gin.Context) {
+			serve.ListBootstrapHandler(ctx, n.env)
+		})
+		v0.POST("/bootstrap/add", func(ctx *gin.Context) {
+			serve.AddBootstrapHandler(ctx, n.env, n.opts.ConfigPath)
+		})
+		v0.POST("/bootstrap/rm", func(ctx *gin.Context) {
+			serve.RemoveBootstrapHandler(ctx, n.env, n.opts.ConfigPath)
+		})
+
+		v0.GET("/debug/metrics/prometheus", gin.WrapH(promhttp.Handler()))
+	}
+	return router
+}
@@ -28,6 +28,13 @@ const (
 	MessageType_AI_PROJECT       MessageType = 2
 	MessageType_CHAT_COMPLETION  MessageType = 16
 	MessageType_IMAGE_GENERATION MessageType = 17
+	// A chunk of a message larger than the pubsub limit, the body is MessageChunk
+	MessageType_MESSAGE_CHUNK       MessageType = 18
+	MessageType_EMBEDDING           MessageType = 19
+	MessageType_AUDIO_TRANSCRIPTION MessageType = 20
+	MessageType_AUDIO_SPEECH        MessageType = 21
+	// Cancel a model request sent before with the same request id, the body is CancelBody
+	MessageType_CANCEL MessageType = 22
 )
 
 // Enum value maps for MessageType.
@@ -38,13 +45,23 @@ var (
 		2:  "AI_PROJECT",
 		16: "CHAT_COMPLETION",
 		17: "IMAGE_GENERATION",
+		18: "MESSAGE_CHUNK",
+		19: "EMBEDDING",
+		20: "AUDIO_TRANSCRIPTION",
+		21: "AUDIO_SPEECH",
+		22: "CANCEL",
 	}
 	MessageType_value = map[string]int32{
-		"PEER_IDENTITY":    0,
-		"HOST_INFO":        1,
-		"AI_PROJECT":       2,
-		"CHAT_COMPLETION":  16,
-		"IMAGE_GENERATION": 17,
+		"PEER_IDENTITY":       0,
+		"HOST_INFO":           1,
+		"AI_PROJECT":          2,
+		"CHAT_COMPLETION":     16,
+		"IMAGE_GENERATION":    17,
+		"MESSAGE_CHUNK":       18,
+		"EMBEDDING":           19,
+		"AUDIO_TRANSCRIPTION": 20,
+		"AUDIO_SPEECH":        21,
+		"CANCEL":              22,
 	}
 )
 
@@ -121,7 +138,7 @@ func (x ChatContentPart_Type) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ChatContentPart_Type.Descriptor instead.
 func (ChatContentPart_Type) EnumDescriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{10, 0}
+	return file_protocol_proto_rawDescGZIP(), []int{22, 0}
 }
 
 type MessageHeader struct {
@@ -133,6 +150,8 @@ type MessageHeader struct {
 	Receiver      string                 `protobuf:"bytes,5,opt,name=receiver,proto3" json:"receiver,omitempty"`
 	NodePubKey    []byte                 `protobuf:"bytes,6,opt,name=node_pub_key,json=nodePubKey,proto3" json:"node_pub_key,omitempty"`
 	Sign          []byte                 `protobuf:"bytes,7,opt,name=sign,proto3" json:"sign,omitempty"`
+	// Scheme of the encrypted body, 0 is the static ECDH of the legacy nodes
+	Encryption    uint32 `protobuf:"varint,8,opt,name=encryption,proto3" json:"encryption,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -216,6 +235,13 @@ func (x *MessageHeader) GetSign() []byte {
 	return nil
 }
 
+func (x *MessageHeader) GetEncryption() uint32 {
+	if x != nil {
+		return x.Encryption
+	}
+	return 0
+}
+
 type Message struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Header        *MessageHeader         `protobuf:"bytes,1,opt,name=header,proto3" json:"header,omitempty"`
@@ -292,6 +318,195 @@ func (x *Message) GetResultMessage() string {
 	return ""
 }
 
+type MessageChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// SHA-256 of the whole marshaled message
+	Hash  []byte `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Index uint32 `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
+	Total uint32 `protobuf:"varint,3,opt,name=total,proto3" json:"total,omitempty"`
+	// Size of the whole marshaled message
+	Size          uint64 `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	Data          []byte `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MessageChunk) Reset() {
+	*x = MessageChunk{}
+	mi := &file_protocol_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MessageChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MessageChunk) ProtoMessage() {}
+
+func (x *MessageChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MessageChunk.ProtoReflect.Descriptor instead.
+func (*MessageChunk) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *MessageChunk) GetHash() []byte {
+	if x != nil {
+		return x.Hash
+	}
+	return nil
+}
+
+func (x *MessageChunk) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *MessageChunk) GetTotal() uint32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *MessageChunk) GetSize() uint64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *MessageChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type CancelBody struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*CancelBody_Req
+	Data          isCancelBody_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelBody) Reset() {
+	*x = CancelBody{}
+	mi := &file_protocol_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelBody) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelBody) ProtoMessage() {}
+
+func (x *CancelBody) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelBody.ProtoReflect.Descriptor instead.
+func (*CancelBody) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CancelBody) GetData() isCancelBody_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *CancelBody) GetReq() *CancelRequest {
+	if x != nil {
+		if x, ok := x.Data.(*CancelBody_Req); ok {
+			return x.Req
+		}
+	}
+	return nil
+}
+
+type isCancelBody_Data interface {
+	isCancelBody_Data()
+}
+
+type CancelBody_Req struct {
+	Req *CancelRequest `protobuf:"bytes,1,opt,name=req,proto3,oneof"`
+}
+
+func (*CancelBody_Req) isCancelBody_Data() {}
+
+type CancelRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Type of the canceled request
+	Type          MessageType `protobuf:"varint,1,opt,name=type,proto3,enum=protocol.MessageType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelRequest) Reset() {
+	*x = CancelRequest{}
+	mi := &file_protocol_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelRequest) ProtoMessage() {}
+
+func (x *CancelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelRequest.ProtoReflect.Descriptor instead.
+func (*CancelRequest) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CancelRequest) GetType() MessageType {
+	if x != nil {
+		return x.Type
+	}
+	return MessageType_PEER_IDENTITY
+}
+
 type PeerIdentityBody struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Data:
@@ -305,7 +520,7 @@ type PeerIdentityBody struct {
 
 func (x *PeerIdentityBody) Reset() {
 	*x = PeerIdentityBody{}
-	mi := &file_protocol_proto_msgTypes[2]
+	mi := &file_protocol_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -317,7 +532,7 @@ func (x *PeerIdentityBody) String() string {
 func (*PeerIdentityBody) ProtoMessage() {}
 
 func (x *PeerIdentityBody) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[2]
+	mi := &file_protocol_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -330,7 +545,7 @@ func (x *PeerIdentityBody) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PeerIdentityBody.ProtoReflect.Descriptor instead.
 func (*PeerIdentityBody) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{2}
+	return file_protocol_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *PeerIdentityBody) GetData() isPeerIdentityBody_Data {
@@ -382,7 +597,7 @@ type PeerIdentityRequest struct {
 
 func (x *PeerIdentityRequest) Reset() {
 	*x = PeerIdentityRequest{}
-	mi := &file_protocol_proto_msgTypes[3]
+	mi := &file_protocol_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -394,7 +609,7 @@ func (x *PeerIdentityRequest) String() string {
 func (*PeerIdentityRequest) ProtoMessage() {}
 
 func (x *PeerIdentityRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[3]
+	mi := &file_protocol_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -407,7 +622,7 @@ func (x *PeerIdentityRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PeerIdentityRequest.ProtoReflect.Descriptor instead.
 func (*PeerIdentityRequest) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{3}
+	return file_protocol_proto_rawDescGZIP(), []int{6}
 }
 
 type PeerIdentityResponse struct {
@@ -423,7 +638,7 @@ type PeerIdentityResponse struct {
 
 func (x *PeerIdentityResponse) Reset() {
 	*x = PeerIdentityResponse{}
-	mi := &file_protocol_proto_msgTypes[4]
+	mi := &file_protocol_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -435,7 +650,7 @@ func (x *PeerIdentityResponse) String() string {
 func (*PeerIdentityResponse) ProtoMessage() {}
 
 func (x *PeerIdentityResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[4]
+	mi := &file_protocol_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -448,7 +663,7 @@ func (x *PeerIdentityResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PeerIdentityResponse.ProtoReflect.Descriptor instead.
 func (*PeerIdentityResponse) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{4}
+	return file_protocol_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *PeerIdentityResponse) GetProtocolVersion() string {
@@ -497,7 +712,7 @@ type WalletVerification struct {
 
 func (x *WalletVerification) Reset() {
 	*x = WalletVerification{}
-	mi := &file_protocol_proto_msgTypes[5]
+	mi := &file_protocol_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -509,7 +724,7 @@ func (x *WalletVerification) String() string {
 func (*WalletVerification) ProtoMessage() {}
 
 func (x *WalletVerification) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[5]
+	mi := &file_protocol_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -522,7 +737,7 @@ func (x *WalletVerification) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use WalletVerification.ProtoReflect.Descriptor instead.
 func (*WalletVerification) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{5}
+	return file_protocol_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *WalletVerification) GetWallet() string {
@@ -559,7 +774,7 @@ type ImageGenerationBody struct {
 
 func (x *ImageGenerationBody) Reset() {
 	*x = ImageGenerationBody{}
-	mi := &file_protocol_proto_msgTypes[6]
+	mi := &file_protocol_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -571,7 +786,7 @@ func (x *ImageGenerationBody) String() string {
 func (*ImageGenerationBody) ProtoMessage() {}
 
 func (x *ImageGenerationBody) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[6]
+	mi := &file_protocol_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -584,7 +799,7 @@ func (x *ImageGenerationBody) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImageGenerationBody.ProtoReflect.Descriptor instead.
 func (*ImageGenerationBody) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{6}
+	return file_protocol_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *ImageGenerationBody) GetData() isImageGenerationBody_Data {
@@ -641,13 +856,15 @@ type ImageGenerationRequest struct {
 	Cid            string                 `protobuf:"bytes,9,opt,name=cid,proto3" json:"cid,omitempty"`
 	Step           int32                  `protobuf:"varint,10,opt,name=step,proto3" json:"step,omitempty"`
 	Wallet         *WalletVerification    `protobuf:"bytes,16,opt,name=wallet,proto3" json:"wallet,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Random seed of the images, the backends pick one if it is not set
+	Seed          *int64 `protobuf:"varint,17,opt,name=seed,proto3,oneof" json:"seed,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ImageGenerationRequest) Reset() {
 	*x = ImageGenerationRequest{}
-	mi := &file_protocol_proto_msgTypes[7]
+	mi := &file_protocol_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -659,7 +876,7 @@ func (x *ImageGenerationRequest) String() string {
 func (*ImageGenerationRequest) ProtoMessage() {}
 
 func (x *ImageGenerationRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[7]
+	mi := &file_protocol_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -672,7 +889,7 @@ func (x *ImageGenerationRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImageGenerationRequest.ProtoReflect.Descriptor instead.
 func (*ImageGenerationRequest) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{7}
+	return file_protocol_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ImageGenerationRequest) GetProject() string {
@@ -752,6 +969,13 @@ func (x *ImageGenerationRequest) GetWallet() *WalletVerification {
 	return nil
 }
 
+func (x *ImageGenerationRequest) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
 type ImageGenerationResponse struct {
 	state         protoimpl.MessageState                         `protogen:"open.v1"`
 	Created       int64                                          `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
@@ -762,7 +986,7 @@ type ImageGenerationResponse struct {
 
 func (x *ImageGenerationResponse) Reset() {
 	*x = ImageGenerationResponse{}
-	mi := &file_protocol_proto_msgTypes[8]
+	mi := &file_protocol_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -774,7 +998,7 @@ func (x *ImageGenerationResponse) String() string {
 func (*ImageGenerationResponse) ProtoMessage() {}
 
 func (x *ImageGenerationResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[8]
+	mi := &file_protocol_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -787,7 +1011,7 @@ func (x *ImageGenerationResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ImageGenerationResponse.ProtoReflect.Descriptor instead.
 func (*ImageGenerationResponse) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{8}
+	return file_protocol_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *ImageGenerationResponse) GetCreated() int64 {
@@ -804,32 +1028,32 @@ func (x *ImageGenerationResponse) GetChoices() []*ImageGenerationResponse_ImageR
 	return nil
 }
 
-type ChatCompletionBody struct {
+type EmbeddingBody struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Data:
 	//
-	//	*ChatCompletionBody_Req
-	//	*ChatCompletionBody_Res
-	Data          isChatCompletionBody_Data `protobuf_oneof:"data"`
+	//	*EmbeddingBody_Req
+	//	*EmbeddingBody_Res
+	Data          isEmbeddingBody_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatCompletionBody) Reset() {
-	*x = ChatCompletionBody{}
-	mi := &file_protocol_proto_msgTypes[9]
+func (x *EmbeddingBody) Reset() {
+	*x = EmbeddingBody{}
+	mi := &file_protocol_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatCompletionBody) String() string {
+func (x *EmbeddingBody) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatCompletionBody) ProtoMessage() {}
+func (*EmbeddingBody) ProtoMessage() {}
 
-func (x *ChatCompletionBody) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[9]
+func (x *EmbeddingBody) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -840,77 +1064,80 @@ func (x *ChatCompletionBody) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatCompletionBody.ProtoReflect.Descriptor instead.
-func (*ChatCompletionBody) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use EmbeddingBody.ProtoReflect.Descriptor instead.
+func (*EmbeddingBody) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{12}
 }
 
-func (x *ChatCompletionBody) GetData() isChatCompletionBody_Data {
+func (x *EmbeddingBody) GetData() isEmbeddingBody_Data {
 	if x != nil {
 		return x.Data
 	}
 	return nil
 }
 
-func (x *ChatCompletionBody) GetReq() *ChatCompletionRequest {
+func (x *EmbeddingBody) GetReq() *EmbeddingRequest {
 	if x != nil {
-		if x, ok := x.Data.(*ChatCompletionBody_Req); ok {
+		if x, ok := x.Data.(*EmbeddingBody_Req); ok {
 			return x.Req
 		}
 	}
 	return nil
 }
 
-func (x *ChatCompletionBody) GetRes() *ChatCompletionResponse {
+func (x *EmbeddingBody) GetRes() *EmbeddingResponse {
 	if x != nil {
-		if x, ok := x.Data.(*ChatCompletionBody_Res); ok {
+		if x, ok := x.Data.(*EmbeddingBody_Res); ok {
 			return x.Res
 		}
 	}
 	return nil
 }
 
-type isChatCompletionBody_Data interface {
-	isChatCompletionBody_Data()
+type isEmbeddingBody_Data interface {
+	isEmbeddingBody_Data()
 }
 
-type ChatCompletionBody_Req struct {
-	Req *ChatCompletionRequest `protobuf:"bytes,1,opt,name=req,proto3,oneof"`
+type EmbeddingBody_Req struct {
+	Req *EmbeddingRequest `protobuf:"bytes,1,opt,name=req,proto3,oneof"`
 }
 
-type ChatCompletionBody_Res struct {
-	Res *ChatCompletionResponse `protobuf:"bytes,2,opt,name=res,proto3,oneof"`
+type EmbeddingBody_Res struct {
+	Res *EmbeddingResponse `protobuf:"bytes,2,opt,name=res,proto3,oneof"`
 }
 
-func (*ChatCompletionBody_Req) isChatCompletionBody_Data() {}
+func (*EmbeddingBody_Req) isEmbeddingBody_Data() {}
 
-func (*ChatCompletionBody_Res) isChatCompletionBody_Data() {}
+func (*EmbeddingBody_Res) isEmbeddingBody_Data() {}
 
-type ChatContentPart struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          ChatContentPart_Type   `protobuf:"varint,1,opt,name=type,proto3,enum=protocol.ChatContentPart_Type" json:"type,omitempty"`
-	Text          *ChatContentPart_Text  `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
-	Image         *ChatContentPart_Image `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
-	Audio         *ChatContentPart_Audio `protobuf:"bytes,4,opt,name=audio,proto3" json:"audio,omitempty"`
+type EmbeddingRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Project string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Model   string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	// Batch of the texts to embed
+	Input         []string            `protobuf:"bytes,3,rep,name=input,proto3" json:"input,omitempty"`
+	Cid           string              `protobuf:"bytes,4,opt,name=cid,proto3" json:"cid,omitempty"`
+	Dimensions    int32               `protobuf:"varint,5,opt,name=dimensions,proto3" json:"dimensions,omitempty"`
+	Wallet        *WalletVerification `protobuf:"bytes,16,opt,name=wallet,proto3" json:"wallet,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatContentPart) Reset() {
-	*x = ChatContentPart{}
-	mi := &file_protocol_proto_msgTypes[10]
+func (x *EmbeddingRequest) Reset() {
+	*x = EmbeddingRequest{}
+	mi := &file_protocol_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatContentPart) String() string {
+func (x *EmbeddingRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatContentPart) ProtoMessage() {}
+func (*EmbeddingRequest) ProtoMessage() {}
 
-func (x *ChatContentPart) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[10]
+func (x *EmbeddingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -921,61 +1148,79 @@ func (x *ChatContentPart) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatContentPart.ProtoReflect.Descriptor instead.
-func (*ChatContentPart) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use EmbeddingRequest.ProtoReflect.Descriptor instead.
+func (*EmbeddingRequest) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{13}
 }
 
-func (x *ChatContentPart) GetType() ChatContentPart_Type {
+func (x *EmbeddingRequest) GetProject() string {
 	if x != nil {
-		return x.Type
+		return x.Project
 	}
-	return ChatContentPart_TEXT
+	return ""
 }
 
-func (x *ChatContentPart) GetText() *ChatContentPart_Text {
+func (x *EmbeddingRequest) GetModel() string {
 	if x != nil {
-		return x.Text
+		return x.Model
 	}
-	return nil
+	return ""
 }
 
-func (x *ChatContentPart) GetImage() *ChatContentPart_Image {
+func (x *EmbeddingRequest) GetInput() []string {
 	if x != nil {
-		return x.Image
+		return x.Input
 	}
 	return nil
 }
 
-func (x *ChatContentPart) GetAudio() *ChatContentPart_Audio {
+func (x *EmbeddingRequest) GetCid() string {
 	if x != nil {
-		return x.Audio
+		return x.Cid
+	}
+	return ""
+}
+
+func (x *EmbeddingRequest) GetDimensions() int32 {
+	if x != nil {
+		return x.Dimensions
+	}
+	return 0
+}
+
+func (x *EmbeddingRequest) GetWallet() *WalletVerification {
+	if x != nil {
+		return x.Wallet
 	}
 	return nil
 }
 
-type ChatContentParts struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Parts         []*ChatContentPart     `protobuf:"bytes,1,rep,name=parts,proto3" json:"parts,omitempty"`
+type EmbeddingResponse struct {
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	Created       int64                          `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Model         string                         `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Data          []*EmbeddingResponse_Embedding `protobuf:"bytes,3,rep,name=data,proto3" json:"data,omitempty"`
+	PromptTokens  int32                          `protobuf:"varint,4,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
+	TotalTokens   int32                          `protobuf:"varint,5,opt,name=total_tokens,json=totalTokens,proto3" json:"total_tokens,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatContentParts) Reset() {
-	*x = ChatContentParts{}
-	mi := &file_protocol_proto_msgTypes[11]
+func (x *EmbeddingResponse) Reset() {
+	*x = EmbeddingResponse{}
+	mi := &file_protocol_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatContentParts) String() string {
+func (x *EmbeddingResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatContentParts) ProtoMessage() {}
+func (*EmbeddingResponse) ProtoMessage() {}
 
-func (x *ChatContentParts) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[11]
+func (x *EmbeddingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -986,41 +1231,72 @@ func (x *ChatContentParts) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatContentParts.ProtoReflect.Descriptor instead.
-func (*ChatContentParts) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use EmbeddingResponse.ProtoReflect.Descriptor instead.
+func (*EmbeddingResponse) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{14}
 }
 
-func (x *ChatContentParts) GetParts() []*ChatContentPart {
+func (x *EmbeddingResponse) GetCreated() int64 {
 	if x != nil {
-		return x.Parts
+		return x.Created
+	}
+	return 0
+}
+
+func (x *EmbeddingResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *EmbeddingResponse) GetData() []*EmbeddingResponse_Embedding {
+	if x != nil {
+		return x.Data
 	}
 	return nil
 }
 
-type ChatCompletionMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
-	Content       []byte                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+func (x *EmbeddingResponse) GetPromptTokens() int32 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *EmbeddingResponse) GetTotalTokens() int32 {
+	if x != nil {
+		return x.TotalTokens
+	}
+	return 0
+}
+
+type AudioTranscriptionBody struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*AudioTranscriptionBody_Req
+	//	*AudioTranscriptionBody_Res
+	Data          isAudioTranscriptionBody_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatCompletionMessage) Reset() {
-	*x = ChatCompletionMessage{}
-	mi := &file_protocol_proto_msgTypes[12]
+func (x *AudioTranscriptionBody) Reset() {
+	*x = AudioTranscriptionBody{}
+	mi := &file_protocol_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatCompletionMessage) String() string {
+func (x *AudioTranscriptionBody) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatCompletionMessage) ProtoMessage() {}
+func (*AudioTranscriptionBody) ProtoMessage() {}
 
-func (x *ChatCompletionMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[12]
+func (x *AudioTranscriptionBody) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1031,54 +1307,82 @@ func (x *ChatCompletionMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatCompletionMessage.ProtoReflect.Descriptor instead.
-func (*ChatCompletionMessage) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use AudioTranscriptionBody.ProtoReflect.Descriptor instead.
+func (*AudioTranscriptionBody) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{15}
 }
 
-func (x *ChatCompletionMessage) GetRole() string {
+func (x *AudioTranscriptionBody) GetData() isAudioTranscriptionBody_Data {
 	if x != nil {
-		return x.Role
+		return x.Data
 	}
-	return ""
+	return nil
 }
 
-func (x *ChatCompletionMessage) GetContent() []byte {
+func (x *AudioTranscriptionBody) GetReq() *AudioTranscriptionRequest {
 	if x != nil {
-		return x.Content
+		if x, ok := x.Data.(*AudioTranscriptionBody_Req); ok {
+			return x.Req
+		}
 	}
 	return nil
 }
 
-type ChatCompletionRequest struct {
-	state         protoimpl.MessageState   `protogen:"open.v1"`
-	Project       string                   `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
-	Model         string                   `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
-	Messages      []*ChatCompletionMessage `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
-	Stream        bool                     `protobuf:"varint,4,opt,name=stream,proto3" json:"stream,omitempty"`
-	Cid           string                   `protobuf:"bytes,5,opt,name=cid,proto3" json:"cid,omitempty"`
-	Temperature   float32                  `protobuf:"fixed32,6,opt,name=temperature,proto3" json:"temperature,omitempty"`
-	TopP          float32                  `protobuf:"fixed32,7,opt,name=top_p,json=topP,proto3" json:"top_p,omitempty"`
-	Wallet        *WalletVerification      `protobuf:"bytes,16,opt,name=wallet,proto3" json:"wallet,omitempty"`
+func (x *AudioTranscriptionBody) GetRes() *AudioTranscriptionResponse {
+	if x != nil {
+		if x, ok := x.Data.(*AudioTranscriptionBody_Res); ok {
+			return x.Res
+		}
+	}
+	return nil
+}
+
+type isAudioTranscriptionBody_Data interface {
+	isAudioTranscriptionBody_Data()
+}
+
+type AudioTranscriptionBody_Req struct {
+	Req *AudioTranscriptionRequest `protobuf:"bytes,1,opt,name=req,proto3,oneof"`
+}
+
+type AudioTranscriptionBody_Res struct {
+	Res *AudioTranscriptionResponse `protobuf:"bytes,2,opt,name=res,proto3,oneof"`
+}
+
+func (*AudioTranscriptionBody_Req) isAudioTranscriptionBody_Data() {}
+
+func (*AudioTranscriptionBody_Res) isAudioTranscriptionBody_Data() {}
+
+type AudioTranscriptionRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Project string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Model   string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Cid     string                 `protobuf:"bytes,3,opt,name=cid,proto3" json:"cid,omitempty"`
+	// The uploaded audio file
+	File     []byte `protobuf:"bytes,4,opt,name=file,proto3" json:"file,omitempty"`
+	Filename string `protobuf:"bytes,5,opt,name=filename,proto3" json:"filename,omitempty"`
+	// The other fields of the multipart form, such as language and response_format
+	Fields        map[string]string   `protobuf:"bytes,6,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Wallet        *WalletVerification `protobuf:"bytes,16,opt,name=wallet,proto3" json:"wallet,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatCompletionRequest) Reset() {
-	*x = ChatCompletionRequest{}
-	mi := &file_protocol_proto_msgTypes[13]
+func (x *AudioTranscriptionRequest) Reset() {
+	*x = AudioTranscriptionRequest{}
+	mi := &file_protocol_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatCompletionRequest) String() string {
+func (x *AudioTranscriptionRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatCompletionRequest) ProtoMessage() {}
+func (*AudioTranscriptionRequest) ProtoMessage() {}
 
-func (x *ChatCompletionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[13]
+func (x *AudioTranscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1089,90 +1393,84 @@ func (x *ChatCompletionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatCompletionRequest.ProtoReflect.Descriptor instead.
-func (*ChatCompletionRequest) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use AudioTranscriptionRequest.ProtoReflect.Descriptor instead.
+func (*AudioTranscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{16}
 }
 
-func (x *ChatCompletionRequest) GetProject() string {
+func (x *AudioTranscriptionRequest) GetProject() string {
 	if x != nil {
 		return x.Project
 	}
 	return ""
 }
 
-func (x *ChatCompletionRequest) GetModel() string {
+func (x *AudioTranscriptionRequest) GetModel() string {
 	if x != nil {
 		return x.Model
 	}
 	return ""
 }
 
-func (x *ChatCompletionRequest) GetMessages() []*ChatCompletionMessage {
+func (x *AudioTranscriptionRequest) GetCid() string {
 	if x != nil {
-		return x.Messages
+		return x.Cid
 	}
-	return nil
+	return ""
 }
 
-func (x *ChatCompletionRequest) GetStream() bool {
+func (x *AudioTranscriptionRequest) GetFile() []byte {
 	if x != nil {
-		return x.Stream
+		return x.File
 	}
-	return false
+	return nil
 }
 
-func (x *ChatCompletionRequest) GetCid() string {
+func (x *AudioTranscriptionRequest) GetFilename() string {
 	if x != nil {
-		return x.Cid
+		return x.Filename
 	}
 	return ""
 }
 
-func (x *ChatCompletionRequest) GetTemperature() float32 {
-	if x != nil {
-		return x.Temperature
-	}
-	return 0
-}
-
-func (x *ChatCompletionRequest) GetTopP() float32 {
+func (x *AudioTranscriptionRequest) GetFields() map[string]string {
 	if x != nil {
-		return x.TopP
+		return x.Fields
 	}
-	return 0
+	return nil
 }
 
-func (x *ChatCompletionRequest) GetWallet() *WalletVerification {
+func (x *AudioTranscriptionRequest) GetWallet() *WalletVerification {
 	if x != nil {
 		return x.Wallet
 	}
 	return nil
 }
 
-type ChatCompletionResponseMessage struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
-	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+type AudioTranscriptionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Body of the model response, JSON or the text formats
+	Data          []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	ContentType   string `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatCompletionResponseMessage) Reset() {
-	*x = ChatCompletionResponseMessage{}
-	mi := &file_protocol_proto_msgTypes[14]
+func (x *AudioTranscriptionResponse) Reset() {
+	*x = AudioTranscriptionResponse{}
+	mi := &file_protocol_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatCompletionResponseMessage) String() string {
+func (x *AudioTranscriptionResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatCompletionResponseMessage) ProtoMessage() {}
+func (*AudioTranscriptionResponse) ProtoMessage() {}
 
-func (x *ChatCompletionResponseMessage) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[14]
+func (x *AudioTranscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1183,51 +1481,51 @@ func (x *ChatCompletionResponseMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatCompletionResponseMessage.ProtoReflect.Descriptor instead.
-func (*ChatCompletionResponseMessage) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use AudioTranscriptionResponse.ProtoReflect.Descriptor instead.
+func (*AudioTranscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{17}
 }
 
-func (x *ChatCompletionResponseMessage) GetRole() string {
+func (x *AudioTranscriptionResponse) GetData() []byte {
 	if x != nil {
-		return x.Role
+		return x.Data
 	}
-	return ""
+	return nil
 }
 
-func (x *ChatCompletionResponseMessage) GetContent() string {
+func (x *AudioTranscriptionResponse) GetContentType() string {
 	if x != nil {
-		return x.Content
+		return x.ContentType
 	}
 	return ""
 }
 
-type ChatCompletionResponse struct {
-	state         protoimpl.MessageState                       `protogen:"open.v1"`
-	Created       int64                                        `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
-	Choices       []*ChatCompletionResponse_ChatResponseChoice `protobuf:"bytes,2,rep,name=choices,proto3" json:"choices,omitempty"`
-	Usage         *ChatCompletionResponse_ChatResponseUsage    `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
-	Id            string                                       `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
-	Object        string                                       `protobuf:"bytes,5,opt,name=object,proto3" json:"object,omitempty"`
+type AudioSpeechBody struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*AudioSpeechBody_Req
+	//	*AudioSpeechBody_Res
+	Data          isAudioSpeechBody_Data `protobuf_oneof:"data"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatCompletionResponse) Reset() {
-	*x = ChatCompletionResponse{}
-	mi := &file_protocol_proto_msgTypes[15]
+func (x *AudioSpeechBody) Reset() {
+	*x = AudioSpeechBody{}
+	mi := &file_protocol_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatCompletionResponse) String() string {
+func (x *AudioSpeechBody) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatCompletionResponse) ProtoMessage() {}
+func (*AudioSpeechBody) ProtoMessage() {}
 
-func (x *ChatCompletionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[15]
+func (x *AudioSpeechBody) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1238,72 +1536,81 @@ func (x *ChatCompletionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatCompletionResponse.ProtoReflect.Descriptor instead.
-func (*ChatCompletionResponse) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use AudioSpeechBody.ProtoReflect.Descriptor instead.
+func (*AudioSpeechBody) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{18}
 }
 
-func (x *ChatCompletionResponse) GetCreated() int64 {
+func (x *AudioSpeechBody) GetData() isAudioSpeechBody_Data {
 	if x != nil {
-		return x.Created
+		return x.Data
 	}
-	return 0
+	return nil
 }
 
-func (x *ChatCompletionResponse) GetChoices() []*ChatCompletionResponse_ChatResponseChoice {
+func (x *AudioSpeechBody) GetReq() *AudioSpeechRequest {
 	if x != nil {
-		return x.Choices
+		if x, ok := x.Data.(*AudioSpeechBody_Req); ok {
+			return x.Req
+		}
 	}
 	return nil
 }
 
-func (x *ChatCompletionResponse) GetUsage() *ChatCompletionResponse_ChatResponseUsage {
+func (x *AudioSpeechBody) GetRes() *AudioSpeechResponse {
 	if x != nil {
-		return x.Usage
+		if x, ok := x.Data.(*AudioSpeechBody_Res); ok {
+			return x.Res
+		}
 	}
 	return nil
 }
 
-func (x *ChatCompletionResponse) GetId() string {
-	if x != nil {
-		return x.Id
-	}
-	return ""
+type isAudioSpeechBody_Data interface {
+	isAudioSpeechBody_Data()
 }
 
-func (x *ChatCompletionResponse) GetObject() string {
-	if x != nil {
-		return x.Object
-	}
-	return ""
+type AudioSpeechBody_Req struct {
+	Req *AudioSpeechRequest `protobuf:"bytes,1,opt,name=req,proto3,oneof"`
 }
 
-type HostInfoBody struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Data:
-	//
-	//	*HostInfoBody_Req
-	//	*HostInfoBody_Res
-	Data          isHostInfoBody_Data `protobuf_oneof:"data"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type AudioSpeechBody_Res struct {
+	Res *AudioSpeechResponse `protobuf:"bytes,2,opt,name=res,proto3,oneof"`
 }
 
-func (x *HostInfoBody) Reset() {
-	*x = HostInfoBody{}
-	mi := &file_protocol_proto_msgTypes[16]
+func (*AudioSpeechBody_Req) isAudioSpeechBody_Data() {}
+
+func (*AudioSpeechBody_Res) isAudioSpeechBody_Data() {}
+
+type AudioSpeechRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Project        string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Model          string                 `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Cid            string                 `protobuf:"bytes,3,opt,name=cid,proto3" json:"cid,omitempty"`
+	Input          string                 `protobuf:"bytes,4,opt,name=input,proto3" json:"input,omitempty"`
+	Voice          string                 `protobuf:"bytes,5,opt,name=voice,proto3" json:"voice,omitempty"`
+	ResponseFormat string                 `protobuf:"bytes,6,opt,name=response_format,json=responseFormat,proto3" json:"response_format,omitempty"`
+	Speed          float32                `protobuf:"fixed32,7,opt,name=speed,proto3" json:"speed,omitempty"`
+	Wallet         *WalletVerification    `protobuf:"bytes,16,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *AudioSpeechRequest) Reset() {
+	*x = AudioSpeechRequest{}
+	mi := &file_protocol_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HostInfoBody) String() string {
+func (x *AudioSpeechRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HostInfoBody) ProtoMessage() {}
+func (*AudioSpeechRequest) ProtoMessage() {}
 
-func (x *HostInfoBody) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[16]
+func (x *AudioSpeechRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1314,73 +1621,1396 @@ func (x *HostInfoBody) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HostInfoBody.ProtoReflect.Descriptor instead.
-func (*HostInfoBody) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{16}
+// Deprecated: Use AudioSpeechRequest.ProtoReflect.Descriptor instead.
+func (*AudioSpeechRequest) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *HostInfoBody) GetData() isHostInfoBody_Data {
+func (x *AudioSpeechRequest) GetProject() string {
 	if x != nil {
-		return x.Data
+		return x.Project
 	}
-	return nil
+	return ""
 }
 
-func (x *HostInfoBody) GetReq() *HostInfoRequest {
+func (x *AudioSpeechRequest) GetModel() string {
 	if x != nil {
-		if x, ok := x.Data.(*HostInfoBody_Req); ok {
-			return x.Req
-		}
+		return x.Model
 	}
-	return nil
+	return ""
 }
 
-func (x *HostInfoBody) GetRes() *HostInfoResponse {
+func (x *AudioSpeechRequest) GetCid() string {
 	if x != nil {
-		if x, ok := x.Data.(*HostInfoBody_Res); ok {
-			return x.Res
-		}
+		return x.Cid
 	}
-	return nil
+	return ""
 }
 
-type isHostInfoBody_Data interface {
-	isHostInfoBody_Data()
-}
+func (x *AudioSpeechRequest) GetInput() string {
+	if x != nil {
+		return x.Input
+	}
+	return ""
+}
+
+func (x *AudioSpeechRequest) GetVoice() string {
+	if x != nil {
+		return x.Voice
+	}
+	return ""
+}
+
+func (x *AudioSpeechRequest) GetResponseFormat() string {
+	if x != nil {
+		return x.ResponseFormat
+	}
+	return ""
+}
+
+func (x *AudioSpeechRequest) GetSpeed() float32 {
+	if x != nil {
+		return x.Speed
+	}
+	return 0
+}
+
+func (x *AudioSpeechRequest) GetWallet() *WalletVerification {
+	if x != nil {
+		return x.Wallet
+	}
+	return nil
+}
+
+type AudioSpeechResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Audio         []byte                 `protobuf:"bytes,1,opt,name=audio,proto3" json:"audio,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AudioSpeechResponse) Reset() {
+	*x = AudioSpeechResponse{}
+	mi := &file_protocol_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AudioSpeechResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AudioSpeechResponse) ProtoMessage() {}
+
+func (x *AudioSpeechResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AudioSpeechResponse.ProtoReflect.Descriptor instead.
+func (*AudioSpeechResponse) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *AudioSpeechResponse) GetAudio() []byte {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+func (x *AudioSpeechResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type ChatCompletionBody struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*ChatCompletionBody_Req
+	//	*ChatCompletionBody_Res
+	Data          isChatCompletionBody_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatCompletionBody) Reset() {
+	*x = ChatCompletionBody{}
+	mi := &file_protocol_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatCompletionBody) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatCompletionBody) ProtoMessage() {}
+
+func (x *ChatCompletionBody) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatCompletionBody.ProtoReflect.Descriptor instead.
+func (*ChatCompletionBody) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ChatCompletionBody) GetData() isChatCompletionBody_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *ChatCompletionBody) GetReq() *ChatCompletionRequest {
+	if x != nil {
+		if x, ok := x.Data.(*ChatCompletionBody_Req); ok {
+			return x.Req
+		}
+	}
+	return nil
+}
+
+func (x *ChatCompletionBody) GetRes() *ChatCompletionResponse {
+	if x != nil {
+		if x, ok := x.Data.(*ChatCompletionBody_Res); ok {
+			return x.Res
+		}
+	}
+	return nil
+}
+
+type isChatCompletionBody_Data interface {
+	isChatCompletionBody_Data()
+}
+
+type ChatCompletionBody_Req struct {
+	Req *ChatCompletionRequest `protobuf:"bytes,1,opt,name=req,proto3,oneof"`
+}
+
+type ChatCompletionBody_Res struct {
+	Res *ChatCompletionResponse `protobuf:"bytes,2,opt,name=res,proto3,oneof"`
+}
+
+func (*ChatCompletionBody_Req) isChatCompletionBody_Data() {}
+
+func (*ChatCompletionBody_Res) isChatCompletionBody_Data() {}
+
+type ChatContentPart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          ChatContentPart_Type   `protobuf:"varint,1,opt,name=type,proto3,enum=protocol.ChatContentPart_Type" json:"type,omitempty"`
+	Text          *ChatContentPart_Text  `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Image         *ChatContentPart_Image `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Audio         *ChatContentPart_Audio `protobuf:"bytes,4,opt,name=audio,proto3" json:"audio,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatContentPart) Reset() {
+	*x = ChatContentPart{}
+	mi := &file_protocol_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatContentPart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatContentPart) ProtoMessage() {}
+
+func (x *ChatContentPart) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatContentPart.ProtoReflect.Descriptor instead.
+func (*ChatContentPart) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ChatContentPart) GetType() ChatContentPart_Type {
+	if x != nil {
+		return x.Type
+	}
+	return ChatContentPart_TEXT
+}
+
+func (x *ChatContentPart) GetText() *ChatContentPart_Text {
+	if x != nil {
+		return x.Text
+	}
+	return nil
+}
+
+func (x *ChatContentPart) GetImage() *ChatContentPart_Image {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *ChatContentPart) GetAudio() *ChatContentPart_Audio {
+	if x != nil {
+		return x.Audio
+	}
+	return nil
+}
+
+type ChatContentParts struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Parts         []*ChatContentPart     `protobuf:"bytes,1,rep,name=parts,proto3" json:"parts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatContentParts) Reset() {
+	*x = ChatContentParts{}
+	mi := &file_protocol_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatContentParts) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatContentParts) ProtoMessage() {}
+
+func (x *ChatContentParts) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatContentParts.ProtoReflect.Descriptor instead.
+func (*ChatContentParts) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ChatContentParts) GetParts() []*ChatContentPart {
+	if x != nil {
+		return x.Parts
+	}
+	return nil
+}
+
+type ChatToolCall struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Function      *ChatToolCall_Function `protobuf:"bytes,3,opt,name=function,proto3" json:"function,omitempty"`
+	Index         *int32                 `protobuf:"varint,4,opt,name=index,proto3,oneof" json:"index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatToolCall) Reset() {
+	*x = ChatToolCall{}
+	mi := &file_protocol_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatToolCall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatToolCall) ProtoMessage() {}
+
+func (x *ChatToolCall) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatToolCall.ProtoReflect.Descriptor instead.
+func (*ChatToolCall) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ChatToolCall) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChatToolCall) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ChatToolCall) GetFunction() *ChatToolCall_Function {
+	if x != nil {
+		return x.Function
+	}
+	return nil
+}
+
+func (x *ChatToolCall) GetIndex() int32 {
+	if x != nil && x.Index != nil {
+		return *x.Index
+	}
+	return 0
+}
+
+type ChatCompletionMessage struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Role  string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	// JSON content for the old nodes, only decoded when content_version is 0,
+	// it is not set for the content parts with images or audios since content_version 1
+	Content []byte `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	// Since content_version 1, a text or the content parts
+	//
+	// Types that are valid to be assigned to TypedContent:
+	//
+	//	*ChatCompletionMessage_Text
+	//	*ChatCompletionMessage_Parts
+	TypedContent   isChatCompletionMessage_TypedContent `protobuf_oneof:"typed_content"`
+	ContentVersion uint32                               `protobuf:"varint,8,opt,name=content_version,json=contentVersion,proto3" json:"content_version,omitempty"`
+	Name           string                               `protobuf:"bytes,4,opt,name=name,proto3" json:"name,omitempty"`
+	ToolCalls      []*ChatToolCall                      `protobuf:"bytes,5,rep,name=tool_calls,json=toolCalls,proto3" json:"tool_calls,omitempty"`
+	ToolCallId     string                               `protobuf:"bytes,6,opt,name=tool_call_id,json=toolCallId,proto3" json:"tool_call_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ChatCompletionMessage) Reset() {
+	*x = ChatCompletionMessage{}
+	mi := &file_protocol_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatCompletionMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatCompletionMessage) ProtoMessage() {}
+
+func (x *ChatCompletionMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatCompletionMessage.ProtoReflect.Descriptor instead.
+func (*ChatCompletionMessage) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ChatCompletionMessage) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ChatCompletionMessage) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *ChatCompletionMessage) GetTypedContent() isChatCompletionMessage_TypedContent {
+	if x != nil {
+		return x.TypedContent
+	}
+	return nil
+}
+
+func (x *ChatCompletionMessage) GetText() string {
+	if x != nil {
+		if x, ok := x.TypedContent.(*ChatCompletionMessage_Text); ok {
+			return x.Text
+		}
+	}
+	return ""
+}
+
+func (x *ChatCompletionMessage) GetParts() *ChatContentParts {
+	if x != nil {
+		if x, ok := x.TypedContent.(*ChatCompletionMessage_Parts); ok {
+			return x.Parts
+		}
+	}
+	return nil
+}
+
+func (x *ChatCompletionMessage) GetContentVersion() uint32 {
+	if x != nil {
+		return x.ContentVersion
+	}
+	return 0
+}
+
+func (x *ChatCompletionMessage) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChatCompletionMessage) GetToolCalls() []*ChatToolCall {
+	if x != nil {
+		return x.ToolCalls
+	}
+	return nil
+}
+
+func (x *ChatCompletionMessage) GetToolCallId() string {
+	if x != nil {
+		return x.ToolCallId
+	}
+	return ""
+}
+
+type isChatCompletionMessage_TypedContent interface {
+	isChatCompletionMessage_TypedContent()
+}
+
+type ChatCompletionMessage_Text struct {
+	Text string `protobuf:"bytes,3,opt,name=text,proto3,oneof"`
+}
+
+type ChatCompletionMessage_Parts struct {
+	Parts *ChatContentParts `protobuf:"bytes,7,opt,name=parts,proto3,oneof"`
+}
+
+func (*ChatCompletionMessage_Text) isChatCompletionMessage_TypedContent() {}
+
+func (*ChatCompletionMessage_Parts) isChatCompletionMessage_TypedContent() {}
+
+type ChatTool struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Function      *ChatTool_Function     `protobuf:"bytes,2,opt,name=function,proto3" json:"function,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatTool) Reset() {
+	*x = ChatTool{}
+	mi := &file_protocol_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatTool) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatTool) ProtoMessage() {}
+
+func (x *ChatTool) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatTool.ProtoReflect.Descriptor instead.
+func (*ChatTool) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ChatTool) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ChatTool) GetFunction() *ChatTool_Function {
+	if x != nil {
+		return x.Function
+	}
+	return nil
+}
+
+type ChatResponseFormat struct {
+	state         protoimpl.MessageState         `protogen:"open.v1"`
+	Type          string                         `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	JsonSchema    *ChatResponseFormat_JSONSchema `protobuf:"bytes,2,opt,name=json_schema,json=jsonSchema,proto3" json:"json_schema,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatResponseFormat) Reset() {
+	*x = ChatResponseFormat{}
+	mi := &file_protocol_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatResponseFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatResponseFormat) ProtoMessage() {}
+
+func (x *ChatResponseFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatResponseFormat.ProtoReflect.Descriptor instead.
+func (*ChatResponseFormat) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ChatResponseFormat) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ChatResponseFormat) GetJsonSchema() *ChatResponseFormat_JSONSchema {
+	if x != nil {
+		return x.JsonSchema
+	}
+	return nil
+}
+
+type ChatCompletionRequest struct {
+	state               protoimpl.MessageState   `protogen:"open.v1"`
+	Project             string                   `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Model               string                   `protobuf:"bytes,2,opt,name=model,proto3" json:"model,omitempty"`
+	Messages            []*ChatCompletionMessage `protobuf:"bytes,3,rep,name=messages,proto3" json:"messages,omitempty"`
+	Stream              bool                     `protobuf:"varint,4,opt,name=stream,proto3" json:"stream,omitempty"`
+	Cid                 string                   `protobuf:"bytes,5,opt,name=cid,proto3" json:"cid,omitempty"`
+	Temperature         *float32                 `protobuf:"fixed32,6,opt,name=temperature,proto3,oneof" json:"temperature,omitempty"`
+	TopP                *float32                 `protobuf:"fixed32,7,opt,name=top_p,json=topP,proto3,oneof" json:"top_p,omitempty"`
+	MaxTokens           int32                    `protobuf:"varint,8,opt,name=max_tokens,json=maxTokens,proto3" json:"max_tokens,omitempty"`
+	MaxCompletionTokens int32                    `protobuf:"varint,9,opt,name=max_completion_tokens,json=maxCompletionTokens,proto3" json:"max_completion_tokens,omitempty"`
+	Stop                []string                 `protobuf:"bytes,10,rep,name=stop,proto3" json:"stop,omitempty"`
+	N                   int32                    `protobuf:"varint,11,opt,name=n,proto3" json:"n,omitempty"`
+	Seed                *int64                   `protobuf:"varint,12,opt,name=seed,proto3,oneof" json:"seed,omitempty"`
+	FrequencyPenalty    float32                  `protobuf:"fixed32,13,opt,name=frequency_penalty,json=frequencyPenalty,proto3" json:"frequency_penalty,omitempty"`
+	PresencePenalty     float32                  `protobuf:"fixed32,14,opt,name=presence_penalty,json=presencePenalty,proto3" json:"presence_penalty,omitempty"`
+	LogitBias           map[string]int32         `protobuf:"bytes,15,rep,name=logit_bias,json=logitBias,proto3" json:"logit_bias,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	Wallet              *WalletVerification      `protobuf:"bytes,16,opt,name=wallet,proto3" json:"wallet,omitempty"`
+	Logprobs            bool                     `protobuf:"varint,17,opt,name=logprobs,proto3" json:"logprobs,omitempty"`
+	TopLogprobs         int32                    `protobuf:"varint,18,opt,name=top_logprobs,json=topLogprobs,proto3" json:"top_logprobs,omitempty"`
+	ResponseFormat      *ChatResponseFormat      `protobuf:"bytes,19,opt,name=response_format,json=responseFormat,proto3" json:"response_format,omitempty"`
+	Tools               []*ChatTool              `protobuf:"bytes,20,rep,name=tools,proto3" json:"tools,omitempty"`
+	// JSON string or object
+	ToolChoice         []byte `protobuf:"bytes,21,opt,name=tool_choice,json=toolChoice,proto3" json:"tool_choice,omitempty"`
+	ParallelToolCalls  *bool  `protobuf:"varint,22,opt,name=parallel_tool_calls,json=parallelToolCalls,proto3,oneof" json:"parallel_tool_calls,omitempty"`
+	StreamIncludeUsage *bool  `protobuf:"varint,23,opt,name=stream_include_usage,json=streamIncludeUsage,proto3,oneof" json:"stream_include_usage,omitempty"`
+	User               string `protobuf:"bytes,24,opt,name=user,proto3" json:"user,omitempty"`
+	// Since version 1, temperature and top_p are only set by the requests setting them,
+	// the old nodes always send them and omit the explicit 0
+	Version       uint32 `protobuf:"varint,25,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatCompletionRequest) Reset() {
+	*x = ChatCompletionRequest{}
+	mi := &file_protocol_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatCompletionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatCompletionRequest) ProtoMessage() {}
+
+func (x *ChatCompletionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatCompletionRequest.ProtoReflect.Descriptor instead.
+func (*ChatCompletionRequest) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *ChatCompletionRequest) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *ChatCompletionRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ChatCompletionRequest) GetMessages() []*ChatCompletionMessage {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *ChatCompletionRequest) GetStream() bool {
+	if x != nil {
+		return x.Stream
+	}
+	return false
+}
+
+func (x *ChatCompletionRequest) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+func (x *ChatCompletionRequest) GetTemperature() float32 {
+	if x != nil && x.Temperature != nil {
+		return *x.Temperature
+	}
+	return 0
+}
+
+func (x *ChatCompletionRequest) GetTopP() float32 {
+	if x != nil && x.TopP != nil {
+		return *x.TopP
+	}
+	return 0
+}
+
+func (x *ChatCompletionRequest) GetMaxTokens() int32 {
+	if x != nil {
+		return x.MaxTokens
+	}
+	return 0
+}
+
+func (x *ChatCompletionRequest) GetMaxCompletionTokens() int32 {
+	if x != nil {
+		return x.MaxCompletionTokens
+	}
+	return 0
+}
+
+func (x *ChatCompletionRequest) GetStop() []string {
+	if x != nil {
+		return x.Stop
+	}
+	return nil
+}
+
+func (x *ChatCompletionRequest) GetN() int32 {
+	if x != nil {
+		return x.N
+	}
+	return 0
+}
+
+func (x *ChatCompletionRequest) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+func (x *ChatCompletionRequest) GetFrequencyPenalty() float32 {
+	if x != nil {
+		return x.FrequencyPenalty
+	}
+	return 0
+}
+
+func (x *ChatCompletionRequest) GetPresencePenalty() float32 {
+	if x != nil {
+		return x.PresencePenalty
+	}
+	return 0
+}
+
+func (x *ChatCompletionRequest) GetLogitBias() map[string]int32 {
+	if x != nil {
+		return x.LogitBias
+	}
+	return nil
+}
+
+func (x *ChatCompletionRequest) GetWallet() *WalletVerification {
+	if x != nil {
+		return x.Wallet
+	}
+	return nil
+}
+
+func (x *ChatCompletionRequest) GetLogprobs() bool {
+	if x != nil {
+		return x.Logprobs
+	}
+	return false
+}
+
+func (x *ChatCompletionRequest) GetTopLogprobs() int32 {
+	if x != nil {
+		return x.TopLogprobs
+	}
+	return 0
+}
+
+func (x *ChatCompletionRequest) GetResponseFormat() *ChatResponseFormat {
+	if x != nil {
+		return x.ResponseFormat
+	}
+	return nil
+}
+
+func (x *ChatCompletionRequest) GetTools() []*ChatTool {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
+}
+
+func (x *ChatCompletionRequest) GetToolChoice() []byte {
+	if x != nil {
+		return x.ToolChoice
+	}
+	return nil
+}
+
+func (x *ChatCompletionRequest) GetParallelToolCalls() bool {
+	if x != nil && x.ParallelToolCalls != nil {
+		return *x.ParallelToolCalls
+	}
+	return false
+}
+
+func (x *ChatCompletionRequest) GetStreamIncludeUsage() bool {
+	if x != nil && x.StreamIncludeUsage != nil {
+		return *x.StreamIncludeUsage
+	}
+	return false
+}
+
+func (x *ChatCompletionRequest) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *ChatCompletionRequest) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type ChatCompletionResponseMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Role          string                 `protobuf:"bytes,1,opt,name=role,proto3" json:"role,omitempty"`
+	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	ToolCalls     []*ChatToolCall        `protobuf:"bytes,3,rep,name=tool_calls,json=toolCalls,proto3" json:"tool_calls,omitempty"`
+	Refusal       string                 `protobuf:"bytes,4,opt,name=refusal,proto3" json:"refusal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatCompletionResponseMessage) Reset() {
+	*x = ChatCompletionResponseMessage{}
+	mi := &file_protocol_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatCompletionResponseMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatCompletionResponseMessage) ProtoMessage() {}
+
+func (x *ChatCompletionResponseMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatCompletionResponseMessage.ProtoReflect.Descriptor instead.
+func (*ChatCompletionResponseMessage) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ChatCompletionResponseMessage) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
+func (x *ChatCompletionResponseMessage) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ChatCompletionResponseMessage) GetToolCalls() []*ChatToolCall {
+	if x != nil {
+		return x.ToolCalls
+	}
+	return nil
+}
+
+func (x *ChatCompletionResponseMessage) GetRefusal() string {
+	if x != nil {
+		return x.Refusal
+	}
+	return ""
+}
+
+type ChatCompletionResponse struct {
+	state             protoimpl.MessageState                       `protogen:"open.v1"`
+	Created           int64                                        `protobuf:"varint,1,opt,name=created,proto3" json:"created,omitempty"`
+	Choices           []*ChatCompletionResponse_ChatResponseChoice `protobuf:"bytes,2,rep,name=choices,proto3" json:"choices,omitempty"`
+	Usage             *ChatCompletionResponse_ChatResponseUsage    `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
+	Id                string                                       `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	Object            string                                       `protobuf:"bytes,5,opt,name=object,proto3" json:"object,omitempty"`
+	Model             string                                       `protobuf:"bytes,6,opt,name=model,proto3" json:"model,omitempty"`
+	SystemFingerprint string                                       `protobuf:"bytes,7,opt,name=system_fingerprint,json=systemFingerprint,proto3" json:"system_fingerprint,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ChatCompletionResponse) Reset() {
+	*x = ChatCompletionResponse{}
+	mi := &file_protocol_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatCompletionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatCompletionResponse) ProtoMessage() {}
+
+func (x *ChatCompletionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatCompletionResponse.ProtoReflect.Descriptor instead.
+func (*ChatCompletionResponse) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ChatCompletionResponse) GetCreated() int64 {
+	if x != nil {
+		return x.Created
+	}
+	return 0
+}
+
+func (x *ChatCompletionResponse) GetChoices() []*ChatCompletionResponse_ChatResponseChoice {
+	if x != nil {
+		return x.Choices
+	}
+	return nil
+}
+
+func (x *ChatCompletionResponse) GetUsage() *ChatCompletionResponse_ChatResponseUsage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *ChatCompletionResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChatCompletionResponse) GetObject() string {
+	if x != nil {
+		return x.Object
+	}
+	return ""
+}
+
+func (x *ChatCompletionResponse) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *ChatCompletionResponse) GetSystemFingerprint() string {
+	if x != nil {
+		return x.SystemFingerprint
+	}
+	return ""
+}
+
+type HostInfoBody struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*HostInfoBody_Req
+	//	*HostInfoBody_Res
+	Data          isHostInfoBody_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostInfoBody) Reset() {
+	*x = HostInfoBody{}
+	mi := &file_protocol_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostInfoBody) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostInfoBody) ProtoMessage() {}
+
+func (x *HostInfoBody) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostInfoBody.ProtoReflect.Descriptor instead.
+func (*HostInfoBody) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *HostInfoBody) GetData() isHostInfoBody_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *HostInfoBody) GetReq() *HostInfoRequest {
+	if x != nil {
+		if x, ok := x.Data.(*HostInfoBody_Req); ok {
+			return x.Req
+		}
+	}
+	return nil
+}
+
+func (x *HostInfoBody) GetRes() *HostInfoResponse {
+	if x != nil {
+		if x, ok := x.Data.(*HostInfoBody_Res); ok {
+			return x.Res
+		}
+	}
+	return nil
+}
+
+type isHostInfoBody_Data interface {
+	isHostInfoBody_Data()
+}
+
+type HostInfoBody_Req struct {
+	Req *HostInfoRequest `protobuf:"bytes,1,opt,name=req,proto3,oneof"`
+}
+
+type HostInfoBody_Res struct {
+	Res *HostInfoResponse `protobuf:"bytes,2,opt,name=res,proto3,oneof"`
+}
+
+func (*HostInfoBody_Req) isHostInfoBody_Data() {}
+
+func (*HostInfoBody_Res) isHostInfoBody_Data() {}
+
+type HostInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostInfoRequest) Reset() {
+	*x = HostInfoRequest{}
+	mi := &file_protocol_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostInfoRequest) ProtoMessage() {}
+
+func (x *HostInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostInfoRequest.ProtoReflect.Descriptor instead.
+func (*HostInfoRequest) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{32}
+}
+
+type HostInfoResponse struct {
+	state         protoimpl.MessageState       `protogen:"open.v1"`
+	Os            *HostInfoResponse_OSInfo     `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`
+	Cpu           []*HostInfoResponse_CpuInfo  `protobuf:"bytes,2,rep,name=cpu,proto3" json:"cpu,omitempty"`
+	Memory        *HostInfoResponse_MemoryInfo `protobuf:"bytes,3,opt,name=memory,proto3" json:"memory,omitempty"`
+	Disk          []*HostInfoResponse_DiskInfo `protobuf:"bytes,4,rep,name=disk,proto3" json:"disk,omitempty"`
+	Gpu           []*HostInfoResponse_GpuInfo  `protobuf:"bytes,5,rep,name=gpu,proto3" json:"gpu,omitempty"`
+	Metrics       *HostMetrics                 `protobuf:"bytes,6,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostInfoResponse) Reset() {
+	*x = HostInfoResponse{}
+	mi := &file_protocol_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostInfoResponse) ProtoMessage() {}
+
+func (x *HostInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostInfoResponse.ProtoReflect.Descriptor instead.
+func (*HostInfoResponse) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *HostInfoResponse) GetOs() *HostInfoResponse_OSInfo {
+	if x != nil {
+		return x.Os
+	}
+	return nil
+}
+
+func (x *HostInfoResponse) GetCpu() []*HostInfoResponse_CpuInfo {
+	if x != nil {
+		return x.Cpu
+	}
+	return nil
+}
+
+func (x *HostInfoResponse) GetMemory() *HostInfoResponse_MemoryInfo {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+func (x *HostInfoResponse) GetDisk() []*HostInfoResponse_DiskInfo {
+	if x != nil {
+		return x.Disk
+	}
+	return nil
+}
+
+func (x *HostInfoResponse) GetGpu() []*HostInfoResponse_GpuInfo {
+	if x != nil {
+		return x.Gpu
+	}
+	return nil
+}
+
+func (x *HostInfoResponse) GetMetrics() *HostMetrics {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+type HostMetrics struct {
+	state         protoimpl.MessageState    `protogen:"open.v1"`
+	Timestamp     int64                     `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Cpu           *HostMetrics_CpuMetrics   `protobuf:"bytes,2,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Memory        *HostMetrics_MemMetrics   `protobuf:"bytes,3,opt,name=memory,proto3" json:"memory,omitempty"`
+	Gpu           []*HostMetrics_GpuMetrics `protobuf:"bytes,4,rep,name=gpu,proto3" json:"gpu,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostMetrics) Reset() {
+	*x = HostMetrics{}
+	mi := &file_protocol_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostMetrics) ProtoMessage() {}
+
+func (x *HostMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostMetrics.ProtoReflect.Descriptor instead.
+func (*HostMetrics) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *HostMetrics) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *HostMetrics) GetCpu() *HostMetrics_CpuMetrics {
+	if x != nil {
+		return x.Cpu
+	}
+	return nil
+}
+
+func (x *HostMetrics) GetMemory() *HostMetrics_MemMetrics {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+func (x *HostMetrics) GetGpu() []*HostMetrics_GpuMetrics {
+	if x != nil {
+		return x.Gpu
+	}
+	return nil
+}
+
+type AIProjectBody struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*AIProjectBody_Req
+	//	*AIProjectBody_Res
+	Data          isAIProjectBody_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AIProjectBody) Reset() {
+	*x = AIProjectBody{}
+	mi := &file_protocol_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AIProjectBody) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AIProjectBody) ProtoMessage() {}
+
+func (x *AIProjectBody) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
 
-type HostInfoBody_Req struct {
-	Req *HostInfoRequest `protobuf:"bytes,1,opt,name=req,proto3,oneof"`
+// Deprecated: Use AIProjectBody.ProtoReflect.Descriptor instead.
+func (*AIProjectBody) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{35}
 }
 
-type HostInfoBody_Res struct {
-	Res *HostInfoResponse `protobuf:"bytes,2,opt,name=res,proto3,oneof"`
+func (x *AIProjectBody) GetData() isAIProjectBody_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
 }
 
-func (*HostInfoBody_Req) isHostInfoBody_Data() {}
+func (x *AIProjectBody) GetReq() *AIProjectRequest {
+	if x != nil {
+		if x, ok := x.Data.(*AIProjectBody_Req); ok {
+			return x.Req
+		}
+	}
+	return nil
+}
 
-func (*HostInfoBody_Res) isHostInfoBody_Data() {}
+func (x *AIProjectBody) GetRes() *AIProjectResponse {
+	if x != nil {
+		if x, ok := x.Data.(*AIProjectBody_Res); ok {
+			return x.Res
+		}
+	}
+	return nil
+}
 
-type HostInfoRequest struct {
+type isAIProjectBody_Data interface {
+	isAIProjectBody_Data()
+}
+
+type AIProjectBody_Req struct {
+	Req *AIProjectRequest `protobuf:"bytes,1,opt,name=req,proto3,oneof"`
+}
+
+type AIProjectBody_Res struct {
+	Res *AIProjectResponse `protobuf:"bytes,2,opt,name=res,proto3,oneof"`
+}
+
+func (*AIProjectBody_Req) isAIProjectBody_Data() {}
+
+func (*AIProjectBody_Res) isAIProjectBody_Data() {}
+
+type AIModelOfProject struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	Api           string                 `protobuf:"bytes,2,opt,name=api,proto3" json:"api,omitempty"`
+	Type          uint32                 `protobuf:"varint,3,opt,name=type,proto3" json:"type,omitempty"`
+	Idle          uint32                 `protobuf:"varint,4,opt,name=idle,proto3" json:"idle,omitempty"`
+	Cid           string                 `protobuf:"bytes,5,opt,name=cid,proto3" json:"cid,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HostInfoRequest) Reset() {
-	*x = HostInfoRequest{}
-	mi := &file_protocol_proto_msgTypes[17]
+func (x *AIModelOfProject) Reset() {
+	*x = AIModelOfProject{}
+	mi := &file_protocol_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HostInfoRequest) String() string {
+func (x *AIModelOfProject) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HostInfoRequest) ProtoMessage() {}
+func (*AIModelOfProject) ProtoMessage() {}
 
-func (x *HostInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[17]
+func (x *AIModelOfProject) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1391,37 +3021,240 @@ func (x *HostInfoRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HostInfoRequest.ProtoReflect.Descriptor instead.
-func (*HostInfoRequest) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{17}
+// Deprecated: Use AIModelOfProject.ProtoReflect.Descriptor instead.
+func (*AIModelOfProject) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{36}
 }
 
-type HostInfoResponse struct {
-	state         protoimpl.MessageState       `protogen:"open.v1"`
-	Os            *HostInfoResponse_OSInfo     `protobuf:"bytes,1,opt,name=os,proto3" json:"os,omitempty"`
-	Cpu           []*HostInfoResponse_CpuInfo  `protobuf:"bytes,2,rep,name=cpu,proto3" json:"cpu,omitempty"`
-	Memory        *HostInfoResponse_MemoryInfo `protobuf:"bytes,3,opt,name=memory,proto3" json:"memory,omitempty"`
-	Disk          []*HostInfoResponse_DiskInfo `protobuf:"bytes,4,rep,name=disk,proto3" json:"disk,omitempty"`
-	Gpu           []*HostInfoResponse_GpuInfo  `protobuf:"bytes,5,rep,name=gpu,proto3" json:"gpu,omitempty"`
+func (x *AIModelOfProject) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *AIModelOfProject) GetApi() string {
+	if x != nil {
+		return x.Api
+	}
+	return ""
+}
+
+func (x *AIModelOfProject) GetType() uint32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *AIModelOfProject) GetIdle() uint32 {
+	if x != nil {
+		return x.Idle
+	}
+	return 0
+}
+
+func (x *AIModelOfProject) GetCid() string {
+	if x != nil {
+		return x.Cid
+	}
+	return ""
+}
+
+type AIProjectOfNode struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Project       string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
+	Models        []*AIModelOfProject    `protobuf:"bytes,2,rep,name=models,proto3" json:"models,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AIProjectOfNode) Reset() {
+	*x = AIProjectOfNode{}
+	mi := &file_protocol_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AIProjectOfNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AIProjectOfNode) ProtoMessage() {}
+
+func (x *AIProjectOfNode) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AIProjectOfNode.ProtoReflect.Descriptor instead.
+func (*AIProjectOfNode) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *AIProjectOfNode) GetProject() string {
+	if x != nil {
+		return x.Project
+	}
+	return ""
+}
+
+func (x *AIProjectOfNode) GetModels() []*AIModelOfProject {
+	if x != nil {
+		return x.Models
+	}
+	return nil
+}
+
+type AIProjectRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AIProjectRequest) Reset() {
+	*x = AIProjectRequest{}
+	mi := &file_protocol_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AIProjectRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AIProjectRequest) ProtoMessage() {}
+
+func (x *AIProjectRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AIProjectRequest.ProtoReflect.Descriptor instead.
+func (*AIProjectRequest) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{38}
+}
+
+type AIProjectResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Projects []*AIProjectOfNode     `protobuf:"bytes,1,rep,name=projects,proto3" json:"projects,omitempty"`
+	NodeType uint32                 `protobuf:"varint,2,opt,name=node_type,json=nodeType,proto3" json:"node_type,omitempty"`
+	// Only included in heartbeats when the node opts in
+	Metrics *HostMetrics `protobuf:"bytes,3,opt,name=metrics,proto3" json:"metrics,omitempty"`
+	// Connections of the node, collected for the topology of the network
+	Connections []*PeerConnection `protobuf:"bytes,4,rep,name=connections,proto3" json:"connections,omitempty"`
+	// Included in heartbeats after the key of the node is rotated
+	Rotation      *KeyRotation `protobuf:"bytes,5,opt,name=rotation,proto3" json:"rotation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AIProjectResponse) Reset() {
+	*x = AIProjectResponse{}
+	mi := &file_protocol_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AIProjectResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AIProjectResponse) ProtoMessage() {}
+
+func (x *AIProjectResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AIProjectResponse.ProtoReflect.Descriptor instead.
+func (*AIProjectResponse) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *AIProjectResponse) GetProjects() []*AIProjectOfNode {
+	if x != nil {
+		return x.Projects
+	}
+	return nil
+}
+
+func (x *AIProjectResponse) GetNodeType() uint32 {
+	if x != nil {
+		return x.NodeType
+	}
+	return 0
+}
+
+func (x *AIProjectResponse) GetMetrics() *HostMetrics {
+	if x != nil {
+		return x.Metrics
+	}
+	return nil
+}
+
+func (x *AIProjectResponse) GetConnections() []*PeerConnection {
+	if x != nil {
+		return x.Connections
+	}
+	return nil
+}
+
+func (x *AIProjectResponse) GetRotation() *KeyRotation {
+	if x != nil {
+		return x.Rotation
+	}
+	return nil
+}
+
+type PeerConnection struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	NodeId string                 `protobuf:"bytes,1,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	// Relay node of the connection, empty if it is direct
+	Relay     string `protobuf:"bytes,2,opt,name=relay,proto3" json:"relay,omitempty"`
+	Direction string `protobuf:"bytes,3,opt,name=direction,proto3" json:"direction,omitempty"`
+	// Round-trip time in microseconds
+	Latency       int64 `protobuf:"varint,4,opt,name=latency,proto3" json:"latency,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *HostInfoResponse) Reset() {
-	*x = HostInfoResponse{}
-	mi := &file_protocol_proto_msgTypes[18]
+func (x *PeerConnection) Reset() {
+	*x = PeerConnection{}
+	mi := &file_protocol_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *HostInfoResponse) String() string {
+func (x *PeerConnection) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*HostInfoResponse) ProtoMessage() {}
+func (*PeerConnection) ProtoMessage() {}
 
-func (x *HostInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[18]
+func (x *PeerConnection) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1432,72 +3265,64 @@ func (x *HostInfoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use HostInfoResponse.ProtoReflect.Descriptor instead.
-func (*HostInfoResponse) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{18}
-}
-
-func (x *HostInfoResponse) GetOs() *HostInfoResponse_OSInfo {
-	if x != nil {
-		return x.Os
-	}
-	return nil
+// Deprecated: Use PeerConnection.ProtoReflect.Descriptor instead.
+func (*PeerConnection) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{40}
 }
 
-func (x *HostInfoResponse) GetCpu() []*HostInfoResponse_CpuInfo {
+func (x *PeerConnection) GetNodeId() string {
 	if x != nil {
-		return x.Cpu
+		return x.NodeId
 	}
-	return nil
+	return ""
 }
 
-func (x *HostInfoResponse) GetMemory() *HostInfoResponse_MemoryInfo {
+func (x *PeerConnection) GetRelay() string {
 	if x != nil {
-		return x.Memory
+		return x.Relay
 	}
-	return nil
+	return ""
 }
 
-func (x *HostInfoResponse) GetDisk() []*HostInfoResponse_DiskInfo {
+func (x *PeerConnection) GetDirection() string {
 	if x != nil {
-		return x.Disk
+		return x.Direction
 	}
-	return nil
+	return ""
 }
 
-func (x *HostInfoResponse) GetGpu() []*HostInfoResponse_GpuInfo {
+func (x *PeerConnection) GetLatency() int64 {
 	if x != nil {
-		return x.Gpu
+		return x.Latency
 	}
-	return nil
+	return 0
 }
 
-type AIProjectBody struct {
-	state protoimpl.MessageState `protogen:"open.v1"`
-	// Types that are valid to be assigned to Data:
-	//
-	//	*AIProjectBody_Req
-	//	*AIProjectBody_Res
-	Data          isAIProjectBody_Data `protobuf_oneof:"data"`
+type KeyRotation struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	PreviousNodeId string                 `protobuf:"bytes,1,opt,name=previous_node_id,json=previousNodeId,proto3" json:"previous_node_id,omitempty"`
+	PreviousPubKey []byte                 `protobuf:"bytes,2,opt,name=previous_pub_key,json=previousPubKey,proto3" json:"previous_pub_key,omitempty"`
+	// Signature of the previous key over the node ids
+	Signature     []byte `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AIProjectBody) Reset() {
-	*x = AIProjectBody{}
-	mi := &file_protocol_proto_msgTypes[19]
+func (x *KeyRotation) Reset() {
+	*x = KeyRotation{}
+	mi := &file_protocol_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AIProjectBody) String() string {
+func (x *KeyRotation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AIProjectBody) ProtoMessage() {}
+func (*KeyRotation) ProtoMessage() {}
 
-func (x *AIProjectBody) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[19]
+func (x *KeyRotation) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1508,78 +3333,56 @@ func (x *AIProjectBody) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AIProjectBody.ProtoReflect.Descriptor instead.
-func (*AIProjectBody) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{19}
+// Deprecated: Use KeyRotation.ProtoReflect.Descriptor instead.
+func (*KeyRotation) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{41}
 }
 
-func (x *AIProjectBody) GetData() isAIProjectBody_Data {
+func (x *KeyRotation) GetPreviousNodeId() string {
 	if x != nil {
-		return x.Data
+		return x.PreviousNodeId
 	}
-	return nil
+	return ""
 }
 
-func (x *AIProjectBody) GetReq() *AIProjectRequest {
+func (x *KeyRotation) GetPreviousPubKey() []byte {
 	if x != nil {
-		if x, ok := x.Data.(*AIProjectBody_Req); ok {
-			return x.Req
-		}
+		return x.PreviousPubKey
 	}
 	return nil
 }
 
-func (x *AIProjectBody) GetRes() *AIProjectResponse {
+func (x *KeyRotation) GetSignature() []byte {
 	if x != nil {
-		if x, ok := x.Data.(*AIProjectBody_Res); ok {
-			return x.Res
-		}
+		return x.Signature
 	}
 	return nil
 }
 
-type isAIProjectBody_Data interface {
-	isAIProjectBody_Data()
-}
-
-type AIProjectBody_Req struct {
-	Req *AIProjectRequest `protobuf:"bytes,1,opt,name=req,proto3,oneof"`
-}
-
-type AIProjectBody_Res struct {
-	Res *AIProjectResponse `protobuf:"bytes,2,opt,name=res,proto3,oneof"`
-}
-
-func (*AIProjectBody_Req) isAIProjectBody_Data() {}
-
-func (*AIProjectBody_Res) isAIProjectBody_Data() {}
-
-type AIModelOfProject struct {
+type ImageGenerationResponse_ImageResponseChoice struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
-	Api           string                 `protobuf:"bytes,2,opt,name=api,proto3" json:"api,omitempty"`
-	Type          uint32                 `protobuf:"varint,3,opt,name=type,proto3" json:"type,omitempty"`
-	Idle          uint32                 `protobuf:"varint,4,opt,name=idle,proto3" json:"idle,omitempty"`
-	Cid           string                 `protobuf:"bytes,5,opt,name=cid,proto3" json:"cid,omitempty"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	B64Json       string                 `protobuf:"bytes,2,opt,name=b64_json,json=b64Json,proto3" json:"b64_json,omitempty"`
+	RevisedPrompt string                 `protobuf:"bytes,3,opt,name=revised_prompt,json=revisedPrompt,proto3" json:"revised_prompt,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AIModelOfProject) Reset() {
-	*x = AIModelOfProject{}
-	mi := &file_protocol_proto_msgTypes[20]
+func (x *ImageGenerationResponse_ImageResponseChoice) Reset() {
+	*x = ImageGenerationResponse_ImageResponseChoice{}
+	mi := &file_protocol_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AIModelOfProject) String() string {
+func (x *ImageGenerationResponse_ImageResponseChoice) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AIModelOfProject) ProtoMessage() {}
+func (*ImageGenerationResponse_ImageResponseChoice) ProtoMessage() {}
 
-func (x *AIModelOfProject) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[20]
+func (x *ImageGenerationResponse_ImageResponseChoice) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1590,69 +3393,55 @@ func (x *AIModelOfProject) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AIModelOfProject.ProtoReflect.Descriptor instead.
-func (*AIModelOfProject) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{20}
+// Deprecated: Use ImageGenerationResponse_ImageResponseChoice.ProtoReflect.Descriptor instead.
+func (*ImageGenerationResponse_ImageResponseChoice) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{11, 0}
 }
 
-func (x *AIModelOfProject) GetModel() string {
+func (x *ImageGenerationResponse_ImageResponseChoice) GetUrl() string {
 	if x != nil {
-		return x.Model
+		return x.Url
 	}
 	return ""
 }
 
-func (x *AIModelOfProject) GetApi() string {
+func (x *ImageGenerationResponse_ImageResponseChoice) GetB64Json() string {
 	if x != nil {
-		return x.Api
+		return x.B64Json
 	}
 	return ""
 }
 
-func (x *AIModelOfProject) GetType() uint32 {
-	if x != nil {
-		return x.Type
-	}
-	return 0
-}
-
-func (x *AIModelOfProject) GetIdle() uint32 {
-	if x != nil {
-		return x.Idle
-	}
-	return 0
-}
-
-func (x *AIModelOfProject) GetCid() string {
+func (x *ImageGenerationResponse_ImageResponseChoice) GetRevisedPrompt() string {
 	if x != nil {
-		return x.Cid
+		return x.RevisedPrompt
 	}
 	return ""
 }
 
-type AIProjectOfNode struct {
+type EmbeddingResponse_Embedding struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Project       string                 `protobuf:"bytes,1,opt,name=project,proto3" json:"project,omitempty"`
-	Models        []*AIModelOfProject    `protobuf:"bytes,2,rep,name=models,proto3" json:"models,omitempty"`
+	Index         int32                  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Embedding     []float32              `protobuf:"fixed32,2,rep,packed,name=embedding,proto3" json:"embedding,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AIProjectOfNode) Reset() {
-	*x = AIProjectOfNode{}
-	mi := &file_protocol_proto_msgTypes[21]
+func (x *EmbeddingResponse_Embedding) Reset() {
+	*x = EmbeddingResponse_Embedding{}
+	mi := &file_protocol_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AIProjectOfNode) String() string {
+func (x *EmbeddingResponse_Embedding) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AIProjectOfNode) ProtoMessage() {}
+func (*EmbeddingResponse_Embedding) ProtoMessage() {}
 
-func (x *AIProjectOfNode) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[21]
+func (x *EmbeddingResponse_Embedding) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1663,46 +3452,48 @@ func (x *AIProjectOfNode) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AIProjectOfNode.ProtoReflect.Descriptor instead.
-func (*AIProjectOfNode) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{21}
+// Deprecated: Use EmbeddingResponse_Embedding.ProtoReflect.Descriptor instead.
+func (*EmbeddingResponse_Embedding) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{14, 0}
 }
 
-func (x *AIProjectOfNode) GetProject() string {
+func (x *EmbeddingResponse_Embedding) GetIndex() int32 {
 	if x != nil {
-		return x.Project
+		return x.Index
 	}
-	return ""
+	return 0
 }
 
-func (x *AIProjectOfNode) GetModels() []*AIModelOfProject {
+func (x *EmbeddingResponse_Embedding) GetEmbedding() []float32 {
 	if x != nil {
-		return x.Models
+		return x.Embedding
 	}
 	return nil
 }
 
-type AIProjectRequest struct {
+type ChatContentPart_Text struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AIProjectRequest) Reset() {
-	*x = AIProjectRequest{}
-	mi := &file_protocol_proto_msgTypes[22]
+func (x *ChatContentPart_Text) Reset() {
+	*x = ChatContentPart_Text{}
+	mi := &file_protocol_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AIProjectRequest) String() string {
+func (x *ChatContentPart_Text) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AIProjectRequest) ProtoMessage() {}
+func (*ChatContentPart_Text) ProtoMessage() {}
 
-func (x *AIProjectRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[22]
+func (x *ChatContentPart_Text) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1713,34 +3504,49 @@ func (x *AIProjectRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AIProjectRequest.ProtoReflect.Descriptor instead.
-func (*AIProjectRequest) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{22}
+// Deprecated: Use ChatContentPart_Text.ProtoReflect.Descriptor instead.
+func (*ChatContentPart_Text) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{22, 0}
 }
 
-type AIProjectResponse struct {
+func (x *ChatContentPart_Text) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ChatContentPart_Text) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type ChatContentPart_Image struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Projects      []*AIProjectOfNode     `protobuf:"bytes,1,rep,name=projects,proto3" json:"projects,omitempty"`
-	NodeType      uint32                 `protobuf:"varint,2,opt,name=node_type,json=nodeType,proto3" json:"node_type,omitempty"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AIProjectResponse) Reset() {
-	*x = AIProjectResponse{}
-	mi := &file_protocol_proto_msgTypes[23]
+func (x *ChatContentPart_Image) Reset() {
+	*x = ChatContentPart_Image{}
+	mi := &file_protocol_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AIProjectResponse) String() string {
+func (x *ChatContentPart_Image) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AIProjectResponse) ProtoMessage() {}
+func (*ChatContentPart_Image) ProtoMessage() {}
 
-func (x *AIProjectResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[23]
+func (x *ChatContentPart_Image) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1751,49 +3557,56 @@ func (x *AIProjectResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AIProjectResponse.ProtoReflect.Descriptor instead.
-func (*AIProjectResponse) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{23}
+// Deprecated: Use ChatContentPart_Image.ProtoReflect.Descriptor instead.
+func (*ChatContentPart_Image) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{22, 1}
 }
 
-func (x *AIProjectResponse) GetProjects() []*AIProjectOfNode {
+func (x *ChatContentPart_Image) GetType() string {
 	if x != nil {
-		return x.Projects
+		return x.Type
 	}
-	return nil
+	return ""
 }
 
-func (x *AIProjectResponse) GetNodeType() uint32 {
+func (x *ChatContentPart_Image) GetUrl() string {
 	if x != nil {
-		return x.NodeType
+		return x.Url
 	}
-	return 0
+	return ""
 }
 
-type ImageGenerationResponse_ImageResponseChoice struct {
+func (x *ChatContentPart_Image) GetDetail() string {
+	if x != nil {
+		return x.Detail
+	}
+	return ""
+}
+
+type ChatContentPart_Audio struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
-	B64Json       string                 `protobuf:"bytes,2,opt,name=b64_json,json=b64Json,proto3" json:"b64_json,omitempty"`
-	RevisedPrompt string                 `protobuf:"bytes,3,opt,name=revised_prompt,json=revisedPrompt,proto3" json:"revised_prompt,omitempty"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Data          string                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	Format        string                 `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ImageGenerationResponse_ImageResponseChoice) Reset() {
-	*x = ImageGenerationResponse_ImageResponseChoice{}
-	mi := &file_protocol_proto_msgTypes[24]
+func (x *ChatContentPart_Audio) Reset() {
+	*x = ChatContentPart_Audio{}
+	mi := &file_protocol_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ImageGenerationResponse_ImageResponseChoice) String() string {
+func (x *ChatContentPart_Audio) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ImageGenerationResponse_ImageResponseChoice) ProtoMessage() {}
+func (*ChatContentPart_Audio) ProtoMessage() {}
 
-func (x *ImageGenerationResponse_ImageResponseChoice) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[24]
+func (x *ChatContentPart_Audio) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1804,55 +3617,55 @@ func (x *ImageGenerationResponse_ImageResponseChoice) ProtoReflect() protoreflec
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ImageGenerationResponse_ImageResponseChoice.ProtoReflect.Descriptor instead.
-func (*ImageGenerationResponse_ImageResponseChoice) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{8, 0}
+// Deprecated: Use ChatContentPart_Audio.ProtoReflect.Descriptor instead.
+func (*ChatContentPart_Audio) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{22, 2}
 }
 
-func (x *ImageGenerationResponse_ImageResponseChoice) GetUrl() string {
+func (x *ChatContentPart_Audio) GetType() string {
 	if x != nil {
-		return x.Url
+		return x.Type
 	}
 	return ""
 }
 
-func (x *ImageGenerationResponse_ImageResponseChoice) GetB64Json() string {
+func (x *ChatContentPart_Audio) GetData() string {
 	if x != nil {
-		return x.B64Json
+		return x.Data
 	}
 	return ""
 }
 
-func (x *ImageGenerationResponse_ImageResponseChoice) GetRevisedPrompt() string {
+func (x *ChatContentPart_Audio) GetFormat() string {
 	if x != nil {
-		return x.RevisedPrompt
+		return x.Format
 	}
 	return ""
 }
 
-type ChatContentPart_Text struct {
+type ChatToolCall_Function struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Text          string                 `protobuf:"bytes,2,opt,name=text,proto3" json:"text,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Arguments     string                 `protobuf:"bytes,2,opt,name=arguments,proto3" json:"arguments,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatContentPart_Text) Reset() {
-	*x = ChatContentPart_Text{}
-	mi := &file_protocol_proto_msgTypes[25]
+func (x *ChatToolCall_Function) Reset() {
+	*x = ChatToolCall_Function{}
+	mi := &file_protocol_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatContentPart_Text) String() string {
+func (x *ChatToolCall_Function) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatContentPart_Text) ProtoMessage() {}
+func (*ChatToolCall_Function) ProtoMessage() {}
 
-func (x *ChatContentPart_Text) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[25]
+func (x *ChatToolCall_Function) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1863,49 +3676,51 @@ func (x *ChatContentPart_Text) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatContentPart_Text.ProtoReflect.Descriptor instead.
-func (*ChatContentPart_Text) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{10, 0}
+// Deprecated: Use ChatToolCall_Function.ProtoReflect.Descriptor instead.
+func (*ChatToolCall_Function) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{24, 0}
 }
 
-func (x *ChatContentPart_Text) GetType() string {
+func (x *ChatToolCall_Function) GetName() string {
 	if x != nil {
-		return x.Type
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ChatContentPart_Text) GetText() string {
+func (x *ChatToolCall_Function) GetArguments() string {
 	if x != nil {
-		return x.Text
+		return x.Arguments
 	}
 	return ""
 }
 
-type ChatContentPart_Image struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
-	Detail        string                 `protobuf:"bytes,3,opt,name=detail,proto3" json:"detail,omitempty"`
+type ChatTool_Function struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Name        string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	// JSON schema of the arguments
+	Parameters    []byte `protobuf:"bytes,3,opt,name=parameters,proto3" json:"parameters,omitempty"`
+	Strict        *bool  `protobuf:"varint,4,opt,name=strict,proto3,oneof" json:"strict,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatContentPart_Image) Reset() {
-	*x = ChatContentPart_Image{}
-	mi := &file_protocol_proto_msgTypes[26]
+func (x *ChatTool_Function) Reset() {
+	*x = ChatTool_Function{}
+	mi := &file_protocol_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatContentPart_Image) String() string {
+func (x *ChatTool_Function) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatContentPart_Image) ProtoMessage() {}
+func (*ChatTool_Function) ProtoMessage() {}
 
-func (x *ChatContentPart_Image) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[26]
+func (x *ChatTool_Function) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1916,56 +3731,64 @@ func (x *ChatContentPart_Image) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatContentPart_Image.ProtoReflect.Descriptor instead.
-func (*ChatContentPart_Image) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{10, 1}
+// Deprecated: Use ChatTool_Function.ProtoReflect.Descriptor instead.
+func (*ChatTool_Function) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{26, 0}
 }
 
-func (x *ChatContentPart_Image) GetType() string {
+func (x *ChatTool_Function) GetName() string {
 	if x != nil {
-		return x.Type
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ChatContentPart_Image) GetUrl() string {
+func (x *ChatTool_Function) GetDescription() string {
 	if x != nil {
-		return x.Url
+		return x.Description
 	}
 	return ""
 }
 
-func (x *ChatContentPart_Image) GetDetail() string {
+func (x *ChatTool_Function) GetParameters() []byte {
 	if x != nil {
-		return x.Detail
+		return x.Parameters
 	}
-	return ""
+	return nil
 }
 
-type ChatContentPart_Audio struct {
+func (x *ChatTool_Function) GetStrict() bool {
+	if x != nil && x.Strict != nil {
+		return *x.Strict
+	}
+	return false
+}
+
+type ChatResponseFormat_JSONSchema struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
-	Data          string                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
-	Format        string                 `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Schema        []byte                 `protobuf:"bytes,3,opt,name=schema,proto3" json:"schema,omitempty"`
+	Strict        *bool                  `protobuf:"varint,4,opt,name=strict,proto3,oneof" json:"strict,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatContentPart_Audio) Reset() {
-	*x = ChatContentPart_Audio{}
-	mi := &file_protocol_proto_msgTypes[27]
+func (x *ChatResponseFormat_JSONSchema) Reset() {
+	*x = ChatResponseFormat_JSONSchema{}
+	mi := &file_protocol_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatContentPart_Audio) String() string {
+func (x *ChatResponseFormat_JSONSchema) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatContentPart_Audio) ProtoMessage() {}
+func (*ChatResponseFormat_JSONSchema) ProtoMessage() {}
 
-func (x *ChatContentPart_Audio) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[27]
+func (x *ChatResponseFormat_JSONSchema) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1976,44 +3799,53 @@ func (x *ChatContentPart_Audio) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatContentPart_Audio.ProtoReflect.Descriptor instead.
-func (*ChatContentPart_Audio) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{10, 2}
+// Deprecated: Use ChatResponseFormat_JSONSchema.ProtoReflect.Descriptor instead.
+func (*ChatResponseFormat_JSONSchema) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{27, 0}
 }
 
-func (x *ChatContentPart_Audio) GetType() string {
+func (x *ChatResponseFormat_JSONSchema) GetName() string {
 	if x != nil {
-		return x.Type
+		return x.Name
 	}
 	return ""
 }
 
-func (x *ChatContentPart_Audio) GetData() string {
+func (x *ChatResponseFormat_JSONSchema) GetDescription() string {
 	if x != nil {
-		return x.Data
+		return x.Description
 	}
 	return ""
 }
 
-func (x *ChatContentPart_Audio) GetFormat() string {
+func (x *ChatResponseFormat_JSONSchema) GetSchema() []byte {
 	if x != nil {
-		return x.Format
+		return x.Schema
 	}
-	return ""
+	return nil
+}
+
+func (x *ChatResponseFormat_JSONSchema) GetStrict() bool {
+	if x != nil && x.Strict != nil {
+		return *x.Strict
+	}
+	return false
 }
 
 type ChatCompletionResponse_ChatResponseChoice struct {
-	state         protoimpl.MessageState         `protogen:"open.v1"`
-	Index         int32                          `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
-	Message       *ChatCompletionResponseMessage `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
-	FinishReason  string                         `protobuf:"bytes,3,opt,name=finish_reason,json=finishReason,proto3" json:"finish_reason,omitempty"`
+	state        protoimpl.MessageState         `protogen:"open.v1"`
+	Index        int32                          `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Message      *ChatCompletionResponseMessage `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	FinishReason string                         `protobuf:"bytes,3,opt,name=finish_reason,json=finishReason,proto3" json:"finish_reason,omitempty"`
+	// JSON object
+	Logprobs      []byte `protobuf:"bytes,4,opt,name=logprobs,proto3" json:"logprobs,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ChatCompletionResponse_ChatResponseChoice) Reset() {
 	*x = ChatCompletionResponse_ChatResponseChoice{}
-	mi := &file_protocol_proto_msgTypes[28]
+	mi := &file_protocol_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2025,7 +3857,7 @@ func (x *ChatCompletionResponse_ChatResponseChoice) String() string {
 func (*ChatCompletionResponse_ChatResponseChoice) ProtoMessage() {}
 
 func (x *ChatCompletionResponse_ChatResponseChoice) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[28]
+	mi := &file_protocol_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2038,7 +3870,7 @@ func (x *ChatCompletionResponse_ChatResponseChoice) ProtoReflect() protoreflect.
 
 // Deprecated: Use ChatCompletionResponse_ChatResponseChoice.ProtoReflect.Descriptor instead.
 func (*ChatCompletionResponse_ChatResponseChoice) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{15, 0}
+	return file_protocol_proto_rawDescGZIP(), []int{30, 0}
 }
 
 func (x *ChatCompletionResponse_ChatResponseChoice) GetIndex() int32 {
@@ -2062,6 +3894,13 @@ func (x *ChatCompletionResponse_ChatResponseChoice) GetFinishReason() string {
 	return ""
 }
 
+func (x *ChatCompletionResponse_ChatResponseChoice) GetLogprobs() []byte {
+	if x != nil {
+		return x.Logprobs
+	}
+	return nil
+}
+
 type ChatCompletionResponse_ChatResponseUsage struct {
 	state            protoimpl.MessageState `protogen:"open.v1"`
 	CompletionTokens int32                  `protobuf:"varint,1,opt,name=completion_tokens,json=completionTokens,proto3" json:"completion_tokens,omitempty"`
@@ -2073,7 +3912,7 @@ type ChatCompletionResponse_ChatResponseUsage struct {
 
 func (x *ChatCompletionResponse_ChatResponseUsage) Reset() {
 	*x = ChatCompletionResponse_ChatResponseUsage{}
-	mi := &file_protocol_proto_msgTypes[29]
+	mi := &file_protocol_proto_msgTypes[53]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2085,7 +3924,7 @@ func (x *ChatCompletionResponse_ChatResponseUsage) String() string {
 func (*ChatCompletionResponse_ChatResponseUsage) ProtoMessage() {}
 
 func (x *ChatCompletionResponse_ChatResponseUsage) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[29]
+	mi := &file_protocol_proto_msgTypes[53]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2098,7 +3937,7 @@ func (x *ChatCompletionResponse_ChatResponseUsage) ProtoReflect() protoreflect.M
 
 // Deprecated: Use ChatCompletionResponse_ChatResponseUsage.ProtoReflect.Descriptor instead.
 func (*ChatCompletionResponse_ChatResponseUsage) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{15, 1}
+	return file_protocol_proto_rawDescGZIP(), []int{30, 1}
 }
 
 func (x *ChatCompletionResponse_ChatResponseUsage) GetCompletionTokens() int32 {
@@ -2136,7 +3975,7 @@ type HostInfoResponse_OSInfo struct {
 
 func (x *HostInfoResponse_OSInfo) Reset() {
 	*x = HostInfoResponse_OSInfo{}
-	mi := &file_protocol_proto_msgTypes[30]
+	mi := &file_protocol_proto_msgTypes[54]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2148,7 +3987,7 @@ func (x *HostInfoResponse_OSInfo) String() string {
 func (*HostInfoResponse_OSInfo) ProtoMessage() {}
 
 func (x *HostInfoResponse_OSInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[30]
+	mi := &file_protocol_proto_msgTypes[54]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2161,7 +4000,7 @@ func (x *HostInfoResponse_OSInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostInfoResponse_OSInfo.ProtoReflect.Descriptor instead.
 func (*HostInfoResponse_OSInfo) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{18, 0}
+	return file_protocol_proto_rawDescGZIP(), []int{33, 0}
 }
 
 func (x *HostInfoResponse_OSInfo) GetOs() string {
@@ -2217,7 +4056,7 @@ type HostInfoResponse_CpuInfo struct {
 
 func (x *HostInfoResponse_CpuInfo) Reset() {
 	*x = HostInfoResponse_CpuInfo{}
-	mi := &file_protocol_proto_msgTypes[31]
+	mi := &file_protocol_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2229,7 +4068,7 @@ func (x *HostInfoResponse_CpuInfo) String() string {
 func (*HostInfoResponse_CpuInfo) ProtoMessage() {}
 
 func (x *HostInfoResponse_CpuInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[31]
+	mi := &file_protocol_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2242,7 +4081,7 @@ func (x *HostInfoResponse_CpuInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostInfoResponse_CpuInfo.ProtoReflect.Descriptor instead.
 func (*HostInfoResponse_CpuInfo) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{18, 1}
+	return file_protocol_proto_rawDescGZIP(), []int{33, 1}
 }
 
 func (x *HostInfoResponse_CpuInfo) GetModelName() string {
@@ -2276,7 +4115,7 @@ type HostInfoResponse_MemoryInfo struct {
 
 func (x *HostInfoResponse_MemoryInfo) Reset() {
 	*x = HostInfoResponse_MemoryInfo{}
-	mi := &file_protocol_proto_msgTypes[32]
+	mi := &file_protocol_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2288,7 +4127,7 @@ func (x *HostInfoResponse_MemoryInfo) String() string {
 func (*HostInfoResponse_MemoryInfo) ProtoMessage() {}
 
 func (x *HostInfoResponse_MemoryInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[32]
+	mi := &file_protocol_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2301,7 +4140,7 @@ func (x *HostInfoResponse_MemoryInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostInfoResponse_MemoryInfo.ProtoReflect.Descriptor instead.
 func (*HostInfoResponse_MemoryInfo) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{18, 2}
+	return file_protocol_proto_rawDescGZIP(), []int{33, 2}
 }
 
 func (x *HostInfoResponse_MemoryInfo) GetTotalPhysicalBytes() int64 {
@@ -2330,7 +4169,7 @@ type HostInfoResponse_DiskInfo struct {
 
 func (x *HostInfoResponse_DiskInfo) Reset() {
 	*x = HostInfoResponse_DiskInfo{}
-	mi := &file_protocol_proto_msgTypes[33]
+	mi := &file_protocol_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2342,7 +4181,7 @@ func (x *HostInfoResponse_DiskInfo) String() string {
 func (*HostInfoResponse_DiskInfo) ProtoMessage() {}
 
 func (x *HostInfoResponse_DiskInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[33]
+	mi := &file_protocol_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2355,7 +4194,7 @@ func (x *HostInfoResponse_DiskInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostInfoResponse_DiskInfo.ProtoReflect.Descriptor instead.
 func (*HostInfoResponse_DiskInfo) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{18, 3}
+	return file_protocol_proto_rawDescGZIP(), []int{33, 3}
 }
 
 func (x *HostInfoResponse_DiskInfo) GetDriveType() string {
@@ -2396,7 +4235,7 @@ type HostInfoResponse_GpuInfo struct {
 
 func (x *HostInfoResponse_GpuInfo) Reset() {
 	*x = HostInfoResponse_GpuInfo{}
-	mi := &file_protocol_proto_msgTypes[34]
+	mi := &file_protocol_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2408,7 +4247,7 @@ func (x *HostInfoResponse_GpuInfo) String() string {
 func (*HostInfoResponse_GpuInfo) ProtoMessage() {}
 
 func (x *HostInfoResponse_GpuInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_protocol_proto_msgTypes[34]
+	mi := &file_protocol_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2421,7 +4260,7 @@ func (x *HostInfoResponse_GpuInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HostInfoResponse_GpuInfo.ProtoReflect.Descriptor instead.
 func (*HostInfoResponse_GpuInfo) Descriptor() ([]byte, []int) {
-	return file_protocol_proto_rawDescGZIP(), []int{18, 4}
+	return file_protocol_proto_rawDescGZIP(), []int{33, 4}
 }
 
 func (x *HostInfoResponse_GpuInfo) GetVendor() string {
@@ -2438,11 +4277,223 @@ func (x *HostInfoResponse_GpuInfo) GetProduct() string {
 	return ""
 }
 
+type HostMetrics_CpuMetrics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UsagePercent  float64                `protobuf:"fixed64,1,opt,name=usage_percent,json=usagePercent,proto3" json:"usage_percent,omitempty"`
+	Load1         float64                `protobuf:"fixed64,2,opt,name=load1,proto3" json:"load1,omitempty"`
+	Load5         float64                `protobuf:"fixed64,3,opt,name=load5,proto3" json:"load5,omitempty"`
+	Load15        float64                `protobuf:"fixed64,4,opt,name=load15,proto3" json:"load15,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HostMetrics_CpuMetrics) Reset() {
+	*x = HostMetrics_CpuMetrics{}
+	mi := &file_protocol_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostMetrics_CpuMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostMetrics_CpuMetrics) ProtoMessage() {}
+
+func (x *HostMetrics_CpuMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostMetrics_CpuMetrics.ProtoReflect.Descriptor instead.
+func (*HostMetrics_CpuMetrics) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{34, 0}
+}
+
+func (x *HostMetrics_CpuMetrics) GetUsagePercent() float64 {
+	if x != nil {
+		return x.UsagePercent
+	}
+	return 0
+}
+
+func (x *HostMetrics_CpuMetrics) GetLoad1() float64 {
+	if x != nil {
+		return x.Load1
+	}
+	return 0
+}
+
+func (x *HostMetrics_CpuMetrics) GetLoad5() float64 {
+	if x != nil {
+		return x.Load5
+	}
+	return 0
+}
+
+func (x *HostMetrics_CpuMetrics) GetLoad15() float64 {
+	if x != nil {
+		return x.Load15
+	}
+	return 0
+}
+
+type HostMetrics_MemMetrics struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	TotalBytes     uint64                 `protobuf:"varint,1,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"`
+	UsedBytes      uint64                 `protobuf:"varint,2,opt,name=used_bytes,json=usedBytes,proto3" json:"used_bytes,omitempty"`
+	AvailableBytes uint64                 `protobuf:"varint,3,opt,name=available_bytes,json=availableBytes,proto3" json:"available_bytes,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *HostMetrics_MemMetrics) Reset() {
+	*x = HostMetrics_MemMetrics{}
+	mi := &file_protocol_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostMetrics_MemMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostMetrics_MemMetrics) ProtoMessage() {}
+
+func (x *HostMetrics_MemMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostMetrics_MemMetrics.ProtoReflect.Descriptor instead.
+func (*HostMetrics_MemMetrics) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{34, 1}
+}
+
+func (x *HostMetrics_MemMetrics) GetTotalBytes() uint64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+func (x *HostMetrics_MemMetrics) GetUsedBytes() uint64 {
+	if x != nil {
+		return x.UsedBytes
+	}
+	return 0
+}
+
+func (x *HostMetrics_MemMetrics) GetAvailableBytes() uint64 {
+	if x != nil {
+		return x.AvailableBytes
+	}
+	return 0
+}
+
+type HostMetrics_GpuMetrics struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Index              uint32                 `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"`
+	Name               string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	UtilizationPercent float64                `protobuf:"fixed64,3,opt,name=utilization_percent,json=utilizationPercent,proto3" json:"utilization_percent,omitempty"`
+	MemoryTotalBytes   uint64                 `protobuf:"varint,4,opt,name=memory_total_bytes,json=memoryTotalBytes,proto3" json:"memory_total_bytes,omitempty"`
+	MemoryUsedBytes    uint64                 `protobuf:"varint,5,opt,name=memory_used_bytes,json=memoryUsedBytes,proto3" json:"memory_used_bytes,omitempty"`
+	TemperatureCelsius float64                `protobuf:"fixed64,6,opt,name=temperature_celsius,json=temperatureCelsius,proto3" json:"temperature_celsius,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *HostMetrics_GpuMetrics) Reset() {
+	*x = HostMetrics_GpuMetrics{}
+	mi := &file_protocol_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HostMetrics_GpuMetrics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HostMetrics_GpuMetrics) ProtoMessage() {}
+
+func (x *HostMetrics_GpuMetrics) ProtoReflect() protoreflect.Message {
+	mi := &file_protocol_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HostMetrics_GpuMetrics.ProtoReflect.Descriptor instead.
+func (*HostMetrics_GpuMetrics) Descriptor() ([]byte, []int) {
+	return file_protocol_proto_rawDescGZIP(), []int{34, 2}
+}
+
+func (x *HostMetrics_GpuMetrics) GetIndex() uint32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *HostMetrics_GpuMetrics) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *HostMetrics_GpuMetrics) GetUtilizationPercent() float64 {
+	if x != nil {
+		return x.UtilizationPercent
+	}
+	return 0
+}
+
+func (x *HostMetrics_GpuMetrics) GetMemoryTotalBytes() uint64 {
+	if x != nil {
+		return x.MemoryTotalBytes
+	}
+	return 0
+}
+
+func (x *HostMetrics_GpuMetrics) GetMemoryUsedBytes() uint64 {
+	if x != nil {
+		return x.MemoryUsedBytes
+	}
+	return 0
+}
+
+func (x *HostMetrics_GpuMetrics) GetTemperatureCelsius() float64 {
+	if x != nil {
+		return x.TemperatureCelsius
+	}
+	return 0
+}
+
 var File_protocol_proto protoreflect.FileDescriptor
 
 var file_protocol_proto_rawDesc = []byte{
 	0x0a, 0x0e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x12, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x22, 0xcf, 0x01, 0x0a, 0x0d, 0x4d,
+	0x12, 0x08, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x22, 0xef, 0x01, 0x0a, 0x0d, 0x4d,
 	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e,
 	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73,
@@ -2455,7 +4506,9 @@ var file_protocol_proto_rawDesc = []byte{
 	0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x12, 0x20, 0x0a, 0x0c, 0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x70,
 	0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x6e, 0x6f,
 	0x64, 0x65, 0x50, 0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x67, 0x6e,
-	0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x73, 0x69, 0x67, 0x6e, 0x22, 0xc1, 0x01, 0x0a,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x73, 0x69, 0x67, 0x6e, 0x12, 0x1e, 0x0a, 0x0a,
+	0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d,
+	0x52, 0x0a, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0xc1, 0x01, 0x0a,
 	0x07, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2f, 0x0a, 0x06, 0x68, 0x65, 0x61, 0x64,
 	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
 	0x63, 0x6f, 0x6c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x48, 0x65, 0x61, 0x64, 0x65,
@@ -2468,174 +4521,414 @@ var file_protocol_proto_rawDesc = []byte{
 	0x65, 0x73, 0x75, 0x6c, 0x74, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x73,
 	0x75, 0x6c, 0x74, 0x5f, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
 	0x09, 0x52, 0x0d, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x22, 0x81, 0x01, 0x0a, 0x10, 0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74,
-	0x79, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x31, 0x0a, 0x03, 0x72, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x50, 0x65,
-	0x65, 0x72, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x71, 0x12, 0x32, 0x0a, 0x03, 0x72, 0x65, 0x73, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
-	0x2e, 0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04,
-	0x64, 0x61, 0x74, 0x61, 0x22, 0x15, 0x0a, 0x13, 0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e,
-	0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xc6, 0x01, 0x0a, 0x14,
-	0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
-	0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
-	0x23, 0x0a, 0x0d, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b,
-	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63,
-	0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x5f, 0x61, 0x64,
-	0x64, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0b, 0x6c, 0x69, 0x73, 0x74, 0x65,
-	0x6e, 0x41, 0x64, 0x64, 0x72, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
-	0x6f, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x63, 0x6f, 0x6c, 0x73, 0x22, 0x5e, 0x0a, 0x12, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x56, 0x65,
-	0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x61,
-	0x6c, 0x6c, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x77, 0x61, 0x6c, 0x6c,
-	0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
-	0x68, 0x61, 0x73, 0x68, 0x22, 0x8a, 0x01, 0x0a, 0x13, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x47, 0x65,
-	0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x34, 0x0a, 0x03,
-	0x72, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x22, 0x76, 0x0a, 0x0c, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b,
+	0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04,
+	0x68, 0x61, 0x73, 0x68, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f,
+	0x74, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c,
+	0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x04,
+	0x73, 0x69, 0x7a, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x41, 0x0a, 0x0a, 0x43, 0x61, 0x6e, 0x63,
+	0x65, 0x6c, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x2b, 0x0a, 0x03, 0x72, 0x65, 0x71, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x03,
+	0x72, 0x65, 0x71, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x3a, 0x0a, 0x0d, 0x43,
+	0x61, 0x6e, 0x63, 0x65, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x29, 0x0a, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x81, 0x01, 0x0a, 0x10, 0x50, 0x65, 0x65, 0x72,
+	0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x31, 0x0a, 0x03,
+	0x72, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74,
+	0x79, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x71, 0x12,
+	0x32, 0x0a, 0x03, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e,
+	0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x03,
+	0x72, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x15, 0x0a, 0x13, 0x50,
+	0x65, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x22, 0xc6, 0x01, 0x0a, 0x14, 0x50, 0x65, 0x65, 0x72, 0x49, 0x64, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x61, 0x67, 0x65, 0x6e, 0x74, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x61,
+	0x67, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x70,
+	0x75, 0x62, 0x6c, 0x69, 0x63, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x09, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x6c, 0x69,
+	0x73, 0x74, 0x65, 0x6e, 0x5f, 0x61, 0x64, 0x64, 0x72, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x0b, 0x6c, 0x69, 0x73, 0x74, 0x65, 0x6e, 0x41, 0x64, 0x64, 0x72, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09,
+	0x52, 0x09, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x73, 0x22, 0x5e, 0x0a, 0x12, 0x57,
+	0x61, 0x6c, 0x6c, 0x65, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x16, 0x0a, 0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67,
+	0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69,
+	0x67, 0x6e, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x68, 0x61, 0x73, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x61, 0x73, 0x68, 0x22, 0x8a, 0x01, 0x0a, 0x13,
+	0x49, 0x6d, 0x61, 0x67, 0x65, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x42,
+	0x6f, 0x64, 0x79, 0x12, 0x34, 0x0a, 0x03, 0x72, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x71, 0x12, 0x35, 0x0a, 0x03, 0x72, 0x65, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x73,
+	0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xe7, 0x02, 0x0a, 0x16, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e,
+	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d,
+	0x62, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a,
+	0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68,
+	0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x10,
+	0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x69, 0x64,
+	0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x65, 0x70, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04,
+	0x73, 0x74, 0x65, 0x70, 0x12, 0x34, 0x0a, 0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e,
+	0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x65,
+	0x65, 0x64, 0x18, 0x11, 0x20, 0x01, 0x28, 0x03, 0x48, 0x00, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64,
+	0x88, 0x01, 0x01, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73, 0x65, 0x65, 0x64, 0x4a, 0x04, 0x08, 0x0b,
+	0x10, 0x10, 0x22, 0xef, 0x01, 0x0a, 0x17, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x47, 0x65, 0x6e, 0x65,
+	0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18,
+	0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x4f, 0x0a, 0x07, 0x63, 0x68, 0x6f, 0x69,
+	0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x35, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x03, 0x72,
-	0x65, 0x71, 0x12, 0x35, 0x0a, 0x03, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65,
-	0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74,
-	0x61, 0x22, 0xc5, 0x02, 0x0a, 0x16, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x47, 0x65, 0x6e, 0x65, 0x72,
-	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07,
-	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70,
-	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x16, 0x0a, 0x06,
-	0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x70, 0x72,
-	0x6f, 0x6d, 0x70, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04,
-	0x73, 0x69, 0x7a, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65,
-	0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52,
-	0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74,
-	0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x27,
-	0x0a, 0x0f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61,
-	0x74, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x09,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x74, 0x65,
-	0x70, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x74, 0x65, 0x70, 0x12, 0x34, 0x0a,
-	0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x56,
-	0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x77, 0x61, 0x6c,
-	0x6c, 0x65, 0x74, 0x4a, 0x04, 0x08, 0x0b, 0x10, 0x10, 0x22, 0xef, 0x01, 0x0a, 0x17, 0x49, 0x6d,
-	0x61, 0x67, 0x65, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12,
-	0x4f, 0x0a, 0x07, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
-	0x32, 0x35, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x49, 0x6d, 0x61, 0x67,
-	0x65, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x52, 0x07, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x73,
-	0x1a, 0x69, 0x0a, 0x13, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x36, 0x34,
-	0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x36, 0x34,
-	0x4a, 0x73, 0x6f, 0x6e, 0x12, 0x25, 0x0a, 0x0e, 0x72, 0x65, 0x76, 0x69, 0x73, 0x65, 0x64, 0x5f,
-	0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65,
-	0x76, 0x69, 0x73, 0x65, 0x64, 0x50, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x22, 0x87, 0x01, 0x0a, 0x12,
-	0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x6f,
-	0x64, 0x79, 0x12, 0x33, 0x0a, 0x03, 0x72, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32,
-	0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43,
-	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x71, 0x12, 0x34, 0x0a, 0x03, 0x72, 0x65, 0x73, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e,
-	0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x73, 0x42, 0x06, 0x0a,
-	0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xcf, 0x03, 0x0a, 0x0f, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f,
-	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x72, 0x74, 0x12, 0x32, 0x0a, 0x04, 0x74, 0x79, 0x70,
-	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
-	0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61,
-	0x72, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x32, 0x0a,
-	0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65,
-	0x6e, 0x74, 0x50, 0x61, 0x72, 0x74, 0x2e, 0x54, 0x65, 0x78, 0x74, 0x52, 0x04, 0x74, 0x65, 0x78,
-	0x74, 0x12, 0x35, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b,
-	0x32, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74,
-	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x72, 0x74, 0x2e, 0x49, 0x6d, 0x61, 0x67,
-	0x65, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x35, 0x0a, 0x05, 0x61, 0x75, 0x64, 0x69,
-	0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
-	0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61,
-	0x72, 0x74, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x52, 0x05, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x1a,
-	0x2e, 0x0a, 0x04, 0x54, 0x65, 0x78, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74,
-	0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x1a,
-	0x45, 0x0a, 0x05, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x03,
-	0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x16,
-	0x0a, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x1a, 0x47, 0x0a, 0x05, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x12,
-	0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74,
-	0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61,
-	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x22,
-	0x26, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x45, 0x58, 0x54, 0x10,
-	0x00, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05,
-	0x41, 0x55, 0x44, 0x49, 0x4f, 0x10, 0x02, 0x22, 0x43, 0x0a, 0x10, 0x43, 0x68, 0x61, 0x74, 0x43,
-	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x72, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x05, 0x70,
-	0x61, 0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e,
-	0x74, 0x50, 0x61, 0x72, 0x74, 0x52, 0x05, 0x70, 0x61, 0x72, 0x74, 0x73, 0x22, 0x45, 0x0a, 0x15,
-	0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e,
-	0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74,
-	0x65, 0x6e, 0x74, 0x22, 0xa1, 0x02, 0x0a, 0x15, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70,
-	0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a,
-	0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x3b, 0x0a,
-	0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43,
-	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
-	0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74,
-	0x72, 0x65, 0x61, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x73, 0x74, 0x72, 0x65,
-	0x61, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x03, 0x63, 0x69, 0x64, 0x12, 0x20, 0x0a, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74,
-	0x75, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x02, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65,
-	0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x12, 0x13, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x5f, 0x70, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x02, 0x52, 0x04, 0x74, 0x6f, 0x70, 0x50, 0x12, 0x34, 0x0a, 0x06, 0x77,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65,
+	0x52, 0x07, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x1a, 0x69, 0x0a, 0x13, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65,
+	0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75,
+	0x72, 0x6c, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x36, 0x34, 0x5f, 0x6a, 0x73, 0x6f, 0x6e, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x36, 0x34, 0x4a, 0x73, 0x6f, 0x6e, 0x12, 0x25, 0x0a,
+	0x0e, 0x72, 0x65, 0x76, 0x69, 0x73, 0x65, 0x64, 0x5f, 0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x65, 0x76, 0x69, 0x73, 0x65, 0x64, 0x50, 0x72,
+	0x6f, 0x6d, 0x70, 0x74, 0x22, 0x78, 0x0a, 0x0d, 0x45, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x69, 0x6e,
+	0x67, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x2e, 0x0a, 0x03, 0x72, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x45, 0x6d,
+	0x62, 0x65, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00,
+	0x52, 0x03, 0x72, 0x65, 0x71, 0x12, 0x2f, 0x0a, 0x03, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x45, 0x6d,
+	0x62, 0x65, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48,
+	0x00, 0x52, 0x03, 0x72, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xc6,
+	0x01, 0x0a, 0x10, 0x45, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x18, 0x03, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x64,
+	0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0a, 0x64, 0x69, 0x6d, 0x65, 0x6e, 0x73, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x34, 0x0a, 0x06, 0x77,
 	0x61, 0x6c, 0x6c, 0x65, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x72,
 	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x57, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x56, 0x65, 0x72,
 	0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65,
-	0x74, 0x4a, 0x04, 0x08, 0x08, 0x10, 0x10, 0x22, 0x4d, 0x0a, 0x1d, 0x43, 0x68, 0x61, 0x74, 0x43,
-	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07,
-	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63,
-	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0x93, 0x04, 0x0a, 0x16, 0x43, 0x68, 0x61, 0x74, 0x43,
-	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x4d, 0x0a, 0x07, 0x63,
-	0x68, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70,
-	0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x43,
-	0x68, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x68, 0x6f, 0x69, 0x63,
-	0x65, 0x52, 0x07, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x12, 0x48, 0x0a, 0x05, 0x75, 0x73,
-	0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x70, 0x72, 0x6f, 0x74,
-	0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x43, 0x68, 0x61, 0x74,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75,
-	0x73, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x1a, 0x92, 0x01, 0x0a,
-	0x12, 0x43, 0x68, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x68, 0x6f,
-	0x69, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x41, 0x0a, 0x07, 0x6d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d, 0x65, 0x73, 0x73,
-	0x61, 0x67, 0x65, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x23, 0x0a, 0x0d,
-	0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x0c, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x52, 0x65, 0x61, 0x73, 0x6f,
-	0x6e, 0x1a, 0x88, 0x01, 0x0a, 0x11, 0x43, 0x68, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x74, 0x4a, 0x04, 0x08, 0x06, 0x10, 0x10, 0x22, 0x87, 0x02, 0x0a, 0x11, 0x45, 0x6d, 0x62, 0x65,
+	0x64, 0x64, 0x69, 0x6e, 0x67, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07,
+	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x39, 0x0a,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x45, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x69, 0x6e, 0x67,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x45, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x69,
+	0x6e, 0x67, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x23, 0x0a, 0x0d, 0x70, 0x72, 0x6f, 0x6d,
+	0x70, 0x74, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0c, 0x70, 0x72, 0x6f, 0x6d, 0x70, 0x74, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x21, 0x0a,
+	0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73,
+	0x1a, 0x3f, 0x0a, 0x09, 0x45, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x14, 0x0a,
+	0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x1c, 0x0a, 0x09, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x69, 0x6e, 0x67,
+	0x18, 0x02, 0x20, 0x03, 0x28, 0x02, 0x52, 0x09, 0x65, 0x6d, 0x62, 0x65, 0x64, 0x64, 0x69, 0x6e,
+	0x67, 0x22, 0x93, 0x01, 0x0a, 0x16, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x37, 0x0a, 0x03,
+	0x72, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00,
+	0x52, 0x03, 0x72, 0x65, 0x71, 0x12, 0x38, 0x0a, 0x03, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x24, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x41, 0x75,
+	0x64, 0x69, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x73, 0x42,
+	0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xcd, 0x02, 0x0a, 0x19, 0x41, 0x75, 0x64, 0x69,
+	0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12,
+	0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x66, 0x69, 0x6c, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x66,
+	0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x66,
+	0x69, 0x6c, 0x65, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x47, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64,
+	0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x46, 0x69, 0x65,
+	0x6c, 0x64, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73,
+	0x12, 0x34, 0x0a, 0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x57, 0x61, 0x6c, 0x6c,
+	0x65, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
+	0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x1a, 0x39, 0x0a, 0x0b, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73,
+	0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x4a, 0x04, 0x08, 0x07, 0x10, 0x10, 0x22, 0x53, 0x0a, 0x1a, 0x41, 0x75, 0x64, 0x69, 0x6f,
+	0x54, 0x72, 0x61, 0x6e, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x7e, 0x0a, 0x0f,
+	0x41, 0x75, 0x64, 0x69, 0x6f, 0x53, 0x70, 0x65, 0x65, 0x63, 0x68, 0x42, 0x6f, 0x64, 0x79, 0x12,
+	0x30, 0x0a, 0x03, 0x72, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x53, 0x70, 0x65,
+	0x65, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65,
+	0x71, 0x12, 0x31, 0x0a, 0x03, 0x72, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x53,
+	0x70, 0x65, 0x65, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52,
+	0x03, 0x72, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xfd, 0x01, 0x0a,
+	0x12, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x53, 0x70, 0x65, 0x65, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75,
+	0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a,
+	0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f,
+	0x64, 0x65, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x76,
+	0x6f, 0x69, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x6f, 0x69, 0x63,
+	0x65, 0x12, 0x27, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x5f, 0x66, 0x6f,
+	0x72, 0x6d, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x72, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x70,
+	0x65, 0x65, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x02, 0x52, 0x05, 0x73, 0x70, 0x65, 0x65, 0x64,
+	0x12, 0x34, 0x0a, 0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x57, 0x61, 0x6c, 0x6c,
+	0x65, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x06,
+	0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x4a, 0x04, 0x08, 0x08, 0x10, 0x10, 0x22, 0x4e, 0x0a, 0x13,
+	0x41, 0x75, 0x64, 0x69, 0x6f, 0x53, 0x70, 0x65, 0x65, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0c, 0x52, 0x05, 0x61, 0x75, 0x64, 0x69, 0x6f, 0x12, 0x21, 0x0a, 0x0c, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x22, 0x87, 0x01, 0x0a,
+	0x12, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x42,
+	0x6f, 0x64, 0x79, 0x12, 0x33, 0x0a, 0x03, 0x72, 0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74,
+	0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x71, 0x12, 0x34, 0x0a, 0x03, 0x72, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x73, 0x42, 0x06,
+	0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0xcf, 0x03, 0x0a, 0x0f, 0x43, 0x68, 0x61, 0x74, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x72, 0x74, 0x12, 0x32, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50,
+	0x61, 0x72, 0x74, 0x2e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x32,
+	0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x74,
+	0x65, 0x6e, 0x74, 0x50, 0x61, 0x72, 0x74, 0x2e, 0x54, 0x65, 0x78, 0x74, 0x52, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x12, 0x35, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61,
+	0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x72, 0x74, 0x2e, 0x49, 0x6d, 0x61,
+	0x67, 0x65, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x35, 0x0a, 0x05, 0x61, 0x75, 0x64,
+	0x69, 0x6f, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50,
+	0x61, 0x72, 0x74, 0x2e, 0x41, 0x75, 0x64, 0x69, 0x6f, 0x52, 0x05, 0x61, 0x75, 0x64, 0x69, 0x6f,
+	0x1a, 0x2e, 0x0a, 0x04, 0x54, 0x65, 0x78, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74,
+	0x1a, 0x45, 0x0a, 0x05, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a,
+	0x03, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12,
+	0x16, 0x0a, 0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x64, 0x65, 0x74, 0x61, 0x69, 0x6c, 0x1a, 0x47, 0x0a, 0x05, 0x41, 0x75, 0x64, 0x69, 0x6f,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d,
+	0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x22, 0x26, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x08, 0x0a, 0x04, 0x54, 0x45, 0x58, 0x54,
+	0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x10, 0x01, 0x12, 0x09, 0x0a,
+	0x05, 0x41, 0x55, 0x44, 0x49, 0x4f, 0x10, 0x02, 0x22, 0x43, 0x0a, 0x10, 0x43, 0x68, 0x61, 0x74,
+	0x43, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x72, 0x74, 0x73, 0x12, 0x2f, 0x0a, 0x05,
+	0x70, 0x61, 0x72, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6e, 0x74, 0x65,
+	0x6e, 0x74, 0x50, 0x61, 0x72, 0x74, 0x52, 0x05, 0x70, 0x61, 0x72, 0x74, 0x73, 0x22, 0xd2, 0x01,
+	0x0a, 0x0c, 0x43, 0x68, 0x61, 0x74, 0x54, 0x6f, 0x6f, 0x6c, 0x43, 0x61, 0x6c, 0x6c, 0x12, 0x0e,
+	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12,
+	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x12, 0x3b, 0x0a, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e,
+	0x43, 0x68, 0x61, 0x74, 0x54, 0x6f, 0x6f, 0x6c, 0x43, 0x61, 0x6c, 0x6c, 0x2e, 0x46, 0x75, 0x6e,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x19, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x48, 0x00,
+	0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x88, 0x01, 0x01, 0x1a, 0x3c, 0x0a, 0x08, 0x46, 0x75,
+	0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x61, 0x72,
+	0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x61,
+	0x72, 0x67, 0x75, 0x6d, 0x65, 0x6e, 0x74, 0x73, 0x42, 0x08, 0x0a, 0x06, 0x5f, 0x69, 0x6e, 0x64,
+	0x65, 0x78, 0x22, 0xb6, 0x02, 0x0a, 0x15, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c,
+	0x65, 0x74, 0x69, 0x6f, 0x6e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04,
+	0x72, 0x6f, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0c, 0x52, 0x07, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74,
+	0x12, 0x32, 0x0a, 0x05, 0x70, 0x61, 0x72, 0x74, 0x73, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x50, 0x61, 0x72, 0x74, 0x73, 0x48, 0x00, 0x52, 0x05, 0x70,
+	0x61, 0x72, 0x74, 0x73, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0e, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x35, 0x0a, 0x0a, 0x74, 0x6f, 0x6f, 0x6c, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x73, 0x18,
+	0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c,
+	0x2e, 0x43, 0x68, 0x61, 0x74, 0x54, 0x6f, 0x6f, 0x6c, 0x43, 0x61, 0x6c, 0x6c, 0x52, 0x09, 0x74,
+	0x6f, 0x6f, 0x6c, 0x43, 0x61, 0x6c, 0x6c, 0x73, 0x12, 0x20, 0x0a, 0x0c, 0x74, 0x6f, 0x6f, 0x6c,
+	0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x74, 0x6f, 0x6f, 0x6c, 0x43, 0x61, 0x6c, 0x6c, 0x49, 0x64, 0x42, 0x0f, 0x0a, 0x0d, 0x74, 0x79,
+	0x70, 0x65, 0x64, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x22, 0xe2, 0x01, 0x0a, 0x08,
+	0x43, 0x68, 0x61, 0x74, 0x54, 0x6f, 0x6f, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x37, 0x0a, 0x08,
+	0x66, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x54, 0x6f,
+	0x6f, 0x6c, 0x2e, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x66, 0x75, 0x6e,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x88, 0x01, 0x0a, 0x08, 0x46, 0x75, 0x6e, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1e, 0x0a, 0x0a, 0x70, 0x61, 0x72, 0x61,
+	0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x70, 0x61,
+	0x72, 0x61, 0x6d, 0x65, 0x74, 0x65, 0x72, 0x73, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x69,
+	0x63, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x72, 0x69,
+	0x63, 0x74, 0x88, 0x01, 0x01, 0x42, 0x09, 0x0a, 0x07, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74,
+	0x22, 0xf7, 0x01, 0x0a, 0x12, 0x43, 0x68, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x48, 0x0a, 0x0b, 0x6a,
+	0x73, 0x6f, 0x6e, 0x5f, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x27, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74,
+	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x2e, 0x4a,
+	0x53, 0x4f, 0x4e, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x0a, 0x6a, 0x73, 0x6f, 0x6e, 0x53,
+	0x63, 0x68, 0x65, 0x6d, 0x61, 0x1a, 0x82, 0x01, 0x0a, 0x0a, 0x4a, 0x53, 0x4f, 0x4e, 0x53, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63,
+	0x68, 0x65, 0x6d, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65,
+	0x6d, 0x61, 0x12, 0x1b, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x08, 0x48, 0x00, 0x52, 0x06, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x88, 0x01, 0x01, 0x42,
+	0x09, 0x0a, 0x07, 0x5f, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x22, 0xd7, 0x08, 0x0a, 0x15, 0x43,
+	0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14,
+	0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x12, 0x3b, 0x0a, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x08, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x06, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x69, 0x64, 0x12, 0x25, 0x0a, 0x0b, 0x74,
+	0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x02,
+	0x48, 0x00, 0x52, 0x0b, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x88,
+	0x01, 0x01, 0x12, 0x18, 0x0a, 0x05, 0x74, 0x6f, 0x70, 0x5f, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28,
+	0x02, 0x48, 0x01, 0x52, 0x04, 0x74, 0x6f, 0x70, 0x50, 0x88, 0x01, 0x01, 0x12, 0x1d, 0x0a, 0x0a,
+	0x6d, 0x61, 0x78, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x09, 0x6d, 0x61, 0x78, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12, 0x32, 0x0a, 0x15, 0x6d,
+	0x61, 0x78, 0x5f, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x13, 0x6d, 0x61, 0x78, 0x43,
+	0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12,
+	0x12, 0x0a, 0x04, 0x73, 0x74, 0x6f, 0x70, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x09, 0x52, 0x04, 0x73,
+	0x74, 0x6f, 0x70, 0x12, 0x0c, 0x0a, 0x01, 0x6e, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x01,
+	0x6e, 0x12, 0x17, 0x0a, 0x04, 0x73, 0x65, 0x65, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x03, 0x48,
+	0x02, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x88, 0x01, 0x01, 0x12, 0x2b, 0x0a, 0x11, 0x66, 0x72,
+	0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79, 0x5f, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x18,
+	0x0d, 0x20, 0x01, 0x28, 0x02, 0x52, 0x10, 0x66, 0x72, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x79,
+	0x50, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x65, 0x73, 0x65,
+	0x6e, 0x63, 0x65, 0x5f, 0x70, 0x65, 0x6e, 0x61, 0x6c, 0x74, 0x79, 0x18, 0x0e, 0x20, 0x01, 0x28,
+	0x02, 0x52, 0x0f, 0x70, 0x72, 0x65, 0x73, 0x65, 0x6e, 0x63, 0x65, 0x50, 0x65, 0x6e, 0x61, 0x6c,
+	0x74, 0x79, 0x12, 0x4d, 0x0a, 0x0a, 0x6c, 0x6f, 0x67, 0x69, 0x74, 0x5f, 0x62, 0x69, 0x61, 0x73,
+	0x18, 0x0f, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f,
+	0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x2e, 0x4c, 0x6f, 0x67, 0x69, 0x74, 0x42, 0x69, 0x61,
+	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x09, 0x6c, 0x6f, 0x67, 0x69, 0x74, 0x42, 0x69, 0x61,
+	0x73, 0x12, 0x34, 0x0a, 0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x18, 0x10, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x57, 0x61, 0x6c,
+	0x6c, 0x65, 0x74, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x06, 0x77, 0x61, 0x6c, 0x6c, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x70, 0x72,
+	0x6f, 0x62, 0x73, 0x18, 0x11, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x70, 0x72,
+	0x6f, 0x62, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x74, 0x6f, 0x70, 0x5f, 0x6c, 0x6f, 0x67, 0x70, 0x72,
+	0x6f, 0x62, 0x73, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x74, 0x6f, 0x70, 0x4c, 0x6f,
+	0x67, 0x70, 0x72, 0x6f, 0x62, 0x73, 0x12, 0x45, 0x0a, 0x0f, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e,
+	0x73, 0x65, 0x5f, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x13, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x0e, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12, 0x28, 0x0a,
+	0x05, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x18, 0x14, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x54, 0x6f, 0x6f, 0x6c,
+	0x52, 0x05, 0x74, 0x6f, 0x6f, 0x6c, 0x73, 0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x6f, 0x6c, 0x5f,
+	0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x18, 0x15, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0a, 0x74, 0x6f,
+	0x6f, 0x6c, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x33, 0x0a, 0x13, 0x70, 0x61, 0x72, 0x61,
+	0x6c, 0x6c, 0x65, 0x6c, 0x5f, 0x74, 0x6f, 0x6f, 0x6c, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x73, 0x18,
+	0x16, 0x20, 0x01, 0x28, 0x08, 0x48, 0x03, 0x52, 0x11, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65,
+	0x6c, 0x54, 0x6f, 0x6f, 0x6c, 0x43, 0x61, 0x6c, 0x6c, 0x73, 0x88, 0x01, 0x01, 0x12, 0x35, 0x0a,
+	0x14, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f,
+	0x75, 0x73, 0x61, 0x67, 0x65, 0x18, 0x17, 0x20, 0x01, 0x28, 0x08, 0x48, 0x04, 0x52, 0x12, 0x73,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x49, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x55, 0x73, 0x61, 0x67,
+	0x65, 0x88, 0x01, 0x01, 0x12, 0x12, 0x0a, 0x04, 0x75, 0x73, 0x65, 0x72, 0x18, 0x18, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x75, 0x73, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x18, 0x19, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x1a, 0x3c, 0x0a, 0x0e, 0x4c, 0x6f, 0x67, 0x69, 0x74, 0x42, 0x69, 0x61, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x42, 0x0e, 0x0a, 0x0c, 0x5f, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65,
+	0x42, 0x08, 0x0a, 0x06, 0x5f, 0x74, 0x6f, 0x70, 0x5f, 0x70, 0x42, 0x07, 0x0a, 0x05, 0x5f, 0x73,
+	0x65, 0x65, 0x64, 0x42, 0x16, 0x0a, 0x14, 0x5f, 0x70, 0x61, 0x72, 0x61, 0x6c, 0x6c, 0x65, 0x6c,
+	0x5f, 0x74, 0x6f, 0x6f, 0x6c, 0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x73, 0x42, 0x17, 0x0a, 0x15, 0x5f,
+	0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x5f, 0x69, 0x6e, 0x63, 0x6c, 0x75, 0x64, 0x65, 0x5f, 0x75,
+	0x73, 0x61, 0x67, 0x65, 0x22, 0x9e, 0x01, 0x0a, 0x1d, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d,
+	0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f,
+	0x6e, 0x74, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6e,
+	0x74, 0x65, 0x6e, 0x74, 0x12, 0x35, 0x0a, 0x0a, 0x74, 0x6f, 0x6f, 0x6c, 0x5f, 0x63, 0x61, 0x6c,
+	0x6c, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x54, 0x6f, 0x6f, 0x6c, 0x43, 0x61, 0x6c, 0x6c,
+	0x52, 0x09, 0x74, 0x6f, 0x6f, 0x6c, 0x43, 0x61, 0x6c, 0x6c, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x72,
+	0x65, 0x66, 0x75, 0x73, 0x61, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65,
+	0x66, 0x75, 0x73, 0x61, 0x6c, 0x22, 0xf4, 0x04, 0x0a, 0x16, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f,
+	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x12, 0x18, 0x0a, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x07, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x12, 0x4d, 0x0a, 0x07, 0x63, 0x68,
+	0x6f, 0x69, 0x63, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x33, 0x2e, 0x70, 0x72,
+	0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c,
+	0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x43, 0x68,
+	0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65,
+	0x52, 0x07, 0x63, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x73, 0x12, 0x48, 0x0a, 0x05, 0x75, 0x73, 0x61,
+	0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x32, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x52,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x52, 0x05, 0x75, 0x73,
+	0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x02, 0x69, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x06, 0x6f, 0x62, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x12, 0x2d, 0x0a, 0x12, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x5f, 0x66, 0x69, 0x6e, 0x67,
+	0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x73,
+	0x79, 0x73, 0x74, 0x65, 0x6d, 0x46, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72, 0x69, 0x6e, 0x74,
+	0x1a, 0xae, 0x01, 0x0a, 0x12, 0x43, 0x68, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
+	0x65, 0x43, 0x68, 0x6f, 0x69, 0x63, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x41, 0x0a,
+	0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x27,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x43, 0x68, 0x61, 0x74, 0x43, 0x6f,
+	0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
+	0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x12, 0x23, 0x0a, 0x0d, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x5f, 0x72, 0x65, 0x61, 0x73, 0x6f,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x66, 0x69, 0x6e, 0x69, 0x73, 0x68, 0x52,
+	0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x6c, 0x6f, 0x67, 0x70, 0x72, 0x6f, 0x62,
+	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x08, 0x6c, 0x6f, 0x67, 0x70, 0x72, 0x6f, 0x62,
+	0x73, 0x1a, 0x88, 0x01, 0x0a, 0x11, 0x43, 0x68, 0x61, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
 	0x73, 0x65, 0x55, 0x73, 0x61, 0x67, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x63, 0x6f, 0x6d, 0x70, 0x6c,
 	0x65, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x01,
 	0x28, 0x05, 0x52, 0x10, 0x63, 0x6f, 0x6d, 0x70, 0x6c, 0x65, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x6f,
@@ -2652,7 +4945,7 @@ var file_protocol_proto_rawDesc = []byte{
 	0x63, 0x6f, 0x6c, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70,
 	0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64,
 	0x61, 0x74, 0x61, 0x22, 0x11, 0x0a, 0x0f, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x9d, 0x07, 0x0a, 0x10, 0x48, 0x6f, 0x73, 0x74, 0x49,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0xce, 0x07, 0x0a, 0x10, 0x48, 0x6f, 0x73, 0x74, 0x49,
 	0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x31, 0x0a, 0x02, 0x6f,
 	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
 	0x6f, 0x6c, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73, 0x70, 0x6f,
@@ -2671,83 +4964,161 @@ var file_protocol_proto_rawDesc = []byte{
 	0x67, 0x70, 0x75, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x22, 0x2e, 0x70, 0x72, 0x6f, 0x74,
 	0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x65, 0x73,
 	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x2e, 0x47, 0x70, 0x75, 0x49, 0x6e, 0x66, 0x6f, 0x52, 0x03, 0x67,
-	0x70, 0x75, 0x1a, 0xd0, 0x01, 0x0a, 0x06, 0x4f, 0x53, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e, 0x0a,
-	0x02, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f, 0x73, 0x12, 0x1a, 0x0a,
-	0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x6c, 0x61,
-	0x74, 0x66, 0x6f, 0x72, 0x6d, 0x5f, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x03, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x0e, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x46, 0x61, 0x6d, 0x69,
-	0x6c, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x5f, 0x76,
-	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x6c,
-	0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25, 0x0a,
-	0x0e, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18,
-	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x56, 0x65, 0x72,
-	0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x5f, 0x61,
-	0x72, 0x63, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6b, 0x65, 0x72, 0x6e, 0x65,
-	0x6c, 0x41, 0x72, 0x63, 0x68, 0x1a, 0x6e, 0x0a, 0x07, 0x43, 0x70, 0x75, 0x49, 0x6e, 0x66, 0x6f,
-	0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65, 0x12,
-	0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x72, 0x65, 0x73,
-	0x12, 0x23, 0x0a, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x61, 0x64,
-	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x54, 0x68,
-	0x72, 0x65, 0x61, 0x64, 0x73, 0x1a, 0x6c, 0x0a, 0x0a, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x49,
-	0x6e, 0x66, 0x6f, 0x12, 0x30, 0x0a, 0x14, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x70, 0x68, 0x79,
-	0x73, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x12, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x50, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61, 0x6c,
-	0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x75,
-	0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x10, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x55, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x42, 0x79,
-	0x74, 0x65, 0x73, 0x1a, 0x83, 0x01, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x6b, 0x49, 0x6e, 0x66, 0x6f,
-	0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x72, 0x69, 0x76, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x72, 0x69, 0x76, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12,
-	0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x14,
-	0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d,
-	0x6f, 0x64, 0x65, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x5f, 0x6e,
-	0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65, 0x72,
-	0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x1a, 0x3b, 0x0a, 0x07, 0x47, 0x70, 0x75,
-	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12, 0x18, 0x0a, 0x07,
-	0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70,
-	0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x22, 0x78, 0x0a, 0x0d, 0x41, 0x49, 0x50, 0x72, 0x6f, 0x6a,
-	0x65, 0x63, 0x74, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x2e, 0x0a, 0x03, 0x72, 0x65, 0x71, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e,
-	0x41, 0x49, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x71, 0x12, 0x2f, 0x0a, 0x03, 0x72, 0x65, 0x73, 0x18, 0x02,
-	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e,
-	0x41, 0x49, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61,
-	0x22, 0x74, 0x0a, 0x10, 0x41, 0x49, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x4f, 0x66, 0x50, 0x72, 0x6f,
-	0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x10, 0x0a, 0x03, 0x61, 0x70,
-	0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61, 0x70, 0x69, 0x12, 0x12, 0x0a, 0x04,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65,
-	0x12, 0x12, 0x0a, 0x04, 0x69, 0x64, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04,
-	0x69, 0x64, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x03, 0x63, 0x69, 0x64, 0x22, 0x5f, 0x0a, 0x0f, 0x41, 0x49, 0x50, 0x72, 0x6f, 0x6a,
-	0x65, 0x63, 0x74, 0x4f, 0x66, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x72, 0x6f,
-	0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x72, 0x6f, 0x6a,
-	0x65, 0x63, 0x74, 0x12, 0x32, 0x0a, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20,
-	0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x41,
-	0x49, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x4f, 0x66, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52,
-	0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x41, 0x49, 0x50, 0x72, 0x6f,
-	0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x67, 0x0a, 0x11, 0x41,
-	0x49, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x35, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x18, 0x01, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x41, 0x49,
-	0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4f, 0x66, 0x4e, 0x6f, 0x64, 0x65, 0x52, 0x08, 0x70,
-	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6e, 0x6f, 0x64, 0x65, 0x5f,
-	0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65,
-	0x54, 0x79, 0x70, 0x65, 0x2a, 0x70, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54,
+	0x70, 0x75, 0x12, 0x2f, 0x0a, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x48,
+	0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x72,
+	0x69, 0x63, 0x73, 0x1a, 0xd0, 0x01, 0x0a, 0x06, 0x4f, 0x53, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x0e,
+	0x0a, 0x02, 0x6f, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x6f, 0x73, 0x12, 0x1a,
+	0x0a, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x6c,
+	0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x5f, 0x66, 0x61, 0x6d, 0x69, 0x6c, 0x79, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x46, 0x61, 0x6d,
+	0x69, 0x6c, 0x79, 0x12, 0x29, 0x0a, 0x10, 0x70, 0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70,
+	0x6c, 0x61, 0x74, 0x66, 0x6f, 0x72, 0x6d, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x25,
+	0x0a, 0x0e, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x56, 0x65,
+	0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x6b, 0x65, 0x72, 0x6e, 0x65, 0x6c, 0x5f,
+	0x61, 0x72, 0x63, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6b, 0x65, 0x72, 0x6e,
+	0x65, 0x6c, 0x41, 0x72, 0x63, 0x68, 0x1a, 0x6e, 0x0a, 0x07, 0x43, 0x70, 0x75, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x4e, 0x61, 0x6d, 0x65,
+	0x12, 0x1f, 0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x6f, 0x72, 0x65,
+	0x73, 0x12, 0x23, 0x0a, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x61,
+	0x64, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0c, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x54,
+	0x68, 0x72, 0x65, 0x61, 0x64, 0x73, 0x1a, 0x6c, 0x0a, 0x0a, 0x4d, 0x65, 0x6d, 0x6f, 0x72, 0x79,
+	0x49, 0x6e, 0x66, 0x6f, 0x12, 0x30, 0x0a, 0x14, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x70, 0x68,
+	0x79, 0x73, 0x69, 0x63, 0x61, 0x6c, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x12, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x50, 0x68, 0x79, 0x73, 0x69, 0x63, 0x61,
+	0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2c, 0x0a, 0x12, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x75, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x10, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x55, 0x73, 0x61, 0x62, 0x6c, 0x65, 0x42,
+	0x79, 0x74, 0x65, 0x73, 0x1a, 0x83, 0x01, 0x0a, 0x08, 0x44, 0x69, 0x73, 0x6b, 0x49, 0x6e, 0x66,
+	0x6f, 0x12, 0x1d, 0x0a, 0x0a, 0x64, 0x72, 0x69, 0x76, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64, 0x72, 0x69, 0x76, 0x65, 0x54, 0x79, 0x70, 0x65,
+	0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x69, 0x7a, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x04, 0x52, 0x09, 0x73, 0x69, 0x7a, 0x65, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12,
+	0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x73, 0x65, 0x72, 0x69, 0x61, 0x6c, 0x5f,
+	0x6e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x73, 0x65,
+	0x72, 0x69, 0x61, 0x6c, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x1a, 0x3b, 0x0a, 0x07, 0x47, 0x70,
+	0x75, 0x49, 0x6e, 0x66, 0x6f, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x76, 0x65, 0x6e, 0x64, 0x6f, 0x72, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x70, 0x72, 0x6f, 0x64, 0x75, 0x63, 0x74, 0x22, 0xb0, 0x05, 0x0a, 0x0b, 0x48, 0x6f, 0x73, 0x74,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x32, 0x0a, 0x03, 0x63, 0x70, 0x75, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x48, 0x6f,
+	0x73, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x43, 0x70, 0x75, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x52, 0x03, 0x63, 0x70, 0x75, 0x12, 0x38, 0x0a, 0x06, 0x6d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73,
+	0x2e, 0x4d, 0x65, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x52, 0x06, 0x6d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x12, 0x32, 0x0a, 0x03, 0x67, 0x70, 0x75, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x20, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x48, 0x6f, 0x73, 0x74,
+	0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x2e, 0x47, 0x70, 0x75, 0x4d, 0x65, 0x74, 0x72, 0x69,
+	0x63, 0x73, 0x52, 0x03, 0x67, 0x70, 0x75, 0x1a, 0x75, 0x0a, 0x0a, 0x43, 0x70, 0x75, 0x4d, 0x65,
+	0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x23, 0x0a, 0x0d, 0x75, 0x73, 0x61, 0x67, 0x65, 0x5f, 0x70,
+	0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x75, 0x73,
+	0x61, 0x67, 0x65, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f,
+	0x61, 0x64, 0x31, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x6c, 0x6f, 0x61, 0x64, 0x31,
+	0x12, 0x14, 0x0a, 0x05, 0x6c, 0x6f, 0x61, 0x64, 0x35, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52,
+	0x05, 0x6c, 0x6f, 0x61, 0x64, 0x35, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x6f, 0x61, 0x64, 0x31, 0x35,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x06, 0x6c, 0x6f, 0x61, 0x64, 0x31, 0x35, 0x1a, 0x75,
+	0x0a, 0x0a, 0x4d, 0x65, 0x6d, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x1f, 0x0a, 0x0b,
+	0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x0a, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x1d, 0x0a,
+	0x0a, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x04, 0x52, 0x09, 0x75, 0x73, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x27, 0x0a, 0x0f,
+	0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65, 0x5f, 0x62, 0x79, 0x74, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x61, 0x76, 0x61, 0x69, 0x6c, 0x61, 0x62, 0x6c, 0x65,
+	0x42, 0x79, 0x74, 0x65, 0x73, 0x1a, 0xf2, 0x01, 0x0a, 0x0a, 0x47, 0x70, 0x75, 0x4d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0d, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2f,
+	0x0a, 0x13, 0x75, 0x74, 0x69, 0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x70, 0x65,
+	0x72, 0x63, 0x65, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x75, 0x74, 0x69,
+	0x6c, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x50, 0x65, 0x72, 0x63, 0x65, 0x6e, 0x74, 0x12,
+	0x2c, 0x0a, 0x12, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x5f,
+	0x62, 0x79, 0x74, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x04, 0x52, 0x10, 0x6d, 0x65, 0x6d,
+	0x6f, 0x72, 0x79, 0x54, 0x6f, 0x74, 0x61, 0x6c, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2a, 0x0a,
+	0x11, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79, 0x5f, 0x75, 0x73, 0x65, 0x64, 0x5f, 0x62, 0x79, 0x74,
+	0x65, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0f, 0x6d, 0x65, 0x6d, 0x6f, 0x72, 0x79,
+	0x55, 0x73, 0x65, 0x64, 0x42, 0x79, 0x74, 0x65, 0x73, 0x12, 0x2f, 0x0a, 0x13, 0x74, 0x65, 0x6d,
+	0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65, 0x5f, 0x63, 0x65, 0x6c, 0x73, 0x69, 0x75, 0x73,
+	0x18, 0x06, 0x20, 0x01, 0x28, 0x01, 0x52, 0x12, 0x74, 0x65, 0x6d, 0x70, 0x65, 0x72, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x43, 0x65, 0x6c, 0x73, 0x69, 0x75, 0x73, 0x22, 0x78, 0x0a, 0x0d, 0x41, 0x49,
+	0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x42, 0x6f, 0x64, 0x79, 0x12, 0x2e, 0x0a, 0x03, 0x72,
+	0x65, 0x71, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x2e, 0x41, 0x49, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x71, 0x12, 0x2f, 0x0a, 0x03, 0x72,
+	0x65, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x2e, 0x41, 0x49, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x48, 0x00, 0x52, 0x03, 0x72, 0x65, 0x73, 0x42, 0x06, 0x0a, 0x04,
+	0x64, 0x61, 0x74, 0x61, 0x22, 0x74, 0x0a, 0x10, 0x41, 0x49, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x4f,
+	0x66, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x12, 0x10,
+	0x0a, 0x03, 0x61, 0x70, 0x69, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x61, 0x70, 0x69,
+	0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x64, 0x6c, 0x65, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x04, 0x69, 0x64, 0x6c, 0x65, 0x12, 0x10, 0x0a, 0x03, 0x63, 0x69, 0x64, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x63, 0x69, 0x64, 0x22, 0x5f, 0x0a, 0x0f, 0x41, 0x49,
+	0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4f, 0x66, 0x4e, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x32, 0x0a, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x2e, 0x41, 0x49, 0x4d, 0x6f, 0x64, 0x65, 0x6c, 0x4f, 0x66, 0x50, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x52, 0x06, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x73, 0x22, 0x12, 0x0a, 0x10, 0x41,
+	0x49, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x22,
+	0x87, 0x02, 0x0a, 0x11, 0x41, 0x49, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63,
+	0x6f, 0x6c, 0x2e, 0x41, 0x49, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x4f, 0x66, 0x4e, 0x6f,
+	0x64, 0x65, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x73, 0x12, 0x1b, 0x0a, 0x09,
+	0x6e, 0x6f, 0x64, 0x65, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x08, 0x6e, 0x6f, 0x64, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x2f, 0x0a, 0x07, 0x6d, 0x65, 0x74,
+	0x72, 0x69, 0x63, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x48, 0x6f, 0x73, 0x74, 0x4d, 0x65, 0x74, 0x72, 0x69, 0x63,
+	0x73, 0x52, 0x07, 0x6d, 0x65, 0x74, 0x72, 0x69, 0x63, 0x73, 0x12, 0x3a, 0x0a, 0x0b, 0x63, 0x6f,
+	0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x18, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x2e, 0x50, 0x65, 0x65, 0x72, 0x43,
+	0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x6e, 0x65,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x31, 0x0a, 0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x63, 0x6f, 0x6c, 0x2e, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52,
+	0x08, 0x72, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x77, 0x0a, 0x0e, 0x50, 0x65, 0x65,
+	0x72, 0x43, 0x6f, 0x6e, 0x6e, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x17, 0x0a, 0x07, 0x6e,
+	0x6f, 0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6e, 0x6f,
+	0x64, 0x65, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x05, 0x72, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x64, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x6c, 0x61, 0x74, 0x65,
+	0x6e, 0x63, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x07, 0x6c, 0x61, 0x74, 0x65, 0x6e,
+	0x63, 0x79, 0x22, 0x7f, 0x0a, 0x0b, 0x4b, 0x65, 0x79, 0x52, 0x6f, 0x74, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x28, 0x0a, 0x10, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x6e, 0x6f,
+	0x64, 0x65, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x70, 0x72, 0x65,
+	0x76, 0x69, 0x6f, 0x75, 0x73, 0x4e, 0x6f, 0x64, 0x65, 0x49, 0x64, 0x12, 0x28, 0x0a, 0x10, 0x70,
+	0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x5f, 0x70, 0x75, 0x62, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x0e, 0x70, 0x72, 0x65, 0x76, 0x69, 0x6f, 0x75, 0x73, 0x50,
+	0x75, 0x62, 0x4b, 0x65, 0x79, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74, 0x75,
+	0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61, 0x74,
+	0x75, 0x72, 0x65, 0x2a, 0xc9, 0x01, 0x0a, 0x0b, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x54,
 	0x79, 0x70, 0x65, 0x12, 0x11, 0x0a, 0x0d, 0x50, 0x45, 0x45, 0x52, 0x5f, 0x49, 0x44, 0x45, 0x4e,
 	0x54, 0x49, 0x54, 0x59, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x48, 0x4f, 0x53, 0x54, 0x5f, 0x49,
 	0x4e, 0x46, 0x4f, 0x10, 0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x41, 0x49, 0x5f, 0x50, 0x52, 0x4f, 0x4a,
 	0x45, 0x43, 0x54, 0x10, 0x02, 0x12, 0x13, 0x0a, 0x0f, 0x43, 0x48, 0x41, 0x54, 0x5f, 0x43, 0x4f,
 	0x4d, 0x50, 0x4c, 0x45, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x10, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4d,
 	0x41, 0x47, 0x45, 0x5f, 0x47, 0x45, 0x4e, 0x45, 0x52, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x11,
-	0x22, 0x04, 0x08, 0x03, 0x10, 0x0f, 0x42, 0x0d, 0x5a, 0x0b, 0x2e, 0x2e, 0x2f, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x12, 0x11, 0x0a, 0x0d, 0x4d, 0x45, 0x53, 0x53, 0x41, 0x47, 0x45, 0x5f, 0x43, 0x48, 0x55, 0x4e,
+	0x4b, 0x10, 0x12, 0x12, 0x0d, 0x0a, 0x09, 0x45, 0x4d, 0x42, 0x45, 0x44, 0x44, 0x49, 0x4e, 0x47,
+	0x10, 0x13, 0x12, 0x17, 0x0a, 0x13, 0x41, 0x55, 0x44, 0x49, 0x4f, 0x5f, 0x54, 0x52, 0x41, 0x4e,
+	0x53, 0x43, 0x52, 0x49, 0x50, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x14, 0x12, 0x10, 0x0a, 0x0c, 0x41,
+	0x55, 0x44, 0x49, 0x4f, 0x5f, 0x53, 0x50, 0x45, 0x45, 0x43, 0x48, 0x10, 0x15, 0x12, 0x0a, 0x0a,
+	0x06, 0x43, 0x41, 0x4e, 0x43, 0x45, 0x4c, 0x10, 0x16, 0x22, 0x04, 0x08, 0x03, 0x10, 0x0f, 0x42,
+	0x0d, 0x5a, 0x0b, 0x2e, 0x2e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x62, 0x06,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -2763,83 +5134,139 @@ func file_protocol_proto_rawDescGZIP() []byte {
 }
 
 var file_protocol_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
-var file_protocol_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
+var file_protocol_proto_msgTypes = make([]protoimpl.MessageInfo, 62)
 var file_protocol_proto_goTypes = []any{
 	(MessageType)(0),                                    // 0: protocol.MessageType
 	(ChatContentPart_Type)(0),                           // 1: protocol.ChatContentPart.Type
 	(*MessageHeader)(nil),                               // 2: protocol.MessageHeader
 	(*Message)(nil),                                     // 3: protocol.Message
-	(*PeerIdentityBody)(nil),                            // 4: protocol.PeerIdentityBody
-	(*PeerIdentityRequest)(nil),                         // 5: protocol.PeerIdentityRequest
-	(*PeerIdentityResponse)(nil),                        // 6: protocol.PeerIdentityResponse
-	(*WalletVerification)(nil),                          // 7: protocol.WalletVerification
-	(*ImageGenerationBody)(nil),                         // 8: protocol.ImageGenerationBody
-	(*ImageGenerationRequest)(nil),                      // 9: protocol.ImageGenerationRequest
-	(*ImageGenerationResponse)(nil),                     // 10: protocol.ImageGenerationResponse
-	(*ChatCompletionBody)(nil),                          // 11: protocol.ChatCompletionBody
-	(*ChatContentPart)(nil),                             // 12: protocol.ChatContentPart
-	(*ChatContentParts)(nil),                            // 13: protocol.ChatContentParts
-	(*ChatCompletionMessage)(nil),                       // 14: protocol.ChatCompletionMessage
-	(*ChatCompletionRequest)(nil),                       // 15: protocol.ChatCompletionRequest
-	(*ChatCompletionResponseMessage)(nil),               // 16: protocol.ChatCompletionResponseMessage
-	(*ChatCompletionResponse)(nil),                      // 17: protocol.ChatCompletionResponse
-	(*HostInfoBody)(nil),                                // 18: protocol.HostInfoBody
-	(*HostInfoRequest)(nil),                             // 19: protocol.HostInfoRequest
-	(*HostInfoResponse)(nil),                            // 20: protocol.HostInfoResponse
-	(*AIProjectBody)(nil),                               // 21: protocol.AIProjectBody
-	(*AIModelOfProject)(nil),                            // 22: protocol.AIModelOfProject
-	(*AIProjectOfNode)(nil),                             // 23: protocol.AIProjectOfNode
-	(*AIProjectRequest)(nil),                            // 24: protocol.AIProjectRequest
-	(*AIProjectResponse)(nil),                           // 25: protocol.AIProjectResponse
-	(*ImageGenerationResponse_ImageResponseChoice)(nil), // 26: protocol.ImageGenerationResponse.ImageResponseChoice
-	(*ChatContentPart_Text)(nil),                        // 27: protocol.ChatContentPart.Text
-	(*ChatContentPart_Image)(nil),                       // 28: protocol.ChatContentPart.Image
-	(*ChatContentPart_Audio)(nil),                       // 29: protocol.ChatContentPart.Audio
-	(*ChatCompletionResponse_ChatResponseChoice)(nil),   // 30: protocol.ChatCompletionResponse.ChatResponseChoice
-	(*ChatCompletionResponse_ChatResponseUsage)(nil),    // 31: protocol.ChatCompletionResponse.ChatResponseUsage
-	(*HostInfoResponse_OSInfo)(nil),                     // 32: protocol.HostInfoResponse.OSInfo
-	(*HostInfoResponse_CpuInfo)(nil),                    // 33: protocol.HostInfoResponse.CpuInfo
-	(*HostInfoResponse_MemoryInfo)(nil),                 // 34: protocol.HostInfoResponse.MemoryInfo
-	(*HostInfoResponse_DiskInfo)(nil),                   // 35: protocol.HostInfoResponse.DiskInfo
-	(*HostInfoResponse_GpuInfo)(nil),                    // 36: protocol.HostInfoResponse.GpuInfo
+	(*MessageChunk)(nil),                                // 4: protocol.MessageChunk
+	(*CancelBody)(nil),                                  // 5: protocol.CancelBody
+	(*CancelRequest)(nil),                               // 6: protocol.CancelRequest
+	(*PeerIdentityBody)(nil),                            // 7: protocol.PeerIdentityBody
+	(*PeerIdentityRequest)(nil),                         // 8: protocol.PeerIdentityRequest
+	(*PeerIdentityResponse)(nil),                        // 9: protocol.PeerIdentityResponse
+	(*WalletVerification)(nil),                          // 10: protocol.WalletVerification
+	(*ImageGenerationBody)(nil),                         // 11: protocol.ImageGenerationBody
+	(*ImageGenerationRequest)(nil),                      // 12: protocol.ImageGenerationRequest
+	(*ImageGenerationResponse)(nil),                     // 13: protocol.ImageGenerationResponse
+	(*EmbeddingBody)(nil),                               // 14: protocol.EmbeddingBody
+	(*EmbeddingRequest)(nil),                            // 15: protocol.EmbeddingRequest
+	(*EmbeddingResponse)(nil),                           // 16: protocol.EmbeddingResponse
+	(*AudioTranscriptionBody)(nil),                      // 17: protocol.AudioTranscriptionBody
+	(*AudioTranscriptionRequest)(nil),                   // 18: protocol.AudioTranscriptionRequest
+	(*AudioTranscriptionResponse)(nil),                  // 19: protocol.AudioTranscriptionResponse
+	(*AudioSpeechBody)(nil),                             // 20: protocol.AudioSpeechBody
+	(*AudioSpeechRequest)(nil),                          // 21: protocol.AudioSpeechRequest
+	(*AudioSpeechResponse)(nil),                         // 22: protocol.AudioSpeechResponse
+	(*ChatCompletionBody)(nil),                          // 23: protocol.ChatCompletionBody
+	(*ChatContentPart)(nil),                             // 24: protocol.ChatContentPart
+	(*ChatContentParts)(nil),                            // 25: protocol.ChatContentParts
+	(*ChatToolCall)(nil),                                // 26: protocol.ChatToolCall
+	(*ChatCompletionMessage)(nil),                       // 27: protocol.ChatCompletionMessage
+	(*ChatTool)(nil),                                    // 28: protocol.ChatTool
+	(*ChatResponseFormat)(nil),                          // 29: protocol.ChatResponseFormat
+	(*ChatCompletionRequest)(nil),                       // 30: protocol.ChatCompletionRequest
+	(*ChatCompletionResponseMessage)(nil),               // 31: protocol.ChatCompletionResponseMessage
+	(*ChatCompletionResponse)(nil),                      // 32: protocol.ChatCompletionResponse
+	(*HostInfoBody)(nil),                                // 33: protocol.HostInfoBody
+	(*HostInfoRequest)(nil),                             // 34: protocol.HostInfoRequest
+	(*HostInfoResponse)(nil),                            // 35: protocol.HostInfoResponse
+	(*HostMetrics)(nil),                                 // 36: protocol.HostMetrics
+	(*AIProjectBody)(nil),                               // 37: protocol.AIProjectBody
+	(*AIModelOfProject)(nil),                            // 38: protocol.AIModelOfProject
+	(*AIProjectOfNode)(nil),                             // 39: protocol.AIProjectOfNode
+	(*AIProjectRequest)(nil),                            // 40: protocol.AIProjectRequest
+	(*AIProjectResponse)(nil),                           // 41: protocol.AIProjectResponse
+	(*PeerConnection)(nil),                              // 42: protocol.PeerConnection
+	(*KeyRotation)(nil),                                 // 43: protocol.KeyRotation
+	(*ImageGenerationResponse_ImageResponseChoice)(nil), // 44: protocol.ImageGenerationResponse.ImageResponseChoice
+	(*EmbeddingResponse_Embedding)(nil),                 // 45: protocol.EmbeddingResponse.Embedding
+	nil,                                                 // 46: protocol.AudioTranscriptionRequest.FieldsEntry
+	(*ChatContentPart_Text)(nil),                        // 47: protocol.ChatContentPart.Text
+	(*ChatContentPart_Image)(nil),                       // 48: protocol.ChatContentPart.Image
+	(*ChatContentPart_Audio)(nil),                       // 49: protocol.ChatContentPart.Audio
+	(*ChatToolCall_Function)(nil),                       // 50: protocol.ChatToolCall.Function
+	(*ChatTool_Function)(nil),                           // 51: protocol.ChatTool.Function
+	(*ChatResponseFormat_JSONSchema)(nil),               // 52: protocol.ChatResponseFormat.JSONSchema
+	nil,                                                 // 53: protocol.ChatCompletionRequest.LogitBiasEntry
+	(*ChatCompletionResponse_ChatResponseChoice)(nil),   // 54: protocol.ChatCompletionResponse.ChatResponseChoice
+	(*ChatCompletionResponse_ChatResponseUsage)(nil),    // 55: protocol.ChatCompletionResponse.ChatResponseUsage
+	(*HostInfoResponse_OSInfo)(nil),                     // 56: protocol.HostInfoResponse.OSInfo
+	(*HostInfoResponse_CpuInfo)(nil),                    // 57: protocol.HostInfoResponse.CpuInfo
+	(*HostInfoResponse_MemoryInfo)(nil),                 // 58: protocol.HostInfoResponse.MemoryInfo
+	(*HostInfoResponse_DiskInfo)(nil),                   // 59: protocol.HostInfoResponse.DiskInfo
+	(*HostInfoResponse_GpuInfo)(nil),                    // 60: protocol.HostInfoResponse.GpuInfo
+	(*HostMetrics_CpuMetrics)(nil),                      // 61: protocol.HostMetrics.CpuMetrics
+	(*HostMetrics_MemMetrics)(nil),                      // 62: protocol.HostMetrics.MemMetrics
+	(*HostMetrics_GpuMetrics)(nil),                      // 63: protocol.HostMetrics.GpuMetrics
 }
 var file_protocol_proto_depIdxs = []int32{
 	2,  // 0: protocol.Message.header:type_name -> protocol.MessageHeader
 	0,  // 1: protocol.Message.type:type_name -> protocol.MessageType
-	5,  // 2: protocol.PeerIdentityBody.req:type_name -> protocol.PeerIdentityRequest
-	6,  // 3: protocol.PeerIdentityBody.res:type_name -> protocol.PeerIdentityResponse
-	9,  // 4: protocol.ImageGenerationBody.req:type_name -> protocol.ImageGenerationRequest
-	10, // 5: protocol.ImageGenerationBody.res:type_name -> protocol.ImageGenerationResponse
-	7,  // 6: protocol.ImageGenerationRequest.wallet:type_name -> protocol.WalletVerification
-	26, // 7: protocol.ImageGenerationResponse.choices:type_name -> protocol.ImageGenerationResponse.ImageResponseChoice
-	15, // 8: protocol.ChatCompletionBody.req:type_name -> protocol.ChatCompletionRequest
-	17, // 9: protocol.ChatCompletionBody.res:type_name -> protocol.ChatCompletionResponse
-	1,  // 10: protocol.ChatContentPart.type:type_name -> protocol.ChatContentPart.Type
-	27, // 11: protocol.ChatContentPart.text:type_name -> protocol.ChatContentPart.Text
-	28, // 12: protocol.ChatContentPart.image:type_name -> protocol.ChatContentPart.Image
-	29, // 13: protocol.ChatContentPart.audio:type_name -> protocol.ChatContentPart.Audio
-	12, // 14: protocol.ChatContentParts.parts:type_name -> protocol.ChatContentPart
-	14, // 15: protocol.ChatCompletionRequest.messages:type_name -> protocol.ChatCompletionMessage
-	7,  // 16: protocol.ChatCompletionRequest.wallet:type_name -> protocol.WalletVerification
-	30, // 17: protocol.ChatCompletionResponse.choices:type_name -> protocol.ChatCompletionResponse.ChatResponseChoice
-	31, // 18: protocol.ChatCompletionResponse.usage:type_name -> protocol.ChatCompletionResponse.ChatResponseUsage
-	19, // 19: protocol.HostInfoBody.req:type_name -> protocol.HostInfoRequest
-	20, // 20: protocol.HostInfoBody.res:type_name -> protocol.HostInfoResponse
-	32, // 21: protocol.HostInfoResponse.os:type_name -> protocol.HostInfoResponse.OSInfo
-	33, // 22: protocol.HostInfoResponse.cpu:type_name -> protocol.HostInfoResponse.CpuInfo
-	34, // 23: protocol.HostInfoResponse.memory:type_name -> protocol.HostInfoResponse.MemoryInfo
-	35, // 24: protocol.HostInfoResponse.disk:type_name -> protocol.HostInfoResponse.DiskInfo
-	36, // 25: protocol.HostInfoResponse.gpu:type_name -> protocol.HostInfoResponse.GpuInfo
-	24, // 26: protocol.AIProjectBody.req:type_name -> protocol.AIProjectRequest
-	25, // 27: protocol.AIProjectBody.res:type_name -> protocol.AIProjectResponse
-	22, // 28: protocol.AIProjectOfNode.models:type_name -> protocol.AIModelOfProject
-	23, // 29: protocol.AIProjectResponse.projects:type_name -> protocol.AIProjectOfNode
-	16, // 30: protocol.ChatCompletionResponse.ChatResponseChoice.message:type_name -> protocol.ChatCompletionResponseMessage
-	31, // [31:31] is the sub-list for method output_type
-	31, // [31:31] is the sub-list for method input_type
-	31, // [31:31] is the sub-list for extension type_name
-	31, // [31:31] is the sub-list for extension extendee
-	0,  // [0:31] is the sub-list for field type_name
+	6,  // 2: protocol.CancelBody.req:type_name -> protocol.CancelRequest
+	0,  // 3: protocol.CancelRequest.type:type_name -> protocol.MessageType
+	8,  // 4: protocol.PeerIdentityBody.req:type_name -> protocol.PeerIdentityRequest
+	9,  // 5: protocol.PeerIdentityBody.res:type_name -> protocol.PeerIdentityResponse
+	12, // 6: protocol.ImageGenerationBody.req:type_name -> protocol.ImageGenerationRequest
+	13, // 7: protocol.ImageGenerationBody.res:type_name -> protocol.ImageGenerationResponse
+	10, // 8: protocol.ImageGenerationRequest.wallet:type_name -> protocol.WalletVerification
+	44, // 9: protocol.ImageGenerationResponse.choices:type_name -> protocol.ImageGenerationResponse.ImageResponseChoice
+	15, // 10: protocol.EmbeddingBody.req:type_name -> protocol.EmbeddingRequest
+	16, // 11: protocol.EmbeddingBody.res:type_name -> protocol.EmbeddingResponse
+	10, // 12: protocol.EmbeddingRequest.wallet:type_name -> protocol.WalletVerification
+	45, // 13: protocol.EmbeddingResponse.data:type_name -> protocol.EmbeddingResponse.Embedding
+	18, // 14: protocol.AudioTranscriptionBody.req:type_name -> protocol.AudioTranscriptionRequest
+	19, // 15: protocol.AudioTranscriptionBody.res:type_name -> protocol.AudioTranscriptionResponse
+	46, // 16: protocol.AudioTranscriptionRequest.fields:type_name -> protocol.AudioTranscriptionRequest.FieldsEntry
+	10, // 17: protocol.AudioTranscriptionRequest.wallet:type_name -> protocol.WalletVerification
+	21, // 18: protocol.AudioSpeechBody.req:type_name -> protocol.AudioSpeechRequest
+	22, // 19: protocol.AudioSpeechBody.res:type_name -> protocol.AudioSpeechResponse
+	10, // 20: protocol.AudioSpeechRequest.wallet:type_name -> protocol.WalletVerification
+	30, // 21: protocol.ChatCompletionBody.req:type_name -> protocol.ChatCompletionRequest
+	32, // 22: protocol.ChatCompletionBody.res:type_name -> protocol.ChatCompletionResponse
+	1,  // 23: protocol.ChatContentPart.type:type_name -> protocol.ChatContentPart.Type
+	47, // 24: protocol.ChatContentPart.text:type_name -> protocol.ChatContentPart.Text
+	48, // 25: protocol.ChatContentPart.image:type_name -> protocol.ChatContentPart.Image
+	49, // 26: protocol.ChatContentPart.audio:type_name -> protocol.ChatContentPart.Audio
+	24, // 27: protocol.ChatContentParts.parts:type_name -> protocol.ChatContentPart
+	50, // 28: protocol.ChatToolCall.function:type_name -> protocol.ChatToolCall.Function
+	25, // 29: protocol.ChatCompletionMessage.parts:type_name -> protocol.ChatContentParts
+	26, // 30: protocol.ChatCompletionMessage.tool_calls:type_name -> protocol.ChatToolCall
+	51, // 31: protocol.ChatTool.function:type_name -> protocol.ChatTool.Function
+	52, // 32: protocol.ChatResponseFormat.json_schema:type_name -> protocol.ChatResponseFormat.JSONSchema
+	27, // 33: protocol.ChatCompletionRequest.messages:type_name -> protocol.ChatCompletionMessage
+	53, // 34: protocol.ChatCompletionRequest.logit_bias:type_name -> protocol.ChatCompletionRequest.LogitBiasEntry
+	10, // 35: protocol.ChatCompletionRequest.wallet:type_name -> protocol.WalletVerification
+	29, // 36: protocol.ChatCompletionRequest.response_format:type_name -> protocol.ChatResponseFormat
+	28, // 37: protocol.ChatCompletionRequest.tools:type_name -> protocol.ChatTool
+	26, // 38: protocol.ChatCompletionResponseMessage.tool_calls:type_name -> protocol.ChatToolCall
+	54, // 39: protocol.ChatCompletionResponse.choices:type_name -> protocol.ChatCompletionResponse.ChatResponseChoice
+	55, // 40: protocol.ChatCompletionResponse.usage:type_name -> protocol.ChatCompletionResponse.ChatResponseUsage
+	34, // 41: protocol.HostInfoBody.req:type_name -> protocol.HostInfoRequest
+	35, // 42: protocol.HostInfoBody.res:type_name -> protocol.HostInfoResponse
+	56, // 43: protocol.HostInfoResponse.os:type_name -> protocol.HostInfoResponse.OSInfo
+	57, // 44: protocol.HostInfoResponse.cpu:type_name -> protocol.HostInfoResponse.CpuInfo
+	58, // 45: protocol.HostInfoResponse.memory:type_name -> protocol.HostInfoResponse.MemoryInfo
+	59, // 46: protocol.HostInfoResponse.disk:type_name -> protocol.HostInfoResponse.DiskInfo
+	60, // 47: protocol.HostInfoResponse.gpu:type_name -> protocol.HostInfoResponse.GpuInfo
+	36, // 48: protocol.HostInfoResponse.metrics:type_name -> protocol.HostMetrics
+	61, // 49: protocol.HostMetrics.cpu:type_name -> protocol.HostMetrics.CpuMetrics
+	62, // 50: protocol.HostMetrics.memory:type_name -> protocol.HostMetrics.MemMetrics
+	63, // 51: protocol.HostMetrics.gpu:type_name -> protocol.HostMetrics.GpuMetrics
+	40, // 52: protocol.AIProjectBody.req:type_name -> protocol.AIProjectRequest
+	41, // 53: protocol.AIProjectBody.res:type_name -> protocol.AIProjectResponse
+	38, // 54: protocol.AIProjectOfNode.models:type_name -> protocol.AIModelOfProject
+	39, // 55: protocol.AIProjectResponse.projects:type_name -> protocol.AIProjectOfNode
+	36, // 56: protocol.AIProjectResponse.metrics:type_name -> protocol.HostMetrics
+	42, // 57: protocol.AIProjectResponse.connections:type_name -> protocol.PeerConnection
+	43, // 58: protocol.AIProjectResponse.rotation:type_name -> protocol.KeyRotation
+	31, // 59: protocol.ChatCompletionResponse.ChatResponseChoice.message:type_name -> protocol.ChatCompletionResponseMessage
+	60, // [60:60] is the sub-list for method output_type
+	60, // [60:60] is the sub-list for method input_type
+	60, // [60:60] is the sub-list for extension type_name
+	60, // [60:60] is the sub-list for extension extendee
+	0,  // [0:60] is the sub-list for field type_name
 }
 
 func init() { file_protocol_proto_init() }
@@ -2847,33 +5274,57 @@ func file_protocol_proto_init() {
 	if File_protocol_proto != nil {
 		return
 	}
-	file_protocol_proto_msgTypes[2].OneofWrappers = []any{
+	file_protocol_proto_msgTypes[3].OneofWrappers = []any{
+		(*CancelBody_Req)(nil),
+	}
+	file_protocol_proto_msgTypes[5].OneofWrappers = []any{
 		(*PeerIdentityBody_Req)(nil),
 		(*PeerIdentityBody_Res)(nil),
 	}
-	file_protocol_proto_msgTypes[6].OneofWrappers = []any{
+	file_protocol_proto_msgTypes[9].OneofWrappers = []any{
 		(*ImageGenerationBody_Req)(nil),
 		(*ImageGenerationBody_Res)(nil),
 	}
-	file_protocol_proto_msgTypes[9].OneofWrappers = []any{
+	file_protocol_proto_msgTypes[10].OneofWrappers = []any{}
+	file_protocol_proto_msgTypes[12].OneofWrappers = []any{
+		(*EmbeddingBody_Req)(nil),
+		(*EmbeddingBody_Res)(nil),
+	}
+	file_protocol_proto_msgTypes[15].OneofWrappers = []any{
+		(*AudioTranscriptionBody_Req)(nil),
+		(*AudioTranscriptionBody_Res)(nil),
+	}
+	file_protocol_proto_msgTypes[18].OneofWrappers = []any{
+		(*AudioSpeechBody_Req)(nil),
+		(*AudioSpeechBody_Res)(nil),
+	}
+	file_protocol_proto_msgTypes[21].OneofWrappers = []any{
 		(*ChatCompletionBody_Req)(nil),
 		(*ChatCompletionBody_Res)(nil),
 	}
-	file_protocol_proto_msgTypes[16].OneofWrappers = []any{
+	file_protocol_proto_msgTypes[24].OneofWrappers = []any{}
+	file_protocol_proto_msgTypes[25].OneofWrappers = []any{
+		(*ChatCompletionMessage_Text)(nil),
+		(*ChatCompletionMessage_Parts)(nil),
+	}
+	file_protocol_proto_msgTypes[28].OneofWrappers = []any{}
+	file_protocol_proto_msgTypes[31].OneofWrappers = []any{
 		(*HostInfoBody_Req)(nil),
 		(*HostInfoBody_Res)(nil),
 	}
-	file_protocol_proto_msgTypes[19].OneofWrappers = []any{
+	file_protocol_proto_msgTypes[35].OneofWrappers = []any{
 		(*AIProjectBody_Req)(nil),
 		(*AIProjectBody_Res)(nil),
 	}
+	file_protocol_proto_msgTypes[49].OneofWrappers = []any{}
+	file_protocol_proto_msgTypes[50].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_protocol_proto_rawDesc,
 			NumEnums:      2,
-			NumMessages:   35,
+			NumMessages:   62,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
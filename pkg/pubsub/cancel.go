@@ -0,0 +1,93 @@
+package ps
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/protocol"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// runningRequests tracks the model requests being executed, so that the nodes sending them can
+// cancel them with a CANCEL message of the same request id.
+type runningRequests struct {
+	mutex    sync.Mutex
+	requests map[string]context.CancelFunc
+}
+
+func newRunningRequests() *runningRequests {
+	return &runningRequests{
+		requests: make(map[string]context.CancelFunc),
+	}
+}
+
+// The requests are keyed by the sending node too, so that other nodes can not cancel them.
+func runningKey(nodeID, requestID string) string {
+	return nodeID + "/" + requestID
+}
+
+// start returns the context of the request, which is canceled by the CANCEL message of the
+// sending node, and the function to call when the request is done.
+func (rr *runningRequests) start(ctx context.Context, header *protocol.MessageHeader) (context.Context, func()) {
+	key := runningKey(header.GetNodeId(), header.GetId())
+	ctx, cancel := context.WithCancel(ctx)
+	rr.mutex.Lock()
+	rr.requests[key] = cancel
+	rr.mutex.Unlock()
+	return ctx, func() {
+		rr.mutex.Lock()
+		delete(rr.requests, key)
+		rr.mutex.Unlock()
+		cancel()
+	}
+}
+
+// cancel cancels the request sent by the node, it returns false if the request is not running.
+func (rr *runningRequests) cancel(nodeID, requestID string) bool {
+	rr.mutex.Lock()
+	cancel, ok := rr.requests[runningKey(nodeID, requestID)]
+	rr.mutex.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+// requestCanceled reports whether the request is canceled by its sending node.
+func requestCanceled(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.Canceled)
+}
+
+// handleCancelMessage stops the model request with the same request id, no response is sent
+// since the sending node no longer waits for the request.
+func (pst *PubSub) handleCancelMessage(ctx context.Context, msg *protocol.Message, decBody []byte) (int, string) {
+	body := &protocol.CancelBody{}
+	if err := proto.Unmarshal(decBody, body); err != nil {
+		log.Logger.Warn("Message type and body do not match")
+		return 0, ""
+	}
+	req := body.GetReq()
+	if req == nil {
+		log.Logger.Warn("No cancel request found")
+		return 0, ""
+	}
+	if pst.running.cancel(msg.Header.GetNodeId(), msg.Header.GetId()) {
+		log.Logger.Infof("Cancel %s request %s from %s", req.GetType().String(), msg.Header.GetId(), msg.Header.GetNodeId())
+	} else {
+		log.Logger.Infof("No running %s request %s from %s to cancel", req.GetType().String(), msg.Header.GetId(), msg.Header.GetNodeId())
+	}
+	return 0, ""
+}
+
+// skipCanceledResponse reports whether the response of the request is not sent, because the
+// sending node has canceled the request.
+func skipCanceledResponse(ctx context.Context, msg *protocol.Message) bool {
+	if !requestCanceled(ctx) {
+		return false
+	}
+	log.Logger.Warnf("Skip the response of the canceled %s request %s from %s", msg.Type.String(), msg.Header.GetId(), msg.Header.GetNodeId())
+	return true
+}
@@ -0,0 +1,39 @@
+package ps
+
+import (
+	"context"
+	"testing"
+
+	"AIComputingNode/pkg/protocol"
+)
+
+func TestRunningRequestsCancel(t *testing.T) {
+	rr := newRunningRequests()
+	header := &protocol.MessageHeader{Id: "request", NodeId: "sender", Receiver: "receiver"}
+	ctx, done := rr.start(context.Background(), header)
+
+	if rr.cancel("other", "request") {
+		t.Error("Request canceled by another node")
+	}
+	if rr.cancel("sender", "other") {
+		t.Error("Unknown request canceled")
+	}
+	if requestCanceled(ctx) {
+		t.Fatal("Request canceled before the cancel message")
+	}
+
+	if !rr.cancel("sender", "request") {
+		t.Fatal("Request not canceled by the sending node")
+	}
+	if !requestCanceled(ctx) {
+		t.Fatal("Context of the request not canceled")
+	}
+
+	done()
+	if rr.cancel("sender", "request") {
+		t.Error("Request canceled after it is done")
+	}
+	if len(rr.requests) != 0 {
+		t.Errorf("%d requests left running", len(rr.requests))
+	}
+}
@@ -0,0 +1,175 @@
+package ps
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"AIComputingNode/pkg/protocol"
+
+	"google.golang.org/protobuf/proto"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+)
+
+// ChunkSize is the size of the data of a chunk, leaving room for the headers under the pubsub limit.
+const ChunkSize = pubsub.DefaultMaxMessageSize - 64*1024
+
+// splitMessage splits the marshaled message into MESSAGE_CHUNK messages if it is over the pubsub limit,
+// the chunks keep the header of the message so that they are routed and checked like the message.
+func splitMessage(message []byte, maxSize int) ([][]byte, error) {
+	if len(message) <= pubsub.DefaultMaxMessageSize {
+		return [][]byte{message}, nil
+	}
+	if len(message) > maxSize {
+		return nil, fmt.Errorf("message of %d bytes exceeds the max message size %d", len(message), maxSize)
+	}
+	msg := &protocol.Message{}
+	if err := proto.Unmarshal(message, msg); err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(message)
+	total := (len(message) + ChunkSize - 1) / ChunkSize
+	chunks := make([][]byte, 0, total)
+	for i := 0; i < total; i++ {
+		end := min((i+1)*ChunkSize, len(message))
+		body, err := proto.Marshal(&protocol.MessageChunk{
+			Hash:  hash[:],
+			Index: uint32(i),
+			Total: uint32(total),
+			Size:  uint64(len(message)),
+			Data:  message[i*ChunkSize : end],
+		})
+		if err != nil {
+			return nil, err
+		}
+		chunk, err := proto.Marshal(&protocol.Message{
+			Header: msg.Header,
+			Type:   protocol.MessageType_MESSAGE_CHUNK,
+			Body:   body,
+		})
+		if err != nil {
+			return nil, err
+		}
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+type assembly struct {
+	header   *protocol.MessageHeader
+	hash     []byte
+	size     uint64
+	chunks   [][]byte
+	received int
+	bytes    int
+	deadline time.Time
+}
+
+// reassembler reassembles the messages from their chunks, the incomplete messages
+// are dropped after the timeout or when the chunks take more memory than allowed.
+type reassembler struct {
+	mu        sync.Mutex
+	pending   map[string]*assembly
+	bytes     int
+	maxSize   int
+	maxMemory int
+	timeout   time.Duration
+}
+
+func newReassembler(maxSize, maxMemory int, timeout time.Duration) *reassembler {
+	return &reassembler{
+		pending:   make(map[string]*assembly),
+		maxSize:   maxSize,
+		maxMemory: maxMemory,
+		timeout:   timeout,
+	}
+}
+
+// add adds the chunk, and returns the whole message once all its chunks are received.
+func (r *reassembler) add(msg *protocol.Message) (*protocol.Message, error) {
+	chunk := &protocol.MessageChunk{}
+	if err := proto.Unmarshal(msg.Body, chunk); err != nil {
+		return nil, err
+	}
+	total := int(chunk.GetTotal())
+	if len(chunk.GetHash()) != sha256.Size || chunk.GetSize() == 0 || chunk.GetSize() > uint64(r.maxSize) ||
+		uint64(total) != (chunk.GetSize()+ChunkSize-1)/ChunkSize || int(chunk.GetIndex()) >= total {
+		return nil, fmt.Errorf("invalid chunk %d/%d of %d bytes", chunk.GetIndex(), total, chunk.GetSize())
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.expire(now)
+
+	key := msg.Header.GetNodeId() + "/" + msg.Header.GetId() + "/" + hex.EncodeToString(chunk.GetHash())
+	a, ok := r.pending[key]
+	if !ok {
+		a = &assembly{
+			header:   msg.Header,
+			hash:     chunk.GetHash(),
+			size:     chunk.GetSize(),
+			chunks:   make([][]byte, total),
+			deadline: now.Add(r.timeout),
+		}
+		r.pending[key] = a
+	}
+	if a.size != chunk.GetSize() || len(a.chunks) != total {
+		r.drop(key, a)
+		return nil, fmt.Errorf("chunk %d/%d does not match the message of %d chunks", chunk.GetIndex(), total, len(a.chunks))
+	}
+	if a.chunks[chunk.GetIndex()] != nil {
+		return nil, nil
+	}
+	if uint64(a.bytes+len(chunk.GetData())) > a.size {
+		r.drop(key, a)
+		return nil, fmt.Errorf("chunks exceed the message size %d", a.size)
+	}
+	if r.bytes+len(chunk.GetData()) > r.maxMemory {
+		r.drop(key, a)
+		return nil, fmt.Errorf("chunks exceed the memory limit %d", r.maxMemory)
+	}
+	a.chunks[chunk.GetIndex()] = chunk.GetData()
+	a.received++
+	a.bytes += len(chunk.GetData())
+	r.bytes += len(chunk.GetData())
+	if a.received < total {
+		return nil, nil
+	}
+
+	r.drop(key, a)
+	message := bytes.Join(a.chunks, nil)
+	if uint64(len(message)) != a.size {
+		return nil, fmt.Errorf("message of %d bytes, expected %d", len(message), a.size)
+	}
+	if hash := sha256.Sum256(message); !bytes.Equal(hash[:], a.hash) {
+		return nil, fmt.Errorf("hash mismatch of the message")
+	}
+	whole := &protocol.Message{}
+	if err := proto.Unmarshal(message, whole); err != nil {
+		return nil, err
+	}
+	if whole.Header.GetId() != a.header.GetId() || whole.Header.GetNodeId() != a.header.GetNodeId() ||
+		whole.Header.GetReceiver() != a.header.GetReceiver() || whole.Type == protocol.MessageType_MESSAGE_CHUNK {
+		return nil, fmt.Errorf("header mismatch of the message")
+	}
+	return whole, nil
+}
+
+func (r *reassembler) drop(key string, a *assembly) {
+	delete(r.pending, key)
+	r.bytes -= a.bytes
+}
+
+func (r *reassembler) expire(now time.Time) {
+	for key, a := range r.pending {
+		if now.After(a.deadline) {
+			r.drop(key, a)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package ps
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/protocol"
+
+	"google.golang.org/protobuf/proto"
+)
+
+func newChunkTestMessage(t *testing.T, size int) []byte {
+	body := make([]byte, size)
+	rand.New(rand.NewSource(1)).Read(body)
+	message, err := proto.Marshal(&protocol.Message{
+		Header: &protocol.MessageHeader{Id: "request", NodeId: "sender", Receiver: "receiver"},
+		Type:   protocol.MessageType_IMAGE_GENERATION,
+		Body:   body,
+	})
+	if err != nil {
+		t.Fatalf("Marshal message: %v", err)
+	}
+	return message
+}
+
+func unmarshalChunks(t *testing.T, chunks [][]byte) []*protocol.Message {
+	msgs := make([]*protocol.Message, len(chunks))
+	for i, chunk := range chunks {
+		msgs[i] = &protocol.Message{}
+		if err := proto.Unmarshal(chunk, msgs[i]); err != nil {
+			t.Fatalf("Unmarshal chunk: %v", err)
+		}
+	}
+	return msgs
+}
+
+func TestChunkReassemble(t *testing.T) {
+	message := newChunkTestMessage(t, 3*ChunkSize+100)
+	chunks, err := splitMessage(message, 64<<20)
+	if err != nil {
+		t.Fatalf("Split message: %v", err)
+	}
+	if len(chunks) != 4 {
+		t.Fatalf("Split into %d chunks, want 4", len(chunks))
+	}
+	for _, chunk := range chunks {
+		if len(chunk) > 1<<20 {
+			t.Errorf("Chunk of %d bytes exceeds the pubsub limit", len(chunk))
+		}
+	}
+
+	// out of order and duplicated chunks
+	msgs := unmarshalChunks(t, chunks)
+	r := newReassembler(64<<20, 256<<20, time.Minute)
+	var whole *protocol.Message
+	for _, i := range []int{2, 0, 2, 3, 1} {
+		if whole != nil {
+			t.Fatalf("Message reassembled before chunk %d", i)
+		}
+		if msgs[i].Type != protocol.MessageType_MESSAGE_CHUNK || msgs[i].Header.GetReceiver() != "receiver" {
+			t.Fatalf("Unexpected chunk %+v", msgs[i].Header)
+		}
+		if whole, err = r.add(msgs[i]); err != nil {
+			t.Fatalf("Add chunk %d: %v", i, err)
+		}
+	}
+	if whole == nil {
+		t.Fatal("Message is not reassembled")
+	}
+	if data, _ := proto.Marshal(whole); !bytes.Equal(data, message) {
+		t.Error("Reassembled message does not match")
+	}
+	if len(r.pending) != 0 || r.bytes != 0 {
+		t.Errorf("Reassembler keeps %d messages of %d bytes", len(r.pending), r.bytes)
+	}
+
+	small := newChunkTestMessage(t, 100)
+	if chunks, err := splitMessage(small, 64<<20); err != nil || len(chunks) != 1 || !bytes.Equal(chunks[0], small) {
+		t.Errorf("Small message is split: %v", err)
+	}
+	if _, err := splitMessage(message, 2<<20); err == nil {
+		t.Error("Message over the max size is split")
+	}
+}
+
+func TestChunkLimits(t *testing.T) {
+	message := newChunkTestMessage(t, 2*ChunkSize)
+	chunks, err := splitMessage(message, 64<<20)
+	if err != nil {
+		t.Fatalf("Split message: %v", err)
+	}
+
+	t.Run("Hash", func(t *testing.T) {
+		msgs := unmarshalChunks(t, chunks)
+		chunk := &protocol.MessageChunk{}
+		proto.Unmarshal(msgs[1].Body, chunk)
+		chunk.Data[0] ^= 1
+		msgs[1].Body, _ = proto.Marshal(chunk)
+
+		r := newReassembler(64<<20, 256<<20, time.Minute)
+		var err error
+		for _, msg := range msgs {
+			_, err = r.add(msg)
+		}
+		if err == nil {
+			t.Error("Tampered message is reassembled")
+		}
+	})
+
+	t.Run("MaxSize", func(t *testing.T) {
+		r := newReassembler(ChunkSize, 256<<20, time.Minute)
+		if _, err := r.add(unmarshalChunks(t, chunks)[0]); err == nil {
+			t.Error("Chunk of a message over the max size is accepted")
+		}
+	})
+
+	t.Run("Memory", func(t *testing.T) {
+		r := newReassembler(64<<20, ChunkSize+ChunkSize/2, time.Minute)
+		other, _ := splitMessage(newChunkTestMessage(t, 2*ChunkSize+1), 64<<20)
+		if _, err := r.add(unmarshalChunks(t, chunks)[0]); err != nil {
+			t.Fatalf("Add chunk: %v", err)
+		}
+		if _, err := r.add(unmarshalChunks(t, other)[0]); err == nil {
+			t.Error("Chunks over the memory limit are accepted")
+		}
+		if r.bytes != ChunkSize {
+			t.Errorf("Reassembler keeps %d bytes", r.bytes)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		r := newReassembler(64<<20, 256<<20, 0)
+		msgs := unmarshalChunks(t, chunks)
+		r.add(msgs[0])
+		time.Sleep(time.Millisecond)
+		if whole, err := r.add(msgs[1]); whole != nil || err != nil {
+			t.Errorf("Expired message is reassembled: %v", err)
+		}
+		// the first chunk expired, only the second one is kept
+		for _, a := range r.pending {
+			if a.received != 1 || a.chunks[0] != nil {
+				t.Errorf("Expired chunk is kept")
+			}
+		}
+	})
+}
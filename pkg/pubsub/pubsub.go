@@ -4,15 +4,17 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
-	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/blob"
 	"AIComputingNode/pkg/db"
 	"AIComputingNode/pkg/hardware"
-	"AIComputingNode/pkg/libp2p/host"
+	"AIComputingNode/pkg/keystore"
 	"AIComputingNode/pkg/log"
 	"AIComputingNode/pkg/model"
 	"AIComputingNode/pkg/protocol"
+	"AIComputingNode/pkg/scheduler"
 	"AIComputingNode/pkg/serve"
 	"AIComputingNode/pkg/timer"
 	"AIComputingNode/pkg/types"
@@ -25,16 +27,29 @@ import (
 var MsgNotSupported string = "Not supported"
 
 type PubSub struct {
+	env         *serve.Env
 	topic       *pubsub.Topic
 	sub         *pubsub.Subscription
 	publishChan chan []byte
+	store       db.Store
+	blobs       *blob.Store
+	chunks      *reassembler
+	running     *runningRequests
+	scheduler   *scheduler.Scheduler
 }
 
-func NewPubSub(topic *pubsub.Topic, sub *pubsub.Subscription, pc chan []byte) *PubSub {
+func NewPubSub(env *serve.Env, topic *pubsub.Topic, sub *pubsub.Subscription, pc chan []byte, store db.Store, blobs *blob.Store, sched *scheduler.Scheduler) *PubSub {
+	timeout, _ := time.ParseDuration(env.Config.Pubsub.ChunkTimeout)
 	return &PubSub{
+		env:         env,
 		topic:       topic,
 		sub:         sub,
 		publishChan: pc,
+		store:       store,
+		blobs:       blobs,
+		chunks:      newReassembler(env.Config.Pubsub.MaxMessageSize, env.Config.Pubsub.MaxChunkMemory, timeout),
+		running:     newRunningRequests(),
+		scheduler:   sched,
 	}
 }
 
@@ -45,8 +60,19 @@ func (pst *PubSub) PublishToTopic(ctx context.Context) {
 			log.Logger.Info("Publish to topic goroutine end")
 			return
 		case message := <-pst.publishChan:
-			if err := pst.topic.Publish(ctx, message); err != nil {
-				log.Logger.Errorf("Error when publish to topic %v", err)
+			chunks, err := splitMessage(message, pst.env.Config.Pubsub.MaxMessageSize)
+			if err != nil {
+				log.Logger.Errorf("Error when split message %v", err)
+				continue
+			}
+			for _, chunk := range chunks {
+				if err := pst.topic.Publish(ctx, chunk); err != nil {
+					log.Logger.Errorf("Error when publish to topic %v", err)
+					break
+				}
+			}
+			if len(chunks) > 1 {
+				log.Logger.Infof("Published %d bytes in %d chunks", len(message), len(chunks))
 			} else {
 				log.Logger.Infof("Published %d bytes", len(message))
 			}
@@ -73,17 +99,37 @@ func (pst *PubSub) ReadFromTopic(ctx context.Context) {
 			log.Logger.Warnf("Unmarshal PubSub: %v", err)
 			continue
 		}
+		// The header is authenticated with the encrypted body, so the sender must be the signed author
+		if from := msg.GetFrom(); from != "" && from.String() != pmsg.Header.GetNodeId() {
+			log.Logger.Warnf("Drop message type %s from %s published by %s", pmsg.Type, pmsg.Header.GetNodeId(), msg.GetFrom())
+			continue
+		}
+		// The RSA keys are not in the peer ids, keep them to encrypt the messages to the peer
+		if from := msg.GetFrom(); from != "" {
+			pst.env.Host.AddPublicKey(from, msg.Key)
+			pst.env.Host.AddPublicKey(from, pmsg.Header.GetNodePubKey())
+		}
 
 		if pmsg.Header.GetId() == "" && pmsg.Header.GetReceiver() == "" {
 			log.Logger.Infof("Received scheduled broadcast message type %s from %s", pmsg.Type, pmsg.Header.GetNodeId())
 			pst.handleScheduledBroadcastMessage(ctx, pmsg)
 			continue
-		} else if pmsg.Header.GetNodeId() == config.GC.Identity.PeerID {
+		} else if pmsg.Header.GetNodeId() == pst.env.Config.Identity.PeerID {
 			log.Logger.Infof("Received message type %s from the node itself", pmsg.Type)
 			continue
-		} else if pmsg.Header.GetReceiver() != config.GC.Identity.PeerID {
+		} else if pmsg.Header.GetReceiver() != pst.env.Config.Identity.PeerID {
 			log.Logger.Infof("Gossip message type %s from %s to %s", pmsg.Type, pmsg.Header.GetNodeId(), pmsg.Header.GetReceiver())
 			continue
+		} else if pmsg.Type == protocol.MessageType_MESSAGE_CHUNK {
+			whole, err := pst.chunks.add(pmsg)
+			if err != nil {
+				log.Logger.Warnf("Drop chunk from %s with request_id %s %v", pmsg.Header.GetNodeId(), pmsg.Header.GetId(), err)
+				continue
+			} else if whole == nil {
+				continue
+			}
+			pmsg = whole
+			log.Logger.Infof("Received chunked message type %s from %s with request_id %s", pmsg.Type, pmsg.Header.GetNodeId(), pmsg.Header.GetId())
 		} else {
 			log.Logger.Infof("Received message type %s from %s with request_id %s", pmsg.Type, pmsg.Header.GetNodeId(), pmsg.Header.GetId())
 		}
@@ -102,7 +148,7 @@ func (pst *PubSub) handleScheduledBroadcastMessage(ctx context.Context, msg *pro
 }
 
 func (pst *PubSub) handleScheduledAIProjectMessage(ctx context.Context, msg *protocol.Message) {
-	if !config.GC.App.PeersCollect.Enabled {
+	if !pst.env.Config.App.PeersCollect.Enabled {
 		log.Logger.Warnf("PeersCollect disabled when received %v message", msg.Type)
 		return
 	}
@@ -116,19 +162,34 @@ func (pst *PubSub) handleScheduledAIProjectMessage(ctx context.Context, msg *pro
 			Timestamp:  time.Now().Unix(),
 			AIProjects: types.ProtocolMessage2AIProject(aiRes),
 			NodeType:   aiRes.NodeType,
+			Metrics:    types.ProtocolMessage2HostMetrics(aiRes.GetMetrics()),
+			// the connections of the heartbeat are the edges of the topology
+			Connections: types.ProtocolMessage2PeerConnections(aiRes.GetConnections()),
+		}
+		pst.store.UpdatePeerCollect(msg.Header.GetNodeId(), info)
+		if r := aiRes.GetRotation(); r != nil {
+			rotation := keystore.Rotation{
+				PreviousPeerID: r.GetPreviousNodeId(),
+				PreviousPubKey: r.GetPreviousPubKey(),
+				Signature:      r.GetSignature(),
+			}
+			if err := rotation.Verify(msg.Header.GetNodeId()); err != nil {
+				log.Logger.Warnf("Invalid key rotation from %s %v", msg.Header.GetNodeId(), err)
+			} else {
+				pst.store.DeletePeerCollect(rotation.PreviousPeerID)
+			}
 		}
-		db.UpdatePeerCollect(msg.Header.GetNodeId(), info)
 	} else {
 		log.Logger.Warn("No ai project response found")
 	}
 }
 
 func (pst *PubSub) handleBroadcastMessage(ctx context.Context, msg *protocol.Message) {
-	existed := serve.ExistRequestItem(msg.Header.GetId())
+	existed := pst.env.Requests.ExistRequestItem(msg.Header.GetId())
 	var code int
 	var message string
 	if msg.GetResultCode() == 0 {
-		msgBody, err := host.Decrypt(msg.Header.GetNodePubKey(), msg.Body)
+		msgBody, err := pst.env.Host.DecryptMessage(msg)
 		if err != nil {
 			code = int(types.ErrCodeDecrypt)
 			message = types.ErrCodeDecrypt.String()
@@ -145,6 +206,14 @@ func (pst *PubSub) handleBroadcastMessage(ctx context.Context, msg *protocol.Mes
 				code, message = pst.handleChatCompletionMessage(ctx, msg, msgBody)
 			case protocol.MessageType_IMAGE_GENERATION:
 				code, message = pst.handleImageGenerationMessage(ctx, msg, msgBody)
+			case protocol.MessageType_EMBEDDING:
+				code, message = pst.handleEmbeddingMessage(ctx, msg, msgBody)
+			case protocol.MessageType_AUDIO_TRANSCRIPTION:
+				code, message = pst.handleAudioTranscriptionMessage(ctx, msg, msgBody)
+			case protocol.MessageType_AUDIO_SPEECH:
+				code, message = pst.handleAudioSpeechMessage(ctx, msg, msgBody)
+			case protocol.MessageType_CANCEL:
+				code, message = pst.handleCancelMessage(ctx, msg, msgBody)
 			default:
 				code = int(types.ErrCodeUnsupported)
 				message = MsgNotSupported
@@ -167,10 +236,10 @@ func (pst *PubSub) handleBroadcastMessage(ctx context.Context, msg *protocol.Mes
 				log.Logger.Errorf("Marshal %s json %v", msg.Type.String(), err)
 				return
 			}
-			serve.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
+			pst.env.Requests.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
 			log.Logger.Warnf("Send %s json response {code: %v, message: %v}", msg.Type.String(), code, message)
 		} else {
-			res := TransformErrorResponse(msg, int32(code), message)
+			res := pst.TransformErrorResponse(msg, int32(code), message)
 			resBytes, err := proto.Marshal(res)
 			if err != nil {
 				log.Logger.Errorf("Marshal %s proto %v", msg.Type.String(), err)
@@ -193,12 +262,12 @@ func (pst *PubSub) handleBroadcastMessage(ctx context.Context, msg *protocol.Mes
 				log.Logger.Errorf("Marshal %s json %v", msg.Type.String(), err)
 				return
 			}
-			serve.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
+			pst.env.Requests.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
 			log.Logger.Warnf("Send %s json response {code: %v, message: %v}", msg.Type.String(), code, message)
 		} else {
 			log.Logger.Warnf("Unknown %s message with request_id %s and {result_code: %v, result_message: %v}, cannot be processed",
 				msg.Type.String(), msg.Header.GetId(), code, message)
-			// res := TransformErrorResponse(msg, int32(code), message)
+			// res := pst.TransformErrorResponse(msg, int32(code), message)
 			// resBytes, err := proto.Marshal(res)
 			// if err != nil {
 			// 	log.Logger.Errorf("Marshal %s proto %v", msg.Type.String(), err)
@@ -214,7 +283,7 @@ func (pst *PubSub) handlePeerIdentityMessage(ctx context.Context, msg *protocol.
 	pi := &protocol.PeerIdentityBody{}
 	if err := proto.Unmarshal(decBody, pi); err == nil {
 		if piReq := pi.GetReq(); piReq != nil {
-			idp := host.Hio.GetIdentifyProtocol()
+			idp := pst.env.Host.GetIdentifyProtocol()
 			piBody := &protocol.PeerIdentityBody{
 				Data: &protocol.PeerIdentityBody_Res{
 					Res: &protocol.PeerIdentityResponse{
@@ -230,21 +299,20 @@ func (pst *PubSub) handlePeerIdentityMessage(ctx context.Context, msg *protocol.
 				log.Logger.Errorf("Marshal Identity Response Body %v", err)
 				return int(types.ErrCodeProtobuf), types.ErrCodeProtobuf.String()
 			}
-			resBody, err = host.Encrypt(ctx, msg.Header.GetNodeId(), resBody)
 			res := protocol.Message{
 				Header: &protocol.MessageHeader{
-					ClientVersion: host.Hio.UserAgent,
+					ClientVersion: pst.env.Host.UserAgent,
 					Timestamp:     time.Now().Unix(),
 					Id:            msg.Header.GetId(),
-					NodeId:        config.GC.Identity.PeerID,
+					NodeId:        pst.env.Config.Identity.PeerID,
 					Receiver:      msg.Header.GetNodeId(),
 				},
 				Type:       protocol.MessageType_PEER_IDENTITY,
 				Body:       resBody,
 				ResultCode: 0,
 			}
-			if err == nil {
-				res.Header.NodePubKey, _ = host.MarshalPubKeyFromPrivKey(host.Hio.PrivKey)
+			if err := pst.env.Host.EncryptMessage(ctx, &res); err != nil {
+				log.Logger.Warnf("Encrypt %s response to %s failed %v", res.Type.String(), res.Header.GetReceiver(), err)
 			}
 			resBytes, err := proto.Marshal(&res)
 			if err != nil {
@@ -273,7 +341,7 @@ func (pst *PubSub) handlePeerIdentityMessage(ctx context.Context, msg *protocol.
 				log.Logger.Errorf("Marshal Identity Protocol %v", err)
 				return int(types.ErrCodeJson), types.ErrCodeJson.String()
 			}
-			serve.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
+			pst.env.Requests.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
 			return 0, ""
 		} else {
 			log.Logger.Error("No request or response found")
@@ -289,7 +357,17 @@ func (pst *PubSub) handleChatCompletionMessage(ctx context.Context, msg *protoco
 	ccb := &protocol.ChatCompletionBody{}
 	if err := proto.Unmarshal(decBody, ccb); err == nil {
 		if chatReq := ccb.GetReq(); chatReq != nil {
+			ctx, done := pst.running.start(ctx, msg.Header)
+			defer done()
+			release, code, message := pst.schedule(ctx, msg)
+			if release == nil {
+				return code, message
+			}
+			defer release()
 			code, message, chatRes := pst.handleChatCompletionRequest(ctx, chatReq, msg.Header)
+			if skipCanceledResponse(ctx, msg) {
+				return 0, ""
+			}
 			igBody := &protocol.ChatCompletionBody{
 				Data: &protocol.ChatCompletionBody_Res{
 					Res: chatRes,
@@ -300,13 +378,12 @@ func (pst *PubSub) handleChatCompletionMessage(ctx context.Context, msg *protoco
 				log.Logger.Errorf("Marshal Chat Completion Response Body %v", err)
 				return int(types.ErrCodeProtobuf), types.ErrCodeProtobuf.String()
 			}
-			resBody, err = host.Encrypt(ctx, msg.Header.GetNodeId(), resBody)
 			res := protocol.Message{
 				Header: &protocol.MessageHeader{
-					ClientVersion: host.Hio.UserAgent,
+					ClientVersion: pst.env.Host.UserAgent,
 					Timestamp:     chatRes.GetCreated(),
 					Id:            msg.Header.GetId(),
-					NodeId:        config.GC.Identity.PeerID,
+					NodeId:        pst.env.Config.Identity.PeerID,
 					Receiver:      msg.Header.GetNodeId(),
 				},
 				Type:          protocol.MessageType_CHAT_COMPLETION,
@@ -314,8 +391,8 @@ func (pst *PubSub) handleChatCompletionMessage(ctx context.Context, msg *protoco
 				ResultCode:    int32(code),
 				ResultMessage: message,
 			}
-			if err == nil {
-				res.Header.NodePubKey, _ = host.MarshalPubKeyFromPrivKey(host.Hio.PrivKey)
+			if err := pst.env.Host.EncryptMessage(ctx, &res); err != nil {
+				log.Logger.Warnf("Encrypt %s response to %s failed %v", res.Type.String(), res.Header.GetReceiver(), err)
 			}
 			resBytes, err := proto.Marshal(&res)
 			if err != nil {
@@ -333,32 +410,14 @@ func (pst *PubSub) handleChatCompletionMessage(ctx context.Context, msg *protoco
 				},
 			}
 			if msg.ResultCode == 0 {
-				res.Created = chatRes.GetCreated()
-				for _, choice := range chatRes.Choices {
-					ccmsg := types.ChatCompletionResponseMessage{
-						Role:    choice.GetMessage().GetRole(),
-						Content: choice.GetMessage().GetContent(),
-					}
-					res.Choices = append(res.Choices, types.ChatResponseChoice{
-						Index:        int(choice.GetIndex()),
-						Message:      ccmsg,
-						FinishReason: choice.GetFinishReason(),
-					})
-				}
-				res.Usage = types.ChatResponseUsage{
-					CompletionTokens: int(chatRes.GetUsage().GetCompletionTokens()),
-					PromptTokens:     int(chatRes.GetUsage().GetPromptTokens()),
-					TotalTokens:      int(chatRes.GetUsage().GetTotalTokens()),
-				}
-				res.Id = chatRes.GetId()
-				res.Object = chatRes.GetObject()
+				res.ChatModelResponseData = types.ProtocolMessage2ChatModelResponse(chatRes)
 			}
 			notifyData, err := json.Marshal(res)
 			if err != nil {
 				log.Logger.Errorf("Marshal Chat Completion Response %v", err)
 				return int(types.ErrCodeJson), types.ErrCodeJson.String()
 			}
-			serve.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
+			pst.env.Requests.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
 			return 0, ""
 		} else {
 			log.Logger.Error("No request or response found")
@@ -374,7 +433,17 @@ func (pst *PubSub) handleImageGenerationMessage(ctx context.Context, msg *protoc
 	ig := &protocol.ImageGenerationBody{}
 	if err := proto.Unmarshal(decBody, ig); err == nil {
 		if igReq := ig.GetReq(); igReq != nil {
+			ctx, done := pst.running.start(ctx, msg.Header)
+			defer done()
+			release, code, message := pst.schedule(ctx, msg)
+			if release == nil {
+				return code, message
+			}
+			defer release()
 			code, message, igRes := pst.handleImageGenerationRequest(ctx, igReq, msg.Header)
+			if skipCanceledResponse(ctx, msg) {
+				return 0, ""
+			}
 			igBody := &protocol.ImageGenerationBody{
 				Data: &protocol.ImageGenerationBody_Res{
 					Res: igRes,
@@ -385,13 +454,12 @@ func (pst *PubSub) handleImageGenerationMessage(ctx context.Context, msg *protoc
 				log.Logger.Errorf("Marshal Image Generation Response Body %v", err)
 				return int(types.ErrCodeProtobuf), types.ErrCodeProtobuf.String()
 			}
-			resBody, err = host.Encrypt(ctx, msg.Header.GetNodeId(), resBody)
 			res := protocol.Message{
 				Header: &protocol.MessageHeader{
-					ClientVersion: host.Hio.UserAgent,
+					ClientVersion: pst.env.Host.UserAgent,
 					Timestamp:     igRes.GetCreated(),
 					Id:            msg.Header.GetId(),
-					NodeId:        config.GC.Identity.PeerID,
+					NodeId:        pst.env.Config.Identity.PeerID,
 					Receiver:      msg.Header.GetNodeId(),
 				},
 				Type:          protocol.MessageType_IMAGE_GENERATION,
@@ -399,8 +467,8 @@ func (pst *PubSub) handleImageGenerationMessage(ctx context.Context, msg *protoc
 				ResultCode:    int32(code),
 				ResultMessage: message,
 			}
-			if err == nil {
-				res.Header.NodePubKey, _ = host.MarshalPubKeyFromPrivKey(host.Hio.PrivKey)
+			if err := pst.env.Host.EncryptMessage(ctx, &res); err != nil {
+				log.Logger.Warnf("Encrypt %s response to %s failed %v", res.Type.String(), res.Header.GetReceiver(), err)
 			}
 			resBytes, err := proto.Marshal(&res)
 			if err != nil {
@@ -432,7 +500,201 @@ func (pst *PubSub) handleImageGenerationMessage(ctx context.Context, msg *protoc
 				log.Logger.Errorf("Marshal Image Generation Response %v", err)
 				return int(types.ErrCodeJson), types.ErrCodeJson.String()
 			}
-			serve.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
+			pst.env.Requests.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
+			return 0, ""
+		} else {
+			log.Logger.Error("No request or response found")
+			return int(types.ErrCodeProtobuf), "No request or response found"
+		}
+	} else {
+		log.Logger.Warn("Message type and body do not match")
+		return int(types.ErrCodeProtobuf), "Message type and body do not match"
+	}
+}
+
+func (pst *PubSub) handleEmbeddingMessage(ctx context.Context, msg *protocol.Message, decBody []byte) (int, string) {
+	eb := &protocol.EmbeddingBody{}
+	if err := proto.Unmarshal(decBody, eb); err == nil {
+		if ebReq := eb.GetReq(); ebReq != nil {
+			ctx, done := pst.running.start(ctx, msg.Header)
+			defer done()
+			release, code, message := pst.schedule(ctx, msg)
+			if release == nil {
+				return code, message
+			}
+			defer release()
+			code, message, ebRes := pst.handleEmbeddingRequest(ctx, ebReq, msg.Header)
+			if skipCanceledResponse(ctx, msg) {
+				return 0, ""
+			}
+			ebBody := &protocol.EmbeddingBody{
+				Data: &protocol.EmbeddingBody_Res{
+					Res: ebRes,
+				},
+			}
+			resBody, err := proto.Marshal(ebBody)
+			if err != nil {
+				log.Logger.Errorf("Marshal Embedding Response Body %v", err)
+				return int(types.ErrCodeProtobuf), types.ErrCodeProtobuf.String()
+			}
+			res := protocol.Message{
+				Header: &protocol.MessageHeader{
+					ClientVersion: pst.env.Host.UserAgent,
+					Timestamp:     time.Now().Unix(),
+					Id:            msg.Header.GetId(),
+					NodeId:        pst.env.Config.Identity.PeerID,
+					Receiver:      msg.Header.GetNodeId(),
+				},
+				Type:          protocol.MessageType_EMBEDDING,
+				Body:          resBody,
+				ResultCode:    int32(code),
+				ResultMessage: message,
+			}
+			if err := pst.env.Host.EncryptMessage(ctx, &res); err != nil {
+				log.Logger.Warnf("Encrypt %s response to %s failed %v", res.Type.String(), res.Header.GetReceiver(), err)
+			}
+			resBytes, err := proto.Marshal(&res)
+			if err != nil {
+				log.Logger.Errorf("Marshal Embedding Response %v", err)
+				return int(types.ErrCodeProtobuf), types.ErrCodeProtobuf.String()
+			}
+			pst.publishChan <- resBytes
+			log.Logger.Info("Sending Embedding Response")
+			return 0, ""
+		} else if ebRes := eb.GetRes(); ebRes != nil {
+			res := types.EmbeddingResponse{
+				BaseHttpResponse: types.BaseHttpResponse{
+					Code:    int(msg.ResultCode),
+					Message: msg.ResultMessage,
+				},
+			}
+			if msg.ResultCode == 0 {
+				res.Object = "list"
+				res.Model = ebRes.GetModel()
+				for _, data := range ebRes.GetData() {
+					res.Data = append(res.Data, types.EmbeddingData{
+						Object:    "embedding",
+						Index:     int(data.GetIndex()),
+						Embedding: data.GetEmbedding(),
+					})
+				}
+				res.Usage = types.EmbeddingUsage{
+					PromptTokens: int(ebRes.GetPromptTokens()),
+					TotalTokens:  int(ebRes.GetTotalTokens()),
+				}
+			}
+			notifyData, err := json.Marshal(res)
+			if err != nil {
+				log.Logger.Errorf("Marshal Embedding Response %v", err)
+				return int(types.ErrCodeJson), types.ErrCodeJson.String()
+			}
+			pst.env.Requests.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
+			return 0, ""
+		} else {
+			log.Logger.Error("No request or response found")
+			return int(types.ErrCodeProtobuf), "No request or response found"
+		}
+	} else {
+		log.Logger.Warn("Message type and body do not match")
+		return int(types.ErrCodeProtobuf), "Message type and body do not match"
+	}
+}
+
+func (pst *PubSub) handleAudioTranscriptionMessage(ctx context.Context, msg *protocol.Message, decBody []byte) (int, string) {
+	body := &protocol.AudioTranscriptionBody{}
+	if err := proto.Unmarshal(decBody, body); err == nil {
+		if req := body.GetReq(); req != nil {
+			ctx, done := pst.running.start(ctx, msg.Header)
+			defer done()
+			release, code, message := pst.schedule(ctx, msg)
+			if release == nil {
+				return code, message
+			}
+			defer release()
+			code, message, res := pst.handleAudioTranscriptionRequest(ctx, req, msg.Header)
+			if skipCanceledResponse(ctx, msg) {
+				return 0, ""
+			}
+			resBody, err := proto.Marshal(&protocol.AudioTranscriptionBody{
+				Data: &protocol.AudioTranscriptionBody_Res{
+					Res: res,
+				},
+			})
+			if err != nil {
+				log.Logger.Errorf("Marshal Audio Transcription Response Body %v", err)
+				return int(types.ErrCodeProtobuf), types.ErrCodeProtobuf.String()
+			}
+			return pst.publishResponse(ctx, msg, protocol.MessageType_AUDIO_TRANSCRIPTION, resBody, code, message)
+		} else if res := body.GetRes(); res != nil {
+			notify := types.AudioResponse{
+				BaseHttpResponse: types.BaseHttpResponse{
+					Code:    int(msg.ResultCode),
+					Message: msg.ResultMessage,
+				},
+			}
+			if msg.ResultCode == 0 {
+				notify.ContentType = res.GetContentType()
+				notify.Data = res.GetData()
+			}
+			notifyData, err := json.Marshal(notify)
+			if err != nil {
+				log.Logger.Errorf("Marshal Audio Transcription Response %v", err)
+				return int(types.ErrCodeJson), types.ErrCodeJson.String()
+			}
+			pst.env.Requests.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
+			return 0, ""
+		} else {
+			log.Logger.Error("No request or response found")
+			return int(types.ErrCodeProtobuf), "No request or response found"
+		}
+	} else {
+		log.Logger.Warn("Message type and body do not match")
+		return int(types.ErrCodeProtobuf), "Message type and body do not match"
+	}
+}
+
+func (pst *PubSub) handleAudioSpeechMessage(ctx context.Context, msg *protocol.Message, decBody []byte) (int, string) {
+	body := &protocol.AudioSpeechBody{}
+	if err := proto.Unmarshal(decBody, body); err == nil {
+		if req := body.GetReq(); req != nil {
+			ctx, done := pst.running.start(ctx, msg.Header)
+			defer done()
+			release, code, message := pst.schedule(ctx, msg)
+			if release == nil {
+				return code, message
+			}
+			defer release()
+			code, message, res := pst.handleAudioSpeechRequest(ctx, req, msg.Header)
+			if skipCanceledResponse(ctx, msg) {
+				return 0, ""
+			}
+			resBody, err := proto.Marshal(&protocol.AudioSpeechBody{
+				Data: &protocol.AudioSpeechBody_Res{
+					Res: res,
+				},
+			})
+			if err != nil {
+				log.Logger.Errorf("Marshal Audio Speech Response Body %v", err)
+				return int(types.ErrCodeProtobuf), types.ErrCodeProtobuf.String()
+			}
+			return pst.publishResponse(ctx, msg, protocol.MessageType_AUDIO_SPEECH, resBody, code, message)
+		} else if res := body.GetRes(); res != nil {
+			notify := types.AudioResponse{
+				BaseHttpResponse: types.BaseHttpResponse{
+					Code:    int(msg.ResultCode),
+					Message: msg.ResultMessage,
+				},
+			}
+			if msg.ResultCode == 0 {
+				notify.ContentType = res.GetContentType()
+				notify.Data = res.GetAudio()
+			}
+			notifyData, err := json.Marshal(notify)
+			if err != nil {
+				log.Logger.Errorf("Marshal Audio Speech Response %v", err)
+				return int(types.ErrCodeJson), types.ErrCodeJson.String()
+			}
+			pst.env.Requests.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
 			return 0, ""
 		} else {
 			log.Logger.Error("No request or response found")
@@ -444,6 +706,34 @@ func (pst *PubSub) handleImageGenerationMessage(ctx context.Context, msg *protoc
 	}
 }
 
+// publishResponse sends the response body of the request message back to its sender.
+func (pst *PubSub) publishResponse(ctx context.Context, msg *protocol.Message, msgType protocol.MessageType, resBody []byte, code int, message string) (int, string) {
+	res := protocol.Message{
+		Header: &protocol.MessageHeader{
+			ClientVersion: pst.env.Host.UserAgent,
+			Timestamp:     time.Now().Unix(),
+			Id:            msg.Header.GetId(),
+			NodeId:        pst.env.Config.Identity.PeerID,
+			Receiver:      msg.Header.GetNodeId(),
+		},
+		Type:          msgType,
+		Body:          resBody,
+		ResultCode:    int32(code),
+		ResultMessage: message,
+	}
+	if err := pst.env.Host.EncryptMessage(ctx, &res); err != nil {
+		log.Logger.Warnf("Encrypt %s response to %s failed %v", res.Type.String(), res.Header.GetReceiver(), err)
+	}
+	resBytes, err := proto.Marshal(&res)
+	if err != nil {
+		log.Logger.Errorf("Marshal %s response %v", res.Type.String(), err)
+		return int(types.ErrCodeProtobuf), types.ErrCodeProtobuf.String()
+	}
+	pst.publishChan <- resBytes
+	log.Logger.Infof("Sending %s response", res.Type.String())
+	return 0, ""
+}
+
 func (pst *PubSub) handleHostInfoMessage(ctx context.Context, msg *protocol.Message, decBody []byte) (int, string) {
 	hi := &protocol.HostInfoBody{}
 	if err := proto.Unmarshal(decBody, hi); err == nil {
@@ -466,13 +756,12 @@ func (pst *PubSub) handleHostInfoMessage(ctx context.Context, msg *protocol.Mess
 				log.Logger.Warnf("Marshal HostInfo Response Body %v", err)
 				return int(types.ErrCodeProtobuf), types.ErrCodeProtobuf.String()
 			}
-			resBody, err = host.Encrypt(ctx, msg.Header.GetNodeId(), resBody)
 			res := protocol.Message{
 				Header: &protocol.MessageHeader{
-					ClientVersion: host.Hio.UserAgent,
+					ClientVersion: pst.env.Host.UserAgent,
 					Timestamp:     time.Now().Unix(),
 					Id:            msg.Header.GetId(),
-					NodeId:        config.GC.Identity.PeerID,
+					NodeId:        pst.env.Config.Identity.PeerID,
 					Receiver:      msg.Header.GetNodeId(),
 				},
 				Type:          protocol.MessageType_HOST_INFO,
@@ -480,8 +769,8 @@ func (pst *PubSub) handleHostInfoMessage(ctx context.Context, msg *protocol.Mess
 				ResultCode:    code,
 				ResultMessage: message,
 			}
-			if err == nil {
-				res.Header.NodePubKey, _ = host.MarshalPubKeyFromPrivKey(host.Hio.PrivKey)
+			if err := pst.env.Host.EncryptMessage(ctx, &res); err != nil {
+				log.Logger.Warnf("Encrypt %s response to %s failed %v", res.Type.String(), res.Header.GetReceiver(), err)
 			}
 			resBytes, err := proto.Marshal(&res)
 			if err != nil {
@@ -504,7 +793,7 @@ func (pst *PubSub) handleHostInfoMessage(ctx context.Context, msg *protocol.Mess
 				log.Logger.Errorf("Marshal HostInfo Protocol %v", err)
 				return int(types.ErrCodeJson), types.ErrCodeJson.String()
 			}
-			serve.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
+			pst.env.Requests.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
 			return 0, ""
 		} else {
 			log.Logger.Error("No request or response found")
@@ -520,7 +809,7 @@ func (pst *PubSub) handleAIProjectMessage(ctx context.Context, msg *protocol.Mes
 	aip := &protocol.AIProjectBody{}
 	if err := proto.Unmarshal(decBody, aip); err == nil {
 		if aiReq := aip.GetReq(); aiReq != nil {
-			projects := model.GetAIProjects()
+			projects := pst.env.Models.GetAIProjects()
 			aiBody := &protocol.AIProjectBody{
 				Data: &protocol.AIProjectBody_Res{
 					Res: types.AIProject2ProtocolMessage(projects, 0),
@@ -531,13 +820,12 @@ func (pst *PubSub) handleAIProjectMessage(ctx context.Context, msg *protocol.Mes
 				log.Logger.Warnf("Marshal AI Project Response Body %v", err)
 				return int(types.ErrCodeProtobuf), types.ErrCodeProtobuf.String()
 			}
-			resBody, err = host.Encrypt(ctx, msg.Header.GetNodeId(), resBody)
 			res := protocol.Message{
 				Header: &protocol.MessageHeader{
-					ClientVersion: host.Hio.UserAgent,
+					ClientVersion: pst.env.Host.UserAgent,
 					Timestamp:     time.Now().Unix(),
 					Id:            msg.Header.GetId(),
-					NodeId:        config.GC.Identity.PeerID,
+					NodeId:        pst.env.Config.Identity.PeerID,
 					Receiver:      msg.Header.GetNodeId(),
 				},
 				Type:          protocol.MessageType_AI_PROJECT,
@@ -545,8 +833,8 @@ func (pst *PubSub) handleAIProjectMessage(ctx context.Context, msg *protocol.Mes
 				ResultCode:    0,
 				ResultMessage: "",
 			}
-			if err == nil {
-				res.Header.NodePubKey, _ = host.MarshalPubKeyFromPrivKey(host.Hio.PrivKey)
+			if err := pst.env.Host.EncryptMessage(ctx, &res); err != nil {
+				log.Logger.Warnf("Encrypt %s response to %s failed %v", res.Type.String(), res.Header.GetReceiver(), err)
 			}
 			resBytes, err := proto.Marshal(&res)
 			if err != nil {
@@ -569,7 +857,7 @@ func (pst *PubSub) handleAIProjectMessage(ctx context.Context, msg *protocol.Mes
 				log.Logger.Errorf("Marshal AI Project Protocol %v", err)
 				return int(types.ErrCodeJson), types.ErrCodeJson.String()
 			}
-			serve.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
+			pst.env.Requests.WriteAndDeleteRequestItem(msg.Header.GetId(), notifyData)
 			return 0, ""
 		} else {
 			log.Logger.Error("No request or response found")
@@ -581,13 +869,13 @@ func (pst *PubSub) handleAIProjectMessage(ctx context.Context, msg *protocol.Mes
 	}
 }
 
-func TransformErrorResponse(msg *protocol.Message, code int32, message string) *protocol.Message {
+func (pst *PubSub) TransformErrorResponse(msg *protocol.Message, code int32, message string) *protocol.Message {
 	res := protocol.Message{
 		Header: &protocol.MessageHeader{
-			ClientVersion: host.Hio.UserAgent,
+			ClientVersion: pst.env.Host.UserAgent,
 			Timestamp:     time.Now().Unix(),
 			Id:            msg.Header.GetId(),
-			NodeId:        config.GC.Identity.PeerID,
+			NodeId:        pst.env.Config.Identity.PeerID,
 			Receiver:      msg.Header.GetNodeId(),
 			NodePubKey:    nil,
 			Sign:          nil,
@@ -603,37 +891,23 @@ func TransformErrorResponse(msg *protocol.Message, code int32, message string) *
 func (pst *PubSub) handleChatCompletionRequest(ctx context.Context, req *protocol.ChatCompletionRequest, reqHeader *protocol.MessageHeader) (int, string, *protocol.ChatCompletionResponse) {
 	response := &protocol.ChatCompletionResponse{}
 
-	mi, err := model.GetModelInfo(req.GetProject(), req.GetModel(), req.GetCid())
+	mi, err := pst.env.Models.GetModelInfo(req.GetProject(), req.GetModel(), req.GetCid())
 	if err != nil {
 		return int(types.ErrCodeModel), err.Error(), response
 	}
 
-	chatReq := types.ChatModelRequest{
-		Model:  req.GetModel(),
-		Stream: req.GetStream(),
-		WalletVerification: types.WalletVerification{
-			Wallet:    req.GetWallet().GetWallet(),
-			Signature: req.GetWallet().GetSignature(),
-			Hash:      req.GetWallet().GetHash(),
-		},
-		// temperature/top_p is 0 when field is omitempty
-		// Temperature: req.GetTemperature(),
-		// TopP:        req.GetTopP(),
-	}
-	for _, ccm := range req.Messages {
-		chatReq.Messages = append(chatReq.Messages, types.ChatCompletionMessage{
-			Role:    ccm.GetRole(),
-			Content: ccm.GetContent(),
-		})
+	chatReq, err := types.ProtocolMessage2ChatModelRequest(req)
+	if err != nil {
+		return int(types.ErrCodeParam), err.Error(), response
 	}
 
-	model.IncRef(req.GetProject(), req.GetModel(), mi.CID)
-	timer.SendAIProjects(pst.publishChan)
+	pst.env.Models.IncRef(req.GetProject(), req.GetModel(), mi.CID)
+	timer.SendAIProjects(pst.publishChan, pst.env.Config, pst.env.Host, pst.env.Models)
 	defer func() {
-		model.DecRef(req.GetProject(), req.GetModel(), mi.CID)
-		timer.SendAIProjects(pst.publishChan)
+		pst.env.Models.DecRef(req.GetProject(), req.GetModel(), mi.CID)
+		timer.SendAIProjects(pst.publishChan, pst.env.Config, pst.env.Host, pst.env.Models)
 	}()
-	chatRes := model.ChatModel(mi.API, chatReq)
+	chatRes := model.ChatModel(ctx, mi.AIModelConfig, chatReq)
 
 	log.Logger.Infof("Execute model %s in %s result {code:%d, message:%s}", req.GetProject(), req.GetModel(), chatRes.Code, chatRes.Message)
 	modelHistory := &types.ModelHistory{
@@ -648,37 +922,23 @@ func (pst *PubSub) handleChatCompletionRequest(ctx context.Context, req *protoco
 		ChatMessages: chatReq.Messages,
 		ChatChoices:  chatRes.Choices,
 		ChatUsage:    chatRes.Usage,
+		Canceled:     requestCanceled(ctx),
 		ImagePrompt:  "",
 		ImageChoices: []types.ImageResponseChoice{},
 	}
-	_ = db.WriteModelHistory(modelHistory)
+	_ = pst.store.WriteModelHistory(modelHistory)
 
 	if chatRes.Code != 0 {
 		return chatRes.Code, chatRes.Message, response
 	}
-	response.Created = chatRes.Created
-	for _, choice := range chatRes.Choices {
-		response.Choices = append(response.Choices, &protocol.ChatCompletionResponse_ChatResponseChoice{
-			Index: int32(choice.Index),
-			Message: &protocol.ChatCompletionResponseMessage{
-				Role:    choice.Message.Role,
-				Content: choice.Message.Content,
-			},
-			FinishReason: choice.FinishReason,
-		})
-	}
-	response.Usage = &protocol.ChatCompletionResponse_ChatResponseUsage{
-		CompletionTokens: int32(chatRes.Usage.CompletionTokens),
-		PromptTokens:     int32(chatRes.Usage.PromptTokens),
-		TotalTokens:      int32(chatRes.Usage.TotalTokens),
-	}
+	response = types.ChatModelResponse2ProtocolMessage(&chatRes.ChatModelResponseData)
 	return chatRes.Code, chatRes.Message, response
 }
 
 func (pst *PubSub) handleImageGenerationRequest(ctx context.Context, req *protocol.ImageGenerationRequest, reqHeader *protocol.MessageHeader) (int, string, *protocol.ImageGenerationResponse) {
 	response := &protocol.ImageGenerationResponse{}
 
-	mi, err := model.GetModelInfo(req.GetProject(), req.GetModel(), req.GetCid())
+	mi, err := pst.env.Models.GetModelInfo(req.GetProject(), req.GetModel(), req.GetCid())
 	if err != nil {
 		return int(types.ErrCodeModel), err.Error(), response
 	}
@@ -691,6 +951,7 @@ func (pst *PubSub) handleImageGenerationRequest(ctx context.Context, req *protoc
 		Width:          int(req.GetWidth()),
 		Height:         int(req.GetHeight()),
 		ResponseFormat: req.GetResponseFormat(),
+		Seed:           req.Seed,
 		WalletVerification: types.WalletVerification{
 			Wallet:    req.GetWallet().GetWallet(),
 			Signature: req.GetWallet().GetSignature(),
@@ -700,13 +961,13 @@ func (pst *PubSub) handleImageGenerationRequest(ctx context.Context, req *protoc
 		// Step: req.GetStep(),
 	}
 
-	model.IncRef(req.GetProject(), req.GetModel(), mi.CID)
-	timer.SendAIProjects(pst.publishChan)
+	pst.env.Models.IncRef(req.GetProject(), req.GetModel(), mi.CID)
+	timer.SendAIProjects(pst.publishChan, pst.env.Config, pst.env.Host, pst.env.Models)
 	defer func() {
-		model.DecRef(req.GetProject(), req.GetModel(), mi.CID)
-		timer.SendAIProjects(pst.publishChan)
+		pst.env.Models.DecRef(req.GetProject(), req.GetModel(), mi.CID)
+		timer.SendAIProjects(pst.publishChan, pst.env.Config, pst.env.Host, pst.env.Models)
 	}()
-	igRes := model.ImageGenerationModel(mi.API, igReq)
+	igRes := model.ImageGenerationModel(ctx, mi.AIModelConfig, igReq)
 
 	if igRes.Code == 0 {
 		log.Logger.Infof("Execute model %s with (%q, %d, %s) result %v",
@@ -729,12 +990,16 @@ func (pst *PubSub) handleImageGenerationRequest(ctx context.Context, req *protoc
 		ChatUsage:    types.ChatResponseUsage{},
 		ImagePrompt:  req.GetPrompt(),
 		ImageChoices: igRes.Choices,
+		Canceled:     requestCanceled(ctx),
 	}
-	_ = db.WriteModelHistory(modelHistory)
+	_ = pst.store.WriteModelHistory(modelHistory)
 
 	if igRes.Code != 0 {
 		return igRes.Code, igRes.Message, response
 	}
+	if pst.env.Config.App.ImageStore.Enabled {
+		pst.blobs.StoreImages(ctx, reqHeader.GetReceiver(), req.GetResponseFormat(), igRes.Choices)
+	}
 	response.Created = igRes.Created
 	for _, choice := range igRes.Choices {
 		response.Choices = append(response.Choices, &protocol.ImageGenerationResponse_ImageResponseChoice{
@@ -745,3 +1010,200 @@ func (pst *PubSub) handleImageGenerationRequest(ctx context.Context, req *protoc
 	}
 	return igRes.Code, igRes.Message, response
 }
+
+func (pst *PubSub) handleEmbeddingRequest(ctx context.Context, req *protocol.EmbeddingRequest, reqHeader *protocol.MessageHeader) (int, string, *protocol.EmbeddingResponse) {
+	response := &protocol.EmbeddingResponse{}
+
+	mi, err := pst.env.Models.GetModelInfo(req.GetProject(), req.GetModel(), req.GetCid())
+	if err != nil {
+		return int(types.ErrCodeModel), err.Error(), response
+	}
+	if mi.Type != types.ModelTypeEmbedding {
+		return int(types.ErrCodeModel), "Not an embedding model", response
+	}
+
+	ebReq := types.EmbeddingModelRequest{
+		Model:      req.GetModel(),
+		Input:      req.GetInput(),
+		Dimensions: int(req.GetDimensions()),
+		WalletVerification: types.WalletVerification{
+			Wallet:    req.GetWallet().GetWallet(),
+			Signature: req.GetWallet().GetSignature(),
+			Hash:      req.GetWallet().GetHash(),
+		},
+	}
+
+	pst.env.Models.IncRef(req.GetProject(), req.GetModel(), mi.CID)
+	timer.SendAIProjects(pst.publishChan, pst.env.Config, pst.env.Host, pst.env.Models)
+	defer func() {
+		pst.env.Models.DecRef(req.GetProject(), req.GetModel(), mi.CID)
+		timer.SendAIProjects(pst.publishChan, pst.env.Config, pst.env.Host, pst.env.Models)
+	}()
+	ebRes := model.EmbeddingModel(ctx, mi.AIModelConfig, ebReq)
+
+	if ebRes.Code == 0 {
+		log.Logger.Infof("Execute model %s with %d inputs result %d embeddings",
+			req.GetModel(), len(req.GetInput()), len(ebRes.Data))
+	} else {
+		log.Logger.Errorf("Execute model %s with %d inputs error {code:%d, message:%s}",
+			req.GetModel(), len(req.GetInput()), ebRes.Code, ebRes.Message)
+	}
+	modelHistory := &types.ModelHistory{
+		TimeStamp:      time.Now().Unix(),
+		ReqId:          reqHeader.GetId(),
+		ReqNodeId:      reqHeader.GetNodeId(),
+		ResNodeId:      reqHeader.GetReceiver(),
+		Code:           ebRes.Code,
+		Message:        ebRes.Message,
+		Project:        req.GetProject(),
+		Model:          req.GetModel(),
+		ChatMessages:   []types.ChatCompletionMessage{},
+		ChatChoices:    []types.ChatResponseChoice{},
+		ChatUsage:      types.ChatResponseUsage{},
+		ImageChoices:   []types.ImageResponseChoice{},
+		EmbeddingInput: req.GetInput(),
+		Canceled:       requestCanceled(ctx),
+	}
+	_ = pst.store.WriteModelHistory(modelHistory)
+
+	if ebRes.Code != 0 {
+		return ebRes.Code, ebRes.Message, response
+	}
+	response.Created = time.Now().Unix()
+	response.Model = ebRes.Model
+	for _, data := range ebRes.Data {
+		response.Data = append(response.Data, &protocol.EmbeddingResponse_Embedding{
+			Index:     int32(data.Index),
+			Embedding: data.Embedding,
+		})
+	}
+	response.PromptTokens = int32(ebRes.Usage.PromptTokens)
+	response.TotalTokens = int32(ebRes.Usage.TotalTokens)
+	return ebRes.Code, ebRes.Message, response
+}
+
+// audioModelInfo finds the model of the audio request and checks its type.
+func (pst *PubSub) audioModelInfo(project, modelName, cid string, modelType int) (*types.ModelIdle, int, string) {
+	mi, err := pst.env.Models.GetModelInfo(project, modelName, cid)
+	if err != nil {
+		return nil, int(types.ErrCodeModel), err.Error()
+	}
+	if mi.Type != modelType {
+		return nil, int(types.ErrCodeModel), "Model type does not match the audio request"
+	}
+	return mi, 0, ""
+}
+
+func (pst *PubSub) handleAudioTranscriptionRequest(ctx context.Context, req *protocol.AudioTranscriptionRequest, reqHeader *protocol.MessageHeader) (int, string, *protocol.AudioTranscriptionResponse) {
+	response := &protocol.AudioTranscriptionResponse{}
+
+	mi, code, message := pst.audioModelInfo(req.GetProject(), req.GetModel(), req.GetCid(), types.ModelTypeAudioTranscription)
+	if code != 0 {
+		return code, message, response
+	}
+	fields := req.GetFields()
+	if fields == nil {
+		fields = map[string]string{}
+	}
+	if fields["model"] == "" {
+		fields["model"] = req.GetModel()
+	}
+	body, contentType, err := model.TranscriptionBody(req.GetFile(), req.GetFilename(), fields)
+	if err != nil {
+		return int(types.ErrCodeModel), err.Error(), response
+	}
+
+	pst.env.Models.IncRef(req.GetProject(), req.GetModel(), mi.CID)
+	timer.SendAIProjects(pst.publishChan, pst.env.Config, pst.env.Host, pst.env.Models)
+	defer func() {
+		pst.env.Models.DecRef(req.GetProject(), req.GetModel(), mi.CID)
+		timer.SendAIProjects(pst.publishChan, pst.env.Config, pst.env.Host, pst.env.Models)
+	}()
+	ctx, cancel := context.WithTimeout(ctx, types.AudioRequestTimeout)
+	defer cancel()
+	res := &types.AudioResponse{}
+	resp, err := model.AudioTranscriptionModel(ctx, mi.AIModelConfig, body, contentType)
+	if err != nil {
+		res.Code = int(types.ErrCodeModel)
+		res.Message = fmt.Sprintf("Post HTTP request error, %v", err)
+	} else {
+		res = model.ReadAudioResponse(resp)
+	}
+	log.Logger.Infof("Execute model %s with %s of %d bytes result {code:%d, message:%s}",
+		req.GetModel(), req.GetFilename(), len(req.GetFile()), res.Code, res.Message)
+	pst.writeAudioHistory(ctx, req.GetProject(), req.GetModel(), reqHeader, res)
+
+	if res.Code != 0 {
+		return res.Code, res.Message, response
+	}
+	response.Data = res.Data
+	response.ContentType = res.ContentType
+	return 0, "", response
+}
+
+func (pst *PubSub) handleAudioSpeechRequest(ctx context.Context, req *protocol.AudioSpeechRequest, reqHeader *protocol.MessageHeader) (int, string, *protocol.AudioSpeechResponse) {
+	response := &protocol.AudioSpeechResponse{}
+
+	mi, code, message := pst.audioModelInfo(req.GetProject(), req.GetModel(), req.GetCid(), types.ModelTypeAudioSpeech)
+	if code != 0 {
+		return code, message, response
+	}
+	asReq := types.AudioSpeechModelRequest{
+		Model:          req.GetModel(),
+		Input:          req.GetInput(),
+		Voice:          req.GetVoice(),
+		ResponseFormat: req.GetResponseFormat(),
+		Speed:          req.GetSpeed(),
+		WalletVerification: types.WalletVerification{
+			Wallet:    req.GetWallet().GetWallet(),
+			Signature: req.GetWallet().GetSignature(),
+			Hash:      req.GetWallet().GetHash(),
+		},
+	}
+
+	pst.env.Models.IncRef(req.GetProject(), req.GetModel(), mi.CID)
+	timer.SendAIProjects(pst.publishChan, pst.env.Config, pst.env.Host, pst.env.Models)
+	defer func() {
+		pst.env.Models.DecRef(req.GetProject(), req.GetModel(), mi.CID)
+		timer.SendAIProjects(pst.publishChan, pst.env.Config, pst.env.Host, pst.env.Models)
+	}()
+	ctx, cancel := context.WithTimeout(ctx, types.AudioRequestTimeout)
+	defer cancel()
+	res := &types.AudioResponse{}
+	resp, err := model.AudioSpeechModel(ctx, mi.AIModelConfig, asReq)
+	if err != nil {
+		res.Code = int(types.ErrCodeModel)
+		res.Message = fmt.Sprintf("Post HTTP request error, %v", err)
+	} else {
+		res = model.ReadAudioResponse(resp)
+	}
+	log.Logger.Infof("Execute model %s with %d characters result {code:%d, message:%s}",
+		req.GetModel(), len(req.GetInput()), res.Code, res.Message)
+	pst.writeAudioHistory(ctx, req.GetProject(), req.GetModel(), reqHeader, res)
+
+	if res.Code != 0 {
+		return res.Code, res.Message, response
+	}
+	response.Audio = res.Data
+	response.ContentType = res.ContentType
+	return 0, "", response
+}
+
+func (pst *PubSub) writeAudioHistory(ctx context.Context, project, modelName string, reqHeader *protocol.MessageHeader, res *types.AudioResponse) {
+	modelHistory := &types.ModelHistory{
+		TimeStamp:    time.Now().Unix(),
+		ReqId:        reqHeader.GetId(),
+		ReqNodeId:    reqHeader.GetNodeId(),
+		ResNodeId:    reqHeader.GetReceiver(),
+		Code:         res.Code,
+		Message:      res.Message,
+		Project:      project,
+		Model:        modelName,
+		ChatMessages: []types.ChatCompletionMessage{},
+		ChatChoices:  []types.ChatResponseChoice{},
+		ChatUsage:    types.ChatResponseUsage{},
+		ImageChoices: []types.ImageResponseChoice{},
+		Canceled:     requestCanceled(ctx),
+	}
+	_ = pst.store.WriteModelHistory(modelHistory)
+}
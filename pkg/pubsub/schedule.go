@@ -0,0 +1,32 @@
+package ps
+
+import (
+	"context"
+	"errors"
+
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/protocol"
+	"AIComputingNode/pkg/scheduler"
+	"AIComputingNode/pkg/types"
+)
+
+// schedule waits for the turn of the model request in the scheduler of the worker, and returns
+// the function to call when the request is done. It returns a nil function with the error
+// response, or with code 0 if the request is canceled by the sending node while queued.
+func (pst *PubSub) schedule(ctx context.Context, msg *protocol.Message) (func(), int, string) {
+	release, err := pst.scheduler.Acquire(ctx, scheduler.Request{
+		ID:        msg.Header.GetId(),
+		Type:      msg.Type.String(),
+		Requester: msg.Header.GetNodeId(),
+	})
+	switch {
+	case err == nil:
+		return release, 0, ""
+	case errors.Is(err, scheduler.ErrBusy), errors.Is(err, scheduler.ErrPreempted):
+		log.Logger.Warnf("Drop %s request %s from %s %v", msg.Type.String(), msg.Header.GetId(), msg.Header.GetNodeId(), err)
+		return nil, int(types.ErrCodeBusy), err.Error()
+	default:
+		log.Logger.Warnf("Queued %s request %s from %s stopped %v", msg.Type.String(), msg.Header.GetId(), msg.Header.GetNodeId(), err)
+		return nil, 0, ""
+	}
+}
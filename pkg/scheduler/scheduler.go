@@ -0,0 +1,282 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/types"
+)
+
+var (
+	ErrBusy      = errors.New("too many queued requests")
+	ErrPreempted = errors.New("preempted by a request of a higher priority or a fairer share")
+)
+
+const defaultClassName = "default"
+
+type class struct {
+	name     string
+	priority int
+	weight   int
+}
+
+// Request is a model request received by the worker.
+type Request struct {
+	ID   string
+	Type string
+	// Peer id of the requesting node, which is authenticated by libp2p
+	Requester string
+}
+
+type item struct {
+	Request
+	class *class
+	// virtual start and finish tags of the weighted fair queuing
+	start    float64
+	finish   float64
+	seq      uint64
+	queuedAt time.Time
+	// receives nil when the request runs, or the error dropping it from the queue
+	ready chan error
+}
+
+// requesterKey keys the last finish tags of the requesters, every priority has its own virtual time.
+type requesterKey struct {
+	priority  int
+	requester string
+}
+
+// Scheduler runs a limited number of model requests at the same time and queues the others.
+// The queued requests of a higher priority class run first, the requesters of the same priority
+// share the worker by the weights of their classes. When the queue is full, a new request
+// preempts the queued request which would run last if it runs before it, running requests are
+// never preempted.
+type Scheduler struct {
+	maxRunning   int
+	maxQueued    int
+	defaultClass *class
+	peers        map[string]*class
+
+	mutex      sync.Mutex
+	running    map[*item]struct{}
+	queued     []*item
+	seq        uint64
+	vtime      map[int]float64
+	lastFinish map[requesterKey]float64
+	preempted  uint64
+	rejected   uint64
+}
+
+func New(cfg config.AppSchedulerConfig) *Scheduler {
+	s := &Scheduler{
+		maxRunning:   cfg.MaxRunning,
+		maxQueued:    cfg.MaxQueued,
+		defaultClass: &class{name: defaultClassName, weight: 1},
+		peers:        make(map[string]*class),
+		running:      make(map[*item]struct{}),
+		vtime:        make(map[int]float64),
+		lastFinish:   make(map[requesterKey]float64),
+	}
+	for _, cc := range cfg.Classes {
+		c := &class{name: cc.Name, priority: cc.Priority, weight: cc.Weight}
+		for _, id := range cc.Peers {
+			if old := s.peers[id]; old == nil || old.priority < c.priority {
+				s.peers[id] = c
+			}
+		}
+	}
+	return s
+}
+
+// classOf returns the class of the highest priority matching the requester.
+func (s *Scheduler) classOf(req Request) *class {
+	if pc := s.peers[req.Requester]; pc != nil && pc.priority > s.defaultClass.priority {
+		return pc
+	}
+	return s.defaultClass
+}
+
+// before reports whether the queued request a runs before b.
+func before(a, b *item) bool {
+	if a.class.priority != b.class.priority {
+		return a.class.priority > b.class.priority
+	}
+	if a.finish != b.finish {
+		return a.finish < b.finish
+	}
+	return a.seq < b.seq
+}
+
+// Acquire waits until the request can run, and returns the function to call when it is done.
+// It returns ErrBusy if the queue is full, ErrPreempted if the request is dropped from the queue,
+// or the error of the context if the context is done before the request runs.
+func (s *Scheduler) Acquire(ctx context.Context, req Request) (func(), error) {
+	s.mutex.Lock()
+	c := s.classOf(req)
+	key := requesterKey{priority: c.priority, requester: req.Requester}
+	start := s.vtime[c.priority]
+	if last := s.lastFinish[key]; last > start {
+		start = last
+	}
+	s.seq++
+	it := &item{
+		Request:  req,
+		class:    c,
+		start:    start,
+		finish:   start + 1/float64(c.weight),
+		seq:      s.seq,
+		queuedAt: time.Now(),
+		ready:    make(chan error, 1),
+	}
+
+	if len(s.running) >= s.maxRunning && len(s.queued) >= s.maxQueued {
+		last := -1
+		for i, q := range s.queued {
+			if last < 0 || before(s.queued[last], q) {
+				last = i
+			}
+		}
+		if last < 0 || !before(it, s.queued[last]) {
+			s.rejected++
+			s.mutex.Unlock()
+			return nil, ErrBusy
+		}
+		victim := s.queued[last]
+		s.drop(last)
+		victim.ready <- ErrPreempted
+		s.preempted++
+	}
+	s.lastFinish[key] = it.finish
+	s.queued = append(s.queued, it)
+	s.dispatch()
+	s.mutex.Unlock()
+
+	select {
+	case err := <-it.ready:
+		if err != nil {
+			return nil, err
+		}
+		return s.releaseFunc(it), nil
+	case <-ctx.Done():
+		s.mutex.Lock()
+		for i, q := range s.queued {
+			if q == it {
+				s.drop(i)
+				s.mutex.Unlock()
+				return nil, ctx.Err()
+			}
+		}
+		s.mutex.Unlock()
+		// the request is run or preempted at the same time
+		if err := <-it.ready; err == nil {
+			s.releaseFunc(it)()
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// drop removes the queued request which does not run, and gives its share back to the requester,
+// it is called with the mutex locked.
+func (s *Scheduler) drop(i int) {
+	it := s.queued[i]
+	s.queued = append(s.queued[:i], s.queued[i+1:]...)
+	key := requesterKey{priority: it.class.priority, requester: it.Requester}
+	if s.lastFinish[key] != it.finish {
+		// a later request of the requester is queued after it
+		return
+	}
+	last := it.start
+	for _, q := range s.queued {
+		if q.class.priority == key.priority && q.Requester == key.requester && q.finish > last {
+			last = q.finish
+		}
+	}
+	s.lastFinish[key] = last
+}
+
+func (s *Scheduler) releaseFunc(it *item) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mutex.Lock()
+			delete(s.running, it)
+			s.dispatch()
+			s.mutex.Unlock()
+		})
+	}
+}
+
+// dispatch runs the queued requests in order until the running requests reach the limit,
+// it is called with the mutex locked.
+func (s *Scheduler) dispatch() {
+	for len(s.running) < s.maxRunning && len(s.queued) > 0 {
+		next := 0
+		for i, q := range s.queued {
+			if before(q, s.queued[next]) {
+				next = i
+			}
+		}
+		it := s.queued[next]
+		s.queued = append(s.queued[:next], s.queued[next+1:]...)
+		if it.start > s.vtime[it.class.priority] {
+			s.vtime[it.class.priority] = it.start
+		}
+		s.running[it] = struct{}{}
+		it.ready <- nil
+	}
+	// the finish tags behind the virtual time no longer change the order
+	for key, finish := range s.lastFinish {
+		if finish <= s.vtime[key.priority] {
+			delete(s.lastFinish, key)
+		}
+	}
+}
+
+func (it *item) state() types.SchedulerRequest {
+	return types.SchedulerRequest{
+		ID:        it.ID,
+		Type:      it.Type,
+		Requester: it.Requester,
+		Class:     it.class.name,
+		Priority:  it.class.priority,
+		QueuedAt:  it.queuedAt.Unix(),
+	}
+}
+
+// State returns the running requests and the queued requests in the order they run.
+func (s *Scheduler) State() types.SchedulerState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	running := make([]*item, 0, len(s.running))
+	for it := range s.running {
+		running = append(running, it)
+	}
+	sort.Slice(running, func(i, j int) bool {
+		return running[i].seq < running[j].seq
+	})
+	queued := append([]*item(nil), s.queued...)
+	sort.Slice(queued, func(i, j int) bool {
+		return before(queued[i], queued[j])
+	})
+
+	state := types.SchedulerState{
+		MaxRunning: s.maxRunning,
+		MaxQueued:  s.maxQueued,
+		Running:    make([]types.SchedulerRequest, 0, len(running)),
+		Queued:     make([]types.SchedulerRequest, 0, len(queued)),
+		Preempted:  s.preempted,
+		Rejected:   s.rejected,
+	}
+	for _, it := range running {
+		state.Running = append(state.Running, it.state())
+	}
+	for _, it := range queued {
+		state.Queued = append(state.Queued, it.state())
+	}
+	return state
+}
@@ -0,0 +1,150 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/config"
+)
+
+const (
+	noisyPeer = "16Uiu2HAmS4CErxrmPryJbbEX2HFQbLK8r8xCA5rmzdSU59rHc9AF"
+	quietPeer = "16Uiu2HAmRTpigc7jAbsLndB2xDEBMAXLb887SBEFhfdJeEJNtqRM"
+	otherPeer = "16Uiu2HAmJ9Hgw6NuF9MAqtZ6sTsTSm2mDy4YkW4S6E3Y9Nh8Qbqo"
+)
+
+// queueRequests holds the only running slot, queues the requests one by one, then releases the slot
+// and returns the ids of the requests in the order they run, or the errors dropping them.
+func queueRequests(t *testing.T, s *Scheduler, reqs []Request) []string {
+	release, err := s.Acquire(context.Background(), Request{ID: "blocker", Requester: otherPeer})
+	if err != nil {
+		t.Fatalf("Acquire blocker failed %v", err)
+	}
+
+	var mutex sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func(req Request) {
+			defer wg.Done()
+			done, err := s.Acquire(context.Background(), req)
+			mutex.Lock()
+			defer mutex.Unlock()
+			if err != nil {
+				order = append(order, req.ID+":"+err.Error())
+				return
+			}
+			order = append(order, req.ID)
+			done()
+		}(req)
+		// wait for the request to be queued or rejected, so that the requests keep their order
+		for deadline := time.Now().Add(time.Second); acquired(s) < uint64(i)+2; {
+			if time.Now().After(deadline) {
+				t.Fatalf("Request %s not queued", req.ID)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+	mutex.Lock()
+	dropped := len(order)
+	mutex.Unlock()
+	if dropped > 0 {
+		// the dropped requests return before the others run
+		time.Sleep(10 * time.Millisecond)
+	}
+	release()
+	wg.Wait()
+	return order
+}
+
+// acquired returns the number of the requests passed to Acquire
+func acquired(s *Scheduler) uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.seq
+}
+
+func TestSchedulerFairQueuing(t *testing.T) {
+	s := New(config.AppSchedulerConfig{MaxRunning: 1, MaxQueued: 10})
+	order := queueRequests(t, s, []Request{
+		{ID: "noisy1", Requester: noisyPeer},
+		{ID: "noisy2", Requester: noisyPeer},
+		{ID: "noisy3", Requester: noisyPeer},
+		{ID: "quiet1", Requester: quietPeer},
+	})
+	expected := []string{"noisy1", "quiet1", "noisy2", "noisy3"}
+	if len(order) != len(expected) {
+		t.Fatalf("Unexpected order %v", order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected order %v but got %v", expected, order)
+		}
+	}
+}
+
+func TestSchedulerPriority(t *testing.T) {
+	s := New(config.AppSchedulerConfig{
+		MaxRunning: 1,
+		MaxQueued:  2,
+		Classes: []config.AppSchedulerClassConfig{
+			{Name: "gold", Priority: 10, Weight: 1, Peers: []string{quietPeer}},
+		},
+	})
+	order := queueRequests(t, s, []Request{
+		{ID: "noisy1", Requester: noisyPeer},
+		{ID: "noisy2", Requester: noisyPeer},
+		{ID: "gold", Requester: quietPeer},
+		{ID: "noisy3", Requester: noisyPeer},
+	})
+	expected := []string{
+		"noisy2:" + ErrPreempted.Error(),
+		"noisy3:" + ErrBusy.Error(),
+		"gold",
+		"noisy1",
+	}
+	if len(order) != len(expected) {
+		t.Fatalf("Unexpected order %v", order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected order %v but got %v", expected, order)
+		}
+	}
+	if state := s.State(); state.Preempted != 1 || state.Rejected != 1 || len(state.Running) != 0 || len(state.Queued) != 0 {
+		t.Errorf("Unexpected state %+v", state)
+	}
+	// the finish tag of the preempted request is given back
+	if finish := s.lastFinish[requesterKey{requester: noisyPeer}]; finish != 1 {
+		t.Errorf("Unexpected finish tag %v of the preempted request", finish)
+	}
+}
+
+func TestSchedulerCancelQueued(t *testing.T) {
+	s := New(config.AppSchedulerConfig{MaxRunning: 1, MaxQueued: 1})
+	release, err := s.Acquire(context.Background(), Request{ID: "running", Requester: noisyPeer})
+	if err != nil {
+		t.Fatalf("Acquire failed %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := s.Acquire(ctx, Request{ID: "queued", Requester: quietPeer}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected deadline exceeded but got %v", err)
+	}
+	if state := s.State(); len(state.Running) != 1 || len(state.Queued) != 0 {
+		t.Fatalf("Unexpected state %+v", state)
+	}
+	// the canceled request does not delay the next requests of the requester
+	if finish := s.lastFinish[requesterKey{requester: quietPeer}]; finish != 0 {
+		t.Fatalf("Unexpected finish tag %v of the canceled request", finish)
+	}
+	release()
+	release()
+	if state := s.State(); len(state.Running) != 0 {
+		t.Fatalf("Unexpected state %+v", state)
+	}
+}
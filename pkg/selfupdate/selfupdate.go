@@ -14,7 +14,7 @@ import (
 	"AIComputingNode/pkg/model"
 )
 
-func UpdateGithubLatestRelease(ctx context.Context, cur_version string, activeReqs *int32) {
+func UpdateGithubLatestRelease(ctx context.Context, cur_version string, activeReqs *int32, models *model.ProjectMap) {
 	// 1. Detect github latest release
 	glr, err := DetectLatestGithubRelease(ctx, 15*time.Second)
 	if err != nil {
@@ -129,7 +129,7 @@ func UpdateGithubLatestRelease(ctx context.Context, cur_version string, activeRe
 
 	// 4. Automatic restart during idle time
 	activeHttpReqs := atomic.LoadInt32(activeReqs)
-	activeModelReqs := model.IdleCount()
+	activeModelReqs := models.IdleCount()
 	log.Logger.Infof(
 		"Currently active http requests %v, model idle count %v",
 		activeHttpReqs,
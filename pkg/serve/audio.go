@@ -0,0 +1,523 @@
+package serve
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"sort"
+	"time"
+
+	"AIComputingNode/pkg/db"
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/model"
+	"AIComputingNode/pkg/protocol"
+	"AIComputingNode/pkg/timer"
+	"AIComputingNode/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+// The audio requests are sent through a libp2p stream when the node can reach the worker directly,
+// so that the audio is streamed back while it is generated. Otherwise they are sent in the pubsub
+// messages, which carry the whole audio file and response.
+
+// audioModelInfo finds the local model of the audio request and checks its type.
+func audioModelInfo(env *Env, project, modelName, cid string, modelType int) (*types.ModelIdle, int, int, string) {
+	mi, err := env.Models.GetModelInfo(project, modelName, cid)
+	if err != nil {
+		return nil, http.StatusInternalServerError, int(types.ErrCodeModel), err.Error()
+	}
+	if mi.Type != modelType {
+		return nil, http.StatusBadRequest, int(types.ErrCodeModel), "Model type does not match the audio request"
+	}
+	return mi, http.StatusOK, 0, ""
+}
+
+// copyAudioResponse copies the model response to the client, every chunk is flushed
+// so that the audio can be played before it is completely generated.
+func copyAudioResponse(w http.ResponseWriter, resp *http.Response) {
+	defer resp.Body.Close()
+	for k, v := range resp.Header {
+		for _, s := range v {
+			w.Header().Set(k, s)
+		}
+	}
+	w.WriteHeader(resp.StatusCode)
+
+	flusher, _ := w.(http.Flusher)
+	buf := make([]byte, 32<<10)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				log.Logger.Warnf("Write audio response failed: %v", err)
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if err != nil {
+			if err != io.EOF {
+				log.Logger.Warnf("Read audio response failed: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// writeAudioResponse writes the model response received in the pubsub messages.
+func writeAudioResponse(w http.ResponseWriter, rsp *types.AudioResponse) (int, int, string) {
+	if rsp.Code != 0 {
+		return http.StatusInternalServerError, rsp.Code, rsp.Message
+	}
+	w.Header().Set("Content-Type", rsp.ContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write(rsp.Data)
+	return http.StatusOK, 0, ""
+}
+
+// newAudioStreamRequest creates the HTTP request of the worker API written into the libp2p stream.
+func newAudioStreamRequest(ctx context.Context, path string, body io.Reader, contentType, project, modelName, cid string) (*http.Request, error) {
+	hreq, err := http.NewRequestWithContext(ctx, "POST", "http://127.0.0.1:8080"+path, body)
+	if err != nil {
+		return nil, err
+	}
+	hreq.Header.Set("Content-Type", contentType)
+
+	queryValues := hreq.URL.Query()
+	queryValues.Add("project", project)
+	queryValues.Add("model", modelName)
+	queryValues.Add("cid", cid)
+	hreq.URL.RawQuery = queryValues.Encode()
+	return hreq, nil
+}
+
+// streamAudioRequest sends the request to the worker through a libp2p stream and
+// copies the audio response to the client.
+func streamAudioRequest(ctx context.Context, env *Env, w http.ResponseWriter, nodeID string, hreq *http.Request) (int, int, string) {
+	stream, err := env.Host.NewStream(ctx, nodeID)
+	if err != nil {
+		log.Logger.Errorf("Open stream with peer node failed: %v", err)
+		return http.StatusInternalServerError, int(types.ErrCodeStream), "Open stream with peer node failed"
+	}
+	stream.SetDeadline(time.Now().Add(types.AudioRequestTimeout))
+	defer stream.Close()
+	defer resetOnCancel(ctx, stream)()
+	log.Logger.Infof("Create libp2p stream with %s success", nodeID)
+
+	if err := hreq.Write(stream); err != nil {
+		stream.Reset()
+		log.Logger.Errorf("Write audio request into libp2p stream failed: %v", err)
+		return http.StatusInternalServerError, int(types.ErrCodeStream), "Write audio request into libp2p stream failed"
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(stream), hreq)
+	if err != nil {
+		stream.Reset()
+		log.Logger.Errorf("Read audio response from libp2p stream failed: %v", err)
+		return http.StatusInternalServerError, int(types.ErrCodeStream), "Read audio response from libp2p stream failed"
+	}
+	copyAudioResponse(w, resp)
+	log.Logger.Info("Handle audio stream request over")
+	return http.StatusOK, 0, ""
+}
+
+func newAudioMessage(ctx context.Context, env *Env, nodeID string, msgType protocol.MessageType, body []byte) (*protocol.Message, int, int, string) {
+	requestID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, http.StatusInternalServerError, int(types.ErrCodeUUID), err.Error()
+	}
+	msg := &protocol.Message{
+		Header: &protocol.MessageHeader{
+			ClientVersion: env.Host.UserAgent,
+			Timestamp:     time.Now().Unix(),
+			Id:            requestID.String(),
+			NodeId:        env.Config.Identity.PeerID,
+			Receiver:      nodeID,
+		},
+		Type:       msgType,
+		Body:       body,
+		ResultCode: 0,
+	}
+	if err := env.Host.EncryptMessage(ctx, msg); err != nil {
+		return nil, http.StatusInternalServerError, int(types.ErrCodeEncrypt), types.ErrCodeEncrypt.String()
+	}
+	return msg, http.StatusOK, 0, ""
+}
+
+func handleAudioTranscriptionRequest(ctx context.Context, env *Env, publishChan chan<- []byte, w http.ResponseWriter, form *multipart.Form, req types.AudioTranscriptionRequest) (int, int, string) {
+	if len(form.Value["model"]) == 0 {
+		// the model field is required by the OpenAI compatible servers
+		form.Value["model"] = []string{req.Model}
+	}
+
+	if req.NodeID == env.Config.Identity.PeerID {
+		mi, status, code, message := audioModelInfo(env, req.Project, req.Model, req.CID, types.ModelTypeAudioTranscription)
+		if code != 0 {
+			return status, code, message
+		}
+		body, contentType, err := model.MultipartBody(form)
+		if err != nil {
+			return http.StatusInternalServerError, int(types.ErrCodeModel), err.Error()
+		}
+		env.Models.IncRef(req.Project, req.Model, mi.CID)
+		timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
+		defer func() {
+			env.Models.DecRef(req.Project, req.Model, mi.CID)
+			timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
+		}()
+		ctx, cancel := context.WithTimeout(ctx, types.AudioRequestTimeout)
+		defer cancel()
+		resp, err := model.AudioTranscriptionModel(ctx, mi.AIModelConfig, body, contentType)
+		if err != nil {
+			log.Logger.Errorf("RoundTrip audio transcription request failed: %v", err)
+			return http.StatusInternalServerError, int(types.ErrCodeModel), err.Error()
+		}
+		copyAudioResponse(w, resp)
+		return http.StatusOK, 0, ""
+	}
+
+	if env.Host.CanStream(req.NodeID) {
+		ctx, cancel := context.WithTimeout(ctx, types.AudioRequestTimeout)
+		defer cancel()
+		body, contentType, err := model.MultipartBody(form)
+		if err != nil {
+			return http.StatusInternalServerError, int(types.ErrCodeModel), err.Error()
+		}
+		hreq, err := newAudioStreamRequest(ctx, "/api/v0/audio/transcriptions", body, contentType, req.Project, req.Model, req.CID)
+		if err != nil {
+			log.Logger.Errorf("Create http request for stream failed: %v", err)
+			return http.StatusInternalServerError, int(types.ErrCodeStream), "Create http request for stream failed"
+		}
+		return streamAudioRequest(ctx, env, w, req.NodeID, hreq)
+	}
+
+	fileHeader := form.File["file"][0]
+	file, err := fileHeader.Open()
+	if err != nil {
+		return http.StatusInternalServerError, int(types.ErrCodeParam), err.Error()
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		return http.StatusInternalServerError, int(types.ErrCodeParam), err.Error()
+	}
+	fields := make(map[string]string, len(form.Value))
+	for name, values := range form.Value {
+		fields[name] = values[0]
+	}
+	body, err := proto.Marshal(&protocol.AudioTranscriptionBody{
+		Data: &protocol.AudioTranscriptionBody_Req{
+			Req: &protocol.AudioTranscriptionRequest{
+				Project:  req.Project,
+				Model:    req.Model,
+				Cid:      req.CID,
+				File:     data,
+				Filename: fileHeader.Filename,
+				Fields:   fields,
+			},
+		},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, int(types.ErrCodeProtobuf), err.Error()
+	}
+	msg, status, code, message := newAudioMessage(ctx, env, req.NodeID, protocol.MessageType_AUDIO_TRANSCRIPTION, body)
+	if code != 0 {
+		return status, code, message
+	}
+	rsp := types.AudioResponse{}
+	if status, code, message := handleRequest(ctx, env, publishChan, msg, &rsp, types.AudioRequestTimeout); code != 0 {
+		return status, code, message
+	}
+	return writeAudioResponse(w, &rsp)
+}
+
+func handleAudioSpeechRequest(ctx context.Context, env *Env, publishChan chan<- []byte, w http.ResponseWriter, req types.AudioSpeechRequest) (int, int, string) {
+	if req.NodeID == env.Config.Identity.PeerID {
+		mi, status, code, message := audioModelInfo(env, req.Project, req.Model, req.CID, types.ModelTypeAudioSpeech)
+		if code != 0 {
+			return status, code, message
+		}
+		env.Models.IncRef(req.Project, req.Model, mi.CID)
+		timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
+		defer func() {
+			env.Models.DecRef(req.Project, req.Model, mi.CID)
+			timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
+		}()
+		ctx, cancel := context.WithTimeout(ctx, types.AudioRequestTimeout)
+		defer cancel()
+		resp, err := model.AudioSpeechModel(ctx, mi.AIModelConfig, req.AudioSpeechModelRequest)
+		if err != nil {
+			log.Logger.Errorf("RoundTrip audio speech request failed: %v", err)
+			return http.StatusInternalServerError, int(types.ErrCodeModel), err.Error()
+		}
+		copyAudioResponse(w, resp)
+		return http.StatusOK, 0, ""
+	}
+
+	if env.Host.CanStream(req.NodeID) {
+		ctx, cancel := context.WithTimeout(ctx, types.AudioRequestTimeout)
+		defer cancel()
+		jsonData, err := json.Marshal(req.AudioSpeechModelRequest)
+		if err != nil {
+			log.Logger.Errorf("Marshal model request body failed: %v", err)
+			return http.StatusInternalServerError, int(types.ErrCodeStream), "Marshal model request body failed"
+		}
+		hreq, err := newAudioStreamRequest(ctx, "/api/v0/audio/speech", bytes.NewReader(jsonData), "application/json", req.Project, req.Model, req.CID)
+		if err != nil {
+			log.Logger.Errorf("Create http request for stream failed: %v", err)
+			return http.StatusInternalServerError, int(types.ErrCodeStream), "Create http request for stream failed"
+		}
+		return streamAudioRequest(ctx, env, w, req.NodeID, hreq)
+	}
+
+	body, err := proto.Marshal(&protocol.AudioSpeechBody{
+		Data: &protocol.AudioSpeechBody_Req{
+			Req: &protocol.AudioSpeechRequest{
+				Project:        req.Project,
+				Model:          req.Model,
+				Cid:            req.CID,
+				Input:          req.Input,
+				Voice:          req.Voice,
+				ResponseFormat: req.ResponseFormat,
+				Speed:          req.Speed,
+				Wallet: &protocol.WalletVerification{
+					Wallet:    req.Wallet,
+					Signature: req.Signature,
+					Hash:      req.Hash,
+				},
+			},
+		},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, int(types.ErrCodeProtobuf), err.Error()
+	}
+	msg, status, code, message := newAudioMessage(ctx, env, req.NodeID, protocol.MessageType_AUDIO_SPEECH, body)
+	if code != 0 {
+		return status, code, message
+	}
+	rsp := types.AudioResponse{}
+	if status, code, message := handleRequest(ctx, env, publishChan, msg, &rsp, types.AudioRequestTimeout); code != 0 {
+		return status, code, message
+	}
+	return writeAudioResponse(w, &rsp)
+}
+
+// audioForm parses the multipart form of the transcription request, which must upload the audio file.
+func audioForm(c *gin.Context) (*multipart.Form, error) {
+	// leave some room for the other fields
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, types.MaxAudioFileSize+1<<20)
+	form, err := c.MultipartForm()
+	if err != nil {
+		return nil, err
+	}
+	if len(form.File["file"]) == 0 {
+		return nil, errors.New("empty audio file")
+	}
+	if form.File["file"][0].Size > types.MaxAudioFileSize {
+		return nil, errors.New("audio file is too large")
+	}
+	return form, nil
+}
+
+// audioPeer selects the node of the proxy request among the nodes running the model of the audio type.
+func audioPeer(env *Env, store db.Store, project, modelName string, modelType int) (types.AIProjectPeerInfo, int, string) {
+	ids, code := store.GetPeersOfAIProjects(project, modelName, 20)
+	if code != 0 {
+		return types.AIProjectPeerInfo{}, int(types.ErrCodeProxy), types.ErrorCode(code).String()
+	}
+
+	peers := []types.AIProjectPeerInfo{}
+	for id, mi := range ids {
+		if mi.Type != modelType {
+			continue
+		}
+		peers = append(peers, types.AIProjectPeerInfo{
+			NodeID:       id,
+			Connectivity: env.Host.Connectedness(id),
+			Latency:      env.Host.StreamLatency(id).Nanoseconds(),
+			Idle:         mi.Idle,
+			CID:          mi.CID,
+			Type:         mi.Type,
+		})
+	}
+	if len(peers) == 0 {
+		return types.AIProjectPeerInfo{}, int(types.ErrCodeProxy), "Not enough available and connected nodes"
+	}
+
+	sort.Sort(types.AIProjectPeerOrder(peers))
+	return peers[0], 0, ""
+}
+
+func AudioTranscriptionHandler(c *gin.Context, env *Env, publishChan chan<- []byte) {
+	rsp := types.BaseHttpResponse{}
+
+	var msg types.AudioTranscriptionRequest
+	if err := c.ShouldBindQuery(&msg); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if err := msg.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	form, err := audioForm(c)
+	if err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusUnprocessableEntity, rsp)
+		return
+	}
+	status, code, message := handleAudioTranscriptionRequest(c.Request.Context(), env, publishChan, c.Writer, form, msg)
+	if code != 0 {
+		c.JSON(status, types.BaseHttpResponse{
+			Code:    code,
+			Message: message,
+		})
+	}
+}
+
+func AudioTranscriptionProxyHandler(c *gin.Context, env *Env, publishChan chan<- []byte, store db.Store) {
+	rsp := types.BaseHttpResponse{}
+
+	if !env.Config.App.PeersCollect.Enabled {
+		rsp.Code = int(types.ErrCodeUnsupported)
+		rsp.Message = types.ErrCodeUnsupported.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	var msg types.AudioTranscriptionProxyRequest
+	if err := c.ShouldBindQuery(&msg); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if err := msg.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	form, err := audioForm(c)
+	if err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusUnprocessableEntity, rsp)
+		return
+	}
+
+	peer, code, message := audioPeer(env, store, msg.Project, msg.Model, types.ModelTypeAudioTranscription)
+	if code != 0 {
+		rsp.Code = code
+		rsp.Message = message
+		c.JSON(http.StatusInternalServerError, rsp)
+		return
+	}
+
+	atReq := types.AudioTranscriptionRequest{
+		NodeID:  peer.NodeID,
+		Project: msg.Project,
+		Model:   msg.Model,
+		CID:     peer.CID,
+	}
+	status, code, message := handleAudioTranscriptionRequest(c.Request.Context(), env, publishChan, c.Writer, form, atReq)
+	if code != 0 {
+		c.JSON(status, types.BaseHttpResponse{
+			Code:    code,
+			Message: message,
+		})
+	}
+}
+
+func AudioSpeechHandler(c *gin.Context, env *Env, publishChan chan<- []byte) {
+	rsp := types.BaseHttpResponse{}
+
+	var msg types.AudioSpeechRequest
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if err := msg.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	status, code, message := handleAudioSpeechRequest(c.Request.Context(), env, publishChan, c.Writer, msg)
+	if code != 0 {
+		c.JSON(status, types.BaseHttpResponse{
+			Code:    code,
+			Message: message,
+		})
+	}
+}
+
+func AudioSpeechProxyHandler(c *gin.Context, env *Env, publishChan chan<- []byte, store db.Store) {
+	rsp := types.BaseHttpResponse{}
+
+	if !env.Config.App.PeersCollect.Enabled {
+		rsp.Code = int(types.ErrCodeUnsupported)
+		rsp.Message = types.ErrCodeUnsupported.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	var msg types.AudioSpeechProxyRequest
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if err := msg.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	peer, code, message := audioPeer(env, store, msg.Project, msg.Model, types.ModelTypeAudioSpeech)
+	if code != 0 {
+		rsp.Code = code
+		rsp.Message = message
+		c.JSON(http.StatusInternalServerError, rsp)
+		return
+	}
+
+	asReq := types.AudioSpeechRequest{
+		NodeID:                  peer.NodeID,
+		CID:                     peer.CID,
+		Project:                 msg.Project,
+		AudioSpeechModelRequest: msg.AudioSpeechModelRequest,
+	}
+	status, code, message := handleAudioSpeechRequest(c.Request.Context(), env, publishChan, c.Writer, asReq)
+	if code != 0 {
+		c.JSON(status, types.BaseHttpResponse{
+			Code:    code,
+			Message: message,
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package serve
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"AIComputingNode/pkg/blob"
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+const blobFetchTimeout = 60 * time.Second
+
+// BlobHandler serves the blob of the CID from the local store, or fetches it from the node
+// which stored it and keeps a copy, so the images are only transferred when they are requested.
+func BlobHandler(c *gin.Context, env *Env, blobs *blob.Store) {
+	id, err := blob.ParseCID(c.Param("cid"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, types.BaseHttpResponse{
+			Code:    int(types.ErrCodeParam),
+			Message: err.Error(),
+		})
+		return
+	}
+
+	data, err := blobs.Get(id)
+	nodeID := c.Query("node_id")
+	if errors.Is(err, blob.ErrNotFound) && nodeID != "" && nodeID != env.Config.Identity.PeerID {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), blobFetchTimeout)
+		defer cancel()
+		var stream network.Stream
+		if stream, err = env.Host.NewProtocolStream(ctx, nodeID, blob.Protocol); err == nil {
+			data, err = blob.Fetch(stream, id, blobs.MaxSize())
+		}
+		if err == nil {
+			if _, err := blobs.Put(data); err != nil {
+				log.Logger.Warnf("Store blob %s fetched from %s failed %v", id, nodeID, err)
+			}
+		} else if !errors.Is(err, blob.ErrNotFound) {
+			log.Logger.Errorf("Fetch blob %s from %s failed %v", id, nodeID, err)
+			c.JSON(http.StatusBadGateway, types.BaseHttpResponse{
+				Code:    int(types.ErrCodeStream),
+				Message: err.Error(),
+			})
+			return
+		}
+	}
+	if errors.Is(err, blob.ErrNotFound) {
+		c.JSON(httpStatus(types.ErrCodeNotFound), types.BaseHttpResponse{
+			Code:    int(types.ErrCodeNotFound),
+			Message: types.ErrCodeNotFound.String(),
+		})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, types.BaseHttpResponse{
+			Code:    int(types.ErrCodeDatabase),
+			Message: err.Error(),
+		})
+		return
+	}
+
+	// the content of a CID never changes
+	c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	c.Header("ETag", `"`+id+`"`)
+	c.Data(http.StatusOK, http.DetectContentType(data), data)
+}
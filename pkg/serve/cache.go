@@ -0,0 +1,237 @@
+package serve
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"AIComputingNode/pkg/blob"
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// CacheHeader tells whether the response is served from the cache: HIT, MISS or BYPASS.
+const CacheHeader = "X-Cache"
+
+var (
+	cacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "aicn_response_cache_requests_total",
+		Help: "Requests of the cached projects by the cache result, hit, miss or bypass",
+	}, []string{"project", "result"})
+	cacheEvictions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "aicn_response_cache_evictions_total",
+		Help: "Cached responses evicted by the size limits",
+	})
+	cacheEntries = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "aicn_response_cache_entries",
+		Help: "Number of the cached responses",
+	})
+	cacheBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "aicn_response_cache_bytes",
+		Help: "Total size of the cached responses in bytes",
+	})
+)
+
+type cacheEntry struct {
+	key     string
+	body    []byte
+	created time.Time
+}
+
+// ResponseCache keeps the responses of the deterministic model requests of the configured projects,
+// the least recently used responses are evicted when the size limits are reached.
+type ResponseCache struct {
+	projects   map[string]bool
+	ttl        time.Duration
+	maxEntries int
+	maxBytes   int64
+
+	mutex   sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+	bytes   int64
+}
+
+func NewResponseCache(cfg config.AppResponseCacheConfig) *ResponseCache {
+	ttl, _ := time.ParseDuration(cfg.TTL)
+	rc := &ResponseCache{
+		projects:   make(map[string]bool),
+		ttl:        ttl,
+		maxEntries: cfg.MaxEntries,
+		maxBytes:   cfg.MaxBytes,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+	}
+	for _, project := range cfg.Projects {
+		rc.projects[project] = true
+	}
+	return rc
+}
+
+// cacheKey returns the key of the normalized request, the fields which do not change the response
+// are cleared by the callers. The cid is the model CID set by the request, it is empty for the proxy
+// requests, whose node is selected later. The raw JSON of the request, such as the message contents,
+// is decoded and marshaled again, so that the requests differing in spaces or key order share the key.
+func cacheKey(kind, project, model, cid string, req any) string {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return ""
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var canonical any
+	if err := decoder.Decode(&canonical); err != nil {
+		return ""
+	}
+	if data, err = json.Marshal(canonical); err != nil {
+		return ""
+	}
+	hash := sha256.Sum256(append([]byte(kind+"\n"), data...))
+	return fmt.Sprintf("%s/%s/%s/%s", project, model, cid, hex.EncodeToString(hash[:]))
+}
+
+// chatCacheKey returns the key of the chat request, or "" if the request is streamed or
+// its sampling is not deterministic.
+func chatCacheKey(project, cid string, req types.ChatModelRequest) string {
+	if req.Stream || req.Temperature == nil || *req.Temperature != 0 {
+		return ""
+	}
+	req.StreamOptions = nil
+	req.User = ""
+	return cacheKey("chat", project, req.Model, cid, req)
+}
+
+// imageGenCacheKey returns the key of the image generation request, or "" if it has no seed.
+func imageGenCacheKey(project, cid string, req types.ImageGenModelRequest) string {
+	if req.Seed == nil {
+		return ""
+	}
+	return cacheKey("image_gen", project, req.Model, cid, req)
+}
+
+func embeddingCacheKey(project, cid string, req types.EmbeddingModelRequest) string {
+	return cacheKey("embedding", project, req.Model, cid, req)
+}
+
+// lookup writes the cached response of the request and reports whether it is found. It returns
+// the key to store the response of the request with, which is empty if the response is not cached.
+func (rc *ResponseCache) lookup(c *gin.Context, project, key string) (string, bool) {
+	if !rc.projects[project] {
+		return "", false
+	}
+	if key == "" {
+		c.Header(CacheHeader, "BYPASS")
+		cacheRequests.WithLabelValues(project, "bypass").Inc()
+		return "", false
+	}
+	body, created, ok := rc.get(key)
+	if !ok {
+		c.Header(CacheHeader, "MISS")
+		cacheRequests.WithLabelValues(project, "miss").Inc()
+		return key, false
+	}
+	c.Header(CacheHeader, "HIT")
+	c.Header("Age", strconv.Itoa(int(time.Since(created).Seconds())))
+	cacheRequests.WithLabelValues(project, "hit").Inc()
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	log.Logger.Infof("Serve the cached response of %s", key)
+	return key, true
+}
+
+// store caches the successful response of the key returned by lookup.
+func (rc *ResponseCache) store(key string, rsp any) {
+	if key == "" {
+		return
+	}
+	body, err := json.Marshal(rsp)
+	if err != nil {
+		log.Logger.Errorf("Marshal the cached response of %s failed %v", key, err)
+		return
+	}
+	rc.put(key, body)
+}
+
+// storeImages caches the image response unless its images are the blobs of a node, which may expire
+// before the response by the image store TTL of that node.
+func (rc *ResponseCache) storeImages(key string, rsp types.ImageGenerationResponse) {
+	for _, choice := range rsp.Choices {
+		if strings.HasPrefix(choice.Url, blob.URLPrefix) {
+			return
+		}
+	}
+	rc.store(key, rsp)
+}
+
+func (rc *ResponseCache) get(key string) ([]byte, time.Time, bool) {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	elem, ok := rc.entries[key]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	entry := elem.Value.(*cacheEntry)
+	if time.Since(entry.created) > rc.ttl {
+		rc.remove(elem)
+		return nil, time.Time{}, false
+	}
+	rc.lru.MoveToFront(elem)
+	return entry.body, entry.created, true
+}
+
+func (rc *ResponseCache) put(key string, body []byte) {
+	if int64(len(body)) > rc.maxBytes {
+		return
+	}
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	if elem, ok := rc.entries[key]; ok {
+		rc.remove(elem)
+	}
+	rc.entries[key] = rc.lru.PushFront(&cacheEntry{
+		key:     key,
+		body:    body,
+		created: time.Now(),
+	})
+	rc.bytes += int64(len(body))
+	cacheEntries.Inc()
+	cacheBytes.Add(float64(len(body)))
+	for len(rc.entries) > rc.maxEntries || rc.bytes > rc.maxBytes {
+		rc.remove(rc.lru.Back())
+		cacheEvictions.Inc()
+	}
+}
+
+// remove deletes the entry of the element, it is called with the mutex locked.
+func (rc *ResponseCache) remove(elem *list.Element) {
+	entry := rc.lru.Remove(elem).(*cacheEntry)
+	delete(rc.entries, entry.key)
+	rc.bytes -= int64(len(entry.body))
+	cacheEntries.Dec()
+	cacheBytes.Sub(float64(len(entry.body)))
+}
+
+// CleanExpired deletes the expired responses, it is called by the scheduled job of the node.
+func (rc *ResponseCache) CleanExpired() {
+	rc.mutex.Lock()
+	defer rc.mutex.Unlock()
+	for elem := rc.lru.Back(); elem != nil; {
+		prev := elem.Prev()
+		if time.Since(elem.Value.(*cacheEntry).created) > rc.ttl {
+			rc.remove(elem)
+		}
+		elem = prev
+	}
+}
@@ -0,0 +1,170 @@
+package serve
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/blob"
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/types"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestCache(maxEntries int, maxBytes int64) *ResponseCache {
+	return NewResponseCache(config.AppResponseCacheConfig{
+		Projects:   []string{"DecentralGPT"},
+		TTL:        "10m",
+		MaxEntries: maxEntries,
+		MaxBytes:   maxBytes,
+	})
+}
+
+// go test -v -timeout 30s -count=1 -run TestChatCacheKey AIComputingNode/pkg/serve
+func TestChatCacheKey(t *testing.T) {
+	zero := float32(0)
+	one := float32(1)
+	req := types.ChatModelRequest{
+		Model:    "Llama3-70B",
+		Messages: []types.ChatCompletionMessage{{Role: "user", Content: json.RawMessage(`"Hello"`)}},
+	}
+	if key := chatCacheKey("DecentralGPT", "", req); key != "" {
+		t.Errorf("Request without temperature is cached %s", key)
+	}
+	req.Temperature = &one
+	if key := chatCacheKey("DecentralGPT", "", req); key != "" {
+		t.Errorf("Request of temperature 1 is cached %s", key)
+	}
+	req.Temperature = &zero
+	key := chatCacheKey("DecentralGPT", "", req)
+	if key == "" {
+		t.Fatal("Request of temperature 0 is not cached")
+	}
+
+	other := req
+	other.User = "user"
+	other.Messages = []types.ChatCompletionMessage{{Role: "user", Content: json.RawMessage(` "Hello" `)}}
+	if k := chatCacheKey("DecentralGPT", "", other); k != key {
+		t.Errorf("Normalized requests have different keys %s %s", key, k)
+	}
+	parts := req
+	parts.Messages = []types.ChatCompletionMessage{{Role: "user", Content: json.RawMessage(`[{"type":"text","text":"Hello"}]`)}}
+	reordered := req
+	reordered.Messages = []types.ChatCompletionMessage{{Role: "user", Content: json.RawMessage(`[ { "text": "Hello", "type": "text" } ]`)}}
+	if k1, k2 := chatCacheKey("DecentralGPT", "", parts), chatCacheKey("DecentralGPT", "", reordered); k1 == "" || k1 != k2 {
+		t.Errorf("Requests of different key orders have different keys %s %s", k1, k2)
+	}
+	wallet := req
+	wallet.WalletVerification = types.WalletVerification{Wallet: "0x1"}
+	if k := chatCacheKey("DecentralGPT", "", wallet); k == key {
+		t.Error("Requests of different wallets have the same key")
+	}
+	if k := chatCacheKey("DecentralGPT", "cid", req); k == key {
+		t.Error("Requests of different CIDs have the same key")
+	}
+	if k := embeddingCacheKey("DecentralGPT", "", types.EmbeddingModelRequest{Model: req.Model}); k == key {
+		t.Error("Requests of different APIs have the same key")
+	}
+	other.Stream = true
+	if k := chatCacheKey("DecentralGPT", "", other); k != "" {
+		t.Errorf("Stream request is cached %s", k)
+	}
+}
+
+// go test -v -timeout 30s -count=1 -run TestImageGenCache AIComputingNode/pkg/serve
+func TestImageGenCache(t *testing.T) {
+	req := types.ImageGenModelRequest{Model: "SuperImage", Prompt: "bird", Number: 1}
+	if key := imageGenCacheKey("DecentralGPT", "", req); key != "" {
+		t.Errorf("Request without seed is cached %s", key)
+	}
+	seed := int64(42)
+	req.Seed = &seed
+	key := imageGenCacheKey("DecentralGPT", "", req)
+	if key == "" {
+		t.Fatal("Request with seed is not cached")
+	}
+	other := int64(43)
+	req.Seed = &other
+	if k := imageGenCacheKey("DecentralGPT", "", req); k == key {
+		t.Error("Requests of different seeds have the same key")
+	}
+
+	rc := newTestCache(10, 1<<20)
+	blobRsp := types.ImageGenerationResponse{}
+	blobRsp.Choices = []types.ImageResponseChoice{{Url: blob.URL("bafkreid", "16Uiu2HAm1")}}
+	rc.storeImages("blob", blobRsp)
+	if _, _, ok := rc.get("blob"); ok {
+		t.Error("Response of the blob images is cached")
+	}
+	urlRsp := types.ImageGenerationResponse{}
+	urlRsp.Choices = []types.ImageResponseChoice{{Url: "https://example.com/bird.png"}}
+	rc.storeImages("url", urlRsp)
+	if _, _, ok := rc.get("url"); !ok {
+		t.Error("Response of the image URLs is not cached")
+	}
+}
+
+// go test -v -timeout 30s -count=1 -run TestResponseCacheLookup AIComputingNode/pkg/serve
+func TestResponseCacheLookup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rc := newTestCache(10, 1<<20)
+	lookup := func(project, key string) (*httptest.ResponseRecorder, string, bool) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		storeKey, hit := rc.lookup(c, project, key)
+		return w, storeKey, hit
+	}
+
+	if w, key, hit := lookup("SuperImageAI", "key"); hit || key != "" || w.Header().Get(CacheHeader) != "" {
+		t.Errorf("Project not opted in is cached: %v %q %v", hit, key, w.Header())
+	}
+	if w, key, hit := lookup("DecentralGPT", ""); hit || key != "" || w.Header().Get(CacheHeader) != "BYPASS" {
+		t.Errorf("Expected bypass but got %v %q %v", hit, key, w.Header())
+	}
+	w, key, hit := lookup("DecentralGPT", "key")
+	if hit || key != "key" || w.Header().Get(CacheHeader) != "MISS" {
+		t.Fatalf("Expected miss but got %v %q %v", hit, key, w.Header())
+	}
+	rsp := types.BaseHttpResponse{Message: "cached"}
+	rc.store(key, rsp)
+	w, _, hit = lookup("DecentralGPT", "key")
+	if !hit || w.Header().Get(CacheHeader) != "HIT" || w.Header().Get("Age") == "" {
+		t.Fatalf("Expected hit but got %v %v", hit, w.Header())
+	}
+	var cached types.BaseHttpResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &cached); err != nil || cached != rsp {
+		t.Errorf("Unexpected cached response %s %v", w.Body.String(), err)
+	}
+}
+
+// go test -v -timeout 30s -count=1 -run TestResponseCacheLimits AIComputingNode/pkg/serve
+func TestResponseCacheLimits(t *testing.T) {
+	rc := newTestCache(2, 10)
+	rc.put("a", []byte("aaaa"))
+	rc.put("b", []byte("bbbb"))
+	// a is used more recently than b
+	if _, _, ok := rc.get("a"); !ok {
+		t.Fatal("Response a not cached")
+	}
+	rc.put("c", []byte("cccc"))
+	if _, _, ok := rc.get("b"); ok {
+		t.Error("Least recently used response is not evicted by the entry limit")
+	}
+	rc.put("d", []byte("dddddd"))
+	if _, _, ok := rc.get("a"); ok || rc.bytes > 10 {
+		t.Errorf("Responses of %d bytes are cached", rc.bytes)
+	}
+	rc.put("e", []byte("eeeeeeeeeee"))
+	if _, _, ok := rc.get("e"); ok {
+		t.Error("Response over the byte limit is cached")
+	}
+
+	rc.ttl = time.Millisecond
+	time.Sleep(2 * time.Millisecond)
+	rc.CleanExpired()
+	if len(rc.entries) != 0 || rc.bytes != 0 || rc.lru.Len() != 0 {
+		t.Errorf("Expired responses are kept %d %d", len(rc.entries), rc.bytes)
+	}
+}
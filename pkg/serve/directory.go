@@ -0,0 +1,314 @@
+package serve
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"AIComputingNode/pkg/db"
+	"AIComputingNode/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+func newPeerDirectoryEntry(id string, info db.PeerCollectInfo) types.PeerDirectoryEntry {
+	nt := types.NodeType(info.NodeType)
+	entry := types.PeerDirectoryEntry{
+		NodeID:        id,
+		NodeType:      info.NodeType,
+		PublicNode:    nt.IsPublicNode(),
+		ClientNode:    nt.IsClientNode(),
+		ModelNode:     nt.IsModelNode(),
+		LastHeartbeat: info.Timestamp,
+		Metrics:       info.Metrics,
+	}
+	if len(info.AIProjects) > 0 {
+		entry.Projects = make(map[string][]string, len(info.AIProjects))
+		for project, models := range info.AIProjects {
+			names := make([]string, 0, len(models))
+			for _, model := range models {
+				if !slices.Contains(names, model.Model) {
+					names = append(names, model.Model)
+				}
+			}
+			entry.Projects[project] = names
+		}
+	}
+	return entry
+}
+
+func matchPeerDirectoryEntry(entry types.PeerDirectoryEntry, req types.PeerDirectoryRequest) bool {
+	if req.NodeType != 0 && entry.NodeType&req.NodeType != req.NodeType {
+		return false
+	}
+	if req.Project != "" {
+		models, ok := entry.Projects[req.Project]
+		if !ok {
+			return false
+		}
+		if req.Model != "" && !slices.Contains(models, req.Model) {
+			return false
+		}
+	}
+	// the relayed peers have limited connections
+	if req.Connected && entry.Connectivity != int(network.Connected) && entry.Connectivity != int(network.Limited) {
+		return false
+	}
+	if req.Relayed && !entry.Relayed {
+		return false
+	}
+	return true
+}
+
+func sortPeerDirectory(entries []types.PeerDirectoryEntry, field string) {
+	desc := strings.HasPrefix(field, "-")
+	field = strings.TrimPrefix(field, "-")
+	less := func(a, b types.PeerDirectoryEntry) bool {
+		switch field {
+		case "node_id":
+			return a.NodeID < b.NodeID
+		case "node_type":
+			return a.NodeType < b.NodeType
+		case "last_heartbeat":
+			return a.LastHeartbeat < b.LastHeartbeat
+		case "latency":
+			return a.Latency < b.Latency
+		case "connectivity":
+			return a.Connectivity < b.Connectivity
+		case "agent_version":
+			return a.AgentVersion < b.AgentVersion
+		default:
+			return false
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if desc {
+			return less(entries[j], entries[i])
+		}
+		return less(entries[i], entries[j])
+	})
+}
+
+func paginatePeerDirectory(entries []types.PeerDirectoryEntry, offset, limit int) []types.PeerDirectoryEntry {
+	if offset >= len(entries) {
+		return []types.PeerDirectoryEntry{}
+	}
+	end := offset + limit
+	if end > len(entries) {
+		end = len(entries)
+	}
+	return entries[offset:end]
+}
+
+func PeerDirectoryHandler(c *gin.Context, env *Env, store db.Store) {
+	rsp := types.PeerDirectoryResponse{
+		Data: make([]types.PeerDirectoryEntry, 0),
+	}
+
+	var req types.PeerDirectoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 20
+	} else if req.Limit > 1000 {
+		req.Limit = 1000
+	}
+
+	infos, code := store.ListPeerCollectInfo()
+	if code != 0 {
+		rsp.Code = code
+		rsp.Message = types.ErrorCode(code).String()
+		c.JSON(httpStatus(types.ErrorCode(code)), rsp)
+		return
+	}
+	// Connected peers are known even if their heartbeats have not been collected
+	for _, conn := range env.Host.PeerConns() {
+		if _, ok := infos[conn.Peer]; !ok {
+			infos[conn.Peer] = db.PeerCollectInfo{}
+		}
+	}
+
+	entries := make([]types.PeerDirectoryEntry, 0, len(infos))
+	for id, info := range infos {
+		entry := newPeerDirectoryEntry(id, info)
+		entry.Connectivity = env.Host.Connectedness(id)
+		entry.Latency = env.Host.Latency(id).Microseconds()
+		entry.AgentVersion = env.Host.AgentVersion(id)
+		entry.Relayed = env.Host.Relayed(id)
+		if matchPeerDirectoryEntry(entry, req) {
+			entries = append(entries, entry)
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].NodeID < entries[j].NodeID
+	})
+	if req.Sort != "" {
+		sortPeerDirectory(entries, req.Sort)
+	}
+
+	rsp.Total = len(entries)
+	rsp.Data = paginatePeerDirectory(entries, req.Offset, req.Limit)
+	c.JSON(http.StatusOK, rsp)
+}
+
+func buildTopology(env *Env, store db.Store) types.TopologyResponse {
+	infos, code := store.ListPeerCollectInfo()
+	if code != 0 {
+		infos = map[string]db.PeerCollectInfo{}
+	}
+	rsp := collectTopology(env.Config.Identity.PeerID, env.Host.PeerConnections(), infos)
+	for i := range rsp.Nodes {
+		if rsp.Nodes[i].Self {
+			rsp.Nodes[i].AgentVersion = env.Host.UserAgent
+		} else {
+			rsp.Nodes[i].AgentVersion = env.Host.AgentVersion(rsp.Nodes[i].NodeID)
+		}
+	}
+	return rsp
+}
+
+// collectTopology returns the nodes and the connections between them, from the live connections
+// of this node and the connections reported in the collected heartbeats. A connection reported
+// by both of its nodes is one edge, the report of this node or of the node of the lower id wins.
+func collectTopology(self string, conns []types.PeerConnection, infos map[string]db.PeerCollectInfo) types.TopologyResponse {
+	rsp := types.TopologyResponse{
+		Timestamp: time.Now().Unix(),
+		Nodes:     make([]types.TopologyNode, 0),
+		Edges:     make([]types.TopologyEdge, 0),
+	}
+	nodes := map[string]bool{}
+	addNode := func(id string) {
+		if nodes[id] {
+			return
+		}
+		nodes[id] = true
+		rsp.Nodes = append(rsp.Nodes, types.TopologyNode{
+			NodeID:   id,
+			NodeType: infos[id].NodeType,
+			Self:     id == self,
+		})
+	}
+	edges := map[[3]string]bool{}
+	addEdge := func(from string, conn types.PeerConnection) {
+		if conn.Relay != "" {
+			from = conn.Relay
+		}
+		if from == conn.NodeID {
+			return
+		}
+		key := [3]string{from, conn.NodeID, conn.Relay}
+		if key[0] > key[1] {
+			key[0], key[1] = key[1], key[0]
+		}
+		if edges[key] {
+			return
+		}
+		edges[key] = true
+		addNode(from)
+		addNode(conn.NodeID)
+		rsp.Edges = append(rsp.Edges, types.TopologyEdge{
+			From:      from,
+			To:        conn.NodeID,
+			Direction: conn.Direction,
+			Latency:   conn.Latency,
+			Relay:     conn.Relay,
+		})
+	}
+
+	addNode(self)
+	for _, conn := range conns {
+		addEdge(self, conn)
+	}
+	ids := make([]string, 0, len(infos))
+	for id := range infos {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		if id == self {
+			continue
+		}
+		addNode(id)
+		for _, conn := range infos[id].Connections {
+			addEdge(id, conn)
+		}
+	}
+	sort.SliceStable(rsp.Edges, func(i, j int) bool {
+		if rsp.Edges[i].From != rsp.Edges[j].From {
+			return rsp.Edges[i].From < rsp.Edges[j].From
+		}
+		return rsp.Edges[i].To < rsp.Edges[j].To
+	})
+	return rsp
+}
+
+// topologyDOT renders the topology in the Graphviz DOT language.
+func topologyDOT(topology types.TopologyResponse) string {
+	var sb strings.Builder
+	sb.WriteString("graph aicn {\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, node := range topology.Nodes {
+		attrs := []string{fmt.Sprintf("label=%q", node.NodeID)}
+		if node.Self {
+			attrs = append(attrs, "style=bold")
+		}
+		nt := types.NodeType(node.NodeType)
+		if nt.IsModelNode() {
+			attrs = append(attrs, "color=blue")
+		} else if nt.IsClientNode() {
+			attrs = append(attrs, "color=green")
+		}
+		fmt.Fprintf(&sb, "  %q [%s];\n", node.NodeID, strings.Join(attrs, ", "))
+	}
+	for _, edge := range topology.Edges {
+		attrs := []string{fmt.Sprintf("label=\"%dus\"", edge.Latency)}
+		if edge.Relay != "" {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&sb, "  %q -- %q [%s];\n", edge.From, edge.To, strings.Join(attrs, ", "))
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+func TopologyHandler(c *gin.Context, env *Env, store db.Store) {
+	rsp := types.TopologyResponse{}
+
+	var req types.TopologyRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	rsp = buildTopology(env, store)
+	if req.Format == "dot" {
+		c.Data(http.StatusOK, "text/vnd.graphviz; charset=utf-8", []byte(topologyDOT(rsp)))
+		return
+	}
+	c.JSON(http.StatusOK, rsp)
+}
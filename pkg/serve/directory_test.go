@@ -0,0 +1,163 @@
+package serve
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"AIComputingNode/pkg/db"
+	"AIComputingNode/pkg/types"
+
+	"github.com/libp2p/go-libp2p/core/network"
+)
+
+// go test -v -timeout 30s -count=1 -run TestPeerDirectory AIComputingNode/pkg/serve
+func TestPeerDirectory(t *testing.T) {
+	infos := map[string]db.PeerCollectInfo{
+		"a": {
+			AIProjects: map[string][]types.ModelIdle{
+				"DecentralGPT": {
+					{AIModelConfig: types.AIModelConfig{Model: "Qwen2.5-72B", CID: "#1"}},
+					{AIModelConfig: types.AIModelConfig{Model: "Qwen2.5-72B", CID: "#2"}},
+				},
+			},
+			NodeType:  uint32(types.ModelFlag),
+			Timestamp: 300,
+		},
+		"b": {
+			NodeType:  uint32(types.PublicIpFlag | types.PeersCollectFlag),
+			Timestamp: 100,
+		},
+		"c": {
+			NodeType:  uint32(types.PublicIpFlag | types.ModelFlag),
+			Timestamp: 200,
+		},
+	}
+	entries := make([]types.PeerDirectoryEntry, 0)
+	for _, id := range []string{"a", "b", "c"} {
+		entry := newPeerDirectoryEntry(id, infos[id])
+		entry.Connectivity = int(network.Connected)
+		if id == "b" {
+			entry.Connectivity = int(network.Limited)
+			entry.Relayed = true
+		}
+		if id == "c" {
+			entry.Connectivity = int(network.NotConnected)
+		}
+		entries = append(entries, entry)
+	}
+	if models := entries[0].Projects["DecentralGPT"]; len(models) != 1 || models[0] != "Qwen2.5-72B" {
+		t.Errorf("Unexpected projects %v", entries[0].Projects)
+	}
+	if !entries[1].PublicNode || !entries[1].ClientNode || entries[1].ModelNode {
+		t.Errorf("Unexpected node type flags %v", entries[1])
+	}
+
+	filter := func(req types.PeerDirectoryRequest) []string {
+		ids := make([]string, 0)
+		for _, entry := range entries {
+			if matchPeerDirectoryEntry(entry, req) {
+				ids = append(ids, entry.NodeID)
+			}
+		}
+		return ids
+	}
+	if ids := filter(types.PeerDirectoryRequest{NodeType: uint32(types.ModelFlag)}); strings.Join(ids, ",") != "a,c" {
+		t.Errorf("Filter by node type got %v", ids)
+	}
+	if ids := filter(types.PeerDirectoryRequest{Project: "DecentralGPT", Model: "Qwen2.5-72B"}); strings.Join(ids, ",") != "a" {
+		t.Errorf("Filter by project got %v", ids)
+	}
+	if ids := filter(types.PeerDirectoryRequest{Project: "DecentralGPT", Model: "Llama3-70B"}); len(ids) != 0 {
+		t.Errorf("Filter by model got %v", ids)
+	}
+	if ids := filter(types.PeerDirectoryRequest{Relayed: true}); strings.Join(ids, ",") != "b" {
+		t.Errorf("Filter by relay got %v", ids)
+	}
+	if ids := filter(types.PeerDirectoryRequest{Connected: true}); strings.Join(ids, ",") != "a,b" {
+		t.Errorf("Filter by connection got %v", ids)
+	}
+
+	sortPeerDirectory(entries, "-last_heartbeat")
+	if entries[0].NodeID != "a" || entries[1].NodeID != "c" || entries[2].NodeID != "b" {
+		t.Errorf("Sort by last heartbeat got %v %v %v", entries[0].NodeID, entries[1].NodeID, entries[2].NodeID)
+	}
+	if page := paginatePeerDirectory(entries, 1, 5); len(page) != 2 || page[0].NodeID != "c" {
+		t.Errorf("Unexpected page %v", page)
+	}
+	if page := paginatePeerDirectory(entries, 3, 5); len(page) != 0 {
+		t.Errorf("Unexpected page %v", page)
+	}
+
+	if err := (types.PeerDirectoryRequest{Sort: "-latency"}).Validate(); err != nil {
+		t.Errorf("Validate sort field failed %v", err)
+	}
+	if err := (types.PeerDirectoryRequest{Sort: "address"}).Validate(); err == nil {
+		t.Error("Validate unsupported sort field succeeded")
+	}
+}
+
+// go test -v -timeout 30s -count=1 -run TestTopologyDOT AIComputingNode/pkg/serve
+func TestTopologyDOT(t *testing.T) {
+	dot := topologyDOT(types.TopologyResponse{
+		Nodes: []types.TopologyNode{
+			{NodeID: "self", Self: true},
+			{NodeID: "relay", NodeType: uint32(types.PublicIpFlag | types.PeersCollectFlag)},
+			{NodeID: "model", NodeType: uint32(types.ModelFlag)},
+		},
+		Edges: []types.TopologyEdge{
+			{From: "self", To: "relay", Latency: 1200},
+			{From: "relay", To: "model", Latency: 89121, Relay: "relay"},
+		},
+	})
+	t.Log(dot)
+	for _, line := range []string{
+		`"self" [label="self", style=bold];`,
+		`"model" [label="model", color=blue];`,
+		`"self" -- "relay" [label="1200us"];`,
+		`"relay" -- "model" [label="89121us", style=dashed];`,
+	} {
+		if !strings.Contains(dot, line) {
+			t.Errorf("DOT output does not contain %s", line)
+		}
+	}
+}
+
+// go test -v -timeout 30s -count=1 -run TestCollectTopology AIComputingNode/pkg/serve
+func TestCollectTopology(t *testing.T) {
+	infos := map[string]db.PeerCollectInfo{
+		"relay": {
+			NodeType: uint32(types.PublicIpFlag | types.PeersCollectFlag),
+			Connections: []types.PeerConnection{
+				{NodeID: "self", Direction: "Inbound", Latency: 1000},
+				{NodeID: "model", Direction: "Inbound", Latency: 3000},
+			},
+		},
+		"model": {
+			NodeType: uint32(types.ModelFlag),
+			Connections: []types.PeerConnection{
+				{NodeID: "relay", Direction: "Outbound", Latency: 3100},
+				{NodeID: "self", Relay: "relay", Direction: "Inbound", Latency: 5000},
+			},
+		},
+	}
+	conns := []types.PeerConnection{{NodeID: "relay", Direction: "Outbound", Latency: 1200}}
+	topology := collectTopology("self", conns, infos)
+
+	edges := make([]string, 0)
+	for _, edge := range topology.Edges {
+		edges = append(edges, fmt.Sprintf("%s-%s/%s/%d", edge.From, edge.To, edge.Relay, edge.Latency))
+	}
+	// the connection between the relay and the model is reported by both of them
+	if s := strings.Join(edges, ","); s != "model-relay//3100,relay-self/relay/5000,self-relay//1200" {
+		t.Errorf("Unexpected edges %s", s)
+	}
+	if len(topology.Nodes) != 3 || !topology.Nodes[0].Self {
+		t.Fatalf("Unexpected nodes %+v", topology.Nodes)
+	}
+	for _, node := range topology.Nodes {
+		if node.NodeType != infos[node.NodeID].NodeType {
+			t.Errorf("Unexpected node type of %+v", node)
+		}
+	}
+}
@@ -7,21 +7,17 @@ import (
 	"net/http"
 	"time"
 
-	"AIComputingNode/pkg/config"
 	"AIComputingNode/pkg/db"
-	"AIComputingNode/pkg/hardware"
-	"AIComputingNode/pkg/libp2p/host"
 	"AIComputingNode/pkg/log"
-	"AIComputingNode/pkg/model"
 	"AIComputingNode/pkg/protocol"
+	"AIComputingNode/pkg/scheduler"
 	"AIComputingNode/pkg/timer"
 	"AIComputingNode/pkg/types"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/libp2p/go-libp2p/core/network"
 	"google.golang.org/protobuf/proto"
-
-	pubsub "github.com/libp2p/go-libp2p-pubsub"
 )
 
 type httpService struct {
@@ -37,6 +33,12 @@ func httpStatus(code types.ErrorCode) int {
 		return http.StatusOK
 	case types.ErrCodeParam, types.ErrCodeParse:
 		return http.StatusBadRequest
+	case types.ErrCodeRateLimit:
+		return http.StatusTooManyRequests
+	case types.ErrCodeNotFound:
+		return http.StatusNotFound
+	case types.ErrCodeBusy:
+		return http.StatusServiceUnavailable
 	case types.ErrCodeProtobuf, types.ErrCodeTimeout, types.ErrCodeInternal:
 		return http.StatusInternalServerError
 	default:
@@ -44,14 +46,14 @@ func httpStatus(code types.ErrorCode) int {
 	}
 }
 
-func IdHandler(c *gin.Context) {
-	id := host.Hio.GetIdentifyProtocol()
+func IdHandler(c *gin.Context, env *Env) {
+	id := env.Host.GetIdentifyProtocol()
 	c.JSON(http.StatusOK, id)
 }
 
-func PeersHandler(c *gin.Context) {
+func PeersHandler(c *gin.Context, store db.Store) {
 	rsp := types.PeerListResponse{}
-	rsp.Data, rsp.Code = db.FindPeers(100)
+	rsp.Data, rsp.Code = store.FindPeers(100)
 	if rsp.Code != 0 {
 		rsp.Message = types.ErrorCode(rsp.Code).String()
 		c.JSON(http.StatusInternalServerError, rsp)
@@ -60,18 +62,30 @@ func PeersHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, rsp)
 }
 
-func handleRequest(publishChan chan<- []byte, req *protocol.Message, rsp any, timeout time.Duration) (int, int, string) {
+// statusClientClosedRequest is returned when the client goes away before the response arrives.
+const statusClientClosedRequest = 499
+
+// cancelableMessages are the model requests the worker stops when they are canceled.
+var cancelableMessages = map[protocol.MessageType]bool{
+	protocol.MessageType_CHAT_COMPLETION:     true,
+	protocol.MessageType_IMAGE_GENERATION:    true,
+	protocol.MessageType_EMBEDDING:           true,
+	protocol.MessageType_AUDIO_TRANSCRIPTION: true,
+	protocol.MessageType_AUDIO_SPEECH:        true,
+}
+
+func handleRequest(ctx context.Context, env *Env, publishChan chan<- []byte, req *protocol.Message, rsp any, timeout time.Duration) (int, int, string) {
 	requestID := req.Header.Id
 	reqBytes, err := proto.Marshal(req)
 	if err != nil {
 		return http.StatusInternalServerError, int(types.ErrCodeProtobuf), err.Error()
 	}
-	if len(reqBytes) > pubsub.DefaultMaxMessageSize {
-		return http.StatusRequestEntityTooLarge, int(types.ErrCodeInternal), "message cannot exceed 1 megabyte"
+	if len(reqBytes) > env.Config.Pubsub.MaxMessageSize {
+		return http.StatusRequestEntityTooLarge, int(types.ErrCodeInternal), fmt.Sprintf("message cannot exceed %d bytes", env.Config.Pubsub.MaxMessageSize)
 	}
 
 	notifyChan := make(chan []byte, 1024)
-	AddRequestItem(requestID, notifyChan)
+	env.Requests.AddRequestItem(requestID, notifyChan)
 
 	publishChan <- reqBytes
 
@@ -88,13 +102,70 @@ func handleRequest(publishChan chan<- []byte, req *protocol.Message, rsp any, ti
 		}
 	case <-time.After(timeout):
 		log.Logger.Warnf("request id %s message type %s timeout", requestID, req.Type)
-		DeleteRequestItem(requestID)
-		close(notifyChan)
+		env.Requests.DeleteRequestItem(requestID)
+		sendCancel(env, publishChan, req)
 		return http.StatusGatewayTimeout, int(types.ErrCodeTimeout), types.ErrCodeTimeout.String()
+	case <-ctx.Done():
+		log.Logger.Warnf("request id %s message type %s canceled %v", requestID, req.Type, ctx.Err())
+		env.Requests.DeleteRequestItem(requestID)
+		sendCancel(env, publishChan, req)
+		return statusClientClosedRequest, int(types.ErrCodeCanceled), types.ErrCodeCanceled.String()
 	}
 }
 
-func PeerHandler(c *gin.Context, publishChan chan<- []byte) {
+// sendCancel tells the worker of a model request to stop it, the CANCEL message has the same
+// request id, and the worker only accepts it from the node sending the request.
+func sendCancel(env *Env, publishChan chan<- []byte, req *protocol.Message) {
+	if !cancelableMessages[req.Type] {
+		return
+	}
+	body, err := proto.Marshal(&protocol.CancelBody{
+		Data: &protocol.CancelBody_Req{
+			Req: &protocol.CancelRequest{
+				Type: req.Type,
+			},
+		},
+	})
+	if err != nil {
+		log.Logger.Errorf("Marshal Cancel Body %v", err)
+		return
+	}
+	msg := &protocol.Message{
+		Header: &protocol.MessageHeader{
+			ClientVersion: env.Host.UserAgent,
+			Timestamp:     time.Now().Unix(),
+			Id:            req.Header.GetId(),
+			NodeId:        env.Config.Identity.PeerID,
+			Receiver:      req.Header.GetReceiver(),
+		},
+		Type: protocol.MessageType_CANCEL,
+		Body: body,
+	}
+	// The context of the request is done, the message is encrypted without it.
+	ctx, cancel := context.WithTimeout(context.Background(), types.OrdinaryRequestTimeout)
+	defer cancel()
+	if err := env.Host.EncryptMessage(ctx, msg); err != nil {
+		log.Logger.Warnf("Encrypt %s message to %s failed %v", msg.Type.String(), msg.Header.GetReceiver(), err)
+		return
+	}
+	msgBytes, err := proto.Marshal(msg)
+	if err != nil {
+		log.Logger.Errorf("Marshal Cancel Message %v", err)
+		return
+	}
+	publishChan <- msgBytes
+	log.Logger.Infof("Sending %s message of %s request %s", msg.Type.String(), req.Type.String(), req.Header.GetId())
+}
+
+// resetOnCancel resets the stream when the client of the request goes away, so that the worker
+// stops the model. The returned function must be called when the request is done.
+func resetOnCancel(ctx context.Context, stream network.Stream) func() bool {
+	return context.AfterFunc(ctx, func() {
+		stream.Reset()
+	})
+}
+
+func PeerHandler(c *gin.Context, env *Env, publishChan chan<- []byte, store db.Store) {
 	// if r.Method != http.MethodPost {
 	// 	http.Error(w, "Method is not supported.", http.StatusMethodNotAllowed)
 	// 	return
@@ -116,65 +187,45 @@ func PeerHandler(c *gin.Context, publishChan chan<- []byte) {
 		return
 	}
 
-	if msg.NodeID == config.GC.Identity.PeerID {
-		rsp.IdentifyProtocol = host.Hio.GetIdentifyProtocol()
-		c.JSON(http.StatusOK, rsp)
-		return
+	rsp, status := queryPeerIdentity(c.Request.Context(), env, publishChan, store, msg.NodeID, msg.RemoteQueryOptions)
+	if rsp.Code != 0 {
+		c.JSON(status, rsp.BaseHttpResponse)
+	} else {
+		c.JSON(status, rsp)
 	}
+}
 
-	requestID, err := uuid.NewRandom()
-	if err != nil {
-		rsp.Code = int(types.ErrCodeUUID)
-		rsp.Message = err.Error()
-		c.JSON(http.StatusInternalServerError, rsp)
+func PeerBatchHandler(c *gin.Context, env *Env, publishChan chan<- []byte, store db.Store) {
+	rsp := types.PeerBatchResponse{}
+
+	var msg types.BatchRemoteQueryRequest
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
 		return
 	}
 
-	pi := &protocol.PeerIdentityBody{
-		Data: &protocol.PeerIdentityBody_Req{
-			Req: &protocol.PeerIdentityRequest{},
-		},
-	}
-	body, err := proto.Marshal(pi)
-	if err != nil {
-		rsp.SetCode(int(types.ErrCodeProtobuf))
-		rsp.SetMessage(err.Error())
-		c.JSON(http.StatusInternalServerError, rsp)
+	if err := msg.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
 		return
 	}
-	body, err = host.Encrypt(c.Request.Context(), msg.NodeID, body)
 
-	req := &protocol.Message{
-		Header: &protocol.MessageHeader{
-			ClientVersion: host.Hio.UserAgent,
-			Timestamp:     time.Now().Unix(),
-			Id:            requestID.String(),
-			NodeId:        config.GC.Identity.PeerID,
-			Receiver:      msg.NodeID,
-			NodePubKey:    nil,
-			Sign:          nil,
-		},
-		Type:       *protocol.MessageType_PEER_IDENTITY.Enum(),
-		Body:       body,
-		ResultCode: 0,
-	}
-	if err == nil {
-		req.Header.NodePubKey, _ = host.MarshalPubKeyFromPrivKey(host.Hio.PrivKey)
-	}
-	status, code, message := handleRequest(publishChan, req, &rsp, types.OrdinaryRequestTimeout)
-	if code != 0 {
-		c.JSON(status, types.BaseHttpResponse{
-			Code:    code,
-			Message: message,
-		})
-	} else if rsp.Code != 0 {
-		c.JSON(http.StatusInternalServerError, rsp)
-	} else {
-		c.JSON(http.StatusOK, rsp)
-	}
+	ids := uniqueNodeIDs(msg.NodeIDs)
+	rsp.Data = make([]types.PeerBatchItem, len(ids))
+	forEachRemoteNode(env, ids, func(index int, id string) {
+		item, _ := queryPeerIdentity(c.Request.Context(), env, publishChan, store, id, msg.RemoteQueryOptions)
+		rsp.Data[index] = types.PeerBatchItem{
+			NodeID:       id,
+			PeerResponse: item,
+		}
+	})
+	c.JSON(http.StatusOK, rsp)
 }
 
-func HostInfoHandler(c *gin.Context, publishChan chan<- []byte) {
+func HostInfoHandler(c *gin.Context, env *Env, publishChan chan<- []byte, store db.Store) {
 	rsp := types.HostInfoResponse{}
 
 	var msg types.HostInfoRequest
@@ -192,77 +243,50 @@ func HostInfoHandler(c *gin.Context, publishChan chan<- []byte) {
 		return
 	}
 
-	if msg.NodeID == config.GC.Identity.PeerID {
-		hd, err := hardware.GetHostInfo()
-		if err != nil {
-			rsp.Code = int(types.ErrCodeHostInfo)
-			rsp.Message = err.Error()
-			c.JSON(http.StatusInternalServerError, rsp)
-			return
-		}
-		rsp.HostInfo = *hd
-		c.JSON(http.StatusOK, rsp)
-		return
+	rsp, status := queryHostInfo(c.Request.Context(), env, publishChan, store, msg.NodeID, msg.RemoteQueryOptions)
+	if rsp.Code != 0 {
+		c.JSON(status, rsp.BaseHttpResponse)
+	} else {
+		c.JSON(status, rsp)
 	}
+}
 
-	requestID, err := uuid.NewRandom()
-	if err != nil {
-		rsp.Code = int(types.ErrCodeUUID)
-		rsp.Message = err.Error()
-		c.JSON(http.StatusInternalServerError, rsp)
+func HostInfoBatchHandler(c *gin.Context, env *Env, publishChan chan<- []byte, store db.Store) {
+	rsp := types.HostInfoBatchResponse{}
+
+	var msg types.BatchRemoteQueryRequest
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
 		return
 	}
 
-	pi := &protocol.HostInfoBody{
-		Data: &protocol.HostInfoBody_Req{
-			Req: &protocol.HostInfoRequest{},
-		},
-	}
-	body, err := proto.Marshal(pi)
-	if err != nil {
-		rsp.SetCode(int(types.ErrCodeProtobuf))
-		rsp.SetMessage(err.Error())
-		c.JSON(http.StatusInternalServerError, rsp)
+	if err := msg.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
 		return
 	}
-	body, err = host.Encrypt(c.Request.Context(), msg.NodeID, body)
 
-	req := &protocol.Message{
-		Header: &protocol.MessageHeader{
-			ClientVersion: host.Hio.UserAgent,
-			Timestamp:     time.Now().Unix(),
-			Id:            requestID.String(),
-			NodeId:        config.GC.Identity.PeerID,
-			Receiver:      msg.NodeID,
-			NodePubKey:    nil,
-			Sign:          nil,
-		},
-		Type:       *protocol.MessageType_HOST_INFO.Enum(),
-		Body:       body,
-		ResultCode: 0,
-	}
-	if err == nil {
-		req.Header.NodePubKey, _ = host.MarshalPubKeyFromPrivKey(host.Hio.PrivKey)
-	}
-	status, code, message := handleRequest(publishChan, req, &rsp, types.OrdinaryRequestTimeout)
-	if code != 0 {
-		c.JSON(status, types.BaseHttpResponse{
-			Code:    code,
-			Message: message,
-		})
-	} else if rsp.Code != 0 {
-		c.JSON(http.StatusInternalServerError, rsp)
-	} else {
-		c.JSON(http.StatusOK, rsp)
-	}
+	ids := uniqueNodeIDs(msg.NodeIDs)
+	rsp.Data = make([]types.HostInfoBatchItem, len(ids))
+	forEachRemoteNode(env, ids, func(index int, id string) {
+		item, _ := queryHostInfo(c.Request.Context(), env, publishChan, store, id, msg.RemoteQueryOptions)
+		rsp.Data[index] = types.HostInfoBatchItem{
+			NodeID:           id,
+			HostInfoResponse: item,
+		}
+	})
+	c.JSON(http.StatusOK, rsp)
 }
 
-func RendezvousPeersHandler(c *gin.Context) {
+func RendezvousPeersHandler(c *gin.Context, env *Env) {
 	ctx, cancel := context.WithCancel(c.Request.Context())
 	defer cancel()
 	rsp := types.PeerListResponse{}
 
-	peerChan, err := host.Hio.FindPeers(ctx, config.GC.App.TopicName)
+	peerChan, err := env.Host.FindPeers(ctx, env.Config.App.TopicName)
 	if err != nil {
 		log.Logger.Warnf("List peer message: %v", err)
 		rsp.Code = int(types.ErrCodeRendezvous)
@@ -278,17 +302,17 @@ func RendezvousPeersHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, rsp)
 }
 
-func SwarmPeersHandler(c *gin.Context) {
-	pinfos := host.Hio.SwarmPeers()
+func SwarmPeersHandler(c *gin.Context, env *Env) {
+	pinfos := env.Host.SwarmPeers()
 	c.JSON(http.StatusOK, pinfos)
 }
 
-func SwarmAddrsHandler(c *gin.Context) {
-	pinfos := host.Hio.SwarmAddrs()
+func SwarmAddrsHandler(c *gin.Context, env *Env) {
+	pinfos := env.Host.SwarmAddrs()
 	c.JSON(http.StatusOK, pinfos)
 }
 
-func SwarmConnectHandler(c *gin.Context) {
+func SwarmConnectHandler(c *gin.Context, env *Env) {
 	rsp := types.SwarmConnectResponse{
 		Code:    0,
 		Message: "ok",
@@ -311,7 +335,7 @@ func SwarmConnectHandler(c *gin.Context) {
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
 	defer cancel()
-	if err := host.Hio.SwarmConnect(ctx, req.NodeAddr); err != nil {
+	if err := env.Host.SwarmConnect(ctx, req.NodeAddr); err != nil {
 		rsp.Code = int(types.ErrCodeInternal)
 		rsp.Message = err.Error()
 		c.JSON(http.StatusInternalServerError, rsp)
@@ -320,7 +344,7 @@ func SwarmConnectHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, rsp)
 }
 
-func SwarmDisconnectHandler(c *gin.Context) {
+func SwarmDisconnectHandler(c *gin.Context, env *Env) {
 	rsp := types.SwarmConnectResponse{
 		Code:    0,
 		Message: "ok",
@@ -341,7 +365,7 @@ func SwarmDisconnectHandler(c *gin.Context) {
 		return
 	}
 
-	if err := host.Hio.SwarmDisconnect(req.NodeAddr); err != nil {
+	if err := env.Host.SwarmDisconnect(req.NodeAddr); err != nil {
 		rsp.Code = int(types.ErrCodeInternal)
 		rsp.Message = err.Error()
 		c.JSON(http.StatusInternalServerError, rsp)
@@ -350,12 +374,12 @@ func SwarmDisconnectHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, rsp)
 }
 
-func PubsubPeersHandler(c *gin.Context) {
-	rsp := host.Hio.PubsubPeers()
+func PubsubPeersHandler(c *gin.Context, env *Env) {
+	rsp := env.Host.PubsubPeers()
 	c.JSON(http.StatusOK, rsp)
 }
 
-func RegisterAIProjectHandler(c *gin.Context, configPath string, publishChan chan<- []byte) {
+func RegisterAIProjectHandler(c *gin.Context, env *Env, configPath string, publishChan chan<- []byte) {
 	rsp := types.BaseHttpResponse{
 		Code:    0,
 		Message: "ok",
@@ -385,35 +409,35 @@ func RegisterAIProjectHandler(c *gin.Context, configPath string, publishChan cha
 		}
 	}
 
-	backup := make([]types.AIProjectConfig, len(config.GC.AIProjects))
-	copy(backup, config.GC.AIProjects)
+	backup := make([]types.AIProjectConfig, len(env.Config.AIProjects))
+	copy(backup, env.Config.AIProjects)
 
 	find := -1
-	for i := range config.GC.AIProjects {
-		if config.GC.AIProjects[i].Project == req.Project {
+	for i := range env.Config.AIProjects {
+		if env.Config.AIProjects[i].Project == req.Project {
 			find = i
 			break
 		}
 	}
 	if find == -1 {
-		config.GC.AIProjects = append(config.GC.AIProjects, req)
+		env.Config.AIProjects = append(env.Config.AIProjects, req)
 	} else {
-		config.GC.AIProjects[find].Models = req.Models
+		env.Config.AIProjects[find].Models = req.Models
 	}
 
-	if err := config.GC.SaveConfig(configPath); err != nil {
+	if err := env.Config.SaveConfig(configPath); err != nil {
 		rsp.Code = int(types.ErrCodeInternal)
 		rsp.Message = fmt.Sprintf("config save err %v", err)
 		c.JSON(http.StatusInternalServerError, rsp)
-		config.GC.AIProjects = backup
+		env.Config.AIProjects = backup
 		return
 	}
 	c.JSON(http.StatusOK, rsp)
-	model.RegisterAIProject(req)
-	timer.SendAIProjects(publishChan)
+	env.Models.RegisterAIProject(req)
+	timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
 }
 
-func UnregisterAIProjectHandler(c *gin.Context, configPath string, publishChan chan<- []byte) {
+func UnregisterAIProjectHandler(c *gin.Context, env *Env, configPath string, publishChan chan<- []byte) {
 	rsp := types.BaseHttpResponse{
 		Code:    0,
 		Message: "ok",
@@ -434,12 +458,12 @@ func UnregisterAIProjectHandler(c *gin.Context, configPath string, publishChan c
 		return
 	}
 
-	backup := make([]types.AIProjectConfig, len(config.GC.AIProjects))
-	copy(backup, config.GC.AIProjects)
+	backup := make([]types.AIProjectConfig, len(env.Config.AIProjects))
+	copy(backup, env.Config.AIProjects)
 
 	find := -1
-	for i := range config.GC.AIProjects {
-		if config.GC.AIProjects[i].Project == req.Project {
+	for i := range env.Config.AIProjects {
+		if env.Config.AIProjects[i].Project == req.Project {
 			find = i
 			break
 		}
@@ -449,21 +473,21 @@ func UnregisterAIProjectHandler(c *gin.Context, configPath string, publishChan c
 		c.JSON(http.StatusOK, rsp)
 		return
 	}
-	config.GC.AIProjects = append(config.GC.AIProjects[:find], config.GC.AIProjects[find+1:]...)
+	env.Config.AIProjects = append(env.Config.AIProjects[:find], env.Config.AIProjects[find+1:]...)
 
-	if err := config.GC.SaveConfig(configPath); err != nil {
+	if err := env.Config.SaveConfig(configPath); err != nil {
 		rsp.Code = int(types.ErrCodeInternal)
 		rsp.Message = fmt.Sprintf("config save err %v", err)
 		c.JSON(http.StatusInternalServerError, rsp)
-		config.GC.AIProjects = backup
+		env.Config.AIProjects = backup
 		return
 	}
 	c.JSON(http.StatusOK, rsp)
-	model.UnregisterAIProject(req.Project)
-	timer.SendAIProjects(publishChan)
+	env.Models.UnregisterAIProject(req.Project)
+	timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
 }
 
-func RegisterAIModelHandler(c *gin.Context, configPath string, publishChan chan<- []byte) {
+func RegisterAIModelHandler(c *gin.Context, env *Env, configPath string, publishChan chan<- []byte) {
 	rsp := types.BaseHttpResponse{
 		Code:    0,
 		Message: "ok",
@@ -484,12 +508,12 @@ func RegisterAIModelHandler(c *gin.Context, configPath string, publishChan chan<
 		return
 	}
 
-	backup := make([]types.AIProjectConfig, len(config.GC.AIProjects))
-	copy(backup, config.GC.AIProjects)
+	backup := make([]types.AIProjectConfig, len(env.Config.AIProjects))
+	copy(backup, env.Config.AIProjects)
 
 	pfind := -1
 	mfind := -1
-	for i, project := range config.GC.AIProjects {
+	for i, project := range env.Config.AIProjects {
 		if project.Project == req.Project {
 			pfind = i
 			for j, model := range project.Models {
@@ -502,49 +526,34 @@ func RegisterAIModelHandler(c *gin.Context, configPath string, publishChan chan<
 	}
 	if pfind == -1 {
 		models := make([]types.AIModelConfig, 0)
-		models = append(models, types.AIModelConfig{
-			Model: req.Model,
-			API:   req.API,
-			Type:  req.Type,
-			CID:   req.CID,
-		})
-		config.GC.AIProjects = append(config.GC.AIProjects, types.AIProjectConfig{
+		models = append(models, req.AIModelConfig)
+		env.Config.AIProjects = append(env.Config.AIProjects, types.AIProjectConfig{
 			Project: req.Project,
 			Models:  models,
 		})
 	} else if mfind == -1 {
-		models := config.GC.AIProjects[pfind].Models
-		models = append(models, types.AIModelConfig{
-			Model: req.Model,
-			API:   req.API,
-			Type:  req.Type,
-			CID:   req.CID,
-		})
-		config.GC.AIProjects[pfind].Models = models
+		models := env.Config.AIProjects[pfind].Models
+		models = append(models, req.AIModelConfig)
+		env.Config.AIProjects[pfind].Models = models
 	} else {
-		models := config.GC.AIProjects[pfind].Models
-		models[mfind] = types.AIModelConfig{
-			Model: req.Model,
-			API:   req.API,
-			Type:  req.Type,
-			CID:   req.CID,
-		}
-		config.GC.AIProjects[pfind].Models = models
+		models := env.Config.AIProjects[pfind].Models
+		models[mfind] = req.AIModelConfig
+		env.Config.AIProjects[pfind].Models = models
 	}
 
-	if err := config.GC.SaveConfig(configPath); err != nil {
+	if err := env.Config.SaveConfig(configPath); err != nil {
 		rsp.Code = int(types.ErrCodeInternal)
 		rsp.Message = fmt.Sprintf("config save err %v", err)
 		c.JSON(http.StatusInternalServerError, rsp)
-		config.GC.AIProjects = backup
+		env.Config.AIProjects = backup
 		return
 	}
 	c.JSON(http.StatusOK, rsp)
-	model.RegisterAIModel(req)
-	timer.SendAIProjects(publishChan)
+	env.Models.RegisterAIModel(req)
+	timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
 }
 
-func UnregisterAIModelHandler(c *gin.Context, configPath string, publishChan chan<- []byte) {
+func UnregisterAIModelHandler(c *gin.Context, env *Env, configPath string, publishChan chan<- []byte) {
 	rsp := types.BaseHttpResponse{
 		Code:    0,
 		Message: "ok",
@@ -565,12 +574,12 @@ func UnregisterAIModelHandler(c *gin.Context, configPath string, publishChan cha
 		return
 	}
 
-	backup := make([]types.AIProjectConfig, len(config.GC.AIProjects))
-	copy(backup, config.GC.AIProjects)
+	backup := make([]types.AIProjectConfig, len(env.Config.AIProjects))
+	copy(backup, env.Config.AIProjects)
 
 	pfind := -1
 	mfind := -1
-	for i, project := range config.GC.AIProjects {
+	for i, project := range env.Config.AIProjects {
 		if project.Project == req.Project {
 			pfind = i
 			for j, model := range project.Models {
@@ -588,26 +597,26 @@ func UnregisterAIModelHandler(c *gin.Context, configPath string, publishChan cha
 		return
 	}
 
-	models := config.GC.AIProjects[pfind].Models
+	models := env.Config.AIProjects[pfind].Models
 	models = append(models[:mfind], models[mfind+1:]...)
-	config.GC.AIProjects[pfind].Models = models
+	env.Config.AIProjects[pfind].Models = models
 	if len(models) == 0 {
-		config.GC.AIProjects = append(config.GC.AIProjects[:pfind], config.GC.AIProjects[pfind+1:]...)
+		env.Config.AIProjects = append(env.Config.AIProjects[:pfind], env.Config.AIProjects[pfind+1:]...)
 	}
 
-	if err := config.GC.SaveConfig(configPath); err != nil {
+	if err := env.Config.SaveConfig(configPath); err != nil {
 		rsp.Code = int(types.ErrCodeInternal)
 		rsp.Message = fmt.Sprintf("config save err %v", err)
 		c.JSON(http.StatusInternalServerError, rsp)
-		config.GC.AIProjects = backup
+		env.Config.AIProjects = backup
 		return
 	}
 	c.JSON(http.StatusOK, rsp)
-	model.UnregisterAIModel(req.Project, req.Model, req.CID)
-	timer.SendAIProjects(publishChan)
+	env.Models.UnregisterAIModel(req.Project, req.Model, req.CID)
+	timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
 }
 
-func GetAIProjectOfNodeHandler(c *gin.Context, publishChan chan<- []byte) {
+func GetAIProjectOfNodeHandler(c *gin.Context, env *Env, publishChan chan<- []byte) {
 	rsp := types.AIProjectListResponse{}
 
 	var msg types.AIProjectListRequest
@@ -625,8 +634,8 @@ func GetAIProjectOfNodeHandler(c *gin.Context, publishChan chan<- []byte) {
 		return
 	}
 
-	if msg.NodeID == config.GC.Identity.PeerID {
-		rsp.Data = model.GetAIProjects()
+	if msg.NodeID == env.Config.Identity.PeerID {
+		rsp.Data = env.Models.GetAIProjects()
 		c.JSON(http.StatusOK, rsp)
 		return
 	}
@@ -651,14 +660,13 @@ func GetAIProjectOfNodeHandler(c *gin.Context, publishChan chan<- []byte) {
 		c.JSON(http.StatusInternalServerError, rsp)
 		return
 	}
-	body, err = host.Encrypt(c.Request.Context(), msg.NodeID, body)
 
 	req := &protocol.Message{
 		Header: &protocol.MessageHeader{
-			ClientVersion: host.Hio.UserAgent,
+			ClientVersion: env.Host.UserAgent,
 			Timestamp:     time.Now().Unix(),
 			Id:            requestID.String(),
-			NodeId:        config.GC.Identity.PeerID,
+			NodeId:        env.Config.Identity.PeerID,
 			Receiver:      msg.NodeID,
 			NodePubKey:    nil,
 			Sign:          nil,
@@ -667,10 +675,8 @@ func GetAIProjectOfNodeHandler(c *gin.Context, publishChan chan<- []byte) {
 		Body:       body,
 		ResultCode: 0,
 	}
-	if err == nil {
-		req.Header.NodePubKey, _ = host.MarshalPubKeyFromPrivKey(host.Hio.PrivKey)
-	}
-	status, code, message := handleRequest(publishChan, req, &rsp, types.OrdinaryRequestTimeout)
+	env.Host.EncryptMessage(c.Request.Context(), req)
+	status, code, message := handleRequest(c.Request.Context(), env, publishChan, req, &rsp, types.OrdinaryRequestTimeout)
 	if code != 0 {
 		c.JSON(status, types.BaseHttpResponse{
 			Code:    code,
@@ -683,7 +689,40 @@ func GetAIProjectOfNodeHandler(c *gin.Context, publishChan chan<- []byte) {
 	}
 }
 
-func ListAIProjectsHandler(c *gin.Context) {
+func ModelHistoryHandler(c *gin.Context, store db.Store) {
+	rsp := types.ModelHistoryResponse{}
+
+	var req types.ModelHistoryRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if req.Number == 0 {
+		req.Number = 20
+	}
+
+	history, err := store.ListModelHistory(req.Number)
+	if err != nil {
+		rsp.Code = int(types.ErrCodeDatabase)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusInternalServerError, rsp)
+		return
+	}
+	rsp.Data = history
+	c.JSON(http.StatusOK, rsp)
+}
+
+func ListAIProjectsHandler(c *gin.Context, store db.Store) {
 	rsp := types.PeerListResponse{}
 
 	var req types.GetAIProjectsRequest
@@ -705,7 +744,7 @@ func ListAIProjectsHandler(c *gin.Context) {
 		req.Number = 100
 	}
 
-	rsp.Data, rsp.Code = db.ListAIProjects(req.Number)
+	rsp.Data, rsp.Code = store.ListAIProjects(req.Number)
 	if rsp.Code != 0 {
 		rsp.Message = types.ErrorCode(rsp.Code).String()
 		c.JSON(http.StatusInternalServerError, rsp)
@@ -714,7 +753,7 @@ func ListAIProjectsHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, rsp)
 }
 
-func GetModelsOfAIProjectHandler(c *gin.Context) {
+func GetModelsOfAIProjectHandler(c *gin.Context, store db.Store) {
 	rsp := types.PeerListResponse{}
 
 	var req types.GetModelsOfAIProjectRequest
@@ -736,7 +775,7 @@ func GetModelsOfAIProjectHandler(c *gin.Context) {
 		req.Number = 100
 	}
 
-	rsp.Data, rsp.Code = db.GetModelsOfAIProjects(req.Project, req.Number)
+	rsp.Data, rsp.Code = store.GetModelsOfAIProjects(req.Project, req.Number)
 	if rsp.Code != 0 {
 		rsp.Message = types.ErrorCode(rsp.Code).String()
 		c.JSON(http.StatusInternalServerError, rsp)
@@ -745,7 +784,7 @@ func GetModelsOfAIProjectHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, rsp)
 }
 
-func GetPeersOfAIProjectHandler(c *gin.Context) {
+func GetPeersOfAIProjectHandler(c *gin.Context, env *Env, store db.Store) {
 	rsp := types.GetPeersOfAIProjectResponse{
 		BaseHttpResponse: types.BaseHttpResponse{
 			Code:    0,
@@ -775,7 +814,7 @@ func GetPeersOfAIProjectHandler(c *gin.Context) {
 		req.Number = 100
 	}
 
-	ids, code := db.GetPeersOfAIProjects(req.Project, req.Model, req.Number)
+	ids, code := store.GetPeersOfAIProjects(req.Project, req.Model, req.Number)
 	if code != 0 {
 		rsp.Code = code
 		rsp.Message = types.ErrorCode(code).String()
@@ -783,25 +822,31 @@ func GetPeersOfAIProjectHandler(c *gin.Context) {
 		return
 	}
 	for id, mi := range ids {
-		rsp.Data = append(rsp.Data, types.AIProjectPeerInfo{
+		info := types.AIProjectPeerInfo{
 			NodeID:       id,
-			Connectivity: host.Hio.Connectedness(id),
-			Latency:      host.Hio.Latency(id).Microseconds(),
+			Connectivity: env.Host.Connectedness(id),
+			Latency:      env.Host.Latency(id).Microseconds(),
 			Idle:         mi.Idle,
 			CID:          mi.CID,
-		})
+			Type:         mi.Type,
+		}
+		pci := &db.PeerCollectInfo{}
+		if err := store.GetAIProjectsOfNode(id, pci); err == nil {
+			info.Metrics = pci.Metrics
+		}
+		rsp.Data = append(rsp.Data, info)
 	}
 	c.JSON(http.StatusOK, rsp)
 }
 
-func ListBootstrapHandler(c *gin.Context) {
+func ListBootstrapHandler(c *gin.Context, env *Env) {
 	rsp := types.PeerListResponse{
-		Data: config.GC.Bootstrap,
+		Data: env.Config.Bootstrap,
 	}
 	c.JSON(http.StatusOK, rsp)
 }
 
-func AddBootstrapHandler(c *gin.Context, configPath string) {
+func AddBootstrapHandler(c *gin.Context, env *Env, configPath string) {
 	rsp := types.SwarmConnectResponse{
 		Code:    0,
 		Message: "ok",
@@ -824,7 +869,7 @@ func AddBootstrapHandler(c *gin.Context, configPath string) {
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
 	defer cancel()
-	if err := host.Hio.SwarmConnectBootstrap(ctx, req.NodeAddr); err != nil {
+	if err := env.Host.SwarmConnectBootstrap(ctx, req.NodeAddr); err != nil {
 		rsp.Code = int(types.ErrCodeInternal)
 		rsp.Message = err.Error()
 		c.JSON(http.StatusInternalServerError, rsp)
@@ -832,7 +877,7 @@ func AddBootstrapHandler(c *gin.Context, configPath string) {
 	}
 
 	var find bool = false
-	for _, ps := range config.GC.Bootstrap {
+	for _, ps := range env.Config.Bootstrap {
 		if ps == req.NodeAddr {
 			find = true
 			break
@@ -843,22 +888,22 @@ func AddBootstrapHandler(c *gin.Context, configPath string) {
 		return
 	}
 
-	backup := make([]string, len(config.GC.Bootstrap))
-	copy(backup, config.GC.Bootstrap)
+	backup := make([]string, len(env.Config.Bootstrap))
+	copy(backup, env.Config.Bootstrap)
 
-	config.GC.Bootstrap = append(config.GC.Bootstrap, req.NodeAddr)
+	env.Config.Bootstrap = append(env.Config.Bootstrap, req.NodeAddr)
 
-	if err := config.GC.SaveConfig(configPath); err != nil {
+	if err := env.Config.SaveConfig(configPath); err != nil {
 		rsp.Code = int(types.ErrCodeInternal)
 		rsp.Message = fmt.Sprintf("config save err %v", err)
 		c.JSON(http.StatusInternalServerError, rsp)
-		config.GC.Bootstrap = backup
+		env.Config.Bootstrap = backup
 		return
 	}
 	c.JSON(http.StatusOK, rsp)
 }
 
-func RemoveBootstrapHandler(c *gin.Context, configPath string) {
+func RemoveBootstrapHandler(c *gin.Context, env *Env, configPath string) {
 	rsp := types.SwarmConnectResponse{
 		Code:    0,
 		Message: "ok",
@@ -879,7 +924,7 @@ func RemoveBootstrapHandler(c *gin.Context, configPath string) {
 		return
 	}
 
-	if err := host.Hio.SwarmDisconnectBootstrap(req.NodeAddr); err != nil {
+	if err := env.Host.SwarmDisconnectBootstrap(req.NodeAddr); err != nil {
 		rsp.Code = int(types.ErrCodeInternal)
 		rsp.Message = err.Error()
 		c.JSON(http.StatusInternalServerError, rsp)
@@ -887,7 +932,7 @@ func RemoveBootstrapHandler(c *gin.Context, configPath string) {
 	}
 
 	var find int = -1
-	for index, ps := range config.GC.Bootstrap {
+	for index, ps := range env.Config.Bootstrap {
 		if ps == req.NodeAddr {
 			find = index
 			break
@@ -898,16 +943,16 @@ func RemoveBootstrapHandler(c *gin.Context, configPath string) {
 		return
 	}
 
-	backup := make([]string, len(config.GC.Bootstrap))
-	copy(backup, config.GC.Bootstrap)
+	backup := make([]string, len(env.Config.Bootstrap))
+	copy(backup, env.Config.Bootstrap)
 
-	config.GC.Bootstrap = append(config.GC.Bootstrap[:find], config.GC.Bootstrap[find+1:]...)
+	env.Config.Bootstrap = append(env.Config.Bootstrap[:find], env.Config.Bootstrap[find+1:]...)
 
-	if err := config.GC.SaveConfig(configPath); err != nil {
+	if err := env.Config.SaveConfig(configPath); err != nil {
 		rsp.Code = int(types.ErrCodeInternal)
 		rsp.Message = fmt.Sprintf("config save err %v", err)
 		c.JSON(http.StatusInternalServerError, rsp)
-		config.GC.Bootstrap = backup
+		env.Config.Bootstrap = backup
 		return
 	}
 	c.JSON(http.StatusOK, rsp)
@@ -953,7 +998,7 @@ func RemoveBootstrapHandler(c *gin.Context, configPath string) {
 // 	// runtime.SetMutexProfileFraction(1)
 
 // 	httpServer = &http.Server{
-// 		Addr:         config.GC.API.Addr,
+// 		Addr:         env.Config.API.Addr,
 // 		Handler:      mux,
 // 		ReadTimeout:  120 * time.Second,
 // 		WriteTimeout: 120 * time.Second,
@@ -977,3 +1022,11 @@ func RemoveBootstrapHandler(c *gin.Context, configPath string) {
 // 		log.Logger.Info("HTTP server is shutdown gracefully")
 // 	}
 // }
+
+// SchedulerHandler returns the model requests running and queued on the worker.
+func SchedulerHandler(c *gin.Context, sched *scheduler.Scheduler) {
+	rsp := types.SchedulerResponse{
+		Data: sched.State(),
+	}
+	c.JSON(http.StatusOK, rsp)
+}
@@ -145,3 +145,28 @@ func TestChannel3(t *testing.T) {
 
 	wg.Wait()
 }
+
+// go test -v -timeout 30s -count=1 -run TestRequestItemCanceled AIComputingNode/pkg/serve
+func TestRequestItemCanceled(t *testing.T) {
+	requests := NewRequestQueue()
+	notifyChan := make(chan []byte, 1)
+	requests.AddRequestItem("canceled", notifyChan)
+	requests.DeleteRequestItem("canceled")
+	if requests.ExistRequestItem("canceled") {
+		t.Fatal("Canceled request is kept")
+	}
+	if _, ok := <-notifyChan; ok {
+		t.Fatal("Channel of the canceled request is not closed")
+	}
+	// the late response neither writes to the closed channel nor panics
+	requests.WriteAndDeleteRequestItem("canceled", []byte("late"))
+	requests.DeleteRequestItem("canceled")
+
+	notifyChan = make(chan []byte, 1)
+	requests.AddRequestItem("answered", notifyChan)
+	requests.WriteAndDeleteRequestItem("answered", []byte("response"))
+	requests.DeleteRequestItem("answered")
+	if data, ok := <-notifyChan; !ok || string(data) != "response" {
+		t.Fatalf("Unexpected response %s %v", data, ok)
+	}
+}
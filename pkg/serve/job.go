@@ -0,0 +1,465 @@
+package serve
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"syscall"
+	"time"
+
+	"AIComputingNode/pkg/blob"
+	"AIComputingNode/pkg/db"
+	"AIComputingNode/pkg/log"
+	"AIComputingNode/pkg/types"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	jobWebhookAttempts = 3
+	jobWebhookTimeout  = 10 * time.Second
+)
+
+// JobManager runs the asynchronous jobs in the background and keeps them in the datastore,
+// so that the results survive client reconnects and the unfinished jobs are resumed when the node restarts.
+type JobManager struct {
+	ctx         context.Context
+	env         *Env
+	publishChan chan<- []byte
+	store       db.Store
+	blobs       *blob.Store
+	webhooks    *http.Client
+	// Semaphore of the running jobs
+	running chan struct{}
+	wg      sync.WaitGroup
+
+	mutex    sync.Mutex
+	watchers map[string]map[chan types.Job]struct{}
+}
+
+// NewJobManager returns the manager of the jobs, which are stopped when the context is canceled.
+func NewJobManager(ctx context.Context, env *Env, publishChan chan<- []byte, store db.Store, blobs *blob.Store) *JobManager {
+	cfg := env.Config.App.Jobs
+	return &JobManager{
+		ctx:         ctx,
+		env:         env,
+		publishChan: publishChan,
+		store:       store,
+		blobs:       blobs,
+		webhooks:    newWebhookClient(cfg.WebhookAllowedHosts),
+		running:     make(chan struct{}, cfg.MaxRunning),
+		watchers:    make(map[string]map[chan types.Job]struct{}),
+	}
+}
+
+// newWebhookClient returns the client posting to the webhooks, which only connects to the public addresses
+// unless the host of the webhook is allowed. The addresses are checked when they are dialed, so that a host
+// name is not resolved to another address after the check, and the redirects are not followed.
+func newWebhookClient(allowedHosts []string) *http.Client {
+	allowed := make(map[string]bool, len(allowedHosts))
+	for _, host := range allowedHosts {
+		allowed[host] = true
+	}
+	dialer := &net.Dialer{Timeout: jobWebhookTimeout}
+	publicDialer := &net.Dialer{
+		Timeout: jobWebhookTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil {
+				return err
+			}
+			if !publicIP(addrPort.Addr()) {
+				return fmt.Errorf("webhook address %s is not public", addrPort.Addr())
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(address); err == nil && allowed[host] {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return publicDialer.DialContext(ctx, network, address)
+	}
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// nonPublicPrefixes are the special-purpose address blocks of IANA which are not reachable
+// on the internet, or which reach the addresses of another block through a translator.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // this network
+	netip.MustParsePrefix("10.0.0.0/8"),      // private
+	netip.MustParsePrefix("100.64.0.0/10"),   // shared address space of CGNAT
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link local
+	netip.MustParsePrefix("172.16.0.0/12"),   // private
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay anycast
+	netip.MustParsePrefix("192.168.0.0/16"),  // private
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved and broadcast
+	netip.MustParsePrefix("::/96"),           // unspecified, loopback and IPv4-compatible
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local NAT64
+	netip.MustParsePrefix("100::/64"),        // discard only
+	netip.MustParsePrefix("2001::/23"),       // IETF protocol assignments, including Teredo
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("3fff::/20"),       // documentation
+	netip.MustParsePrefix("fc00::/7"),        // unique local
+	netip.MustParsePrefix("fe80::/10"),       // link local
+	netip.MustParsePrefix("fec0::/10"),       // site local
+	netip.MustParsePrefix("ff00::/8"),        // multicast
+}
+
+// publicIP reports whether the address is a public unicast address, the IPv4-mapped IPv6 addresses
+// are checked as the IPv4 addresses, and the zones are ignored since the prefixes never contain them.
+func publicIP(addr netip.Addr) bool {
+	if !addr.IsValid() {
+		return false
+	}
+	addr = addr.Unmap().WithZone("")
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Resume runs the jobs which were queued or running when the node stopped,
+// and posts the finished jobs to the webhooks which have not received them.
+func (jm *JobManager) Resume() {
+	jobs, err := jm.store.ListPendingJobs()
+	if err != nil {
+		log.Logger.Errorf("List pending jobs failed %v", err)
+		return
+	}
+	for _, job := range jobs {
+		log.Logger.Infof("Resume %s job %s", job.Type, job.ID)
+		jm.start(job)
+	}
+}
+
+// Wait waits for the jobs to stop after the context of the manager is canceled,
+// the stopped jobs stay unfinished in the datastore.
+func (jm *JobManager) Wait() {
+	jm.wg.Wait()
+}
+
+// Submit saves the job of the request and runs it in the background.
+func (jm *JobManager) Submit(req types.JobRequest) (types.Job, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return types.Job{}, err
+	}
+	now := time.Now().Unix()
+	job := types.Job{
+		ID:        id.String(),
+		Type:      req.Type,
+		Status:    types.JobStatusQueued,
+		Progress:  types.JobProgressQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Webhook:   req.Webhook,
+		Request:   req.Request,
+	}
+	if err := jm.store.PutJob(&job); err != nil {
+		return job, err
+	}
+	jm.start(job)
+	return job, nil
+}
+
+func (jm *JobManager) Get(id string) (types.Job, error) {
+	return jm.store.GetJob(id)
+}
+
+// Watch returns the channel of the updates of the job, and the function to stop watching.
+func (jm *JobManager) Watch(id string) (<-chan types.Job, func()) {
+	// a job is updated at most 3 times, the updates are never blocked
+	ch := make(chan types.Job, 4)
+	jm.mutex.Lock()
+	if jm.watchers[id] == nil {
+		jm.watchers[id] = make(map[chan types.Job]struct{})
+	}
+	jm.watchers[id][ch] = struct{}{}
+	jm.mutex.Unlock()
+	return ch, func() {
+		jm.mutex.Lock()
+		delete(jm.watchers[id], ch)
+		if len(jm.watchers[id]) == 0 {
+			delete(jm.watchers, id)
+		}
+		jm.mutex.Unlock()
+	}
+}
+
+func (jm *JobManager) start(job types.Job) {
+	jm.wg.Add(1)
+	go func() {
+		defer jm.wg.Done()
+		jm.run(job)
+	}()
+}
+
+func (jm *JobManager) run(job types.Job) {
+	if !job.Finished() && !jm.runRequest(&job) {
+		return
+	}
+	if job.Webhook != "" && !job.WebhookDelivered && jm.postWebhook(job) {
+		job.WebhookDelivered = true
+		jm.update(&job)
+	}
+}
+
+// runRequest runs the request of the job and saves the result,
+// it returns false if the job is stopped by the context of the manager.
+func (jm *JobManager) runRequest(job *types.Job) bool {
+	select {
+	case jm.running <- struct{}{}:
+	case <-jm.ctx.Done():
+		return false
+	}
+	defer func() {
+		<-jm.running
+	}()
+
+	job.Status = types.JobStatusRunning
+	job.Progress = types.JobProgressRunning
+	jm.update(job)
+
+	result, succeeded := jm.execute(*job)
+	if jm.ctx.Err() != nil {
+		log.Logger.Infof("Stop %s job %s, which is resumed when the node restarts", job.Type, job.ID)
+		return false
+	}
+	job.Result = result
+	job.Status = types.JobStatusFailed
+	if succeeded {
+		job.Status = types.JobStatusSucceeded
+	}
+	job.Progress = types.JobProgressFinished
+	jm.update(job)
+	log.Logger.Infof("Finish %s job %s with status %s", job.Type, job.ID, job.Status)
+	return true
+}
+
+// execute sends the request of the job and returns the response of the synchronous API.
+func (jm *JobManager) execute(job types.Job) (json.RawMessage, bool) {
+	ctx, cancel := context.WithTimeout(jm.ctx, types.ImageGenerationRequestTimeout)
+	defer cancel()
+
+	rsp := types.ImageGenerationResponse{}
+	var code int
+	var message string
+	switch job.Type {
+	case types.JobTypeImageGen:
+		var req types.ImageGenerationRequest
+		if err := json.Unmarshal(job.Request, &req); err != nil {
+			code, message = int(types.ErrCodeParse), types.ErrCodeParse.String()
+			break
+		}
+		_, code, message = handleImageGenRequest(ctx, jm.env, jm.publishChan, jm.blobs, req, &rsp)
+	case types.JobTypeImageGenProxy:
+		var req types.ImageGenerationProxyRequest
+		if err := json.Unmarshal(job.Request, &req); err != nil {
+			code, message = int(types.ErrCodeParse), types.ErrCodeParse.String()
+			break
+		}
+		var igReq types.ImageGenerationRequest
+		if igReq, code, message = imageGenProxyPeer(jm.env, jm.store, req); code == 0 {
+			_, code, message = handleImageGenRequest(ctx, jm.env, jm.publishChan, jm.blobs, igReq, &rsp)
+		}
+	default:
+		code, message = int(types.ErrCodeUnsupported), types.ErrCodeUnsupported.String()
+	}
+
+	var result any = rsp
+	if code != 0 {
+		result = types.BaseHttpResponse{
+			Code:    code,
+			Message: message,
+		}
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Logger.Errorf("Marshal result of job %s failed %v", job.ID, err)
+		return nil, false
+	}
+	return data, code == 0 && rsp.Code == 0
+}
+
+// update saves the job and sends it to the watchers.
+func (jm *JobManager) update(job *types.Job) {
+	job.UpdatedAt = time.Now().Unix()
+	if err := jm.store.PutJob(job); err != nil {
+		log.Logger.Errorf("Update job %s failed %v", job.ID, err)
+	}
+	jm.mutex.Lock()
+	defer jm.mutex.Unlock()
+	for ch := range jm.watchers[job.ID] {
+		select {
+		case ch <- *job:
+		default:
+		}
+	}
+}
+
+// postWebhook posts the finished job to its webhook, and reports whether it is delivered.
+func (jm *JobManager) postWebhook(job types.Job) bool {
+	body, err := json.Marshal(job)
+	if err != nil {
+		log.Logger.Errorf("Marshal job %s failed %v", job.ID, err)
+		return false
+	}
+	for attempt := 0; attempt < jobWebhookAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(time.Duration(attempt) * time.Second):
+			case <-jm.ctx.Done():
+				return false
+			}
+		}
+		if err = jm.sendWebhook(job.Webhook, body); err == nil {
+			log.Logger.Infof("Post job %s to webhook %s success", job.ID, job.Webhook)
+			return true
+		}
+		log.Logger.Warnf("Post job %s to webhook %s failed in %d time %v", job.ID, job.Webhook, attempt, err)
+	}
+	return false
+}
+
+func (jm *JobManager) sendWebhook(webhook string, body []byte) error {
+	ctx, cancel := context.WithTimeout(jm.ctx, jobWebhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := jm.webhooks.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("response status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func JobSubmitHandler(c *gin.Context, jobs *JobManager) {
+	rsp := types.JobResponse{}
+
+	var msg types.JobRequest
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if err := msg.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if msg.Type == types.JobTypeImageGenProxy && !jobs.env.Config.App.PeersCollect.Enabled {
+		rsp.Code = int(types.ErrCodeUnsupported)
+		rsp.Message = types.ErrCodeUnsupported.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	job, err := jobs.Submit(msg)
+	if err != nil {
+		rsp.Code = int(types.ErrCodeDatabase)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusInternalServerError, rsp)
+		return
+	}
+	rsp.Data = &job
+	c.JSON(http.StatusOK, rsp)
+}
+
+// getJob loads the job of the id parameter, or writes the error response.
+func getJob(c *gin.Context, jobs *JobManager) (types.Job, bool) {
+	job, err := jobs.Get(c.Param("id"))
+	if errors.Is(err, db.ErrJobNotFound) {
+		c.JSON(http.StatusNotFound, types.BaseHttpResponse{
+			Code:    int(types.ErrCodeNotFound),
+			Message: types.ErrCodeNotFound.String(),
+		})
+		return job, false
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, types.BaseHttpResponse{
+			Code:    int(types.ErrCodeDatabase),
+			Message: err.Error(),
+		})
+		return job, false
+	}
+	return job, true
+}
+
+func JobHandler(c *gin.Context, jobs *JobManager) {
+	job, ok := getJob(c, jobs)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, types.JobResponse{Data: &job})
+}
+
+// JobEventsHandler sends the job and its updates as server-sent "progress" events,
+// until the job is finished.
+func JobEventsHandler(c *gin.Context, jobs *JobManager) {
+	// watch before loading the job, so that no update is missed
+	updates, stop := jobs.Watch(c.Param("id"))
+	defer stop()
+	job, ok := getJob(c, jobs)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Status(http.StatusOK)
+	for {
+		data, err := json.Marshal(job)
+		if err != nil {
+			log.Logger.Errorf("Marshal job %s failed %v", job.ID, err)
+			return
+		}
+		if _, err := fmt.Fprintf(c.Writer, "event: progress\ndata: %s\n\n", data); err != nil {
+			return
+		}
+		c.Writer.Flush()
+		if job.Finished() {
+			return
+		}
+		select {
+		case job = <-updates:
+		case <-c.Request.Context().Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package serve
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/db"
+	"AIComputingNode/pkg/types"
+)
+
+// go test -v -timeout 30s -count=1 -run TestWebhookClient AIComputingNode/pkg/serve
+func TestWebhookClient(t *testing.T) {
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/hook", http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer hook.Close()
+	u, _ := url.Parse(hook.URL)
+
+	if _, err := newWebhookClient(nil).Post(hook.URL+"/hook", "application/json", nil); err == nil {
+		t.Error("Webhook of the loopback address is posted")
+	}
+	// the host name is checked by the resolved address
+	localhost := "http://localhost:" + u.Port() + "/hook"
+	if _, err := newWebhookClient(nil).Post(localhost, "application/json", nil); err == nil {
+		t.Error("Webhook of localhost is posted")
+	}
+
+	client := newWebhookClient([]string{u.Hostname()})
+	resp, err := client.Post(hook.URL+"/hook", "application/json", nil)
+	if err != nil || resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("Webhook of the allowed host is not posted %v %v", resp, err)
+	}
+	resp.Body.Close()
+	resp, err = client.Post(hook.URL+"/redirect", "application/json", nil)
+	if err != nil || resp.StatusCode != http.StatusFound {
+		t.Fatalf("Redirect of the webhook is followed %v %v", resp, err)
+	}
+	resp.Body.Close()
+
+	for _, ip := range []string{
+		"10.0.0.1", "172.16.0.1", "192.168.1.1", "169.254.169.254", "127.0.0.1", "0.0.0.0", "0.1.2.3",
+		"100.64.0.1", "100.127.255.254", "192.0.0.1", "192.0.2.1", "198.18.0.1", "198.19.255.255",
+		"198.51.100.1", "203.0.113.1", "224.0.0.1", "255.255.255.255",
+		"::", "::1", "::a00:1", "::ffff:127.0.0.1", "::ffff:10.0.0.1", "::ffff:100.64.0.1", "64:ff9b::a00:1",
+		"64:ff9b:1::1", "100::1", "2001::1", "2001:db8::1", "2002:a00:1::1", "3fff::1", "fc00::1", "fd00::1",
+		"fe80::1", "fe80::1%eth0", "fec0::1", "ff02::1",
+	} {
+		if publicIP(netip.MustParseAddr(ip)) {
+			t.Errorf("Address %s is public", ip)
+		}
+	}
+	for _, ip := range []string{"8.8.8.8", "100.128.0.1", "198.20.0.1", "::ffff:8.8.8.8", "2001:4860:4860::8888"} {
+		if !publicIP(netip.MustParseAddr(ip)) {
+			t.Errorf("Address %s is not public", ip)
+		}
+	}
+	if publicIP(netip.Addr{}) {
+		t.Error("Invalid address is public")
+	}
+}
+
+// go test -v -timeout 30s -count=1 -run TestResumeWebhook AIComputingNode/pkg/serve
+func TestResumeWebhook(t *testing.T) {
+	posted := make(chan types.Job, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var job types.Job
+		json.NewDecoder(r.Body).Decode(&job)
+		posted <- job
+	}))
+	defer hook.Close()
+	u, _ := url.Parse(hook.URL)
+
+	store := db.NewMemoryStore(false)
+	now := time.Now().Unix()
+	// the node stopped after the job finished but before its webhook was delivered
+	job := types.Job{
+		ID:        "finished",
+		Type:      types.JobTypeImageGen,
+		Status:    types.JobStatusSucceeded,
+		Progress:  types.JobProgressFinished,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Webhook:   hook.URL,
+	}
+	if err := store.PutJob(&job); err != nil {
+		t.Fatalf("Put job failed %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	env := NewEnv(&config.Config{}, nil, nil)
+	env.Config.App.Jobs = config.AppJobsConfig{MaxRunning: 1, WebhookAllowedHosts: []string{u.Hostname()}}
+	jm := NewJobManager(ctx, env, nil, store, nil)
+	jm.Resume()
+	select {
+	case got := <-posted:
+		if got.ID != job.ID || got.Status != types.JobStatusSucceeded {
+			t.Errorf("Unexpected webhook job %+v", got)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for the webhook")
+	}
+	for deadline := time.Now().Add(5 * time.Second); ; {
+		if saved, err := store.GetJob(job.ID); err == nil && saved.WebhookDelivered {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("Webhook of the resumed job is not saved as delivered %+v %v", saved, err)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	cancel()
+	jm.Wait()
+
+	if pending, err := store.ListPendingJobs(); err != nil || len(pending) != 0 {
+		t.Errorf("Unexpected pending jobs %v %v", pending, err)
+	}
+}
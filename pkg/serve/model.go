@@ -12,9 +12,8 @@ import (
 	"sort"
 	"time"
 
-	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/blob"
 	"AIComputingNode/pkg/db"
-	"AIComputingNode/pkg/libp2p/host"
 	"AIComputingNode/pkg/log"
 	"AIComputingNode/pkg/model"
 	"AIComputingNode/pkg/protocol"
@@ -26,19 +25,19 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-func handleChatCompletionRequest(ctx context.Context, publishChan chan<- []byte, req *types.ChatCompletionRequest, rsp *types.ChatCompletionResponse) (int, int, string) {
-	if req.NodeID == config.GC.Identity.PeerID {
-		mi, err := model.GetModelInfo(req.Project, req.Model, req.CID)
+func handleChatCompletionRequest(ctx context.Context, env *Env, publishChan chan<- []byte, req *types.ChatCompletionRequest, rsp *types.ChatCompletionResponse) (int, int, string) {
+	if req.NodeID == env.Config.Identity.PeerID {
+		mi, err := env.Models.GetModelInfo(req.Project, req.Model, req.CID)
 		if err != nil {
 			return http.StatusInternalServerError, int(types.ErrCodeModel), err.Error()
 		}
-		model.IncRef(req.Project, req.Model, mi.CID)
-		timer.SendAIProjects(publishChan)
+		env.Models.IncRef(req.Project, req.Model, mi.CID)
+		timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
 		defer func() {
-			model.DecRef(req.Project, req.Model, mi.CID)
-			timer.SendAIProjects(publishChan)
+			env.Models.DecRef(req.Project, req.Model, mi.CID)
+			timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
 		}()
-		*rsp = *model.ChatModel(mi.API, req.ChatModelRequest)
+		*rsp = *model.ChatModel(ctx, mi.AIModelConfig, req.ChatModelRequest)
 		log.Logger.Infof("Execute model %s result {code:%d, message:%s}", req.Model, rsp.Code, rsp.Message)
 		return http.StatusOK, rsp.Code, rsp.Message
 	}
@@ -48,47 +47,22 @@ func handleChatCompletionRequest(ctx context.Context, publishChan chan<- []byte,
 		return http.StatusInternalServerError, int(types.ErrCodeUUID), err.Error()
 	}
 
-	ccms := make([]*protocol.ChatCompletionMessage, 0)
-	for _, ccm := range req.Messages {
-		ccms = append(ccms, &protocol.ChatCompletionMessage{
-			Role:    ccm.Role,
-			Content: ccm.Content,
-		})
-	}
-
 	pi := &protocol.ChatCompletionBody{
 		Data: &protocol.ChatCompletionBody_Req{
-			Req: &protocol.ChatCompletionRequest{
-				Project:  req.Project,
-				Model:    req.Model,
-				Messages: ccms,
-				Stream:   false,
-				Wallet: &protocol.WalletVerification{
-					Wallet:    req.Wallet,
-					Signature: req.Signature,
-					Hash:      req.Hash,
-				},
-				Cid:         req.CID,
-				Temperature: req.Temperature,
-				TopP:        req.TopP,
-			},
+			Req: types.ChatModelRequest2ProtocolMessage(req.Project, req.CID, &req.ChatModelRequest),
 		},
 	}
 	body, err := proto.Marshal(pi)
 	if err != nil {
 		return http.StatusInternalServerError, int(types.ErrCodeProtobuf), err.Error()
 	}
-	body, err = host.Encrypt(ctx, req.NodeID, body)
-	if err != nil {
-		return http.StatusInternalServerError, int(types.ErrCodeEncrypt), types.ErrCodeEncrypt.String()
-	}
 
 	msg := &protocol.Message{
 		Header: &protocol.MessageHeader{
-			ClientVersion: host.Hio.UserAgent,
+			ClientVersion: env.Host.UserAgent,
 			Timestamp:     time.Now().Unix(),
 			Id:            requestID.String(),
-			NodeId:        config.GC.Identity.PeerID,
+			NodeId:        env.Config.Identity.PeerID,
 			Receiver:      req.NodeID,
 			NodePubKey:    nil,
 			Sign:          nil,
@@ -97,36 +71,22 @@ func handleChatCompletionRequest(ctx context.Context, publishChan chan<- []byte,
 		Body:       body,
 		ResultCode: 0,
 	}
-	msg.Header.NodePubKey, _ = host.MarshalPubKeyFromPrivKey(host.Hio.PrivKey)
-	return handleRequest(publishChan, msg, rsp, types.ChatCompletionRequestTimeout)
+	if err := env.Host.EncryptMessage(ctx, msg); err != nil {
+		return http.StatusInternalServerError, int(types.ErrCodeEncrypt), types.ErrCodeEncrypt.String()
+	}
+	return handleRequest(ctx, env, publishChan, msg, rsp, types.ChatCompletionRequestTimeout)
 }
 
-func handleChatCompletionStreamRequest(ctx context.Context, w http.ResponseWriter, req *types.ChatCompletionRequest, rsp *types.ChatCompletionResponse) (int, int, string) {
-	if req.NodeID == config.GC.Identity.PeerID {
-		mi, err := model.GetModelInfo(req.Project, req.Model, req.CID)
+func handleChatCompletionStreamRequest(ctx context.Context, env *Env, w http.ResponseWriter, req *types.ChatCompletionRequest, rsp *types.ChatCompletionResponse) (int, int, string) {
+	if req.NodeID == env.Config.Identity.PeerID {
+		mi, err := env.Models.GetModelInfo(req.Project, req.Model, req.CID)
 		log.Logger.Info("Received chat completion stream request from the node itself")
 		if err != nil {
 			return http.StatusInternalServerError, int(types.ErrCodeModel), err.Error()
 		}
 
-		jsonData, err := json.Marshal(req.ChatModelRequest)
-		if err != nil {
-			// rsp.Code = int(types.ErrCodeModel)
-			// rsp.Message = "Marshal model request body failed"
-			log.Logger.Errorf("Marshal model request body failed: %v", err)
-			return http.StatusInternalServerError, int(types.ErrCodeModel), "Marshal model request body failed"
-		}
-		hreq, err := http.NewRequestWithContext(ctx, "POST", mi.API, bytes.NewBuffer(jsonData))
-		if err != nil {
-			// rsp.Code = int(types.ErrCodeModel)
-			// rsp.Message = "Create http request for stream failed"
-			log.Logger.Errorf("Create http request for stream failed: %v", err)
-			return http.StatusInternalServerError, int(types.ErrCodeModel), "Create http request for stream failed"
-		}
-		hreq.Header.Set("Content-Type", "application/json")
-
-		log.Logger.Infof("Making request to %s", hreq.URL)
-		resp, err := http.DefaultTransport.RoundTrip(hreq)
+		log.Logger.Infof("Making request to %s", mi.API)
+		resp, err := model.ChatModelStream(ctx, mi.AIModelConfig, req.ChatModelRequest)
 		if err != nil {
 			// rsp.Code = int(types.ErrCodeModel)
 			// rsp.Message = fmt.Sprintf("RoundTrip chat request failed: %v", err)
@@ -194,7 +154,7 @@ func handleChatCompletionStreamRequest(ctx context.Context, w http.ResponseWrite
 	queryValues.Add("cid", req.CID)
 	hreq.URL.RawQuery = queryValues.Encode()
 
-	stream, err := host.Hio.NewStream(ctx, req.NodeID)
+	stream, err := env.Host.NewStream(ctx, req.NodeID)
 	if err != nil {
 		// rsp.Code = int(types.ErrCodeStream)
 		// rsp.Message = "Open stream with peer node failed"
@@ -203,6 +163,7 @@ func handleChatCompletionStreamRequest(ctx context.Context, w http.ResponseWrite
 	}
 	stream.SetDeadline(time.Now().Add(types.ChatCompletionRequestTimeout))
 	defer stream.Close()
+	defer resetOnCancel(ctx, stream)()
 	log.Logger.Infof("Create libp2p stream with %s success", req.NodeID)
 
 	err = hreq.Write(stream)
@@ -265,7 +226,7 @@ func handleChatCompletionStreamRequest(ctx context.Context, w http.ResponseWrite
 	return http.StatusOK, 0, ""
 }
 
-func ChatCompletionHandler(c *gin.Context, publishChan chan<- []byte) {
+func ChatCompletionHandler(c *gin.Context, env *Env, publishChan chan<- []byte, cache *ResponseCache) {
 	rsp := types.ChatCompletionResponse{}
 
 	var msg types.ChatCompletionRequest
@@ -283,8 +244,13 @@ func ChatCompletionHandler(c *gin.Context, publishChan chan<- []byte) {
 		return
 	}
 
+	key, hit := cache.lookup(c, msg.Project, chatCacheKey(msg.Project, msg.CID, msg.ChatModelRequest))
+	if hit {
+		return
+	}
+
 	if !msg.Stream {
-		status, code, message := handleChatCompletionRequest(c.Request.Context(), publishChan, &msg, &rsp)
+		status, code, message := handleChatCompletionRequest(c.Request.Context(), env, publishChan, &msg, &rsp)
 		if code != 0 {
 			c.JSON(status, types.BaseHttpResponse{
 				Code:    code,
@@ -293,6 +259,7 @@ func ChatCompletionHandler(c *gin.Context, publishChan chan<- []byte) {
 		} else if rsp.Code != 0 {
 			c.JSON(http.StatusInternalServerError, rsp)
 		} else {
+			cache.store(key, rsp)
 			c.JSON(http.StatusOK, rsp)
 		}
 		return
@@ -300,7 +267,7 @@ func ChatCompletionHandler(c *gin.Context, publishChan chan<- []byte) {
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), types.ChatCompletionRequestTimeout)
 	defer cancel()
-	status, code, message := handleChatCompletionStreamRequest(ctx, c.Writer, &msg, &rsp)
+	status, code, message := handleChatCompletionStreamRequest(ctx, env, c.Writer, &msg, &rsp)
 	if code != 0 {
 		c.JSON(status, types.BaseHttpResponse{
 			Code:    code,
@@ -311,10 +278,10 @@ func ChatCompletionHandler(c *gin.Context, publishChan chan<- []byte) {
 	}
 }
 
-func ChatCompletionProxyHandler(c *gin.Context, publishChan chan<- []byte) {
+func ChatCompletionProxyHandler(c *gin.Context, env *Env, publishChan chan<- []byte, store db.Store, cache *ResponseCache) {
 	rsp := types.ChatCompletionResponse{}
 
-	if !config.GC.App.PeersCollect.Enabled {
+	if !env.Config.App.PeersCollect.Enabled {
 		rsp.Code = int(types.ErrCodeUnsupported)
 		rsp.Message = types.ErrCodeUnsupported.String()
 		c.JSON(http.StatusBadRequest, rsp)
@@ -336,7 +303,13 @@ func ChatCompletionProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 		return
 	}
 
-	ids, code := db.GetPeersOfAIProjects(msg.Project, msg.Model, 20)
+	// the proxy requests share the responses of all the nodes of the model
+	key, hit := cache.lookup(c, msg.Project, chatCacheKey(msg.Project, "", msg.ChatModelRequest))
+	if hit {
+		return
+	}
+
+	ids, code := store.GetPeersOfAIProjects(msg.Project, msg.Model, 20)
 	if code != 0 {
 		rsp.Code = int(types.ErrCodeProxy)
 		rsp.Message = types.ErrorCode(code).String()
@@ -346,11 +319,12 @@ func ChatCompletionProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 
 	peers := []types.AIProjectPeerInfo{}
 	for id, mi := range ids {
-		conn := host.Hio.Connectedness(id)
-		if msg.Stream && conn != 1 {
+		// the relayed peers can serve streams too, with a higher latency
+		if msg.Stream && !env.Host.CanStream(id) {
 			continue
 		}
-		latency := host.Hio.Latency(id).Nanoseconds()
+		conn := env.Host.Connectedness(id)
+		latency := env.Host.StreamLatency(id).Nanoseconds()
 		if msg.Stream && latency == 0 {
 			continue
 		}
@@ -360,11 +334,12 @@ func ChatCompletionProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 			Latency:      latency,
 			Idle:         mi.Idle,
 			CID:          mi.CID,
+			Type:         mi.Type,
 		})
 	}
 	if len(peers) == 0 {
 		rsp.Code = int(types.ErrCodeProxy)
-		rsp.Message = "Not enough available and directly connected nodes"
+		rsp.Message = "Not enough available and connected nodes"
 		c.JSON(http.StatusInternalServerError, rsp)
 		return
 	}
@@ -379,7 +354,7 @@ func ChatCompletionProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 	}
 
 	if !msg.Stream {
-		status, code, message := handleChatCompletionRequest(c.Request.Context(), publishChan, &chatReq, &rsp)
+		status, code, message := handleChatCompletionRequest(c.Request.Context(), env, publishChan, &chatReq, &rsp)
 		if code != 0 {
 			c.JSON(status, types.BaseHttpResponse{
 				Code:    code,
@@ -388,6 +363,7 @@ func ChatCompletionProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 		} else if rsp.Code != 0 {
 			c.JSON(http.StatusInternalServerError, rsp)
 		} else {
+			cache.store(key, rsp)
 			c.JSON(http.StatusOK, rsp)
 		}
 		return
@@ -395,7 +371,7 @@ func ChatCompletionProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), types.ChatCompletionRequestTimeout)
 	defer cancel()
-	status, code, message := handleChatCompletionStreamRequest(ctx, c.Writer, &chatReq, &rsp)
+	status, code, message := handleChatCompletionStreamRequest(ctx, env, c.Writer, &chatReq, &rsp)
 	if code != 0 {
 		c.JSON(status, types.BaseHttpResponse{
 			Code:    code,
@@ -419,7 +395,7 @@ func ChatCompletionProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 	   		if msg.Stream {
 	   			ctx, cancel := context.WithTimeout(c.Request.Context(), types.ChatCompletionRequestTimeout)
 	   			defer cancel()
-	   			status, code, message := handleChatCompletionStreamRequest(ctx, c.Writer, &chatReq, &rsp)
+	   			status, code, message := handleChatCompletionStreamRequest(ctx, env, c.Writer, &chatReq, &rsp)
 	   			if code != 0 {
 	   				log.Logger.Warnf("Roundtrip chat completion proxy %v %v %v to %s in %d time", status, code, message, peer.NodeID, failed_count)
 	   				failed_count += 1
@@ -433,7 +409,7 @@ func ChatCompletionProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 	   				return
 	   			}
 	   		}
-	   		status, code, message := handleChatCompletionRequest(c.Request.Context(), publishChan, &chatReq, &rsp)
+	   		status, code, message := handleChatCompletionRequest(c.Request.Context(), env, publishChan, &chatReq, &rsp)
 	   		if code != 0 {
 	   			log.Logger.Warnf("Roundtrip chat completion proxy %v %v %v to %s in %d time", status, code, message, peer.NodeID, failed_count)
 	   			failed_count += 1
@@ -455,20 +431,23 @@ func ChatCompletionProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 	*/
 }
 
-func handleImageGenRequest(ctx context.Context, publishChan chan<- []byte, req types.ImageGenerationRequest, rsp *types.ImageGenerationResponse) (int, int, string) {
-	if req.NodeID == config.GC.Identity.PeerID {
-		mi, err := model.GetModelInfo(req.Project, req.Model, req.CID)
+func handleImageGenRequest(ctx context.Context, env *Env, publishChan chan<- []byte, blobs *blob.Store, req types.ImageGenerationRequest, rsp *types.ImageGenerationResponse) (int, int, string) {
+	if req.NodeID == env.Config.Identity.PeerID {
+		mi, err := env.Models.GetModelInfo(req.Project, req.Model, req.CID)
 		if err != nil {
 			return http.StatusInternalServerError, int(types.ErrCodeModel), err.Error()
 		}
-		model.IncRef(req.Project, req.Model, mi.CID)
-		timer.SendAIProjects(publishChan)
+		env.Models.IncRef(req.Project, req.Model, mi.CID)
+		timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
 		defer func() {
-			model.DecRef(req.Project, req.Model, mi.CID)
-			timer.SendAIProjects(publishChan)
+			env.Models.DecRef(req.Project, req.Model, mi.CID)
+			timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
 		}()
-		*rsp = *model.ImageGenerationModel(mi.API, req.ImageGenModelRequest)
+		*rsp = *model.ImageGenerationModel(ctx, mi.AIModelConfig, req.ImageGenModelRequest)
 		log.Logger.Infof("Execute model %s result {code:%d, message:%s}", req.Model, rsp.Code, rsp.Message)
+		if rsp.Code == 0 && env.Config.App.ImageStore.Enabled {
+			blobs.StoreImages(ctx, req.NodeID, req.ResponseFormat, rsp.Choices)
+		}
 		return http.StatusOK, rsp.Code, rsp.Message
 	}
 
@@ -500,7 +479,7 @@ func handleImageGenRequest(ctx context.Context, publishChan chan<- []byte, req t
 		queryValues.Add("cid", req.CID)
 		hreq.URL.RawQuery = queryValues.Encode()
 
-		stream, err := host.Hio.NewStream(ctx, req.NodeID)
+		stream, err := env.Host.NewStream(ctx, req.NodeID)
 		if err != nil {
 			// rsp.Code = int(types.ErrCodeStream)
 			// rsp.Message = "Open stream with peer node failed"
@@ -509,6 +488,7 @@ func handleImageGenRequest(ctx context.Context, publishChan chan<- []byte, req t
 		}
 		stream.SetDeadline(time.Now().Add(types.ImageGenerationRequestTimeout))
 		defer stream.Close()
+		defer resetOnCancel(ctx, stream)()
 		log.Logger.Infof("Create libp2p stream with %s success", req.NodeID)
 
 		err = hreq.Write(stream)
@@ -618,6 +598,7 @@ func handleImageGenRequest(ctx context.Context, publishChan chan<- []byte, req t
 				},
 				Cid:  req.CID,
 				Step: req.Step,
+				Seed: req.Seed,
 			},
 		},
 	}
@@ -625,17 +606,13 @@ func handleImageGenRequest(ctx context.Context, publishChan chan<- []byte, req t
 	if err != nil {
 		return http.StatusInternalServerError, int(types.ErrCodeProtobuf), err.Error()
 	}
-	body, err = host.Encrypt(ctx, req.NodeID, body)
-	if err != nil {
-		return http.StatusInternalServerError, int(types.ErrCodeEncrypt), types.ErrCodeEncrypt.String()
-	}
 
 	msg := &protocol.Message{
 		Header: &protocol.MessageHeader{
-			ClientVersion: host.Hio.UserAgent,
+			ClientVersion: env.Host.UserAgent,
 			Timestamp:     time.Now().Unix(),
 			Id:            requestID.String(),
-			NodeId:        config.GC.Identity.PeerID,
+			NodeId:        env.Config.Identity.PeerID,
 			Receiver:      req.NodeID,
 			NodePubKey:    nil,
 			Sign:          nil,
@@ -644,11 +621,13 @@ func handleImageGenRequest(ctx context.Context, publishChan chan<- []byte, req t
 		Body:       body,
 		ResultCode: 0,
 	}
-	msg.Header.NodePubKey, _ = host.MarshalPubKeyFromPrivKey(host.Hio.PrivKey)
-	return handleRequest(publishChan, msg, rsp, types.ImageGenerationRequestTimeout)
+	if err := env.Host.EncryptMessage(ctx, msg); err != nil {
+		return http.StatusInternalServerError, int(types.ErrCodeEncrypt), types.ErrCodeEncrypt.String()
+	}
+	return handleRequest(ctx, env, publishChan, msg, rsp, types.ImageGenerationRequestTimeout)
 }
 
-func ImageGenHandler(c *gin.Context, publishChan chan<- []byte) {
+func ImageGenHandler(c *gin.Context, env *Env, publishChan chan<- []byte, blobs *blob.Store, cache *ResponseCache) {
 	rsp := types.ImageGenerationResponse{}
 
 	var msg types.ImageGenerationRequest
@@ -666,7 +645,12 @@ func ImageGenHandler(c *gin.Context, publishChan chan<- []byte) {
 		return
 	}
 
-	status, code, message := handleImageGenRequest(c.Request.Context(), publishChan, msg, &rsp)
+	key, hit := cache.lookup(c, msg.Project, imageGenCacheKey(msg.Project, msg.CID, msg.ImageGenModelRequest))
+	if hit {
+		return
+	}
+
+	status, code, message := handleImageGenRequest(c.Request.Context(), env, publishChan, blobs, msg, &rsp)
 	if code != 0 {
 		c.JSON(status, types.BaseHttpResponse{
 			Code:    code,
@@ -675,14 +659,55 @@ func ImageGenHandler(c *gin.Context, publishChan chan<- []byte) {
 	} else if rsp.Code != 0 {
 		c.JSON(http.StatusInternalServerError, rsp)
 	} else {
+		cache.storeImages(key, rsp)
 		c.JSON(http.StatusOK, rsp)
 	}
 }
 
-func ImageGenProxyHandler(c *gin.Context, publishChan chan<- []byte) {
+// imageGenProxyPeer selects the node of the image generation proxy request.
+func imageGenProxyPeer(env *Env, store db.Store, msg types.ImageGenerationProxyRequest) (types.ImageGenerationRequest, int, string) {
+	ids, code := store.GetPeersOfAIProjects(msg.Project, msg.Model, 20)
+	if code != 0 {
+		return types.ImageGenerationRequest{}, int(types.ErrCodeProxy), types.ErrorCode(code).String()
+	}
+
+	peers := []types.AIProjectPeerInfo{}
+	for id, mi := range ids {
+		if msg.ResponseFormat == "b64_json" && !env.Host.CanStream(id) {
+			continue
+		}
+		conn := env.Host.Connectedness(id)
+		latency := env.Host.StreamLatency(id).Nanoseconds()
+		if msg.ResponseFormat == "b64_json" && latency == 0 {
+			continue
+		}
+		peers = append(peers, types.AIProjectPeerInfo{
+			NodeID:       id,
+			Connectivity: conn,
+			Latency:      latency,
+			Idle:         mi.Idle,
+			CID:          mi.CID,
+			Type:         mi.Type,
+		})
+	}
+	if len(peers) == 0 {
+		return types.ImageGenerationRequest{}, int(types.ErrCodeProxy), "Not enough available and connected nodes"
+	}
+
+	sort.Sort(types.AIProjectPeerOrder(peers))
+
+	return types.ImageGenerationRequest{
+		NodeID:               peers[0].NodeID,
+		CID:                  peers[0].CID,
+		Project:              msg.Project,
+		ImageGenModelRequest: msg.ImageGenModelRequest,
+	}, 0, ""
+}
+
+func ImageGenProxyHandler(c *gin.Context, env *Env, publishChan chan<- []byte, store db.Store, blobs *blob.Store, cache *ResponseCache) {
 	rsp := types.ImageGenerationResponse{}
 
-	if !config.GC.App.PeersCollect.Enabled {
+	if !env.Config.App.PeersCollect.Enabled {
 		rsp.Code = int(types.ErrCodeUnsupported)
 		rsp.Message = types.ErrCodeUnsupported.String()
 		c.JSON(http.StatusBadRequest, rsp)
@@ -704,48 +729,21 @@ func ImageGenProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 		return
 	}
 
-	ids, code := db.GetPeersOfAIProjects(msg.Project, msg.Model, 20)
-	if code != 0 {
-		rsp.Code = int(types.ErrCodeProxy)
-		rsp.Message = types.ErrorCode(code).String()
-		c.JSON(http.StatusInternalServerError, rsp)
+	// the proxy requests share the responses of all the nodes of the model
+	key, hit := cache.lookup(c, msg.Project, imageGenCacheKey(msg.Project, "", msg.ImageGenModelRequest))
+	if hit {
 		return
 	}
 
-	peers := []types.AIProjectPeerInfo{}
-	for id, mi := range ids {
-		conn := host.Hio.Connectedness(id)
-		if msg.ResponseFormat == "b64_json" && conn != 1 {
-			continue
-		}
-		latency := host.Hio.Latency(id).Nanoseconds()
-		if msg.ResponseFormat == "b64_json" && latency == 0 {
-			continue
-		}
-		peers = append(peers, types.AIProjectPeerInfo{
-			NodeID:       id,
-			Connectivity: conn,
-			Latency:      latency,
-			Idle:         mi.Idle,
-			CID:          mi.CID,
-		})
-	}
-	if len(peers) == 0 {
-		rsp.Code = int(types.ErrCodeProxy)
-		rsp.Message = "Not enough available and directly connected nodes"
+	igReq, code, message := imageGenProxyPeer(env, store, msg)
+	if code != 0 {
+		rsp.Code = code
+		rsp.Message = message
 		c.JSON(http.StatusInternalServerError, rsp)
 		return
 	}
 
-	sort.Sort(types.AIProjectPeerOrder(peers))
-
-	igReq := types.ImageGenerationRequest{
-		NodeID:               peers[0].NodeID,
-		CID:                  peers[0].CID,
-		Project:              msg.Project,
-		ImageGenModelRequest: msg.ImageGenModelRequest,
-	}
-	status, code, message := handleImageGenRequest(c.Request.Context(), publishChan, igReq, &rsp)
+	status, code, message := handleImageGenRequest(c.Request.Context(), env, publishChan, blobs, igReq, &rsp)
 	if code != 0 {
 		c.JSON(status, types.BaseHttpResponse{
 			Code:    code,
@@ -754,6 +752,7 @@ func ImageGenProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 	} else if rsp.Code != 0 {
 		c.JSON(http.StatusInternalServerError, rsp)
 	} else {
+		cache.storeImages(key, rsp)
 		c.JSON(http.StatusOK, rsp)
 	}
 	/*
@@ -768,7 +767,7 @@ func ImageGenProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 	   			Project:              msg.Project,
 	   			ImageGenModelRequest: msg.ImageGenModelRequest,
 	   		}
-	   		status, code, message := handleImageGenRequest(c.Request.Context(), publishChan, igReq, &rsp)
+	   		status, code, message := handleImageGenRequest(c.Request.Context(), env, publishChan, blobs, igReq, &rsp)
 	   		if code != 0 {
 	   			log.Logger.Warnf("Roundtrip image gen proxy %v %v %v to %s in %d time", status, code, message, peer.NodeID, failed_count)
 	   			failed_count += 1
@@ -790,17 +789,17 @@ func ImageGenProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 	*/
 }
 
-func handleImageEditRequest(ctx context.Context, publishChan chan<- []byte, w http.ResponseWriter, form *multipart.Form, req types.ImageGenerationRequest) (int, int, string) {
-	if req.NodeID == config.GC.Identity.PeerID {
-		mi, err := model.GetModelInfo(req.Project, req.Model, req.CID)
+func handleImageEditRequest(ctx context.Context, env *Env, publishChan chan<- []byte, w http.ResponseWriter, form *multipart.Form, req types.ImageGenerationRequest) (int, int, string) {
+	if req.NodeID == env.Config.Identity.PeerID {
+		mi, err := env.Models.GetModelInfo(req.Project, req.Model, req.CID)
 		if err != nil {
 			return http.StatusInternalServerError, int(types.ErrCodeModel), err.Error()
 		}
-		model.IncRef(req.Project, req.Model, mi.CID)
-		timer.SendAIProjects(publishChan)
+		env.Models.IncRef(req.Project, req.Model, mi.CID)
+		timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
 		defer func() {
-			model.DecRef(req.Project, req.Model, mi.CID)
-			timer.SendAIProjects(publishChan)
+			env.Models.DecRef(req.Project, req.Model, mi.CID)
+			timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
 		}()
 		resp, err := model.ImageEditModel(mi.API, form)
 		if err != nil {
@@ -826,10 +825,6 @@ func handleImageEditRequest(ctx context.Context, publishChan chan<- []byte, w ht
 		return http.StatusOK, 0, ""
 	}
 
-	if host.Hio.Connectedness(req.NodeID) != 1 {
-		return http.StatusInternalServerError, int(types.ErrCodeStream), "Not available and directly connected node"
-	}
-
 	log.Logger.Info("Received image edit b64_json request")
 	ctx, cancel := context.WithTimeout(ctx, types.ImageGenerationRequestTimeout)
 	defer cancel()
@@ -895,7 +890,7 @@ func handleImageEditRequest(ctx context.Context, publishChan chan<- []byte, w ht
 	queryValues.Add("cid", req.CID)
 	hreq.URL.RawQuery = queryValues.Encode()
 
-	stream, err := host.Hio.NewStream(ctx, req.NodeID)
+	stream, err := env.Host.NewStream(ctx, req.NodeID)
 	if err != nil {
 		// rsp.Code = int(types.ErrCodeStream)
 		// rsp.Message = "Open stream with peer node failed"
@@ -904,6 +899,7 @@ func handleImageEditRequest(ctx context.Context, publishChan chan<- []byte, w ht
 	}
 	stream.SetDeadline(time.Now().Add(types.ImageGenerationRequestTimeout))
 	defer stream.Close()
+	defer resetOnCancel(ctx, stream)()
 	log.Logger.Infof("Create libp2p stream with %s success", req.NodeID)
 
 	err = hreq.Write(stream)
@@ -983,7 +979,7 @@ func handleImageEditRequest(ctx context.Context, publishChan chan<- []byte, w ht
 	}
 }
 
-func ImageEditHandler(c *gin.Context, publishChan chan<- []byte) {
+func ImageEditHandler(c *gin.Context, env *Env, publishChan chan<- []byte) {
 	rsp := types.ImageGenerationResponse{}
 
 	var msg types.ImageGenerationRequest
@@ -1007,7 +1003,7 @@ func ImageEditHandler(c *gin.Context, publishChan chan<- []byte) {
 		rsp.Message = types.ErrCodeParam.String()
 		c.JSON(http.StatusUnprocessableEntity, rsp)
 	}
-	status, code, message := handleImageEditRequest(c.Request.Context(), publishChan, c.Writer, form, msg)
+	status, code, message := handleImageEditRequest(c.Request.Context(), env, publishChan, c.Writer, form, msg)
 	if code != 0 {
 		c.JSON(status, types.BaseHttpResponse{
 			Code:    code,
@@ -1016,10 +1012,10 @@ func ImageEditHandler(c *gin.Context, publishChan chan<- []byte) {
 	}
 }
 
-func ImageEditProxyHandler(c *gin.Context, publishChan chan<- []byte) {
+func ImageEditProxyHandler(c *gin.Context, env *Env, publishChan chan<- []byte, store db.Store) {
 	rsp := types.ImageGenerationResponse{}
 
-	if !config.GC.App.PeersCollect.Enabled {
+	if !env.Config.App.PeersCollect.Enabled {
 		rsp.Code = int(types.ErrCodeUnsupported)
 		rsp.Message = types.ErrCodeUnsupported.String()
 		c.JSON(http.StatusBadRequest, rsp)
@@ -1048,7 +1044,7 @@ func ImageEditProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 		c.JSON(http.StatusUnprocessableEntity, rsp)
 	}
 
-	ids, code := db.GetPeersOfAIProjects(msg.Project, msg.Model, 20)
+	ids, code := store.GetPeersOfAIProjects(msg.Project, msg.Model, 20)
 	if code != 0 {
 		rsp.Code = int(types.ErrCodeProxy)
 		rsp.Message = types.ErrorCode(code).String()
@@ -1058,11 +1054,11 @@ func ImageEditProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 
 	peers := []types.AIProjectPeerInfo{}
 	for id, mi := range ids {
-		conn := host.Hio.Connectedness(id)
-		if /*msg.ResponseFormat == "b64_json" &&*/ conn != 1 {
+		if /*msg.ResponseFormat == "b64_json" &&*/ !env.Host.CanStream(id) {
 			continue
 		}
-		latency := host.Hio.Latency(id).Nanoseconds()
+		conn := env.Host.Connectedness(id)
+		latency := env.Host.StreamLatency(id).Nanoseconds()
 		if /*msg.ResponseFormat == "b64_json" &&*/ latency == 0 {
 			continue
 		}
@@ -1072,11 +1068,12 @@ func ImageEditProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 			Latency:      latency,
 			Idle:         mi.Idle,
 			CID:          mi.CID,
+			Type:         mi.Type,
 		})
 	}
 	if len(peers) == 0 {
 		rsp.Code = int(types.ErrCodeProxy)
-		rsp.Message = "Not enough available and directly connected nodes"
+		rsp.Message = "Not enough available and connected nodes"
 		c.JSON(http.StatusInternalServerError, rsp)
 		return
 	}
@@ -1089,11 +1086,198 @@ func ImageEditProxyHandler(c *gin.Context, publishChan chan<- []byte) {
 		Project:              msg.Project,
 		ImageGenModelRequest: msg.ImageGenModelRequest,
 	}
-	status, code, message := handleImageEditRequest(c.Request.Context(), publishChan, c.Writer, form, igReq)
+	status, code, message := handleImageEditRequest(c.Request.Context(), env, publishChan, c.Writer, form, igReq)
+	if code != 0 {
+		c.JSON(status, types.BaseHttpResponse{
+			Code:    code,
+			Message: message,
+		})
+	}
+}
+
+func handleEmbeddingRequest(ctx context.Context, env *Env, publishChan chan<- []byte, req types.EmbeddingRequest, rsp *types.EmbeddingResponse) (int, int, string) {
+	if req.NodeID == env.Config.Identity.PeerID {
+		mi, err := env.Models.GetModelInfo(req.Project, req.Model, req.CID)
+		if err != nil {
+			return http.StatusInternalServerError, int(types.ErrCodeModel), err.Error()
+		}
+		if mi.Type != types.ModelTypeEmbedding {
+			return http.StatusBadRequest, int(types.ErrCodeModel), "Not an embedding model"
+		}
+		env.Models.IncRef(req.Project, req.Model, mi.CID)
+		timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
+		defer func() {
+			env.Models.DecRef(req.Project, req.Model, mi.CID)
+			timer.SendAIProjects(publishChan, env.Config, env.Host, env.Models)
+		}()
+		*rsp = *model.EmbeddingModel(ctx, mi.AIModelConfig, req.EmbeddingModelRequest)
+		log.Logger.Infof("Execute model %s result {code:%d, message:%s}", req.Model, rsp.Code, rsp.Message)
+		return http.StatusOK, rsp.Code, rsp.Message
+	}
+
+	requestID, err := uuid.NewRandom()
+	if err != nil {
+		return http.StatusInternalServerError, int(types.ErrCodeUUID), err.Error()
+	}
+
+	pi := &protocol.EmbeddingBody{
+		Data: &protocol.EmbeddingBody_Req{
+			Req: &protocol.EmbeddingRequest{
+				Project:    req.Project,
+				Model:      req.Model,
+				Input:      req.Input,
+				Cid:        req.CID,
+				Dimensions: int32(req.Dimensions),
+				Wallet: &protocol.WalletVerification{
+					Wallet:    req.Wallet,
+					Signature: req.Signature,
+					Hash:      req.Hash,
+				},
+			},
+		},
+	}
+	body, err := proto.Marshal(pi)
+	if err != nil {
+		return http.StatusInternalServerError, int(types.ErrCodeProtobuf), err.Error()
+	}
+
+	msg := &protocol.Message{
+		Header: &protocol.MessageHeader{
+			ClientVersion: env.Host.UserAgent,
+			Timestamp:     time.Now().Unix(),
+			Id:            requestID.String(),
+			NodeId:        env.Config.Identity.PeerID,
+			Receiver:      req.NodeID,
+			NodePubKey:    nil,
+			Sign:          nil,
+		},
+		Type:       *protocol.MessageType_EMBEDDING.Enum(),
+		Body:       body,
+		ResultCode: 0,
+	}
+	if err := env.Host.EncryptMessage(ctx, msg); err != nil {
+		return http.StatusInternalServerError, int(types.ErrCodeEncrypt), types.ErrCodeEncrypt.String()
+	}
+	return handleRequest(ctx, env, publishChan, msg, rsp, types.EmbeddingRequestTimeout)
+}
+
+func EmbeddingHandler(c *gin.Context, env *Env, publishChan chan<- []byte, cache *ResponseCache) {
+	rsp := types.EmbeddingResponse{}
+
+	var msg types.EmbeddingRequest
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if err := msg.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	key, hit := cache.lookup(c, msg.Project, embeddingCacheKey(msg.Project, msg.CID, msg.EmbeddingModelRequest))
+	if hit {
+		return
+	}
+
+	status, code, message := handleEmbeddingRequest(c.Request.Context(), env, publishChan, msg, &rsp)
 	if code != 0 {
 		c.JSON(status, types.BaseHttpResponse{
 			Code:    code,
 			Message: message,
 		})
+	} else if rsp.Code != 0 {
+		c.JSON(http.StatusInternalServerError, rsp)
+	} else {
+		cache.store(key, rsp)
+		c.JSON(http.StatusOK, rsp)
+	}
+}
+
+func EmbeddingProxyHandler(c *gin.Context, env *Env, publishChan chan<- []byte, store db.Store, cache *ResponseCache) {
+	rsp := types.EmbeddingResponse{}
+
+	if !env.Config.App.PeersCollect.Enabled {
+		rsp.Code = int(types.ErrCodeUnsupported)
+		rsp.Message = types.ErrCodeUnsupported.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	var msg types.EmbeddingProxyRequest
+	if err := c.ShouldBindJSON(&msg); err != nil {
+		rsp.Code = int(types.ErrCodeParse)
+		rsp.Message = types.ErrCodeParse.String()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	if err := msg.Validate(); err != nil {
+		rsp.Code = int(types.ErrCodeParam)
+		rsp.Message = err.Error()
+		c.JSON(http.StatusBadRequest, rsp)
+		return
+	}
+
+	// the proxy requests share the responses of all the nodes of the model
+	key, hit := cache.lookup(c, msg.Project, embeddingCacheKey(msg.Project, "", msg.EmbeddingModelRequest))
+	if hit {
+		return
+	}
+
+	ids, code := store.GetPeersOfAIProjects(msg.Project, msg.Model, 20)
+	if code != 0 {
+		rsp.Code = int(types.ErrCodeProxy)
+		rsp.Message = types.ErrorCode(code).String()
+		c.JSON(http.StatusInternalServerError, rsp)
+		return
+	}
+
+	peers := []types.AIProjectPeerInfo{}
+	for id, mi := range ids {
+		// only the models advertised as embedding models
+		if mi.Type != types.ModelTypeEmbedding {
+			continue
+		}
+		peers = append(peers, types.AIProjectPeerInfo{
+			NodeID:       id,
+			Connectivity: env.Host.Connectedness(id),
+			Latency:      env.Host.StreamLatency(id).Nanoseconds(),
+			Idle:         mi.Idle,
+			CID:          mi.CID,
+			Type:         mi.Type,
+		})
+	}
+	if len(peers) == 0 {
+		rsp.Code = int(types.ErrCodeProxy)
+		rsp.Message = "Not enough available and connected nodes"
+		c.JSON(http.StatusInternalServerError, rsp)
+		return
+	}
+
+	sort.Sort(types.AIProjectPeerOrder(peers))
+
+	ebReq := types.EmbeddingRequest{
+		NodeID:                peers[0].NodeID,
+		CID:                   peers[0].CID,
+		Project:               msg.Project,
+		EmbeddingModelRequest: msg.EmbeddingModelRequest,
+	}
+
+	status, code, message := handleEmbeddingRequest(c.Request.Context(), env, publishChan, ebReq, &rsp)
+	if code != 0 {
+		c.JSON(status, types.BaseHttpResponse{
+			Code:    code,
+			Message: message,
+		})
+	} else if rsp.Code != 0 {
+		c.JSON(http.StatusInternalServerError, rsp)
+	} else {
+		cache.store(key, rsp)
+		c.JSON(http.StatusOK, rsp)
 	}
 }
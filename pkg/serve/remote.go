@@ -0,0 +1,219 @@
+package serve
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"AIComputingNode/pkg/db"
+	"AIComputingNode/pkg/hardware"
+	"AIComputingNode/pkg/protocol"
+	"AIComputingNode/pkg/types"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+)
+
+// RemoteLimiter limits the frequency of the requests sent to each remote node
+type RemoteLimiter struct {
+	mutex sync.Mutex
+	last  map[string]time.Time
+}
+
+// Allow reports whether a request to key can be sent now and records it if so.
+func (rl *RemoteLimiter) Allow(key string, interval time.Duration) bool {
+	rl.mutex.Lock()
+	defer rl.mutex.Unlock()
+	now := time.Now()
+	if last, ok := rl.last[key]; ok && now.Sub(last) < interval {
+		return false
+	}
+	if len(rl.last) >= 4096 {
+		for k, last := range rl.last {
+			if now.Sub(last) >= interval {
+				delete(rl.last, k)
+			}
+		}
+	}
+	rl.last[key] = now
+	return true
+}
+
+func remoteQueryInterval(env *Env) time.Duration {
+	interval, _ := time.ParseDuration(env.Config.App.RemoteQuery.MinInterval)
+	return interval
+}
+
+func remoteQueryMaxAge(env *Env, opts types.RemoteQueryOptions) time.Duration {
+	if opts.MaxAge > 0 {
+		return time.Duration(opts.MaxAge) * time.Second
+	}
+	ttl, _ := time.ParseDuration(env.Config.App.RemoteQuery.CacheTTL)
+	return ttl
+}
+
+func newRemoteRequest(ctx context.Context, env *Env, nodeID string, msgType protocol.MessageType, pb proto.Message) (*protocol.Message, int, int, string) {
+	requestID, err := uuid.NewRandom()
+	if err != nil {
+		return nil, http.StatusInternalServerError, int(types.ErrCodeUUID), err.Error()
+	}
+	body, err := proto.Marshal(pb)
+	if err != nil {
+		return nil, http.StatusInternalServerError, int(types.ErrCodeProtobuf), err.Error()
+	}
+
+	req := &protocol.Message{
+		Header: &protocol.MessageHeader{
+			ClientVersion: env.Host.UserAgent,
+			Timestamp:     time.Now().Unix(),
+			Id:            requestID.String(),
+			NodeId:        env.Config.Identity.PeerID,
+			Receiver:      nodeID,
+			NodePubKey:    nil,
+			Sign:          nil,
+		},
+		Type:       msgType,
+		Body:       body,
+		ResultCode: 0,
+	}
+	env.Host.EncryptMessage(ctx, req)
+	return req, http.StatusOK, 0, ""
+}
+
+// queryPeerIdentity returns the identify protocol of the node, answering from the
+// cache when it is fresh enough and asking the node over pubsub otherwise.
+func queryPeerIdentity(ctx context.Context, env *Env, publishChan chan<- []byte, store db.Store, nodeID string, opts types.RemoteQueryOptions) (types.PeerResponse, int) {
+	rsp := types.PeerResponse{}
+	if nodeID == env.Config.Identity.PeerID {
+		rsp.IdentifyProtocol = env.Host.GetIdentifyProtocol()
+		return rsp, http.StatusOK
+	}
+
+	cached := types.PeerResponse{}
+	cachedAt, err := store.GetRemoteCache(db.RemoteCachePeerIdentity, nodeID, &cached.IdentifyProtocol)
+	hasCache := err == nil
+	if hasCache {
+		cached.CachedAt = cachedAt
+		if !opts.Refresh && time.Since(time.Unix(cachedAt, 0)) <= remoteQueryMaxAge(env, opts) {
+			return cached, http.StatusOK
+		}
+	}
+	if !env.remote.Allow(db.RemoteCachePeerIdentity+"/"+nodeID, remoteQueryInterval(env)) {
+		if hasCache {
+			return cached, http.StatusOK
+		}
+		rsp.Code = int(types.ErrCodeRateLimit)
+		rsp.Message = types.ErrCodeRateLimit.String()
+		return rsp, http.StatusTooManyRequests
+	}
+
+	pi := &protocol.PeerIdentityBody{
+		Data: &protocol.PeerIdentityBody_Req{
+			Req: &protocol.PeerIdentityRequest{},
+		},
+	}
+	req, status, code, message := newRemoteRequest(ctx, env, nodeID, protocol.MessageType_PEER_IDENTITY, pi)
+	if code == 0 {
+		status, code, message = handleRequest(ctx, env, publishChan, req, &rsp, types.OrdinaryRequestTimeout)
+	}
+	if code != 0 {
+		rsp.Code = code
+		rsp.Message = message
+		return rsp, status
+	}
+	if rsp.Code != 0 {
+		return rsp, http.StatusInternalServerError
+	}
+	store.PutRemoteCache(db.RemoteCachePeerIdentity, nodeID, rsp.IdentifyProtocol)
+	return rsp, http.StatusOK
+}
+
+// queryHostInfo returns the machine information of the node, answering from the
+// cache when it is fresh enough and asking the node over pubsub otherwise.
+func queryHostInfo(ctx context.Context, env *Env, publishChan chan<- []byte, store db.Store, nodeID string, opts types.RemoteQueryOptions) (types.HostInfoResponse, int) {
+	rsp := types.HostInfoResponse{}
+	if nodeID == env.Config.Identity.PeerID {
+		hd, err := hardware.GetHostInfo()
+		if err != nil {
+			rsp.Code = int(types.ErrCodeHostInfo)
+			rsp.Message = err.Error()
+			return rsp, http.StatusInternalServerError
+		}
+		rsp.HostInfo = *hd
+		return rsp, http.StatusOK
+	}
+
+	cached := types.HostInfoResponse{}
+	cachedAt, err := store.GetRemoteCache(db.RemoteCacheHostInfo, nodeID, &cached.HostInfo)
+	hasCache := err == nil
+	if hasCache {
+		cached.CachedAt = cachedAt
+		if !opts.Refresh && time.Since(time.Unix(cachedAt, 0)) <= remoteQueryMaxAge(env, opts) {
+			return cached, http.StatusOK
+		}
+	}
+	if !env.remote.Allow(db.RemoteCacheHostInfo+"/"+nodeID, remoteQueryInterval(env)) {
+		if hasCache {
+			return cached, http.StatusOK
+		}
+		rsp.Code = int(types.ErrCodeRateLimit)
+		rsp.Message = types.ErrCodeRateLimit.String()
+		return rsp, http.StatusTooManyRequests
+	}
+
+	hi := &protocol.HostInfoBody{
+		Data: &protocol.HostInfoBody_Req{
+			Req: &protocol.HostInfoRequest{},
+		},
+	}
+	req, status, code, message := newRemoteRequest(ctx, env, nodeID, protocol.MessageType_HOST_INFO, hi)
+	if code == 0 {
+		status, code, message = handleRequest(ctx, env, publishChan, req, &rsp, types.OrdinaryRequestTimeout)
+	}
+	if code != 0 {
+		rsp.Code = code
+		rsp.Message = message
+		return rsp, status
+	}
+	if rsp.Code != 0 {
+		return rsp, http.StatusInternalServerError
+	}
+	store.PutRemoteCache(db.RemoteCacheHostInfo, nodeID, rsp.HostInfo)
+	return rsp, http.StatusOK
+}
+
+// forEachRemoteNode calls fn for each node with bounded concurrency and waits for all of them.
+func forEachRemoteNode(env *Env, ids []string, fn func(index int, id string)) {
+	concurrency := env.Config.App.RemoteQuery.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(index int, id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(index, id)
+		}(i, id)
+	}
+	wg.Wait()
+}
+
+func uniqueNodeIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	result := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		result = append(result, id)
+	}
+	return result
+}
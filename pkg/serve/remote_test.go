@@ -0,0 +1,60 @@
+package serve
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"AIComputingNode/pkg/config"
+)
+
+// go test -v -timeout 30s -count=1 -run TestRemoteLimiter AIComputingNode/pkg/serve
+func TestRemoteLimiter(t *testing.T) {
+	rl := RemoteLimiter{last: make(map[string]time.Time)}
+	if !rl.Allow("host_info/a", time.Minute) {
+		t.Fatal("First request was rejected")
+	}
+	if rl.Allow("host_info/a", time.Minute) {
+		t.Error("Second request within interval was allowed")
+	}
+	if !rl.Allow("host_info/b", time.Minute) {
+		t.Error("Request to another target was rejected")
+	}
+	if !rl.Allow("host_info/c", 0) || !rl.Allow("host_info/c", 0) {
+		t.Error("Request was rejected without interval")
+	}
+}
+
+// go test -v -timeout 30s -count=1 -run TestForEachRemoteNode AIComputingNode/pkg/serve
+func TestForEachRemoteNode(t *testing.T) {
+	env := NewEnv(&config.Config{}, nil, nil)
+	env.Config.App.RemoteQuery.BatchConcurrency = 4
+
+	ids := uniqueNodeIDs([]string{"a", "b", "a", "c", "d", "e", "f", "b"})
+	if len(ids) != 6 {
+		t.Fatalf("Unexpected unique node ids %v", ids)
+	}
+
+	var running, peak int32
+	results := make([]string, len(ids))
+	forEachRemoteNode(env, ids, func(index int, id string) {
+		cur := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if cur <= old || atomic.CompareAndSwapInt32(&peak, old, cur) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		results[index] = id
+		atomic.AddInt32(&running, -1)
+	})
+	if peak > 4 {
+		t.Errorf("Concurrency %d exceeds the limit", peak)
+	}
+	for i, id := range ids {
+		if results[i] != id {
+			t.Errorf("Result %d is %q, expected %q", i, results[i], id)
+		}
+	}
+}
@@ -2,6 +2,11 @@ package serve
 
 import (
 	"sync"
+	"time"
+
+	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/libp2p/host"
+	"AIComputingNode/pkg/model"
 )
 
 // type RequestItem struct {
@@ -12,9 +17,26 @@ import (
 // var RequestQueue = make([]RequestItem, 0)
 // var QueueLock = sync.Mutex{}
 
-var requestQueue = RequestQueue{
-	mutex:    sync.RWMutex{},
-	elements: make(map[string]chan []byte),
+// Env is the state of a node used by the handlers of the HTTP API and the pubsub messages,
+// so that several nodes can run in a process.
+type Env struct {
+	Config *config.Config
+	Host   *host.HostInfo
+	Models *model.ProjectMap
+	// requests waiting for the responses from the pubsub topic
+	Requests *RequestQueue
+
+	remote *RemoteLimiter
+}
+
+func NewEnv(cfg *config.Config, hio *host.HostInfo, models *model.ProjectMap) *Env {
+	return &Env{
+		Config:   cfg,
+		Host:     hio,
+		Models:   models,
+		Requests: NewRequestQueue(),
+		remote:   &RemoteLimiter{last: make(map[string]time.Time)},
+	}
 }
 
 type RequestQueue struct {
@@ -22,6 +44,10 @@ type RequestQueue struct {
 	elements map[string]chan []byte
 }
 
+func NewRequestQueue() *RequestQueue {
+	return &RequestQueue{elements: make(map[string]chan []byte)}
+}
+
 func (sm *RequestQueue) Load(key string) (chan []byte, bool) {
 	sm.mutex.RLock()
 	defer sm.mutex.RUnlock()
@@ -45,16 +71,20 @@ func (sm *RequestQueue) Delete(key string) (chan []byte, bool) {
 	return value, ok
 }
 
-func AddRequestItem(id string, notify chan []byte) {
-	requestQueue.Store(id, notify)
+func (sm *RequestQueue) AddRequestItem(id string, notify chan []byte) {
+	sm.Store(id, notify)
 }
 
-func DeleteRequestItem(id string) {
-	requestQueue.Delete(id)
+// DeleteRequestItem removes the pending request and closes its channel, unless the response
+// has taken it from the queue already.
+func (sm *RequestQueue) DeleteRequestItem(id string) {
+	if value, ok := sm.Delete(id); ok && value != nil {
+		close(value)
+	}
 }
 
-func ExistRequestItem(id string) bool {
-	_, ok := requestQueue.Load(id)
+func (sm *RequestQueue) ExistRequestItem(id string) bool {
+	_, ok := sm.Load(id)
 	return ok
 }
 
@@ -70,10 +100,10 @@ func ExistRequestItem(id string) bool {
 // 	QueueLock.Unlock()
 // }
 
-func WriteAndDeleteRequestItem(id string, data []byte) {
-	if value, ok := requestQueue.Load(id); ok && value != nil {
+func (sm *RequestQueue) WriteAndDeleteRequestItem(id string, data []byte) {
+	// the request is removed before the write, so that it is not closed by the waiting handler
+	if value, ok := sm.Delete(id); ok && value != nil {
 		value <- data
 		close(value)
 	}
-	requestQueue.Delete(id)
 }
@@ -1,9 +1,11 @@
 package timer
 
 import (
+	"context"
 	"time"
 
 	"AIComputingNode/pkg/config"
+	"AIComputingNode/pkg/hardware"
 	"AIComputingNode/pkg/libp2p/host"
 	"AIComputingNode/pkg/log"
 	"AIComputingNode/pkg/model"
@@ -13,13 +15,13 @@ import (
 	"google.golang.org/protobuf/proto"
 )
 
-func SendAIProjects(pcn chan<- []byte) {
-	projects := model.GetAIProjects()
+func SendAIProjects(pcn chan<- []byte, cfg *config.Config, hio *host.HostInfo, models *model.ProjectMap) {
+	projects := models.GetAIProjects()
 	var nt types.NodeType = 0x00
-	if config.GC.Swarm.RelayService.Enabled {
+	if cfg.Swarm.RelayService.Enabled {
 		nt |= types.PublicIpFlag
 	}
-	if config.GC.App.PeersCollect.Enabled {
+	if cfg.App.PeersCollect.Enabled {
 		nt |= types.PeersCollectFlag
 	}
 	for _, models := range projects {
@@ -28,9 +30,30 @@ func SendAIProjects(pcn chan<- []byte) {
 			break
 		}
 	}
+	aiRes := types.AIProject2ProtocolMessage(projects, uint32(nt))
+	aiRes.Connections = types.PeerConnections2ProtocolMessage(hio.PeerConnections())
+	if cfg.App.PeersCollect.HeartbeatMetrics {
+		// heartbeats are also sent when model references change, so reuse recent metrics
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		metrics, _ := hardware.LatestHostMetrics(ctx, 10*time.Second)
+		cancel()
+		aiRes.Metrics = types.HostMetrics2ProtocolMessage(metrics)
+	}
+	if cfg.Identity.Rotation.PreviousPeerID != "" {
+		// announce the rotated key until the rotation is removed from the configuration
+		if rotation, err := cfg.Identity.Rotation.Decode(); err != nil {
+			log.Logger.Warnf("Decode key rotation %v", err)
+		} else {
+			aiRes.Rotation = &protocol.KeyRotation{
+				PreviousNodeId: rotation.PreviousPeerID,
+				PreviousPubKey: rotation.PreviousPubKey,
+				Signature:      rotation.Signature,
+			}
+		}
+	}
 	aiBody := &protocol.AIProjectBody{
 		Data: &protocol.AIProjectBody_Res{
-			Res: types.AIProject2ProtocolMessage(projects, uint32(nt)),
+			Res: aiRes,
 		},
 	}
 	body, err := proto.Marshal(aiBody)
@@ -40,10 +63,10 @@ func SendAIProjects(pcn chan<- []byte) {
 	}
 	protoMsg := protocol.Message{
 		Header: &protocol.MessageHeader{
-			ClientVersion: host.Hio.UserAgent,
+			ClientVersion: hio.UserAgent,
 			Timestamp:     time.Now().Unix(),
 			Id:            "",
-			NodeId:        config.GC.Identity.PeerID,
+			NodeId:        cfg.Identity.PeerID,
 			Receiver:      "",
 			NodePubKey:    nil,
 			Sign:          nil,
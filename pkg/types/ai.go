@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net"
 	"net/url"
+	"slices"
 	"time"
 )
 
@@ -11,6 +12,19 @@ var (
 	OrdinaryRequestTimeout        = 2 * time.Minute
 	ChatCompletionRequestTimeout  = 3 * time.Minute
 	ImageGenerationRequestTimeout = 5 * time.Minute
+	EmbeddingRequestTimeout       = 1 * time.Minute
+	AudioRequestTimeout           = 5 * time.Minute
+)
+
+// Types of the models, advertised in the heartbeats so that the nodes collecting them
+// know which requests the models serve
+const (
+	ModelTypeChat = iota
+	ModelTypeImageGeneration
+	ModelTypeImageEdit
+	ModelTypeEmbedding
+	ModelTypeAudioTranscription
+	ModelTypeAudioSpeech
 )
 
 type AIProjectConfig struct {
@@ -18,11 +32,38 @@ type AIProjectConfig struct {
 	Models  []AIModelConfig `json:"Models"`
 }
 
+// Backends of the models, which decide how the requests and responses are translated for the model API
+const (
+	// OpenAI compatible API wrapped in the code/message envelope of this project
+	ModelBackendDefault       = ""
+	ModelBackendOpenAI        = "openai"
+	ModelBackendOllama        = "ollama"
+	ModelBackendVLLM          = "vllm"
+	ModelBackendTGI           = "tgi"
+	ModelBackendLlamaCpp      = "llamacpp"
+	ModelBackendComfyUI       = "comfyui"
+	ModelBackendAutomatic1111 = "automatic1111"
+)
+
+var ModelBackends = []string{
+	ModelBackendDefault,
+	ModelBackendOpenAI,
+	ModelBackendOllama,
+	ModelBackendVLLM,
+	ModelBackendTGI,
+	ModelBackendLlamaCpp,
+	ModelBackendComfyUI,
+	ModelBackendAutomatic1111,
+}
+
 type AIModelConfig struct {
-	Model string `json:"Model"`
-	API   string `json:"API"`
-	Type  int    `json:"Type"`
-	CID   string `json:"CID"`
+	Model   string `json:"Model"`
+	API     string `json:"API"`
+	Type    int    `json:"Type"`
+	CID     string `json:"CID"`
+	Backend string `json:"Backend,omitempty"`
+	// API format workflow file of the ComfyUI backend
+	Workflow string `json:"Workflow,omitempty"`
 }
 
 type AIModelRegister struct {
@@ -61,6 +102,16 @@ func (config AIModelConfig) Validate() error {
 	if config.CID == "" {
 		return fmt.Errorf("cid represents the docker container id, which can not be empty")
 	}
+	return config.validateBackend()
+}
+
+func (config AIModelConfig) validateBackend() error {
+	if !slices.Contains(ModelBackends, config.Backend) {
+		return fmt.Errorf("unknown model backend %q", config.Backend)
+	}
+	if config.Workflow != "" && config.Backend != ModelBackendComfyUI {
+		return fmt.Errorf("workflow is only supported by the %s backend", ModelBackendComfyUI)
+	}
 	return nil
 }
 
@@ -80,7 +131,7 @@ func (config AIModelRegister) Validate() error {
 	if config.CID == "" {
 		return fmt.Errorf("cid represents the docker container id, which can not be empty")
 	}
-	return nil
+	return config.validateBackend()
 }
 
 func (config AIModelUnregister) Validate() error {
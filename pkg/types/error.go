@@ -23,6 +23,10 @@ const (
 	ErrCodeProxy
 	ErrCodeStream
 	ErrCodeDeprecated
+	ErrCodeRateLimit
+	ErrCodeNotFound
+	ErrCodeCanceled
+	ErrCodeBusy
 	ErrCodeInternal ErrorCode = 5000
 )
 
@@ -47,6 +51,10 @@ var errMsg = map[ErrorCode]string{
 	ErrCodeProxy:       "Proxy error",
 	ErrCodeStream:      "Stream error",
 	ErrCodeDeprecated:  "Deprecated function",
+	ErrCodeRateLimit:   "Rate limit exceeded",
+	ErrCodeNotFound:    "Not found",
+	ErrCodeCanceled:    "Request canceled",
+	ErrCodeBusy:        "Worker busy",
 	ErrCodeInternal:    "Internal server error",
 }
 
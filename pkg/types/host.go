@@ -1,11 +1,12 @@
 package types
 
 type HostInfo struct {
-	Os     OSInfo     `json:"os,omitempty"`
-	Cpu    []CpuInfo  `json:"cpu,omitempty"`
-	Memory MemoryInfo `json:"memory,omitempty"`
-	Disk   []DiskInfo `json:"disk,omitempty"`
-	Gpu    []GpuInfo  `json:"gpu,omitempty"`
+	Os      OSInfo       `json:"os,omitempty"`
+	Cpu     []CpuInfo    `json:"cpu,omitempty"`
+	Memory  MemoryInfo   `json:"memory,omitempty"`
+	Disk    []DiskInfo   `json:"disk,omitempty"`
+	Gpu     []GpuInfo    `json:"gpu,omitempty"`
+	Metrics *HostMetrics `json:"metrics,omitempty"`
 }
 
 type OSInfo struct {
@@ -39,3 +40,48 @@ type GpuInfo struct {
 	Vendor  string `json:"vendor"`
 	Product string `json:"product"`
 }
+
+// HostMetrics holds the live resource usage of a node, as opposed to the
+// static hardware description in HostInfo.
+type HostMetrics struct {
+	Timestamp int64        `json:"timestamp"`
+	Cpu       CpuMetrics   `json:"cpu"`
+	Memory    MemMetrics   `json:"memory"`
+	Gpu       []GpuMetrics `json:"gpu,omitempty"`
+}
+
+type CpuMetrics struct {
+	UsagePercent float64 `json:"usage_percent"`
+	Load1        float64 `json:"load1"`
+	Load5        float64 `json:"load5"`
+	Load15       float64 `json:"load15"`
+}
+
+type MemMetrics struct {
+	TotalBytes     uint64 `json:"total_bytes"`
+	UsedBytes      uint64 `json:"used_bytes"`
+	AvailableBytes uint64 `json:"available_bytes"`
+}
+
+type GpuMetrics struct {
+	Index              uint32  `json:"index"`
+	Name               string  `json:"name"`
+	UtilizationPercent float64 `json:"utilization_percent"`
+	MemoryTotalBytes   uint64  `json:"memory_total_bytes"`
+	MemoryUsedBytes    uint64  `json:"memory_used_bytes"`
+	TemperatureCelsius float64 `json:"temperature_celsius"`
+}
+
+func (mm MemMetrics) UsagePercent() float64 {
+	if mm.TotalBytes == 0 {
+		return 0
+	}
+	return float64(mm.UsedBytes) * 100 / float64(mm.TotalBytes)
+}
+
+func (gm GpuMetrics) MemoryUsagePercent() float64 {
+	if gm.MemoryTotalBytes == 0 {
+		return 0
+	}
+	return float64(gm.MemoryUsedBytes) * 100 / float64(gm.MemoryTotalBytes)
+}
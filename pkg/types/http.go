@@ -4,9 +4,15 @@ import (
 	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"slices"
+	"strings"
 )
 
+// Maximum number of nodes that can be queried in one batch request
+const MaxBatchRemoteQueryNodes = 1000
+
 type HttpResponse interface {
 	SetCode(code int)
 	SetMessage(message string)
@@ -26,18 +32,62 @@ type PeerListResponse struct {
 	Data []string `json:"data"`
 }
 
-type PeerRequest BaseHttpRequest
+// Controls how the cached responses of remote nodes are used
+type RemoteQueryOptions struct {
+	// Ignore the cache and always send the request to the remote node
+	Refresh bool `json:"refresh,omitempty"`
+	// Maximum age of an acceptable cached response in seconds,
+	// 0 means the cache ttl in the configuration file
+	MaxAge int64 `json:"max_age,omitempty"`
+}
+
+type PeerRequest struct {
+	BaseHttpRequest
+	RemoteQueryOptions
+}
 
 type PeerResponse struct {
 	BaseHttpResponse
 	IdentifyProtocol
+	// Unix time of the cached response, 0 means the response is fresh
+	CachedAt int64 `json:"cached_at,omitempty"`
 }
 
-type HostInfoRequest BaseHttpRequest
+type HostInfoRequest struct {
+	BaseHttpRequest
+	RemoteQueryOptions
+}
 
 type HostInfoResponse struct {
 	BaseHttpResponse
 	HostInfo
+	// Unix time of the cached response, 0 means the response is fresh
+	CachedAt int64 `json:"cached_at,omitempty"`
+}
+
+type BatchRemoteQueryRequest struct {
+	NodeIDs []string `json:"node_ids"`
+	RemoteQueryOptions
+}
+
+type PeerBatchItem struct {
+	NodeID string `json:"node_id"`
+	PeerResponse
+}
+
+type PeerBatchResponse struct {
+	BaseHttpResponse
+	Data []PeerBatchItem `json:"data"`
+}
+
+type HostInfoBatchItem struct {
+	NodeID string `json:"node_id"`
+	HostInfoResponse
+}
+
+type HostInfoBatchResponse struct {
+	BaseHttpResponse
+	Data []HostInfoBatchItem `json:"data"`
 }
 
 type WalletVerification struct {
@@ -63,17 +113,106 @@ type ChatDynamicContentPart struct {
 	InputAudio *ChatAudioContentPart `json:"input_audio,omitempty"`
 }
 
+type ChatToolCallFunction struct {
+	Name string `json:"name,omitempty"`
+	// JSON arguments generated by the model, which may be partial in the stream chunks
+	Arguments string `json:"arguments"`
+}
+
+type ChatToolCall struct {
+	// Only in the stream chunks, the index of the tool call being generated
+	Index    *int                 `json:"index,omitempty"`
+	ID       string               `json:"id,omitempty"`
+	Type     string               `json:"type,omitempty"`
+	Function ChatToolCallFunction `json:"function"`
+}
+
 type ChatCompletionMessage struct {
 	Role    string          `json:"role"`
 	Content json.RawMessage `json:"content"`
+	Name    string          `json:"name,omitempty"`
+	// Tool calls of the assistant messages
+	ToolCalls []ChatToolCall `json:"tool_calls,omitempty"`
+	// Tool call answered by the tool messages
+	ToolCallID string `json:"tool_call_id,omitempty"`
+}
+
+type ChatFunctionDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// JSON schema of the arguments
+	Parameters json.RawMessage `json:"parameters,omitempty"`
+	Strict     *bool           `json:"strict,omitempty"`
+}
+
+type ChatTool struct {
+	Type     string                 `json:"type"`
+	Function ChatFunctionDefinition `json:"function"`
+}
+
+type ChatJSONSchema struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Schema      json.RawMessage `json:"schema,omitempty"`
+	Strict      *bool           `json:"strict,omitempty"`
+}
+
+type ChatResponseFormat struct {
+	// text, json_object or json_schema
+	Type       string          `json:"type"`
+	JSONSchema *ChatJSONSchema `json:"json_schema,omitempty"`
+}
+
+type ChatStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"`
+}
+
+// ChatStop is a stop sequence or an array of them.
+type ChatStop []string
+
+func (stop *ChatStop) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*stop = ChatStop{text}
+		return nil
+	}
+	var texts []string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return errors.New("stop must be a string or an array of strings")
+	}
+	*stop = texts
+	return nil
+}
+
+// ChatOptions are the optional parameters of the OpenAI chat completion API,
+// the zero values are left out and the backends use their defaults.
+type ChatOptions struct {
+	Temperature         *float32            `json:"temperature,omitempty"`
+	TopP                *float32            `json:"top_p,omitempty"`
+	MaxTokens           int                 `json:"max_tokens,omitempty"`
+	MaxCompletionTokens int                 `json:"max_completion_tokens,omitempty"`
+	Stop                ChatStop            `json:"stop,omitempty"`
+	N                   int                 `json:"n,omitempty"`
+	Seed                *int64              `json:"seed,omitempty"`
+	FrequencyPenalty    float32             `json:"frequency_penalty,omitempty"`
+	PresencePenalty     float32             `json:"presence_penalty,omitempty"`
+	LogitBias           map[string]int      `json:"logit_bias,omitempty"`
+	Logprobs            bool                `json:"logprobs,omitempty"`
+	TopLogprobs         int                 `json:"top_logprobs,omitempty"`
+	ResponseFormat      *ChatResponseFormat `json:"response_format,omitempty"`
+	Tools               []ChatTool          `json:"tools,omitempty"`
+	// "none", "auto", "required" or the object of the function to call
+	ToolChoice        json.RawMessage    `json:"tool_choice,omitempty"`
+	ParallelToolCalls *bool              `json:"parallel_tool_calls,omitempty"`
+	StreamOptions     *ChatStreamOptions `json:"stream_options,omitempty"`
+	User              string             `json:"user,omitempty"`
 }
 
 type ChatModelRequest struct {
-	Model       string                  `json:"model"`
-	Messages    []ChatCompletionMessage `json:"messages"`
-	Stream      bool                    `json:"stream"`
-	Temperature float32                 `json:"temperature,omitempty"`
-	TopP        float32                 `json:"top_p,omitempty"`
+	Model    string                  `json:"model"`
+	Messages []ChatCompletionMessage `json:"messages"`
+	Stream   bool                    `json:"stream"`
+	ChatOptions
 	WalletVerification
 }
 
@@ -90,20 +229,24 @@ type ChatCompletionProxyRequest struct {
 }
 
 type ChatCompletionResponseMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role      string         `json:"role"`
+	Content   string         `json:"content"`
+	ToolCalls []ChatToolCall `json:"tool_calls,omitempty"`
+	Refusal   string         `json:"refusal,omitempty"`
 }
 
 type ChatResponseChoice struct {
 	Index        int                           `json:"index"`
 	Message      ChatCompletionResponseMessage `json:"message"`
 	FinishReason string                        `json:"finish_reason"`
+	Logprobs     json.RawMessage               `json:"logprobs,omitempty"`
 }
 
 type StreamChatResponseChoice struct {
 	Index        int                           `json:"index"`
 	Delta        ChatCompletionResponseMessage `json:"delta"`
 	FinishReason string                        `json:"finish_reason"`
+	Logprobs     json.RawMessage               `json:"logprobs,omitempty"`
 }
 
 type ChatResponseUsage struct {
@@ -113,11 +256,13 @@ type ChatResponseUsage struct {
 }
 
 type ChatModelResponseData struct {
-	Id      string               `json:"id"`
-	Object  string               `json:"object"`
-	Created int64                `json:"created"`
-	Choices []ChatResponseChoice `json:"choices"`
-	Usage   ChatResponseUsage    `json:"usage"`
+	Id                string               `json:"id"`
+	Object            string               `json:"object"`
+	Created           int64                `json:"created"`
+	Model             string               `json:"model,omitempty"`
+	SystemFingerprint string               `json:"system_fingerprint,omitempty"`
+	Choices           []ChatResponseChoice `json:"choices"`
+	Usage             ChatResponseUsage    `json:"usage"`
 }
 
 type StreamChatModelResponseData struct {
@@ -141,6 +286,7 @@ type ImageGenModelRequest struct {
 	Width          int    `json:"width"`
 	Height         int    `json:"height"`
 	Step           int32  `json:"step,omitempty"`
+	Seed           *int64 `json:"seed,omitempty"`
 	ResponseFormat string `json:"response_format"`
 	WalletVerification
 }
@@ -173,6 +319,111 @@ type ImageGenerationResponse struct {
 	ImageModelResponse
 }
 
+// EmbeddingInput is a text or a batch of texts.
+type EmbeddingInput []string
+
+func (input *EmbeddingInput) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*input = EmbeddingInput{text}
+		return nil
+	}
+	var texts []string
+	if err := json.Unmarshal(data, &texts); err != nil {
+		return errors.New("input must be a string or an array of strings")
+	}
+	*input = texts
+	return nil
+}
+
+type EmbeddingModelRequest struct {
+	Model          string         `json:"model"`
+	Input          EmbeddingInput `json:"input"`
+	EncodingFormat string         `json:"encoding_format,omitempty"`
+	Dimensions     int            `json:"dimensions,omitempty"`
+	WalletVerification
+}
+
+type EmbeddingRequest struct {
+	NodeID  string `json:"node_id"`
+	Project string `json:"project"`
+	CID     string `json:"cid"`
+	EmbeddingModelRequest
+}
+
+type EmbeddingProxyRequest struct {
+	Project string `json:"project"`
+	EmbeddingModelRequest
+}
+
+type EmbeddingData struct {
+	Object    string    `json:"object"`
+	Index     int       `json:"index"`
+	Embedding []float32 `json:"embedding"`
+}
+
+type EmbeddingUsage struct {
+	PromptTokens int `json:"prompt_tokens"`
+	TotalTokens  int `json:"total_tokens"`
+}
+
+type EmbeddingModelResponse struct {
+	Object string          `json:"object"`
+	Model  string          `json:"model"`
+	Data   []EmbeddingData `json:"data"`
+	Usage  EmbeddingUsage  `json:"usage"`
+}
+
+type EmbeddingResponse struct {
+	BaseHttpResponse
+	EmbeddingModelResponse
+}
+
+// AudioTranscriptionRequest is the query of the transcription request, the audio file and
+// the other parameters are uploaded in the multipart form.
+type AudioTranscriptionRequest struct {
+	NodeID  string `json:"node_id" form:"node_id"`
+	Project string `json:"project" form:"project"`
+	Model   string `json:"model" form:"model"`
+	CID     string `json:"cid" form:"cid"`
+}
+
+type AudioTranscriptionProxyRequest struct {
+	Project string `json:"project" form:"project"`
+	Model   string `json:"model" form:"model"`
+}
+
+type AudioSpeechModelRequest struct {
+	Model string `json:"model"`
+	// Text to generate the audio for
+	Input string `json:"input"`
+	Voice string `json:"voice"`
+	// mp3, opus, aac, flac, wav or pcm
+	ResponseFormat string  `json:"response_format,omitempty"`
+	Speed          float32 `json:"speed,omitempty"`
+	WalletVerification
+}
+
+type AudioSpeechRequest struct {
+	NodeID  string `json:"node_id"`
+	Project string `json:"project"`
+	CID     string `json:"cid"`
+	AudioSpeechModelRequest
+}
+
+type AudioSpeechProxyRequest struct {
+	Project string `json:"project"`
+	AudioSpeechModelRequest
+}
+
+// AudioResponse carries the body of the audio model response in the pubsub messages,
+// which is written to the client with the content type as it is.
+type AudioResponse struct {
+	BaseHttpResponse
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
 type SwarmConnectRequest struct {
 	NodeAddr string `json:"node_addr"`
 }
@@ -202,11 +453,13 @@ type GetPeersOfAIProjectRequest struct {
 }
 
 type AIProjectPeerInfo struct {
-	NodeID       string `json:"node_id"`
-	Connectivity int    `json:"connectivity"`
-	Latency      int64  `json:"latency"`
-	Idle         int    `json:"Idle"`
-	CID          string `json:"cid"`
+	NodeID       string       `json:"node_id"`
+	Connectivity int          `json:"connectivity"`
+	Latency      int64        `json:"latency"`
+	Idle         int          `json:"Idle"`
+	CID          string       `json:"cid"`
+	Type         int          `json:"type"`
+	Metrics      *HostMetrics `json:"metrics,omitempty"`
 }
 
 type GetPeersOfAIProjectResponse struct {
@@ -214,6 +467,91 @@ type GetPeersOfAIProjectResponse struct {
 	Data []AIProjectPeerInfo `json:"data"`
 }
 
+type ModelHistoryRequest struct {
+	Number int `json:"number" form:"number"`
+}
+
+type ModelHistoryResponse struct {
+	BaseHttpResponse
+	Data []ModelHistory `json:"data"`
+}
+
+type PeerDirectoryRequest struct {
+	// Only peers with all of these node type flags
+	NodeType uint32 `json:"node_type" form:"node_type"`
+	Project  string `json:"project" form:"project"`
+	Model    string `json:"model" form:"model"`
+	// Only peers connected to this node
+	Connected bool `json:"connected" form:"connected"`
+	// Only peers connected to this node through a relay
+	Relayed bool `json:"relayed" form:"relayed"`
+	// Sort field, prefix with "-" for descending order
+	Sort   string `json:"sort" form:"sort"`
+	Offset int    `json:"offset" form:"offset"`
+	Limit  int    `json:"limit" form:"limit"`
+}
+
+type PeerDirectoryEntry struct {
+	NodeID        string              `json:"node_id"`
+	NodeType      uint32              `json:"node_type"`
+	PublicNode    bool                `json:"public_node"`
+	ClientNode    bool                `json:"client_node"`
+	ModelNode     bool                `json:"model_node"`
+	LastHeartbeat int64               `json:"last_heartbeat"`
+	Connectivity  int                 `json:"connectivity"`
+	Latency       int64               `json:"latency"`
+	AgentVersion  string              `json:"agent_version,omitempty"`
+	Relayed       bool                `json:"relayed"`
+	Projects      map[string][]string `json:"projects,omitempty"`
+	Metrics       *HostMetrics        `json:"metrics,omitempty"`
+}
+
+type PeerDirectoryResponse struct {
+	BaseHttpResponse
+	Total int                  `json:"total"`
+	Data  []PeerDirectoryEntry `json:"data"`
+}
+
+type TopologyRequest struct {
+	// "json" or "dot", default "json"
+	Format string `json:"format" form:"format"`
+}
+
+type TopologyNode struct {
+	NodeID       string `json:"node_id"`
+	NodeType     uint32 `json:"node_type"`
+	AgentVersion string `json:"agent_version,omitempty"`
+	Self         bool   `json:"self,omitempty"`
+}
+
+type TopologyEdge struct {
+	From      string `json:"from"`
+	To        string `json:"to"`
+	Direction string `json:"direction"`
+	Latency   int64  `json:"latency"`
+	Relay     string `json:"relay,omitempty"`
+}
+
+// PeerConnection is a connection reported by a node in its heartbeats.
+type PeerConnection struct {
+	NodeID    string `json:"node_id"`
+	Relay     string `json:"relay,omitempty"`
+	Direction string `json:"direction"`
+	Latency   int64  `json:"latency"`
+}
+
+type TopologyResponse struct {
+	BaseHttpResponse
+	Timestamp int64          `json:"timestamp"`
+	Nodes     []TopologyNode `json:"nodes"`
+	Edges     []TopologyEdge `json:"edges"`
+}
+
+// Fields of PeerDirectoryEntry that can be sorted by
+var PeerDirectorySortFields = []string{
+	"node_id", "node_type", "last_heartbeat", "latency", "connectivity", "agent_version",
+}
+
 type AIProjectPeerOrder []AIProjectPeerInfo
 
 func (a AIProjectPeerOrder) Len() int      { return len(a) }
@@ -277,6 +615,14 @@ func (res *ImageGenerationResponse) SetMessage(message string) {
 	res.Message = message
 }
 
+func (res *EmbeddingResponse) SetCode(code int) {
+	res.Code = code
+}
+
+func (res *EmbeddingResponse) SetMessage(message string) {
+	res.Message = message
+}
+
 func (res *AIProjectListResponse) SetCode(code int) {
 	res.Code = code
 }
@@ -289,16 +635,38 @@ func (req PeerRequest) Validate() error {
 	if req.NodeID == "" {
 		return errors.New("empty node_id")
 	}
-	return nil
+	return req.RemoteQueryOptions.Validate()
 }
 
 func (req HostInfoRequest) Validate() error {
 	if req.NodeID == "" {
 		return errors.New("empty node_id")
 	}
+	return req.RemoteQueryOptions.Validate()
+}
+
+func (opts RemoteQueryOptions) Validate() error {
+	if opts.MaxAge < 0 {
+		return errors.New("max_age can not be negative")
+	}
 	return nil
 }
 
+func (req BatchRemoteQueryRequest) Validate() error {
+	if len(req.NodeIDs) == 0 {
+		return errors.New("empty node_ids")
+	}
+	if len(req.NodeIDs) > MaxBatchRemoteQueryNodes {
+		return fmt.Errorf("node_ids can not exceed %d", MaxBatchRemoteQueryNodes)
+	}
+	for _, id := range req.NodeIDs {
+		if id == "" {
+			return errors.New("empty node_id in node_ids")
+		}
+	}
+	return req.RemoteQueryOptions.Validate()
+}
+
 func (req WalletVerification) Validate() error {
 	if req.Wallet == "" {
 		return errors.New("empty wallet")
@@ -339,6 +707,18 @@ func (ccm ChatCompletionMessage) Validate() error {
 	default:
 		return errors.New("unknowned role of chat message author")
 	}
+	if ccm.Role == "tool" && ccm.ToolCallID == "" {
+		return errors.New("empty tool_call_id of tool message")
+	}
+	for _, call := range ccm.ToolCalls {
+		if call.ID == "" || call.Function.Name == "" {
+			return errors.New("invalid tool call of chat message")
+		}
+	}
+	if len(ccm.ToolCalls) > 0 && (len(ccm.Content) == 0 || string(ccm.Content) == "null") {
+		// the content of the assistant message is omitted when it calls the tools
+		return nil
+	}
 	if len(ccm.Content) < 2 {
 		return errors.New("invalid content of chat message")
 	}
@@ -360,12 +740,59 @@ func (ccm ChatCompletionMessage) Validate() error {
 	return nil
 }
 
+// MaxChatStops is the maximum number of the stop sequences
+const MaxChatStops = 4
+
+func (opts ChatOptions) Validate() error {
+	if opts.Temperature != nil && (*opts.Temperature < 0 || *opts.Temperature > 2) {
+		return errors.New("temperature must be between 0 and 2")
+	}
+	if opts.TopP != nil && (*opts.TopP < 0 || *opts.TopP > 1) {
+		return errors.New("top_p must be between 0 and 1")
+	}
+	if opts.MaxTokens < 0 || opts.MaxCompletionTokens < 0 || opts.N < 0 || opts.TopLogprobs < 0 {
+		return errors.New("max_tokens, max_completion_tokens, n and top_logprobs can not be negative")
+	}
+	if opts.TopLogprobs > 20 {
+		return errors.New("top_logprobs must be at most 20")
+	}
+	if opts.FrequencyPenalty < -2 || opts.FrequencyPenalty > 2 || opts.PresencePenalty < -2 || opts.PresencePenalty > 2 {
+		return errors.New("frequency_penalty and presence_penalty must be between -2 and 2")
+	}
+	if len(opts.Stop) > MaxChatStops {
+		return fmt.Errorf("at most %d stop sequences", MaxChatStops)
+	}
+	if format := opts.ResponseFormat; format != nil {
+		switch format.Type {
+		case "text", "json_object":
+		case "json_schema":
+			if format.JSONSchema == nil || format.JSONSchema.Name == "" {
+				return errors.New("empty json_schema of response_format")
+			}
+		default:
+			return errors.New("response_format must be text, json_object or json_schema")
+		}
+	}
+	for _, tool := range opts.Tools {
+		if tool.Type != "function" || tool.Function.Name == "" {
+			return errors.New("only the named function tools are supported")
+		}
+	}
+	if len(opts.ToolChoice) > 0 && !json.Valid(opts.ToolChoice) {
+		return errors.New("invalid tool_choice")
+	}
+	return nil
+}
+
 func (req ChatModelRequest) Validate() error {
 	for _, ccm := range req.Messages {
 		if err := ccm.Validate(); err != nil {
 			return err
 		}
 	}
+	if err := req.ChatOptions.Validate(); err != nil {
+		return err
+	}
 	// if err := req.WalletVerification.Validate(); err != nil {
 	// 	return err
 	// }
@@ -430,6 +857,115 @@ func (req ImageGenerationProxyRequest) Validate() error {
 	return nil
 }
 
+// MaxEmbeddingInputs is the maximum number of texts in a batch of embedding request
+const MaxEmbeddingInputs = 2048
+
+func (req EmbeddingModelRequest) Validate() error {
+	if len(req.Input) == 0 {
+		return errors.New("empty input")
+	}
+	if len(req.Input) > MaxEmbeddingInputs {
+		return fmt.Errorf("input can not exceed %d texts", MaxEmbeddingInputs)
+	}
+	if req.EncodingFormat != "" && req.EncodingFormat != "float" {
+		return errors.New("only float encoding_format is supported")
+	}
+	if req.Dimensions < 0 {
+		return errors.New("invalid dimensions")
+	}
+	return nil
+}
+
+func (req EmbeddingRequest) Validate() error {
+	if req.NodeID == "" {
+		return errors.New("empty node_id")
+	}
+	if req.Project == "" {
+		return errors.New("empty project")
+	}
+	if req.Model == "" {
+		return errors.New("empty model")
+	}
+	return req.EmbeddingModelRequest.Validate()
+}
+
+func (req EmbeddingProxyRequest) Validate() error {
+	if req.Project == "" {
+		return errors.New("empty project")
+	}
+	if req.Model == "" {
+		return errors.New("empty model")
+	}
+	return req.EmbeddingModelRequest.Validate()
+}
+
+// MaxAudioFileSize is the maximum size of the uploaded audio files
+const MaxAudioFileSize = 25 << 20
+
+// MaxSpeechInput is the maximum length of the text to generate the audio for
+const MaxSpeechInput = 4096
+
+var audioSpeechFormats = []string{"", "mp3", "opus", "aac", "flac", "wav", "pcm"}
+
+func (req AudioTranscriptionRequest) Validate() error {
+	if req.NodeID == "" {
+		return errors.New("empty node_id")
+	}
+	if req.Project == "" {
+		return errors.New("empty project")
+	}
+	if req.Model == "" {
+		return errors.New("empty model")
+	}
+	return nil
+}
+
+func (req AudioTranscriptionProxyRequest) Validate() error {
+	if req.Project == "" {
+		return errors.New("empty project")
+	}
+	if req.Model == "" {
+		return errors.New("empty model")
+	}
+	return nil
+}
+
+func (req AudioSpeechModelRequest) Validate() error {
+	if req.Model == "" {
+		return errors.New("empty model")
+	}
+	if req.Input == "" {
+		return errors.New("empty input")
+	}
+	if len(req.Input) > MaxSpeechInput {
+		return fmt.Errorf("input can not exceed %d characters", MaxSpeechInput)
+	}
+	if !slices.Contains(audioSpeechFormats, req.ResponseFormat) {
+		return fmt.Errorf("unsupported response_format %s", req.ResponseFormat)
+	}
+	if req.Speed != 0 && (req.Speed < 0.25 || req.Speed > 4) {
+		return errors.New("speed must be between 0.25 and 4.0")
+	}
+	return nil
+}
+
+func (req AudioSpeechRequest) Validate() error {
+	if req.NodeID == "" {
+		return errors.New("empty node_id")
+	}
+	if req.Project == "" {
+		return errors.New("empty project")
+	}
+	return req.AudioSpeechModelRequest.Validate()
+}
+
+func (req AudioSpeechProxyRequest) Validate() error {
+	if req.Project == "" {
+		return errors.New("empty project")
+	}
+	return req.AudioSpeechModelRequest.Validate()
+}
+
 func (req SwarmConnectRequest) Validate() error {
 	if req.NodeAddr == "" {
 		return errors.New("empty node_addr")
@@ -451,6 +987,13 @@ func (req GetAIProjectsRequest) Validate() error {
 	return nil
 }
 
+func (req ModelHistoryRequest) Validate() error {
+	if req.Number < 0 {
+		return errors.New("invalid number")
+	}
+	return nil
+}
+
 func (req GetModelsOfAIProjectRequest) Validate() error {
 	if req.Project == "" {
 		return errors.New("empty project")
@@ -474,6 +1017,32 @@ func (req GetPeersOfAIProjectRequest) Validate() error {
 	return nil
 }
 
+func (req PeerDirectoryRequest) Validate() error {
+	if req.Model != "" && req.Project == "" {
+		return errors.New("model must be used together with project")
+	}
+	if req.Sort != "" {
+		field := strings.TrimPrefix(req.Sort, "-")
+		if !slices.Contains(PeerDirectorySortFields, field) {
+			return fmt.Errorf("unsupported sort field %s", field)
+		}
+	}
+	if req.Offset < 0 {
+		return errors.New("invalid offset")
+	}
+	if req.Limit < 0 {
+		return errors.New("invalid limit")
+	}
+	return nil
+}
+
+func (req TopologyRequest) Validate() error {
+	if req.Format != "" && req.Format != "json" && req.Format != "dot" {
+		return fmt.Errorf("unsupported format %s", req.Format)
+	}
+	return nil
+}
+
 func (req ChatModelRequest) RequestBody() (io.ReadCloser, int64, error) {
 	jsonData, err := json.Marshal(req)
 	if err != nil {
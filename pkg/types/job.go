@@ -0,0 +1,98 @@
+package types
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// Types of the asynchronous jobs, the request of a job is the body of the synchronous API
+const (
+	// POST /api/v0/image/gen
+	JobTypeImageGen = "image_gen"
+	// POST /api/v0/image/gen/proxy
+	JobTypeImageGenProxy = "image_gen_proxy"
+)
+
+// Status of the asynchronous jobs
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// Progress of the jobs in percent, the model backends do not report their progress
+const (
+	JobProgressQueued   = 0
+	JobProgressRunning  = 50
+	JobProgressFinished = 100
+)
+
+type JobRequest struct {
+	Type string `json:"type"`
+	// The finished job is posted to the URL
+	Webhook string          `json:"webhook,omitempty"`
+	Request json.RawMessage `json:"request"`
+}
+
+type Job struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	Progress  int    `json:"progress"`
+	CreatedAt int64  `json:"created_at"`
+	UpdatedAt int64  `json:"updated_at"`
+	Webhook   string `json:"webhook,omitempty"`
+	// The webhook has answered with a 2xx status
+	WebhookDelivered bool            `json:"webhook_delivered,omitempty"`
+	Request          json.RawMessage `json:"request"`
+	// Response of the synchronous API when the job is finished, including the error code
+	Result json.RawMessage `json:"result,omitempty"`
+}
+
+type JobResponse struct {
+	BaseHttpResponse
+	Data *Job `json:"data,omitempty"`
+}
+
+func (job Job) Finished() bool {
+	return job.Status == JobStatusSucceeded || job.Status == JobStatusFailed
+}
+
+// Pending reports whether the job is unfinished or its webhook is not delivered yet.
+func (job Job) Pending() bool {
+	return !job.Finished() || (job.Webhook != "" && !job.WebhookDelivered)
+}
+
+func (req JobRequest) Validate() error {
+	if req.Webhook != "" {
+		u, err := url.Parse(req.Webhook)
+		if err != nil {
+			return fmt.Errorf("invalid webhook %v", err)
+		}
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return errors.New("webhook must be an http or https url")
+		}
+	}
+	if len(req.Request) == 0 {
+		return errors.New("empty request")
+	}
+	switch req.Type {
+	case JobTypeImageGen:
+		var igReq ImageGenerationRequest
+		if err := json.Unmarshal(req.Request, &igReq); err != nil {
+			return fmt.Errorf("invalid request %v", err)
+		}
+		return igReq.Validate()
+	case JobTypeImageGenProxy:
+		var igReq ImageGenerationProxyRequest
+		if err := json.Unmarshal(req.Request, &igReq); err != nil {
+			return fmt.Errorf("invalid request %v", err)
+		}
+		return igReq.Validate()
+	default:
+		return fmt.Errorf("unsupported job type %q", req.Type)
+	}
+}
@@ -19,4 +19,8 @@ type ModelHistory struct {
 	ImagePrompt string `json:"image_prompt"`
 	// Image Generation Response
 	ImageChoices []ImageResponseChoice `json:"image_choices"`
+	// Embedding Request
+	EmbeddingInput []string `json:"embedding_input,omitempty"`
+	// The request is canceled by the node sending it
+	Canceled bool `json:"canceled,omitempty"`
 }
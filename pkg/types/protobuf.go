@@ -1,6 +1,11 @@
 package types
 
-import "AIComputingNode/pkg/protocol"
+import (
+	"encoding/json"
+	"fmt"
+
+	"AIComputingNode/pkg/protocol"
+)
 
 func HostInfo2ProtocolMessage(hostInfo *HostInfo) *protocol.HostInfoResponse {
 	res := &protocol.HostInfoResponse{
@@ -38,6 +43,7 @@ func HostInfo2ProtocolMessage(hostInfo *HostInfo) *protocol.HostInfoResponse {
 			Product: gpu.Product,
 		})
 	}
+	res.Metrics = HostMetrics2ProtocolMessage(hostInfo.Metrics)
 	return res
 }
 
@@ -77,9 +83,72 @@ func ProtocolMessage2HostInfo(res *protocol.HostInfoResponse) *HostInfo {
 			Product: gpu.GetProduct(),
 		})
 	}
+	hostInfo.Metrics = ProtocolMessage2HostMetrics(res.GetMetrics())
 	return hostInfo
 }
 
+func HostMetrics2ProtocolMessage(metrics *HostMetrics) *protocol.HostMetrics {
+	if metrics == nil {
+		return nil
+	}
+	res := &protocol.HostMetrics{
+		Timestamp: metrics.Timestamp,
+		Cpu: &protocol.HostMetrics_CpuMetrics{
+			UsagePercent: metrics.Cpu.UsagePercent,
+			Load1:        metrics.Cpu.Load1,
+			Load5:        metrics.Cpu.Load5,
+			Load15:       metrics.Cpu.Load15,
+		},
+		Memory: &protocol.HostMetrics_MemMetrics{
+			TotalBytes:     metrics.Memory.TotalBytes,
+			UsedBytes:      metrics.Memory.UsedBytes,
+			AvailableBytes: metrics.Memory.AvailableBytes,
+		},
+	}
+	for _, gpu := range metrics.Gpu {
+		res.Gpu = append(res.Gpu, &protocol.HostMetrics_GpuMetrics{
+			Index:              gpu.Index,
+			Name:               gpu.Name,
+			UtilizationPercent: gpu.UtilizationPercent,
+			MemoryTotalBytes:   gpu.MemoryTotalBytes,
+			MemoryUsedBytes:    gpu.MemoryUsedBytes,
+			TemperatureCelsius: gpu.TemperatureCelsius,
+		})
+	}
+	return res
+}
+
+func ProtocolMessage2HostMetrics(res *protocol.HostMetrics) *HostMetrics {
+	if res == nil {
+		return nil
+	}
+	metrics := &HostMetrics{
+		Timestamp: res.GetTimestamp(),
+		Cpu: CpuMetrics{
+			UsagePercent: res.GetCpu().GetUsagePercent(),
+			Load1:        res.GetCpu().GetLoad1(),
+			Load5:        res.GetCpu().GetLoad5(),
+			Load15:       res.GetCpu().GetLoad15(),
+		},
+		Memory: MemMetrics{
+			TotalBytes:     res.GetMemory().GetTotalBytes(),
+			UsedBytes:      res.GetMemory().GetUsedBytes(),
+			AvailableBytes: res.GetMemory().GetAvailableBytes(),
+		},
+	}
+	for _, gpu := range res.GetGpu() {
+		metrics.Gpu = append(metrics.Gpu, GpuMetrics{
+			Index:              gpu.GetIndex(),
+			Name:               gpu.GetName(),
+			UtilizationPercent: gpu.GetUtilizationPercent(),
+			MemoryTotalBytes:   gpu.GetMemoryTotalBytes(),
+			MemoryUsedBytes:    gpu.GetMemoryUsedBytes(),
+			TemperatureCelsius: gpu.GetTemperatureCelsius(),
+		})
+	}
+	return metrics
+}
+
 func AIProject2ProtocolMessage(projs map[string][]ModelIdle, nt uint32) *protocol.AIProjectResponse {
 	res := &protocol.AIProjectResponse{
 		NodeType: nt,
@@ -122,3 +191,394 @@ func ProtocolMessage2AIProject(res *protocol.AIProjectResponse) map[string][]Mod
 	}
 	return projects
 }
+
+func PeerConnections2ProtocolMessage(conns []PeerConnection) []*protocol.PeerConnection {
+	res := make([]*protocol.PeerConnection, 0, len(conns))
+	for _, conn := range conns {
+		res = append(res, &protocol.PeerConnection{
+			NodeId:    conn.NodeID,
+			Relay:     conn.Relay,
+			Direction: conn.Direction,
+			Latency:   conn.Latency,
+		})
+	}
+	return res
+}
+
+func ProtocolMessage2PeerConnections(conns []*protocol.PeerConnection) []PeerConnection {
+	if len(conns) == 0 {
+		return nil
+	}
+	res := make([]PeerConnection, 0, len(conns))
+	for _, conn := range conns {
+		res = append(res, PeerConnection{
+			NodeID:    conn.GetNodeId(),
+			Relay:     conn.GetRelay(),
+			Direction: conn.GetDirection(),
+			Latency:   conn.GetLatency(),
+		})
+	}
+	return res
+}
+
+func ChatToolCalls2ProtocolMessage(calls []ChatToolCall) []*protocol.ChatToolCall {
+	var res []*protocol.ChatToolCall
+	for _, call := range calls {
+		pc := &protocol.ChatToolCall{
+			Id:   call.ID,
+			Type: call.Type,
+			Function: &protocol.ChatToolCall_Function{
+				Name:      call.Function.Name,
+				Arguments: call.Function.Arguments,
+			},
+		}
+		if call.Index != nil {
+			index := int32(*call.Index)
+			pc.Index = &index
+		}
+		res = append(res, pc)
+	}
+	return res
+}
+
+func ProtocolMessage2ChatToolCalls(calls []*protocol.ChatToolCall) []ChatToolCall {
+	var res []ChatToolCall
+	for _, pc := range calls {
+		call := ChatToolCall{
+			ID:   pc.GetId(),
+			Type: pc.GetType(),
+			Function: ChatToolCallFunction{
+				Name:      pc.GetFunction().GetName(),
+				Arguments: pc.GetFunction().GetArguments(),
+			},
+		}
+		if pc.Index != nil {
+			index := int(pc.GetIndex())
+			call.Index = &index
+		}
+		res = append(res, call)
+	}
+	return res
+}
+
+// ChatContentVersion is the version of the typed content of the protobuf chat messages,
+// the content of version 0 is the legacy JSON bytes.
+const ChatContentVersion = 1
+
+var chatContentPartTypes = map[string]protocol.ChatContentPart_Type{
+	"text":        protocol.ChatContentPart_TEXT,
+	"image_url":   protocol.ChatContentPart_IMAGE,
+	"input_audio": protocol.ChatContentPart_AUDIO,
+}
+
+// ChatContent2ProtocolMessage sets the text or the content parts of the message. The text contents
+// are also kept in the legacy JSON bytes read by the old nodes, but the contents with images or audios
+// are not, so that their data is not sent twice. The other contents, such as the null content of the
+// tool calls, are only kept in the JSON bytes.
+func ChatContent2ProtocolMessage(content json.RawMessage, msg *protocol.ChatCompletionMessage) {
+	// null is decoded into an empty string or array too
+	if len(content) == 0 || (content[0] != '"' && content[0] != '[') {
+		msg.Content = content
+		return
+	}
+	var text string
+	if err := json.Unmarshal(content, &text); err == nil {
+		msg.Content = content
+		msg.TypedContent = &protocol.ChatCompletionMessage_Text{Text: text}
+		msg.ContentVersion = ChatContentVersion
+		return
+	}
+
+	parts := []ChatDynamicContentPart{}
+	if err := json.Unmarshal(content, &parts); err != nil {
+		msg.Content = content
+		return
+	}
+	pps := &protocol.ChatContentParts{}
+	textOnly := true
+	for _, part := range parts {
+		if err := part.Validate(); err != nil {
+			msg.Content = content
+			return
+		}
+		textOnly = textOnly && part.Type == "text"
+		pp := &protocol.ChatContentPart{Type: chatContentPartTypes[part.Type]}
+		switch part.Type {
+		case "text":
+			pp.Text = &protocol.ChatContentPart_Text{Type: part.Type, Text: part.Text}
+		case "image_url":
+			pp.Image = &protocol.ChatContentPart_Image{Type: part.Type, Url: part.ImageUrl.Url, Detail: part.ImageUrl.Detail}
+		case "input_audio":
+			pp.Audio = &protocol.ChatContentPart_Audio{Type: part.Type, Data: part.InputAudio.Data, Format: part.InputAudio.Format}
+		}
+		pps.Parts = append(pps.Parts, pp)
+	}
+	if textOnly {
+		msg.Content = content
+	}
+	msg.TypedContent = &protocol.ChatCompletionMessage_Parts{Parts: pps}
+	msg.ContentVersion = ChatContentVersion
+}
+
+// ProtocolMessage2ChatContent decodes the content of the message into JSON,
+// the content parts are validated before they are sent to the model.
+func ProtocolMessage2ChatContent(msg *protocol.ChatCompletionMessage) (json.RawMessage, error) {
+	if msg.GetContentVersion() == 0 {
+		content := msg.GetContent()
+		if len(content) > 0 && content[0] == '[' {
+			parts := []ChatDynamicContentPart{}
+			if err := json.Unmarshal(content, &parts); err != nil {
+				return nil, fmt.Errorf("invalid content of %s message: %v", msg.GetRole(), err)
+			}
+			for _, part := range parts {
+				if err := part.Validate(); err != nil {
+					return nil, fmt.Errorf("invalid content part of %s message: %v", msg.GetRole(), err)
+				}
+			}
+		}
+		return content, nil
+	}
+	switch content := msg.GetTypedContent().(type) {
+	case *protocol.ChatCompletionMessage_Text:
+		return json.Marshal(content.Text)
+	case *protocol.ChatCompletionMessage_Parts:
+		parts := make([]ChatDynamicContentPart, 0, len(content.Parts.GetParts()))
+		for _, pp := range content.Parts.GetParts() {
+			var part ChatDynamicContentPart
+			switch pp.GetType() {
+			case protocol.ChatContentPart_TEXT:
+				part.Type = "text"
+				part.Text = pp.GetText().GetText()
+			case protocol.ChatContentPart_IMAGE:
+				part.Type = "image_url"
+				if pp.Image != nil {
+					part.ImageUrl = &ChatImageContentPart{Url: pp.Image.GetUrl(), Detail: pp.Image.GetDetail()}
+				}
+			case protocol.ChatContentPart_AUDIO:
+				part.Type = "input_audio"
+				if pp.Audio != nil {
+					part.InputAudio = &ChatAudioContentPart{Data: pp.Audio.GetData(), Format: pp.Audio.GetFormat()}
+				}
+			}
+			if err := part.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid content part of %s message: %v", msg.GetRole(), err)
+			}
+			parts = append(parts, part)
+		}
+		return json.Marshal(parts)
+	}
+	// the content is omitted, such as the assistant message of the tool calls
+	return nil, nil
+}
+
+func ChatModelRequest2ProtocolMessage(project, cid string, req *ChatModelRequest) *protocol.ChatCompletionRequest {
+	res := &protocol.ChatCompletionRequest{
+		Version:             ChatRequestVersion,
+		Project:             project,
+		Model:               req.Model,
+		Stream:              req.Stream,
+		Cid:                 cid,
+		Temperature:         req.Temperature,
+		TopP:                req.TopP,
+		MaxTokens:           int32(req.MaxTokens),
+		MaxCompletionTokens: int32(req.MaxCompletionTokens),
+		Stop:                req.Stop,
+		N:                   int32(req.N),
+		Seed:                req.Seed,
+		FrequencyPenalty:    req.FrequencyPenalty,
+		PresencePenalty:     req.PresencePenalty,
+		Logprobs:            req.Logprobs,
+		TopLogprobs:         int32(req.TopLogprobs),
+		ToolChoice:          req.ToolChoice,
+		ParallelToolCalls:   req.ParallelToolCalls,
+		User:                req.User,
+		Wallet: &protocol.WalletVerification{
+			Wallet:    req.Wallet,
+			Signature: req.Signature,
+			Hash:      req.Hash,
+		},
+	}
+	for _, ccm := range req.Messages {
+		msg := &protocol.ChatCompletionMessage{
+			Role:       ccm.Role,
+			Name:       ccm.Name,
+			ToolCalls:  ChatToolCalls2ProtocolMessage(ccm.ToolCalls),
+			ToolCallId: ccm.ToolCallID,
+		}
+		ChatContent2ProtocolMessage(ccm.Content, msg)
+		res.Messages = append(res.Messages, msg)
+	}
+	if len(req.LogitBias) > 0 {
+		res.LogitBias = make(map[string]int32, len(req.LogitBias))
+		for token, bias := range req.LogitBias {
+			res.LogitBias[token] = int32(bias)
+		}
+	}
+	if format := req.ResponseFormat; format != nil {
+		res.ResponseFormat = &protocol.ChatResponseFormat{Type: format.Type}
+		if schema := format.JSONSchema; schema != nil {
+			res.ResponseFormat.JsonSchema = &protocol.ChatResponseFormat_JSONSchema{
+				Name:        schema.Name,
+				Description: schema.Description,
+				Schema:      schema.Schema,
+				Strict:      schema.Strict,
+			}
+		}
+	}
+	for _, tool := range req.Tools {
+		res.Tools = append(res.Tools, &protocol.ChatTool{
+			Type: tool.Type,
+			Function: &protocol.ChatTool_Function{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				Parameters:  tool.Function.Parameters,
+				Strict:      tool.Function.Strict,
+			},
+		})
+	}
+	if req.StreamOptions != nil {
+		res.StreamIncludeUsage = &req.StreamOptions.IncludeUsage
+	}
+	return res
+}
+
+// ChatRequestVersion is the version of the protobuf chat requests, the temperature and top_p
+// of version 0 are not optional, so their zero values are omitted from the messages.
+const ChatRequestVersion = 1
+
+func ProtocolMessage2ChatModelRequest(req *protocol.ChatCompletionRequest) (ChatModelRequest, error) {
+	res := ChatModelRequest{
+		Model:  req.GetModel(),
+		Stream: req.GetStream(),
+		ChatOptions: ChatOptions{
+			Temperature:         req.Temperature,
+			TopP:                req.TopP,
+			MaxTokens:           int(req.GetMaxTokens()),
+			MaxCompletionTokens: int(req.GetMaxCompletionTokens()),
+			Stop:                req.GetStop(),
+			N:                   int(req.GetN()),
+			Seed:                req.Seed,
+			FrequencyPenalty:    req.GetFrequencyPenalty(),
+			PresencePenalty:     req.GetPresencePenalty(),
+			Logprobs:            req.GetLogprobs(),
+			TopLogprobs:         int(req.GetTopLogprobs()),
+			ToolChoice:          req.GetToolChoice(),
+			ParallelToolCalls:   req.ParallelToolCalls,
+			User:                req.GetUser(),
+		},
+		WalletVerification: WalletVerification{
+			Wallet:    req.GetWallet().GetWallet(),
+			Signature: req.GetWallet().GetSignature(),
+			Hash:      req.GetWallet().GetHash(),
+		},
+	}
+	if req.GetVersion() == 0 {
+		// the explicit 0 of the old nodes is not distinguished from the absent value
+		temperature, topP := req.GetTemperature(), req.GetTopP()
+		res.Temperature = &temperature
+		res.TopP = &topP
+	}
+	for _, ccm := range req.Messages {
+		content, err := ProtocolMessage2ChatContent(ccm)
+		if err != nil {
+			return res, err
+		}
+		res.Messages = append(res.Messages, ChatCompletionMessage{
+			Role:       ccm.GetRole(),
+			Content:    content,
+			Name:       ccm.GetName(),
+			ToolCalls:  ProtocolMessage2ChatToolCalls(ccm.GetToolCalls()),
+			ToolCallID: ccm.GetToolCallId(),
+		})
+	}
+	if len(req.LogitBias) > 0 {
+		res.LogitBias = make(map[string]int, len(req.LogitBias))
+		for token, bias := range req.LogitBias {
+			res.LogitBias[token] = int(bias)
+		}
+	}
+	if format := req.GetResponseFormat(); format != nil {
+		res.ResponseFormat = &ChatResponseFormat{Type: format.GetType()}
+		if schema := format.GetJsonSchema(); schema != nil {
+			res.ResponseFormat.JSONSchema = &ChatJSONSchema{
+				Name:        schema.GetName(),
+				Description: schema.GetDescription(),
+				Schema:      schema.GetSchema(),
+				Strict:      schema.Strict,
+			}
+		}
+	}
+	for _, tool := range req.Tools {
+		res.Tools = append(res.Tools, ChatTool{
+			Type: tool.GetType(),
+			Function: ChatFunctionDefinition{
+				Name:        tool.GetFunction().GetName(),
+				Description: tool.GetFunction().GetDescription(),
+				Parameters:  tool.GetFunction().GetParameters(),
+				Strict:      tool.GetFunction().Strict,
+			},
+		})
+	}
+	if req.StreamIncludeUsage != nil {
+		res.StreamOptions = &ChatStreamOptions{IncludeUsage: req.GetStreamIncludeUsage()}
+	}
+	return res, nil
+}
+
+func ChatModelResponse2ProtocolMessage(data *ChatModelResponseData) *protocol.ChatCompletionResponse {
+	res := &protocol.ChatCompletionResponse{
+		Created:           data.Created,
+		Id:                data.Id,
+		Object:            data.Object,
+		Model:             data.Model,
+		SystemFingerprint: data.SystemFingerprint,
+		Usage: &protocol.ChatCompletionResponse_ChatResponseUsage{
+			CompletionTokens: int32(data.Usage.CompletionTokens),
+			PromptTokens:     int32(data.Usage.PromptTokens),
+			TotalTokens:      int32(data.Usage.TotalTokens),
+		},
+	}
+	for _, choice := range data.Choices {
+		res.Choices = append(res.Choices, &protocol.ChatCompletionResponse_ChatResponseChoice{
+			Index: int32(choice.Index),
+			Message: &protocol.ChatCompletionResponseMessage{
+				Role:      choice.Message.Role,
+				Content:   choice.Message.Content,
+				ToolCalls: ChatToolCalls2ProtocolMessage(choice.Message.ToolCalls),
+				Refusal:   choice.Message.Refusal,
+			},
+			FinishReason: choice.FinishReason,
+			Logprobs:     choice.Logprobs,
+		})
+	}
+	return res
+}
+
+func ProtocolMessage2ChatModelResponse(res *protocol.ChatCompletionResponse) ChatModelResponseData {
+	data := ChatModelResponseData{
+		Id:                res.GetId(),
+		Object:            res.GetObject(),
+		Created:           res.GetCreated(),
+		Model:             res.GetModel(),
+		SystemFingerprint: res.GetSystemFingerprint(),
+		Usage: ChatResponseUsage{
+			CompletionTokens: int(res.GetUsage().GetCompletionTokens()),
+			PromptTokens:     int(res.GetUsage().GetPromptTokens()),
+			TotalTokens:      int(res.GetUsage().GetTotalTokens()),
+		},
+	}
+	for _, choice := range res.Choices {
+		data.Choices = append(data.Choices, ChatResponseChoice{
+			Index: int(choice.GetIndex()),
+			Message: ChatCompletionResponseMessage{
+				Role:      choice.GetMessage().GetRole(),
+				Content:   choice.GetMessage().GetContent(),
+				ToolCalls: ProtocolMessage2ChatToolCalls(choice.GetMessage().GetToolCalls()),
+				Refusal:   choice.GetMessage().GetRefusal(),
+			},
+			FinishReason: choice.GetFinishReason(),
+			Logprobs:     choice.GetLogprobs(),
+		})
+	}
+	return data
+}
@@ -0,0 +1,189 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"AIComputingNode/pkg/protocol"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// compactJSON re-encodes the value so that the JSON documents can be compared.
+func compactJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal %+v: %v", v, err)
+	}
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		t.Fatalf("Compact %s: %v", data, err)
+	}
+	return buf.String()
+}
+
+func TestChatRequestProtobuf(t *testing.T) {
+	bodies := []string{
+		`{"model":"llama3","messages":[{"role":"user","content":"Hi"}],"stream":false}`,
+		`{"model":"llama3","messages":[{"role":"system","content":"You are a weather bot."},` +
+			`{"role":"user","content":[{"type":"text","text":"Weather in Paris?"}],"name":"alice"},` +
+			`{"role":"assistant","content":null,"tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}}]},` +
+			`{"role":"tool","content":"\"sunny\"","tool_call_id":"call_1"}],` +
+			`"stream":true,"temperature":0,"top_p":0.9,"max_tokens":100,"max_completion_tokens":120,"stop":["\n\n","END"],"n":2,"seed":42,` +
+			`"frequency_penalty":0.5,"presence_penalty":-0.5,"logit_bias":{"50256":-100},"logprobs":true,"top_logprobs":3,` +
+			`"response_format":{"type":"json_schema","json_schema":{"name":"weather","description":"The weather","schema":{"type":"object"},"strict":true}},` +
+			`"tools":[{"type":"function","function":{"name":"get_weather","description":"Get the weather","parameters":{"type":"object","properties":{"city":{"type":"string"}}},"strict":false}}],` +
+			`"tool_choice":{"type":"function","function":{"name":"get_weather"}},"parallel_tool_calls":false,"stream_options":{"include_usage":true},"user":"alice",` +
+			`"wallet":"0x1","signature":"0x2","hash":"0x3"}`,
+		`{"model":"llama3","messages":[{"role":"user","content":"Hi"}],"stream":false,"stop":"END","response_format":{"type":"json_object"},"tool_choice":"auto"}`,
+	}
+	for _, body := range bodies {
+		var req ChatModelRequest
+		if err := json.Unmarshal([]byte(body), &req); err != nil {
+			t.Fatalf("Unmarshal %s: %v", body, err)
+		}
+		if err := req.Validate(); err != nil {
+			t.Errorf("Validate %s: %v", body, err)
+		}
+		want := compactJSON(t, req)
+
+		data, err := proto.Marshal(ChatModelRequest2ProtocolMessage("DecentralGPT", "d15c4007271b", &req))
+		if err != nil {
+			t.Fatalf("Marshal protobuf of %s: %v", body, err)
+		}
+		pr := &protocol.ChatCompletionRequest{}
+		if err := proto.Unmarshal(data, pr); err != nil {
+			t.Fatalf("Unmarshal protobuf of %s: %v", body, err)
+		}
+		if pr.GetProject() != "DecentralGPT" || pr.GetCid() != "d15c4007271b" {
+			t.Errorf("Project and cid of %s: %s, %s", body, pr.GetProject(), pr.GetCid())
+		}
+		got, err := ProtocolMessage2ChatModelRequest(pr)
+		if err != nil {
+			t.Fatalf("Chat request of %s: %v", body, err)
+		}
+		if s := compactJSON(t, got); s != want {
+			t.Errorf("Round trip of chat request\n got: %s\nwant: %s", s, want)
+		}
+	}
+}
+
+func TestLegacyChatRequestProtobuf(t *testing.T) {
+	// the old nodes omit the explicit 0 of the temperature and top_p
+	legacy := &protocol.ChatCompletionRequest{
+		Model:    "llama3",
+		Messages: []*protocol.ChatCompletionMessage{{Role: "user", Content: []byte(`"Hi"`)}},
+	}
+	got, err := ProtocolMessage2ChatModelRequest(legacy)
+	if err != nil {
+		t.Fatalf("Legacy chat request: %v", err)
+	}
+	if got.Temperature == nil || *got.Temperature != 0 || got.TopP == nil || *got.TopP != 0 {
+		t.Errorf("Temperature and top_p of the legacy request: %v, %v", got.Temperature, got.TopP)
+	}
+
+	req := ChatModelRequest{Model: "llama3", Messages: []ChatCompletionMessage{{Role: "user", Content: json.RawMessage(`"Hi"`)}}}
+	got, err = ProtocolMessage2ChatModelRequest(ChatModelRequest2ProtocolMessage("DecentralGPT", "", &req))
+	if err != nil {
+		t.Fatalf("Chat request: %v", err)
+	}
+	if got.Temperature != nil || got.TopP != nil {
+		t.Errorf("Unset temperature and top_p are set: %v, %v", got.Temperature, got.TopP)
+	}
+}
+
+func TestChatResponseProtobuf(t *testing.T) {
+	bodies := []string{
+		`{"id":"chatcmpl-1","object":"chat.completion","created":1718000000,"choices":[{"index":0,"message":{"role":"assistant","content":"Hi"},"finish_reason":"stop"}],` +
+			`"usage":{"completion_tokens":1,"prompt_tokens":2,"total_tokens":3}}`,
+		`{"id":"chatcmpl-2","object":"chat.completion","created":1718000000,"model":"gpt-4o","system_fingerprint":"fp_1","choices":[` +
+			`{"index":0,"message":{"role":"assistant","content":"","tool_calls":[{"id":"call_1","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Paris\"}"}},` +
+			`{"id":"call_2","type":"function","function":{"name":"get_weather","arguments":"{\"city\":\"Rome\"}"}}]},"finish_reason":"tool_calls"},` +
+			`{"index":1,"message":{"role":"assistant","content":"","refusal":"I can not help with that."},"finish_reason":"stop",` +
+			`"logprobs":{"content":[{"token":"I","logprob":-0.1,"top_logprobs":[]}]}}],` +
+			`"usage":{"completion_tokens":20,"prompt_tokens":30,"total_tokens":50}}`,
+	}
+	for _, body := range bodies {
+		var res ChatModelResponseData
+		if err := json.Unmarshal([]byte(body), &res); err != nil {
+			t.Fatalf("Unmarshal %s: %v", body, err)
+		}
+		want := compactJSON(t, res)
+
+		data, err := proto.Marshal(ChatModelResponse2ProtocolMessage(&res))
+		if err != nil {
+			t.Fatalf("Marshal protobuf of %s: %v", body, err)
+		}
+		pr := &protocol.ChatCompletionResponse{}
+		if err := proto.Unmarshal(data, pr); err != nil {
+			t.Fatalf("Unmarshal protobuf of %s: %v", body, err)
+		}
+		if s := compactJSON(t, ProtocolMessage2ChatModelResponse(pr)); s != want {
+			t.Errorf("Round trip of chat response\n got: %s\nwant: %s", s, want)
+		}
+	}
+}
+
+func TestChatContentProtobuf(t *testing.T) {
+	tests := []struct {
+		content string
+		typed   bool
+		legacy  bool
+	}{
+		{`"Hello"`, true, true},
+		{`[{"type":"text","text":"Hello"},{"type":"text","text":"World"}]`, true, true},
+		{`[{"type":"text","text":"What is in the image?"},{"type":"image_url","image_url":{"url":"data:image/png;base64,aW1hZ2U=","detail":"low"}},` +
+			`{"type":"input_audio","input_audio":{"data":"YXVkaW8=","format":"wav"}}]`, true, false},
+		{`null`, false, true},
+	}
+	for _, test := range tests {
+		msg := &protocol.ChatCompletionMessage{Role: "user"}
+		ChatContent2ProtocolMessage(json.RawMessage(test.content), msg)
+		if typed := msg.GetContentVersion() == ChatContentVersion && msg.GetTypedContent() != nil; typed != test.typed {
+			t.Errorf("Typed content of %s: %v", test.content, msg)
+		}
+		// the old nodes only read the JSON bytes, which are not sent with the images and audios
+		if legacy := string(msg.GetContent()) == test.content; legacy != test.legacy || (!legacy && len(msg.GetContent()) > 0) {
+			t.Errorf("Legacy content of %s: %s", test.content, msg.GetContent())
+		}
+		data, err := proto.Marshal(msg)
+		if err != nil {
+			t.Fatalf("Marshal protobuf of %s: %v", test.content, err)
+		}
+		pm := &protocol.ChatCompletionMessage{}
+		if err := proto.Unmarshal(data, pm); err != nil {
+			t.Fatalf("Unmarshal protobuf of %s: %v", test.content, err)
+		}
+		content, err := ProtocolMessage2ChatContent(pm)
+		if err != nil || string(content) != test.content {
+			t.Errorf("Content of %s: %s, %v", test.content, content, err)
+		}
+	}
+
+	// the messages of the old nodes only have the JSON bytes
+	legacy := &protocol.ChatCompletionMessage{Role: "user", Content: []byte(`[{"type":"text","text":"Hi"}]`)}
+	if content, err := ProtocolMessage2ChatContent(legacy); err != nil || string(content) != `[{"type":"text","text":"Hi"}]` {
+		t.Errorf("Legacy content: %s, %v", content, err)
+	}
+	for _, content := range []string{`[{"type":"file","file":{"file_id":"file-1"}}]`, `[{"type":"image_url"}]`, `[1]`} {
+		legacy := &protocol.ChatCompletionMessage{Role: "user", Content: []byte(content)}
+		if _, err := ProtocolMessage2ChatContent(legacy); err == nil {
+			t.Errorf("Invalid legacy content %s is accepted", content)
+		}
+	}
+
+	invalid := &protocol.ChatCompletionMessage{
+		Role:           "user",
+		ContentVersion: ChatContentVersion,
+		TypedContent: &protocol.ChatCompletionMessage_Parts{
+			Parts: &protocol.ChatContentParts{
+				Parts: []*protocol.ChatContentPart{{Type: protocol.ChatContentPart_IMAGE}},
+			},
+		},
+	}
+	if _, err := ProtocolMessage2ChatContent(invalid); err == nil {
+		t.Error("Image part without url is accepted")
+	}
+}
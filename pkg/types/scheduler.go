@@ -0,0 +1,29 @@
+package types
+
+// SchedulerRequest is a model request running or queued in the scheduler of the worker
+type SchedulerRequest struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Peer id of the requesting node
+	Requester string `json:"requester"`
+	Class     string `json:"class"`
+	Priority  int    `json:"priority"`
+	QueuedAt  int64  `json:"queued_at"`
+}
+
+type SchedulerState struct {
+	MaxRunning int                `json:"max_running"`
+	MaxQueued  int                `json:"max_queued"`
+	Running    []SchedulerRequest `json:"running"`
+	// The queued requests in the order they run
+	Queued []SchedulerRequest `json:"queued"`
+	// Number of the queued requests dropped for the requests of a higher priority or a fairer share
+	Preempted uint64 `json:"preempted"`
+	// Number of the requests rejected because the queue is full
+	Rejected uint64 `json:"rejected"`
+}
+
+type SchedulerResponse struct {
+	BaseHttpResponse
+	Data SchedulerState `json:"data"`
+}
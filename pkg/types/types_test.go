@@ -462,3 +462,113 @@ func TestJsonMap(t *testing.T) {
 	}
 	t.Logf("Unmarshal json sucess %v", js)
 }
+
+func TestEmbeddingInput(t *testing.T) {
+	tests := []struct {
+		body string
+		want EmbeddingInput
+	}{
+		{`{"model":"bge-m3","input":"cat"}`, EmbeddingInput{"cat"}},
+		{`{"model":"bge-m3","input":["cat","bird"]}`, EmbeddingInput{"cat", "bird"}},
+	}
+	for _, test := range tests {
+		var req EmbeddingModelRequest
+		if err := json.Unmarshal([]byte(test.body), &req); err != nil {
+			t.Fatalf("Unmarshal %s: %v", test.body, err)
+		}
+		if !reflect.DeepEqual(req.Input, test.want) {
+			t.Errorf("Input of %s = %v, want %v", test.body, req.Input, test.want)
+		}
+		if err := req.Validate(); err != nil {
+			t.Errorf("Validate %s: %v", test.body, err)
+		}
+	}
+
+	var req EmbeddingModelRequest
+	if err := json.Unmarshal([]byte(`{"model":"bge-m3","input":[1,2]}`), &req); err == nil {
+		t.Error("Unmarshal tokens input")
+	}
+	if err := json.Unmarshal([]byte(`{"model":"bge-m3","input":[]}`), &req); err != nil || req.Validate() == nil {
+		t.Errorf("Validate empty input: %v", err)
+	}
+}
+
+func TestAudioSpeechValidate(t *testing.T) {
+	tests := []struct {
+		req   AudioSpeechModelRequest
+		valid bool
+	}{
+		{AudioSpeechModelRequest{Model: "tts-1", Input: "hello", Voice: "alloy"}, true},
+		{AudioSpeechModelRequest{Model: "tts-1", Input: "hello", Voice: "alloy", ResponseFormat: "wav", Speed: 1.5}, true},
+		{AudioSpeechModelRequest{Model: "tts-1", Input: "", Voice: "alloy"}, false},
+		{AudioSpeechModelRequest{Model: "tts-1", Input: "hello", Voice: "alloy", ResponseFormat: "ogg"}, false},
+		{AudioSpeechModelRequest{Model: "tts-1", Input: "hello", Voice: "alloy", Speed: 5}, false},
+	}
+	for _, test := range tests {
+		if err := test.req.Validate(); (err == nil) != test.valid {
+			t.Errorf("Validate %+v: %v", test.req, err)
+		}
+	}
+}
+
+func TestJobRequestValidate(t *testing.T) {
+	tests := []struct {
+		body  string
+		valid bool
+	}{
+		{`{"type":"image_gen","request":{"node_id":"16Uiu2HAm","project":"SuperImageAI","model":"SuperImage","prompt":"cat"}}`, true},
+		{`{"type":"image_gen_proxy","webhook":"https://example.com/hook","request":{"project":"SuperImageAI","model":"SuperImage"}}`, true},
+		{`{"type":"image_gen","request":{"project":"SuperImageAI","model":"SuperImage"}}`, false},
+		{`{"type":"image_gen_proxy","webhook":"ftp://example.com/hook","request":{"project":"SuperImageAI","model":"SuperImage"}}`, false},
+		{`{"type":"image_gen_proxy"}`, false},
+		{`{"type":"chat","request":{}}`, false},
+	}
+	for _, test := range tests {
+		var req JobRequest
+		if err := json.Unmarshal([]byte(test.body), &req); err != nil {
+			t.Fatalf("Unmarshal %s: %v", test.body, err)
+		}
+		if err := req.Validate(); (err == nil) != test.valid {
+			t.Errorf("Validate %s: %v", test.body, err)
+		}
+	}
+}
+
+func TestChatOptionsValidate(t *testing.T) {
+	tests := []struct {
+		body  string
+		valid bool
+	}{
+		{`{"temperature":0,"top_p":1,"stop":"END"}`, true},
+		{`{"tools":[{"type":"function","function":{"name":"get_weather"}}],"tool_choice":"auto"}`, true},
+		{`{"temperature":3}`, false},
+		{`{"top_p":1.5}`, false},
+		{`{"max_tokens":-1}`, false},
+		{`{"stop":["a","b","c","d","e"]}`, false},
+		{`{"response_format":{"type":"xml"}}`, false},
+		{`{"response_format":{"type":"json_schema"}}`, false},
+		{`{"tools":[{"type":"function","function":{"name":""}}]}`, false},
+	}
+	for _, test := range tests {
+		var opts ChatOptions
+		if err := json.Unmarshal([]byte(test.body), &opts); err != nil {
+			t.Fatalf("Unmarshal %s: %v", test.body, err)
+		}
+		if err := opts.Validate(); (err == nil) != test.valid {
+			t.Errorf("Validate %s: %v", test.body, err)
+		}
+	}
+
+	tool := ChatCompletionMessage{Role: "tool", Content: []byte(`"sunny"`)}
+	if err := tool.Validate(); err == nil {
+		t.Error("Validate tool message without tool_call_id")
+	}
+	assistant := ChatCompletionMessage{
+		Role:      "assistant",
+		Content:   []byte("null"),
+		ToolCalls: []ChatToolCall{{ID: "call_1", Type: "function", Function: ChatToolCallFunction{Name: "get_weather", Arguments: "{}"}}},
+	}
+	if err := assistant.Validate(); err != nil {
+		t.Errorf("Validate assistant message with tool calls: %v", err)
+	}
+}